@@ -0,0 +1,238 @@
+// Package commit implements Pedersen commitments to a scalar or to a vector of
+// scalars. Given the generators G_1, ..., G_n and H, the commitment to the
+// messages m_1, ..., m_k (k <= n) with the randomizer r is
+//   C = rH + m_1G_1 + ... + m_kG_k
+// It hides the messages as long as r is random, and binds the committer to
+// them as long as nobody knows a discrete logarithm relation between the
+// generators. The commitments are additively homomorphic: the sum of the
+// commitments to m and m' with the randomizers r and r' is the commitment to
+// m + m' with the randomizer r + r'.
+//
+// The generators are nothing-up-my-sleeve points derived from a domain
+// separator (see NewGenerators), so that everybody can derive them and check
+// that nobody knows their discrete logarithms. Besides opening a commitment
+// by revealing its messages and randomizer, one can prove the knowledge of an
+// opening without revealing it (see Generators.Prove), with the
+// Camenisch/Stadler framework of the proof package.
+package commit
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strconv"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/proof"
+)
+
+// Suite wraps the functionalities needed by the commit package.
+type Suite interface {
+	kyber.Group
+	kyber.HashFactory
+	kyber.Encoding
+	kyber.XOFFactory
+	kyber.Random
+}
+
+var errorNoGenerators = errors.New("commit: at least one generator is needed")
+var errorMessagesLength = errors.New("commit: more messages than generators")
+var errorInvalidOpening = errors.New("commit: invalid opening")
+
+// Generators are the bases of the commitments: G[i] is the base of the i-th
+// message and H the one of the randomizer.
+type Generators struct {
+	suite Suite
+	G     []kyber.Point
+	H     kyber.Point
+}
+
+// NewGenerators derives the generators for commitments to up to n messages.
+// Each generator is obtained by decoding the output of the XOF of the suite,
+// seeded with the domain separator and the name of the generator, into a point
+// (see kyber.Point.Embed), so that its discrete logarithm is unknown. The same
+// domain separator always gives the same generators, and different ones give
+// independent generators: each protocol should use its own.
+//
+// The groups whose Embed method does not decode the given random bytes, but
+// multiplies the base point, must not be used. This package works over
+// edwards25519 and G1 of bn256.
+func NewGenerators(suite Suite, n int, domainSep []byte) (*Generators, error) {
+	if n < 1 {
+		return nil, errorNoGenerators
+	}
+	g := &Generators{
+		suite: suite,
+		G:     make([]kyber.Point, n),
+		H:     derive(suite, domainSep, 'H', 0),
+	}
+	for i := range g.G {
+		g.G[i] = derive(suite, domainSep, 'G', uint32(i))
+	}
+	return g, nil
+}
+
+// derive returns the generator of the given name and index, hashing the
+// length of the domain separator so that no two inputs give the same seed.
+func derive(suite Suite, domainSep []byte, name byte, i uint32) kyber.Point {
+	seed := make([]byte, 4+len(domainSep)+5)
+	binary.BigEndian.PutUint32(seed, uint32(len(domainSep)))
+	copy(seed[4:], domainSep)
+	seed[4+len(domainSep)] = name
+	binary.BigEndian.PutUint32(seed[5+len(domainSep):], i)
+	return suite.Point().Embed(nil, suite.XOF(seed))
+}
+
+// Commit returns the commitment rH + sum_i msgs[i]G[i]. There must not be more
+// messages than generators.
+func (g *Generators) Commit(msgs []kyber.Scalar, r kyber.Scalar) (kyber.Point, error) {
+	if len(msgs) > len(g.G) {
+		return nil, errorMessagesLength
+	}
+	C := g.suite.Point().Mul(r, g.H)
+	tmp := g.suite.Point()
+	for i, m := range msgs {
+		C.Add(C, tmp.Mul(m, g.G[i]))
+	}
+	return C, nil
+}
+
+// Open checks that the messages and the randomizer open the commitment C, and
+// returns an error otherwise.
+func (g *Generators) Open(C kyber.Point, msgs []kyber.Scalar, r kyber.Scalar) error {
+	D, err := g.Commit(msgs, r)
+	if err != nil {
+		return err
+	}
+	if !D.Equal(C) {
+		return errorInvalidOpening
+	}
+	return nil
+}
+
+// Add returns the sum of the commitments a and b, which is a commitment to the
+// sum of their messages with the sum of their randomizers.
+func (g *Generators) Add(a, b kyber.Point) kyber.Point {
+	return g.suite.Point().Add(a, b)
+}
+
+// Blind returns the commitment C blinded with the randomizer r, i.e. C + rH,
+// which commits to the same messages as C with the randomizer of C plus r.
+// The result cannot be linked to C without knowing r.
+func (g *Generators) Blind(C kyber.Point, r kyber.Scalar) kyber.Point {
+	rH := g.suite.Point().Mul(r, g.H)
+	return rH.Add(C, rH)
+}
+
+// MarshalBinary encodes the generators as their number n on 4 bytes in big
+// endian followed by H, G[0], ..., G[n-1], e.g. to bind the transcript of a
+// Fiat-Shamir proof to them.
+func (g *Generators) MarshalBinary() ([]byte, error) {
+	var b bytes.Buffer
+	if _, err := g.MarshalTo(&b); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// MarshalTo writes the encoding of MarshalBinary to w.
+func (g *Generators) MarshalTo(w io.Writer) (int, error) {
+	var n [4]byte
+	binary.BigEndian.PutUint32(n[:], uint32(len(g.G)))
+	written, err := w.Write(n[:])
+	if err != nil {
+		return written, err
+	}
+	for _, P := range append([]kyber.Point{g.H}, g.G...) {
+		m, err := P.MarshalTo(w)
+		written += m
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// UnmarshalGenerators decodes generators encoded with MarshalBinary. Unlike
+// NewGenerators, it cannot check that nobody knows a discrete logarithm
+// relation between them: generators from an untrusted source must be derived
+// again with NewGenerators and the domain separator of the protocol instead.
+func UnmarshalGenerators(suite Suite, b []byte) (*Generators, error) {
+	if len(b) < 4 {
+		return nil, errors.New("commit: generators too short")
+	}
+	n := binary.BigEndian.Uint32(b)
+	if n < 1 {
+		return nil, errorNoGenerators
+	}
+	l := suite.PointLen()
+	if uint64(len(b)-4) != uint64(n+1)*uint64(l) {
+		return nil, errors.New("commit: generators of invalid length")
+	}
+	points := make([]kyber.Point, n+1)
+	for i := range points {
+		points[i] = suite.Point()
+		off := 4 + i*l
+		if err := points[i].UnmarshalBinary(b[off : off+l]); err != nil {
+			return nil, err
+		}
+	}
+	return &Generators{suite: suite, G: points[1:], H: points[0]}, nil
+}
+
+// Prove returns a non-interactive zero-knowledge proof that the prover knows
+// an opening of the commitment C, without revealing it. The messages beyond
+// len(msgs) are zero, as in Commit.
+func (g *Generators) Prove(C kyber.Point, msgs []kyber.Scalar, r kyber.Scalar) ([]byte, error) {
+	if len(msgs) > len(g.G) {
+		return nil, errorMessagesLength
+	}
+	secrets := map[string]kyber.Scalar{"r": r}
+	for i := range g.G {
+		if i < len(msgs) {
+			secrets[msgName(i)] = msgs[i]
+		} else {
+			secrets[msgName(i)] = g.suite.Scalar().Zero()
+		}
+	}
+	pred, points := g.predicate(C)
+	return proof.HashProve(g.suite, g.protocolName(C), pred.Prover(g.suite, secrets, points, nil))
+}
+
+// Verify checks a proof produced by Prove for the commitment C, and returns an
+// error if it is invalid.
+func (g *Generators) Verify(C kyber.Point, prf []byte) error {
+	pred, points := g.predicate(C)
+	return proof.HashVerify(g.suite, g.protocolName(C), pred.Verifier(g.suite, points), prf)
+}
+
+// predicate returns the statement C = rH + sum_i m_iG_i and its public points.
+func (g *Generators) predicate(C kyber.Point) (proof.Predicate, map[string]kyber.Point) {
+	points := map[string]kyber.Point{"C": C, "H": g.H}
+	terms := []string{"r", "H"}
+	for i, G := range g.G {
+		points[baseName(i)] = G
+		terms = append(terms, msgName(i), baseName(i))
+	}
+	return proof.Rep("C", terms...), points
+}
+
+// protocolName binds the proof to the generators and to the commitment, which
+// the proof package does not hash itself.
+func (g *Generators) protocolName(C kyber.Point) string {
+	name := []byte("kyber commit opening")
+	for _, P := range append([]kyber.Point{g.H, C}, g.G...) {
+		b, _ := P.MarshalBinary()
+		name = append(name, b...)
+	}
+	return string(name)
+}
+
+func msgName(i int) string {
+	return "m" + strconv.Itoa(i)
+}
+
+func baseName(i int) string {
+	return "G" + strconv.Itoa(i)
+}
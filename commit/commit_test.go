@@ -0,0 +1,175 @@
+package commit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/pairing/bn256"
+)
+
+var suites = []Suite{edwards25519.NewBlakeSHA256Ed25519(), bn256.NewSuiteG1()}
+
+func randomScalars(suite Suite, n int) []kyber.Scalar {
+	s := make([]kyber.Scalar, n)
+	for i := range s {
+		s[i] = suite.Scalar().Pick(suite.RandomStream())
+	}
+	return s
+}
+
+func TestGenerators(t *testing.T) {
+	for _, suite := range suites {
+		g, err := NewGenerators(suite, 4, []byte("tests"))
+		require.NoError(t, err)
+		require.Len(t, g.G, 4)
+
+		// deterministic
+		g2, err := NewGenerators(suite, 5, []byte("tests"))
+		require.NoError(t, err)
+		require.True(t, g.H.Equal(g2.H))
+		for i := range g.G {
+			require.True(t, g.G[i].Equal(g2.G[i]))
+		}
+
+		// all distinct, and distinct for another domain separator
+		g3, err := NewGenerators(suite, 4, []byte("other tests"))
+		require.NoError(t, err)
+		all := append([]kyber.Point{g.H, g3.H, suite.Point().Base()}, append(g.G, g3.G...)...)
+		for i := range all {
+			require.False(t, all[i].Equal(suite.Point().Null()))
+			for j := range all[:i] {
+				require.False(t, all[i].Equal(all[j]), "generators %d and %d", i, j)
+			}
+		}
+
+		_, err = NewGenerators(suite, 0, []byte("tests"))
+		require.Equal(t, errorNoGenerators, err)
+	}
+}
+
+func TestCommit(t *testing.T) {
+	for _, suite := range suites {
+		g, err := NewGenerators(suite, 3, []byte("tests"))
+		require.NoError(t, err)
+		msgs := randomScalars(suite, 3)
+		r := suite.Scalar().Pick(suite.RandomStream())
+		C, err := g.Commit(msgs, r)
+		require.NoError(t, err)
+		require.NoError(t, g.Open(C, msgs, r))
+
+		// a wrong randomizer or message does not open the commitment
+		require.Equal(t, errorInvalidOpening, g.Open(C, msgs, suite.Scalar().Add(r, suite.Scalar().One())))
+		require.Equal(t, errorInvalidOpening, g.Open(C, randomScalars(suite, 3), r))
+		require.Equal(t, errorInvalidOpening, g.Open(C, msgs[:2], r))
+
+		// a single message, as a classic Pedersen commitment
+		C1, err := g.Commit(msgs[:1], r)
+		require.NoError(t, err)
+		require.True(t, C1.Equal(suite.Point().Add(suite.Point().Mul(r, g.H), suite.Point().Mul(msgs[0], g.G[0]))))
+
+		// hiding: another randomizer gives another commitment
+		C2, err := g.Commit(msgs, suite.Scalar().Pick(suite.RandomStream()))
+		require.NoError(t, err)
+		require.False(t, C.Equal(C2))
+
+		_, err = g.Commit(randomScalars(suite, 4), r)
+		require.Equal(t, errorMessagesLength, err)
+	}
+}
+
+func TestCommitHomomorphic(t *testing.T) {
+	for _, suite := range suites {
+		g, err := NewGenerators(suite, 3, []byte("tests"))
+		require.NoError(t, err)
+		m1, m2 := randomScalars(suite, 3), randomScalars(suite, 2)
+		r1 := suite.Scalar().Pick(suite.RandomStream())
+		r2 := suite.Scalar().Pick(suite.RandomStream())
+		C1, err := g.Commit(m1, r1)
+		require.NoError(t, err)
+		C2, err := g.Commit(m2, r2)
+		require.NoError(t, err)
+
+		sum := make([]kyber.Scalar, len(m1))
+		for i := range sum {
+			sum[i] = suite.Scalar().Set(m1[i])
+			if i < len(m2) {
+				sum[i].Add(sum[i], m2[i])
+			}
+		}
+		require.NoError(t, g.Open(g.Add(C1, C2), sum, suite.Scalar().Add(r1, r2)))
+		require.Error(t, g.Open(g.Add(C1, C2), sum, r1))
+	}
+}
+
+func TestBlind(t *testing.T) {
+	for _, suite := range suites {
+		g, err := NewGenerators(suite, 2, []byte("tests"))
+		require.NoError(t, err)
+		msgs := randomScalars(suite, 2)
+		r, r2 := suite.Scalar().Pick(suite.RandomStream()), suite.Scalar().Pick(suite.RandomStream())
+		C, err := g.Commit(msgs, r)
+		require.NoError(t, err)
+		B := g.Blind(C, r2)
+		require.False(t, B.Equal(C))
+		require.NoError(t, g.Open(B, msgs, suite.Scalar().Add(r, r2)))
+		require.Error(t, g.Open(B, msgs, r))
+	}
+}
+
+func TestMarshalGenerators(t *testing.T) {
+	for _, suite := range suites {
+		g, err := NewGenerators(suite, 3, []byte("tests"))
+		require.NoError(t, err)
+		b, err := g.MarshalBinary()
+		require.NoError(t, err)
+		require.Len(t, b, 4+4*suite.PointLen())
+
+		g2, err := UnmarshalGenerators(suite, b)
+		require.NoError(t, err)
+		require.True(t, g.H.Equal(g2.H))
+		require.Len(t, g2.G, len(g.G))
+		for i := range g.G {
+			require.True(t, g.G[i].Equal(g2.G[i]))
+		}
+
+		_, err = UnmarshalGenerators(suite, b[:len(b)-1])
+		require.Error(t, err)
+		_, err = UnmarshalGenerators(suite, b[:4+suite.PointLen()])
+		require.Error(t, err)
+		_, err = UnmarshalGenerators(suite, []byte{0, 0, 0, 0})
+		require.Equal(t, errorNoGenerators, err)
+	}
+}
+
+func TestProveOpening(t *testing.T) {
+	for _, suite := range suites {
+		g, err := NewGenerators(suite, 3, []byte("tests"))
+		require.NoError(t, err)
+		msgs := randomScalars(suite, 2)
+		r := suite.Scalar().Pick(suite.RandomStream())
+		C, err := g.Commit(msgs, r)
+		require.NoError(t, err)
+
+		prf, err := g.Prove(C, msgs, r)
+		require.NoError(t, err)
+		require.NoError(t, g.Verify(C, prf))
+
+		// the proof does not hold for another commitment or other generators
+		C2, err := g.Commit(msgs, suite.Scalar().Pick(suite.RandomStream()))
+		require.NoError(t, err)
+		require.Error(t, g.Verify(C2, prf))
+		g2, err := NewGenerators(suite, 3, []byte("other tests"))
+		require.NoError(t, err)
+		require.Error(t, g2.Verify(C, prf))
+
+		// a proof with a wrong opening does not verify
+		prf, err = g.Prove(C, msgs, suite.Scalar().Add(r, suite.Scalar().One()))
+		require.NoError(t, err)
+		require.Error(t, g.Verify(C, prf))
+
+		_, err = g.Prove(C, randomScalars(suite, 4), r)
+		require.Equal(t, errorMessagesLength, err)
+	}
+}
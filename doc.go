@@ -52,6 +52,8 @@ The `group/nist` package provides NIST-standardized elliptic curves built on
 the Go crypto library.
 The 'group/edwards25519' sub-package provides the kyber.Group interface
 using the popular Ed25519 curve.
+The 'group/secp256k1' sub-package provides the secp256k1 curve of the
+Bitcoin and Ethereum keys.
 
 Other sub-packages build more interesting high-level cryptographic tools
 atop these primitive interfaces, including:
@@ -70,6 +72,9 @@ or I know the secret y associated with public key Y",
 without revealing anything about either secret
 or even which branch of the "or" clause is true.
 
+- proof/rangeproof: Bulletproofs range proofs that committed values lie in
+[0, 2^n), aggregated over several values.
+
 - sign: The sign directory contains different signature schemes.
 
 - sign/anon provides anonymous and pseudonymous public-key encryption and signing,
@@ -78,6 +83,9 @@ is defined as an explicit anonymity set containing several public keys
 rather than just one. For example, a member of an organization's board of trustees
 might prove to be a member of the board without revealing which member she is.
 
+- sign/blind provides blind Schnorr and BLS signatures, with which a signer
+signs messages it does not see, e.g. to issue anonymous tokens.
+
 - sign/cosi provides collective signature algorithm, where a bunch of signers create a
 unique, compact and efficiently verifiable signature using the Schnorr signature as a basis.
 
@@ -85,6 +93,11 @@ unique, compact and efficiently verifiable signature using the Schnorr signature
 
 - sign/schnorr provides a basic vanilla Schnorr signature scheme implementation.
 
+- sign/threshold provides turnkey threshold BLS signatures, whose nodes run the
+distributed key generation by exchanging messages before signing.
+
+- sign/vrf provides a verifiable random function and its threshold variant.
+
 - shuffle: Verifiable cryptographic shuffles of ElGamal ciphertexts,
 which can be used to implement (for example) voting or auction schemes
 that keep the sources of individual votes or bids private
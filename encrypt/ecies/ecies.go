@@ -8,12 +8,89 @@ import (
 	"crypto/sha256"
 	"errors"
 	"hash"
+	"io"
 
 	"go.dedis.ch/kyber/v3"
 	"go.dedis.ch/kyber/v3/util/random"
 	"golang.org/x/crypto/hkdf"
 )
 
+// keyLen is the length of the symmetric key derived for the AEAD.
+const keyLen = 32
+
+// Option configures the behavior of EncryptWithOptions and
+// DecryptWithOptions. Decryption must use the same options as the encryption,
+// except for WithEphemeralRand which is only used during encryption.
+type Option func(*options)
+
+type options struct {
+	hash func() hash.Hash
+	aead func(key []byte) (cipher.AEAD, error)
+	info []byte
+	ad   []byte
+	rand cipher.Stream
+}
+
+// WithHash sets the hash function used by HKDF to derive the symmetric key
+// and nonce from the shared DH key. The default is SHA256.
+func WithHash(hash func() hash.Hash) Option {
+	return func(o *options) {
+		o.hash = hash
+	}
+}
+
+// WithAEAD sets the constructor of the AEAD used to encrypt the message from
+// a 32-byte symmetric key, e.g. chacha20poly1305.New. The nonce is derived via
+// HKDF with the size required by the AEAD. The default is AES-GCM.
+func WithAEAD(aead func(key []byte) (cipher.AEAD, error)) Option {
+	return func(o *options) {
+		o.aead = aead
+	}
+}
+
+// WithKDFInfo sets the info parameter of HKDF, binding the derived key to an
+// application specific context. The default is an empty info.
+func WithKDFInfo(info []byte) Option {
+	return func(o *options) {
+		o.info = info
+	}
+}
+
+// WithAssociatedData sets the additional data authenticated, but not
+// encrypted, by the AEAD, e.g. metadata sent in the clear along with the
+// ciphertext. The default is no additional data.
+func WithAssociatedData(ad []byte) Option {
+	return func(o *options) {
+		o.ad = ad
+	}
+}
+
+// WithEphemeralRand sets the source of randomness used to pick the ephemeral
+// key during encryption. The default is random.New(). A deterministic stream
+// should only be used to produce test vectors.
+func WithEphemeralRand(rand cipher.Stream) Option {
+	return func(o *options) {
+		o.rand = rand
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		hash: sha256.New,
+		aead: newGCM,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.hash == nil {
+		o.hash = sha256.New
+	}
+	if o.aead == nil {
+		o.aead = newGCM
+	}
+	return o
+}
+
 // Encrypt first computes a shared DH key using the given public key, then
 // HKDF-derives a symmetric key (and nonce) from that, and finally uses these
 // values to encrypt the given message via AES-GCM. If the hash input parameter
@@ -21,12 +98,29 @@ import (
 // containing the ephemeral elliptic curve point of the DH key exchange and the
 // ciphertext or an error.
 func Encrypt(group kyber.Group, public kyber.Point, message []byte, hash func() hash.Hash) ([]byte, error) {
-	if hash == nil {
-		hash = sha256.New
+	return EncryptWithOptions(group, public, message, WithHash(hash))
+}
+
+// EncryptWithAAD works like Encrypt, except that the additional data aad is
+// authenticated, but not encrypted, by the AEAD, e.g. to bind the ciphertext
+// to a session ID so that it cannot be replayed in another session. The
+// ciphertext only decrypts with DecryptWithAAD and the same aad.
+func EncryptWithAAD(group kyber.Group, public kyber.Point, message, aad []byte, hash func() hash.Hash) ([]byte, error) {
+	return EncryptWithOptions(group, public, message, WithHash(hash), WithAssociatedData(aad))
+}
+
+// EncryptWithOptions works like Encrypt, except that the hash, the AEAD, the
+// HKDF info, the associated data and the source of randomness of the ephemeral key can be
+// configured with the given options. Without any option, the output is
+// compatible with Encrypt using SHA256.
+func EncryptWithOptions(group kyber.Group, public kyber.Point, message []byte, opts ...Option) ([]byte, error) {
+	o := newOptions(opts)
+	if o.rand == nil {
+		o.rand = random.New()
 	}
 
 	// Generate an ephemeral elliptic curve scalar and point
-	r := group.Scalar().Pick(random.New())
+	r := group.Scalar().Pick(o.rand)
 	R := group.Point().Mul(r, nil)
 
 	// Compute shared DH key
@@ -34,26 +128,13 @@ func Encrypt(group kyber.Group, public kyber.Point, message []byte, hash func()
 
 	// Derive symmetric key and nonce via HKDF (NOTE: Since we use a new
 	// ephemeral key for every ECIES encryption and thus have a fresh
-	// HKDF-derived key for AES-GCM, the nonce for AES-GCM can be an arbitrary
-	// (even static) value. We derive it here simply via HKDF as well.)
-	len := 32 + 12
-	buf, err := deriveKey(hash, dh, len)
+	// HKDF-derived key for the AEAD, the nonce can be an arbitrary (even
+	// static) value. We derive it here simply via HKDF as well.)
+	aead, nonce, err := deriveAEAD(o, dh)
 	if err != nil {
 		return nil, err
 	}
-	key := buf[:32]
-	nonce := buf[32:len]
-
-	// Encrypt message using AES-GCM
-	aes, err := aes.NewCipher(key)
-	if err != nil {
-		return nil, err
-	}
-	aesgcm, err := cipher.NewGCM(aes)
-	if err != nil {
-		return nil, err
-	}
-	c := aesgcm.Seal(nil, nonce, message, nil)
+	c := aead.Seal(nil, nonce, message, o.ad)
 
 	// Serialize ephemeral elliptic curve point and ciphertext
 	var ctx bytes.Buffer
@@ -75,52 +156,84 @@ func Encrypt(group kyber.Group, public kyber.Point, message []byte, hash func()
 // input parameter is nil then SHA256 is used as a default. Decrypt returns the
 // plaintext message or an error.
 func Decrypt(group kyber.Group, private kyber.Scalar, ctx []byte, hash func() hash.Hash) ([]byte, error) {
-	if hash == nil {
-		hash = sha256.New
-	}
+	return DecryptWithOptions(group, private, ctx, WithHash(hash))
+}
+
+// DecryptWithAAD works like Decrypt for a ciphertext of EncryptWithAAD. It
+// returns an error if aad is not the additional data of the encryption.
+func DecryptWithAAD(group kyber.Group, private kyber.Scalar, ctx, aad []byte, hash func() hash.Hash) ([]byte, error) {
+	return DecryptWithOptions(group, private, ctx, WithHash(hash), WithAssociatedData(aad))
+}
+
+// DecryptWithOptions works like Decrypt, except that the hash, the AEAD, the
+// HKDF info and the associated data can be configured with the given options. They must match
+// the options used to encrypt the ciphertext.
+func DecryptWithOptions(group kyber.Group, private kyber.Scalar, ctx []byte, opts ...Option) ([]byte, error) {
+	o := newOptions(opts)
 
 	// Reconstruct the ephemeral elliptic curve point
 	R := group.Point()
 	l := group.PointLen()
+	if len(ctx) < l {
+		return nil, errors.New("ecies: ciphertext too short")
+	}
 	if err := R.UnmarshalBinary(ctx[:l]); err != nil {
 		return nil, err
 	}
 
 	// Compute shared DH key and derive the symmetric key and nonce via HKDF
 	dh := group.Point().Mul(private, R)
-	len := 32 + 12
-	buf, err := deriveKey(hash, dh, len)
+	aead, nonce, err := deriveAEAD(o, dh)
 	if err != nil {
 		return nil, err
 	}
-	key := buf[:32]
-	nonce := buf[32:len]
+	return aead.Open(nil, nonce, ctx[l:], o.ad)
+}
 
-	// Decrypt message using AES-GCM
-	aes, err := aes.NewCipher(key)
+// DecryptWithDH works like DecryptWithOptions, but takes the shared DH key
+// private*R of the ciphertext instead of the private key, e.g. when the
+// private key is shared among nodes which compute the DH key together, see
+// kyber/encrypt/threshold. The options must match the ones of the encryption.
+func DecryptWithDH(group kyber.Group, dh kyber.Point, ctx []byte, opts ...Option) ([]byte, error) {
+	o := newOptions(opts)
+	l := group.PointLen()
+	if len(ctx) < l {
+		return nil, errors.New("ecies: ciphertext too short")
+	}
+	aead, nonce, err := deriveAEAD(o, dh)
 	if err != nil {
 		return nil, err
 	}
-	aesgcm, err := cipher.NewGCM(aes)
+	return aead.Open(nil, nonce, ctx[l:], o.ad)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	aes, err := aes.NewCipher(key)
 	if err != nil {
 		return nil, err
 	}
-	return aesgcm.Open(nil, nonce, ctx[l:], nil)
+	return cipher.NewGCM(aes)
 }
 
-func deriveKey(hash func() hash.Hash, dh kyber.Point, len int) ([]byte, error) {
+// deriveAEAD reads the symmetric key followed by the nonce from HKDF and
+// returns the corresponding AEAD.
+func deriveAEAD(o *options, dh kyber.Point) (cipher.AEAD, []byte, error) {
 	dhb, err := dh.MarshalBinary()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	kdf := hkdf.New(o.hash, dhb, nil, o.info)
+	key := make([]byte, keyLen)
+	if _, err := io.ReadFull(kdf, key); err != nil {
+		return nil, nil, errors.New("ecies: hkdf-derived key too short")
 	}
-	hkdf := hkdf.New(hash, dhb, nil, nil)
-	key := make([]byte, len, len)
-	n, err := hkdf.Read(key)
+	aead, err := o.aead(key)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	if n < len {
-		return nil, errors.New("ecies: hkdf-derived key too short")
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(kdf, nonce); err != nil {
+		return nil, nil, errors.New("ecies: hkdf-derived nonce too short")
 	}
-	return key, nil
+	return aead, nonce, nil
 }
@@ -1,11 +1,14 @@
 package ecies
 
 import (
+	"encoding/hex"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 	"go.dedis.ch/kyber/v3/group/edwards25519"
 	"go.dedis.ch/kyber/v3/util/random"
+	"go.dedis.ch/kyber/v3/xof/blake2xb"
+	"golang.org/x/crypto/chacha20poly1305"
 )
 
 func TestECIES(t *testing.T) {
@@ -44,3 +47,172 @@ func TestECIESFailCiphertext(t *testing.T) {
 	_, err = Decrypt(suite, private, ciphertext, nil)
 	require.NotNil(t, err)
 }
+
+// vectorInfo is the HKDF info used by the ChaCha20-Poly1305 test vector.
+var vectorInfo = []byte("ecies interop v1")
+
+func TestECIESDefaultOptionsVector(t *testing.T) {
+	// ciphertext produced by Encrypt before options were introduced, with
+	// the same deterministic keys
+	expected := "1d64d30257fe2e160e433381328ad07ad50f5c4ba5ffccfe6bbcac38f2ee083e" +
+		"bd2598343ad0d7b1547e1b327197a2f87ed895c2af13ddb2be4734"
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	private := suite.Scalar().Pick(blake2xb.New([]byte("ecies private key")))
+	public := suite.Point().Mul(private, nil)
+	message := []byte("Hello ECIES")
+
+	ciphertext, err := EncryptWithOptions(suite, public, message,
+		WithHash(suite.Hash),
+		WithEphemeralRand(blake2xb.New([]byte("ecies ephemeral key"))))
+	require.NoError(t, err)
+	require.Equal(t, expected, hex.EncodeToString(ciphertext))
+
+	plaintext, err := Decrypt(suite, private, ciphertext, suite.Hash)
+	require.NoError(t, err)
+	require.Equal(t, message, plaintext)
+}
+
+func TestECIESChaCha20Poly1305Vector(t *testing.T) {
+	expected := "1d64d30257fe2e160e433381328ad07ad50f5c4ba5ffccfe6bbcac38f2ee083e" +
+		"c34342b30ba22daef051efa42b94ecde9144a398ededfb81042473"
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	private := suite.Scalar().Pick(blake2xb.New([]byte("ecies private key")))
+	public := suite.Point().Mul(private, nil)
+	message := []byte("Hello ECIES")
+	opts := []Option{
+		WithHash(suite.Hash),
+		WithAEAD(chacha20poly1305.New),
+		WithKDFInfo(vectorInfo),
+	}
+
+	ciphertext, err := EncryptWithOptions(suite, public, message,
+		append(opts, WithEphemeralRand(blake2xb.New([]byte("ecies ephemeral key"))))...)
+	require.NoError(t, err)
+	require.Equal(t, expected, hex.EncodeToString(ciphertext))
+
+	buff, err := hex.DecodeString(expected)
+	require.NoError(t, err)
+	plaintext, err := DecryptWithOptions(suite, private, buff, opts...)
+	require.NoError(t, err)
+	require.Equal(t, message, plaintext)
+
+	// the default AES-GCM configuration can not decrypt it
+	_, err = Decrypt(suite, private, buff, suite.Hash)
+	require.Error(t, err)
+}
+
+func TestECIESOptions(t *testing.T) {
+	message := []byte("Hello ECIES")
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	private := suite.Scalar().Pick(random.New())
+	public := suite.Point().Mul(private, nil)
+
+	// no option is the same as Encrypt with SHA256
+	ciphertext, err := EncryptWithOptions(suite, public, message)
+	require.NoError(t, err)
+	plaintext, err := Decrypt(suite, private, ciphertext, nil)
+	require.NoError(t, err)
+	require.Equal(t, message, plaintext)
+
+	ciphertext, err = Encrypt(suite, public, message, nil)
+	require.NoError(t, err)
+	plaintext, err = DecryptWithOptions(suite, private, ciphertext)
+	require.NoError(t, err)
+	require.Equal(t, message, plaintext)
+
+	// truncated ciphertext
+	_, err = DecryptWithOptions(suite, private, ciphertext[:suite.PointLen()-1])
+	require.Error(t, err)
+}
+
+func TestECIESMismatchedKDFInfo(t *testing.T) {
+	message := []byte("Hello ECIES")
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	private := suite.Scalar().Pick(random.New())
+	public := suite.Point().Mul(private, nil)
+
+	ciphertext, err := EncryptWithOptions(suite, public, message,
+		WithAEAD(chacha20poly1305.New), WithKDFInfo(vectorInfo))
+	require.NoError(t, err)
+
+	plaintext, err := DecryptWithOptions(suite, private, ciphertext,
+		WithAEAD(chacha20poly1305.New), WithKDFInfo([]byte("another context")))
+	require.Error(t, err)
+	require.Nil(t, plaintext)
+
+	plaintext, err = DecryptWithOptions(suite, private, ciphertext,
+		WithAEAD(chacha20poly1305.New))
+	require.Error(t, err)
+	require.Nil(t, plaintext)
+}
+
+func TestECIESAssociatedData(t *testing.T) {
+	message := []byte("Hello ECIES")
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	private := suite.Scalar().Pick(random.New())
+	public := suite.Point().Mul(private, nil)
+
+	ciphertext, err := EncryptWithOptions(suite, public, message, WithAssociatedData([]byte("header")))
+	require.NoError(t, err)
+
+	plaintext, err := DecryptWithOptions(suite, private, ciphertext, WithAssociatedData([]byte("header")))
+	require.NoError(t, err)
+	require.Equal(t, message, plaintext)
+
+	plaintext, err = DecryptWithOptions(suite, private, ciphertext, WithAssociatedData([]byte("tampered")))
+	require.Error(t, err)
+	require.Nil(t, plaintext)
+
+	_, err = Decrypt(suite, private, ciphertext, nil)
+	require.Error(t, err)
+}
+
+func TestECIESWithAAD(t *testing.T) {
+	message := []byte("Hello ECIES")
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	private := suite.Scalar().Pick(random.New())
+	public := suite.Point().Mul(private, nil)
+	session := []byte("session 1")
+
+	ciphertext, err := EncryptWithAAD(suite, public, message, session, nil)
+	require.NoError(t, err)
+	plaintext, err := DecryptWithAAD(suite, private, ciphertext, session, nil)
+	require.NoError(t, err)
+	require.Equal(t, message, plaintext)
+	plaintext, err = DecryptWithOptions(suite, private, ciphertext, WithAssociatedData(session))
+	require.NoError(t, err)
+	require.Equal(t, message, plaintext)
+
+	_, err = DecryptWithAAD(suite, private, ciphertext, []byte("session 2"), nil)
+	require.Error(t, err)
+	_, err = Decrypt(suite, private, ciphertext, nil)
+	require.Error(t, err)
+	ciphertext, err = Encrypt(suite, public, message, nil)
+	require.NoError(t, err)
+	_, err = DecryptWithAAD(suite, private, ciphertext, session, nil)
+	require.Error(t, err)
+}
+
+func TestECIESDecryptWithDH(t *testing.T) {
+	message := []byte("Hello ECIES")
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	private := suite.Scalar().Pick(random.New())
+	public := suite.Point().Mul(private, nil)
+
+	ciphertext, err := EncryptWithOptions(suite, public, message, WithAssociatedData([]byte("header")))
+	require.NoError(t, err)
+	R := suite.Point()
+	require.NoError(t, R.UnmarshalBinary(ciphertext[:suite.PointLen()]))
+	dh := suite.Point().Mul(private, R)
+
+	plaintext, err := DecryptWithDH(suite, dh, ciphertext, WithAssociatedData([]byte("header")))
+	require.NoError(t, err)
+	require.Equal(t, message, plaintext)
+
+	_, err = DecryptWithDH(suite, public, ciphertext, WithAssociatedData([]byte("header")))
+	require.Error(t, err)
+	_, err = DecryptWithDH(suite, dh, ciphertext)
+	require.Error(t, err)
+	_, err = DecryptWithDH(suite, dh, ciphertext[:suite.PointLen()-1])
+	require.Error(t, err)
+}
@@ -0,0 +1,144 @@
+// Package elgamal implements ElGamal encryption of points and the
+// re-encryption of ElGamal ciphertexts, the building block of re-encryption
+// mixnets such as the ones built with the shuffle package.
+//
+// A point M is encrypted to the public key Y = xG as the pair (K, C) =
+// (kG, kY + M) for a fresh random scalar k. Rerandomize turns it into
+// (K + rG, C + rY), which encrypts the same point with the randomness k + r
+// and cannot be linked to (K, C) without the private key x.
+// ProveRerandomize proves that a pair is a re-encryption of another one,
+// without revealing r, and VerifyRerandomize checks such a proof.
+package elgamal
+
+import (
+	"crypto/cipher"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/proof"
+)
+
+// Suite wraps the functionalities needed by the elgamal package. These are
+// the same as the ones needed by the proof package.
+type Suite proof.Suite
+
+// ErrInvalidProof is returned by VerifyRerandomize when the proof does not
+// show that the second ciphertext is a re-encryption of the first one.
+var ErrInvalidProof = errors.New("elgamal: invalid re-encryption proof")
+
+// protocolName is the domain separation tag of the re-encryption proofs.
+const protocolName = "kyber/elgamal/rerandomize"
+
+// rerandomizePred states that the differences dK = K2 - K and dC = C2 - C
+// between the two ciphertexts are rG and rY for the same secret r.
+var rerandomizePred = proof.And(
+	proof.Rep("dK", "r", "G"),
+	proof.Rep("dC", "r", "Y"),
+)
+
+// Encrypt encrypts the point M to the public key Y and returns the
+// ciphertext (K, C). The randomness is drawn from rand, or from the random
+// stream of the suite if rand is nil.
+func Encrypt(suite Suite, Y, M kyber.Point, rand cipher.Stream) (K, C kyber.Point) {
+	if rand == nil {
+		rand = suite.RandomStream()
+	}
+	k := suite.Scalar().Pick(rand)
+	K = suite.Point().Mul(k, nil)
+	C = suite.Point().Mul(k, Y)
+	C.Add(C, M)
+	return K, C
+}
+
+// Decrypt decrypts the ciphertext (K, C) with the private key x and returns
+// the encrypted point.
+func Decrypt(suite Suite, x kyber.Scalar, K, C kyber.Point) kyber.Point {
+	S := suite.Point().Mul(x, K)
+	return S.Sub(C, S)
+}
+
+// Rerandomize re-encrypts the ciphertext (K, C), encrypted to the public key
+// Y, with fresh randomness: the returned ciphertext (K2, C2) decrypts to the
+// same point. It also returns the randomness r that was added, which
+// ProveRerandomize needs and which must be kept secret otherwise. The
+// randomness is drawn from rand, or from the random stream of the suite if
+// rand is nil.
+func Rerandomize(suite Suite, Y, K, C kyber.Point, rand cipher.Stream) (K2, C2 kyber.Point, r kyber.Scalar) {
+	if rand == nil {
+		rand = suite.RandomStream()
+	}
+	r = suite.Scalar().Pick(rand)
+	K2 = suite.Point().Mul(r, nil)
+	K2.Add(K2, K)
+	C2 = suite.Point().Mul(r, Y)
+	C2.Add(C2, C)
+	return K2, C2, r
+}
+
+// ProveRerandomize returns a non-interactive zero-knowledge proof that
+// (K2, C2) is the re-encryption of (K, C) under the public key Y with the
+// randomness r returned by Rerandomize, i.e. a proof of equality of the
+// discrete logarithms of K2 - K to the base G and of C2 - C to the base Y.
+// The proof is in its binary form, of SizeRerandomizeProof bytes, and is
+// bound to the public key and both ciphertexts.
+func ProveRerandomize(suite Suite, Y, K, C, K2, C2 kyber.Point, r kyber.Scalar) ([]byte, error) {
+	name, err := statement(Y, K, C, K2, C2)
+	if err != nil {
+		return nil, err
+	}
+	sval := map[string]kyber.Scalar{"r": r}
+	pval := statementPoints(suite, Y, K, C, K2, C2)
+	prover := rerandomizePred.Prover(suite, sval, pval, nil)
+	return proof.HashProve(suite, name, prover)
+}
+
+// VerifyRerandomize checks a proof returned by ProveRerandomize that
+// (K2, C2) is a re-encryption of (K, C) under the public key Y. It returns
+// an error wrapping ErrInvalidProof if the proof does not hold.
+func VerifyRerandomize(suite Suite, Y, K, C, K2, C2 kyber.Point, prf []byte) error {
+	if len(prf) != SizeRerandomizeProof(suite) {
+		return fmt.Errorf("%w: proof of %d bytes instead of %d", ErrInvalidProof,
+			len(prf), SizeRerandomizeProof(suite))
+	}
+	name, err := statement(Y, K, C, K2, C2)
+	if err != nil {
+		return err
+	}
+	verifier := rerandomizePred.Verifier(suite, statementPoints(suite, Y, K, C, K2, C2))
+	if err := proof.HashVerify(suite, name, verifier, prf); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidProof, err)
+	}
+	return nil
+}
+
+// SizeRerandomizeProof returns the length of the binary encoding of a
+// re-encryption proof: the commitments to dK and dC followed by the response.
+func SizeRerandomizeProof(suite Suite) int {
+	return 2*suite.PointLen() + suite.ScalarLen()
+}
+
+// statementPoints returns the public points of rerandomizePred.
+func statementPoints(suite Suite, Y, K, C, K2, C2 kyber.Point) map[string]kyber.Point {
+	return map[string]kyber.Point{
+		"G":  suite.Point().Base(),
+		"Y":  Y,
+		"dK": suite.Point().Sub(K2, K),
+		"dC": suite.Point().Sub(C2, C),
+	}
+}
+
+// statement returns the protocol name given to the Fiat-Shamir transform,
+// which binds the proof to the public key and to both ciphertexts and not
+// only to their differences.
+func statement(Y, K, C, K2, C2 kyber.Point) (string, error) {
+	buff := []byte(protocolName)
+	for _, p := range []kyber.Point{Y, K, C, K2, C2} {
+		b, err := p.MarshalBinary()
+		if err != nil {
+			return "", fmt.Errorf("elgamal: %v", err)
+		}
+		buff = append(buff, b...)
+	}
+	return string(buff), nil
+}
@@ -0,0 +1,85 @@
+package elgamal
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	x := suite.Scalar().Pick(suite.RandomStream())
+	Y := suite.Point().Mul(x, nil)
+	M := suite.Point().Embed([]byte("mixnet"), random.New())
+
+	K, C := Encrypt(suite, Y, M, nil)
+	require.True(t, Decrypt(suite, x, K, C).Equal(M))
+}
+
+func TestRerandomizeChain(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	x := suite.Scalar().Pick(suite.RandomStream())
+	Y := suite.Point().Mul(x, nil)
+	message := []byte("the original vote")
+	M := suite.Point().Embed(message, random.New())
+	K, C := Encrypt(suite, Y, M, nil)
+
+	for i := 0; i < 3; i++ {
+		K2, C2, r := Rerandomize(suite, Y, K, C, nil)
+		require.False(t, K2.Equal(K))
+		require.False(t, C2.Equal(C))
+
+		prf, err := ProveRerandomize(suite, Y, K, C, K2, C2, r)
+		require.NoError(t, err)
+		require.Len(t, prf, SizeRerandomizeProof(suite))
+		require.NoError(t, VerifyRerandomize(suite, Y, K, C, K2, C2, prf))
+
+		K, C = K2, C2
+	}
+
+	data, err := Decrypt(suite, x, K, C).Data()
+	require.NoError(t, err)
+	require.Equal(t, message, data)
+}
+
+func TestRerandomizeInvalidProof(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	x := suite.Scalar().Pick(suite.RandomStream())
+	Y := suite.Point().Mul(x, nil)
+	M := suite.Point().Embed([]byte("yes"), random.New())
+	K, C := Encrypt(suite, Y, M, nil)
+	K2, C2, r := Rerandomize(suite, Y, K, C, nil)
+	prf, err := ProveRerandomize(suite, Y, K, C, K2, C2, r)
+	require.NoError(t, err)
+
+	check := func(Y, K, C, K2, C2 kyber.Point, prf []byte) {
+		err := VerifyRerandomize(suite, Y, K, C, K2, C2, prf)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrInvalidProof))
+	}
+
+	// a ciphertext which swaps the plaintext for another one
+	M2 := suite.Point().Embed([]byte("no"), random.New())
+	swapped := suite.Point().Sub(C2, M)
+	swapped.Add(swapped, M2)
+	check(Y, K, C, K2, swapped, prf)
+	// a proof of the swap made with the randomness of the honest one
+	forged, err := ProveRerandomize(suite, Y, K, C, K2, swapped, r)
+	require.NoError(t, err)
+	check(Y, K, C, K2, swapped, forged)
+
+	// another public key
+	check(suite.Point().Pick(suite.RandomStream()), K, C, K2, C2, prf)
+	// another input ciphertext
+	check(Y, K2, C2, K2, C2, prf)
+	// a tampered or truncated proof
+	tampered := append([]byte{}, prf...)
+	tampered[len(tampered)-1] ^= 0x01
+	check(Y, K, C, K2, C2, tampered)
+	check(Y, K, C, K2, C2, prf[:len(prf)-1])
+	check(Y, K, C, K2, C2, append(prf, 0))
+}
@@ -0,0 +1,171 @@
+// Package ibe implements the identity-based encryption of "Identity-Based
+// Encryption from the Weil Pairing" by Boneh and Franklin, in its chosen
+// ciphertext secure variant, on top of the BLS signatures of kyber/sign/bls:
+// the master public key is a BLS public key, and the private key of an
+// identity is the BLS signature of the identity under the master private
+// key. A message encrypted to an identity thus decrypts with the signature of
+// the identity, which anyone can check against the master public key.
+//
+// With the threshold BLS signatures of a randomness beacon, see
+// kyber/sign/beacon, the identities are the rounds of the beacon and a message
+// encrypted to a future round decrypts once the beacon publishes the
+// signature of the round: this is timelock encryption, as the tlock scheme
+// of drand, see TimelockEncrypt. The identities of the rounds and the layout
+// of the ciphertexts are the ones of tlock, but the ciphertexts of a drand
+// network only decrypt with a suite of its curve, BLS12-381, and a BLS scheme
+// hashing the messages as drand does.
+//
+// The ciphertext of a message m to the identity ID is (U, V, W) with
+//
+//	U = r*B, V = σ ⊕ H2(e(H(ID), X)^r), W = m ⊕ H4(σ), r = H3(σ, m)
+//
+// for a random σ, the base point B and the master public key X of the key
+// group, and the hash H of the messages of the BLS scheme onto the signature
+// group. The decryption recovers σ from e(S, U) for the signature S of ID,
+// then m, and checks that U = H3(σ, m)*B.
+package ibe
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/sign/bls"
+	"go.dedis.ch/kyber/v3/util/random"
+	"go.dedis.ch/kyber/v3/xof/blake2xb"
+)
+
+// sigmaLen is the length of the random σ of the encryption.
+const sigmaLen = 32
+
+// ErrDecryption is returned when a ciphertext does not decrypt with the given
+// private key, because it was encrypted to another identity or modified.
+var ErrDecryption = errors.New("ibe: invalid ciphertext or private key")
+
+// Domain separation of the hashes H2, H3 and H4.
+var (
+	h2Domain = []byte("IBE-H2")
+	h3Domain = []byte("IBE-H3")
+	h4Domain = []byte("IBE-H4")
+)
+
+// Ciphertext is a ciphertext (U, V, W) of the scheme.
+type Ciphertext struct {
+	// U = r*B, a point of the key group
+	U kyber.Point
+	// V = σ ⊕ H2(e(H(ID), X)^r)
+	V []byte
+	// W = m ⊕ H4(σ), as long as the message
+	W []byte
+}
+
+// Encrypt encrypts the message to the identity under the master public key,
+// a point of the key group of the BLS scheme. The ciphertext decrypts with
+// the BLS signature of the identity under the master private key.
+func Encrypt(scheme *bls.Scheme, master kyber.Point, id, msg []byte) (*Ciphertext, error) {
+	if master == nil {
+		return nil, errors.New("ibe: nil master public key")
+	}
+	Qid, err := scheme.HashMessage(id)
+	if err != nil {
+		return nil, err
+	}
+	sigma := random.Bits(8*sigmaLen, false, random.New())
+	r := h3(scheme, sigma, msg)
+	// e(H(ID), X)^r = e(H(ID), r*X)
+	gidr := scheme.Pair(Qid, scheme.KeyGroup().Point().Mul(r, master))
+	h, err := h2(gidr)
+	if err != nil {
+		return nil, err
+	}
+	return &Ciphertext{
+		U: scheme.KeyGroup().Point().Mul(r, nil),
+		V: xor(sigma, h),
+		W: xor(msg, h4(sigma, len(msg))),
+	}, nil
+}
+
+// Decrypt decrypts the ciphertext with the private key of its identity, i.e.
+// the BLS signature of the identity, a point of the signature group. It
+// returns ErrDecryption if the ciphertext was encrypted to another identity
+// or modified.
+func Decrypt(scheme *bls.Scheme, private kyber.Point, c *Ciphertext) ([]byte, error) {
+	if c == nil || c.U == nil || len(c.V) != sigmaLen {
+		return nil, errors.New("ibe: malformed ciphertext")
+	}
+	h, err := h2(scheme.Pair(private, c.U))
+	if err != nil {
+		return nil, err
+	}
+	sigma := xor(c.V, h)
+	msg := xor(c.W, h4(sigma, len(c.W)))
+	r := h3(scheme, sigma, msg)
+	if !scheme.KeyGroup().Point().Mul(r, nil).Equal(c.U) {
+		return nil, ErrDecryption
+	}
+	return msg, nil
+}
+
+// MarshalBinary encodes the ciphertext as U || V || W.
+func (c *Ciphertext) MarshalBinary() ([]byte, error) {
+	u, err := c.U.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return append(append(u, c.V...), c.W...), nil
+}
+
+// UnmarshalCiphertext decodes a ciphertext of the scheme encoded with
+// MarshalBinary.
+func UnmarshalCiphertext(scheme *bls.Scheme, b []byte) (*Ciphertext, error) {
+	U := scheme.KeyGroup().Point()
+	l := U.MarshalSize()
+	if len(b) < l+sigmaLen {
+		return nil, errors.New("ibe: ciphertext too short")
+	}
+	if err := U.UnmarshalBinary(b[:l]); err != nil {
+		return nil, fmt.Errorf("ibe: invalid ciphertext: %w", err)
+	}
+	return &Ciphertext{
+		U: U,
+		V: append([]byte{}, b[l:l+sigmaLen]...),
+		W: append([]byte{}, b[l+sigmaLen:]...),
+	}, nil
+}
+
+// h2 hashes the point of GT to the mask of σ.
+func h2(gt kyber.Point) ([]byte, error) {
+	buf, err := gt.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	_, _ = h.Write(h2Domain)
+	_, _ = h.Write(buf)
+	return h.Sum(nil), nil
+}
+
+// h3 hashes σ and the message to the scalar r.
+func h3(scheme *bls.Scheme, sigma, msg []byte) kyber.Scalar {
+	h := sha256.New()
+	_, _ = h.Write(h3Domain)
+	_, _ = h.Write(sigma)
+	_, _ = h.Write(msg)
+	return scheme.KeyGroup().Scalar().Pick(blake2xb.New(h.Sum(nil)))
+}
+
+// h4 derives the mask of the message of length n from σ.
+func h4(sigma []byte, n int) []byte {
+	mask := make([]byte, n)
+	blake2xb.New(append(append([]byte{}, h4Domain...), sigma...)).XORKeyStream(mask, mask)
+	return mask
+}
+
+func xor(a, b []byte) []byte {
+	r := make([]byte, len(a))
+	for i := range a {
+		r[i] = a[i] ^ b[i]
+	}
+	return r
+}
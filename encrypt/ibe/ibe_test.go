@@ -0,0 +1,112 @@
+package ibe
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing/bn256"
+	"go.dedis.ch/kyber/v3/share"
+	"go.dedis.ch/kyber/v3/sign/beacon"
+	"go.dedis.ch/kyber/v3/sign/bls"
+	"go.dedis.ch/kyber/v3/sign/tbls"
+)
+
+var suite = bn256.NewSuite()
+
+func TestIBE(t *testing.T) {
+	for _, scheme := range []*bls.Scheme{bls.NewSchemeOnG1(suite), bls.NewSchemeOnG2(suite)} {
+		x, X := scheme.NewKeyPair(suite.RandomStream())
+		id := []byte("alice@example.com")
+		msg := []byte("Hello Boneh-Franklin")
+		c, err := Encrypt(scheme, X, id, msg)
+		require.NoError(t, err)
+
+		sig, err := scheme.Sign(x, id)
+		require.NoError(t, err)
+		private := scheme.SignatureGroup().Point()
+		require.NoError(t, private.UnmarshalBinary(sig))
+		decrypted, err := Decrypt(scheme, private, c)
+		require.NoError(t, err)
+		require.Equal(t, msg, decrypted)
+
+		// the ciphertext survives its encoding
+		buf, err := c.MarshalBinary()
+		require.NoError(t, err)
+		c2, err := UnmarshalCiphertext(scheme, buf)
+		require.NoError(t, err)
+		decrypted, err = Decrypt(scheme, private, c2)
+		require.NoError(t, err)
+		require.Equal(t, msg, decrypted)
+		_, err = UnmarshalCiphertext(scheme, buf[:scheme.KeyGroup().PointLen()+sigmaLen-1])
+		require.Error(t, err)
+
+		// the private key of another identity does not decrypt
+		other, err := scheme.Sign(x, []byte("bob@example.com"))
+		require.NoError(t, err)
+		require.NoError(t, private.UnmarshalBinary(other))
+		_, err = Decrypt(scheme, private, c)
+		require.True(t, errors.Is(err, ErrDecryption))
+
+		// a modified ciphertext does not decrypt
+		require.NoError(t, private.UnmarshalBinary(sig))
+		c2.W[0] ^= 1
+		_, err = Decrypt(scheme, private, c2)
+		require.True(t, errors.Is(err, ErrDecryption))
+		c2, err = UnmarshalCiphertext(scheme, buf)
+		require.NoError(t, err)
+		c2.V[0] ^= 1
+		_, err = Decrypt(scheme, private, c2)
+		require.True(t, errors.Is(err, ErrDecryption))
+	}
+}
+
+func TestTimelock(t *testing.T) {
+	n, th := 5, 3
+	scheme := tbls.NewSchemeOnG1(suite)
+	poly := share.NewPriPoly(suite.G2(), th, nil, suite.RandomStream())
+	public := poly.Commit(suite.G2().Point().Base())
+	shares := poly.Shares(n)
+	b := beacon.NewBeacon(scheme, public, n)
+
+	round := uint64(42)
+	msg := []byte("Hello from the past")
+	c, err := TimelockEncrypt(scheme, b.PublicKey(), round, msg)
+	require.NoError(t, err)
+
+	sign := func(round uint64) *beacon.Round {
+		partials := make([][]byte, n)
+		for i, s := range shares {
+			partials[i], err = b.PartialSign(s, round, nil)
+			require.NoError(t, err)
+		}
+		r, _, err := b.Aggregate(round, nil, partials)
+		require.NoError(t, err)
+		require.NoError(t, b.Verify(r))
+		return r
+	}
+
+	decrypted, err := TimelockDecrypt(scheme, sign(round), c)
+	require.NoError(t, err)
+	require.Equal(t, msg, decrypted)
+	require.Equal(t, RoundID(round), sign(round).Message())
+
+	// the signature of another round does not decrypt
+	_, err = TimelockDecrypt(scheme, sign(round-1), c)
+	require.True(t, errors.Is(err, ErrDecryption))
+
+	// nor the one of a chained beacon
+	r := sign(round)
+	r.PreviousSignature = []byte{1}
+	_, err = TimelockDecrypt(scheme, r, c)
+	require.True(t, errors.Is(err, ErrChainedRound))
+
+	// the public key must be the one of the beacon
+	_, err = TimelockEncrypt(scheme, nil, round, msg)
+	require.Error(t, err)
+	wrong := suite.G2().Point().Pick(suite.RandomStream())
+	c, err = TimelockEncrypt(scheme, wrong, round, msg)
+	require.NoError(t, err)
+	_, err = TimelockDecrypt(scheme, sign(round), c)
+	require.True(t, errors.Is(err, ErrDecryption))
+}
@@ -0,0 +1,45 @@
+package ibe
+
+import (
+	"errors"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/sign/beacon"
+	"go.dedis.ch/kyber/v3/sign/tbls"
+)
+
+// ErrChainedRound is returned by TimelockDecrypt for the round of a chained
+// beacon, whose identity cannot be known in advance.
+var ErrChainedRound = errors.New("ibe: timelock encryption needs an unchained beacon")
+
+// RoundID returns the identity of the round of an unchained beacon, which is
+// the message the beacon signs for the round: the SHA-256 hash of the round
+// number as 8 bytes in big endian, as drand, see beacon.Message.
+func RoundID(round uint64) []byte {
+	return beacon.Message(round, nil)
+}
+
+// TimelockEncrypt encrypts the message to the given round of the unchained
+// beacon of the distributed public key, whose signatures follow the
+// threshold BLS scheme. Nobody, the nodes of the beacon included, can
+// decrypt it before t nodes sign the round.
+func TimelockEncrypt(scheme *tbls.Scheme, public kyber.Point, round uint64, msg []byte) (*Ciphertext, error) {
+	return Encrypt(scheme.BLS(), public, RoundID(round), msg)
+}
+
+// TimelockDecrypt decrypts the ciphertext encrypted to the round with its
+// signature. It returns ErrDecryption if the ciphertext was encrypted to
+// another round or modified, or if the signature of the round is invalid.
+func TimelockDecrypt(scheme *tbls.Scheme, r *beacon.Round, c *Ciphertext) ([]byte, error) {
+	if r == nil {
+		return nil, errors.New("ibe: nil round")
+	}
+	if r.PreviousSignature != nil {
+		return nil, ErrChainedRound
+	}
+	S := scheme.BLS().SignatureGroup().Point()
+	if err := S.UnmarshalBinary(r.Signature); err != nil {
+		return nil, err
+	}
+	return Decrypt(scheme.BLS(), S, c)
+}
@@ -0,0 +1,109 @@
+package threshold
+
+import (
+	"bytes"
+	"errors"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/proof/dleq"
+	"go.dedis.ch/kyber/v3/share"
+)
+
+var errInvalidProof = errors.New("threshold: invalid decryption proof")
+var errInvalidBase = errors.New("threshold: public polynomial not committed with the base point")
+
+// DecryptElGamal combines the decryption shares of the ElGamal ciphertext
+// (K, C) = (k*G, k*X + M) encrypted to the distributed public key X, as
+// Combine does for the header K, and returns the message point M = C - x*K.
+// As Combine, it returns an InvalidSharesError if any decryption share is
+// invalid, and ErrTooFewShares if less than t decryption shares of distinct
+// nodes are given.
+func DecryptElGamal(suite Suite, pubPoly *share.PubPoly, K, C kyber.Point, shares []*DecryptionShare, t, n int) (kyber.Point, error) {
+	if C == nil {
+		return nil, errors.New("threshold: nil ciphertext")
+	}
+	S, err := Combine(suite, pubPoly, K, shares, t, n)
+	if err != nil {
+		return nil, err
+	}
+	return S.Sub(C, S), nil
+}
+
+// VerifyShare checks the proof of the decryption share of the header against
+// the public share of its node, given by the public polynomial of the
+// distributed key. The proof is a DLEQ proof that log_G(X_i) == log_K(D_i),
+// where G is the base point of the suite, X_i the public share and D_i the
+// decryption share: the public polynomial must be committed with the base
+// point, as the ones of the DKG are. It returns nil if the decryption share
+// is valid.
+func VerifyShare(suite Suite, pubPoly *share.PubPoly, header kyber.Point, s *DecryptionShare) error {
+	if err := checkBase(suite, pubPoly); err != nil {
+		return err
+	}
+	return verifyShare(suite, pubPoly, header, s)
+}
+
+// checkBase returns errInvalidBase if the public polynomial is committed with
+// another base than the base point of the suite, which PartialDecrypt uses.
+func checkBase(suite Suite, pubPoly *share.PubPoly) error {
+	if pubPoly == nil {
+		return errors.New("threshold: nil public polynomial")
+	}
+	if b, _ := pubPoly.Info(); b != nil && !b.Equal(suite.Point().Base()) {
+		return errInvalidBase
+	}
+	return nil
+}
+
+func verifyShare(suite Suite, pubPoly *share.PubPoly, header kyber.Point, s *DecryptionShare) error {
+	if s == nil || s.Share == nil || s.Share.V == nil || s.Share.I < 0 {
+		return ErrInvalidShare
+	}
+	p, err := unmarshalProof(suite, s.Proof)
+	if err != nil {
+		return err
+	}
+	X := pubPoly.Eval(s.Share.I).V
+	if err := p.Verify(suite, suite.Point().Base(), header, X, s.Share.V); err != nil {
+		return errInvalidProof
+	}
+	return nil
+}
+
+// marshalProof encodes the proof as C || R || VG || VH.
+func marshalProof(p *dleq.Proof) ([]byte, error) {
+	var b bytes.Buffer
+	for _, m := range []kyber.Marshaling{p.C, p.R, p.VG, p.VH} {
+		if _, err := m.MarshalTo(&b); err != nil {
+			return nil, err
+		}
+	}
+	return b.Bytes(), nil
+}
+
+func unmarshalProof(suite Suite, buff []byte) (*dleq.Proof, error) {
+	p := &dleq.Proof{
+		C:  suite.Scalar(),
+		R:  suite.Scalar(),
+		VG: suite.Point(),
+		VH: suite.Point(),
+	}
+	sl := suite.ScalarLen()
+	pl := suite.PointLen()
+	if len(buff) != 2*sl+2*pl {
+		return nil, errInvalidProof
+	}
+	if err := p.C.UnmarshalBinary(buff[:sl]); err != nil {
+		return nil, err
+	}
+	if err := p.R.UnmarshalBinary(buff[sl : 2*sl]); err != nil {
+		return nil, err
+	}
+	if err := p.VG.UnmarshalBinary(buff[2*sl : 2*sl+pl]); err != nil {
+		return nil, err
+	}
+	if err := p.VH.UnmarshalBinary(buff[2*sl+pl:]); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
@@ -0,0 +1,72 @@
+package threshold
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/share"
+)
+
+func elGamalEncrypt(public kyber.Point, msg []byte) (K, C kyber.Point) {
+	M := suite.Point().Embed(msg, suite.RandomStream())
+	k := suite.Scalar().Pick(suite.RandomStream())
+	K = suite.Point().Mul(k, nil)
+	C = suite.Point().Mul(k, public)
+	return K, C.Add(C, M)
+}
+
+func TestDecryptElGamal(t *testing.T) {
+	n, th := 7, 3
+	priPoly := share.NewPriPoly(suite, th, nil, suite.RandomStream())
+	pubPoly := priPoly.Commit(nil)
+	priShares := priPoly.Shares(n)
+
+	msg := []byte("Hello threshold")
+	K, C := elGamalEncrypt(pubPoly.Commit(), msg)
+
+	shares := make([]*DecryptionShare, n)
+	for i, s := range priShares {
+		var err error
+		shares[i], err = PartialDecrypt(suite, s, K)
+		require.NoError(t, err)
+		require.NoError(t, VerifyShare(suite, pubPoly, K, shares[i]))
+	}
+
+	// any t shares decrypt
+	M, err := DecryptElGamal(suite, pubPoly, K, C, shares[n-th:], th, n)
+	require.NoError(t, err)
+	decrypted, err := M.Data()
+	require.NoError(t, err)
+	require.Equal(t, msg, decrypted)
+
+	// a bad decryption share and a valid proof of another node are pointed at
+	shares[1] = &DecryptionShare{Share: &share.PubShare{I: 1, V: suite.Point().Pick(suite.RandomStream())}, Proof: shares[1].Proof}
+	shares[2] = &DecryptionShare{Share: shares[2].Share, Proof: shares[3].Proof}
+	_, err = DecryptElGamal(suite, pubPoly, K, C, shares, th, n)
+	var invalid *InvalidSharesError
+	require.True(t, errors.As(err, &invalid))
+	require.Equal(t, []int{1, 2}, invalid.Positions)
+
+	// malformed proof
+	bad := &DecryptionShare{Share: shares[0].Share, Proof: shares[0].Proof[1:]}
+	require.Equal(t, errInvalidProof, VerifyShare(suite, pubPoly, K, bad))
+}
+
+func TestVerifyShareBase(t *testing.T) {
+	n, th := 5, 3
+	priPoly := share.NewPriPoly(suite, th, nil, suite.RandomStream())
+	priShares := priPoly.Shares(n)
+	K := suite.Point().Pick(suite.RandomStream())
+	s, err := PartialDecrypt(suite, priShares[0], K)
+	require.NoError(t, err)
+
+	// the decryption shares are proven with the base point of the suite, the
+	// one of the public polynomials committed with a nil or the base point
+	require.NoError(t, VerifyShare(suite, priPoly.Commit(suite.Point().Base()), K, s))
+	other := priPoly.Commit(suite.Point().Pick(suite.RandomStream()))
+	require.Equal(t, errInvalidBase, VerifyShare(suite, other, K, s))
+	_, err = Combine(suite, other, K, []*DecryptionShare{s}, th, n)
+	require.Equal(t, errInvalidBase, err)
+}
@@ -0,0 +1,211 @@
+package threshold
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/share"
+)
+
+// ErrInvalidCiphertext is returned for a TDH2 ciphertext whose proof of
+// validity does not verify.
+var ErrInvalidCiphertext = errors.New("threshold: invalid TDH2 ciphertext")
+
+// Domain separation of the hashes of TDH2 and seed of its second generator.
+var (
+	tdh2KeyDomain       = []byte("kyber.threshold.TDH2.key")
+	tdh2ChallengeDomain = []byte("kyber.threshold.TDH2.challenge")
+	tdh2Generator       = []byte("kyber.threshold.TDH2.generator")
+)
+
+// TDH2Ciphertext is a ciphertext of the TDH2 scheme of "Securing Threshold
+// Cryptosystems against Chosen Ciphertext Attack" by Shoup and Gennaro. Unlike
+// the ciphertexts of Encrypt, it carries a proof of its validity which the
+// nodes check before computing their decryption share, so that an adversary
+// cannot get the decryption shares of a ciphertext it derived from another
+// one: the scheme is secure against chosen ciphertext attacks, and the label
+// is bound to the ciphertext.
+type TDH2Ciphertext struct {
+	// Payload is the message XOR the key stream derived from r*X
+	Payload []byte
+	// Label is public data bound to the ciphertext, e.g. the identity of the
+	// recipient of the decryption
+	Label []byte
+	// U = r*G and Ubar = r*Gbar for the base point G, the second generator
+	// Gbar and the random r of the encryption
+	U, Ubar kyber.Point
+	// E and F are the proof that U and Ubar have the same discrete logarithm,
+	// bound to the payload and the label
+	E, F kyber.Scalar
+}
+
+// EncryptTDH2 encrypts the message to the distributed public key with TDH2,
+// binding the label to the ciphertext. Any t nodes holding shares of the
+// private key decrypt it with PartialDecryptTDH2 and DecryptTDH2.
+func EncryptTDH2(suite Suite, public kyber.Point, msg, label []byte) (*TDH2Ciphertext, error) {
+	if public == nil {
+		return nil, errors.New("threshold: nil public key")
+	}
+	rand := suite.RandomStream()
+	r := suite.Scalar().Pick(rand)
+	s := suite.Scalar().Pick(rand)
+	gbar := tdh2GeneratorPoint(suite)
+
+	c := &TDH2Ciphertext{
+		Payload: make([]byte, len(msg)),
+		Label:   append([]byte{}, label...),
+		U:       suite.Point().Mul(r, nil),
+		Ubar:    suite.Point().Mul(r, gbar),
+	}
+	if err := tdh2KeyStream(suite, suite.Point().Mul(r, public), c.Payload, msg); err != nil {
+		return nil, err
+	}
+	w := suite.Point().Mul(s, nil)
+	wbar := suite.Point().Mul(s, gbar)
+	e, err := tdh2Challenge(suite, c, w, wbar)
+	if err != nil {
+		return nil, err
+	}
+	c.E = e
+	c.F = suite.Scalar().Add(s, suite.Scalar().Mul(r, e))
+	return c, nil
+}
+
+// Verify checks the proof of validity of the ciphertext, i.e. that
+// E == H(Payload, Label, U, F*G - E*U, Ubar, F*Gbar - E*Ubar). It returns
+// ErrInvalidCiphertext if it does not hold.
+func (c *TDH2Ciphertext) Verify(suite Suite) error {
+	if c == nil || c.U == nil || c.Ubar == nil || c.E == nil || c.F == nil {
+		return ErrInvalidCiphertext
+	}
+	gbar := tdh2GeneratorPoint(suite)
+	w := suite.Point().Sub(suite.Point().Mul(c.F, nil), suite.Point().Mul(c.E, c.U))
+	wbar := suite.Point().Sub(suite.Point().Mul(c.F, gbar), suite.Point().Mul(c.E, c.Ubar))
+	e, err := tdh2Challenge(suite, c, w, wbar)
+	if err != nil {
+		return err
+	}
+	if !e.Equal(c.E) {
+		return ErrInvalidCiphertext
+	}
+	return nil
+}
+
+// MarshalBinary encodes the ciphertext as U || Ubar || E || F, followed by the
+// length of the label on 4 bytes in big endian, the label and the payload.
+func (c *TDH2Ciphertext) MarshalBinary() ([]byte, error) {
+	var b []byte
+	for _, m := range []kyber.Marshaling{c.U, c.Ubar, c.E, c.F} {
+		buf, err := m.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		b = append(b, buf...)
+	}
+	var l [4]byte
+	binary.BigEndian.PutUint32(l[:], uint32(len(c.Label)))
+	b = append(b, l[:]...)
+	b = append(b, c.Label...)
+	return append(b, c.Payload...), nil
+}
+
+// UnmarshalTDH2Ciphertext decodes a ciphertext encoded with MarshalBinary.
+// It does not check its validity, see Verify.
+func UnmarshalTDH2Ciphertext(suite Suite, b []byte) (*TDH2Ciphertext, error) {
+	pl, sl := suite.PointLen(), suite.ScalarLen()
+	fixed := 2*pl + 2*sl + 4
+	if len(b) < fixed {
+		return nil, errors.New("threshold: TDH2 ciphertext too short")
+	}
+	c := &TDH2Ciphertext{
+		U:    suite.Point(),
+		Ubar: suite.Point(),
+		E:    suite.Scalar(),
+		F:    suite.Scalar(),
+	}
+	off := 0
+	for _, m := range []struct {
+		v kyber.Marshaling
+		l int
+	}{{c.U, pl}, {c.Ubar, pl}, {c.E, sl}, {c.F, sl}} {
+		if err := m.v.UnmarshalBinary(b[off : off+m.l]); err != nil {
+			return nil, fmt.Errorf("threshold: invalid TDH2 ciphertext: %w", err)
+		}
+		off += m.l
+	}
+	l := int(binary.BigEndian.Uint32(b[off:]))
+	off += 4
+	if l > len(b)-off {
+		return nil, errors.New("threshold: TDH2 label too long")
+	}
+	c.Label = append([]byte{}, b[off:off+l]...)
+	c.Payload = append([]byte{}, b[off+l:]...)
+	return c, nil
+}
+
+// PartialDecryptTDH2 checks the validity of the ciphertext and returns the
+// decryption share x_i*U of the node holding the private share priShare, with
+// the proof of its correctness. It returns ErrInvalidCiphertext without any
+// decryption share if the ciphertext is invalid.
+func PartialDecryptTDH2(suite Suite, priShare *share.PriShare, c *TDH2Ciphertext) (*DecryptionShare, error) {
+	if err := c.Verify(suite); err != nil {
+		return nil, err
+	}
+	return PartialDecrypt(suite, priShare, c.U)
+}
+
+// DecryptTDH2 checks the validity of the ciphertext, combines the decryption
+// shares as Combine, and returns the message. As Combine, it returns an
+// InvalidSharesError if any decryption share is invalid, and ErrTooFewShares
+// if less than t decryption shares of distinct nodes are given.
+func DecryptTDH2(suite Suite, pubPoly *share.PubPoly, c *TDH2Ciphertext, shares []*DecryptionShare, t, n int) ([]byte, error) {
+	if err := c.Verify(suite); err != nil {
+		return nil, err
+	}
+	h, err := Combine(suite, pubPoly, c.U, shares, t, n)
+	if err != nil {
+		return nil, err
+	}
+	msg := make([]byte, len(c.Payload))
+	if err := tdh2KeyStream(suite, h, msg, c.Payload); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// tdh2GeneratorPoint returns the second generator Gbar, whose discrete
+// logarithm to the base point nobody knows.
+func tdh2GeneratorPoint(suite Suite) kyber.Point {
+	return suite.Point().Pick(suite.XOF(tdh2Generator))
+}
+
+// tdh2KeyStream sets dst to src XOR the key stream derived from the DH point.
+func tdh2KeyStream(suite Suite, dh kyber.Point, dst, src []byte) error {
+	buf, err := dh.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	suite.XOF(append(append([]byte{}, tdh2KeyDomain...), buf...)).XORKeyStream(dst, src)
+	return nil
+}
+
+// tdh2Challenge returns the challenge of the proof of validity of the
+// ciphertext with the commitments w and wbar.
+func tdh2Challenge(suite Suite, c *TDH2Ciphertext, w, wbar kyber.Point) (kyber.Scalar, error) {
+	h := suite.Hash()
+	_, _ = h.Write(tdh2ChallengeDomain)
+	for _, b := range [][]byte{c.Payload, c.Label} {
+		if err := binary.Write(h, binary.BigEndian, uint64(len(b))); err != nil {
+			return nil, err
+		}
+		_, _ = h.Write(b)
+	}
+	for _, p := range []kyber.Point{c.U, w, c.Ubar, wbar} {
+		if _, err := p.MarshalTo(h); err != nil {
+			return nil, err
+		}
+	}
+	return suite.Scalar().Pick(suite.XOF(h.Sum(nil))), nil
+}
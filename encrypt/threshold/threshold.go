@@ -0,0 +1,172 @@
+// Package threshold implements the hybrid encryption of payloads of any size
+// to a distributed public key X = x*G, e.g. the one of a DKG, such that any t
+// of the n nodes holding a share of x can decrypt them together without ever
+// reconstructing x:
+//  1. Encrypt encrypts the payload with ECIES (see kyber/encrypt/ecies): an
+//     ephemeral key k gives the header K = k*G of the ciphertext, and the
+//     AEAD key is derived from the DH key k*X.
+//  2. Every node i holding the share x_i publishes the decryption share
+//     x_i*K of the header computed by PartialDecrypt, with a proof of its
+//     correctness against the public share of the node (see VerifyShare).
+//  3. Combine checks the decryption shares and interpolates t of them into
+//     the DH key x*K = k*X, with which Decrypt opens the AEAD.
+//
+// Combine checks every decryption share before using any of them, and fails
+// with an InvalidSharesError identifying the bad ones, so that a bad share
+// never gives a wrong DH key which would only fail when opening the AEAD.
+//
+// The decryption shares also decrypt the ElGamal ciphertexts (k*G, k*X + M)
+// of a message point M, see DecryptElGamal.
+//
+// The package also implements the TDH2 scheme of Shoup and Gennaro, see
+// EncryptTDH2, whose ciphertexts carry a proof of their validity which the
+// nodes check before computing their decryption share, so that the
+// decryption resists chosen ciphertext attacks by the requesters of the
+// decryption shares.
+package threshold
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/encrypt/ecies"
+	"go.dedis.ch/kyber/v3/proof/dleq"
+	"go.dedis.ch/kyber/v3/share"
+)
+
+// Suite describes the functionalities needed by this package.
+type Suite interface {
+	kyber.Group
+	kyber.HashFactory
+	kyber.XOFFactory
+	kyber.Random
+}
+
+// ErrInvalidShare is wrapped by the InvalidSharesError of Combine.
+var ErrInvalidShare = errors.New("threshold: invalid decryption share")
+
+// ErrTooFewShares is returned by Combine when it is given less than t
+// decryption shares of distinct nodes.
+var ErrTooFewShares = errors.New("threshold: not enough decryption shares")
+
+// InvalidSharesError is returned by Combine when some of the decryption shares
+// are invalid. It wraps ErrInvalidShare.
+type InvalidSharesError struct {
+	// Positions of the invalid decryption shares in the list given to
+	// Combine, in increasing order.
+	Positions []int
+}
+
+func (e *InvalidSharesError) Error() string {
+	positions := make([]string, len(e.Positions))
+	for i, p := range e.Positions {
+		positions[i] = strconv.Itoa(p)
+	}
+	return fmt.Sprintf("%v at positions %s", ErrInvalidShare, strings.Join(positions, ", "))
+}
+
+func (e *InvalidSharesError) Unwrap() error {
+	return ErrInvalidShare
+}
+
+// DecryptionShare is the share of the DH key of a ciphertext computed by one
+// node.
+type DecryptionShare struct {
+	// Share is x_i*K, of the index i of the share of the node
+	Share *share.PubShare
+	// Proof of the correctness of the decryption share
+	Proof []byte
+}
+
+// Encrypt encrypts the plaintext to the distributed public key with ECIES and
+// the given options, the default ones being the ones of ecies.Encrypt with
+// SHA256. The ciphertext is the header K followed by the AEAD payload, and
+// decrypts with Decrypt and the same options.
+func Encrypt(suite Suite, public kyber.Point, plaintext []byte, opts ...ecies.Option) ([]byte, error) {
+	if public == nil {
+		return nil, errors.New("threshold: nil public key")
+	}
+	return ecies.EncryptWithOptions(suite, public, plaintext, opts...)
+}
+
+// Header returns the header K of the ciphertext, which the nodes decrypt with
+// PartialDecrypt.
+func Header(suite Suite, ciphertext []byte) (kyber.Point, error) {
+	l := suite.PointLen()
+	if len(ciphertext) < l {
+		return nil, errors.New("threshold: ciphertext too short")
+	}
+	K := suite.Point()
+	if err := K.UnmarshalBinary(ciphertext[:l]); err != nil {
+		return nil, fmt.Errorf("threshold: invalid header: %w", err)
+	}
+	return K, nil
+}
+
+// PartialDecrypt returns the decryption share of the header of a ciphertext
+// by the node holding the private share priShare, e.g. the one of
+// DistKeyShare.PriShare of a DKG, with the proof of its correctness (see
+// VerifyShare).
+func PartialDecrypt(suite Suite, priShare *share.PriShare, header kyber.Point) (*DecryptionShare, error) {
+	if priShare == nil || priShare.V == nil || header == nil {
+		return nil, ErrInvalidShare
+	}
+	proof, _, D, err := dleq.NewDLEQProof(suite, suite.Point().Base(), header, priShare.V)
+	if err != nil {
+		return nil, err
+	}
+	buff, err := marshalProof(proof)
+	if err != nil {
+		return nil, err
+	}
+	return &DecryptionShare{Share: &share.PubShare{I: priShare.I, V: D}, Proof: buff}, nil
+}
+
+// Combine checks all the decryption shares of the header against the public
+// polynomial of the distributed key, and interpolates t of them into the DH
+// key of the ciphertext. It returns an InvalidSharesError if any decryption
+// share is invalid, even if t valid ones are given, and ErrTooFewShares if
+// less than t decryption shares of distinct nodes are given.
+func Combine(suite Suite, pubPoly *share.PubPoly, header kyber.Point, shares []*DecryptionShare, t, n int) (kyber.Point, error) {
+	if header == nil {
+		return nil, errors.New("threshold: nil header")
+	}
+	if err := checkBase(suite, pubPoly); err != nil {
+		return nil, err
+	}
+	var bad []int
+	pubShares := make([]*share.PubShare, len(shares))
+	for i, s := range shares {
+		if verifyShare(suite, pubPoly, header, s) != nil {
+			bad = append(bad, i)
+			continue
+		}
+		pubShares[i] = s.Share
+	}
+	if bad != nil {
+		return nil, &InvalidSharesError{Positions: bad}
+	}
+	dh, err := share.RecoverCommit(suite, pubShares, t, n)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTooFewShares, err)
+	}
+	return dh, nil
+}
+
+// Decrypt combines the decryption shares of the header of the ciphertext, see
+// Combine, and returns the plaintext. The options must be the ones of the
+// encryption.
+func Decrypt(suite Suite, pubPoly *share.PubPoly, ciphertext []byte, shares []*DecryptionShare, t, n int, opts ...ecies.Option) ([]byte, error) {
+	K, err := Header(suite, ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	dh, err := Combine(suite, pubPoly, K, shares, t, n)
+	if err != nil {
+		return nil, err
+	}
+	return ecies.DecryptWithDH(suite, dh, ciphertext, opts...)
+}
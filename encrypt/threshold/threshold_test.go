@@ -0,0 +1,151 @@
+package threshold
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/encrypt/ecies"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/share"
+)
+
+var suite = edwards25519.NewBlakeSHA256Ed25519()
+
+func TestThresholdEncryption(t *testing.T) {
+	n, th := 7, 3
+	priPoly := share.NewPriPoly(suite, th, nil, suite.RandomStream())
+	pubPoly := priPoly.Commit(nil)
+	priShares := priPoly.Shares(n)
+
+	msg := []byte("Hello threshold hybrid encryption")
+	ad := ecies.WithAssociatedData([]byte("context"))
+	ciphertext, err := Encrypt(suite, pubPoly.Commit(), msg, ad)
+	require.NoError(t, err)
+	K, err := Header(suite, ciphertext)
+	require.NoError(t, err)
+
+	shares := make([]*DecryptionShare, n)
+	for i, s := range priShares {
+		shares[i], err = PartialDecrypt(suite, s, K)
+		require.NoError(t, err)
+	}
+	decrypted, err := Decrypt(suite, pubPoly, ciphertext, shares[2:2+th], th, n, ad)
+	require.NoError(t, err)
+	require.Equal(t, msg, decrypted)
+
+	// the DH key is the one of a decryption with the private key
+	dh, err := Combine(suite, pubPoly, K, shares, th, n)
+	require.NoError(t, err)
+	require.True(t, dh.Equal(suite.Point().Mul(priPoly.Secret(), K)))
+	decrypted, err = ecies.DecryptWithOptions(suite, priPoly.Secret(), ciphertext, ad)
+	require.NoError(t, err)
+	require.Equal(t, msg, decrypted)
+
+	// the associated data is authenticated
+	_, err = Decrypt(suite, pubPoly, ciphertext, shares, th, n)
+	require.Error(t, err)
+
+	// too few decryption shares, or duplicated ones
+	_, err = Decrypt(suite, pubPoly, ciphertext, shares[:th-1], th, n, ad)
+	require.True(t, errors.Is(err, ErrTooFewShares))
+	_, err = Decrypt(suite, pubPoly, ciphertext, []*DecryptionShare{shares[0], shares[1], shares[1]}, th, n, ad)
+	require.True(t, errors.Is(err, ErrTooFewShares))
+
+	// too short ciphertext
+	_, err = Decrypt(suite, pubPoly, ciphertext[:suite.PointLen()-1], shares, th, n, ad)
+	require.Error(t, err)
+}
+
+func TestThresholdInvalidShares(t *testing.T) {
+	n, th := 5, 3
+	priPoly := share.NewPriPoly(suite, th, nil, suite.RandomStream())
+	pubPoly := priPoly.Commit(nil)
+	priShares := priPoly.Shares(n)
+
+	ciphertext, err := Encrypt(suite, pubPoly.Commit(), []byte("Hello"))
+	require.NoError(t, err)
+	K, err := Header(suite, ciphertext)
+	require.NoError(t, err)
+	shares := make([]*DecryptionShare, n)
+	for i, s := range priShares {
+		shares[i], err = PartialDecrypt(suite, s, K)
+		require.NoError(t, err)
+	}
+
+	// a wrong decryption share, a share of another node, a missing share and
+	// a share of another header are all pointed at, even though enough valid
+	// shares are given
+	other := suite.Point().Pick(suite.RandomStream())
+	otherShare, err := PartialDecrypt(suite, priShares[4], other)
+	require.NoError(t, err)
+	shares[0].Share.V = suite.Point().Pick(suite.RandomStream())
+	shares[1].Share = &share.PubShare{I: 2, V: shares[2].Share.V}
+	shares[3] = nil
+	shares = append(shares, otherShare)
+	_, err = Decrypt(suite, pubPoly, ciphertext, shares, th, n)
+	require.True(t, errors.Is(err, ErrInvalidShare))
+	var invalid *InvalidSharesError
+	require.True(t, errors.As(err, &invalid))
+	require.Equal(t, []int{0, 1, 3, 5}, invalid.Positions)
+	require.Equal(t, "threshold: invalid decryption share at positions 0, 1, 3, 5", err.Error())
+}
+
+func TestTDH2(t *testing.T) {
+	n, th := 7, 3
+	priPoly := share.NewPriPoly(suite, th, nil, suite.RandomStream())
+	pubPoly := priPoly.Commit(nil)
+	priShares := priPoly.Shares(n)
+
+	msg := []byte("Hello TDH2")
+	label := []byte("label")
+	c, err := EncryptTDH2(suite, pubPoly.Commit(), msg, label)
+	require.NoError(t, err)
+	require.NoError(t, c.Verify(suite))
+
+	// the ciphertext survives its encoding
+	buf, err := c.MarshalBinary()
+	require.NoError(t, err)
+	c, err = UnmarshalTDH2Ciphertext(suite, buf)
+	require.NoError(t, err)
+	require.Equal(t, label, c.Label)
+
+	shares := make([]*DecryptionShare, n)
+	for i, s := range priShares {
+		shares[i], err = PartialDecryptTDH2(suite, s, c)
+		require.NoError(t, err)
+	}
+	decrypted, err := DecryptTDH2(suite, pubPoly, c, shares[n-th:], th, n)
+	require.NoError(t, err)
+	require.Equal(t, msg, decrypted)
+
+	_, err = DecryptTDH2(suite, pubPoly, c, shares[:th-1], th, n)
+	require.True(t, errors.Is(err, ErrTooFewShares))
+	bad := append([]*DecryptionShare{shares[1]}, shares[3:3+th]...)
+	bad[0] = &DecryptionShare{Share: shares[0].Share, Proof: shares[1].Proof}
+	_, err = DecryptTDH2(suite, pubPoly, c, bad, th, n)
+	require.True(t, errors.Is(err, ErrInvalidShare))
+
+	// the nodes refuse to decrypt a modified ciphertext or label
+	for _, modify := range []func(c *TDH2Ciphertext){
+		func(c *TDH2Ciphertext) { c.Payload[0] ^= 1 },
+		func(c *TDH2Ciphertext) { c.Label = []byte("other") },
+		func(c *TDH2Ciphertext) { c.U = suite.Point().Add(c.U, suite.Point().Base()) },
+		func(c *TDH2Ciphertext) { c.Ubar = suite.Point().Add(c.Ubar, suite.Point().Base()) },
+		func(c *TDH2Ciphertext) { c.F = suite.Scalar().Add(c.F, suite.Scalar().One()) },
+	} {
+		mod, err := UnmarshalTDH2Ciphertext(suite, buf)
+		require.NoError(t, err)
+		modify(mod)
+		_, err = PartialDecryptTDH2(suite, priShares[0], mod)
+		require.True(t, errors.Is(err, ErrInvalidCiphertext))
+		_, err = DecryptTDH2(suite, pubPoly, mod, shares, th, n)
+		require.True(t, errors.Is(err, ErrInvalidCiphertext))
+	}
+
+	// truncated encodings
+	_, err = UnmarshalTDH2Ciphertext(suite, buf[:2*suite.PointLen()])
+	require.Error(t, err)
+	_, err = UnmarshalTDH2Ciphertext(suite, buf[:2*suite.PointLen()+2*suite.ScalarLen()+4+len(label)-1])
+	require.Error(t, err)
+}
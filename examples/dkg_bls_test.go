@@ -43,10 +43,9 @@ func Test_Example_DKG_BLS(t *testing.T) {
 		privKey := suite.Scalar().Pick(suite.RandomStream())
 		pubKey := suite.Point().Mul(privKey, nil)
 
-		// TODO validate privKey is non-zero
-		if privKey.Equal(suite.Scalar().Zero()) {
-			panic("Cannot go with zero share")
-		}
+		// Scalar.Equal runs in constant time, so checking the private key
+		// against zero does not leak it
+		require.False(t, privKey.Equal(suite.Scalar().Zero()), "Cannot go with zero share")
 
 		pubKeys[i] = pubKey
 		nodes[i] = &node{
@@ -88,13 +87,30 @@ func Test_Example_DKG_BLS(t *testing.T) {
 		}
 	}
 
-	// 5. Process the responses on each node
-	for _, node := range nodes {
+	// 5. Process the responses on each node. A node only returns a
+	// justification for a complaint about its own deal, which must be sent to
+	// the other nodes once they processed the complaint. There are none in
+	// this happy path.
+	type justification struct {
+		from int
+		j    *dkg.Justification
+	}
+	var justifications []justification
+	for i, node := range nodes {
 		for _, resp := range node.resps {
-			_, err := node.dkg.ProcessResponse(resp)
+			j, err := node.dkg.ProcessResponse(resp)
 			require.NoError(t, err)
-			// err = node.dkg.ProcessJustification(justification)
-			// require.NoError(t, err)
+			if j != nil {
+				justifications = append(justifications, justification{i, j})
+			}
+		}
+	}
+	for _, j := range justifications {
+		for i, node := range nodes {
+			if i == j.from {
+				continue
+			}
+			require.NoError(t, node.dkg.ProcessJustification(j.j))
 		}
 	}
 
@@ -127,6 +143,16 @@ func Test_Example_DKG_BLS(t *testing.T) {
 
 	pubPoly := share.NewPubPoly(suite, suite.Point().Base(), commitments)
 
+	// 7.1. Any node can compute the public share of every other node from the
+	// commitments, e.g. to check its signature shares
+	pubShares := dkg.PublicShares(suite, commitments, n)
+	for i, node := range nodes {
+		distrKey, err := node.dkg.DistKeyShare()
+		require.NoError(t, err)
+		require.True(t, pubShares[i].V.Equal(suite.Point().Mul(node.secretShare.V, nil)))
+		require.True(t, distrKey.PublicShare(i).V.Equal(pubShares[i].V))
+	}
+
 	// 8. Sign with new subgroup (> threshold) should be sucesfully
 	message := []byte("Hello world")
 	sigShares := make([][]byte, 0)
@@ -136,6 +162,7 @@ func Test_Example_DKG_BLS(t *testing.T) {
 		}
 		S, err := tbls.Sign(suite, node.secretShare, message)
 		require.NoError(t, err)
+		require.NoError(t, tbls.VerifyShareAgainst(suite, pubShares[i], message, S))
 		sigShares = append(sigShares, S)
 	}
 
@@ -208,13 +235,26 @@ func Test_Example_DKG_BLS(t *testing.T) {
 		}
 	}
 
-	// 10.5. Process the responses on each node
-	for _, node := range newNodes {
+	// 10.5. Process the responses on each node. A node only returns a
+	// justification for a complaint about its own deal, which must be sent to
+	// the other nodes once they processed the complaint. There are none in
+	// this happy path.
+	justifications = nil
+	for i, node := range newNodes {
 		for _, resp := range node.resps {
-			_, err := node.dkg.ProcessResponse(resp)
+			j, err := node.dkg.ProcessResponse(resp)
 			require.NoError(t, err)
-			// err = node.dkg.ProcessJustification(justification)
-			// require.NoError(t, err)
+			if j != nil {
+				justifications = append(justifications, justification{i, j})
+			}
+		}
+	}
+	for _, j := range justifications {
+		for i, node := range newNodes {
+			if i == j.from {
+				continue
+			}
+			require.NoError(t, node.dkg.ProcessJustification(j.j))
 		}
 	}
 
@@ -272,3 +312,90 @@ func Test_Example_DKG_BLS(t *testing.T) {
 	sig, err = tbls.Recover(suite, pubPoly, message, sigShares, threshold, n)
 	require.Error(t, err)
 }
+
+/*
+This example runs the same DKG as above, except that each node's share is
+evaluated at an index derived by hashing its public key instead of at its
+position in the list of nodes. The nodes then produce a threshold BLS signature
+that is recovered from the hashed indices.
+*/
+func Test_Example_DKG_BLS_HashedIndices(t *testing.T) {
+	var suite = pairing.NewSuiteBn256()
+
+	n := 7
+	threshold := 3
+
+	privKeys := make([]kyber.Scalar, n)
+	pubKeys := make([]kyber.Point, n)
+	for i := 0; i < n; i++ {
+		privKeys[i] = suite.Scalar().Pick(suite.RandomStream())
+		pubKeys[i] = suite.Point().Mul(privKeys[i], nil)
+	}
+
+	// 1. Create the DKGs with hashed indices on each node
+	dkgs := make([]*dkg.DistKeyGenerator, n)
+	for i := range dkgs {
+		d, err := dkg.NewDistKeyHandler(&dkg.Config{
+			Suite:            suite,
+			Longterm:         privKeys[i],
+			NewNodes:         pubKeys,
+			Threshold:        threshold,
+			UseHashedIndices: true,
+		})
+		require.NoError(t, err)
+		dkgs[i] = d
+	}
+
+	// 2. Exchange the deals and the responses
+	resps := make([]*dkg.Response, 0)
+	for _, d := range dkgs {
+		deals, err := d.Deals()
+		require.NoError(t, err)
+		for i, deal := range deals {
+			resp, err := dkgs[i].ProcessDeal(deal)
+			require.NoError(t, err)
+			resps = append(resps, resp)
+		}
+	}
+	for _, resp := range resps {
+		for i, d := range dkgs {
+			if resp.Response.Index == uint32(i) {
+				continue
+			}
+			_, err := d.ProcessResponse(resp)
+			require.NoError(t, err)
+		}
+	}
+
+	// 3. Get the distributed key shares, each share carrying its hashed index
+	shares := make([]*dkg.DistKeyShare, n)
+	for i, d := range dkgs {
+		require.True(t, d.Certified())
+		dks, err := d.DistKeyShare()
+		require.NoError(t, err)
+		expected, err := share.HashIndex(suite, suite, pubKeys[i])
+		require.NoError(t, err)
+		require.True(t, expected.Equal(dks.X))
+		shares[i] = dks
+	}
+	pubPoly := share.NewPubPoly(suite, suite.Point().Base(), shares[0].Commitments())
+
+	// 4. A threshold of nodes signs the message and the signature is recovered
+	// from their hashed indices
+	message := []byte("Hello world")
+	xs := make([]kyber.Scalar, 0)
+	sigShares := make([][]byte, 0)
+	for _, dks := range shares[n-threshold:] {
+		sig, err := bls.Sign(suite, dks.XShare().V, message)
+		require.NoError(t, err)
+		xs = append(xs, dks.X)
+		sigShares = append(sigShares, sig)
+	}
+	sig, err := tbls.RecoverX(suite, pubPoly, message, xs, sigShares, threshold)
+	require.NoError(t, err)
+	require.NoError(t, bls.Verify(suite, shares[0].Public(), message, sig))
+
+	// 5. Less than a threshold of signatures is not enough
+	_, err = tbls.RecoverX(suite, pubPoly, message, xs[1:], sigShares[1:], threshold)
+	require.Error(t, err)
+}
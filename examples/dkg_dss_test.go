@@ -0,0 +1,111 @@
+package examples
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+	dkg "go.dedis.ch/kyber/v3/share/dkg/pedersen"
+	"go.dedis.ch/kyber/v3/sign/dss"
+	"go.dedis.ch/kyber/v3/sign/eddsa"
+)
+
+/*
+This example illustrates how to use the dkg/pedersen API together with the dss
+package to issue a distributed Schnorr signature that can be verified with the
+regular EdDSA verification function. Two DKGs are run among the nodes: the
+first one generates the longterm distributed key, the second one generates the
+distributed random secret used only once as the nonce of the signature. A
+threshold of nodes then issues partial signatures that are exchanged in their
+binary form and combined into the final signature.
+*/
+func Test_Example_DKG_DSS(t *testing.T) {
+	var suite = edwards25519.NewBlakeSHA256Ed25519()
+
+	n := 7
+	threshold := 4
+
+	privKeys := make([]kyber.Scalar, n)
+	pubKeys := make([]kyber.Point, n)
+	for i := 0; i < n; i++ {
+		privKeys[i] = suite.Scalar().Pick(suite.RandomStream())
+		pubKeys[i] = suite.Point().Mul(privKeys[i], nil)
+	}
+
+	// runDKG runs a full DKG among the nodes and returns the distributed key
+	// share of every node
+	runDKG := func() []*dkg.DistKeyShare {
+		dkgs := make([]*dkg.DistKeyGenerator, n)
+		for i := range dkgs {
+			d, err := dkg.NewDistKeyGenerator(suite, privKeys[i], pubKeys, threshold)
+			require.NoError(t, err)
+			dkgs[i] = d
+		}
+		resps := make([]*dkg.Response, 0)
+		for _, d := range dkgs {
+			deals, err := d.Deals()
+			require.NoError(t, err)
+			for i, deal := range deals {
+				resp, err := dkgs[i].ProcessDeal(deal)
+				require.NoError(t, err)
+				resps = append(resps, resp)
+			}
+		}
+		for _, resp := range resps {
+			for i, d := range dkgs {
+				if resp.Response.Index == uint32(i) {
+					continue
+				}
+				_, err := d.ProcessResponse(resp)
+				require.NoError(t, err)
+			}
+		}
+		shares := make([]*dkg.DistKeyShare, n)
+		for i, d := range dkgs {
+			require.True(t, d.Certified())
+			dks, err := d.DistKeyShare()
+			require.NoError(t, err)
+			shares[i] = dks
+		}
+		return shares
+	}
+
+	// 1. Generate the longterm distributed key
+	longterms := runDKG()
+	// 2. Generate the distributed random secret used as nonce
+	randoms := runDKG()
+
+	// 3. Each node creates its DSS and a threshold of them issue partial
+	// signatures, sent to the other nodes in their binary form
+	message := []byte("Hello world")
+	dsss := make([]*dss.DSS, n)
+	for i := range dsss {
+		d, err := dss.NewDSS(suite, privKeys[i], pubKeys, longterms[i], randoms[i], message, threshold)
+		require.NoError(t, err)
+		dsss[i] = d
+	}
+	partials := make([][]byte, 0)
+	for _, d := range dsss[:threshold] {
+		ps, err := d.PartialSig()
+		require.NoError(t, err)
+		buff, err := ps.MarshalBinary()
+		require.NoError(t, err)
+		partials = append(partials, buff)
+	}
+
+	// 4. The last node, which did not sign, combines the partial signatures
+	combiner := dsss[n-1]
+	for _, buff := range partials {
+		ps, err := dss.UnmarshalPartialSig(suite, buff)
+		require.NoError(t, err)
+		require.NoError(t, combiner.ProcessPartialSig(ps))
+	}
+	require.True(t, combiner.EnoughPartialSig())
+	sig, err := combiner.Signature()
+	require.NoError(t, err)
+
+	// 5. The signature verifies with EdDSA under the longterm distributed key
+	require.NoError(t, eddsa.Verify(longterms[0].Public(), message, sig))
+	require.Error(t, eddsa.Verify(longterms[0].Public(), []byte("other message"), sig))
+}
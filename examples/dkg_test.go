@@ -98,13 +98,30 @@ func Test_Example_DKG(t *testing.T) {
 		}
 	}
 
-	// 5. Process the responses on each node
-	for _, node := range nodes {
+	// 5. Process the responses on each node. A node only returns a
+	// justification for a complaint about its own deal, which must be sent to
+	// the other nodes once they processed the complaint. There are none in
+	// this happy path.
+	type justification struct {
+		from int
+		j    *dkg.Justification
+	}
+	var justifications []justification
+	for i, node := range nodes {
 		for _, resp := range node.resps {
-			_, err := node.dkg.ProcessResponse(resp)
+			j, err := node.dkg.ProcessResponse(resp)
 			require.NoError(t, err)
-			// err = node.dkg.ProcessJustification(justification)
-			// require.NoError(t, err)
+			if j != nil {
+				justifications = append(justifications, justification{i, j})
+			}
+		}
+	}
+	for _, j := range justifications {
+		for i, node := range nodes {
+			if i == j.from {
+				continue
+			}
+			require.NoError(t, node.dkg.ProcessJustification(j.j))
 		}
 	}
 
@@ -259,3 +276,125 @@ func Test_Example_DKG(t *testing.T) {
 		node.dkg = newDkg
 	}
 }
+
+/*
+This example illustrates how to reshare a distributed key to a group of a
+different size with dkg.Reshare: the key is generated among 5 nodes with a
+threshold of 3, then 2 of them leave and 4 new nodes join, so that the 7 nodes
+of the new group hold shares of the same key with a threshold of 4.
+*/
+func Test_Example_DKG_Reshare(t *testing.T) {
+	oldN, newT := 5, 4
+
+	newKeys := func(n int) ([]kyber.Scalar, []kyber.Point) {
+		privs := make([]kyber.Scalar, n)
+		pubs := make([]kyber.Point, n)
+		for i := range privs {
+			privs[i] = suite.Scalar().Pick(suite.RandomStream())
+			pubs[i] = suite.Point().Mul(privs[i], nil)
+		}
+		return privs, pubs
+	}
+
+	// 1. Generate the distributed key among the old nodes, as in
+	// Test_Example_DKG
+	oldPrivs, oldPubs := newKeys(oldN)
+	dkgs := make([]*dkg.DistKeyGenerator, oldN)
+	for i := range dkgs {
+		d, err := dkg.NewDistKeyGenerator(suite, oldPrivs[i], oldPubs, 3)
+		require.NoError(t, err)
+		dkgs[i] = d
+	}
+	var resps []*dkg.Response
+	for _, d := range dkgs {
+		deals, err := d.Deals()
+		require.NoError(t, err)
+		for i, deal := range deals {
+			resp, err := dkgs[i].ProcessDeal(deal)
+			require.NoError(t, err)
+			resps = append(resps, resp)
+		}
+	}
+	for _, resp := range resps {
+		for i, d := range dkgs {
+			if resp.Response.Index != uint32(i) {
+				_, err := d.ProcessResponse(resp)
+				require.NoError(t, err)
+			}
+		}
+	}
+	oldShares := make([]*dkg.DistKeyShare, oldN)
+	for i, d := range dkgs {
+		dks, err := d.DistKeyShare()
+		require.NoError(t, err)
+		oldShares[i] = dks
+	}
+	publicKey := oldShares[0].Public()
+
+	// 2. The old nodes 0 and 1 leave and four nodes join. The nodes which
+	// join only know the public commitments of the distributed key.
+	joinPrivs, joinPubs := newKeys(4)
+	newPrivs := append(append([]kyber.Scalar{}, oldPrivs[2:]...), joinPrivs...)
+	newPubs := append(append([]kyber.Point{}, oldPubs[2:]...), joinPubs...)
+	newN := len(newPubs)
+	commits := oldShares[0].Commits
+
+	olds := make([]*dkg.Resharing, oldN)
+	for i := range olds {
+		r, err := dkg.Reshare(suite, oldPrivs[i], oldShares[i], oldPubs, newPubs, newT)
+		require.NoError(t, err)
+		olds[i] = r
+	}
+	news := make([]*dkg.Resharing, newN)
+	copy(news, olds[2:])
+	for i := oldN - 2; i < newN; i++ {
+		r, err := dkg.Reshare(suite, newPrivs[i], &dkg.DistKeyShare{Commits: commits}, oldPubs, newPubs, newT)
+		require.NoError(t, err)
+		news[i] = r
+	}
+	t.Log("leaving old nodes:", news[0].Leaving())
+	t.Log("joining new nodes:", news[0].Joining())
+
+	// 3. Every old node deals to the new nodes, which need the deals of at
+	// least the old threshold of them
+	resps = nil
+	for _, old := range olds {
+		deals, err := old.Deals()
+		require.NoError(t, err)
+		for i, deal := range deals {
+			resp, err := news[i].ProcessDeal(deal)
+			require.NoError(t, err)
+			resps = append(resps, resp)
+		}
+	}
+	for _, node := range news {
+		require.True(t, node.EnoughDeals())
+		require.Empty(t, node.MissingDeals())
+	}
+
+	// 4. The responses go to the leaving old nodes and to the new nodes
+	for _, resp := range resps {
+		for _, old := range olds[:2] {
+			_, err := old.ProcessResponse(resp)
+			require.NoError(t, err)
+		}
+		for i, node := range news {
+			if resp.Response.Index != uint32(i) {
+				_, err := node.ProcessResponse(resp)
+				require.NoError(t, err)
+			}
+		}
+	}
+
+	// 5. The new nodes hold shares of the same distributed key
+	shares := make([]*share.PriShare, newN)
+	for i, node := range news {
+		dks, err := node.DistKeyShare()
+		require.NoError(t, err)
+		require.True(t, publicKey.Equal(dks.Public()))
+		shares[i] = dks.PriShare()
+	}
+	secret, err := share.RecoverSecret(suite, shares, newT, newN)
+	require.NoError(t, err)
+	require.True(t, publicKey.Equal(suite.Point().Mul(secret, nil)))
+}
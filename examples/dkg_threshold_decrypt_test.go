@@ -0,0 +1,94 @@
+package examples
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/encrypt/threshold"
+	"go.dedis.ch/kyber/v3/share"
+	dkg "go.dedis.ch/kyber/v3/share/dkg/pedersen"
+)
+
+/*
+This example illustrates how to use the dkg/pedersen API together with the
+threshold package to decrypt an ElGamal ciphertext encrypted to the distributed
+public key. Unlike the variants of Test_Example_DKG, only a threshold of nodes
+takes part in the decryption and every decryption share comes with a proof of
+correctness, so that a bad share is detected before being combined.
+*/
+func Test_Example_DKG_ThresholdDecryption(t *testing.T) {
+	n := 7
+	th := 3
+
+	privKeys := make([]kyber.Scalar, n)
+	pubKeys := make([]kyber.Point, n)
+	for i := 0; i < n; i++ {
+		privKeys[i] = suite.Scalar().Pick(suite.RandomStream())
+		pubKeys[i] = suite.Point().Mul(privKeys[i], nil)
+	}
+
+	// 1. Run the DKG
+	dkgs := make([]*dkg.DistKeyGenerator, n)
+	for i := range dkgs {
+		d, err := dkg.NewDistKeyGenerator(suite, privKeys[i], pubKeys, th)
+		require.NoError(t, err)
+		dkgs[i] = d
+	}
+	resps := make([]*dkg.Response, 0)
+	for _, d := range dkgs {
+		deals, err := d.Deals()
+		require.NoError(t, err)
+		for i, deal := range deals {
+			resp, err := dkgs[i].ProcessDeal(deal)
+			require.NoError(t, err)
+			resps = append(resps, resp)
+		}
+	}
+	for _, resp := range resps {
+		for i, d := range dkgs {
+			if resp.Response.Index == uint32(i) {
+				continue
+			}
+			_, err := d.ProcessResponse(resp)
+			require.NoError(t, err)
+		}
+	}
+	shares := make([]*dkg.DistKeyShare, n)
+	for i, d := range dkgs {
+		require.True(t, d.Certified())
+		dks, err := d.DistKeyShare()
+		require.NoError(t, err)
+		shares[i] = dks
+	}
+	publicKey := shares[0].Public()
+	pubPoly := share.NewPubPoly(suite, nil, shares[0].Commitments())
+
+	// 2. Encrypt a message to the distributed public key
+	message := []byte("Hello world")
+	K, C, remainder := ElGamalEncrypt(suite, publicKey, message)
+	require.Equal(t, 0, len(remainder))
+
+	// 3. A threshold of nodes publish their decryption shares, and one of them
+	// is corrupted
+	decShares := make([]*threshold.DecryptionShare, th+1)
+	for i := range decShares {
+		var err error
+		decShares[i], err = threshold.PartialDecrypt(suite, shares[i].PriShare(), K)
+		require.NoError(t, err)
+	}
+	decShares[0].Share.V = suite.Point().Pick(suite.RandomStream())
+
+	// 4. Anyone identifies the bad decryption share and decrypts the message
+	// with the valid ones
+	_, err := threshold.DecryptElGamal(suite, pubPoly, K, C, decShares, th, n)
+	var invalid *threshold.InvalidSharesError
+	require.True(t, errors.As(err, &invalid))
+	require.Equal(t, []int{0}, invalid.Positions)
+	M, err := threshold.DecryptElGamal(suite, pubPoly, K, C, decShares[1:], th, n)
+	require.NoError(t, err)
+	decrypted, err := M.Data()
+	require.NoError(t, err)
+	require.Equal(t, message, decrypted)
+}
@@ -0,0 +1,101 @@
+package examples
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/encrypt/threshold"
+	"go.dedis.ch/kyber/v3/share"
+	dkg "go.dedis.ch/kyber/v3/share/dkg/pedersen"
+)
+
+/*
+This example illustrates how to use the dkg/pedersen API together with the
+threshold package to encrypt a payload of any size to the distributed public
+key. Unlike Test_Example_DKG_ThresholdDecryption, the message is not embedded
+in a point: the distributed key only wraps the key of an AEAD, which encrypts
+the payload. A threshold of nodes decrypt the header of the ciphertext, and a
+bad decryption share is pointed at instead of giving a wrong AEAD key.
+*/
+func Test_Example_DKG_ThresholdEncryption(t *testing.T) {
+	n := 7
+	th := 3
+
+	privKeys := make([]kyber.Scalar, n)
+	pubKeys := make([]kyber.Point, n)
+	for i := 0; i < n; i++ {
+		privKeys[i] = suite.Scalar().Pick(suite.RandomStream())
+		pubKeys[i] = suite.Point().Mul(privKeys[i], nil)
+	}
+
+	// 1. Run the DKG
+	dkgs := make([]*dkg.DistKeyGenerator, n)
+	for i := range dkgs {
+		d, err := dkg.NewDistKeyGenerator(suite, privKeys[i], pubKeys, th)
+		require.NoError(t, err)
+		dkgs[i] = d
+	}
+	resps := make([]*dkg.Response, 0)
+	for _, d := range dkgs {
+		deals, err := d.Deals()
+		require.NoError(t, err)
+		for i, deal := range deals {
+			resp, err := dkgs[i].ProcessDeal(deal)
+			require.NoError(t, err)
+			resps = append(resps, resp)
+		}
+	}
+	for _, resp := range resps {
+		for i, d := range dkgs {
+			if resp.Response.Index == uint32(i) {
+				continue
+			}
+			_, err := d.ProcessResponse(resp)
+			require.NoError(t, err)
+		}
+	}
+	shares := make([]*dkg.DistKeyShare, n)
+	for i, d := range dkgs {
+		require.True(t, d.Certified())
+		dks, err := d.DistKeyShare()
+		require.NoError(t, err)
+		shares[i] = dks
+	}
+	publicKey := shares[0].Public()
+	pubPoly := share.NewPubPoly(suite, nil, shares[0].Commitments())
+
+	// 2. Encrypt a payload to the distributed public key
+	message := make([]byte, 1000)
+	suite.RandomStream().XORKeyStream(message, message)
+	ciphertext, err := threshold.Encrypt(suite, publicKey, message)
+	require.NoError(t, err)
+
+	// 3. A threshold of nodes publish their decryption shares of the header,
+	// and one of them is corrupted
+	K, err := threshold.Header(suite, ciphertext)
+	require.NoError(t, err)
+	decShares := make([]*threshold.DecryptionShare, th)
+	for i := range decShares {
+		decShares[i], err = threshold.PartialDecrypt(suite, shares[i].PriShare(), K)
+		require.NoError(t, err)
+	}
+	good := decShares[0]
+	decShares[0] = &threshold.DecryptionShare{
+		Share: &share.PubShare{I: good.Share.I, V: suite.Point().Pick(suite.RandomStream())},
+		Proof: good.Proof,
+	}
+
+	// 4. The bad decryption share is identified, and the payload is decrypted
+	// once it is replaced by a valid one
+	_, err = threshold.Decrypt(suite, pubPoly, ciphertext, decShares, th, n)
+	var invalid *threshold.InvalidSharesError
+	require.True(t, errors.As(err, &invalid))
+	require.Equal(t, []int{0}, invalid.Positions)
+	decShares[0], err = threshold.PartialDecrypt(suite, shares[th].PriShare(), K)
+	require.NoError(t, err)
+	decrypted, err := threshold.Decrypt(suite, pubPoly, ciphertext, decShares, th, n)
+	require.NoError(t, err)
+	require.Equal(t, message, decrypted)
+}
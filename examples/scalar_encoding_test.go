@@ -0,0 +1,50 @@
+package examples
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/group/mod"
+	"go.dedis.ch/kyber/v3/pairing/bn256"
+	"go.dedis.ch/kyber/v3/util/encoding"
+)
+
+/*
+This example illustrates how to move a scalar between suites which disagree
+on the byte order of their scalars: MarshalBinary is little endian for
+edwards25519, as in RFC 8032, and big endian for bn256. Decoding the bytes of
+one suite with the UnmarshalBinary of the other would silently give another
+scalar, so the scalar is imported with an explicit byte order instead.
+*/
+func Example_scalarByteOrder() {
+	// A private key exported by another library, as a big endian integer.
+	const exported = "0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f20"
+
+	ed := edwards25519.NewBlakeSHA256Ed25519()
+	bn := bn256.NewSuiteG1()
+
+	edKey, err := encoding.ScalarFromHex(ed, exported, mod.BigEndian)
+	if err != nil {
+		panic(err)
+	}
+	bnKey, err := encoding.ScalarFromHex(bn, exported, mod.BigEndian)
+	if err != nil {
+		panic(err)
+	}
+
+	// The same integer, but not the same bytes.
+	edBytes, _ := edKey.MarshalBinary()
+	bnBytes, _ := bnKey.MarshalBinary()
+	fmt.Println("edwards25519: " + hex.EncodeToString(edBytes))
+	fmt.Println("bn256:        " + hex.EncodeToString(bnBytes))
+
+	// Out of range scalars are rejected rather than reduced.
+	_, err = encoding.ScalarFromHex(ed, "ff"+exported[2:], mod.BigEndian)
+	fmt.Println(err)
+
+	// Output:
+	// edwards25519: 201f1e1d1c1b1a191817161514131211100f0e0d0c0b0a090807060504030201
+	// bn256:        0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f20
+	// non-canonical scalar
+}
@@ -13,6 +13,9 @@ type Scalar interface {
 	Marshaling
 
 	// Equality test for two Scalars derived from the same Group.
+	// The scalars of edwards25519 and bn256 are compared in constant time on
+	// their canonical values. The ones based on math/big, such as mod.Int,
+	// are not: secret scalars of these groups must not be compared.
 	Equal(s2 Scalar) bool
 
 	// Set sets the receiver equal to another Scalar a.
@@ -66,7 +69,10 @@ type Scalar interface {
 type Point interface {
 	Marshaling
 
-	// Equality test for two Points derived from the same Group.
+	// Equality test for two Points derived from the same Group, independently
+	// of the internal representation of the points. The points of
+	// edwards25519 and bn256 are compared in constant time, but the ones
+	// based on math/big, such as the points of group/nist, are not.
 	Equal(s2 Point) bool
 
 	// Null sets the receiver to the neutral identity element.
@@ -124,6 +130,84 @@ type AllowsVarTime interface {
 	AllowVarTime(bool)
 }
 
+// VartimeMulAdder allows callers to determine if a given kyber.Point supports
+// a variable time double-base scalar multiplication. If a Point implements
+// VartimeMulAdder, then MulAddVartime(a, A, b) sets the receiver to a*A + b*B
+// where B is the standard base point. Such an operation is typically faster
+// than two independent scalar multiplications, but it risks leaking
+// information via a timing side channel. It is thus only safe to use on public
+// Scalars and Points, e.g. when verifying signatures.
+type VartimeMulAdder interface {
+	MulAddVartime(a Scalar, A Point, b Scalar) Point
+}
+
+// Precomputer allows callers to determine if a given kyber.Point supports
+// precomputing a table of its multiples. If a Point implements Precomputer,
+// then Precompute() builds the table of the current value of the point, and
+// the subsequent calls of Mul(s, P) with the point P as base use it, as
+// Mul(s, nil) does for the standard base point. The table costs a few
+// multiplications to build and tens of kilobytes of memory, so that it only
+// pays off for a base multiplied many times, e.g. a long-term public key or
+// the generators of a commitment scheme. It is kept by Clone and Set, and
+// ignored once the point changes; the multiplications with it run in
+// constant time.
+type Precomputer interface {
+	Precompute()
+}
+
+// ScalarEncoder allows callers to encode and decode a Scalar with an explicit
+// byte order. The byte order of MarshalBinary and SetBytes depends on the
+// implementation, e.g. little endian for edwards25519 as in RFC 8032 and big
+// endian for bn256, so that moving the bytes of a scalar between suites, or
+// importing it from another library, requires the explicit encodings.
+type ScalarEncoder interface {
+	// BytesBE returns the big endian encoding of the scalar reduced modulo
+	// the order, of MarshalSize bytes.
+	BytesBE() []byte
+
+	// SetBytesBE sets the receiver to the big endian encoding b of
+	// MarshalSize bytes. It returns an error, leaving the receiver
+	// unchanged, if b is not the canonical encoding of a scalar, i.e. of
+	// an integer smaller than the order.
+	SetBytesBE(b []byte) (Scalar, error)
+
+	// BytesLE returns the little endian encoding of the scalar reduced
+	// modulo the order, of MarshalSize bytes.
+	BytesLE() []byte
+
+	// SetBytesLE is the little endian counterpart of SetBytesBE.
+	SetBytesLE(b []byte) (Scalar, error)
+
+	// SetBytesWide sets the receiver to the big endian integer b reduced
+	// modulo the order, as the hash-to-scalar of RFC 9380 does with its
+	// OS2IP of the hash. b must be at most 64 bytes long, or twice
+	// MarshalSize for larger orders, so that the reduction of a uniform b
+	// is indistinguishable from a uniform scalar when b is long enough.
+	SetBytesWide(b []byte) (Scalar, error)
+}
+
+// Wiper allows callers to erase a secret Scalar from memory once it is no
+// longer needed, e.g. a nonce after signing. Wipe overwrites the memory
+// holding the value of the Scalar, which is then equal to zero and can still
+// be used. It is a best-effort measure: Go gives no guarantee that the value
+// was not copied elsewhere before, e.g. by the garbage collector or in the
+// intermediate results of the arithmetic.
+type Wiper interface {
+	Wipe()
+}
+
+// PointHasher allows callers to hash a message to a point of a Group with the
+// hash_to_curve function of RFC 9380, e.g. for BLS signatures or VRFs, so
+// that the points are the same as the ones of any other implementation of the
+// same hash-to-curve suite. Each group documents the suite it implements.
+type PointHasher interface {
+	// HashToPoint returns the point of the message msg under the domain
+	// separation tag dst, which must not be empty and should be unique to
+	// the protocol and the suite. The point is in the prime-order subgroup
+	// and its discrete logarithm is unknown.
+	HashToPoint(dst, msg []byte) (Point, error)
+}
+
 // Group interface represents a mathematical group
 // usable for Diffie-Hellman key exchange, ElGamal encryption,
 // and the related body of public-key cryptographic algorithms
@@ -3,8 +3,12 @@ package edwards25519
 import (
 	"crypto/cipher"
 	"crypto/sha512"
+	"crypto/subtle"
+	"errors"
+	"math/big"
 
 	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/util/h2c"
 	"go.dedis.ch/kyber/v3/util/random"
 )
 
@@ -74,3 +78,50 @@ func (c *Curve) NewKey(stream cipher.Stream) kyber.Scalar {
 	secret, _, _ := c.NewKeyAndSeed(stream)
 	return secret
 }
+
+// HashToPoint hashes the message msg to a point of the prime-order subgroup
+// with the domain separation tag dst, following the hash-to-curve suite
+// edwards25519_XMD:SHA-512_ELL2_RO_ of RFC 9380: the message is hashed to two
+// field elements, which are mapped to the curve with Elligator2, and the sum
+// of both points is multiplied by the cofactor. It runs in constant time
+// with respect to the message, and implements kyber.PointHasher.
+func (c *Curve) HashToPoint(dst, msg []byte) (kyber.Point, error) {
+	u, err := h2c.HashToField(sha512.New, dst, msg, prime, 2)
+	if err != nil {
+		return nil, err
+	}
+	Q := Elligator2(fieldElementFromBig(u[0]))
+	Q.Add(Q, Elligator2(fieldElementFromBig(u[1])))
+	return new(point).Mul(cofactorScalar, Q), nil
+}
+
+// DecodeClearCofactor decodes the canonical encoding b of any point of the
+// curve, as in RFC 8032, and returns it multiplied by the cofactor, i.e. a
+// point of the prime-order subgroup. Unlike Point().UnmarshalBinary, it
+// accepts the points with a component of small order, as the
+// try-and-increment hash-to-curve of ECVRF (RFC 9381) requires.
+func (c *Curve) DecodeClearCofactor(b []byte) (kyber.Point, error) {
+	var Q point
+	if !Q.ge.FromBytes(b) {
+		return nil, errors.New("invalid Ed25519 curve point")
+	}
+	var e [32]byte
+	Q.ge.ToBytes(&e)
+	if subtle.ConstantTimeCompare(e[:], b) != 1 {
+		return nil, errors.New("non-canonical Ed25519 curve point")
+	}
+	return new(point).Mul(cofactorScalar, &Q), nil
+}
+
+// fieldElementFromBig returns the field element of the integer x, which must
+// be reduced modulo the prime.
+func fieldElementFromBig(x *big.Int) *FieldElement {
+	var b [32]byte
+	be := x.Bytes()
+	for i := range be {
+		b[i] = be[len(be)-1-i]
+	}
+	fe := new(FieldElement)
+	feFromBytes(&fe.fe, b[:])
+	return fe
+}
@@ -1,10 +1,16 @@
 package edwards25519
 
 import (
+	"encoding/hex"
+	"errors"
 	"math"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/util/h2c"
 	"go.dedis.ch/kyber/v3/util/test"
 )
 
@@ -26,6 +32,72 @@ func TestCurve_NewKey(t *testing.T) {
 	}
 }
 
+// hashToPointVectors are the vectors of the suite
+// edwards25519_XMD:SHA-512_ELL2_RO_ of Appendix J.5.1 of RFC 9380.
+var hashToPointVectors = []struct {
+	msg    string
+	px, py string
+}{
+	{"",
+		"3c3da6925a3c3c268448dcabb47ccde5439559d9599646a8260e47b1e4822fc6",
+		"09a6c8561a0b22bef63124c588ce4c62ea83a3c899763af26d795302e115dc21"},
+	{"abc",
+		"608040b42285cc0d72cbb3985c6b04c935370c7361f4b7fbdb1ae7f8c1a8ecad",
+		"1a8395b88338f22e435bbd301183e7f20a5f9de643f11882fb237f88268a5531"},
+	{"abcdef0123456789",
+		"6d7fabf47a2dc03fe7d47f7dddd21082c5fb8f86743cd020f3fb147d57161472",
+		"53060a3d140e7fbcda641ed3cf42c88a75411e648a1add71217f70ea8ec561a6"},
+	{"q128_" + strings.Repeat("q", 128),
+		"5fb0b92acedd16f3bcb0ef83f5c7b7a9466b5f1e0d8d217421878ea3686f8524",
+		"2eca15e355fcfa39d2982f67ddb0eea138e2994f5956ed37b7f72eea5e89d2f7"},
+	{"a512_" + strings.Repeat("a", 512),
+		"0efcfde5898a839b00997fbe40d2ebe950bc81181afbd5cd6b9618aa336c1e8c",
+		"6dc2fc04f266c5c27f236a80b14f92ccd051ef1ff027f26a07f8c0f327d8f995"},
+}
+
+func TestCurve_HashToPoint(t *testing.T) {
+	var hasher kyber.PointHasher = tSuite
+	dst := []byte("QUUX-V01-CS02-with-edwards25519_XMD:SHA-512_ELL2_RO_")
+	for _, v := range hashToPointVectors {
+		P, err := hasher.HashToPoint(dst, []byte(v.msg))
+		require.NoError(t, err)
+		want := encodePoint(fieldElementFromHex(t, v.px), fieldElementFromHex(t, v.py))
+		got, err := P.MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, hex.EncodeToString(want), hex.EncodeToString(got), "msg %q", v.msg)
+		require.True(t, P.(*point).IsTorsionFree())
+	}
+
+	_, err := hasher.HashToPoint(nil, []byte("msg"))
+	require.True(t, errors.Is(err, h2c.ErrEmptyDST))
+}
+
+func TestCurve_DecodeClearCofactor(t *testing.T) {
+	c := new(Curve)
+	P := c.Point().Pick(tSuite.RandomStream())
+	b, err := P.MarshalBinary()
+	require.NoError(t, err)
+	Q, err := c.DecodeClearCofactor(b)
+	require.NoError(t, err)
+	require.True(t, Q.Equal(c.Point().Mul(cofactorScalar, P)))
+
+	// the point (0, -1) of order 2, which UnmarshalBinary rejects
+	b = make([]byte, 32)
+	b[0], b[31] = 0xec, 0x7f
+	for i := 1; i < 31; i++ {
+		b[i] = 0xff
+	}
+	require.Error(t, c.Point().UnmarshalBinary(b))
+	Q, err = c.DecodeClearCofactor(b)
+	require.NoError(t, err)
+	require.True(t, Q.Equal(nullPoint))
+
+	// y = p is not canonical
+	b[0] = 0xed
+	_, err = c.DecodeClearCofactor(b)
+	require.Error(t, err)
+}
+
 func BenchmarkScalarAdd(b *testing.B)    { groupBench.ScalarAdd(b.N) }
 func BenchmarkScalarSub(b *testing.B)    { groupBench.ScalarSub(b.N) }
 func BenchmarkScalarNeg(b *testing.B)    { groupBench.ScalarNeg(b.N) }
@@ -0,0 +1,234 @@
+package edwards25519
+
+import (
+	"crypto/subtle"
+	"errors"
+
+	"go.dedis.ch/kyber/v3"
+)
+
+// FieldElement is an element of GF(2^255-19), the field over which the curve
+// is defined, for protocols which need the field arithmetic itself, e.g. to
+// hash to the curve with Elligator2. Its encoding is the canonical one of
+// RFC 8032, 32 bytes in little-endian of the integer in [0, 2^255-19). All
+// the operations run in constant time. The zero value is the element zero,
+// and the methods set the receiver to their result and return it, like the
+// ones of kyber.Scalar.
+type FieldElement struct {
+	fe fieldElement
+}
+
+// SetBytes sets v to the element of the canonical encoding b. It returns an
+// error, leaving v unchanged, if b is not 32 bytes long, if its most
+// significant bit is set or if it encodes an integer of at least 2^255-19.
+func (v *FieldElement) SetBytes(b []byte) (*FieldElement, error) {
+	if len(b) != 32 {
+		return nil, errors.New("invalid field element length")
+	}
+	var fe fieldElement
+	feFromBytes(&fe, b)
+	// Re-encoding reduces the integer modulo the prime and clears the most
+	// significant bit, so it differs from non-canonical encodings.
+	var c [32]byte
+	feToBytes(&c, &fe)
+	if subtle.ConstantTimeCompare(c[:], b) != 1 {
+		return nil, errors.New("non-canonical field element")
+	}
+	v.fe = fe
+	return v, nil
+}
+
+// Bytes returns the canonical 32-byte encoding of v.
+func (v *FieldElement) Bytes() []byte {
+	var b [32]byte
+	feToBytes(&b, &v.fe)
+	return b[:]
+}
+
+// Equal returns true if v and u are the same element.
+func (v *FieldElement) Equal(u *FieldElement) bool {
+	var diff fieldElement
+	feSub(&diff, &v.fe, &u.fe)
+	return feIsNonZero(&diff) == 0
+}
+
+// Add sets v to a + b.
+func (v *FieldElement) Add(a, b *FieldElement) *FieldElement {
+	feAdd(&v.fe, &a.fe, &b.fe)
+	return v.carry()
+}
+
+// Sub sets v to a - b.
+func (v *FieldElement) Sub(a, b *FieldElement) *FieldElement {
+	feSub(&v.fe, &a.fe, &b.fe)
+	return v.carry()
+}
+
+// Mul sets v to a * b.
+func (v *FieldElement) Mul(a, b *FieldElement) *FieldElement {
+	feMul(&v.fe, &a.fe, &b.fe)
+	return v
+}
+
+// Square sets v to a * a.
+func (v *FieldElement) Square(a *FieldElement) *FieldElement {
+	feSquare(&v.fe, &a.fe)
+	return v
+}
+
+// Invert sets v to 1/a, or to zero if a is zero.
+func (v *FieldElement) Invert(a *FieldElement) *FieldElement {
+	feInvert(&v.fe, &a.fe)
+	return v
+}
+
+// Sqrt sets v to the square root of a whose encoding is even, and returns
+// true, if a is a square. Otherwise, it sets v to zero and returns false.
+func (v *FieldElement) Sqrt(a *FieldElement) (*FieldElement, bool) {
+	ok := feSqrt(&v.fe, &a.fe)
+	return v, ok == 1
+}
+
+// IsSquare returns true if v is a square, including zero.
+func (v *FieldElement) IsSquare() bool {
+	var r fieldElement
+	return feSqrt(&r, &v.fe) == 1
+}
+
+// carry brings the limbs of v back within the bounds expected by feMul, which
+// feAdd and feSub can exceed when they are chained.
+func (v *FieldElement) carry() *FieldElement {
+	var b [32]byte
+	feToBytes(&b, &v.fe)
+	feFromBytes(&v.fe, b[:])
+	return v
+}
+
+// feSqrt sets out to the square root of z whose encoding is even and returns
+// 1 if z is a square. Otherwise, it sets out to zero and returns 0.
+func feSqrt(out, z *fieldElement) int32 {
+	var r, r2, check, zero fieldElement
+
+	// r = z^((q+3)/8) is a square root of z or of -z if z is a square
+	fePow22523(&r, z)
+	feMul(&r, &r, z)
+	feSquare(&check, &r)
+
+	feSub(&r2, &check, z)
+	isRoot := 1 - feIsNonZero(&r2)
+	feAdd(&r2, &check, z)
+	isNegRoot := 1 - feIsNonZero(&r2)
+	feMul(&r2, &r, &sqrtM1)
+	feCMove(&r, &r2, isNegRoot)
+
+	feNeg(&r2, &r)
+	feCMove(&r, &r2, int32(feIsNegative(&r)))
+	ok := isRoot | isNegRoot
+	feCMove(&r, &zero, 1-ok)
+	feCopy(out, &r)
+	return ok
+}
+
+// ell2C2 is the constant c2 = 2^((q+3)/8) of map_to_curve_elligator2_curve25519.
+var ell2C2 = fieldElement{
+	-32595791, -7943725, 9377950, 3500415, 12389472, -272473, -25146209, -2005654, 326686, 11406482,
+}
+
+// ell2C1 is sqrt(-486664), with an even encoding, which scales the Montgomery
+// coordinates of curve25519 to the Edwards ones of edwards25519.
+var ell2C1 = fieldElement{
+	-12222970, -8312128, -11511410, 9067497, -15300785, -241793, 25456130, 14121551, -12187136, 3972024,
+}
+
+// Elligator2 maps the field element u to a point of the curve with the
+// map_to_curve_elligator2_edwards25519 function of RFC 9380, i.e. the
+// Elligator2 map to curve25519 followed by the rational map to edwards25519,
+// in constant time. Like map_to_curve, it does not clear the cofactor, so
+// the point can be outside of the prime-order subgroup: protocols which need
+// a point of the subgroup, as encode_to_curve and hash_to_curve, must still
+// multiply it by the cofactor 8. The field element u is typically derived
+// from a message with the hash_to_field function of RFC 9380.
+func Elligator2(u *FieldElement) kyber.Point {
+	var xMn, xMd, yM fieldElement
+	elligator2Curve25519(&xMn, &xMd, &yM, &u.fe)
+
+	// rational map of Appendix G.2.2 of RFC 9380, with yMd = 1
+	var xn, xd, yn, yd, tv1, one fieldElement
+	feOne(&one)
+	feMul(&xn, &xMn, &ell2C1)
+	feMul(&xd, &xMd, &yM)
+	feSub(&yn, &xMn, &xMd)
+	feAdd(&yd, &xMn, &xMd)
+	feMul(&tv1, &xd, &yd)
+	e := 1 - feIsNonZero(&tv1)
+	feCMove(&xn, &tv1, e) // tv1 is zero if e is set
+	feCMove(&xd, &one, e)
+	feCMove(&yn, &one, e)
+	feCMove(&yd, &one, e)
+
+	P := new(point)
+	feMul(&P.ge.X, &xn, &yd)
+	feMul(&P.ge.Y, &yn, &xd)
+	feMul(&P.ge.Z, &xd, &yd)
+	feMul(&P.ge.T, &xn, &yn)
+	return P
+}
+
+// elligator2Curve25519 sets xn/xd and y to the affine coordinates of the
+// point of curve25519 of u, following map_to_curve_elligator2_curve25519 of
+// Appendix G.2.1 of RFC 9380 step by step.
+func elligator2Curve25519(xn, xd, y, u *fieldElement) {
+	var tv1, tv2, tv3, x1n, x2n, gxd, gx1, gx2 fieldElement
+	var y11, y12, y1, y21, y22, y2, one fieldElement
+	feOne(&one)
+
+	feSquare2(&tv1, u)    // tv1 = 2 * u^2
+	feAdd(xd, &tv1, &one) // xd = tv1 + 1
+	feNeg(&x1n, &paramA)  // x1n = -J
+	feSquare(&tv2, xd)
+	feMul(&gxd, &tv2, xd) // gxd = xd^3
+	feMul(&gx1, &paramA, &tv1)
+	feMul(&gx1, &gx1, &x1n)
+	feAdd(&gx1, &gx1, &tv2)
+	feMul(&gx1, &gx1, &x1n) // gx1 = x1n^3 + J * x1n^2 * xd + x1n * xd^2
+	feSquare(&tv3, &gxd)
+	feSquare(&tv2, &tv3) // tv2 = gxd^4
+	feMul(&tv3, &tv3, &gxd)
+	feMul(&tv3, &tv3, &gx1) // tv3 = gx1 * gxd^3
+	feMul(&tv2, &tv2, &tv3) // tv2 = gx1 * gxd^7
+	fePow22523(&y11, &tv2)
+	feMul(&y11, &y11, &tv3)
+	feMul(&y12, &y11, &sqrtM1)
+	feSquare(&tv2, &y11)
+	feMul(&tv2, &tv2, &gxd)
+	feSub(&tv2, &tv2, &gx1)
+	e1 := 1 - feIsNonZero(&tv2)
+	feCopy(&y1, &y12)
+	feCMove(&y1, &y11, e1) // if gx1 is square, y1 is its root over gxd
+
+	feMul(&x2n, &x1n, &tv1)
+	feMul(&y21, &y11, u)
+	feMul(&y21, &y21, &ell2C2)
+	feMul(&y22, &y21, &sqrtM1)
+	feMul(&gx2, &gx1, &tv1)
+	feSquare(&tv2, &y21)
+	feMul(&tv2, &tv2, &gxd)
+	feSub(&tv2, &tv2, &gx2)
+	e2 := 1 - feIsNonZero(&tv2)
+	feCopy(&y2, &y22)
+	feCMove(&y2, &y21, e2)
+
+	feSquare(&tv2, &y1)
+	feMul(&tv2, &tv2, &gxd)
+	feSub(&tv2, &tv2, &gx1)
+	e3 := 1 - feIsNonZero(&tv2)
+	feCopy(xn, &x2n)
+	feCMove(xn, &x1n, e3)
+	feCopy(y, &y2)
+	feCMove(y, &y1, e3)
+
+	// y is odd for x1n and even for x2n
+	e4 := int32(feIsNegative(y))
+	feNeg(&tv2, y)
+	feCMove(y, &tv2, e3^e4)
+}
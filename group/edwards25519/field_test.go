@@ -0,0 +1,188 @@
+package edwards25519
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fieldElementFromInt returns the field element of the integer x, which must
+// be in [0, 2^255-19).
+func fieldElementFromInt(t *testing.T, x *big.Int) *FieldElement {
+	b := make([]byte, 32)
+	x.FillBytes(b)
+	reverse(b)
+	fe, err := new(FieldElement).SetBytes(b)
+	require.NoError(t, err)
+	return fe
+}
+
+// fieldElementFromHex returns the field element of the big-endian hex string
+// h, as written in RFC 9380.
+func fieldElementFromHex(t *testing.T, h string) *FieldElement {
+	x, ok := new(big.Int).SetString(h, 16)
+	require.True(t, ok)
+	return fieldElementFromInt(t, x)
+}
+
+func reverse(b []byte) {
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+}
+
+func TestFieldElement_SetBytes(t *testing.T) {
+	one := make([]byte, 32)
+	one[0] = 1
+	fe, err := new(FieldElement).SetBytes(one)
+	require.NoError(t, err)
+	require.Equal(t, one, fe.Bytes())
+
+	max := make([]byte, 32)
+	new(big.Int).Sub(prime, big.NewInt(1)).FillBytes(max)
+	reverse(max)
+	fe, err = new(FieldElement).SetBytes(max)
+	require.NoError(t, err)
+	require.Equal(t, max, fe.Bytes())
+
+	invalid := [][]byte{
+		nil,
+		one[:31],
+		append(one, 0),
+	}
+	for _, x := range []*big.Int{prime, new(big.Int).Add(prime, big.NewInt(1)), new(big.Int).Lsh(big.NewInt(1), 255)} {
+		b := make([]byte, 33)
+		x.FillBytes(b)
+		reverse(b)
+		invalid = append(invalid, b[:32])
+	}
+	for _, b := range invalid {
+		var fe FieldElement
+		_, err := fe.SetBytes(b)
+		require.Error(t, err, "%x", b)
+		require.Equal(t, FieldElement{}, fe)
+	}
+}
+
+func TestFieldElement_Arithmetic(t *testing.T) {
+	a, _ := new(big.Int).SetString("1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef", 16)
+	b := new(big.Int).Sub(prime, big.NewInt(42))
+	fa, fb := fieldElementFromInt(t, a), fieldElementFromInt(t, b)
+	mod := func(x *big.Int) *FieldElement {
+		return fieldElementFromInt(t, x.Mod(x, prime))
+	}
+
+	require.True(t, mod(new(big.Int).Add(a, b)).Equal(new(FieldElement).Add(fa, fb)))
+	require.True(t, mod(new(big.Int).Sub(a, b)).Equal(new(FieldElement).Sub(fa, fb)))
+	require.True(t, mod(new(big.Int).Mul(a, b)).Equal(new(FieldElement).Mul(fa, fb)))
+	require.True(t, mod(new(big.Int).Mul(a, a)).Equal(new(FieldElement).Square(fa)))
+	require.True(t, mod(new(big.Int).ModInverse(a, prime)).Equal(new(FieldElement).Invert(fa)))
+	require.True(t, new(FieldElement).Invert(new(FieldElement)).Equal(new(FieldElement)))
+
+	// chained additions stay within the bounds of the multiplication
+	sum, want := new(FieldElement), new(big.Int)
+	for i := 0; i < 100; i++ {
+		sum.Add(sum, fb)
+		want.Add(want, b)
+	}
+	require.True(t, mod(want.Mul(want, a)).Equal(sum.Mul(sum, fa)))
+}
+
+func TestFieldElement_Sqrt(t *testing.T) {
+	for i := int64(0); i < 50; i++ {
+		x := fieldElementFromInt(t, big.NewInt(i))
+		r, ok := new(FieldElement).Sqrt(x)
+		isSquare := big.Jacobi(big.NewInt(i), prime) >= 0
+		require.Equal(t, isSquare, ok, "%d", i)
+		require.Equal(t, isSquare, x.IsSquare(), "%d", i)
+		if !ok {
+			require.True(t, r.Equal(new(FieldElement)))
+			continue
+		}
+		require.Zero(t, r.Bytes()[0]&1)
+		require.True(t, new(FieldElement).Square(r).Equal(x))
+	}
+
+	// sqrt(-1) is the constant of the package
+	minusOne := fieldElementFromInt(t, new(big.Int).Sub(prime, big.NewInt(1)))
+	r, ok := new(FieldElement).Sqrt(minusOne)
+	require.True(t, ok)
+	require.True(t, r.Equal(&FieldElement{sqrtM1}) || new(FieldElement).Sub(new(FieldElement), r).Equal(&FieldElement{sqrtM1}))
+}
+
+// elligator2Vectors are the vectors of the suite
+// edwards25519_XMD:SHA-512_ELL2_NU_ of Appendix J.5.2 of RFC 9380: u is the
+// output of hash_to_field for the message, Q the output of map_to_curve for u.
+var elligator2Vectors = []struct {
+	msg    string
+	u      string
+	qx, qy string
+}{
+	{
+		msg: "",
+		u:   "7f3e7fb9428103ad7f52db32f9df32505d7b427d894c5093f7a0f0374a30641d",
+		qx:  "42836f691d05211ebc65ef8fcf01e0fb6328ec9c4737c26050471e50803022eb",
+		qy:  "22cb4aaa555e23bd460262d2130d6a3c9207aa8bbb85060928beb263d6d42a95",
+	},
+	{
+		msg: "abc",
+		u:   "09cfa30ad79bd59456594a0f5d3a76f6b71c6787b04de98be5cd201a556e253b",
+		qx:  "333e41b61c6dd43af220c1ac34a3663e1cf537f996bab50ab66e33c4bd8e4e19",
+		qy:  "51b6f178eb08c4a782c820e306b82c6e273ab22e258d972cd0c511787b2a3443",
+	},
+	{
+		msg: "abcdef0123456789",
+		u:   "475ccff99225ef90d78cc9338e9f6a6bb7b17607c0c4428937de75d33edba941",
+		qx:  "55186c242c78e7d0ec5b6c9553f04c6aeef64e69ec2e824472394da32647cfc6",
+		qy:  "5b9ea3c265ee42256a8f724f616307ef38496ef7eba391c08f99f3bea6fa88f0",
+	},
+	{
+		msg: "q128_qqq...",
+		u:   "049a1c8bd51bcb2aec339f387d1ff51428b88d0763a91bcdf6929814ac95d03d",
+		qx:  "024b6e1621606dca8071aa97b43dce4040ca78284f2a527dcf5d0fbfac2b07e7",
+		qy:  "5102353883d739bdc9f8a3af650342b171217167dcce34f8db57208ec1dfdbf2",
+	},
+	{
+		msg: "a512_aaa...",
+		u:   "3cb0178a8137cefa5b79a3a57c858d7eeeaa787b2781be4a362a2f0750d24fa0",
+		qx:  "3e6368cff6e88a58e250c54bd27d2c989ae9b3acb6067f2651ad282ab8c21cd9",
+		qy:  "38fb39f1566ca118ae6c7af42810c0bb9767ae5960abb5a8ca792530bfb9447d",
+	},
+}
+
+// encodePoint returns the encoding of the point of the affine coordinates
+// x and y.
+func encodePoint(x, y *FieldElement) []byte {
+	b := y.Bytes()
+	b[31] |= x.Bytes()[0] << 7
+	return b
+}
+
+func TestElligator2(t *testing.T) {
+	for _, v := range elligator2Vectors {
+		Q := Elligator2(fieldElementFromHex(t, v.u))
+		want := encodePoint(fieldElementFromHex(t, v.qx), fieldElementFromHex(t, v.qy))
+		got, err := Q.MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, hex.EncodeToString(want), hex.EncodeToString(got), "msg %q", v.msg)
+	}
+
+	// clearing the cofactor gives the output P of encode_to_curve
+	Q := Elligator2(fieldElementFromHex(t, elligator2Vectors[0].u))
+	P := new(point).Mul(cofactorScalar, Q)
+	want := encodePoint(
+		fieldElementFromHex(t, "1ff2b70ecf862799e11b7ae744e3489aa058ce805dd323a936375a84695e76da"),
+		fieldElementFromHex(t, "222e314d04a4d5725e9f2aff9fb2a6b69ef375a1214eb19021ceab2d687f0f9b"))
+	got, _ := P.MarshalBinary()
+	require.Equal(t, want, got)
+	require.True(t, P.(*point).IsTorsionFree())
+
+	// u = 0 maps to the exceptional case of the rational map, the identity,
+	// and u and -u map to the same point
+	require.True(t, Elligator2(new(FieldElement)).Equal(nullPoint))
+	one := fieldElementFromInt(t, big.NewInt(1))
+	minusOne := new(FieldElement).Sub(new(FieldElement), one)
+	require.True(t, Elligator2(one).Equal(Elligator2(minusOne)))
+}
@@ -0,0 +1,74 @@
+//go:build go1.18
+// +build go1.18
+
+package edwards25519
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+)
+
+// FuzzPointUnmarshalBinary checks that UnmarshalBinary never panics, and that
+// the points it accepts have the encoding they were decoded from.
+func FuzzPointUnmarshalBinary(f *testing.F) {
+	base, _ := new(point).Base().MarshalBinary()
+	f.Add(base)
+	f.Add(bytes.Repeat([]byte{0xff}, 32))
+	for _, key := range weakKeys {
+		f.Add(key)
+	}
+
+	f.Fuzz(func(t *testing.T, buf []byte) {
+		var P point
+		if err := P.UnmarshalBinary(buf); err != nil {
+			return
+		}
+		out, err := P.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(out, buf) {
+			t.Fatalf("%x decoded to a point encoded as %x", buf, out)
+		}
+		if !P.IsTorsionFree() {
+			t.Fatalf("%x decoded to a point of small order", buf)
+		}
+	})
+}
+
+// FuzzFieldElementRoundTrip checks that SetBytes accepts exactly the
+// canonical encodings, which Bytes returns back, and that Elligator2 maps
+// them to points on the curve.
+func FuzzFieldElementRoundTrip(f *testing.F) {
+	f.Add(make([]byte, 32))
+	f.Add(bytes.Repeat([]byte{0xff}, 32))
+	f.Add(append([]byte{0xed}, append(bytes.Repeat([]byte{0xff}, 30), 0x7f)...))
+
+	f.Fuzz(func(t *testing.T, buf []byte) {
+		var fe FieldElement
+		if _, err := fe.SetBytes(buf); err != nil {
+			if len(buf) == 32 {
+				var x big.Int
+				le := append([]byte{}, buf...)
+				reverse(le)
+				if x.SetBytes(le).Cmp(prime) < 0 {
+					t.Fatalf("canonical encoding %x rejected: %v", buf, err)
+				}
+			}
+			return
+		}
+		if out := fe.Bytes(); !bytes.Equal(out, buf) {
+			t.Fatalf("%x decoded to a field element encoded as %x", buf, out)
+		}
+		P := Elligator2(&fe)
+		enc, err := P.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var Q point
+		if !Q.ge.FromBytes(enc) || !Q.Equal(P) {
+			t.Fatalf("%x mapped to %x, which is not on the curve", buf, enc)
+		}
+	})
+}
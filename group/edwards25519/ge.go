@@ -405,6 +405,87 @@ func geScalarMultBase(h *extendedGroupElement, a *[32]byte) {
 	}
 }
 
+// fixedBaseTable holds the multiples (j+1)*256^i*A of a point A, with which
+// geScalarMultTable multiplies A as geScalarMultBase does the base point.
+type fixedBaseTable struct {
+	// A as it was when the table was computed
+	base  extendedGroupElement
+	mults [32][8]cachedGroupElement
+}
+
+func newFixedBaseTable(A *extendedGroupElement) *fixedBaseTable {
+	table := &fixedBaseTable{base: *A}
+	var t completedGroupElement
+	var u extendedGroupElement
+	var r projectiveGroupElement
+	p := *A
+	for i := range table.mults {
+		// mults[i][j] = (j+1)*p with p = 256^i*A
+		p.ToCached(&table.mults[i][0])
+		for j := 1; j < 8; j++ {
+			t.Add(&p, &table.mults[i][j-1])
+			t.ToExtended(&u)
+			u.ToCached(&table.mults[i][j])
+		}
+		// p = 256*p = 32*(8*p)
+		u.Double(&t)
+		for j := 0; j < 4; j++ {
+			t.ToProjective(&r)
+			r.Double(&t)
+		}
+		t.ToExtended(&p)
+	}
+	return table
+}
+
+// geScalarMultTable computes h = a*A with the table of A, in constant time.
+//
+// Preconditions:
+//   a[31] <= 127
+func geScalarMultTable(h *extendedGroupElement, a *[32]byte, table *fixedBaseTable) {
+	var e [64]int8
+
+	for i, v := range a {
+		e[2*i] = int8(v & 15)
+		e[2*i+1] = int8((v >> 4) & 15)
+	}
+
+	carry := int8(0)
+	for i := 0; i < 63; i++ {
+		e[i] += carry
+		carry = (e[i] + 8) >> 4
+		e[i] -= carry << 4
+	}
+	e[63] += carry
+	// each e[i] is between -8 and 8.
+
+	h.Zero()
+	var c cachedGroupElement
+	var r completedGroupElement
+	for i := 1; i < 64; i += 2 {
+		selectCached(&c, &table.mults[i/2], int32(e[i]))
+		r.Add(h, &c)
+		r.ToExtended(h)
+	}
+
+	var s projectiveGroupElement
+
+	h.Double(&r)
+	r.ToProjective(&s)
+	s.Double(&r)
+	r.ToProjective(&s)
+	s.Double(&r)
+	r.ToProjective(&s)
+	s.Double(&r)
+	r.ToExtended(h)
+
+	for i := 0; i < 64; i += 2 {
+		selectCached(&c, &table.mults[i/2], int32(e[i]))
+		r.Add(h, &c)
+		r.ToExtended(h)
+	}
+}
+
 func selectCached(c *cachedGroupElement, Ai *[8]cachedGroupElement, b int32) {
 	bNegative := negative(b)
 	bAbs := b - (((-bNegative) & b) << 1)
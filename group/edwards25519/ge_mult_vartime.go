@@ -69,3 +69,73 @@ func geScalarMultVartime(h *extendedGroupElement, a *[32]byte,
 
 	t.ToExtended(h)
 }
+
+// geDoubleScalarMultVartime computes h = a*A + b*B, where
+//   a = a[0]+256*a[1]+...+256^31 a[31]
+//   b = b[0]+256*b[1]+...+256^31 b[31]
+//   B is the Ed25519 base point (x,4/5) with x positive.
+//
+// The multiples of A are computed on the fly as in geScalarMultVartime
+// while the multiples of B are taken from the precomputed table bi.
+//
+// Preconditions:
+//   a[31] <= 127
+//   b[31] <= 127
+func geDoubleScalarMultVartime(h *extendedGroupElement, a *[32]byte,
+	A *extendedGroupElement, b *[32]byte) {
+
+	var aSlide, bSlide [256]int8
+	var Ai [8]cachedGroupElement // A,3A,5A,7A,9A,11A,13A,15A
+	var t completedGroupElement
+	var u, A2 extendedGroupElement
+	var r projectiveGroupElement
+	var i int
+
+	slide(&aSlide, a)
+	slide(&bSlide, b)
+
+	A.ToCached(&Ai[0])
+	A.Double(&t)
+	t.ToExtended(&A2)
+	for i := 0; i < 7; i++ {
+		t.Add(&A2, &Ai[i])
+		t.ToExtended(&u)
+		u.ToCached(&Ai[i+1])
+	}
+
+	// Process the multiplications from most-significant bit downward
+	for i = 255; ; i-- {
+		if i < 0 { // no bits set
+			h.Zero()
+			return
+		}
+		if aSlide[i] != 0 || bSlide[i] != 0 {
+			break
+		}
+	}
+
+	r.Zero()
+	for ; i >= 0; i-- {
+		r.Double(&t)
+
+		if aSlide[i] > 0 {
+			t.ToExtended(&u)
+			t.Add(&u, &Ai[aSlide[i]/2])
+		} else if aSlide[i] < 0 {
+			t.ToExtended(&u)
+			t.Sub(&u, &Ai[(-aSlide[i])/2])
+		}
+
+		if bSlide[i] > 0 {
+			t.ToExtended(&u)
+			t.MixedAdd(&u, &bi[bSlide[i]/2])
+		} else if bSlide[i] < 0 {
+			t.ToExtended(&u)
+			t.MixedSub(&u, &bi[(-bSlide[i])/2])
+		}
+
+		t.ToProjective(&r)
+	}
+
+	t.ToExtended(h)
+}
@@ -16,6 +16,7 @@ package edwards25519
 
 import (
 	"crypto/cipher"
+	"crypto/subtle"
 	"encoding/hex"
 	"errors"
 	"io"
@@ -29,6 +30,8 @@ var marshalPointID = [8]byte{'e', 'd', '.', 'p', 'o', 'i', 'n', 't'}
 type point struct {
 	ge      extendedGroupElement
 	varTime bool
+	// multiples of the point computed by Precompute, if any
+	table *fixedBaseTable
 }
 
 func (P *point) String() string {
@@ -52,10 +55,25 @@ func (P *point) MarshalID() [8]byte {
 	return marshalPointID
 }
 
+// UnmarshalBinary decodes a point from its 32-byte encoding. It only accepts
+// the canonical encodings of the points of the prime-order subgroup, so that
+// each point has a unique encoding, and leaves the receiver unchanged on error.
 func (P *point) UnmarshalBinary(b []byte) error {
-	if !P.ge.FromBytes(b) {
+	var Q point
+	if !Q.ge.FromBytes(b) {
 		return errors.New("invalid Ed25519 curve point")
 	}
+	// Re-encoding reduces y modulo the prime and clears the sign of x = 0, so
+	// it differs from non-canonical encodings.
+	var c [32]byte
+	Q.ge.ToBytes(&c)
+	if subtle.ConstantTimeCompare(c[:], b) != 1 {
+		return errors.New("non-canonical Ed25519 curve point")
+	}
+	if !Q.IsTorsionFree() {
+		return errors.New("Ed25519 curve point not in the prime-order subgroup")
+	}
+	P.ge = Q.ge
 	return nil
 }
 
@@ -67,29 +85,36 @@ func (P *point) UnmarshalFrom(r io.Reader) (int, error) {
 	return marshalling.PointUnmarshalFrom(P, r)
 }
 
-// Equality test for two Points on the same curve
+// Equality test for two Points on the same curve. The comparison is done on
+// the projective coordinates, X1*Z2 == X2*Z1 and Y1*Z2 == Y2*Z1, which avoids
+// the field inversions required to compute the affine encodings.
 func (P *point) Equal(P2 kyber.Point) bool {
+	E2 := P2.(*point)
 
-	var b1, b2 [32]byte
-	P.ge.ToBytes(&b1)
-	P2.(*point).ge.ToBytes(&b2)
-	for i := range b1 {
-		if b1[i] != b2[i] {
-			return false
-		}
-	}
-	return true
+	var a, b fieldElement
+	feMul(&a, &P.ge.X, &E2.ge.Z)
+	feMul(&b, &E2.ge.X, &P.ge.Z)
+	feSub(&a, &a, &b)
+	x := feIsNonZero(&a)
+
+	feMul(&a, &P.ge.Y, &E2.ge.Z)
+	feMul(&b, &E2.ge.Y, &P.ge.Z)
+	feSub(&a, &a, &b)
+	y := feIsNonZero(&a)
+
+	return x|y == 0
 }
 
 // Set point to be equal to P2.
 func (P *point) Set(P2 kyber.Point) kyber.Point {
 	P.ge = P2.(*point).ge
+	P.table = P2.(*point).table
 	return P
 }
 
 // Set point to be equal to P2.
 func (P *point) Clone() kyber.Point {
-	return &point{ge: P.ge}
+	return &point{ge: P.ge, table: P.table}
 }
 
 // Set to the neutral element, which is (0,1) for twisted Edwards curves.
@@ -212,7 +237,9 @@ func (P *point) Neg(A kyber.Point) kyber.Point {
 	return P
 }
 
-// Mul multiplies point p by scalar s using the repeated doubling method.
+// Mul multiplies point p by scalar s using the repeated doubling method. If A
+// is nil, the base point is multiplied with a precomputed table instead, as
+// is A if its table was computed with Precompute.
 func (P *point) Mul(s kyber.Scalar, A kyber.Point) kyber.Point {
 
 	a := &s.(*scalar).v
@@ -220,7 +247,9 @@ func (P *point) Mul(s kyber.Scalar, A kyber.Point) kyber.Point {
 	if A == nil {
 		geScalarMultBase(&P.ge, a)
 	} else {
-		if P.varTime {
+		if t := A.(*point).table; t != nil && t.base == A.(*point).ge {
+			geScalarMultTable(&P.ge, a, t)
+		} else if P.varTime {
 			geScalarMultVartime(&P.ge, a, &A.(*point).ge)
 		} else {
 			geScalarMult(&P.ge, a, &A.(*point).ge)
@@ -230,6 +259,13 @@ func (P *point) Mul(s kyber.Scalar, A kyber.Point) kyber.Point {
 	return P
 }
 
+// Precompute computes the table of the multiples of the point with which Mul
+// multiplies it about twice as fast, see kyber.Precomputer. The table
+// takes 40 KiB.
+func (P *point) Precompute() {
+	P.table = newFixedBaseTable(&P.ge)
+}
+
 // HasSmallOrder determines whether the group element has small order
 //
 // Provides resilience against malicious key substitution attacks (M-S-UEO)
@@ -290,3 +326,16 @@ func (P *point) IsCanonical(s []byte) bool {
 
 	return 1-(c&d&1) == 1
 }
+
+// IsTorsionFree determines whether the group element lies in the prime-order
+// subgroup, i.e. whether it has no component of small order. Protocols that
+// rely on the uniqueness of a group element derived from untrusted input
+// (e.g. VRF outputs) must reject points that are not torsion free.
+//
+// The multiplication by the order runs in variable time, which only depends
+// on the order, the same for all points, and not on P.
+func (P *point) IsTorsionFree() bool {
+	var Q point
+	geScalarMultVartime(&Q.ge, &primeOrderScalar.v, &P.ge)
+	return Q.Equal(nullPoint)
+}
@@ -1,11 +1,13 @@
 package edwards25519
 
 import (
+	"bytes"
 	"encoding/hex"
 	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
 )
 
 func TestPoint_Marshal(t *testing.T) {
@@ -18,8 +20,7 @@ func TestPoint_Marshal(t *testing.T) {
 func TestPoint_HasSmallOrder(t *testing.T) {
 	for _, key := range weakKeys {
 		p := point{}
-		err := p.UnmarshalBinary(key)
-		require.Nil(t, err)
+		require.True(t, p.ge.FromBytes(key))
 		require.True(t, p.HasSmallOrder(), fmt.Sprintf("%s should be considered to have a small order", hex.EncodeToString(key)))
 	}
 }
@@ -45,8 +46,8 @@ func Test_PointIsCanonical(t *testing.T) {
 
 		// Check if it's a valid point on the curve that's
 		// not canonical
-		err := point.UnmarshalBinary(buffer)
-		if err == nil && !point.IsCanonical(buffer) {
+		ok := point.ge.FromBytes(buffer)
+		if ok && !point.IsCanonical(buffer) {
 			actualNonCanonicalCount++
 		}
 
@@ -55,10 +56,138 @@ func Test_PointIsCanonical(t *testing.T) {
 
 		// Check if it's a valid point on the curve that's
 		// not canonical
-		err = point.UnmarshalBinary(buffer)
-		if err == nil && !point.IsCanonical(buffer) {
+		ok = point.ge.FromBytes(buffer)
+		if ok && !point.IsCanonical(buffer) {
 			actualNonCanonicalCount++
 		}
 	}
 	require.Equal(t, expectedNonCanonicalCount, actualNonCanonicalCount, "Incorrect number of non canonical points detected")
 }
+
+// TestPoint_IsTorsionFree ensures that points with a small order component
+// are detected while prime-order points are accepted
+func TestPoint_IsTorsionFree(t *testing.T) {
+	base := new(point).Base()
+	require.True(t, base.(*point).IsTorsionFree())
+
+	for _, key := range weakKeys {
+		small := point{}
+		require.True(t, small.ge.FromBytes(key))
+		if small.Equal(nullPoint) {
+			continue
+		}
+		p := new(point)
+		p.Add(base, &small)
+		require.False(t, p.IsTorsionFree())
+	}
+}
+
+// TestPoint_UnmarshalStrict ensures that only the canonical encodings of the
+// points of the prime-order subgroup are decoded
+func TestPoint_UnmarshalStrict(t *testing.T) {
+	s := NewBlakeSHA256Ed25519()
+	for i := 0; i < 16; i++ {
+		P := s.Point().Pick(s.RandomStream())
+		buf, err := P.MarshalBinary()
+		require.NoError(t, err)
+		Q := s.Point()
+		require.NoError(t, Q.UnmarshalBinary(buf))
+		require.True(t, P.Equal(Q))
+	}
+
+	identity := make([]byte, 32)
+	identity[0] = 1
+	require.NoError(t, s.Point().UnmarshalBinary(identity))
+
+	var rejected [][]byte
+	for _, key := range weakKeys {
+		if !bytes.Equal(key, identity) {
+			rejected = append(rejected, key)
+		}
+	}
+	// the identity with the sign bit of x = 0 set
+	negZero := append([]byte{}, identity...)
+	negZero[31] |= 0x80
+	// the identity with y = p + 1
+	nonReduced := append([]byte{0xee}, bytes.Repeat([]byte{0xff}, 30)...)
+	nonReduced = append(nonReduced, 0x7f)
+	allFF := bytes.Repeat([]byte{0xff}, 32)
+	rejected = append(rejected, negZero, nonReduced, allFF)
+
+	P := s.Point().Pick(s.RandomStream())
+	Q := P.Clone()
+	for _, buf := range rejected {
+		require.Error(t, Q.UnmarshalBinary(buf), hex.EncodeToString(buf))
+		require.True(t, P.Equal(Q), "receiver modified on error")
+	}
+	for n := 0; n <= 64; n++ {
+		if n != 32 {
+			require.Error(t, Q.UnmarshalBinary(make([]byte, n)))
+		}
+	}
+}
+
+func TestPoint_EqualRepresentations(t *testing.T) {
+	s := NewBlakeSHA256Ed25519()
+	a := s.Scalar().Pick(s.RandomStream())
+	b := s.Scalar().Pick(s.RandomStream())
+	Q := s.Point().Pick(s.RandomStream())
+
+	// the same point computed through different chains of operations has
+	// different projective coordinates
+	P1 := s.Point().Mul(s.Scalar().Add(a, b), nil)
+	P2 := s.Point().Add(s.Point().Mul(a, nil), s.Point().Mul(b, nil))
+	P3 := s.Point().Sub(s.Point().Add(P1, Q), Q)
+	require.NotEqual(t, P1.(*point).ge, P3.(*point).ge)
+	for _, P := range []kyber.Point{P2, P3} {
+		require.True(t, P1.Equal(P))
+		require.True(t, P.Equal(P1))
+	}
+	require.False(t, P1.Equal(Q))
+	require.True(t, s.Point().Sub(Q, Q).Equal(s.Point().Null()))
+}
+
+// TestPoint_MulBase cross-checks the multiplication of the base point with the
+// precomputed table against the generic multiplication.
+func TestPoint_MulBase(t *testing.T) {
+	n := 10000
+	if testing.Short() {
+		n = 500
+	}
+	base := tSuite.Point().Base()
+	for i := 0; i < n; i++ {
+		s := tSuite.Scalar().Pick(tSuite.RandomStream())
+		require.True(t, tSuite.Point().Mul(s, base).Equal(tSuite.Point().Mul(s, nil)))
+	}
+}
+
+// TestPoint_Precompute cross-checks the multiplications of a point with its
+// precomputed table against the generic multiplication.
+func TestPoint_Precompute(t *testing.T) {
+	A := tSuite.Point().Pick(tSuite.RandomStream())
+	P := A.Clone()
+	P.(kyber.Precomputer).Precompute()
+	for i := 0; i < 500; i++ {
+		s := tSuite.Scalar().Pick(tSuite.RandomStream())
+		require.True(t, tSuite.Point().Mul(s, A).Equal(tSuite.Point().Mul(s, P)))
+	}
+	s := tSuite.Scalar().Pick(tSuite.RandomStream())
+	require.True(t, tSuite.Point().Mul(s, A).Equal(tSuite.Point().Mul(s, P.Clone())))
+	require.True(t, tSuite.Point().Null().Equal(tSuite.Point().Mul(tSuite.Scalar().Zero(), P)))
+
+	// the table is ignored once the point changes
+	P.Add(P, A)
+	A.Add(A, A)
+	require.True(t, tSuite.Point().Mul(s, A).Equal(tSuite.Point().Mul(s, P)))
+}
+
+func BenchmarkPointMulPrecomputed(b *testing.B) {
+	A := tSuite.Point().Pick(tSuite.RandomStream())
+	A.(kyber.Precomputer).Precompute()
+	s := tSuite.Scalar().Pick(tSuite.RandomStream())
+	P := tSuite.Point()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		P.Mul(s, A)
+	}
+}
@@ -1,5 +1,7 @@
 package edwards25519
 
+import "go.dedis.ch/kyber/v3"
+
 // AllowVarTime sets a flag in this object which determines if a faster
 // but variable time implementation can be used. Set this only on Points
 // which represent public information. Using variable time algorithms to
@@ -7,3 +9,14 @@ package edwards25519
 func (P *point) AllowVarTime(varTime bool) {
 	P.varTime = varTime
 }
+
+// MulAddVartime sets the receiver to a*A + b*B where B is the standard base
+// point, using a variable time double-base scalar multiplication. It is
+// significantly faster than computing both products separately, which makes
+// it well suited to signature verification. As the running time depends on
+// the scalars, it must only be used on public information such as the
+// values involved in a signature verification, never on secret ones.
+func (P *point) MulAddVartime(a kyber.Scalar, A kyber.Point, b kyber.Scalar) kyber.Point {
+	geDoubleScalarMultVartime(&P.ge, &a.(*scalar).v, &A.(*point).ge, &b.(*scalar).v)
+	return P
+}
@@ -0,0 +1,64 @@
+package edwards25519
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+)
+
+// TestPoint_MulAddVartime checks that the variable time double-base
+// multiplication agrees with the constant time path on random inputs.
+func TestPoint_MulAddVartime(t *testing.T) {
+	stream := tSuite.RandomStream()
+	exp := tSuite.Point()
+	res := tSuite.Point()
+	for i := 0; i < 10000; i++ {
+		a := tSuite.Scalar().Pick(stream)
+		b := tSuite.Scalar().Pick(stream)
+		A := tSuite.Point().Pick(stream)
+
+		exp.Mul(a, A)
+		exp.Add(exp, tSuite.Point().Mul(b, nil))
+		res.(kyber.VartimeMulAdder).MulAddVartime(a, A, b)
+		require.True(t, exp.Equal(res), "mismatch for a=%s A=%s b=%s", a, A, b)
+	}
+
+	// edge cases with zero scalars
+	zero := tSuite.Scalar().Zero()
+	one := tSuite.Scalar().One()
+	A := tSuite.Point().Pick(stream)
+	res.(kyber.VartimeMulAdder).MulAddVartime(zero, A, zero)
+	require.True(t, res.Equal(nullPoint))
+	res.(kyber.VartimeMulAdder).MulAddVartime(one, A, zero)
+	require.True(t, res.Equal(A))
+	res.(kyber.VartimeMulAdder).MulAddVartime(zero, A, one)
+	require.True(t, res.Equal(tSuite.Point().Base()))
+}
+
+func BenchmarkPointMulAdd(b *testing.B) {
+	stream := tSuite.RandomStream()
+	s1 := tSuite.Scalar().Pick(stream)
+	s2 := tSuite.Scalar().Pick(stream)
+	A := tSuite.Point().Pick(stream)
+	P := tSuite.Point()
+	Q := tSuite.Point()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		P.Mul(s1, A)
+		Q.Mul(s2, nil)
+		P.Add(P, Q)
+	}
+}
+
+func BenchmarkPointMulAddVartime(b *testing.B) {
+	stream := tSuite.RandomStream()
+	s1 := tSuite.Scalar().Pick(stream)
+	s2 := tSuite.Scalar().Pick(stream)
+	A := tSuite.Point().Pick(stream)
+	P := tSuite.Point().(kyber.VartimeMulAdder)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		P.MulAddVartime(s1, A, s2)
+	}
+}
@@ -7,6 +7,7 @@ package edwards25519
 import (
 	"crypto/cipher"
 	"crypto/subtle"
+	"encoding/binary"
 	"encoding/hex"
 	"errors"
 	"io"
@@ -29,11 +30,22 @@ type scalar struct {
 	v [32]byte
 }
 
-// Equality test for two Scalars derived from the same Group
+// Equality test for two Scalars derived from the same Group. The comparison
+// runs in constant time on the canonical encodings, so that a scalar
+// unmarshalled from a non-reduced encoding equals its reduced form.
 func (s *scalar) Equal(s2 kyber.Scalar) bool {
-	v1 := s.v[:]
-	v2 := s2.(*scalar).v[:]
-	return subtle.ConstantTimeCompare(v1, v2) != 0
+	var v1, v2 [32]byte
+	canonical(&v1, &s.v)
+	canonical(&v2, &s2.(*scalar).v)
+	return subtle.ConstantTimeCompare(v1[:], v2[:]) == 1
+}
+
+// canonical sets out to the little endian encoding of v reduced modulo the
+// prime order, in constant time.
+func canonical(out, v *[32]byte) {
+	var wide [64]byte
+	copy(wide[:], v[:])
+	scReduce(out, &wide)
 }
 
 // Set equal to another Scalar a
@@ -56,7 +68,18 @@ func (s *scalar) setInt(i *mod.Int) kyber.Scalar {
 
 // SetInt64 sets the scalar to a small integer value.
 func (s *scalar) SetInt64(v int64) kyber.Scalar {
-	return s.setInt(mod.NewInt64(v, primeOrder))
+	// |v| < 2^63 is smaller than the group order, so its little endian
+	// encoding is already reduced
+	u := uint64(v)
+	if v < 0 {
+		u = uint64(-v)
+	}
+	s.v = [32]byte{}
+	binary.LittleEndian.PutUint64(s.v[:8], u)
+	if v < 0 {
+		s.Neg(s)
+	}
+	return s
 }
 
 func (s *scalar) toInt() *mod.Int {
@@ -69,6 +92,13 @@ func (s *scalar) Zero() kyber.Scalar {
 	return s
 }
 
+// Wipe overwrites the value of the scalar with zeros. See kyber.Wiper.
+func (s *scalar) Wipe() {
+	for i := range s.v {
+		s.v[i] = 0
+	}
+}
+
 // Set to the multiplicative identity (1)
 func (s *scalar) One() kyber.Scalar {
 	s.v = [32]byte{1}
@@ -143,6 +173,58 @@ func (s *scalar) SetBytes(b []byte) kyber.Scalar {
 	return s.setInt(mod.NewIntBytes(b, primeOrder, mod.LittleEndian))
 }
 
+// BytesLE returns the canonical little endian encoding of s, as in RFC 8032.
+func (s *scalar) BytesLE() []byte {
+	var out [32]byte
+	canonical(&out, &s.v)
+	return out[:]
+}
+
+// BytesBE returns the canonical big endian encoding of s.
+func (s *scalar) BytesBE() []byte {
+	return reversed(s.BytesLE())
+}
+
+// SetBytesLE sets s to the canonical little endian encoding b of 32 bytes, as
+// in RFC 8032. It returns an error if b is not smaller than the order.
+func (s *scalar) SetBytesLE(b []byte) (kyber.Scalar, error) {
+	if len(b) != 32 {
+		return nil, errors.New("wrong size buffer")
+	}
+	if !s.IsCanonical(b) {
+		return nil, errors.New("non-canonical scalar")
+	}
+	copy(s.v[:], b)
+	return s, nil
+}
+
+// SetBytesBE sets s to the canonical big endian encoding b of 32 bytes. It
+// returns an error if b is not smaller than the order.
+func (s *scalar) SetBytesBE(b []byte) (kyber.Scalar, error) {
+	return s.SetBytesLE(reversed(b))
+}
+
+// SetBytesWide sets s to the big endian integer b of at most 64 bytes reduced
+// modulo the order, in constant time.
+func (s *scalar) SetBytesWide(b []byte) (kyber.Scalar, error) {
+	if len(b) > 64 {
+		return nil, errors.New("wrong size buffer")
+	}
+	var wide [64]byte
+	copy(wide[:], reversed(b))
+	scReduce(&s.v, &wide)
+	return s, nil
+}
+
+// reversed returns a copy of b in reversed byte order.
+func reversed(b []byte) []byte {
+	r := make([]byte, len(b))
+	for i := range b {
+		r[len(b)-1-i] = b[i]
+	}
+	return r
+}
+
 // String returns the string representation of this scalar (fixed length of 32 bytes, little endian).
 func (s *scalar) String() string {
 	b, _ := s.toInt().MarshalBinary()
@@ -1,12 +1,16 @@
 package edwards25519
 
 import (
+	"bytes"
 	"fmt"
+	"math"
 	"math/big"
+	"math/rand"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/group/mod"
 	"go.dedis.ch/kyber/v3/util/random"
 )
 
@@ -110,6 +114,22 @@ func TestString(t *testing.T) {
 	}
 }
 
+func TestSetInt64(t *testing.T) {
+	values := []int64{0, 1, -1, 2, -2, 0x100, 1 << 32, -(1 << 40), math.MaxInt64, math.MinInt64}
+	for i := 0; i < 100; i++ {
+		values = append(values, rand.Int63()-rand.Int63())
+	}
+	for _, v := range values {
+		expected := new(scalar).setInt(mod.NewInt64(v, primeOrder))
+		require.True(t, expected.Equal(new(scalar).SetInt64(v)), "%d", v)
+	}
+	allocs := testing.AllocsPerRun(10, func() {
+		var s scalar
+		s.SetInt64(-42)
+	})
+	require.Equal(t, 0.0, allocs)
+}
+
 func TestScalar_Marshal(t *testing.T) {
 	s := &scalar{}
 	require.Equal(t, "ed.scala", fmt.Sprintf("%s", s.MarshalID()))
@@ -479,3 +499,72 @@ func Test_ScalarIsCanonical(t *testing.T) {
 		candidateBuf[0]++
 	}
 }
+
+func TestScalarEqualCanonical(t *testing.T) {
+	s := new(scalar).Pick(random.New()).(*scalar)
+
+	// s + l is a non-canonical encoding of s
+	v := new(big.Int).Add(&s.toInt().V, primeOrder)
+	nonCanonical := newScalarInt(v)
+	require.False(t, bytes.Equal(s.v[:], nonCanonical.v[:]))
+	require.True(t, s.Equal(nonCanonical))
+	require.True(t, nonCanonical.Equal(s))
+
+	other := new(scalar).Add(s, one)
+	require.False(t, s.Equal(other))
+	require.False(t, new(scalar).Zero().Equal(one))
+	require.True(t, new(scalar).Zero().Equal(primeOrderScalar))
+}
+
+func TestScalarByteOrder(t *testing.T) {
+	s := new(scalar).Pick(random.New()).(*scalar)
+	var enc kyber.ScalarEncoder = s
+	le, err := s.MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, le, enc.BytesLE())
+	be := enc.BytesBE()
+	for i := range be {
+		require.Equal(t, le[31-i], be[i])
+	}
+	s2, err := new(scalar).SetBytesBE(be)
+	require.NoError(t, err)
+	require.True(t, s.Equal(s2))
+	s2, err = new(scalar).SetBytesLE(le)
+	require.NoError(t, err)
+	require.True(t, s.Equal(s2))
+
+	// the order is rejected, the order minus one is not
+	l := primeOrder.Bytes()
+	_, err = new(scalar).SetBytesBE(l)
+	require.Error(t, err)
+	l[31]--
+	s2, err = new(scalar).SetBytesBE(l)
+	require.NoError(t, err)
+	require.True(t, s2.Equal(minusOne))
+	_, err = new(scalar).SetBytesLE(append(minusOne.BytesLE(), 0))
+	require.Error(t, err)
+
+	// the wide reduction is the one of big.Int
+	wide := make([]byte, 64)
+	for i := range wide {
+		wide[i] = 0xff
+	}
+	for _, n := range []int{0, 1, 31, 32, 33, 64} {
+		s2, err = new(scalar).SetBytesWide(wide[:n])
+		require.NoError(t, err)
+		v := new(big.Int).SetBytes(wide[:n])
+		require.True(t, s2.Equal(newScalarInt(v.Mod(v, primeOrder))))
+	}
+	_, err = new(scalar).SetBytesWide(append(wide, 0))
+	require.Error(t, err)
+}
+
+func TestScalarWipe(t *testing.T) {
+	s := new(scalar).Pick(random.New()).(*scalar)
+	var w kyber.Wiper = s
+	w.Wipe()
+	require.Equal(t, [32]byte{}, s.v)
+	require.True(t, s.Equal(new(scalar).Zero()))
+	s.Add(s, new(scalar).One())
+	require.True(t, s.Equal(new(scalar).One()))
+}
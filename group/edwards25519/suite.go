@@ -53,6 +53,20 @@ func (s *SuiteEd25519) RandomStream() cipher.Stream {
 	return random.New()
 }
 
+// seedStreamDomain separates the streams of RandomStreamFromSeed from any
+// other use of the XOF of the suite.
+const seedStreamDomain = "kyber.edwards25519.RandomStreamFromSeed"
+
+// RandomStreamFromSeed returns a deterministic cipher.Stream derived from the
+// given seed with the XOF of the suite. The same seed yields the same stream
+// across versions of this package, so that values picked from it can be used
+// as test vectors. The stream is only as unpredictable as the seed.
+func (s *SuiteEd25519) RandomStreamFromSeed(seed []byte) cipher.Stream {
+	x := s.XOF([]byte(seedStreamDomain))
+	_, _ = x.Write(seed)
+	return x
+}
+
 // NewBlakeSHA256Ed25519 returns a cipher suite based on package
 // go.dedis.ch/kyber/v3/xof/blake2xb, SHA-256, and the Ed25519 curve.
 // It produces cryptographically random numbers via package crypto/rand.
@@ -0,0 +1,26 @@
+package edwards25519
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestRandomStreamFromSeed checks that the seeded stream is stable, as its
+// output is part of the compatibility contract of the suite.
+func TestRandomStreamFromSeed(t *testing.T) {
+	s := NewBlakeSHA256Ed25519()
+	for i := 0; i < 2; i++ {
+		r := s.RandomStreamFromSeed([]byte("kyber test vector"))
+		scalar, err := s.Scalar().Pick(r).MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, "8c4e9e05ffed9e2ebf4d7f0c87d0cb34a5e1d79f58ca0a345748e65fe0d8960e", hex.EncodeToString(scalar))
+		point, err := s.Point().Pick(r).MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, "6b0e97530c0041dc43353420dbc06ad1939e787062706df6557cfba6d7c20f46", hex.EncodeToString(point))
+	}
+
+	other := s.Scalar().Pick(s.RandomStreamFromSeed([]byte("kyber test vector 2")))
+	require.False(t, other.Equal(s.Scalar().Pick(s.RandomStreamFromSeed([]byte("kyber test vector")))))
+}
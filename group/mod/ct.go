@@ -0,0 +1,272 @@
+package mod
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// Modulus is an odd prime modulus with the precomputed values of its
+// Montgomery arithmetic on fixed-length words, which the Ints of
+// NewConstantTimeInt64 use for Add, Sub, Neg, Mul, Div, Inv and Exp, where
+// the exponent is public, and for the reductions of SetBytes and
+// SetBytesWide. These operations compute on as many words as the modulus,
+// without branching nor accessing memory depending on the values, unlike the
+// math/big arithmetic of the other Ints.
+//
+// This is not a complete constant-time implementation. The values are kept
+// in the big.Int V of the Ints, which drops the high zero words, and each
+// operation copies them from and to V: the number of words copied and the
+// size of V depend on the number of high zero words of the values, which
+// only differs from the one of the modulus for values much smaller than it.
+// The comparisons, Equal, the encodings, Jacobi and Sqrt go through math/big
+// and do not run in constant time.
+type Modulus struct {
+	m *big.Int
+	// m in little-endian words
+	limbs []big.Word
+	// -m⁻¹ mod 2^_W
+	m0inv big.Word
+	// R² mod m, with R = 2^(_W·n) for n words
+	rr []big.Word
+	// m - 2, the exponent of the inverses
+	mMinus2 *big.Int
+}
+
+// NewModulus returns the Modulus m, which must be an odd prime, for the Ints
+// of NewConstantTimeInt64.
+func NewModulus(m *big.Int) *Modulus {
+	if m.Sign() <= 0 || m.Bit(0) == 0 {
+		panic("mod: the modulus of the constant-time arithmetic must be odd")
+	}
+	n := len(m.Bits())
+	limbs := make([]big.Word, n)
+	copy(limbs, m.Bits())
+
+	// Newton's iteration doubles the number of correct low bits of the
+	// inverse of m0, starting from 3 bits as m0·m0 = 1 mod 8
+	inv := limbs[0]
+	for i := 0; i < 6; i++ {
+		inv *= 2 - limbs[0]*inv
+	}
+
+	rr := new(big.Int).Lsh(one, uint(2*n*bits.UintSize))
+	rr.Mod(rr, m)
+	return &Modulus{
+		m:       m,
+		limbs:   limbs,
+		m0inv:   -inv,
+		rr:      newNat(n, rr.Bits()),
+		mMinus2: new(big.Int).Sub(m, two),
+	}
+}
+
+// Int returns the modulus as a big.Int, which must not be modified.
+func (m *Modulus) Int() *big.Int {
+	return m.m
+}
+
+// newNat returns the n-word copy of the little-endian words x, which must
+// not be longer.
+func newNat(n int, x []big.Word) []big.Word {
+	z := make([]big.Word, n)
+	copy(z, x)
+	return z
+}
+
+// nat returns the words of the value x of an Int, which is reduced if it is
+// longer than the modulus.
+func (m *Modulus) nat(x *big.Int) []big.Word {
+	if len(x.Bits()) > len(m.limbs) {
+		return m.reduce(x.Bits())
+	}
+	return newNat(len(m.limbs), x.Bits())
+}
+
+// add sets z to x + y mod m.
+func (m *Modulus) add(z, x, y *big.Int) {
+	a, b := m.nat(x), m.nat(y)
+	z.SetBits(m.addNat(a, a, b))
+}
+
+// sub sets z to x - y mod m.
+func (m *Modulus) sub(z, x, y *big.Int) {
+	a, b := m.nat(x), m.nat(y)
+	z.SetBits(m.subNat(a, a, b))
+}
+
+// mul sets z to x·y mod m.
+func (m *Modulus) mul(z, x, y *big.Int) {
+	a, b := m.nat(x), m.nat(y)
+	// (a·b·R⁻¹)·R²·R⁻¹ = a·b
+	m.montMul(a, a, b)
+	z.SetBits(m.montMul(a, a, m.rr))
+}
+
+// exp sets z to x^e mod m. The exponent is public, only its length being
+// processed in constant time.
+func (m *Modulus) exp(z, x, e *big.Int) {
+	a := m.nat(x)
+	if e.Sign() < 0 {
+		a = m.expNat(a, m.mMinus2)
+		e = new(big.Int).Neg(e)
+	}
+	z.SetBits(m.expNat(a, e))
+}
+
+// inv sets z to x⁻¹ mod m, with Fermat's little theorem. The inverse of zero
+// is zero.
+func (m *Modulus) inv(z, x *big.Int) {
+	z.SetBits(m.expNat(m.nat(x), m.mMinus2))
+}
+
+// setBytes sets z to the big-endian integer b reduced modulo m.
+func (m *Modulus) setBytes(z *big.Int, b []byte) {
+	var x big.Int
+	z.SetBits(m.reduce(x.SetBytes(b).Bits()))
+}
+
+// addNat sets z to x + y mod m and returns it, with x, y < m.
+func (m *Modulus) addNat(z, x, y []big.Word) []big.Word {
+	var c uint
+	for i := range z {
+		var s uint
+		s, c = bits.Add(uint(x[i]), uint(y[i]), c)
+		z[i] = big.Word(s)
+	}
+	return m.condSub(z, c)
+}
+
+// subNat sets z to x - y mod m and returns it, with x, y < m.
+func (m *Modulus) subNat(z, x, y []big.Word) []big.Word {
+	var b uint
+	for i := range z {
+		var d uint
+		d, b = bits.Sub(uint(x[i]), uint(y[i]), b)
+		z[i] = big.Word(d)
+	}
+	// adds m back if the difference is negative
+	mask := -b
+	var c uint
+	for i := range z {
+		var s uint
+		s, c = bits.Add(uint(z[i]), uint(m.limbs[i])&mask, c)
+		z[i] = big.Word(s)
+	}
+	return z
+}
+
+// condSub subtracts m from hi·R + z if it is at least m, for a value smaller
+// than 2m, and returns z.
+func (m *Modulus) condSub(z []big.Word, hi uint) []big.Word {
+	var b uint
+	for i := range z {
+		_, b = bits.Sub(uint(z[i]), uint(m.limbs[i]), b)
+	}
+	// m is subtracted unless the difference is negative, i.e. there is a
+	// borrow and no high word
+	mask := -((hi | (b ^ 1)) & 1)
+	b = 0
+	for i := range z {
+		var d uint
+		d, b = bits.Sub(uint(z[i]), uint(m.limbs[i])&mask, b)
+		z[i] = big.Word(d)
+	}
+	return z
+}
+
+// montMul sets z to x·y·R⁻¹ mod m and returns it, with x < R and y < m, so
+// that x·y < m·R, with the CIOS Montgomery multiplication. z may alias x or y.
+func (m *Modulus) montMul(z, x, y []big.Word) []big.Word {
+	n := len(m.limbs)
+	// on the stack for moduli of up to 1024 bits
+	var buf [18]big.Word
+	t := buf[:]
+	if n+2 > len(buf) {
+		t = make([]big.Word, n+2)
+	}
+	for i := range t {
+		t[i] = 0
+	}
+	for i := 0; i < n; i++ {
+		// t += x·y[i]
+		var c big.Word
+		for j := 0; j < n; j++ {
+			c, t[j] = mulAddWWW(x[j], y[i], t[j], c)
+		}
+		var cc uint
+		var s uint
+		s, cc = bits.Add(uint(t[n]), uint(c), 0)
+		t[n], t[n+1] = big.Word(s), big.Word(cc)
+
+		// t = (t + u·m) / 2^_W, with u such that t + u·m = 0 mod 2^_W
+		u := t[0] * m.m0inv
+		c, _ = mulAddWWW(u, m.limbs[0], t[0], 0)
+		for j := 1; j < n; j++ {
+			c, t[j-1] = mulAddWWW(u, m.limbs[j], t[j], c)
+		}
+		s, cc = bits.Add(uint(t[n]), uint(c), 0)
+		t[n-1], t[n] = big.Word(s), t[n+1]+big.Word(cc)
+	}
+	copy(z, t[:n])
+	return m.condSub(z[:n], uint(t[n]))
+}
+
+// expNat returns x^e mod m, with x < m, with windows of 4 bits of e. The
+// exponent being public, the multiplications depend on it but not on x.
+func (m *Modulus) expNat(x []big.Word, e *big.Int) []big.Word {
+	n := len(m.limbs)
+	one := newNat(n, []big.Word{1})
+	// table[k] = x^k·R mod m, in the Montgomery domain
+	var table [16][]big.Word
+	table[0] = m.montMul(make([]big.Word, n), one, m.rr)
+	table[1] = m.montMul(make([]big.Word, n), x, m.rr)
+	for k := 2; k < len(table); k++ {
+		table[k] = m.montMul(make([]big.Word, n), table[k-1], table[1])
+	}
+	z := newNat(n, table[0])
+	for i := (e.BitLen() + 3) / 4 * 4; i > 0; i -= 4 {
+		for j := 0; j < 4; j++ {
+			m.montMul(z, z, z)
+		}
+		w := e.Bit(i-1)<<3 | e.Bit(i-2)<<2 | e.Bit(i-3)<<1 | e.Bit(i-4)
+		if w != 0 {
+			m.montMul(z, z, table[w])
+		}
+	}
+	return m.montMul(z, z, one)
+}
+
+// reduce returns the little-endian words x of any length reduced modulo m,
+// processing them by chunks c of n words from the most significant one,
+// with r = r·R + c mod m.
+func (m *Modulus) reduce(x []big.Word) []big.Word {
+	n := len(m.limbs)
+	one := newNat(n, []big.Word{1})
+	r := make([]big.Word, n)
+	c := make([]big.Word, n)
+	for j := (len(x) + n - 1) / n; j > 0; j-- {
+		for i := range c {
+			c[i] = 0
+			if k := (j-1)*n + i; k < len(x) {
+				c[i] = x[k]
+			}
+		}
+		// c·R·R⁻¹ = c mod m, as c < R
+		m.montMul(c, c, m.rr)
+		m.montMul(c, c, one)
+		m.montMul(r, r, m.rr)
+		m.addNat(r, r, c)
+	}
+	return r
+}
+
+// mulAddWWW returns x·y + z + c as the words (hi, lo).
+func mulAddWWW(x, y, z, c big.Word) (hi, lo big.Word) {
+	h, l := bits.Mul(uint(x), uint(y))
+	var cc uint
+	l, cc = bits.Add(l, uint(z), 0)
+	h += cc
+	l, cc = bits.Add(l, uint(c), 0)
+	h += cc
+	return big.Word(h), big.Word(l)
+}
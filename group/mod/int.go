@@ -4,6 +4,7 @@ package mod
 
 import (
 	"crypto/cipher"
+	"crypto/subtle"
 	"encoding/hex"
 	"errors"
 	"io"
@@ -46,10 +47,19 @@ const (
 // target objects, and receive the modulus of the first operand.
 // For efficiency the modulus field M is a pointer,
 // whose target is assumed never to change.
+//
+// The arithmetic of math/big is not constant time, so that it leaks the
+// values of secret Ints through timing side channels. The Ints of
+// NewConstantTimeInt64 compute on fixed-length words instead, see Modulus for
+// what does and does not run in constant time, which the Ints computed from
+// them inherit.
 type Int struct {
 	V  big.Int   // Integer value from 0 through M-1
 	M  *big.Int  // Modulus for finite field arithmetic
 	BO ByteOrder // Endianness which will be used on input and output
+
+	// constant-time arithmetic modulo M, if any
+	ct *Modulus
 }
 
 // NewInt creaters a new Int with a given big.Int and a big.Int modulus.
@@ -62,6 +72,15 @@ func NewInt64(v int64, M *big.Int) *Int {
 	return new(Int).Init64(v, M)
 }
 
+// NewConstantTimeInt64 creates a new Int with a given int64 value modulo m,
+// whose arithmetic operations compute on fixed-length words, as do the ones
+// of the Ints computed from it. See Modulus for what runs in constant time.
+func NewConstantTimeInt64(v int64, m *Modulus) *Int {
+	i := new(Int).Init64(v, m.m)
+	i.ct = m
+	return i
+}
+
 // NewIntBytes creates a new Int with a given slice of bytes and a big.Int
 // modulus.
 func NewIntBytes(a []byte, m *big.Int, byteOrder ByteOrder) *Int {
@@ -78,6 +97,7 @@ func NewIntString(n, d string, base int, m *big.Int) *Int {
 // Note that the value is copied; the modulus is not.
 func (i *Int) Init(V *big.Int, m *big.Int) *Int {
 	i.M = m
+	i.ct = nil
 	i.BO = BigEndian
 	i.V.Set(V).Mod(&i.V, m)
 	return i
@@ -86,6 +106,7 @@ func (i *Int) Init(V *big.Int, m *big.Int) *Int {
 // Init64 creates an Int with an int64 value and big.Int modulus.
 func (i *Int) Init64(v int64, m *big.Int) *Int {
 	i.M = m
+	i.ct = nil
 	i.BO = BigEndian
 	i.V.SetInt64(v).Mod(&i.V, m)
 	return i
@@ -94,6 +115,7 @@ func (i *Int) Init64(v int64, m *big.Int) *Int {
 // InitBytes init the Int to a number represented in a big-endian byte string.
 func (i *Int) InitBytes(a []byte, m *big.Int, byteOrder ByteOrder) *Int {
 	i.M = m
+	i.ct = nil
 	i.BO = byteOrder
 	i.SetBytes(a)
 	return i
@@ -103,6 +125,7 @@ func (i *Int) InitBytes(a []byte, m *big.Int, byteOrder ByteOrder) *Int {
 // specified with a pair of strings in a given base.
 func (i *Int) InitString(n, d string, base int, m *big.Int) *Int {
 	i.M = m
+	i.ct = nil
 	i.BO = BigEndian
 	if _, succ := i.SetString(n, d, base); !succ {
 		panic("InitString: invalid fraction representation")
@@ -139,9 +162,16 @@ func (i *Int) Cmp(s2 kyber.Scalar) int {
 	return i.V.Cmp(&s2.(*Int).V)
 }
 
-// Equal returns true if the two Ints are equal
+// Equal returns true if the two Ints are equal. The fixed-length encodings of
+// the values are compared with subtle.ConstantTimeCompare, but encoding them
+// through math/big does not run in constant time.
 func (i *Int) Equal(s2 kyber.Scalar) bool {
-	return i.V.Cmp(&s2.(*Int).V) == 0
+	j := s2.(*Int)
+	l := i.MarshalSize()
+	if m := j.MarshalSize(); m > l {
+		l = m
+	}
+	return subtle.ConstantTimeCompare(i.LittleEndian(l, l), j.LittleEndian(l, l)) == 1
 }
 
 // Nonzero returns true if the integer value is nonzero.
@@ -156,11 +186,17 @@ func (i *Int) Set(a kyber.Scalar) kyber.Scalar {
 	ai := a.(*Int)
 	i.V.Set(&ai.V)
 	i.M = ai.M
+	i.ct = ai.ct
 	return i
 }
 
 // Clone returns a separate duplicate of this Int.
 func (i *Int) Clone() kyber.Scalar {
+	if i.ct != nil {
+		ni := &Int{M: i.M, BO: i.BO, ct: i.ct}
+		ni.V.Set(&i.V)
+		return ni
+	}
 	ni := new(Int).Init(&i.V, i.M)
 	ni.BO = i.BO
 	return ni
@@ -172,6 +208,18 @@ func (i *Int) Zero() kyber.Scalar {
 	return i
 }
 
+// Wipe overwrites the words holding the value of the Int, including the
+// unused capacity of its buffer, and sets it to 0. The modulus is kept, so
+// that the Int can still be used. See kyber.Wiper.
+func (i *Int) Wipe() {
+	words := i.V.Bits()
+	words = words[:cap(words)]
+	for j := range words {
+		words[j] = 0
+	}
+	i.V.SetInt64(0)
+}
+
 // One sets the Int to the value 1.  The modulus must already be initialized.
 func (i *Int) One() kyber.Scalar {
 	i.V.SetInt64(1)
@@ -209,6 +257,11 @@ func (i *Int) Add(a, b kyber.Scalar) kyber.Scalar {
 	ai := a.(*Int)
 	bi := b.(*Int)
 	i.M = ai.M
+	i.ct = ai.ct
+	if i.ct != nil {
+		i.ct.add(&i.V, &ai.V, &bi.V)
+		return i
+	}
 	i.V.Add(&ai.V, &bi.V).Mod(&i.V, i.M)
 	return i
 }
@@ -219,6 +272,11 @@ func (i *Int) Sub(a, b kyber.Scalar) kyber.Scalar {
 	ai := a.(*Int)
 	bi := b.(*Int)
 	i.M = ai.M
+	i.ct = ai.ct
+	if i.ct != nil {
+		i.ct.sub(&i.V, &ai.V, &bi.V)
+		return i
+	}
 	i.V.Sub(&ai.V, &bi.V).Mod(&i.V, i.M)
 	return i
 }
@@ -227,6 +285,12 @@ func (i *Int) Sub(a, b kyber.Scalar) kyber.Scalar {
 func (i *Int) Neg(a kyber.Scalar) kyber.Scalar {
 	ai := a.(*Int)
 	i.M = ai.M
+	i.ct = ai.ct
+	if i.ct != nil {
+		var zero big.Int
+		i.ct.sub(&i.V, &zero, &ai.V)
+		return i
+	}
 	if ai.V.Sign() > 0 {
 		i.V.Sub(i.M, &ai.V)
 	} else {
@@ -241,6 +305,11 @@ func (i *Int) Mul(a, b kyber.Scalar) kyber.Scalar {
 	ai := a.(*Int)
 	bi := b.(*Int)
 	i.M = ai.M
+	i.ct = ai.ct
+	if i.ct != nil {
+		i.ct.mul(&i.V, &ai.V, &bi.V)
+		return i
+	}
 	i.V.Mul(&ai.V, &bi.V).Mod(&i.V, i.M)
 	return i
 }
@@ -251,6 +320,12 @@ func (i *Int) Div(a, b kyber.Scalar) kyber.Scalar {
 	bi := b.(*Int)
 	var t big.Int
 	i.M = ai.M
+	i.ct = ai.ct
+	if i.ct != nil {
+		i.ct.inv(&t, &bi.V)
+		i.ct.mul(&i.V, &ai.V, &t)
+		return i
+	}
 	i.V.Mul(&ai.V, t.ModInverse(&bi.V, i.M))
 	i.V.Mod(&i.V, i.M)
 	return i
@@ -260,6 +335,11 @@ func (i *Int) Div(a, b kyber.Scalar) kyber.Scalar {
 func (i *Int) Inv(a kyber.Scalar) kyber.Scalar {
 	ai := a.(*Int)
 	i.M = ai.M
+	i.ct = ai.ct
+	if i.ct != nil {
+		i.ct.inv(&i.V, &ai.V)
+		return i
+	}
 	i.V.ModInverse(&a.(*Int).V, i.M)
 	return i
 }
@@ -269,6 +349,11 @@ func (i *Int) Inv(a kyber.Scalar) kyber.Scalar {
 func (i *Int) Exp(a kyber.Scalar, e *big.Int) kyber.Scalar {
 	ai := a.(*Int)
 	i.M = ai.M
+	i.ct = ai.ct
+	if i.ct != nil {
+		i.ct.exp(&i.V, &ai.V, e)
+		return i
+	}
 	// to protect against golang/go#22830
 	var tmp big.Int
 	tmp.Exp(&ai.V, e, i.M)
@@ -281,6 +366,7 @@ func (i *Int) Exp(a kyber.Scalar, e *big.Int) kyber.Scalar {
 func (i *Int) Jacobi(as kyber.Scalar) kyber.Scalar {
 	ai := as.(*Int)
 	i.M = ai.M
+	i.ct = ai.ct
 	i.V.SetInt64(int64(big.Jacobi(&ai.V, i.M)))
 	return i
 }
@@ -292,6 +378,7 @@ func (i *Int) Sqrt(as kyber.Scalar) bool {
 	ai := as.(*Int)
 	out := i.V.ModSqrt(&ai.V, ai.M)
 	i.M = ai.M
+	i.ct = ai.ct
 	return out != nil
 }
 
@@ -387,6 +474,10 @@ func (i *Int) SetBytes(a []byte) kyber.Scalar {
 	if i.BO == LittleEndian {
 		buff = reverse(nil, a)
 	}
+	if i.ct != nil {
+		i.ct.setBytes(&i.V, buff)
+		return i
+	}
 	i.V.SetBytes(buff).Mod(&i.V, i.M)
 	return i
 }
@@ -413,6 +504,62 @@ func (i *Int) LittleEndian(min, max int) []byte {
 	return buf
 }
 
+// BytesBE returns the big endian encoding of i, of MarshalSize bytes,
+// whatever the ByteOrder of i.
+func (i *Int) BytesBE() []byte {
+	b := make([]byte, i.MarshalSize())
+	v := i.V.Bytes()
+	copy(b[len(b)-len(v):], v)
+	return b
+}
+
+// BytesLE returns the little endian encoding of i, of MarshalSize bytes,
+// whatever the ByteOrder of i.
+func (i *Int) BytesLE() []byte {
+	return reverse(nil, i.BytesBE())
+}
+
+// SetBytesBE sets i to the big endian encoding b of MarshalSize bytes,
+// whatever the ByteOrder of i. It returns an error if b is not smaller than
+// the modulus.
+func (i *Int) SetBytesBE(b []byte) (kyber.Scalar, error) {
+	if len(b) != i.MarshalSize() {
+		return nil, errors.New("SetBytesBE: wrong size buffer")
+	}
+	var v big.Int
+	if v.SetBytes(b).Cmp(i.M) >= 0 {
+		return nil, errors.New("SetBytesBE: value out of range")
+	}
+	i.V.Set(&v)
+	return i, nil
+}
+
+// SetBytesLE sets i to the little endian encoding b of MarshalSize bytes,
+// whatever the ByteOrder of i. It returns an error if b is not smaller than
+// the modulus.
+func (i *Int) SetBytesLE(b []byte) (kyber.Scalar, error) {
+	return i.SetBytesBE(reverse(nil, b))
+}
+
+// SetBytesWide sets i to the big endian integer b reduced modulo M, whatever
+// the ByteOrder of i. b must be at most 64 bytes long, or twice MarshalSize if
+// it is larger.
+func (i *Int) SetBytesWide(b []byte) (kyber.Scalar, error) {
+	max := 2 * i.MarshalSize()
+	if max < 64 {
+		max = 64
+	}
+	if len(b) > max {
+		return nil, errors.New("SetBytesWide: wrong size buffer")
+	}
+	if i.ct != nil {
+		i.ct.setBytes(&i.V, b)
+		return i, nil
+	}
+	i.V.SetBytes(b).Mod(&i.V, i.M)
+	return i, nil
+}
+
 // reverse copies src into dst in byte-reversed order and returns dst,
 // such that src[0] goes into dst[len-1] and vice versa.
 // dst and src may be the same slice but otherwise must not overlap.
@@ -8,6 +8,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/util/random"
 )
 
 func TestIntEndianness(t *testing.T) {
@@ -98,3 +100,116 @@ func TestIntClone(t *testing.T) {
 		t.Error("Should not be equal")
 	}
 }
+
+func TestIntByteOrder(t *testing.T) {
+	modulo := big.NewInt(65521)
+	for _, bo := range []ByteOrder{BigEndian, LittleEndian} {
+		i := NewInt64(0x1234, modulo)
+		i.BO = bo
+		require.Equal(t, []byte{0x12, 0x34}, i.BytesBE())
+		require.Equal(t, []byte{0x34, 0x12}, i.BytesLE())
+		i2 := NewInt64(0, modulo)
+		i2.BO = bo
+		_, err := i2.SetBytesBE([]byte{0x12, 0x34})
+		require.NoError(t, err)
+		require.True(t, i.Equal(i2))
+		_, err = i2.SetBytesLE([]byte{0x34, 0x12})
+		require.NoError(t, err)
+		require.True(t, i.Equal(i2))
+
+		// the modulus is rejected, and so are buffers of the wrong size
+		_, err = i2.SetBytesBE([]byte{0xff, 0xf1})
+		require.Error(t, err)
+		_, err = i2.SetBytesLE([]byte{0xf1, 0xff})
+		require.Error(t, err)
+		_, err = i2.SetBytesBE([]byte{0x12})
+		require.Error(t, err)
+		require.True(t, i.Equal(i2))
+
+		_, err = i2.SetBytesWide([]byte{0x01, 0x00, 0x0f})
+		require.NoError(t, err)
+		require.Equal(t, int64(0x1000f%65521), i2.Int64())
+		_, err = i2.SetBytesWide(make([]byte, 65))
+		require.Error(t, err)
+	}
+}
+
+func TestIntWipe(t *testing.T) {
+	modulo, _ := new(big.Int).SetString("ffffffffffffffffffffffffffffff61", 16)
+	i := NewInt64(0, modulo)
+	i.V.SetString("123456789abcdef0123456789abcdef", 16)
+	words := i.V.Bits()
+	var w kyber.Wiper = i
+	w.Wipe()
+	require.True(t, i.Equal(NewInt64(0, modulo)))
+	for _, word := range words[:cap(words)] {
+		require.Zero(t, word)
+	}
+	// the modulus is kept
+	i.Add(i, NewInt64(2, modulo))
+	require.Equal(t, int64(2), i.Int64())
+}
+
+// TestConstantTimeInt cross-checks the constant-time arithmetic against the
+// one of math/big, for moduli of one word, of a curve order and of more bits
+// than words.
+func TestConstantTimeInt(t *testing.T) {
+	p256, _ := new(big.Int).SetString("ffffffff00000000ffffffffffffffffbce6faada7179e84f3b9cac2fc632551", 16)
+	p521 := new(big.Int).Lsh(one, 521)
+	p521.Sub(p521, one)
+	rand := random.New()
+	for _, m := range []*big.Int{big.NewInt(65521), p256, p521} {
+		ct := NewModulus(m)
+		for k := 0; k < 100; k++ {
+			a := NewConstantTimeInt64(0, ct).Pick(rand).(*Int)
+			b := NewConstantTimeInt64(0, ct).Pick(rand).(*Int)
+			if k == 0 {
+				b.Zero()
+			}
+			va, vb := NewInt(&a.V, m), NewInt(&b.V, m)
+			check := func(ct, v kyber.Scalar) {
+				require.Equal(t, 0, ct.(*Int).V.Cmp(&v.(*Int).V), "%v %v mod %v", a, b, m)
+			}
+			check(NewConstantTimeInt64(0, ct).Add(a, b), NewInt64(0, m).Add(va, vb))
+			check(NewConstantTimeInt64(0, ct).Sub(a, b), NewInt64(0, m).Sub(va, vb))
+			check(NewConstantTimeInt64(0, ct).Neg(b), NewInt64(0, m).Neg(vb))
+			check(NewConstantTimeInt64(0, ct).Mul(a, b), NewInt64(0, m).Mul(va, vb))
+			check(NewConstantTimeInt64(0, ct).Inv(a), NewInt64(0, m).Inv(va))
+			check(NewConstantTimeInt64(0, ct).Div(b, a), NewInt64(0, m).Div(vb, va))
+			e := big.NewInt(int64(k) - 50)
+			check(NewConstantTimeInt64(0, ct).Exp(a, e), NewInt64(0, m).Exp(va, e))
+
+			wide := random.Bits(uint(8*a.MarshalSize()+64), false, rand)
+			check(NewConstantTimeInt64(0, ct).SetBytes(wide), NewInt64(0, m).SetBytes(wide))
+			_, err := a.SetBytesWide(wide)
+			require.NoError(t, err)
+			check(a, NewInt64(0, m).SetBytes(wide))
+		}
+		// the results keep the constant-time arithmetic
+		a := NewConstantTimeInt64(3, ct)
+		require.Equal(t, ct, NewInt64(0, m).Add(a, a).(*Int).ct)
+		require.Equal(t, ct, a.Clone().(*Int).ct)
+		require.Zero(t, NewConstantTimeInt64(0, ct).Inv(NewConstantTimeInt64(0, ct)).(*Int).V.Sign())
+	}
+}
+
+func BenchmarkIntMul(b *testing.B) {
+	m, _ := new(big.Int).SetString("ffffffff00000000ffffffffffffffffbce6faada7179e84f3b9cac2fc632551", 16)
+	for _, s := range []struct {
+		name string
+		i    *Int
+	}{{"big", NewInt64(0, m)}, {"ct", NewConstantTimeInt64(0, NewModulus(m))}} {
+		x := s.i.Clone().Pick(random.New())
+		y := s.i.Clone().Pick(random.New())
+		b.Run(s.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				x.Mul(x, y)
+			}
+		})
+		b.Run(s.name+"/Inv", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				x.Inv(y)
+			}
+		})
+	}
+}
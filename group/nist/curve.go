@@ -4,6 +4,7 @@ import (
 	"crypto/cipher"
 	"crypto/elliptic"
 	"errors"
+	"hash"
 	"io"
 	"math/big"
 
@@ -218,6 +219,12 @@ type curve struct {
 	elliptic.Curve
 	curveOps
 	p *elliptic.CurveParams
+	// constant-time arithmetic of the scalars
+	order *mod.Modulus
+	// hash function and constant Z of the hash-to-curve suite, see
+	// HashToPoint
+	h2cHash func() hash.Hash
+	h2cZ    *big.Int
 }
 
 // Return the number of bytes in the encoding of a Scalar for this curve.
@@ -226,9 +233,10 @@ func (c *curve) ScalarLen() int { return (c.p.N.BitLen() + 7) / 8 }
 // Create a Scalar associated with this curve. The scalars created by
 // this package implement kyber.Scalar's SetBytes method, interpreting
 // the bytes as a big-endian integer, so as to be compatible with the
-// Go standard library's big.Int type.
+// Go standard library's big.Int type. Their arithmetic operations compute on
+// fixed-length words, see mod.Modulus for what runs in constant time.
 func (c *curve) Scalar() kyber.Scalar {
-	return mod.NewInt64(0, c.p.N)
+	return mod.NewConstantTimeInt64(0, c.order)
 }
 
 // Number of bytes required to store one coordinate on this curve
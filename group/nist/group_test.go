@@ -1,8 +1,16 @@
 package nist
 
 import (
+	"crypto/elliptic"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/util/h2c"
 	"go.dedis.ch/kyber/v3/util/test"
 )
 
@@ -24,6 +32,47 @@ func TestSetBytesBE(t *testing.T) {
 	}
 }
 
+// hashToPointVectors are the vectors of the suite P256_XMD:SHA-256_SSWU_RO_ of
+// Appendix J.1.1 of RFC 9380.
+var hashToPointVectors = []struct {
+	msg    string
+	px, py string
+}{
+	{"",
+		"2c15230b26dbc6fc9a37051158c95b79656e17a1a920b11394ca91c44247d3e4",
+		"8a7a74985cc5c776cdfe4b1f19884970453912e9d31528c060be9ab5c43e8415"},
+	{"abc",
+		"0bb8b87485551aa43ed54f009230450b492fead5f1cc91658775dac4a3388a0f",
+		"5c41b3d0731a27a7b14bc0bf0ccded2d8751f83493404c84a88e71ffd424212e"},
+	{"abcdef0123456789",
+		"65038ac8f2b1def042a5df0b33b1f4eca6bff7cb0f9c6c1526811864e544ed80",
+		"cad44d40a656e7aff4002a8de287abc8ae0482b5ae825822bb870d6df9b56ca3"},
+	{"q128_" + strings.Repeat("q", 128),
+		"4be61ee205094282ba8a2042bcb48d88dfbb609301c49aa8b078533dc65a0b5d",
+		"98f8df449a072c4721d241a3b1236d3caccba603f916ca680f4539d2bfb3c29e"},
+	{"a512_" + strings.Repeat("a", 512),
+		"457ae2981f70ca85d8e24c308b14db22f3e3862c5ea0f652ca38b5e49cd64bc5",
+		"ecb9f0eadc9aeed232dabc53235368c1394c78de05dd96893eefa62b0f4757dc"},
+}
+
+func TestP256HashToPoint(t *testing.T) {
+	var hasher kyber.PointHasher = testP256
+	dst := []byte("QUUX-V01-CS02-with-P256_XMD:SHA-256_SSWU_RO_")
+	for _, v := range hashToPointVectors {
+		P, err := hasher.HashToPoint(dst, []byte(v.msg))
+		require.NoError(t, err)
+		x, _ := new(big.Int).SetString(v.px, 16)
+		y, _ := new(big.Int).SetString(v.py, 16)
+		got, err := P.MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, hex.EncodeToString(elliptic.Marshal(elliptic.P256(), x, y)),
+			hex.EncodeToString(got), "msg %q", v.msg)
+	}
+
+	_, err := hasher.HashToPoint(nil, []byte("msg"))
+	require.True(t, errors.Is(err, h2c.ErrEmptyDST))
+}
+
 var benchP256 = test.NewGroupBench(testP256)
 
 func BenchmarkScalarAdd(b *testing.B)    { benchP256.ScalarAdd(b.N) }
@@ -0,0 +1,80 @@
+package nist
+
+import (
+	"math/big"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/util/h2c"
+)
+
+// HashToPoint hashes the message msg to a point of the curve with the domain
+// separation tag dst, following the hash-to-curve suite
+// P256_XMD:SHA-256_SSWU_RO_ of RFC 9380 for P-256: the message is hashed to
+// two field elements, which are mapped to the curve with the simplified
+// Shallue-van de Woestijne-Ulas method, and the result is the sum of both
+// points. The cofactor of the NIST curves is 1. Like the rest of this package,
+// it does not run in constant time. It implements kyber.PointHasher.
+func (c *curve) HashToPoint(dst, msg []byte) (kyber.Point, error) {
+	u, err := h2c.HashToField(c.h2cHash, dst, msg, c.p.P, 2)
+	if err != nil {
+		return nil, err
+	}
+	x0, y0 := c.mapToCurveSSWU(u[0])
+	x1, y1 := c.mapToCurveSSWU(u[1])
+	P := c.Point().(*curvePoint)
+	P.x, P.y = c.Add(x0, y0, x1, y1)
+	return P, nil
+}
+
+// mapToCurveSSWU returns the affine coordinates of the point of the field
+// element u, following the simplified SWU method of Section 6.6.2 of RFC 9380
+// for the curves y² = x³ - 3x + B.
+func (c *curve) mapToCurveSSWU(u *big.Int) (x, y *big.Int) {
+	P := c.p.P
+	A := big.NewInt(-3)
+	B := c.p.B
+	mod := func(v *big.Int) *big.Int { return v.Mod(v, P) }
+	g := func(x *big.Int) *big.Int {
+		gx := new(big.Int).Mul(x, x)
+		gx.Add(gx, A).Mul(gx, x).Add(gx, B)
+		return mod(gx)
+	}
+
+	// tv1 = inv0(Z² * u⁴ + Z * u²)
+	zu2 := new(big.Int).Mul(u, u)
+	mod(zu2.Mul(zu2, c.h2cZ))
+	tv1 := new(big.Int).Mul(zu2, zu2)
+	mod(tv1.Add(tv1, zu2))
+	// x1 = (-B / A) * (1 + tv1), or B / (Z * A) if tv1 = 0
+	x1 := new(big.Int)
+	if tv1.Sign() == 0 {
+		x1.Mul(c.h2cZ, A)
+		x1.ModInverse(mod(x1), P)
+		x1.Mul(x1, B)
+	} else {
+		tv1.ModInverse(tv1, P)
+		x1.ModInverse(mod(new(big.Int).Neg(A)), P)
+		x1.Mul(x1, B)
+		x1.Mul(x1, tv1.Add(tv1, big.NewInt(1)))
+	}
+	mod(x1)
+
+	x, gx := x1, g(x1)
+	if !isSquare(gx, P) {
+		// x2 = Z * u² * x1
+		x = mod(new(big.Int).Mul(zu2, x1))
+		gx = g(x)
+	}
+	y = c.sqrt(gx)
+	if u.Bit(0) != y.Bit(0) {
+		y.Sub(P, y)
+	}
+	return x, mod(y)
+}
+
+// isSquare returns true if x is a square modulo the prime P, including zero.
+func isSquare(x, P *big.Int) bool {
+	e := new(big.Int).Rsh(P, 1)
+	r := new(big.Int).Exp(x, e, P)
+	return r.Sign() == 0 || r.Cmp(big.NewInt(1)) == 0
+}
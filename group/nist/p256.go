@@ -2,7 +2,10 @@ package nist
 
 import (
 	"crypto/elliptic"
+	"crypto/sha256"
 	"math/big"
+
+	"go.dedis.ch/kyber/v3/group/mod"
 )
 
 // P256 implements the kyber.Group interface
@@ -71,6 +74,10 @@ func (curve *p256) sqrt(c *big.Int) *big.Int {
 func (curve *p256) Init() curve {
 	curve.curve.Curve = elliptic.P256()
 	curve.p = curve.Params()
+	curve.order = mod.NewModulus(curve.p.N)
 	curve.curveOps = curve
+	// suite P256_XMD:SHA-256_SSWU_RO_ of RFC 9380
+	curve.h2cHash = sha256.New
+	curve.h2cZ = big.NewInt(-10)
 	return curve.curve
 }
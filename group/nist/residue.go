@@ -48,12 +48,14 @@ func (p *residuePoint) Base() kyber.Point {
 
 func (p *residuePoint) Set(p2 kyber.Point) kyber.Point {
 	p.g = p2.(*residuePoint).g
-	p.Int = p2.(*residuePoint).Int
+	p.Int.Set(&p2.(*residuePoint).Int)
 	return p
 }
 
 func (p *residuePoint) Clone() kyber.Point {
-	return &residuePoint{g: p.g, Int: p.Int}
+	c := &residuePoint{g: p.g}
+	c.Int.Set(&p.Int)
+	return c
 }
 
 func (p *residuePoint) Valid() bool {
@@ -0,0 +1,246 @@
+package secp256k1
+
+import (
+	"encoding/binary"
+	"math/big"
+	"math/bits"
+)
+
+// fieldElement is an element of GF(p), p = 2^256 - 2^32 - 977, as four 64-bit
+// limbs in little endian order. The elements are always fully reduced, and
+// the arithmetic runs in constant time, except for the exponentiations
+// whose exponents are public constants.
+type fieldElement [4]uint64
+
+// fieldReduce is 2^256 mod p = 2^32 + 977.
+const fieldReduce = 0x1000003d1
+
+var fieldP = fieldElement{0xfffffffefffffc2f, 0xffffffffffffffff, 0xffffffffffffffff, 0xffffffffffffffff}
+
+// fieldPBig is p as a big.Int.
+var fieldPBig, _ = new(big.Int).SetString("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f", 16)
+
+// p - 2, the exponent of the inversion
+var fieldInvExp = new(big.Int).Sub(fieldPBig, big.NewInt(2))
+
+// (p + 1) / 4, the exponent of the square root as p = 3 mod 4
+var fieldSqrtExp = new(big.Int).Rsh(new(big.Int).Add(fieldPBig, big.NewInt(1)), 2)
+
+func (e *fieldElement) zero() *fieldElement {
+	*e = fieldElement{}
+	return e
+}
+
+func (e *fieldElement) one() *fieldElement {
+	*e = fieldElement{1}
+	return e
+}
+
+func (e *fieldElement) setInt(v uint64) *fieldElement {
+	*e = fieldElement{v}
+	return e
+}
+
+// reduceOnce subtracts p from the 256-bit value v, with the carry bit above
+// it, if the value is at least p.
+func (e *fieldElement) reduceOnce(v *fieldElement, carry uint64) *fieldElement {
+	var t fieldElement
+	var b uint64
+	t[0], b = bits.Sub64(v[0], fieldP[0], 0)
+	t[1], b = bits.Sub64(v[1], fieldP[1], b)
+	t[2], b = bits.Sub64(v[2], fieldP[2], b)
+	t[3], b = bits.Sub64(v[3], fieldP[3], b)
+	// keep v if v < p, i.e. if there is no carry and the subtraction borrowed
+	mask := -(b &^ carry)
+	for i := range e {
+		e[i] = v[i]&mask | t[i]&^mask
+	}
+	return e
+}
+
+func (e *fieldElement) add(a, b *fieldElement) *fieldElement {
+	var s fieldElement
+	var c uint64
+	s[0], c = bits.Add64(a[0], b[0], 0)
+	s[1], c = bits.Add64(a[1], b[1], c)
+	s[2], c = bits.Add64(a[2], b[2], c)
+	s[3], c = bits.Add64(a[3], b[3], c)
+	return e.reduceOnce(&s, c)
+}
+
+func (e *fieldElement) sub(a, b *fieldElement) *fieldElement {
+	var d fieldElement
+	var br uint64
+	d[0], br = bits.Sub64(a[0], b[0], 0)
+	d[1], br = bits.Sub64(a[1], b[1], br)
+	d[2], br = bits.Sub64(a[2], b[2], br)
+	d[3], br = bits.Sub64(a[3], b[3], br)
+	// add p back if the subtraction borrowed
+	mask := -br
+	var c uint64
+	e[0], c = bits.Add64(d[0], fieldP[0]&mask, 0)
+	e[1], c = bits.Add64(d[1], fieldP[1]&mask, c)
+	e[2], c = bits.Add64(d[2], fieldP[2]&mask, c)
+	e[3], _ = bits.Add64(d[3], fieldP[3]&mask, c)
+	return e
+}
+
+func (e *fieldElement) neg(a *fieldElement) *fieldElement {
+	var z fieldElement
+	return e.sub(&z, a)
+}
+
+func (e *fieldElement) mul(a, b *fieldElement) *fieldElement {
+	var t [8]uint64
+	for i := 0; i < 4; i++ {
+		var carry uint64
+		for j := 0; j < 4; j++ {
+			hi, lo := bits.Mul64(a[i], b[j])
+			var c uint64
+			lo, c = bits.Add64(lo, t[i+j], 0)
+			hi += c
+			lo, c = bits.Add64(lo, carry, 0)
+			hi += c
+			t[i+j] = lo
+			carry = hi
+		}
+		t[i+4] = carry
+	}
+	return e.reduceWide(&t)
+}
+
+func (e *fieldElement) square(a *fieldElement) *fieldElement {
+	return e.mul(a, a)
+}
+
+// reduceWide sets e to the 512-bit value t modulo p, folding the upper half
+// with 2^256 = 2^32 + 977 mod p.
+func (e *fieldElement) reduceWide(t *[8]uint64) *fieldElement {
+	var r fieldElement
+	var carry uint64
+	for i := 0; i < 4; i++ {
+		hi, lo := bits.Mul64(t[4+i], fieldReduce)
+		var c uint64
+		lo, c = bits.Add64(lo, t[i], 0)
+		hi += c
+		lo, c = bits.Add64(lo, carry, 0)
+		hi += c
+		r[i] = lo
+		carry = hi
+	}
+	// fold the carry, which is less than 2^34
+	hi, lo := bits.Mul64(carry, fieldReduce)
+	var c uint64
+	r[0], c = bits.Add64(r[0], lo, 0)
+	r[1], c = bits.Add64(r[1], hi, c)
+	r[2], c = bits.Add64(r[2], 0, c)
+	r[3], c = bits.Add64(r[3], 0, c)
+	// fold the last carry bit: the value then wrapped to a small one, so that
+	// adding 2^32 + 977 does not carry again
+	r[0], c = bits.Add64(r[0], c*fieldReduce, 0)
+	r[1], c = bits.Add64(r[1], 0, c)
+	r[2], c = bits.Add64(r[2], 0, c)
+	r[3], _ = bits.Add64(r[3], 0, c)
+	return e.reduceOnce(&r, 0)
+}
+
+// exp sets e to a^x for the public exponent x.
+func (e *fieldElement) exp(a *fieldElement, x *big.Int) *fieldElement {
+	var r fieldElement
+	r.one()
+	base := *a
+	for i := x.BitLen() - 1; i >= 0; i-- {
+		r.square(&r)
+		if x.Bit(i) == 1 {
+			r.mul(&r, &base)
+		}
+	}
+	*e = r
+	return e
+}
+
+// invert sets e to 1/a, or to zero if a is zero.
+func (e *fieldElement) invert(a *fieldElement) *fieldElement {
+	return e.exp(a, fieldInvExp)
+}
+
+// sqrt sets e to a square root of a and returns true if a is a square. It
+// leaves e unchanged otherwise.
+func (e *fieldElement) sqrt(a *fieldElement) bool {
+	var r, check fieldElement
+	r.exp(a, fieldSqrtExp)
+	if check.square(&r).equal(a) != 1 {
+		return false
+	}
+	*e = r
+	return true
+}
+
+// equal returns 1 if e == a and 0 otherwise, in constant time.
+func (e *fieldElement) equal(a *fieldElement) uint64 {
+	var d uint64
+	for i := range e {
+		d |= e[i] ^ a[i]
+	}
+	return isZero(d)
+}
+
+// isZero returns 1 if e is zero and 0 otherwise, in constant time.
+func (e *fieldElement) isZero() uint64 {
+	return isZero(e[0] | e[1] | e[2] | e[3])
+}
+
+// isOdd returns the parity of e.
+func (e *fieldElement) isOdd() uint64 {
+	return e[0] & 1
+}
+
+// selectFrom sets e to a if cond is 1 and to b if cond is 0, in constant time.
+func (e *fieldElement) selectFrom(a, b *fieldElement, cond uint64) *fieldElement {
+	mask := -cond
+	for i := range e {
+		e[i] = a[i]&mask | b[i]&^mask
+	}
+	return e
+}
+
+// bytes returns the 32-byte big endian encoding of e.
+func (e *fieldElement) bytes() []byte {
+	var b [32]byte
+	for i := range e {
+		binary.BigEndian.PutUint64(b[24-8*i:], e[i])
+	}
+	return b[:]
+}
+
+// setBytes sets e to the 32-byte big endian integer b and returns true if it
+// is smaller than p.
+func (e *fieldElement) setBytes(b []byte) bool {
+	var v fieldElement
+	for i := range v {
+		v[i] = binary.BigEndian.Uint64(b[24-8*i:])
+	}
+	var r fieldElement
+	r.reduceOnce(&v, 0)
+	*e = v
+	return r.equal(&v) == 1
+}
+
+// setBig sets e to x mod p.
+func (e *fieldElement) setBig(x *big.Int) *fieldElement {
+	var b [32]byte
+	v := new(big.Int).Mod(x, fieldPBig).Bytes()
+	copy(b[32-len(v):], v)
+	e.setBytes(b[:])
+	return e
+}
+
+// big returns e as a big.Int.
+func (e *fieldElement) big() *big.Int {
+	return new(big.Int).SetBytes(e.bytes())
+}
+
+// isZero returns 1 if x is zero and 0 otherwise, in constant time.
+func isZero(x uint64) uint64 {
+	return 1 ^ (x|-x)>>63
+}
@@ -0,0 +1,192 @@
+package secp256k1
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/sign/schnorr"
+	"go.dedis.ch/kyber/v3/util/random"
+	"go.dedis.ch/kyber/v3/util/test"
+)
+
+var testSuite = NewBlakeSHA256Secp256k1()
+
+func TestSecp256k1(t *testing.T) { test.SuiteTest(t, testSuite) }
+
+// multiples of the base point, from the SEC 2 parameters of the curve
+var baseMultiples = []struct {
+	k    int64
+	x, y string
+}{
+	{1,
+		"79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+		"483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8"},
+	{2,
+		"c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac09b95c709ee5",
+		"1ae168fea63dc339a3c58419466ceaeef7f632653266d0e1236431a950cfe52a"},
+	{3,
+		"f9308a019258c31049344f85f89d5229b531c845836f99b08601f113bce036f9",
+		"388f7b0f632de8140fe337e62a37f3566500a99934c2231b6cb9fd7584b8e672"},
+}
+
+func TestBaseMultiples(t *testing.T) {
+	for _, v := range baseMultiples {
+		P := testSuite.Point().Mul(testSuite.Scalar().SetInt64(v.k), nil).(*point)
+		x, y, inf := P.affine()
+		require.False(t, inf)
+		require.Equal(t, v.x, hex.EncodeToString(x.bytes()), "k = %d", v.k)
+		require.Equal(t, v.y, hex.EncodeToString(y.bytes()), "k = %d", v.k)
+	}
+
+	b, err := testSuite.Point().Base().MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", hex.EncodeToString(b))
+
+	// n·G is the identity, and (n-1)·G = -G
+	var P point
+	P.scalarMul(order.Bytes(), new(point).Base().(*point))
+	require.True(t, P.Equal(testSuite.Point().Null()))
+	k := testSuite.Scalar().SetInt64(-1)
+	require.True(t, testSuite.Point().Mul(k, nil).Equal(testSuite.Point().Neg(testSuite.Point().Base())))
+}
+
+// TestReference compares the scalar multiplication with a reference
+// implementation with big.Int in affine coordinates.
+func TestReference(t *testing.T) {
+	p := fieldPBig
+	add := func(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+		var l *big.Int
+		if x1.Cmp(x2) == 0 {
+			// 3x² / 2y
+			l = new(big.Int).Mul(x1, x1)
+			l.Mul(l, big.NewInt(3))
+			l.Mul(l, new(big.Int).ModInverse(new(big.Int).Lsh(y1, 1), p))
+		} else {
+			l = new(big.Int).Sub(y2, y1)
+			l.Mul(l, new(big.Int).ModInverse(new(big.Int).Sub(x2, x1), p))
+		}
+		l.Mod(l, p)
+		x3 := new(big.Int).Mul(l, l)
+		x3.Sub(x3, x1).Sub(x3, x2).Mod(x3, p)
+		y3 := new(big.Int).Sub(x1, x3)
+		y3.Mul(y3, l).Sub(y3, y1).Mod(y3, p)
+		return x3, y3
+	}
+	mul := func(k, x, y *big.Int) (*big.Int, *big.Int) {
+		var rx, ry *big.Int
+		for i := k.BitLen() - 1; i >= 0; i-- {
+			if rx != nil {
+				rx, ry = add(rx, ry, rx, ry)
+			}
+			if k.Bit(i) == 1 {
+				if rx == nil {
+					rx, ry = x, y
+				} else {
+					rx, ry = add(rx, ry, x, y)
+				}
+			}
+		}
+		return rx, ry
+	}
+
+	rand := random.New()
+	Q := testSuite.Point().Pick(rand).(*point)
+	qx, qy, _ := Q.affine()
+	for i := 0; i < 20; i++ {
+		k := testSuite.Scalar().Pick(rand)
+		kb, _ := k.MarshalBinary()
+		kk := new(big.Int).SetBytes(kb)
+
+		x, y, _ := testSuite.Point().Mul(k, nil).(*point).affine()
+		rx, ry := mul(kk, curveGx.big(), curveGy.big())
+		require.Equal(t, rx, x.big())
+		require.Equal(t, ry, y.big())
+
+		x, y, _ = testSuite.Point().Mul(k, Q).(*point).affine()
+		rx, ry = mul(kk, qx.big(), qy.big())
+		require.Equal(t, rx, x.big())
+		require.Equal(t, ry, y.big())
+	}
+}
+
+func TestField(t *testing.T) {
+	rand := random.New()
+	for i := 0; i < 100; i++ {
+		a := random.Int(fieldPBig, rand)
+		b := random.Int(fieldPBig, rand)
+		var fa, fb, r fieldElement
+		fa.setBig(a)
+		fb.setBig(b)
+
+		exp := new(big.Int).Mul(a, b)
+		require.Equal(t, exp.Mod(exp, fieldPBig), r.mul(&fa, &fb).big())
+		exp = new(big.Int).Add(a, b)
+		require.Equal(t, exp.Mod(exp, fieldPBig), r.add(&fa, &fb).big())
+		exp = new(big.Int).Sub(a, b)
+		require.Equal(t, exp.Mod(exp, fieldPBig), r.sub(&fa, &fb).big())
+		require.Equal(t, new(big.Int).ModInverse(a, fieldPBig), r.invert(&fa).big())
+	}
+
+	// the largest values exercise the carries of the reduction
+	var m, r fieldElement
+	m.setBig(new(big.Int).Sub(fieldPBig, big.NewInt(1)))
+	require.Equal(t, big.NewInt(1), r.mul(&m, &m).big())
+	require.Equal(t, new(big.Int).Sub(fieldPBig, big.NewInt(2)), r.add(&m, &m).big())
+}
+
+func TestDouble(t *testing.T) {
+	rand := random.New()
+	P := testSuite.Point().Pick(rand).(*point)
+	var d point
+	d.double(P)
+	require.True(t, d.Equal(testSuite.Point().Add(P, P)))
+	d.double(new(point).Null().(*point))
+	require.True(t, d.Equal(testSuite.Point().Null()))
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	P := testSuite.Point()
+	b, err := testSuite.Point().Pick(random.New()).MarshalBinary()
+	require.NoError(t, err)
+
+	// wrong length
+	require.Error(t, P.UnmarshalBinary(b[:pointLen-1]))
+	// wrong prefix
+	c := append([]byte{}, b...)
+	c[0] = 4
+	require.Error(t, P.UnmarshalBinary(c))
+	// x-coordinate not smaller than p
+	c = append([]byte{2}, fieldP.bytes()...)
+	require.Error(t, P.UnmarshalBinary(c))
+	// x = 5 is not on the curve: 5³ + 7 is not a square
+	c = make([]byte, pointLen)
+	c[0], c[pointLen-1] = 2, 5
+	require.Error(t, P.UnmarshalBinary(c))
+
+	// both signs
+	require.NoError(t, P.UnmarshalBinary(b))
+	b[0] ^= 1
+	Q := testSuite.Point()
+	require.NoError(t, Q.UnmarshalBinary(b))
+	require.True(t, Q.Equal(testSuite.Point().Neg(P)))
+}
+
+func TestSchnorr(t *testing.T) {
+	priv := testSuite.Scalar().Pick(random.New())
+	pub := testSuite.Point().Mul(priv, nil)
+	msg := []byte("secp256k1")
+	sig, err := schnorr.Sign(testSuite, priv, msg)
+	require.NoError(t, err)
+	require.NoError(t, schnorr.Verify(testSuite, pub, msg, sig))
+	require.Error(t, schnorr.Verify(testSuite, pub, []byte("other"), sig))
+}
+
+var benchSecp256k1 = test.NewGroupBench(testSuite)
+
+func BenchmarkPointAdd(b *testing.B)     { benchSecp256k1.PointAdd(b.N) }
+func BenchmarkPointMul(b *testing.B)     { benchSecp256k1.PointMul(b.N) }
+func BenchmarkPointBaseMul(b *testing.B) { benchSecp256k1.PointBaseMul(b.N) }
+func BenchmarkPointEncode(b *testing.B)  { benchSecp256k1.PointEncode(b.N) }
+func BenchmarkPointDecode(b *testing.B)  { benchSecp256k1.PointDecode(b.N) }
@@ -0,0 +1,372 @@
+package secp256k1
+
+import (
+	"crypto/cipher"
+	"encoding/hex"
+	"errors"
+	"io"
+	"math/big"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/group/internal/marshalling"
+	"go.dedis.ch/kyber/v3/group/mod"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+// coordLen is the length of the encoding of a coordinate, and pointLen the
+// length of the compressed SEC 1 encoding of a point.
+const (
+	coordLen = 32
+	pointLen = 1 + coordLen
+)
+
+// curveB3 is 3·b, with b = 7 the constant of the curve y² = x³ + b.
+var curveB3 = fieldElement{21}
+
+var curveGx, curveGy fieldElement
+
+func init() {
+	curveGx.setBig(fromHex("79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"))
+	curveGy.setBig(fromHex("483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8"))
+}
+
+// point is a point of the curve in homogeneous projective coordinates
+// (X : Y : Z), standing for the affine point (X/Z, Y/Z). The identity is
+// (0 : 1 : 0). The additions use the complete formulas of "Complete addition
+// formulas for prime order elliptic curves" by Renes, Costello and Batina,
+// which have no exceptional case, so that the scalar multiplication runs in
+// constant time.
+type point struct {
+	x, y, z fieldElement
+}
+
+func (P *point) String() string {
+	b, _ := P.MarshalBinary()
+	return hex.EncodeToString(b)
+}
+
+func (P *point) Equal(P2 kyber.Point) bool {
+	Q := P2.(*point)
+	// (X1 : Y1 : Z1) = (X2 : Y2 : Z2) iff X1·Z2 = X2·Z1 and Y1·Z2 = Y2·Z1
+	var a, b, c, d fieldElement
+	a.mul(&P.x, &Q.z)
+	b.mul(&Q.x, &P.z)
+	c.mul(&P.y, &Q.z)
+	d.mul(&Q.y, &P.z)
+	return a.equal(&b)&c.equal(&d) == 1
+}
+
+func (P *point) Null() kyber.Point {
+	P.x.zero()
+	P.y.one()
+	P.z.zero()
+	return P
+}
+
+func (P *point) Base() kyber.Point {
+	P.x = curveGx
+	P.y = curveGy
+	P.z.one()
+	return P
+}
+
+func (P *point) Set(P2 kyber.Point) kyber.Point {
+	*P = *P2.(*point)
+	return P
+}
+
+func (P *point) Clone() kyber.Point {
+	Q := *P
+	return &Q
+}
+
+// EmbedLen returns the number of bytes which can be embedded in a point:
+// the last byte of the x-coordinate holds the length of the data, and its
+// first byte is kept random.
+func (P *point) EmbedLen() int {
+	return (256 - 8 - 8) / 8
+}
+
+func (P *point) Pick(rand cipher.Stream) kyber.Point {
+	return P.Embed(nil, rand)
+}
+
+// Embed picks a point whose x-coordinate holds the data, followed by its
+// length, and random bytes before it.
+func (P *point) Embed(data []byte, rand cipher.Stream) kyber.Point {
+	dl := P.EmbedLen()
+	if dl > len(data) {
+		dl = len(data)
+	}
+	for {
+		b := random.Bits(256, false, rand)
+		if data != nil {
+			b[coordLen-1] = byte(dl)
+			copy(b[coordLen-dl-1:coordLen-1], data)
+		}
+		var x fieldElement
+		if !x.setBytes(b) {
+			continue
+		}
+		if y, ok := curveY(&x); ok {
+			// pick a random sign for y
+			s := random.Bits(1, false, rand)
+			var ny fieldElement
+			ny.neg(y)
+			y.selectFrom(&ny, y, uint64(s[0]&1))
+			P.x, P.y = x, *y
+			P.z.one()
+			return P
+		}
+	}
+}
+
+// Data extracts the data embedded in a point with Embed.
+func (P *point) Data() ([]byte, error) {
+	x, _, inf := P.affine()
+	if inf {
+		return nil, errors.New("secp256k1: no data in the identity")
+	}
+	b := x.bytes()
+	dl := int(b[coordLen-1])
+	if dl > P.EmbedLen() {
+		return nil, errors.New("secp256k1: invalid embedded data length")
+	}
+	return b[coordLen-dl-1 : coordLen-1], nil
+}
+
+// Add sets P to A + B with the complete addition formula of Algorithm 7 of
+// Renes, Costello and Batina, for a = 0.
+func (P *point) Add(A, B kyber.Point) kyber.Point {
+	P.add(A.(*point), B.(*point))
+	return P
+}
+
+func (P *point) Sub(A, B kyber.Point) kyber.Point {
+	var nb point
+	nb.neg(B.(*point))
+	P.add(A.(*point), &nb)
+	return P
+}
+
+func (P *point) Neg(A kyber.Point) kyber.Point {
+	P.neg(A.(*point))
+	return P
+}
+
+// Mul sets P to s·B, or to s·G with the base point G if B is nil, in
+// constant time.
+func (P *point) Mul(s kyber.Scalar, B kyber.Point) kyber.Point {
+	var b point
+	if B == nil {
+		b.Base()
+	} else {
+		b = *B.(*point)
+	}
+	var k [coordLen]byte
+	v := s.(*mod.Int).V.Bytes()
+	copy(k[coordLen-len(v):], v)
+	P.scalarMul(k[:], &b)
+	return P
+}
+
+func (P *point) MarshalSize() int {
+	return pointLen
+}
+
+// MarshalBinary returns the compressed SEC 1 encoding of the point: 0x02 or
+// 0x03 for an even or odd y-coordinate, followed by the x-coordinate in big
+// endian. The identity, which SEC 1 encodes as a single zero byte, is
+// encoded as pointLen zero bytes instead, so that all the encodings have the
+// same length.
+func (P *point) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, pointLen)
+	x, y, inf := P.affine()
+	if inf {
+		return buf, nil
+	}
+	buf[0] = 2 | byte(y.isOdd())
+	copy(buf[1:], x.bytes())
+	return buf, nil
+}
+
+// UnmarshalBinary decodes a point encoded with MarshalBinary, and checks that
+// it is on the curve.
+func (P *point) UnmarshalBinary(buf []byte) error {
+	if len(buf) != pointLen {
+		return errors.New("secp256k1: invalid point encoding length")
+	}
+	var c byte
+	for _, b := range buf {
+		c |= b
+	}
+	if c == 0 {
+		P.Null()
+		return nil
+	}
+	if buf[0] != 2 && buf[0] != 3 {
+		return errors.New("secp256k1: invalid point encoding prefix")
+	}
+	var x fieldElement
+	if !x.setBytes(buf[1:]) {
+		return errors.New("secp256k1: x-coordinate out of range")
+	}
+	y, ok := curveY(&x)
+	if !ok {
+		return errors.New("secp256k1: point not on the curve")
+	}
+	var ny fieldElement
+	ny.neg(y)
+	y.selectFrom(&ny, y, y.isOdd()^uint64(buf[0]&1))
+	P.x, P.y = x, *y
+	P.z.one()
+	return nil
+}
+
+func (P *point) MarshalTo(w io.Writer) (int, error) {
+	return marshalling.PointMarshalTo(P, w)
+}
+
+func (P *point) UnmarshalFrom(r io.Reader) (int, error) {
+	return marshalling.PointUnmarshalFrom(P, r)
+}
+
+// affine returns the affine coordinates of the point, and true if it is the
+// identity.
+func (P *point) affine() (x, y fieldElement, inf bool) {
+	var zinv fieldElement
+	zinv.invert(&P.z)
+	x.mul(&P.x, &zinv)
+	y.mul(&P.y, &zinv)
+	return x, y, P.z.isZero() == 1
+}
+
+func (P *point) neg(A *point) {
+	P.x = A.x
+	P.y.neg(&A.y)
+	P.z = A.z
+}
+
+// add sets P to A + B, following Algorithm 7 of Renes, Costello and Batina.
+// The formula is complete: it also doubles, and handles the identity.
+func (P *point) add(A, B *point) {
+	var t0, t1, t2, t3, t4, x3, y3, z3 fieldElement
+	t0.mul(&A.x, &B.x)
+	t1.mul(&A.y, &B.y)
+	t2.mul(&A.z, &B.z)
+	t3.add(&A.x, &A.y)
+	t4.add(&B.x, &B.y)
+	t3.mul(&t3, &t4)
+	t4.add(&t0, &t1)
+	t3.sub(&t3, &t4)
+	t4.add(&A.y, &A.z)
+	x3.add(&B.y, &B.z)
+	t4.mul(&t4, &x3)
+	x3.add(&t1, &t2)
+	t4.sub(&t4, &x3)
+	x3.add(&A.x, &A.z)
+	y3.add(&B.x, &B.z)
+	x3.mul(&x3, &y3)
+	y3.add(&t0, &t2)
+	y3.sub(&x3, &y3)
+	x3.add(&t0, &t0)
+	t0.add(&x3, &t0)
+	t2.mul(&curveB3, &t2)
+	z3.add(&t1, &t2)
+	t1.sub(&t1, &t2)
+	y3.mul(&curveB3, &y3)
+	x3.mul(&t4, &y3)
+	t2.mul(&t3, &t1)
+	x3.sub(&t2, &x3)
+	y3.mul(&y3, &t0)
+	t1.mul(&t1, &z3)
+	y3.add(&t1, &y3)
+	t0.mul(&t0, &t3)
+	z3.mul(&z3, &t4)
+	z3.add(&z3, &t0)
+	P.x, P.y, P.z = x3, y3, z3
+}
+
+// double sets P to 2·A, following Algorithm 9 of Renes, Costello and Batina.
+func (P *point) double(A *point) {
+	var t0, t1, t2, x3, y3, z3 fieldElement
+	t0.square(&A.y)
+	z3.add(&t0, &t0)
+	z3.add(&z3, &z3)
+	z3.add(&z3, &z3)
+	t1.mul(&A.y, &A.z)
+	t2.square(&A.z)
+	t2.mul(&curveB3, &t2)
+	x3.mul(&t2, &z3)
+	y3.add(&t0, &t2)
+	z3.mul(&t1, &z3)
+	t1.add(&t2, &t2)
+	t2.add(&t1, &t2)
+	t0.sub(&t0, &t2)
+	y3.mul(&t0, &y3)
+	y3.add(&x3, &y3)
+	t1.mul(&A.x, &A.y)
+	x3.mul(&t0, &t1)
+	x3.add(&x3, &x3)
+	P.x, P.y, P.z = x3, y3, z3
+}
+
+// selectFrom sets P to A if cond is 1 and to B if cond is 0, in constant
+// time.
+func (P *point) selectFrom(A, B *point, cond uint64) {
+	P.x.selectFrom(&A.x, &B.x, cond)
+	P.y.selectFrom(&A.y, &B.y, cond)
+	P.z.selectFrom(&A.z, &B.z, cond)
+}
+
+// scalarMul sets P to k·B for the 32-byte big endian scalar k, with a fixed
+// window of 4 bits and constant time lookups in the table of the multiples
+// of B.
+func (P *point) scalarMul(k []byte, B *point) {
+	var table [16]point
+	table[0].Null()
+	table[1] = *B
+	for i := 2; i < 16; i += 2 {
+		table[i].double(&table[i/2])
+		table[i+1].add(&table[i], B)
+	}
+
+	var r, t point
+	r.Null()
+	for i, b := range k {
+		for j, w := range [2]byte{b >> 4, b & 0xf} {
+			if i > 0 || j > 0 {
+				r.double(&r)
+				r.double(&r)
+				r.double(&r)
+				r.double(&r)
+			}
+			t.Null()
+			for v := range table {
+				t.selectFrom(&table[v], &t, isZero(uint64(v)^uint64(w)))
+			}
+			r.add(&r, &t)
+		}
+	}
+	*P = r
+}
+
+// curveY returns a square root of x³ + 7, and false if there is none.
+func curveY(x *fieldElement) (*fieldElement, bool) {
+	var y2, y fieldElement
+	y2.square(x)
+	y2.mul(&y2, x)
+	y2.add(&y2, new(fieldElement).setInt(7))
+	if !y.sqrt(&y2) {
+		return nil, false
+	}
+	return &y, true
+}
+
+func fromHex(s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		panic("secp256k1: invalid hex constant " + s)
+	}
+	return n
+}
@@ -0,0 +1,111 @@
+// Package secp256k1 implements the group of the secp256k1 elliptic curve of
+// SEC 2, the curve of the keys of Bitcoin and Ethereum, so that the schemes
+// of kyber, such as the schnorr and dss signatures, ecies or the DKG, can be
+// used with such keys.
+//
+// The points are encoded with the compressed SEC 1 encoding of 33 bytes, and
+// the scalars as 32-byte big endian integers modulo the order of the curve.
+// The point arithmetic, including the scalar multiplication, runs in
+// constant time, while the scalar arithmetic relies on mod.Int, whose
+// arithmetic operations compute on fixed-length words but which is not
+// entirely constant time, see mod.Modulus.
+package secp256k1
+
+import (
+	"crypto/cipher"
+	"crypto/sha256"
+	"hash"
+	"io"
+	"math/big"
+	"reflect"
+
+	"go.dedis.ch/fixbuf"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/group/internal/marshalling"
+	"go.dedis.ch/kyber/v3/group/mod"
+	"go.dedis.ch/kyber/v3/util/random"
+	"go.dedis.ch/kyber/v3/xof/blake2xb"
+)
+
+// order is the prime order n of the group generated by the base point.
+var order = fromHex("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141")
+
+var orderModulus = mod.NewModulus(order)
+
+// Curve is the secp256k1 group. It implements kyber.Group.
+type Curve struct{}
+
+func (c *Curve) String() string {
+	return "Secp256k1"
+}
+
+// ScalarLen returns the length of the encoding of a scalar.
+func (c *Curve) ScalarLen() int {
+	return coordLen
+}
+
+// Scalar returns a new scalar modulo the order of the group, which encodes as
+// a big endian integer. Its arithmetic operations compute on fixed-length
+// words, see mod.Modulus for what runs in constant time.
+func (c *Curve) Scalar() kyber.Scalar {
+	return mod.NewConstantTimeInt64(0, orderModulus)
+}
+
+// PointLen returns the length of the encoding of a point.
+func (c *Curve) PointLen() int {
+	return pointLen
+}
+
+// Point returns a new point, set to the identity.
+func (c *Curve) Point() kyber.Point {
+	P := new(point)
+	P.Null()
+	return P
+}
+
+// Order returns the order n of the group.
+func (c *Curve) Order() *big.Int {
+	return new(big.Int).Set(order)
+}
+
+// SuiteSecp256k1 is the suite of the secp256k1 group, with SHA-256 and
+// blake2xb.
+type SuiteSecp256k1 struct {
+	Curve
+}
+
+// Hash returns a new SHA-256 hash.
+func (s *SuiteSecp256k1) Hash() hash.Hash {
+	return sha256.New()
+}
+
+// XOF returns the blake2xb XOF seeded with the key.
+func (s *SuiteSecp256k1) XOF(key []byte) kyber.XOF {
+	return blake2xb.New(key)
+}
+
+// RandomStream returns a cipher.Stream that returns a key stream
+// from crypto/rand.
+func (s *SuiteSecp256k1) RandomStream() cipher.Stream {
+	return random.New()
+}
+
+func (s *SuiteSecp256k1) Read(r io.Reader, objs ...interface{}) error {
+	return fixbuf.Read(r, s, objs)
+}
+
+func (s *SuiteSecp256k1) Write(w io.Writer, objs ...interface{}) error {
+	return fixbuf.Write(w, objs)
+}
+
+// New implements the kyber.Encoding interface
+func (s *SuiteSecp256k1) New(t reflect.Type) interface{} {
+	return marshalling.GroupNew(s, t)
+}
+
+// NewBlakeSHA256Secp256k1 returns a cipher suite based on package
+// go.dedis.ch/kyber/v3/xof/blake2xb, SHA-256, and the secp256k1 elliptic
+// curve. It returns random streams from Go's crypto/rand.
+func NewBlakeSHA256Secp256k1() *SuiteSecp256k1 {
+	return new(SuiteSecp256k1)
+}
@@ -1,8 +1,13 @@
 package pairing
 
 import (
+	"hash"
+
 	"go.dedis.ch/kyber/v3"
 	"go.dedis.ch/kyber/v3/pairing/bn256"
+	"go.dedis.ch/kyber/v3/xof/keccak"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/sha3"
 )
 
 // SuiteBn256 is an adapter that implements the suites.Suite interface so that
@@ -24,6 +29,34 @@ func NewSuiteBn256() *SuiteBn256 {
 	}
 }
 
+// NewSuiteBn256WithHash makes a new BN256 suite that hashes with newHash,
+// including the messages signed with BLS, and uses newXOF as its XOF. A nil
+// function keeps the default one, see bn256.NewSuiteWithHash. Signatures made
+// with one hash do not verify with another.
+func NewSuiteBn256WithHash(newHash func() hash.Hash, newXOF func(seed []byte) kyber.XOF) *SuiteBn256 {
+	return &SuiteBn256{
+		Suite: bn256.NewSuiteWithHash(newHash, newXOF),
+	}
+}
+
+// NewSuiteBn256SHA3 makes a new BN256 suite that hashes with SHA3-256 and
+// uses the Keccak XOF instead of SHA-256 and BLAKE2Xb.
+func NewSuiteBn256SHA3() *SuiteBn256 {
+	return NewSuiteBn256WithHash(sha3.New256, keccak.New)
+}
+
+// NewSuiteBn256BLAKE2b makes a new BN256 suite that hashes with BLAKE2b-256
+// instead of SHA-256. Its XOF is the default BLAKE2Xb.
+func NewSuiteBn256BLAKE2b() *SuiteBn256 {
+	return NewSuiteBn256WithHash(newBlake2b256, nil)
+}
+
+func newBlake2b256() hash.Hash {
+	// blake2b.New256 only fails for keys longer than 64 bytes
+	h, _ := blake2b.New256(nil)
+	return h
+}
+
 // Point generates a point from the G2 group that can only be used
 // for public keys
 func (s *SuiteBn256) Point() kyber.Point {
@@ -49,3 +82,22 @@ func (s *SuiteBn256) ScalarLen() int {
 func (s *SuiteBn256) String() string {
 	return "bn256.adapter"
 }
+
+// GroupSuite is a view of one of the groups of a pairing suite as a suite of
+// its own, e.g. to run a DKG whose commitments must be points of that group.
+// The embedded pairing suite still gives access to the other groups and to
+// the pairing.
+type GroupSuite struct {
+	kyber.Group
+	Suite
+}
+
+// G1Suite returns the view of the group G1 of the pairing suite.
+func G1Suite(suite Suite) *GroupSuite {
+	return &GroupSuite{Group: suite.G1(), Suite: suite}
+}
+
+// G2Suite returns the view of the group G2 of the pairing suite.
+func G2Suite(suite Suite) *GroupSuite {
+	return &GroupSuite{Group: suite.G2(), Suite: suite}
+}
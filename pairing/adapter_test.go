@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing/bn256"
 	"go.dedis.ch/kyber/v3/util/key"
 )
 
@@ -26,3 +28,29 @@ func TestAdapter_SuiteBn256(t *testing.T) {
 
 	require.Equal(t, "bn256.adapter", suite.String())
 }
+
+func TestGroupSuite(t *testing.T) {
+	suite := bn256.NewSuite()
+	g1, g2 := G1Suite(suite), G2Suite(suite)
+	require.Equal(t, suite.G1().String(), g1.String())
+	require.Equal(t, suite.G2().String(), g2.String())
+	require.IsType(t, suite.G1().Point(), g1.Point())
+	require.IsType(t, suite.G2().Point(), g2.Point())
+
+	// both views still pair
+	pair := key.NewKeyPair(g1)
+	require.True(t, g2.Pair(pair.Public, g2.Point().Base()).Equal(
+		suite.Pair(suite.G1().Point().Base(), g2.Point().Mul(pair.Private, nil))))
+}
+
+func TestAdapter_SuiteBn256WithHash(t *testing.T) {
+	msg := []byte("hash onto the curve")
+	def := NewSuiteBn256()
+	for _, suite := range []*SuiteBn256{NewSuiteBn256SHA3(), NewSuiteBn256BLAKE2b()} {
+		require.Equal(t, 32, suite.Hash().Size())
+		require.NotEqual(t, def.Hash().Sum(msg), suite.Hash().Sum(msg))
+		h1 := suite.G1().Point().(interface{ Hash([]byte) kyber.Point }).Hash(msg)
+		h2 := def.G1().Point().(interface{ Hash([]byte) kyber.Point }).Hash(msg)
+		require.False(t, h1.Equal(h2))
+	}
+}
@@ -0,0 +1,249 @@
+//go:build !nobasetable
+// +build !nobasetable
+
+package bn256
+
+import (
+	"math/big"
+	"sync"
+)
+
+// This file implements the multiplication of the generators of G₁ and G₂ with
+// precomputed tables. The scalar k is recoded into 65 signed radix-16 digits
+// dᵢ ∈ [-8, 8] such that k = Σ dᵢ16ⁱ, so that kG = Σ dᵢ(16ⁱG) only takes 65
+// additions and no doubling once the multiples j16ⁱG, j = 1..8, are known.
+//
+// As in mulGLV, the multiples are looked up without branching nor accessing
+// memory depending on the digits, and added with the complete formulas of
+// Renes, Costello and Batina, so that the multiplication runs in constant
+// time.
+//
+// The tables take 49 KiB for G₁ and 98 KiB for G₂, and are computed on the
+// first multiplication of the corresponding generator, or by Precompute for
+// other points. Building with the nobasetable tag leaves them out, the
+// generators being then multiplied like any other point.
+
+// baseWindows is the number of signed digits of a scalar: the 64 digits of a
+// 256-bit scalar and a last one for the carry, which is 0 or 1.
+const baseWindows = 65
+
+// g1Table holds the multiples (j+1)16ⁱP of a point P of G₁.
+type g1Table struct {
+	// P as it was when the table was computed
+	base  curvePoint
+	mults [baseWindows][8]projPoint
+}
+
+// g2Table holds the multiples (j+1)16ⁱP of a point P of G₂.
+type g2Table struct {
+	base  twistPoint
+	mults [baseWindows][8]twistProjPoint
+}
+
+var (
+	g1BaseOnce  sync.Once
+	g1BaseTable *g1Table
+
+	g2BaseOnce  sync.Once
+	g2BaseTable *g2Table
+)
+
+// mulBase sets c to scalar·G₁ in constant time.
+func (c *curvePoint) mulBase(scalar *big.Int) {
+	g1BaseOnce.Do(func() { g1BaseTable = newG1Table(curveGen) })
+	c.mulTable(g1BaseTable, scalar)
+}
+
+// mulTable sets c to scalar·P in constant time, with the table of P.
+func (c *curvePoint) mulTable(table *g1Table, scalar *big.Int) {
+	k := scalarWords(scalar)
+	d := signedDigits(&k)
+
+	sum, t := &projPoint{}, &projPoint{}
+	sum.setInfinity()
+	for i := range d {
+		t.lookupSigned(&table.mults[i], d[i])
+		sum.add(sum, t)
+	}
+	sum.toJacobian(c)
+}
+
+// mulBase sets c to scalar·G₂ in constant time.
+func (c *twistPoint) mulBase(scalar *big.Int) {
+	g2BaseOnce.Do(func() { g2BaseTable = newG2Table(twistGen) })
+	c.mulTable(g2BaseTable, scalar)
+}
+
+// mulTable sets c to scalar·P in constant time, with the table of P.
+func (c *twistPoint) mulTable(table *g2Table, scalar *big.Int) {
+	k := scalarWords(scalar)
+	d := signedDigits(&k)
+
+	sum, t := &twistProjPoint{}, &twistProjPoint{}
+	sum.setInfinity()
+	for i := range d {
+		t.lookupSigned(&table.mults[i], d[i])
+		sum.add(sum, t)
+	}
+	sum.toJacobian(c)
+}
+
+// signedDigits returns the digits dᵢ ∈ [-8, 8] such that k = Σ dᵢ16ⁱ, in
+// constant time. k must be reduced mod Order.
+func signedDigits(k *[4]uint64) [baseWindows]int8 {
+	var d [baseWindows]int8
+	var carry int8
+	for i := 0; i < baseWindows-1; i++ {
+		d[i] = int8(window(k, i)) + carry
+		carry = (d[i] + 8) >> 4
+		d[i] -= carry << 4
+	}
+	d[baseWindows-1] = carry
+	return d
+}
+
+// digitMasks returns, for -8 ≤ d ≤ 8, the masks such that masks[j] is all ones
+// iff |d| = j+1, and the sign of d (1 if negative).
+func digitMasks(d int8) (masks [8]uint64, neg uint64) {
+	m := d >> 7
+	abs := uint64((d ^ m) - m)
+	for j := range masks {
+		x := uint64(j+1) ^ abs
+		masks[j] = ((x | -x) >> 63) - 1
+	}
+	return masks, uint64(m & 1)
+}
+
+func newG1Table(g *curvePoint) *g1Table {
+	table := &g1Table{base: *g}
+	p := &projPoint{}
+	p.fromJacobian(g)
+	for i := range table.mults {
+		// mults[i][j] = (j+1)16ⁱg
+		m := &table.mults[i]
+		m[0] = *p
+		for j := 1; j < 8; j++ {
+			m[j].add(&m[j-1], p)
+		}
+		p.add(&m[7], &m[7])
+	}
+	return table
+}
+
+// lookupSigned sets c to d·P where table[j] = (j+1)P, without leaking d.
+func (c *projPoint) lookupSigned(table *[8]projPoint, d int8) {
+	masks, neg := digitMasks(d)
+	c.setInfinity()
+	for j := range table {
+		cmovGFp(&c.x, &table[j].x, masks[j])
+		cmovGFp(&c.y, &table[j].y, masks[j])
+		cmovGFp(&c.z, &table[j].z, masks[j])
+	}
+	negY := &gfP{}
+	gfpNeg(negY, &c.y)
+	cmovGFp(&c.y, negY, -neg)
+}
+
+// twistProjPoint is a point of the twist in homogeneous projective coordinates,
+// like projPoint for the curve.
+type twistProjPoint struct {
+	x, y, z gfP2
+}
+
+// twistB3 is 3b for the twist y²=x³+b.
+var twistB3 = (&gfP2{}).Add(twistB, (&gfP2{}).Add(twistB, twistB))
+
+func newG2Table(g *twistPoint) *g2Table {
+	table := &g2Table{base: *g}
+	p := &twistProjPoint{}
+	p.fromJacobian(g)
+	for i := range table.mults {
+		m := &table.mults[i]
+		m[0] = *p
+		for j := 1; j < 8; j++ {
+			m[j].add(&m[j-1], p)
+		}
+		p.add(&m[7], &m[7])
+	}
+	return table
+}
+
+func (c *twistProjPoint) fromJacobian(a *twistPoint) {
+	z2 := (&gfP2{}).Square(&a.z)
+	c.x.Mul(&a.x, &a.z)
+	c.y.Set(&a.y)
+	c.z.Mul(z2, &a.z)
+}
+
+func (c *twistProjPoint) toJacobian(a *twistPoint) {
+	z2 := (&gfP2{}).Square(&c.z)
+	a.x.Mul(&c.x, &c.z)
+	a.y.Mul(&c.y, z2)
+	a.z.Set(&c.z)
+	a.t.Set(z2)
+}
+
+func (c *twistProjPoint) setInfinity() {
+	c.x.SetZero()
+	c.y.SetOne()
+	c.z.SetZero()
+}
+
+// add sets c to a + b with the same complete formula as projPoint.add. The
+// points may alias.
+func (c *twistProjPoint) add(a, b *twistProjPoint) {
+	t0, t1, t2, t3, t4 := &gfP2{}, &gfP2{}, &gfP2{}, &gfP2{}, &gfP2{}
+	x3, y3, z3 := &gfP2{}, &gfP2{}, &gfP2{}
+	t0.Mul(&a.x, &b.x)
+	t1.Mul(&a.y, &b.y)
+	t2.Mul(&a.z, &b.z)
+	t3.Add(&a.x, &a.y)
+	t4.Add(&b.x, &b.y)
+	t3.Mul(t3, t4)
+	t4.Add(t0, t1)
+	t3.Sub(t3, t4)
+	t4.Add(&a.y, &a.z)
+	x3.Add(&b.y, &b.z)
+	t4.Mul(t4, x3)
+	x3.Add(t1, t2)
+	t4.Sub(t4, x3)
+	x3.Add(&a.x, &a.z)
+	y3.Add(&b.x, &b.z)
+	x3.Mul(x3, y3)
+	y3.Add(t0, t2)
+	y3.Sub(x3, y3)
+	x3.Add(t0, t0)
+	t0.Add(x3, t0)
+	t2.Mul(t2, twistB3)
+	z3.Add(t1, t2)
+	t1.Sub(t1, t2)
+	y3.Mul(y3, twistB3)
+	x3.Mul(t4, y3)
+	t2.Mul(t3, t1)
+	x3.Sub(t2, x3)
+	y3.Mul(y3, t0)
+	t1.Mul(t1, z3)
+	y3.Add(t1, y3)
+	t0.Mul(t0, t3)
+	z3.Mul(z3, t4)
+	z3.Add(z3, t0)
+	c.x, c.y, c.z = *x3, *y3, *z3
+}
+
+// lookupSigned sets c to d·P where table[j] = (j+1)P, without leaking d.
+func (c *twistProjPoint) lookupSigned(table *[8]twistProjPoint, d int8) {
+	masks, neg := digitMasks(d)
+	c.setInfinity()
+	for j := range table {
+		cmovGFp2(&c.x, &table[j].x, masks[j])
+		cmovGFp2(&c.y, &table[j].y, masks[j])
+		cmovGFp2(&c.z, &table[j].z, masks[j])
+	}
+	negY := (&gfP2{}).Neg(&c.y)
+	cmovGFp2(&c.y, negY, -neg)
+}
+
+func cmovGFp2(c, a *gfP2, mask uint64) {
+	cmovGFp(&c.x, &a.x, mask)
+	cmovGFp(&c.y, &a.y, mask)
+}
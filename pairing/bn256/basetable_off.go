@@ -0,0 +1,41 @@
+//go:build nobasetable
+// +build nobasetable
+
+package bn256
+
+import "math/big"
+
+// Without the precomputed tables of basetable.go, the generators are
+// multiplied like any other point, and Precompute computes no table.
+
+type g1Table struct {
+	base curvePoint
+}
+
+type g2Table struct {
+	base twistPoint
+}
+
+func newG1Table(*curvePoint) *g1Table { return nil }
+
+func newG2Table(*twistPoint) *g2Table { return nil }
+
+// mulTable sets c to scalar·P in constant time.
+func (c *curvePoint) mulTable(table *g1Table, scalar *big.Int) {
+	c.mulGLV(&table.base, scalar)
+}
+
+// mulTable sets c to scalar·P.
+func (c *twistPoint) mulTable(table *g2Table, scalar *big.Int) {
+	c.Mul(&table.base, scalar)
+}
+
+// mulBase sets c to scalar·G₁ in constant time.
+func (c *curvePoint) mulBase(scalar *big.Int) {
+	c.mulGLV(curveGen, scalar)
+}
+
+// mulBase sets c to scalar·G₂.
+func (c *twistPoint) mulBase(scalar *big.Int) {
+	c.Mul(twistGen, scalar)
+}
@@ -0,0 +1,117 @@
+//go:build !nobasetable
+// +build !nobasetable
+
+package bn256
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+// baseTestScalars returns the scalars of the GLV tests followed by random
+// ones, 10000 in total unless testing.Short.
+func baseTestScalars() []*big.Int {
+	scalars := glvTestScalars()
+	n := 10000
+	if testing.Short() {
+		n = 500
+	}
+	for len(scalars) < n {
+		scalars = append(scalars, random.Int(Order, random.New()))
+	}
+	return scalars
+}
+
+func TestSignedDigits(t *testing.T) {
+	sixteen := big.NewInt(16)
+	for _, k := range glvTestScalars() {
+		w := scalarWords(k)
+		d := signedDigits(&w)
+		sum := new(big.Int)
+		for i := len(d) - 1; i >= 0; i-- {
+			require.True(t, d[i] >= -8 && d[i] <= 8)
+			sum.Mul(sum, sixteen).Add(sum, big.NewInt(int64(d[i])))
+		}
+		require.Zero(t, sum.Sub(sum, k).Mod(sum, Order).Sign(), "wrong digits of %v", k)
+	}
+}
+
+func TestG1MulBase(t *testing.T) {
+	exp, res := &curvePoint{}, &curvePoint{}
+	for _, k := range baseTestScalars() {
+		exp.mulGLV(curveGen, k)
+		res.mulBase(k)
+		requireCurveEqual(t, exp, res)
+	}
+}
+
+func TestG2MulBase(t *testing.T) {
+	exp, res := &twistPoint{}, &twistPoint{}
+	for _, k := range baseTestScalars() {
+		exp.Mul(twistGen, new(big.Int).Mod(k, Order))
+		res.mulBase(k)
+		exp.MakeAffine()
+		res.MakeAffine()
+		require.Equal(t, exp.x, res.x, "wrong multiple %v", k)
+		require.Equal(t, exp.y, res.y, "wrong multiple %v", k)
+	}
+}
+
+func TestPointMulBase(t *testing.T) {
+	suite := NewSuite()
+	for _, g := range []kyber.Group{suite.G1(), suite.G2()} {
+		for i := 0; i < 10; i++ {
+			s := g.Scalar().Pick(random.New())
+			require.True(t, g.Point().Mul(s, g.Point().Base()).Equal(g.Point().Mul(s, nil)))
+		}
+		require.True(t, g.Point().Null().Equal(g.Point().Mul(g.Scalar().Zero(), nil)))
+	}
+}
+
+func TestPointPrecompute(t *testing.T) {
+	suite := NewSuite()
+	for _, g := range []kyber.Group{suite.G1(), suite.G2()} {
+		A := g.Point().Pick(random.New())
+		P := A.Clone()
+		P.(kyber.Precomputer).Precompute()
+		for i := 0; i < 10; i++ {
+			s := g.Scalar().Pick(random.New())
+			require.True(t, g.Point().Mul(s, A).Equal(g.Point().Mul(s, P)))
+			require.True(t, g.Point().Mul(s, A).Equal(g.Point().Mul(s, P.Clone())))
+		}
+		require.True(t, g.Point().Null().Equal(g.Point().Mul(g.Scalar().Zero(), P)))
+
+		// the table is ignored once the point changes
+		s := g.Scalar().Pick(random.New())
+		P.Add(P, A)
+		A.Add(A, A)
+		require.True(t, g.Point().Mul(s, A).Equal(g.Point().Mul(s, P)))
+	}
+}
+
+func BenchmarkG1MulBaseTable(b *testing.B) {
+	benchmarkG1Mul(b, func(c, _ *curvePoint, k *big.Int) { c.mulBase(k) })
+}
+
+func benchmarkG2Mul(b *testing.B, mul func(c *twistPoint, k *big.Int)) {
+	k := random.Int(Order, random.New())
+	c := &twistPoint{}
+	// computes the tables before starting the timer
+	mul(c, k)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mul(c, k)
+	}
+}
+
+func BenchmarkG2MulDoubleAndAdd(b *testing.B) {
+	benchmarkG2Mul(b, func(c *twistPoint, k *big.Int) { c.Mul(twistGen, k) })
+}
+
+func BenchmarkG2MulBaseTable(b *testing.B) {
+	benchmarkG2Mul(b, func(c *twistPoint, k *big.Int) { c.mulBase(k) })
+}
@@ -0,0 +1,47 @@
+//go:build go1.18
+// +build go1.18
+
+package bn256
+
+import (
+	"bytes"
+	"testing"
+
+	"go.dedis.ch/kyber/v3"
+)
+
+// fuzzUnmarshalBinary checks that UnmarshalBinary never panics, and that the
+// points it accepts have the encoding they were decoded from.
+func fuzzUnmarshalBinary(f *testing.F, newPoint func() kyber.Point) {
+	base, _ := newPoint().Base().MarshalBinary()
+	null, _ := newPoint().Null().MarshalBinary()
+	f.Add(base)
+	f.Add(null)
+	f.Add(bytes.Repeat([]byte{0xff}, len(base)))
+
+	f.Fuzz(func(t *testing.T, buf []byte) {
+		p := newPoint()
+		if err := p.UnmarshalBinary(buf); err != nil {
+			return
+		}
+		out, err := p.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(out, buf) {
+			t.Fatalf("%x decoded to a point encoded as %x", buf, out)
+		}
+	})
+}
+
+func FuzzPointG1UnmarshalBinary(f *testing.F) {
+	fuzzUnmarshalBinary(f, func() kyber.Point { return newPointG1() })
+}
+
+func FuzzPointG2UnmarshalBinary(f *testing.F) {
+	fuzzUnmarshalBinary(f, func() kyber.Point { return newPointG2() })
+}
+
+func FuzzPointGTUnmarshalBinary(f *testing.F) {
+	fuzzUnmarshalBinary(f, func() kyber.Point { return newPointGT() })
+}
@@ -1,6 +1,7 @@
 package bn256
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
 )
@@ -31,7 +32,8 @@ func newGFpFromBigInt(bigInt *big.Int) *gfP {
 	}
 
 	out := new(gfP)
-	out.Unmarshal(leftPad32(bigInt.Bytes()))
+	// bigInt is reduced by the callers
+	_ = out.Unmarshal(leftPad32(bigInt.Bytes()))
 	montEncode(out, out)
 	return out
 }
@@ -75,13 +77,25 @@ func (e *gfP) Marshal(out []byte) {
 	}
 }
 
-func (e *gfP) Unmarshal(in []byte) {
+// Unmarshal decodes the big-endian integer of the first 32 bytes of in. It
+// returns an error if the integer is not reduced modulo p, in which case e is
+// left with the unreduced value.
+func (e *gfP) Unmarshal(in []byte) error {
 	for w := uint(0); w < 4; w++ {
 		e[3-w] = 0
 		for b := uint(0); b < 8; b++ {
 			e[3-w] += uint64(in[8*w+b]) << (56 - 8*b)
 		}
 	}
+	for i := 3; i >= 0; i-- {
+		if e[i] < p2[i] {
+			return nil
+		}
+		if e[i] > p2[i] {
+			return errors.New("bn256: coordinate exceeds modulus")
+		}
+	}
+	return errors.New("bn256: coordinate equals modulus")
 }
 
 func (e *gfP) BigInt() *big.Int {
@@ -1,5 +1,7 @@
 package bn256
 
+import "math/big"
+
 // For details of the algorithms used, see "Multiplication and Squaring on
 // Pairing-Friendly Fields, Devegili et al.
 // http://eprint.iacr.org/2006/471.pdf.
@@ -157,3 +159,58 @@ func (e *gfP2) Clone() gfP2 {
 
 	return n
 }
+
+// Exp sets e=a^power and then returns e. It runs in variable time.
+func (e *gfP2) Exp(a *gfP2, power *big.Int) *gfP2 {
+	sum := (&gfP2{}).SetOne()
+	t := &gfP2{}
+	for i := power.BitLen() - 1; i >= 0; i-- {
+		t.Square(sum)
+		if power.Bit(i) != 0 {
+			sum.Mul(t, a)
+		} else {
+			sum.Set(t)
+		}
+	}
+	return e.Set(sum)
+}
+
+// Equal returns true if e and a are the same element.
+func (e *gfP2) Equal(a *gfP2) bool {
+	return (&gfP2{}).Sub(e, a).IsZero()
+}
+
+var (
+	pMinus3Over4 = new(big.Int).Rsh(new(big.Int).Sub(p, big.NewInt(3)), 2)
+	pMinus1Over2 = new(big.Int).Rsh(new(big.Int).Sub(p, big.NewInt(1)), 1)
+)
+
+// Sqrt sets e to a square root of a and returns true if a is a square, and
+// returns false otherwise. It implements algorithm 9 of "Square root
+// computation over even extension fields", Adj and Rodríguez-Henríquez, which
+// applies as p = 3 mod 4. It runs in variable time.
+func (e *gfP2) Sqrt(a *gfP2) bool {
+	minusOne := (&gfP2{}).SetOne()
+	minusOne.Neg(minusOne)
+
+	a1 := (&gfP2{}).Exp(a, pMinus3Over4)
+	alpha := (&gfP2{}).Mul(a1, a)
+	x0 := (&gfP2{}).Set(alpha)
+	alpha.Mul(alpha, a1)
+
+	r := &gfP2{}
+	if alpha.Equal(minusOne) {
+		// multiply by i: (xi+y)i = yi-x
+		gfpNeg(&r.y, &x0.x)
+		r.x.Set(&x0.y)
+	} else {
+		b := (&gfP2{}).SetOne()
+		b.Add(b, alpha).Exp(b, pMinus1Over2)
+		r.Mul(b, x0)
+	}
+	if !(&gfP2{}).Square(r).Equal(a) {
+		return false
+	}
+	e.Set(r)
+	return true
+}
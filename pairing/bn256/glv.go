@@ -0,0 +1,350 @@
+package bn256
+
+import (
+	"encoding/binary"
+	"math/big"
+	"math/bits"
+)
+
+// This file implements the scalar multiplication of G₁ with the GLV method:
+// the curve has the efficient endomorphism φ(x, y) = (βx, y), where β is a
+// primitive cube root of unity mod p, which acts on G₁ as the multiplication
+// by λ, a cube root of unity mod Order. A scalar k is decomposed into
+// k = k₁ + k₂λ mod Order with k₁, k₂ of about 128 bits, so that kP = k₁P +
+// k₂φ(P) only takes half of the doublings.
+//
+// mulGLV is meant for secret scalars: it does not branch nor access memory
+// depending on the scalar, and uses the complete addition formulas of Renes,
+// Costello and Batina (https://eprint.iacr.org/2015/1060.pdf, algorithms 7 and
+// 9) so that no special case is handled separately. mulGLVVartime is faster
+// but must only be used with public scalars.
+
+// glvBeta is β = ξ^((2p²-2)/3), so that φ(P) = λP.
+var glvBeta = xiTo2PSquaredMinus2Over3
+
+// glvLambda is the cube root of unity λ mod Order matching glvBeta.
+var glvLambda = bigFromBase10("9971566668618268521530616648191882281418254099768607949373")
+
+// The short basis (a₁, b₁), (a₂, b₂) of the lattice of the (x, y) such that
+// x + yλ = 0 mod Order, with b₂ = a₁ and b₁ < 0, stored as little-endian
+// 64-bit words: a₁ = 13037178982157583875,
+// -b₁ = 254952053719217182009119236802174855688 and
+// a₂ = 254952053719217182022156415784332439563.
+var (
+	glvA1    = [4]uint64{0xb4ed5d35d8b10603}
+	glvNegB1 = [4]uint64{0xb31b1546df0d2a08, 0xbfcdfabe288a7c7a}
+	glvA2    = [4]uint64{0x6808727cb7be300b, 0xbfcdfabe288a7c7b}
+	glvB2    = glvA1
+)
+
+// glvG1 = round(2²⁵⁶b₂/Order) and glvG2 = round(-2²⁵⁶b₁/Order) approximate the
+// coefficients of the decomposition: c₁ = ⌊k·glvG1/2²⁵⁶⌋ and c₂ = ⌊k·glvG2/2²⁵⁶⌋.
+var (
+	glvG1 = [4]uint64{0x424dd4af740d1660, 0x1}
+	glvG2 = [4]uint64{0xae13553c7c4a4446, 0x55ae596cffd56e3d, 0x1}
+)
+
+// glvWindows is the number of 4-bit windows covering k₁ and k₂, which never
+// exceed 128 bits.
+const glvWindows = 33
+
+// glvDecompose returns k₁ and k₂ in absolute value together with their signs
+// (1 if negative) such that k = k₁ + k₂λ mod Order, in constant time.
+func glvDecompose(k *[4]uint64) (k1, k2 [4]uint64, neg1, neg2 uint64) {
+	c1 := mulHigh256(k, &glvG1)
+	c2 := mulHigh256(k, &glvG2)
+
+	// k₁ = k - c₁a₁ - c₂a₂ and k₂ = -c₁b₁ - c₂b₂, computed modulo 2²⁵⁶ as
+	// their absolute value is small
+	t1 := mulLow256(&c1, &glvA1)
+	t2 := mulLow256(&c2, &glvA2)
+	k1 = sub256(k, &t1)
+	k1 = sub256(&k1, &t2)
+	t1 = mulLow256(&c1, &glvNegB1)
+	t2 = mulLow256(&c2, &glvB2)
+	k2 = sub256(&t1, &t2)
+
+	neg1 = k1[3] >> 63
+	neg2 = k2[3] >> 63
+	k1 = condNeg256(&k1, neg1)
+	k2 = condNeg256(&k2, neg2)
+	return
+}
+
+func mulFull256(a, b *[4]uint64) [8]uint64 {
+	var r [8]uint64
+	for i := 0; i < 4; i++ {
+		var carry uint64
+		for j := 0; j < 4; j++ {
+			hi, lo := bits.Mul64(a[i], b[j])
+			var c uint64
+			lo, c = bits.Add64(lo, r[i+j], 0)
+			hi += c
+			lo, c = bits.Add64(lo, carry, 0)
+			hi += c
+			r[i+j] = lo
+			carry = hi
+		}
+		r[i+4] = carry
+	}
+	return r
+}
+
+func mulHigh256(a, b *[4]uint64) [4]uint64 {
+	r := mulFull256(a, b)
+	return [4]uint64{r[4], r[5], r[6], r[7]}
+}
+
+func mulLow256(a, b *[4]uint64) [4]uint64 {
+	r := mulFull256(a, b)
+	return [4]uint64{r[0], r[1], r[2], r[3]}
+}
+
+func sub256(a, b *[4]uint64) [4]uint64 {
+	var r [4]uint64
+	var borrow uint64
+	for i := range r {
+		r[i], borrow = bits.Sub64(a[i], b[i], borrow)
+	}
+	return r
+}
+
+// condNeg256 returns -a mod 2²⁵⁶ if neg is 1 and a if neg is 0.
+func condNeg256(a *[4]uint64, neg uint64) [4]uint64 {
+	mask := -neg
+	var r [4]uint64
+	carry := neg
+	for i := range r {
+		r[i], carry = bits.Add64(a[i]^mask, 0, carry)
+	}
+	return r
+}
+
+// scalarWords returns the scalar reduced mod Order as little-endian 64-bit
+// words. The scalars of the suite are already reduced and skip the variable
+// time reduction, and the words do not depend on the bit length of the
+// scalar.
+func scalarWords(scalar *big.Int) [4]uint64 {
+	k := scalar
+	if k.Sign() < 0 || k.Cmp(Order) >= 0 {
+		k = new(big.Int).Mod(k, Order)
+	}
+	var buf [32]byte
+	k.FillBytes(buf[:])
+	var w [4]uint64
+	for i := range w {
+		w[i] = binary.BigEndian.Uint64(buf[24-8*i:])
+	}
+	return w
+}
+
+// window returns the i-th 4-bit window of k.
+func window(k *[4]uint64, i int) uint64 {
+	return (k[i/16] >> (4 * uint(i%16))) & 0xf
+}
+
+// projPoint is a point of the curve in homogeneous projective coordinates
+// (X:Y:Z), where the affine point is (X/Z, Y/Z) and (0:1:0) is the point at
+// infinity.
+type projPoint struct {
+	x, y, z gfP
+}
+
+func (c *projPoint) fromJacobian(a *curvePoint) {
+	z2 := &gfP{}
+	gfpMul(z2, &a.z, &a.z)
+	gfpMul(&c.x, &a.x, &a.z)
+	c.y.Set(&a.y)
+	gfpMul(&c.z, z2, &a.z)
+}
+
+func (c *projPoint) toJacobian(a *curvePoint) {
+	z2 := &gfP{}
+	gfpMul(z2, &c.z, &c.z)
+	gfpMul(&a.x, &c.x, &c.z)
+	gfpMul(&a.y, &c.y, z2)
+	a.z.Set(&c.z)
+	a.t.Set(z2)
+}
+
+func (c *projPoint) setInfinity() {
+	c.x = gfP{0}
+	c.y = *newGFp(1)
+	c.z = gfP{0}
+}
+
+// mulB3 sets c to 3b·a = 9a.
+func mulB3(c, a *gfP) {
+	t := &gfP{}
+	gfpAdd(t, a, a)
+	gfpAdd(t, t, t)
+	gfpAdd(t, t, t)
+	gfpAdd(c, t, a)
+}
+
+// add sets c to a + b using the complete addition formula for a = 0. The
+// points may alias.
+func (c *projPoint) add(a, b *projPoint) {
+	t0, t1, t2, t3, t4 := &gfP{}, &gfP{}, &gfP{}, &gfP{}, &gfP{}
+	x3, y3, z3 := &gfP{}, &gfP{}, &gfP{}
+	gfpMul(t0, &a.x, &b.x)
+	gfpMul(t1, &a.y, &b.y)
+	gfpMul(t2, &a.z, &b.z)
+	gfpAdd(t3, &a.x, &a.y)
+	gfpAdd(t4, &b.x, &b.y)
+	gfpMul(t3, t3, t4)
+	gfpAdd(t4, t0, t1)
+	gfpSub(t3, t3, t4)
+	gfpAdd(t4, &a.y, &a.z)
+	gfpAdd(x3, &b.y, &b.z)
+	gfpMul(t4, t4, x3)
+	gfpAdd(x3, t1, t2)
+	gfpSub(t4, t4, x3)
+	gfpAdd(x3, &a.x, &a.z)
+	gfpAdd(y3, &b.x, &b.z)
+	gfpMul(x3, x3, y3)
+	gfpAdd(y3, t0, t2)
+	gfpSub(y3, x3, y3)
+	gfpAdd(x3, t0, t0)
+	gfpAdd(t0, x3, t0)
+	mulB3(t2, t2)
+	gfpAdd(z3, t1, t2)
+	gfpSub(t1, t1, t2)
+	mulB3(y3, y3)
+	gfpMul(x3, t4, y3)
+	gfpMul(t2, t3, t1)
+	gfpSub(x3, t2, x3)
+	gfpMul(y3, y3, t0)
+	gfpMul(t1, t1, z3)
+	gfpAdd(y3, t1, y3)
+	gfpMul(t0, t0, t3)
+	gfpMul(z3, z3, t4)
+	gfpAdd(z3, z3, t0)
+	c.x, c.y, c.z = *x3, *y3, *z3
+}
+
+// double sets c to 2a using the complete doubling formula for a = 0.
+func (c *projPoint) double(a *projPoint) {
+	t0, t1, t2 := &gfP{}, &gfP{}, &gfP{}
+	x3, y3, z3 := &gfP{}, &gfP{}, &gfP{}
+	gfpMul(t0, &a.y, &a.y)
+	gfpAdd(z3, t0, t0)
+	gfpAdd(z3, z3, z3)
+	gfpAdd(z3, z3, z3)
+	gfpMul(t1, &a.y, &a.z)
+	gfpMul(t2, &a.z, &a.z)
+	mulB3(t2, t2)
+	gfpMul(x3, t2, z3)
+	gfpAdd(y3, t0, t2)
+	gfpMul(z3, t1, z3)
+	gfpAdd(t1, t2, t2)
+	gfpAdd(t2, t1, t2)
+	gfpSub(t0, t0, t2)
+	gfpMul(y3, t0, y3)
+	gfpAdd(y3, x3, y3)
+	gfpMul(t1, &a.x, &a.y)
+	gfpMul(x3, t0, t1)
+	gfpAdd(x3, x3, x3)
+	c.x, c.y, c.z = *x3, *y3, *z3
+}
+
+// cmovGFp sets c to a if mask is all ones and leaves it unchanged if mask is
+// zero.
+func cmovGFp(c, a *gfP, mask uint64) {
+	for i := range c {
+		c[i] ^= mask & (c[i] ^ a[i])
+	}
+}
+
+// lookup sets c to table[idx] without leaking idx through the memory accesses.
+func (c *projPoint) lookup(table *[16]projPoint, idx uint64) {
+	c.setInfinity()
+	for j := range table {
+		// mask is all ones iff j == idx
+		d := uint64(j) ^ idx
+		mask := ((d | -d) >> 63) - 1
+		cmovGFp(&c.x, &table[j].x, mask)
+		cmovGFp(&c.y, &table[j].y, mask)
+		cmovGFp(&c.z, &table[j].z, mask)
+	}
+}
+
+// mulGLV sets c to scalar·a in constant time.
+func (c *curvePoint) mulGLV(a *curvePoint, scalar *big.Int) {
+	k := scalarWords(scalar)
+	k1, k2, neg1, neg2 := glvDecompose(&k)
+
+	// table1[j] = j·(±a) and table2[j] = φ(j·(±a)) = j·λ(±a)
+	var base, table1, table2 [16]projPoint
+	base[0].setInfinity()
+	base[1].fromJacobian(a)
+	for j := 2; j < 16; j++ {
+		base[j].add(&base[j-1], &base[1])
+	}
+	negY := &gfP{}
+	for j := range base {
+		gfpNeg(negY, &base[j].y)
+		table1[j] = base[j]
+		cmovGFp(&table1[j].y, negY, -neg1)
+		table2[j] = base[j]
+		gfpMul(&table2[j].x, &table2[j].x, glvBeta)
+		cmovGFp(&table2[j].y, negY, -neg2)
+	}
+
+	sum, t := &projPoint{}, &projPoint{}
+	sum.setInfinity()
+	for i := glvWindows - 1; i >= 0; i-- {
+		sum.double(sum)
+		sum.double(sum)
+		sum.double(sum)
+		sum.double(sum)
+		t.lookup(&table1, window(&k1, i))
+		sum.add(sum, t)
+		t.lookup(&table2, window(&k2, i))
+		sum.add(sum, t)
+	}
+	sum.toJacobian(c)
+}
+
+// mulGLVVartime sets c to scalar·a. Its running time depends on the scalar.
+func (c *curvePoint) mulGLVVartime(a *curvePoint, scalar *big.Int) {
+	k := scalarWords(scalar)
+	k1, k2, neg1, neg2 := glvDecompose(&k)
+
+	var table1, table2 [16]curvePoint
+	table1[0].SetInfinity()
+	table1[1].Set(a)
+	if neg1 == 1 {
+		table1[1].Neg(a)
+	}
+	for j := 2; j < 16; j++ {
+		table1[j].Add(&table1[j-1], &table1[1])
+	}
+	for j := range table1 {
+		table2[j].Set(&table1[j])
+		gfpMul(&table2[j].x, &table2[j].x, glvBeta)
+		if neg1 != neg2 {
+			gfpNeg(&table2[j].y, &table2[j].y)
+		}
+	}
+
+	sum, t := &curvePoint{}, &curvePoint{}
+	sum.SetInfinity()
+	for i := glvWindows - 1; i >= 0; i-- {
+		if !sum.IsInfinity() {
+			for d := 0; d < 4; d++ {
+				t.Double(sum)
+				sum.Set(t)
+			}
+		}
+		if w := window(&k1, i); w != 0 {
+			t.Add(sum, &table1[w])
+			sum.Set(t)
+		}
+		if w := window(&k2, i); w != 0 {
+			t.Add(sum, &table2[w])
+			sum.Set(t)
+		}
+	}
+	c.Set(sum)
+}
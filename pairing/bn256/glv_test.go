@@ -0,0 +1,138 @@
+package bn256
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/group/mod"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+func glvTestScalars() []*big.Int {
+	scalars := []*big.Int{
+		big.NewInt(0),
+		big.NewInt(1),
+		big.NewInt(2),
+		big.NewInt(15),
+		big.NewInt(16),
+		new(big.Int).Sub(Order, big.NewInt(1)),
+		new(big.Int).Sub(Order, big.NewInt(2)),
+		new(big.Int).Rsh(Order, 1),
+		new(big.Int).Set(glvLambda),
+		new(big.Int).Lsh(big.NewInt(1), 128),
+		new(big.Int).Lsh(big.NewInt(1), 255),
+		// scalars out of range are reduced
+		new(big.Int).Set(Order),
+		new(big.Int).Add(Order, big.NewInt(5)),
+		big.NewInt(-3),
+	}
+	for i := 0; i < 100; i++ {
+		scalars = append(scalars, random.Int(Order, random.New()))
+	}
+	return scalars
+}
+
+func TestGLVDecompose(t *testing.T) {
+	for _, k := range glvTestScalars() {
+		w := scalarWords(k)
+		k1, k2, neg1, neg2 := glvDecompose(&w)
+		require.Zero(t, k1[2]|k1[3]|k2[2]|k2[3])
+
+		b1 := new(big.Int).SetUint64(k1[1])
+		b1.Lsh(b1, 64).Add(b1, new(big.Int).SetUint64(k1[0]))
+		if neg1 == 1 {
+			b1.Neg(b1)
+		}
+		b2 := new(big.Int).SetUint64(k2[1])
+		b2.Lsh(b2, 64).Add(b2, new(big.Int).SetUint64(k2[0]))
+		if neg2 == 1 {
+			b2.Neg(b2)
+		}
+		b2.Mul(b2, glvLambda).Add(b2, b1).Sub(b2, k).Mod(b2, Order)
+		require.Zero(t, b2.Sign(), "wrong decomposition of %v", k)
+	}
+}
+
+func TestGLVEndomorphism(t *testing.T) {
+	phi := curveGen.Clone()
+	gfpMul(&phi.x, &phi.x, glvBeta)
+	lambdaG := &curvePoint{}
+	lambdaG.Mul(curveGen, glvLambda)
+	requireCurveEqual(t, phi, lambdaG)
+}
+
+func TestGLVMul(t *testing.T) {
+	points := []*curvePoint{curveGen, {}}
+	points[1].Mul(curveGen, random.Int(Order, random.New()))
+	for _, P := range points {
+		for _, k := range glvTestScalars() {
+			reduced := new(big.Int).Mod(k, Order)
+			exp := &curvePoint{}
+			exp.Mul(P, reduced)
+
+			ct := &curvePoint{}
+			ct.mulGLV(P, k)
+			requireCurveEqual(t, exp, ct)
+			require.True(t, ct.IsOnCurve())
+
+			vt := &curvePoint{}
+			vt.mulGLVVartime(P, k)
+			requireCurveEqual(t, exp, vt)
+		}
+	}
+
+	// infinity times anything is infinity
+	inf := &curvePoint{}
+	inf.SetInfinity()
+	res := &curvePoint{}
+	res.mulGLV(inf, big.NewInt(12345))
+	require.True(t, res.IsInfinity())
+	res.mulGLVVartime(inf, big.NewInt(12345))
+	require.True(t, res.IsInfinity())
+
+	// in-place multiplication
+	P := curveGen.Clone()
+	P.mulGLV(P, big.NewInt(7))
+	exp := &curvePoint{}
+	exp.Mul(curveGen, big.NewInt(7))
+	requireCurveEqual(t, exp, P)
+}
+
+func TestPointG1VarTime(t *testing.T) {
+	s := mod.NewInt(random.Int(Order, random.New()), Order)
+	p1 := newPointG1().Mul(s, nil)
+	p2 := newPointG1()
+	p2.AllowVarTime(true)
+	p2.Mul(s, nil)
+	require.True(t, p1.Equal(p2))
+}
+
+func requireCurveEqual(t *testing.T, a, b *curvePoint) {
+	a, b = a.Clone(), b.Clone()
+	a.MakeAffine()
+	b.MakeAffine()
+	require.Equal(t, a.x, b.x)
+	require.Equal(t, a.y, b.y)
+}
+
+func benchmarkG1Mul(b *testing.B, mul func(c, a *curvePoint, k *big.Int)) {
+	k := random.Int(Order, random.New())
+	c := &curvePoint{}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mul(c, curveGen, k)
+	}
+}
+
+func BenchmarkG1MulDoubleAndAdd(b *testing.B) {
+	benchmarkG1Mul(b, func(c, a *curvePoint, k *big.Int) { c.Mul(a, k) })
+}
+
+func BenchmarkG1MulGLV(b *testing.B) {
+	benchmarkG1Mul(b, func(c, a *curvePoint, k *big.Int) { c.mulGLV(a, k) })
+}
+
+func BenchmarkG1MulGLVVartime(b *testing.B) {
+	benchmarkG1Mul(b, func(c, a *curvePoint, k *big.Int) { c.mulGLVVartime(a, k) })
+}
@@ -21,7 +21,9 @@ func (g *groupG1) PointLen() int {
 }
 
 func (g *groupG1) Point() kyber.Point {
-	return newPointG1()
+	p := newPointG1()
+	p.newHash = g.commonSuite.hashFunc()
+	return p
 }
 
 type groupG2 struct {
@@ -38,7 +40,9 @@ func (g *groupG2) PointLen() int {
 }
 
 func (g *groupG2) Point() kyber.Point {
-	return newPointG2()
+	p := newPointG2()
+	p.newHash = g.commonSuite.hashFunc()
+	return p
 }
 
 type groupGT struct {
@@ -58,6 +62,9 @@ func (g *groupGT) Point() kyber.Point {
 	return newPointGT()
 }
 
+// orderModulus is the constant-time arithmetic of the scalars.
+var orderModulus = mod.NewModulus(Order)
+
 // common functionalities across G1, G2, and GT
 type common struct{}
 
@@ -65,8 +72,11 @@ func (c *common) ScalarLen() int {
 	return mod.NewInt64(0, Order).MarshalSize()
 }
 
+// Scalar returns a new scalar modulo Order, whose arithmetic operations
+// compute on fixed-length words, see mod.Modulus for what runs in constant
+// time.
 func (c *common) Scalar() kyber.Scalar {
-	return mod.NewInt64(0, Order)
+	return mod.NewConstantTimeInt64(0, orderModulus)
 }
 
 func (c *common) PrimeOrder() bool {
@@ -74,5 +84,5 @@ func (c *common) PrimeOrder() bool {
 }
 
 func (c *common) NewKey(rand cipher.Stream) kyber.Scalar {
-	return mod.NewInt64(0, Order).Pick(rand)
+	return mod.NewConstantTimeInt64(0, orderModulus).Pick(rand)
 }
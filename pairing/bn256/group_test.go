@@ -0,0 +1,13 @@
+package bn256
+
+import (
+	"testing"
+
+	"go.dedis.ch/kyber/v3/util/test"
+)
+
+func TestSuiteG1(t *testing.T) { test.SuiteTest(t, NewSuiteG1()) }
+func TestSuiteG2(t *testing.T) { test.SuiteTest(t, NewSuiteG2()) }
+
+func BenchmarkGroupG1(b *testing.B) { test.BenchGroup(b, NewSuiteG1()) }
+func BenchmarkGroupG2(b *testing.B) { test.BenchGroup(b, NewSuiteG2()) }
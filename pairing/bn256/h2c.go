@@ -0,0 +1,242 @@
+package bn256
+
+import (
+	"crypto/sha256"
+	"io"
+	"math/big"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/util/h2c"
+)
+
+// RFC 9380 defines no hash-to-curve suite for this curve, so that G1 and G2
+// follow the generic construction of the RFC with the Shallue-van de
+// Woestijne (SvdW) map of its Section 6.6.1, which applies to any curve
+// y² = x³ + B, with the suite identifiers BN256G1_XMD:SHA-256_SVDW_RO_ and
+// BN256G2_XMD:SHA-256_SVDW_RO_. The constant Z of the map is 1 for both
+// curves: it is the one find_z_svdw of Appendix H.1 of RFC 9380 returns.
+// Unlike the other hashes of the package, they always use SHA-256, whatever
+// the hash function of the suite.
+
+// svdwG1 holds the constants of the SvdW map to the curve y² = x³ + 3 of G1
+// over GF(p), see mapToCurveG1.
+var svdwG1 = func() (c struct{ z, c1, c2, c3, c4 *big.Int }) {
+	mod := func(x *big.Int) *big.Int { return x.Mod(x, p) }
+	inv := func(x *big.Int) *big.Int { return new(big.Int).ModInverse(x, p) }
+	c.z = big.NewInt(1)
+	// c1 = g(Z)
+	c.c1 = mod(new(big.Int).Add(new(big.Int).Exp(c.z, big.NewInt(3), p), curveB.BigInt()))
+	// c2 = -Z / 2
+	c.c2 = mod(new(big.Int).Mul(new(big.Int).Neg(c.z), inv(big.NewInt(2))))
+	// c3 = sqrt(-g(Z) * 3 * Z²), with sgn0(c3) = 0
+	t := mod(new(big.Int).Mul(new(big.Int).Neg(c.c1), big.NewInt(3)))
+	c.c3 = sqrtG1(mod(t.Mul(t, new(big.Int).Mul(c.z, c.z))))
+	if c.c3.Bit(0) == 1 {
+		c.c3.Sub(p, c.c3)
+	}
+	// c4 = -4 * g(Z) / (3 * Z²)
+	t = mod(new(big.Int).Mul(big.NewInt(-4), c.c1))
+	c.c4 = mod(t.Mul(t, inv(mod(new(big.Int).Mul(big.NewInt(3), new(big.Int).Mul(c.z, c.z))))))
+	return c
+}()
+
+// HashToPoint hashes the message msg to a point of G1 with the domain
+// separation tag dst, following the suite BN256G1_XMD:SHA-256_SVDW_RO_: the
+// message is hashed to two field elements with hash_to_field of RFC 9380,
+// which are mapped to the curve with the SvdW map, and the result is the sum
+// of both points. The cofactor of G1 is 1. It does not run in constant time.
+// It implements kyber.PointHasher.
+func (g *groupG1) HashToPoint(dst, msg []byte) (kyber.Point, error) {
+	u, err := h2c.HashToField(sha256.New, dst, msg, p, 2)
+	if err != nil {
+		return nil, err
+	}
+	return g.fieldToPoint(u), nil
+}
+
+// HashReaderToPoint works like HashToPoint on the message read from r until
+// EOF, without holding it in memory. It returns the error of r, if any.
+func (g *groupG1) HashReaderToPoint(dst []byte, r io.Reader) (kyber.Point, error) {
+	u, err := h2c.HashToFieldReader(sha256.New, dst, r, p, 2)
+	if err != nil {
+		return nil, err
+	}
+	return g.fieldToPoint(u), nil
+}
+
+// fieldToPoint returns the sum of the points of G1 of the field elements
+// u[0] and u[1].
+func (g *groupG1) fieldToPoint(u []*big.Int) kyber.Point {
+	P := g.Point().(*pointG1)
+	P.g.Add(mapToCurveG1(u[0]), mapToCurveG1(u[1]))
+	return P
+}
+
+// mapToCurveG1 returns the point of G1 of the field element u, following
+// map_to_curve_svdw of Appendix F.1 of RFC 9380.
+func mapToCurveG1(u *big.Int) *curvePoint {
+	c := svdwG1
+	mod := func(x *big.Int) *big.Int { return x.Mod(x, p) }
+	g := func(x *big.Int) *big.Int {
+		gx := new(big.Int).Exp(x, big.NewInt(3), p)
+		return mod(gx.Add(gx, curveB.BigInt()))
+	}
+
+	tv1 := mod(new(big.Int).Mul(u, u))
+	mod(tv1.Mul(tv1, c.c1))
+	tv2 := mod(new(big.Int).Add(big.NewInt(1), tv1))
+	tv1 = mod(tv1.Sub(big.NewInt(1), tv1))
+	tv3 := mod(new(big.Int).Mul(tv1, tv2))
+	if tv3.Sign() != 0 {
+		tv3.ModInverse(tv3, p)
+	}
+	tv4 := mod(new(big.Int).Mul(u, tv1))
+	mod(tv4.Mul(tv4, tv3))
+	mod(tv4.Mul(tv4, c.c3))
+
+	x := mod(new(big.Int).Sub(c.c2, tv4))
+	gx := g(x)
+	if !isSquareG1(gx) {
+		x = mod(new(big.Int).Add(c.c2, tv4))
+		gx = g(x)
+		if !isSquareG1(gx) {
+			x = mod(new(big.Int).Mul(tv2, tv2))
+			mod(x.Mul(x, tv3))
+			mod(x.Mul(x, x))
+			mod(x.Mul(x, c.c4))
+			x = mod(x.Add(x, c.z))
+			gx = g(x)
+		}
+	}
+	y := sqrtG1(gx)
+	if u.Bit(0) != y.Bit(0) {
+		y = mod(y.Sub(p, y))
+	}
+
+	X, Y := newGFpFromBigInt(x), newGFpFromBigInt(y)
+	return &curvePoint{*X, *Y, *newGFp(1), *newGFp(1)}
+}
+
+// isSquareG1 returns true if x is a square of GF(p), including zero.
+func isSquareG1(x *big.Int) bool {
+	return big.Jacobi(x, p) >= 0
+}
+
+// sqrtG1 returns a square root of the square x of GF(p), as p = 3 mod 4.
+func sqrtG1(x *big.Int) *big.Int {
+	e := new(big.Int).Add(p, big.NewInt(1))
+	return new(big.Int).Exp(x, e.Rsh(e, 2), p)
+}
+
+// svdwG2 holds the constants of the SvdW map to the twist curve y² = x³ + 3/ξ
+// of G2 over GF(p²), see mapToCurveG2.
+var svdwG2 = func() (c struct{ z, c1, c2, c3, c4 *gfP2 }) {
+	three := &gfP2{y: *newGFp(3)}
+	c.z = (&gfP2{}).SetOne()
+	// c1 = g(Z)
+	c.c1 = (&gfP2{}).Square(c.z)
+	c.c1.Mul(c.c1, c.z).Add(c.c1, twistB)
+	// c2 = -Z / 2
+	c.c2 = (&gfP2{}).Invert(&gfP2{y: *newGFp(2)})
+	c.c2.Mul(c.c2, c.z).Neg(c.c2)
+	// c3 = sqrt(-g(Z) * 3 * Z²), with sgn0(c3) = 0
+	t := (&gfP2{}).Square(c.z)
+	t.Mul(t, three).Mul(t, c.c1).Neg(t)
+	c.c3 = &gfP2{}
+	c.c3.Sqrt(t)
+	if sgn0G2(c.c3) == 1 {
+		c.c3.Neg(c.c3)
+	}
+	// c4 = -4 * g(Z) / (3 * Z²)
+	t = (&gfP2{}).Square(c.z)
+	t.Mul(t, three).Invert(t)
+	c.c4 = (&gfP2{}).Mul(t, c.c1)
+	c.c4.Mul(c.c4, &gfP2{y: *newGFp(-4)})
+	return c
+}()
+
+// HashToPoint hashes the message msg to a point of G2 with the domain
+// separation tag dst, following the suite BN256G2_XMD:SHA-256_SVDW_RO_: the
+// message is hashed to two elements of GF(p²) with hash_to_field of RFC
+// 9380, which are mapped to the twist curve with the SvdW map, and the sum of
+// both points is multiplied by the cofactor 2p - n of G2. An element u0 + u1·i
+// of GF(p²) is made of two consecutive elements u0 and u1 of GF(p) output by
+// hash_to_field. It does not run in constant time. It implements
+// kyber.PointHasher.
+func (g *groupG2) HashToPoint(dst, msg []byte) (kyber.Point, error) {
+	u, err := h2c.HashToField(sha256.New, dst, msg, p, 4)
+	if err != nil {
+		return nil, err
+	}
+	return g.fieldToPoint(u), nil
+}
+
+// HashReaderToPoint works like HashToPoint on the message read from r until
+// EOF, without holding it in memory. It returns the error of r, if any.
+func (g *groupG2) HashReaderToPoint(dst []byte, r io.Reader) (kyber.Point, error) {
+	u, err := h2c.HashToFieldReader(sha256.New, dst, r, p, 4)
+	if err != nil {
+		return nil, err
+	}
+	return g.fieldToPoint(u), nil
+}
+
+// fieldToPoint returns the point of G2 of the elements u[0] + u[1]·i and
+// u[2] + u[3]·i of GF(p²).
+func (g *groupG2) fieldToPoint(u []*big.Int) kyber.Point {
+	Q := &twistPoint{}
+	Q.Add(mapToCurveG2(u[0], u[1]), mapToCurveG2(u[2], u[3]))
+	P := g.Point().(*pointG2)
+	P.g.Mul(Q, twistCofactor)
+	return P
+}
+
+// mapToCurveG2 returns the point of the twist curve of the element u0 + u1·i
+// of GF(p²), following map_to_curve_svdw of Appendix F.1 of RFC 9380.
+func mapToCurveG2(u0, u1 *big.Int) *twistPoint {
+	c := svdwG2
+	one := (&gfP2{}).SetOne()
+	g := func(x *gfP2) *gfP2 {
+		gx := (&gfP2{}).Square(x)
+		return gx.Mul(gx, x).Add(gx, twistB)
+	}
+	u := &gfP2{x: *newGFpFromBigInt(u1), y: *newGFpFromBigInt(u0)}
+
+	tv1 := (&gfP2{}).Square(u)
+	tv1.Mul(tv1, c.c1)
+	tv2 := (&gfP2{}).Add(one, tv1)
+	tv1.Sub(one, tv1)
+	tv3 := (&gfP2{}).Mul(tv1, tv2)
+	tv3.Invert(tv3)
+	tv4 := (&gfP2{}).Mul(u, tv1)
+	tv4.Mul(tv4, tv3).Mul(tv4, c.c3)
+
+	y := &gfP2{}
+	x := (&gfP2{}).Sub(c.c2, tv4)
+	if !y.Sqrt(g(x)) {
+		x.Add(c.c2, tv4)
+		if !y.Sqrt(g(x)) {
+			x.Square(tv2).Mul(x, tv3).Square(x).Mul(x, c.c4).Add(x, c.z)
+			y.Sqrt(g(x))
+		}
+	}
+	if sgn0G2(u) != sgn0G2(y) {
+		y.Neg(y)
+	}
+
+	pt := &twistPoint{x: *x, y: *y}
+	pt.z.SetOne()
+	pt.t.SetOne()
+	return pt
+}
+
+// sgn0G2 returns the sign of the element x of GF(p²), as the sgn0 function of
+// RFC 9380 for m = 2: the parity of its real part, or of its imaginary part
+// if the real part is zero.
+func sgn0G2(x *gfP2) uint {
+	d := gfP2Decode(x)
+	if d.y != (gfP{}) {
+		return uint(d.y[0] & 1)
+	}
+	return uint(d.x[0] & 1)
+}
@@ -0,0 +1,100 @@
+package bn256
+
+import (
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/util/h2c"
+)
+
+var hashToPointMessages = []string{
+	"",
+	"abc",
+	"abcdef0123456789",
+	"q128_" + strings.Repeat("q", 128),
+	"a512_" + strings.Repeat("a", 512),
+}
+
+// The vectors of the suites BN256G1_XMD:SHA-256_SVDW_RO_ and
+// BN256G2_XMD:SHA-256_SVDW_RO_ were computed with an independent
+// implementation of RFC 9380, for the messages of hashToPointMessages and the
+// tags of the style of the RFC.
+var hashToG1Vectors = []string{
+	"24806e759b4a774899c983aad9032f5bf7570d2320896c99a181e2fdeb12bb3376b08d168cf7755a0a094881a48f5a19f32d7146bb66d5914b7488422291150d",
+	"64ae303357450c22fee03159020f3d847de6d27a19d58da9cf4f2688ce42e31e5e5afd6b978975f0d2644ff3f3e611580f442b1aaa09faf74fc6ad6762b5ec55",
+	"13dd8022b75d2f8b2305255257fb2b5fdc283ca9e08a68aaebfa074a3e22fb245424bfa2f8b514bb406b846d1da502eaef57e720622fed8fe79c005e20d803ce",
+	"4ca146c352e451fd9e7dec0120a4c21ed0f1e379d80df4add98c2725e555b20c1f1c574398b9bcc9221b630e04616d8b9ac6b02dd641d39ec2ec047a2c42bef7",
+	"3d7af00e53a54e34f6cff2201ea0f42f27ac836fa1ab84623cb578250397218728a06a90e87e54eed0d94e3cd162f1f974357e792722efbeca264b958c89b21e",
+}
+
+var hashToG2Vectors = []string{
+	"1c2518531a4b4240910c31f9085d3027ccd82dbbf53dd99fa334a4483ab6525c7bb728255447706f2590cc645d084cb78dcb55f4aa972aee5ade472ecbd7cb3681a016d957226639395e0bcc89e094c61431c8e477776275a1e784d154f4982f44a7a049774a316880560bb9d95cd61afb6cd405d05092f38914cca6ab1d7ffb",
+	"33d1a4858d102902e7ccfffd70db1c4afe6c80fe14be557747407c71c7d137e52a965aa3ba873a3517642259b32cc8ffb296c78793ec6e9a9c03d36de6ad40bd4ba074caa9764fea8c4f6ac402b6a3940f0dbec26577ac473289f5ad4bf8f1f88a2e255e08762b65b4a42d140ecb64acfb13f6efb4929048dc05df8000b88cd8",
+	"747a95bb1b680726d87059193a7c2ac81333b4ff4c45fbb5525fa6842b2fb6668796d6b701bae10de9c1dc8ea577d1c34fa7e1dfd514e40ec87301c19af75ba27078cdf914e694441c95ca23e5d70d3a5f724feb4a54480a2b14f864f3f4456d5506daa0381274050c4879064945d5af64b91bedabae1ee789506267dd96c0f6",
+	"2978b6b781df3d3bdafc113d093d9cae898f4c86eeceb7fb9fb614ae6fe067b34a9f5d2488e0fdc6394f49e707c0a0f9f07e4fdf74168754a7100496bf1ee402683ce0782e0618e6ca97e4cc40dd4e6f41bfa177c364e121f21082a89eb66e395d0d3017acd6b825598024dca896b0731d0d3cd1cdd75fac7dafe37f9ee72ef2",
+	"22b612126284cd71c246bcf7ce62fd6bda7bcb78cc8e00ff0f76c38b44d1b1b10d5002bc757c4e304cf4db9eac4a56b66ee5168b1f880399a9002b5e685b27997916f5540f36d62cd7885a65e620acd6eb528da3394d3e54e126493fa3b18a516d9163540c713acfc19a2bbfc1abca4c49cdee9332258c45604daa3be3f7e04f",
+}
+
+func testHashToPoint(t *testing.T, g kyber.Group, dst string, vectors []string) {
+	hasher, ok := g.(kyber.PointHasher)
+	require.True(t, ok)
+	minusOne := g.Scalar().SetInt64(-1)
+	for i, msg := range hashToPointMessages {
+		P, err := hasher.HashToPoint([]byte(dst), []byte(msg))
+		require.NoError(t, err)
+		buf, err := P.MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, vectors[i], hex.EncodeToString(buf), "msg %q", msg)
+
+		// the point decodes, and is in the subgroup of prime order
+		Q := g.Point()
+		require.NoError(t, Q.UnmarshalBinary(buf))
+		require.True(t, Q.Equal(P))
+		require.False(t, P.Equal(g.Point().Null()))
+		require.True(t, g.Point().Mul(minusOne, P).Equal(g.Point().Neg(P)))
+	}
+
+	_, err := hasher.HashToPoint(nil, []byte("msg"))
+	require.True(t, errors.Is(err, h2c.ErrEmptyDST))
+
+	// the messages read from a reader hash to the same points
+	readerHasher, ok := g.(interface {
+		HashReaderToPoint([]byte, io.Reader) (kyber.Point, error)
+	})
+	require.True(t, ok)
+	for _, msg := range hashToPointMessages {
+		P, err := hasher.HashToPoint([]byte(dst), []byte(msg))
+		require.NoError(t, err)
+		Q, err := readerHasher.HashReaderToPoint([]byte(dst), iotest.OneByteReader(strings.NewReader(msg)))
+		require.NoError(t, err)
+		require.True(t, P.Equal(Q), "msg %q", msg)
+	}
+}
+
+func TestG1HashToPoint(t *testing.T) {
+	testHashToPoint(t, NewSuite().G1(), "QUUX-V01-CS02-with-BN256G1_XMD:SHA-256_SVDW_RO_", hashToG1Vectors)
+}
+
+func TestG2HashToPoint(t *testing.T) {
+	testHashToPoint(t, NewSuite().G2(), "QUUX-V01-CS02-with-BN256G2_XMD:SHA-256_SVDW_RO_", hashToG2Vectors)
+}
+
+func TestHashToPointPairing(t *testing.T) {
+	// the pairing of hashed points is bilinear, as for any points of G1 and G2
+	suite := NewSuite()
+	dst := []byte("kyber-test")
+	P, err := suite.G1().(kyber.PointHasher).HashToPoint(dst, []byte("g1"))
+	require.NoError(t, err)
+	Q, err := suite.G2().(kyber.PointHasher).HashToPoint(dst, []byte("g2"))
+	require.NoError(t, err)
+	x := suite.G1().Scalar().Pick(suite.RandomStream())
+	left := suite.Pair(suite.G1().Point().Mul(x, P), Q)
+	right := suite.Pair(P, suite.G2().Point().Mul(x, Q))
+	require.True(t, left.Equal(right))
+}
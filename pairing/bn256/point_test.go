@@ -3,8 +3,10 @@ package bn256
 import (
 	"bytes"
 	"encoding/hex"
-	"go.dedis.ch/kyber/v3/util/random"
 	"testing"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/util/random"
 )
 
 func TestPointG1_HashToPoint(t *testing.T) {
@@ -54,3 +56,110 @@ func TestPointG1_EmbedData(t *testing.T) {
 		t.Error("G1: Embed/Data produced wrong output: ", string(mm), " expected ", string(m))
 	}
 }
+
+func TestPointG2_HashToPoint(t *testing.T) {
+	for _, m := range [][]byte{nil, []byte("abc"), []byte("The quick brown fox")} {
+		p := new(pointG2).Hash(m).(*pointG2)
+		if !p.g.IsOnCurve() || p.g.IsInfinity() {
+			t.Fatal("hash is not a point of the twist curve")
+		}
+		// the hash must be in G2, i.e. of order n
+		o := &twistPoint{}
+		o.Mul(p.g, Order)
+		if !o.IsInfinity() {
+			t.Fatal("hash is not in G2")
+		}
+		buf, err := p.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		q := newPointG2()
+		if err := q.UnmarshalBinary(buf); err != nil {
+			t.Fatal(err)
+		}
+		if !q.Equal(p) || !q.Equal(new(pointG2).Hash(m)) {
+			t.Fatal("hash is not deterministic")
+		}
+		if p.Equal(new(pointG2).Hash(append(m, 0))) {
+			t.Fatal("different messages give the same hash")
+		}
+	}
+}
+
+func TestGFp2_Sqrt(t *testing.T) {
+	for i := int64(1); i < 20; i++ {
+		a := &gfP2{*newGFp(i), *newGFp(i * 7)}
+		sq := (&gfP2{}).Square(a)
+		r := &gfP2{}
+		if !r.Sqrt(sq) || !(&gfP2{}).Square(r).Equal(sq) {
+			t.Fatal("square root of a square not found")
+		}
+	}
+	// ξ = i+3 is not a square, as the tower GF(p¹²) relies on it
+	xi := &gfP2{*newGFp(1), *newGFp(3)}
+	if (&gfP2{}).Sqrt(xi) {
+		t.Fatal("square root of a non square found")
+	}
+}
+
+func TestPoint_UnmarshalStrict(t *testing.T) {
+	// the generator (1, -2) of G1 with its x coordinate encoded as 1 + p
+	g1, _ := newPointG1().Base().MarshalBinary()
+	nonCanonical := append(p.FillBytes(make([]byte, 32)), g1[32:]...)
+	nonCanonical[31]++
+
+	// a point of the twist out of G2, which has a cofactor
+	x := (&gfP2{}).SetOne()
+	for {
+		y := (&gfP2{}).Square(x)
+		y.Mul(y, x).Add(y, twistB)
+		if y.Sqrt(y) {
+			break
+		}
+		x.Add(x, (&gfP2{}).SetOne())
+	}
+	twist := &twistPoint{x: *x}
+	twist.y.Square(x).Mul(&twist.y, x).Add(&twist.y, twistB).Sqrt(&twist.y)
+	twist.z.SetOne()
+	twist.t.SetOne()
+	outOfG2, _ := (&pointG2{g: twist}).MarshalBinary()
+
+	// 2 is not in the subgroup of order Order of GF(p¹²)
+	two := (&gfP12{}).SetOne()
+	two.y.z.y = *newGFp(2)
+	outOfGT, _ := (&pointGT{g: two}).MarshalBinary()
+
+	for _, test := range []struct {
+		p        kyber.Point
+		rejected [][]byte
+	}{
+		{newPointG1(), [][]byte{nonCanonical}},
+		{newPointG2(), [][]byte{outOfG2}},
+		{newPointGT(), [][]byte{outOfGT, make([]byte, 12*32)}},
+	} {
+		size := test.p.MarshalSize()
+		q := test.p.Clone().Pick(random.New())
+		buf, err := q.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := test.p.UnmarshalBinary(buf); err != nil || !test.p.Equal(q) {
+			t.Fatalf("%s: round trip failed: %v", q, err)
+		}
+
+		rejected := append(test.rejected, bytes.Repeat([]byte{0xff}, size))
+		for n := 0; n <= 2*size; n++ {
+			if n != size {
+				rejected = append(rejected, append(buf, make([]byte, size)...)[:n])
+			}
+		}
+		for _, b := range rejected {
+			if err := test.p.UnmarshalBinary(b); err == nil {
+				t.Fatalf("%x was accepted", b)
+			}
+			if !test.p.Equal(q) {
+				t.Fatal("receiver modified on error")
+			}
+		}
+	}
+}
@@ -11,6 +11,12 @@
 // http://cryptojedi.org/papers/dclxvi-20100714.pdf. Its output is compatible
 // with the implementation described in that paper.
 //
+// This curve is not the one named BN254 or alt_bn128, used by the precompiled
+// contracts of Ethereum (EIP-196 and EIP-197). Both are Barreto-Naehrig curves
+// but they are built from different parameters, so that their fields, groups
+// and pairings have nothing in common: the points of this package cannot be
+// converted to points checked by these contracts, whatever their encoding.
+//
 // This package previously claimed to operate at a 128-bit security level.
 // However, recent improvements in attacks mean that is no longer true. See
 // https://moderncrypto.org/mail-archive/curves/2016/000740.html.
@@ -46,6 +52,18 @@ func NewSuite() *Suite {
 	return s
 }
 
+// NewSuiteWithHash works like NewSuite, except that the suite uses newHash
+// instead of SHA-256 for Hash and to hash messages onto G1 and G2, e.g. in BLS
+// signatures, and newXOF instead of BLAKE2Xb for XOF and RandomStreamFromSeed.
+// A nil function keeps the default one. The hash-to-curve of G1 reduces the
+// digest modulo p, so that newHash should output at least 32 bytes.
+func NewSuiteWithHash(newHash func() hash.Hash, newXOF func(seed []byte) kyber.XOF) *Suite {
+	s := NewSuite()
+	s.commonSuite.newHash = newHash
+	s.commonSuite.newXOF = newXOF
+	return s
+}
+
 // NewSuiteG1 returns a G1 suite.
 func NewSuiteG1() *Suite {
 	s := NewSuite()
@@ -115,6 +133,18 @@ type commonSuite struct {
 	s cipher.Stream
 	// kyber.Group is only set if we have a combined Suite
 	kyber.Group
+	// hash and XOF of the suite, nil for the default ones
+	newHash func() hash.Hash
+	newXOF  func(seed []byte) kyber.XOF
+}
+
+// hashFunc returns the hash function of the suite, which also hashes the
+// messages onto the curves. It is SHA-256 by default.
+func (c *commonSuite) hashFunc() func() hash.Hash {
+	if c == nil {
+		return sha256.New
+	}
+	return hashFunc(c.newHash)
 }
 
 // New implements the kyber.Encoding interface.
@@ -150,13 +180,18 @@ func (c *commonSuite) Write(w io.Writer, objs ...interface{}) error {
 	return fixbuf.Write(w, objs)
 }
 
-// Hash returns a newly instantiated sha256 hash function.
+// Hash returns a newly instantiated sha256 hash function, or the one given
+// to NewSuiteWithHash.
 func (c *commonSuite) Hash() hash.Hash {
-	return sha256.New()
+	return c.hashFunc()()
 }
 
-// XOF returns a newlly instantiated blake2xb XOF function.
+// XOF returns a newlly instantiated blake2xb XOF function, or the one given
+// to NewSuiteWithHash.
 func (c *commonSuite) XOF(seed []byte) kyber.XOF {
+	if c.newXOF != nil {
+		return c.newXOF(seed)
+	}
 	return blake2xb.New(seed)
 }
 
@@ -169,6 +204,20 @@ func (c *commonSuite) RandomStream() cipher.Stream {
 	return random.New()
 }
 
+// seedStreamDomain separates the streams of RandomStreamFromSeed from any
+// other use of the XOF of the suite.
+const seedStreamDomain = "kyber.bn256.RandomStreamFromSeed"
+
+// RandomStreamFromSeed returns a deterministic cipher.Stream derived from the
+// given seed with the XOF of the suite. The same seed yields the same stream
+// across versions of this package, so that values picked from it can be used
+// as test vectors. The stream is only as unpredictable as the seed.
+func (c *commonSuite) RandomStreamFromSeed(seed []byte) cipher.Stream {
+	x := c.XOF([]byte(seedStreamDomain))
+	_, _ = x.Write(seed)
+	return x
+}
+
 // String returns a recognizable string that this is a combined suite.
 func (c commonSuite) String() string {
 	if c.Group != nil {
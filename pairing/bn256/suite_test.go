@@ -2,6 +2,7 @@ package bn256
 
 import (
 	"bytes"
+	"encoding/hex"
 	"fmt"
 	"testing"
 
@@ -406,3 +407,44 @@ func TestNegPairAll(t *testing.T) {
 	require.True(t, pair2.Equal(pair3))
 	require.True(t, pair3.Equal(pair4))
 }
+
+// TestRandomStreamFromSeed checks that the seeded stream is stable, as its
+// output is part of the compatibility contract of the suite.
+func TestRandomStreamFromSeed(t *testing.T) {
+	suite := NewSuite()
+	for i := 0; i < 2; i++ {
+		r := suite.RandomStreamFromSeed([]byte("kyber test vector"))
+		scalar, err := suite.G1().Scalar().Pick(r).MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, "5f01f71865428f5bdee04860ea281d1795ebe2bac3fae3f4f83a1ca1d2abddef", hex.EncodeToString(scalar))
+		p1, err := suite.G1().Point().Pick(r).MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, "6a7f6aa671b30dbe669ed3568702dc0e48bcd79b384d7597248192f8b324fd336667fbe62b677e680b7043655810fe2a140d34e5703df6e46add33d35939ba13", hex.EncodeToString(p1))
+	}
+
+	p2, err := suite.G2().Point().Pick(suite.RandomStreamFromSeed([]byte("kyber test vector"))).MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, "8d8e99477e993810ef430e3e700494d1aebd6911a9dbb051578a121b29d2e311233709f3abb9ca11ec99fa9032e946de454a777e375c1cefc3c27bdff8c11b72753b824bca16a24aac227ce42c99a2a6de55d523a481e94238ad912f73173d498369209a1fe5660ed5400a6631c1a540c8952d3b31b847373198e882ad51ceaf", hex.EncodeToString(p2))
+}
+
+func TestPointEqualRepresentations(t *testing.T) {
+	suite := NewSuite()
+	for _, g := range []kyber.Group{suite.G1(), suite.G2(), suite.GT()} {
+		a := g.Scalar().Pick(random.New())
+		b := g.Scalar().Pick(random.New())
+		Q := g.Point().Pick(random.New())
+
+		P1 := g.Point().Mul(g.Scalar().Add(a, b), nil)
+		P2 := g.Point().Add(g.Point().Mul(a, nil), g.Point().Mul(b, nil))
+		P3 := g.Point().Sub(g.Point().Add(P1, Q), Q)
+		for _, P := range []kyber.Point{P2, P3} {
+			require.True(t, P1.Equal(P))
+			require.True(t, P.Equal(P1))
+		}
+		require.False(t, P1.Equal(Q))
+		require.True(t, g.Point().Sub(Q, Q).Equal(g.Point().Null()))
+
+		require.True(t, a.Equal(g.Scalar().Sub(g.Scalar().Add(a, b), b)))
+		require.False(t, a.Equal(b))
+	}
+}
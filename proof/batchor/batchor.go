@@ -0,0 +1,285 @@
+// Package batchor provides non-interactive zero-knowledge proofs of the OR
+// composition of a dlog-equality statement and of a discard statement, with
+// compact proofs and batched verification.
+//
+// Given the bases G, H and J shared by all the instances, the proof of an
+// instance (X, Y, Z) shows that
+//   log_{G}(X) == log_{H}(Y)  OR  the prover knows log_{J}(Z)
+// without revealing which side holds. In a mixnet, X is the public share of a
+// decrypting node, Y its decryption share and Z the commitment of the marker
+// set when the ciphertext has been discarded as invalid.
+//
+// The proofs follow the OR composition of Cramer, Damgård and Schoenmakers:
+// the prover simulates the side it does not know a witness for, and splits the
+// Fiat-Shamir challenge c into c0 + c1 between both sides. A proof holds the
+// commitments of both sides, so that BatchVerify checks many instances with a
+// single random linear combination of their verification equations.
+package batchor
+
+import (
+	"errors"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+// Suite wraps the functionalities needed by the batchor package.
+type Suite interface {
+	kyber.Group
+	kyber.HashFactory
+	kyber.XOFFactory
+	kyber.Random
+}
+
+var errorDifferentLengths = errors.New("batchor: inputs of different lengths")
+var errorInvalidProof = errors.New("batchor: invalid proof")
+var errorInvalidWitness = errors.New("batchor: witness does not match the statement")
+var errorProofLength = errors.New("batchor: invalid proof length")
+
+// Bases are the base points shared by all the instances.
+type Bases struct {
+	G, H, J kyber.Point
+}
+
+// Statement is one instance: X and Y have the same discrete logarithm with
+// respect to G and H, or the discrete logarithm of Z with respect to J is
+// known.
+type Statement struct {
+	X, Y, Z kyber.Point
+}
+
+// Proof is a proof of a Statement. The commitments VG and VH belong to the
+// dlog-equality side and VJ to the discard side. C0 is the challenge of the
+// dlog-equality side, the one of the discard side being c - C0 where c is the
+// Fiat-Shamir challenge. R0 and R1 are the responses of both sides.
+type Proof struct {
+	VG, VH, VJ kyber.Point
+	C0         kyber.Scalar
+	R0, R1     kyber.Scalar
+}
+
+// ProveDLEQ returns a proof of the statement knowing x such that X = xG and
+// Y = xH.
+func ProveDLEQ(suite Suite, b *Bases, st *Statement, x kyber.Scalar) (*Proof, error) {
+	if !suite.Point().Mul(x, b.G).Equal(st.X) || !suite.Point().Mul(x, b.H).Equal(st.Y) {
+		return nil, errorInvalidWitness
+	}
+	rand := suite.RandomStream()
+	// simulate the discard side
+	c1 := suite.Scalar().Pick(rand)
+	r1 := suite.Scalar().Pick(rand)
+	p := &Proof{R1: r1}
+	p.VJ = suite.Point().Add(suite.Point().Mul(r1, b.J), suite.Point().Mul(c1, st.Z))
+
+	v := suite.Scalar().Pick(rand)
+	p.VG = suite.Point().Mul(v, b.G)
+	p.VH = suite.Point().Mul(v, b.H)
+
+	c := challenge(suite, b, st, p)
+	p.C0 = c.Sub(c, c1)
+	p.R0 = suite.Scalar().Mul(x, p.C0)
+	p.R0.Sub(v, p.R0)
+	return p, nil
+}
+
+// ProveDiscard returns a proof of the statement knowing z such that Z = zJ.
+func ProveDiscard(suite Suite, b *Bases, st *Statement, z kyber.Scalar) (*Proof, error) {
+	if !suite.Point().Mul(z, b.J).Equal(st.Z) {
+		return nil, errorInvalidWitness
+	}
+	rand := suite.RandomStream()
+	// simulate the dlog-equality side
+	c0 := suite.Scalar().Pick(rand)
+	r0 := suite.Scalar().Pick(rand)
+	p := &Proof{C0: c0, R0: r0}
+	p.VG = suite.Point().Add(suite.Point().Mul(r0, b.G), suite.Point().Mul(c0, st.X))
+	p.VH = suite.Point().Add(suite.Point().Mul(r0, b.H), suite.Point().Mul(c0, st.Y))
+
+	v := suite.Scalar().Pick(rand)
+	p.VJ = suite.Point().Mul(v, b.J)
+
+	c1 := challenge(suite, b, st, p)
+	c1.Sub(c1, c0)
+	p.R1 = suite.Scalar().Mul(z, c1)
+	p.R1.Sub(v, p.R1)
+	return p, nil
+}
+
+// Verify examines the validity of the proof of the statement. The proof is
+// valid if the following conditions hold, with c1 = H(...) - c0:
+//   vG == r0G + c0X
+//   vH == r0H + c0Y
+//   vJ == r1J + c1Z
+func (p *Proof) Verify(suite Suite, b *Bases, st *Statement) error {
+	if !p.complete() {
+		return errorInvalidProof
+	}
+	c1 := p.c1(suite, b, st)
+	vG := suite.Point().Add(suite.Point().Mul(p.R0, b.G), suite.Point().Mul(p.C0, st.X))
+	vH := suite.Point().Add(suite.Point().Mul(p.R0, b.H), suite.Point().Mul(p.C0, st.Y))
+	vJ := suite.Point().Add(suite.Point().Mul(p.R1, b.J), suite.Point().Mul(c1, st.Z))
+	if !(vG.Equal(p.VG) && vH.Equal(p.VH) && vJ.Equal(p.VJ)) {
+		return errorInvalidProof
+	}
+	return nil
+}
+
+// BatchVerify verifies the proofs of many statements sharing the same bases
+// and returns the indices of the invalid ones. The verification equations of
+// all the proofs are combined with random coefficients into a single one,
+// which holds if all the proofs are valid, and fails with overwhelming
+// probability otherwise. Only if it fails, the proofs are verified one by one
+// to identify the invalid ones.
+func BatchVerify(suite Suite, b *Bases, sts []*Statement, proofs []*Proof) ([]int, error) {
+	if len(sts) != len(proofs) {
+		return nil, errorDifferentLengths
+	}
+	if batchHolds(suite, b, sts, proofs) {
+		return nil, nil
+	}
+	var bad []int
+	for i, p := range proofs {
+		if p == nil || sts[i] == nil || p.Verify(suite, b, sts[i]) != nil {
+			bad = append(bad, i)
+		}
+	}
+	return bad, nil
+}
+
+// batchHolds checks that
+//   sum_i a_i(r0_i G + c0_i X_i - vG_i) + b_i(r0_i H + c0_i Y_i - vH_i) +
+//         d_i(r1_i J + c1_i Z_i - vJ_i) == 0
+// for random coefficients a_i, b_i and d_i. The terms of the bases are
+// gathered so that they are multiplied once. The coefficients are 128 bits
+// long, which bounds the probability that invalid proofs pass by 2^-128 and
+// makes their multiplications faster.
+func batchHolds(suite Suite, b *Bases, sts []*Statement, proofs []*Proof) bool {
+	rand := suite.RandomStream()
+	coeff := make([]byte, 16)
+	short := func() kyber.Scalar {
+		random.Bytes(coeff, rand)
+		return suite.Scalar().SetBytes(coeff)
+	}
+	sG, sH, sJ := suite.Scalar().Zero(), suite.Scalar().Zero(), suite.Scalar().Zero()
+	sum := allowVarTime(suite.Point().Null())
+	tmp := allowVarTime(suite.Point())
+	k := suite.Scalar()
+	for i, p := range proofs {
+		st := sts[i]
+		if p == nil || st == nil || !p.complete() {
+			return false
+		}
+		a, bb, d := short(), short(), short()
+		c1 := p.c1(suite, b, st)
+
+		sG.Add(sG, k.Mul(a, p.R0))
+		sH.Add(sH, k.Mul(bb, p.R0))
+		sJ.Add(sJ, k.Mul(d, p.R1))
+		sum.Add(sum, tmp.Mul(k.Mul(a, p.C0), st.X))
+		sum.Add(sum, tmp.Mul(k.Mul(bb, p.C0), st.Y))
+		sum.Add(sum, tmp.Mul(k.Mul(d, c1), st.Z))
+		sum.Sub(sum, tmp.Mul(a, p.VG))
+		sum.Sub(sum, tmp.Mul(bb, p.VH))
+		sum.Sub(sum, tmp.Mul(d, p.VJ))
+	}
+	sum.Add(sum, tmp.Mul(sG, b.G))
+	sum.Add(sum, tmp.Mul(sH, b.H))
+	sum.Add(sum, tmp.Mul(sJ, b.J))
+	return sum.Equal(suite.Point().Null())
+}
+
+func (p *Proof) complete() bool {
+	return p.VG != nil && p.VH != nil && p.VJ != nil && p.C0 != nil && p.R0 != nil && p.R1 != nil
+}
+
+// c1 returns the challenge of the discard side.
+func (p *Proof) c1(suite Suite, b *Bases, st *Statement) kyber.Scalar {
+	c := challenge(suite, b, st, p)
+	return c.Sub(c, p.C0)
+}
+
+// challenge computes the Fiat-Shamir challenge c = H(G,H,J,X,Y,Z,vG,vH,vJ).
+func challenge(suite Suite, b *Bases, st *Statement, p *Proof) kyber.Scalar {
+	h := suite.Hash()
+	for _, P := range []kyber.Point{b.G, b.H, b.J, st.X, st.Y, st.Z, p.VG, p.VH, p.VJ} {
+		_, _ = P.MarshalTo(h)
+	}
+	return suite.Scalar().Pick(suite.XOF(h.Sum(nil)))
+}
+
+func allowVarTime(P kyber.Point) kyber.Point {
+	if vt, ok := P.(kyber.AllowsVarTime); ok {
+		vt.AllowVarTime(true)
+	}
+	return P
+}
+
+// MarshalBinary encodes the proof as vG || vH || vJ || c0 || r0 || r1.
+func (p *Proof) MarshalBinary() ([]byte, error) {
+	if !p.complete() {
+		return nil, errorInvalidProof
+	}
+	var buf []byte
+	for _, m := range []kyber.Marshaling{p.VG, p.VH, p.VJ, p.C0, p.R0, p.R1} {
+		b, err := m.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, b...)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary decodes a proof encoded by MarshalBinary.
+func (p *Proof) UnmarshalBinary(suite Suite, buf []byte) error {
+	if len(buf) != proofLen(suite) {
+		return errorProofLength
+	}
+	q := &Proof{
+		VG: suite.Point(), VH: suite.Point(), VJ: suite.Point(),
+		C0: suite.Scalar(), R0: suite.Scalar(), R1: suite.Scalar(),
+	}
+	for _, m := range []kyber.Marshaling{q.VG, q.VH, q.VJ, q.C0, q.R0, q.R1} {
+		l := m.MarshalSize()
+		if err := m.UnmarshalBinary(buf[:l]); err != nil {
+			return err
+		}
+		buf = buf[l:]
+	}
+	*p = *q
+	return nil
+}
+
+// MarshalProofs encodes a list of proofs as the concatenation of their
+// encodings.
+func MarshalProofs(proofs []*Proof) ([]byte, error) {
+	var buf []byte
+	for _, p := range proofs {
+		b, err := p.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, b...)
+	}
+	return buf, nil
+}
+
+// UnmarshalProofs decodes a list of proofs encoded by MarshalProofs.
+func UnmarshalProofs(suite Suite, buf []byte) ([]*Proof, error) {
+	l := proofLen(suite)
+	if len(buf)%l != 0 {
+		return nil, errorProofLength
+	}
+	proofs := make([]*Proof, len(buf)/l)
+	for i := range proofs {
+		proofs[i] = &Proof{}
+		if err := proofs[i].UnmarshalBinary(suite, buf[i*l:(i+1)*l]); err != nil {
+			return nil, err
+		}
+	}
+	return proofs, nil
+}
+
+func proofLen(suite Suite) int {
+	return 3*suite.PointLen() + 3*suite.ScalarLen()
+}
@@ -0,0 +1,160 @@
+package batchor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+)
+
+var suite = edwards25519.NewBlakeSHA256Ed25519()
+
+func testBases() *Bases {
+	return &Bases{
+		G: suite.Point().Base(),
+		H: suite.Point().Pick(suite.XOF([]byte("H"))),
+		J: suite.Point().Pick(suite.XOF([]byte("J"))),
+	}
+}
+
+// instances returns n statements with their proofs, every third one being a
+// discard.
+func instances(t testing.TB, b *Bases, n int) ([]*Statement, []*Proof) {
+	x := suite.Scalar().Pick(suite.RandomStream())
+	X := suite.Point().Mul(x, b.G)
+	sts := make([]*Statement, n)
+	proofs := make([]*Proof, n)
+	for i := range sts {
+		var err error
+		if i%3 == 2 {
+			z := suite.Scalar().Pick(suite.RandomStream())
+			sts[i] = &Statement{X: X, Y: suite.Point().Pick(suite.RandomStream()), Z: suite.Point().Mul(z, b.J)}
+			proofs[i], err = ProveDiscard(suite, b, sts[i], z)
+		} else {
+			sts[i] = &Statement{X: X, Y: suite.Point().Mul(x, b.H), Z: suite.Point().Pick(suite.RandomStream())}
+			proofs[i], err = ProveDLEQ(suite, b, sts[i], x)
+		}
+		require.NoError(t, err)
+	}
+	return sts, proofs
+}
+
+func TestProofs(t *testing.T) {
+	b := testBases()
+	sts, proofs := instances(t, b, 6)
+	for i, p := range proofs {
+		require.NoError(t, p.Verify(suite, b, sts[i]))
+		// a proof does not verify for another statement
+		require.Error(t, p.Verify(suite, b, sts[(i+1)%len(sts)]))
+	}
+
+	// wrong witnesses
+	z := suite.Scalar().Pick(suite.RandomStream())
+	_, err := ProveDLEQ(suite, b, sts[0], z)
+	require.Equal(t, errorInvalidWitness, err)
+	_, err = ProveDiscard(suite, b, sts[0], z)
+	require.Equal(t, errorInvalidWitness, err)
+
+	// incomplete proof
+	require.Equal(t, errorInvalidProof, (&Proof{}).Verify(suite, b, sts[0]))
+}
+
+// forge tries to prove the statement without knowing any witness, by
+// simulating both sides.
+func forge(b *Bases, st *Statement) *Proof {
+	rand := suite.RandomStream()
+	p := &Proof{C0: suite.Scalar().Pick(rand), R0: suite.Scalar().Pick(rand), R1: suite.Scalar().Pick(rand)}
+	c1 := suite.Scalar().Pick(rand)
+	p.VG = suite.Point().Add(suite.Point().Mul(p.R0, b.G), suite.Point().Mul(p.C0, st.X))
+	p.VH = suite.Point().Add(suite.Point().Mul(p.R0, b.H), suite.Point().Mul(p.C0, st.Y))
+	p.VJ = suite.Point().Add(suite.Point().Mul(p.R1, b.J), suite.Point().Mul(c1, st.Z))
+	return p
+}
+
+func TestProofWithoutWitness(t *testing.T) {
+	b := testBases()
+	rand := suite.RandomStream()
+	// Y has another discrete logarithm than X and the one of Z is unknown
+	st := &Statement{
+		X: suite.Point().Mul(suite.Scalar().Pick(rand), b.G),
+		Y: suite.Point().Mul(suite.Scalar().Pick(rand), b.H),
+		Z: suite.Point().Pick(rand),
+	}
+	p := forge(b, st)
+	require.Equal(t, errorInvalidProof, p.Verify(suite, b, st))
+
+	sts, proofs := instances(t, b, 10)
+	sts[4], proofs[4] = st, p
+	bad, err := BatchVerify(suite, b, sts, proofs)
+	require.NoError(t, err)
+	require.Equal(t, []int{4}, bad)
+}
+
+func TestBatchVerify(t *testing.T) {
+	b := testBases()
+	n := 10000
+	if testing.Short() {
+		n = 100
+	}
+	sts, proofs := instances(t, b, n)
+	bad, err := BatchVerify(suite, b, sts, proofs)
+	require.NoError(t, err)
+	require.Empty(t, bad)
+
+	// invalid proofs are identified
+	sts, proofs = sts[:100], proofs[:100]
+	proofs[1] = proofs[0]
+	proofs[99].R1 = suite.Scalar().Add(proofs[99].R1, suite.Scalar().One())
+	sts[7] = nil
+	bad, err = BatchVerify(suite, b, sts, proofs)
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 7, 99}, bad)
+
+	_, err = BatchVerify(suite, b, sts, proofs[1:])
+	require.Equal(t, errorDifferentLengths, err)
+}
+
+func TestMarshalProofs(t *testing.T) {
+	b := testBases()
+	sts, proofs := instances(t, b, 5)
+	buf, err := MarshalProofs(proofs)
+	require.NoError(t, err)
+	require.Equal(t, len(proofs)*(3*suite.PointLen()+3*suite.ScalarLen()), len(buf))
+
+	decoded, err := UnmarshalProofs(suite, buf)
+	require.NoError(t, err)
+	require.Equal(t, len(proofs), len(decoded))
+	for i, p := range decoded {
+		require.NoError(t, p.Verify(suite, b, sts[i]))
+	}
+	bad, err := BatchVerify(suite, b, sts, decoded)
+	require.NoError(t, err)
+	require.Empty(t, bad)
+
+	_, err = UnmarshalProofs(suite, buf[1:])
+	require.Equal(t, errorProofLength, err)
+	p := &Proof{}
+	require.Equal(t, errorProofLength, p.UnmarshalBinary(suite, buf))
+	_, err = (&Proof{}).MarshalBinary()
+	require.Equal(t, errorInvalidProof, err)
+}
+
+func BenchmarkBatchVerify(b *testing.B) {
+	bases := testBases()
+	sts, proofs := instances(b, bases, 1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = BatchVerify(suite, bases, sts, proofs)
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	bases := testBases()
+	sts, proofs := instances(b, bases, 1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j, p := range proofs {
+			_ = p.Verify(suite, bases, sts[j])
+		}
+	}
+}
@@ -46,13 +46,7 @@ func NewDLEQProof(suite Suite, G kyber.Point, H kyber.Point, x kyber.Scalar) (pr
 	vH := suite.Point().Mul(v, H)
 
 	// Challenge
-	h := suite.Hash()
-	xG.MarshalTo(h)
-	xH.MarshalTo(h)
-	vG.MarshalTo(h)
-	vH.MarshalTo(h)
-	cb := h.Sum(nil)
-	c := suite.Scalar().Pick(suite.XOF(cb))
+	c := challenge(suite, xG, xH, vG, vH)
 
 	// Response
 	r := suite.Scalar()
@@ -62,8 +56,8 @@ func NewDLEQProof(suite Suite, G kyber.Point, H kyber.Point, x kyber.Scalar) (pr
 }
 
 // NewDLEQProofBatch computes lists of NIZK dlog-equality proofs and of
-// encrypted base points xG and xH. Note that the challenge is computed over all
-// input values.
+// encrypted base points xG and xH. Each proof has its own challenge so that it
+// can be verified independently of the others.
 func NewDLEQProofBatch(suite Suite, G []kyber.Point, H []kyber.Point, secrets []kyber.Scalar) (proof []*Proof, xG []kyber.Point, xH []kyber.Point, err error) {
 	if len(G) != len(H) || len(H) != len(secrets) {
 		return nil, nil, nil, errorDifferentLengths
@@ -71,56 +65,31 @@ func NewDLEQProofBatch(suite Suite, G []kyber.Point, H []kyber.Point, secrets []
 
 	n := len(secrets)
 	proofs := make([]*Proof, n)
-	v := make([]kyber.Scalar, n)
 	xG = make([]kyber.Point, n)
 	xH = make([]kyber.Point, n)
-	vG := make([]kyber.Point, n)
-	vH := make([]kyber.Point, n)
 
 	for i, x := range secrets {
-		// Encrypt base points with secrets
-		xG[i] = suite.Point().Mul(x, G[i])
-		xH[i] = suite.Point().Mul(x, H[i])
-
-		// Commitments
-		v[i] = suite.Scalar().Pick(suite.RandomStream())
-		vG[i] = suite.Point().Mul(v[i], G[i])
-		vH[i] = suite.Point().Mul(v[i], H[i])
-	}
-
-	// Collective challenge
-	h := suite.Hash()
-	for _, x := range xG {
-		x.MarshalTo(h)
-	}
-	for _, x := range xH {
-		x.MarshalTo(h)
-	}
-	for _, x := range vG {
-		x.MarshalTo(h)
-	}
-	for _, x := range vH {
-		x.MarshalTo(h)
-	}
-	cb := h.Sum(nil)
-
-	c := suite.Scalar().Pick(suite.XOF(cb))
-
-	// Responses
-	for i, x := range secrets {
-		r := suite.Scalar()
-		r.Mul(x, c).Sub(v[i], r)
-		proofs[i] = &Proof{c, r, vG[i], vH[i]}
+		proofs[i], xG[i], xH[i], err = NewDLEQProof(suite, G[i], H[i], x)
+		if err != nil {
+			return nil, nil, nil, err
+		}
 	}
 
 	return proofs, xG, xH, nil
 }
 
 // Verify examines the validity of the NIZK dlog-equality proof.
-// The proof is valid if the following two conditions hold:
+// The proof is valid if the following three conditions hold:
+//   c == H(xG,xH,vG,vH)
 //   vG == rG + c(xG)
 //   vH == rH + c(xH)
 func (p *Proof) Verify(suite Suite, G kyber.Point, H kyber.Point, xG kyber.Point, xH kyber.Point) error {
+	if p.C == nil || p.R == nil || p.VG == nil || p.VH == nil {
+		return errorInvalidProof
+	}
+	if !p.C.Equal(challenge(suite, xG, xH, p.VG, p.VH)) {
+		return errorInvalidProof
+	}
 	rG := suite.Point().Mul(p.R, G)
 	rH := suite.Point().Mul(p.R, H)
 	cxG := suite.Point().Mul(p.C, xG)
@@ -132,3 +101,12 @@ func (p *Proof) Verify(suite Suite, G kyber.Point, H kyber.Point, xG kyber.Point
 	}
 	return nil
 }
+
+// challenge computes the Fiat-Shamir challenge c = H(xG,xH,vG,vH).
+func challenge(suite Suite, xG, xH, vG, vH kyber.Point) kyber.Scalar {
+	h := suite.Hash()
+	for _, p := range []kyber.Point{xG, xH, vG, vH} {
+		_, _ = p.MarshalTo(h)
+	}
+	return suite.Scalar().Pick(suite.XOF(h.Sum(nil)))
+}
@@ -59,3 +59,29 @@ func TestDLEQLengths(t *testing.T) {
 	_, _, _, err := NewDLEQProofBatch(suite, g, h, x)
 	require.Equal(t, err, errorDifferentLengths)
 }
+
+func TestDLEQForgedProof(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	g := suite.Point().Pick(rng)
+	h := suite.Point().Pick(rng)
+	x := suite.Scalar().Pick(rng)
+	xG := suite.Point().Mul(x, g)
+	// xH does not share the discrete logarithm of xG
+	xH := suite.Point().Pick(rng)
+
+	// commitments built from arbitrary c and r satisfy the verification
+	// equations without any knowledge of x
+	c := suite.Scalar().Pick(rng)
+	r := suite.Scalar().Pick(rng)
+	vG := suite.Point().Add(suite.Point().Mul(r, g), suite.Point().Mul(c, xG))
+	vH := suite.Point().Add(suite.Point().Mul(r, h), suite.Point().Mul(c, xH))
+	forged := &Proof{C: c, R: r, VG: vG, VH: vH}
+	require.Equal(t, errorInvalidProof, forged.Verify(suite, g, h, xG, xH))
+
+	// a valid proof does not verify for other points
+	proof, xG, xH, err := NewDLEQProof(suite, g, h, x)
+	require.NoError(t, err)
+	require.Nil(t, proof.Verify(suite, g, h, xG, xH))
+	require.Error(t, proof.Verify(suite, g, h, xG, suite.Point().Pick(rng)))
+	require.Error(t, (&Proof{}).Verify(suite, g, h, xG, xH))
+}
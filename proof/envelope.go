@@ -0,0 +1,289 @@
+package proof
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/suites"
+)
+
+// EnvelopeVersion1 is the version byte of the current envelope encoding.
+const EnvelopeVersion1 byte = 1
+
+var errEnvelopeVersion = errors.New("proof: unknown envelope version")
+var errEnvelopeFormat = errors.New("proof: malformed envelope")
+
+// Envelope is a non-interactive proof together with everything needed to
+// verify it later, except for the values of the public points: the name of
+// the suite it was produced with and its predicate.
+type Envelope struct {
+	SuiteName string
+	Suite     Suite
+	Predicate Predicate
+	Proof     []byte
+}
+
+// Encode returns the envelope of a proof produced by HashProve. The predicate
+// must be given in the canonical form returned by Canonical and the suite by
+// its name in the suites registry.
+//
+// The encoding consists of the version byte EnvelopeVersion1 followed by the
+// suite name, the predicate and the proof, each prefixed by its length as an
+// unsigned varint.
+func Encode(suiteName, predicate string, proof []byte) []byte {
+	buf := []byte{EnvelopeVersion1}
+	for _, f := range [][]byte{[]byte(suiteName), []byte(predicate), proof} {
+		var l [binary.MaxVarintLen64]byte
+		buf = append(buf, l[:binary.PutUvarint(l[:], uint64(len(f)))]...)
+		buf = append(buf, f...)
+	}
+	return buf
+}
+
+// Decode parses an envelope produced by Encode, looks up its suite in the
+// suites registry and parses its predicate.
+func Decode(envelope []byte) (*Envelope, error) {
+	if len(envelope) == 0 || envelope[0] != EnvelopeVersion1 {
+		return nil, errEnvelopeVersion
+	}
+	buf := envelope[1:]
+	var fields [3][]byte
+	for i := range fields {
+		l, n := binary.Uvarint(buf)
+		if n <= 0 || l > uint64(len(buf)-n) {
+			return nil, errEnvelopeFormat
+		}
+		fields[i] = buf[n : n+int(l)]
+		buf = buf[n+int(l):]
+	}
+	if len(buf) != 0 {
+		return nil, errEnvelopeFormat
+	}
+
+	name := string(fields[0])
+	suite, err := suites.Find(name)
+	if err != nil {
+		return nil, err
+	}
+	pred, err := ParsePredicate(string(fields[1]))
+	if err != nil {
+		return nil, err
+	}
+	return &Envelope{
+		SuiteName: name,
+		Suite:     suite,
+		Predicate: pred,
+		Proof:     append([]byte{}, fields[2]...),
+	}, nil
+}
+
+// Verify checks the proof of the envelope with HashVerify, given the protocol
+// name it was produced with and the values of the public points of the
+// predicate.
+func (e *Envelope) Verify(protocolName string, points map[string]kyber.Point) error {
+	return HashVerify(e.Suite, protocolName, e.Predicate.Verifier(e.Suite, points), e.Proof)
+}
+
+// Canonical returns the canonical string form of a predicate, which
+// ParsePredicate turns back into the same predicate: Rep(P,x1,B1,...,xn,Bn),
+// And(p1,...,pn) and Or(p1,...,pn). Unlike String, it preserves the structure
+// of nested And and Or predicates, which the proofs depend on. Variable names
+// must only contain letters, digits, '_' and '.'.
+func Canonical(pred Predicate) (string, error) {
+	var b strings.Builder
+	if err := writeCanonical(&b, pred); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+func writeCanonical(b *strings.Builder, pred Predicate) error {
+	var sub []Predicate
+	switch p := pred.(type) {
+	case *repPred:
+		names := []string{p.P}
+		for _, t := range p.T {
+			names = append(names, t.S, t.B)
+		}
+		b.WriteString("Rep(")
+		for i, n := range names {
+			if !validName(n) {
+				return fmt.Errorf("proof: invalid variable name %q", n)
+			}
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(n)
+		}
+		b.WriteByte(')')
+		return nil
+	case *andPred:
+		b.WriteString("And(")
+		sub = *p
+	case *orPred:
+		b.WriteString("Or(")
+		sub = *p
+	default:
+		return fmt.Errorf("proof: unsupported predicate %T", pred)
+	}
+	if len(sub) == 0 {
+		return errors.New("proof: empty predicate")
+	}
+	for i, s := range sub {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		if err := writeCanonical(b, s); err != nil {
+			return err
+		}
+	}
+	b.WriteByte(')')
+	return nil
+}
+
+// ParseError is returned by ParsePredicate for malformed predicates. Pos is
+// the byte offset of the error in the parsed string.
+type ParseError struct {
+	Pos int
+	Msg string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("proof: invalid predicate at position %d: %s", e.Pos, e.Msg)
+}
+
+// ParsePredicate parses the canonical string form of a predicate, as returned
+// by Canonical. Spaces are allowed between the tokens.
+func ParsePredicate(s string) (Predicate, error) {
+	p := &parser{s: s}
+	pred, err := p.predicate()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.pos != len(s) {
+		return nil, p.errorf("unexpected %q after predicate", s[p.pos])
+	}
+	return pred, nil
+}
+
+type parser struct {
+	s   string
+	pos int
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return &ParseError{Pos: p.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) skipSpaces() {
+	for p.pos < len(p.s) && (p.s[p.pos] == ' ' || p.s[p.pos] == '\t' || p.s[p.pos] == '\n') {
+		p.pos++
+	}
+}
+
+func (p *parser) name() (string, error) {
+	p.skipSpaces()
+	start := p.pos
+	for p.pos < len(p.s) && isNameByte(p.s[p.pos]) {
+		p.pos++
+	}
+	if start == p.pos {
+		if p.pos == len(p.s) {
+			return "", p.errorf("unexpected end of predicate, expected a name")
+		}
+		return "", p.errorf("unexpected %q, expected a name", p.s[p.pos])
+	}
+	return p.s[start:p.pos], nil
+}
+
+// next consumes the next non-space byte, which must be one of the given ones.
+func (p *parser) next(expected string) (byte, error) {
+	p.skipSpaces()
+	if p.pos == len(p.s) {
+		return 0, p.errorf("unexpected end of predicate, expected one of %q", expected)
+	}
+	c := p.s[p.pos]
+	if strings.IndexByte(expected, c) < 0 {
+		return 0, p.errorf("unexpected %q, expected one of %q", c, expected)
+	}
+	p.pos++
+	return c, nil
+}
+
+func (p *parser) predicate() (Predicate, error) {
+	p.skipSpaces()
+	start := p.pos
+	op, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.next("("); err != nil {
+		return nil, err
+	}
+	switch op {
+	case "Rep":
+		var names []string
+		for {
+			n, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+			names = append(names, n)
+			c, err := p.next(",)")
+			if err != nil {
+				return nil, err
+			}
+			if c == ')' {
+				break
+			}
+		}
+		if len(names)%2 != 1 {
+			p.pos = start
+			return nil, p.errorf("Rep needs a point followed by (scalar, base) pairs")
+		}
+		return Rep(names[0], names[1:]...), nil
+	case "And", "Or":
+		var sub []Predicate
+		for {
+			s, err := p.predicate()
+			if err != nil {
+				return nil, err
+			}
+			sub = append(sub, s)
+			c, err := p.next(",)")
+			if err != nil {
+				return nil, err
+			}
+			if c == ')' {
+				break
+			}
+		}
+		if op == "And" {
+			return And(sub...), nil
+		}
+		return Or(sub...), nil
+	default:
+		p.pos = start
+		return nil, p.errorf("unknown predicate %q", op)
+	}
+}
+
+func isNameByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '.'
+}
+
+func validName(n string) bool {
+	if n == "" {
+		return false
+	}
+	for i := 0; i < len(n); i++ {
+		if !isNameByte(n[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,110 @@
+package proof
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/suites"
+)
+
+func TestEnvelopeRoundTrip(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	x := suite.Scalar().Pick(suite.RandomStream())
+	y := suite.Scalar().Pick(suite.RandomStream())
+	B := suite.Point().Base()
+	X := suite.Point().Mul(x, nil)
+	Y := suite.Point().Mul(y, X)
+	R := suite.Point().Add(X, Y)
+	sval := map[string]kyber.Scalar{"x": x, "y": y}
+	pval := map[string]kyber.Point{"B": B, "X": X, "Y": Y, "R": R}
+
+	rep := Rep("X", "x", "B")
+	and := And(rep, Rep("R", "x", "B", "y", "X"))
+	or := Or(Rep("Y", "x", "B"), And(and))
+	tests := []struct {
+		pred      Predicate
+		choice    map[Predicate]int
+		canonical string
+	}{
+		{rep, nil, "Rep(X,x,B)"},
+		{and, nil, "And(Rep(X,x,B),Rep(R,x,B,y,X))"},
+		{or, map[Predicate]int{or: 1}, "Or(Rep(Y,x,B),And(And(Rep(X,x,B),Rep(R,x,B,y,X))))"},
+	}
+	for _, test := range tests {
+		prf, err := HashProve(suite, "TEST", test.pred.Prover(suite, sval, pval, test.choice))
+		require.NoError(t, err)
+		canonical, err := Canonical(test.pred)
+		require.NoError(t, err)
+		require.Equal(t, test.canonical, canonical)
+
+		env, err := Decode(Encode(suite.String(), canonical, prf))
+		require.NoError(t, err)
+		require.Equal(t, suite.String(), env.SuiteName)
+		require.Equal(t, test.pred.String(), env.Predicate.String())
+		require.Equal(t, prf, env.Proof)
+		again, err := Canonical(env.Predicate)
+		require.NoError(t, err)
+		require.Equal(t, canonical, again)
+
+		require.NoError(t, env.Verify("TEST", pval))
+		require.Error(t, env.Verify("OTHER", pval))
+		wrong := map[string]kyber.Point{"B": B, "X": Y, "Y": X, "R": R}
+		require.Error(t, env.Verify("TEST", wrong))
+	}
+}
+
+func TestEnvelopeDecodeErrors(t *testing.T) {
+	_, err := Decode(nil)
+	require.Equal(t, errEnvelopeVersion, err)
+	env := Encode("Ed25519", "Rep(X,x,B)", []byte{1, 2, 3})
+	_, err = Decode(append([]byte{EnvelopeVersion1 + 1}, env[1:]...))
+	require.Equal(t, errEnvelopeVersion, err)
+	_, err = Decode(env[:len(env)-1])
+	require.Equal(t, errEnvelopeFormat, err)
+	_, err = Decode(append(env, 0))
+	require.Equal(t, errEnvelopeFormat, err)
+
+	_, err = Decode(Encode("unknown", "Rep(X,x,B)", nil))
+	require.True(t, errors.Is(err, suites.ErrUnknownSuite))
+	_, err = Decode(Encode("Ed25519", "Rep(X,x)", nil))
+	require.Error(t, err)
+}
+
+func TestParsePredicate(t *testing.T) {
+	pred, err := ParsePredicate(" Or( Rep(X, x, B) ,And(Rep(P.1,x_1,B)) ) ")
+	require.NoError(t, err)
+	c, err := Canonical(pred)
+	require.NoError(t, err)
+	require.Equal(t, "Or(Rep(X,x,B),And(Rep(P.1,x_1,B)))", c)
+
+	malformed := []struct {
+		s   string
+		pos int
+	}{
+		{"", 0},
+		{"Rep", 3},
+		{"Rep(X,x)", 0},
+		{"And(Rep(X,x))", 4},
+		{"Rep(X,x,B", 9},
+		{"Rep(X,,B)", 6},
+		{"Rep(X,x,B))", 10},
+		{"Xor(Rep(X,x,B))", 0},
+		{"And()", 4},
+		{"And(Rep(X,x,B);Rep(Y,y,B))", 14},
+		{"Or(Rep(X,x,B),Foo(Y))", 14},
+		{"Rep(X=x*B)", 5},
+	}
+	for _, m := range malformed {
+		_, err := ParsePredicate(m.s)
+		require.Error(t, err, m.s)
+		perr, ok := err.(*ParseError)
+		require.True(t, ok, m.s)
+		require.Equal(t, m.pos, perr.Pos, "%s: %v", m.s, err)
+	}
+
+	_, err = Canonical(Rep("X=Y", "x", "B"))
+	require.Error(t, err)
+}
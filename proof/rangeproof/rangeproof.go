@@ -0,0 +1,582 @@
+// Package rangeproof implements the range proofs of "Bulletproofs: Short
+// Proofs for Confidential Transactions and More" by Bünz et al. A range proof
+// shows that the Pedersen commitments V_j = v_j*G + gamma_j*H commit to
+// values v_j in [0, 2^n), without revealing them. The proof of m values,
+// aggregated into a single proof, holds 2*log2(n*m) + 4 points and 5 scalars
+// thanks to the inner-product argument.
+//
+// The generators are derived with the commit package, so that nobody knows
+// their discrete logarithms, and the proofs are non-interactive with the
+// Fiat-Shamir transform. The verification checks all the equations of a
+// proof at once with a single multi-scalar multiplication of kyber/util/msm,
+// which the prover only uses on values that do not depend on its secrets.
+//
+// The proofs work over any group whose generators the commit package can
+// derive, e.g. edwards25519; kyber has no ristretto255 group.
+package rangeproof
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/commit"
+	"go.dedis.ch/kyber/v3/util/msm"
+)
+
+// Suite wraps the functionalities needed by the rangeproof package.
+type Suite interface {
+	kyber.Group
+	kyber.HashFactory
+	kyber.Encoding
+	kyber.XOFFactory
+	kyber.Random
+}
+
+// ErrInvalidProof is returned by Verify for a proof that does not verify.
+var ErrInvalidProof = errors.New("rangeproof: invalid proof")
+
+var (
+	pedersenDomain = []byte("kyber.rangeproof.pedersen")
+	vectorDomain   = []byte("kyber.rangeproof.vectors")
+	proofDomain    = []byte("kyber.rangeproof.proof")
+)
+
+// Params are the parameters of the range proofs of values of a number of
+// bits, aggregated by up to a number of parties.
+type Params struct {
+	suite   Suite
+	bits    int
+	parties int
+	G, H    kyber.Point   // bases of the values and of the blindings
+	gs, hs  []kyber.Point // bases of the vectors of the inner products
+}
+
+// NewParams returns the parameters of the proofs that values are in
+// [0, 2^bits), for a number of bits which is a power of 2 up to 64, for
+// proofs aggregating up to the given number of values.
+func NewParams(suite Suite, bits, parties int) (*Params, error) {
+	if bits < 1 || bits > 64 || bits&(bits-1) != 0 {
+		return nil, errors.New("rangeproof: the number of bits must be a power of 2 up to 64")
+	}
+	if parties < 1 {
+		return nil, errors.New("rangeproof: at least one party is needed")
+	}
+	parties = nextPow2(parties)
+	ped, err := commit.NewGenerators(suite, 1, pedersenDomain)
+	if err != nil {
+		return nil, err
+	}
+	vec, err := commit.NewGenerators(suite, 2*bits*parties, vectorDomain)
+	if err != nil {
+		return nil, err
+	}
+	return &Params{
+		suite:   suite,
+		bits:    bits,
+		parties: parties,
+		G:       ped.G[0],
+		H:       ped.H,
+		gs:      vec.G[:bits*parties],
+		hs:      vec.G[bits*parties:],
+	}, nil
+}
+
+// Commit returns the Pedersen commitment v*G + gamma*H to the value v with the
+// blinding gamma.
+func (p *Params) Commit(v uint64, gamma kyber.Scalar) kyber.Point {
+	V := p.suite.Point().Mul(gamma, p.H)
+	return V.Add(V, p.suite.Point().Mul(p.scalar(v), p.G))
+}
+
+// Proof is an aggregated range proof.
+type Proof struct {
+	// commitments to the bits of the values and to the blinding vectors
+	A, S kyber.Point
+	// commitments to the coefficients of the polynomial t(X)
+	T1, T2 kyber.Point
+	// blinding of t(x), blinding of A and S, and t(x) at the challenge x
+	TauX, Mu, THat kyber.Scalar
+	// the inner-product argument: the points of each round and the final
+	// scalars a and b
+	L, R           []kyber.Point
+	InnerA, InnerB kyber.Scalar
+}
+
+// Prove returns the proof that the values are in the range of the parameters,
+// together with their commitments with the given blindings. The proof
+// verifies with the commitments in the same order.
+func (p *Params) Prove(values []uint64, gammas []kyber.Scalar) (*Proof, []kyber.Point, error) {
+	if len(values) != len(gammas) {
+		return nil, nil, errors.New("rangeproof: as many blindings as values are needed")
+	}
+	if len(values) == 0 || len(values) > p.parties {
+		return nil, nil, errors.New("rangeproof: invalid number of values")
+	}
+	commits := make([]kyber.Point, len(values))
+	for j, v := range values {
+		if p.bits < 64 && v>>uint(p.bits) != 0 {
+			return nil, nil, errors.New("rangeproof: value out of range")
+		}
+		commits[j] = p.Commit(v, gammas[j])
+	}
+
+	suite := p.suite
+	m := nextPow2(len(values))
+	n := p.bits
+	N := n * m
+	gs, hs := p.gs[:N], p.hs[:N]
+	rand := suite.RandomStream()
+	tr, err := p.newTranscript(commits, m)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// A = alpha*H + <aL, gs> + <aR, hs> with the bits aL of the values and
+	// aR = aL - 1
+	one := suite.Scalar().One()
+	aL := make([]kyber.Scalar, N)
+	aR := make([]kyber.Scalar, N)
+	for j := 0; j < m; j++ {
+		var v uint64
+		if j < len(values) {
+			v = values[j]
+		}
+		for k := 0; k < n; k++ {
+			aL[j*n+k] = p.scalar((v >> uint(k)) & 1)
+			aR[j*n+k] = suite.Scalar().Sub(aL[j*n+k], one)
+		}
+	}
+	alpha := suite.Scalar().Pick(rand)
+	A := suite.Point().Mul(alpha, p.H)
+	A.Add(A, mulSum(suite, concat(aL, aR), concatPoints(gs, hs)))
+
+	// S = rho*H + <sL, gs> + <sR, hs> with random blinding vectors
+	sL := randomScalars(suite, N)
+	sR := randomScalars(suite, N)
+	rho := suite.Scalar().Pick(rand)
+	S := suite.Point().Mul(rho, p.H)
+	S.Add(S, mulSum(suite, concat(sL, sR), concatPoints(gs, hs)))
+
+	if err := tr.append(A, S); err != nil {
+		return nil, nil, err
+	}
+	y := tr.challenge()
+	z := tr.challenge()
+
+	// l(X) = l0 + l1*X and r(X) = r0 + r1*X with
+	// l0 = aL - z, l1 = sL,
+	// r0 = y^N o (aR + z) + sum_j z^(2+j) * (0^(j*n) || 2^n || 0^((m-j-1)*n)),
+	// r1 = y^N o sR
+	yPow := powers(suite, y, N)
+	zPow := powers(suite, z, m+3)
+	two := powers(suite, p.scalar(2), n)
+	l0 := make([]kyber.Scalar, N)
+	r0 := make([]kyber.Scalar, N)
+	r1 := make([]kyber.Scalar, N)
+	for i := 0; i < N; i++ {
+		j, k := i/n, i%n
+		l0[i] = suite.Scalar().Sub(aL[i], z)
+		r0[i] = suite.Scalar().Add(aR[i], z)
+		r0[i].Mul(r0[i], yPow[i])
+		r0[i].Add(r0[i], suite.Scalar().Mul(zPow[2+j], two[k]))
+		r1[i] = suite.Scalar().Mul(yPow[i], sR[i])
+	}
+
+	// t(X) = <l(X), r(X)> = t0 + t1*X + t2*X^2
+	t1 := suite.Scalar().Add(innerProduct(suite, l0, r1), innerProduct(suite, sL, r0))
+	t2 := innerProduct(suite, sL, r1)
+	tau1 := suite.Scalar().Pick(rand)
+	tau2 := suite.Scalar().Pick(rand)
+	T1 := suite.Point().Add(suite.Point().Mul(t1, p.G), suite.Point().Mul(tau1, p.H))
+	T2 := suite.Point().Add(suite.Point().Mul(t2, p.G), suite.Point().Mul(tau2, p.H))
+	if err := tr.append(T1, T2); err != nil {
+		return nil, nil, err
+	}
+	x := tr.challenge()
+
+	l := make([]kyber.Scalar, N)
+	r := make([]kyber.Scalar, N)
+	for i := range l {
+		l[i] = suite.Scalar().Add(l0[i], suite.Scalar().Mul(sL[i], x))
+		r[i] = suite.Scalar().Add(r0[i], suite.Scalar().Mul(r1[i], x))
+	}
+	tHat := innerProduct(suite, l, r)
+	// tau_x = tau2*x^2 + tau1*x + sum_j z^(2+j)*gamma_j
+	tauX := suite.Scalar().Mul(tau2, x)
+	tauX.Add(tauX, tau1).Mul(tauX, x)
+	for j, gamma := range gammas {
+		tauX.Add(tauX, suite.Scalar().Mul(zPow[2+j], gamma))
+	}
+	mu := suite.Scalar().Add(alpha, suite.Scalar().Mul(rho, x))
+	if err := tr.append(tauX, mu, tHat); err != nil {
+		return nil, nil, err
+	}
+	w := tr.challenge()
+
+	// inner-product argument for <l, gs> + <r, hs'> + <l, r>*Q with
+	// hs'_i = y^-i * hs_i and Q = w*G
+	yInv := powers(suite, suite.Scalar().Inv(y), N)
+	hsp := make([]kyber.Point, N)
+	for i := range hsp {
+		hsp[i] = suite.Point().Mul(yInv[i], hs[i])
+	}
+	Q := suite.Point().Mul(w, p.G)
+	proof := &Proof{A: A, S: S, T1: T1, T2: T2, TauX: tauX, Mu: mu, THat: tHat}
+	if err := p.proveInnerProduct(tr, proof, concatPoints(gs), hsp, Q, l, r); err != nil {
+		return nil, nil, err
+	}
+	return proof, commits, nil
+}
+
+// proveInnerProduct runs the rounds of the inner-product argument, halving
+// the vectors at each round, and sets the points L and R of the rounds and
+// the final scalars of the proof.
+func (p *Params) proveInnerProduct(tr *transcript, proof *Proof, G, H []kyber.Point, Q kyber.Point, a, b []kyber.Scalar) error {
+	suite := p.suite
+	for n := len(a); n > 1; n /= 2 {
+		h := n / 2
+		cL := innerProduct(suite, a[:h], b[h:])
+		cR := innerProduct(suite, a[h:], b[:h])
+		// l and r hide the values as well as the proof, so that L and R can
+		// use the variable time multi-scalar multiplication
+		L, err := msm.MultiScalarMult(suite, concatPoints(G[h:], H[:h], []kyber.Point{Q}), concat(a[:h], b[h:], []kyber.Scalar{cL}))
+		if err != nil {
+			return err
+		}
+		R, err := msm.MultiScalarMult(suite, concatPoints(G[:h], H[h:], []kyber.Point{Q}), concat(a[h:], b[:h], []kyber.Scalar{cR}))
+		if err != nil {
+			return err
+		}
+		if err := tr.append(L, R); err != nil {
+			return err
+		}
+		proof.L = append(proof.L, L)
+		proof.R = append(proof.R, R)
+		u := tr.challenge()
+		uInv := suite.Scalar().Inv(u)
+		for i := 0; i < h; i++ {
+			// a' = u*a_lo + u^-1*a_hi, b' = u^-1*b_lo + u*b_hi
+			// G' = u^-1*G_lo + u*G_hi, H' = u*H_lo + u^-1*H_hi
+			a[i] = suite.Scalar().Add(suite.Scalar().Mul(u, a[i]), suite.Scalar().Mul(uInv, a[h+i]))
+			b[i] = suite.Scalar().Add(suite.Scalar().Mul(uInv, b[i]), suite.Scalar().Mul(u, b[h+i]))
+			G[i] = suite.Point().Add(suite.Point().Mul(uInv, G[i]), suite.Point().Mul(u, G[h+i]))
+			H[i] = suite.Point().Add(suite.Point().Mul(u, H[i]), suite.Point().Mul(uInv, H[h+i]))
+		}
+		a, b, G, H = a[:h], b[:h], G[:h], H[:h]
+	}
+	proof.InnerA, proof.InnerB = a[0], b[0]
+	return nil
+}
+
+// Verify checks the proof that the commitments commit to values in the range
+// of the parameters. It returns ErrInvalidProof if the proof does not verify.
+func (p *Params) Verify(commits []kyber.Point, proof *Proof) error {
+	if len(commits) == 0 || len(commits) > p.parties {
+		return errors.New("rangeproof: invalid number of commitments")
+	}
+	suite := p.suite
+	m := nextPow2(len(commits))
+	n := p.bits
+	N := n * m
+	rounds := 0
+	for 1<<uint(rounds) < N {
+		rounds++
+	}
+	if proof == nil || len(proof.L) != rounds || len(proof.R) != rounds ||
+		proof.A == nil || proof.S == nil || proof.T1 == nil || proof.T2 == nil ||
+		proof.TauX == nil || proof.Mu == nil || proof.THat == nil ||
+		proof.InnerA == nil || proof.InnerB == nil {
+		return ErrInvalidProof
+	}
+
+	tr, err := p.newTranscript(commits, m)
+	if err != nil {
+		return err
+	}
+	if err := tr.append(proof.A, proof.S); err != nil {
+		return err
+	}
+	y := tr.challenge()
+	z := tr.challenge()
+	if err := tr.append(proof.T1, proof.T2); err != nil {
+		return err
+	}
+	x := tr.challenge()
+	if err := tr.append(proof.TauX, proof.Mu, proof.THat); err != nil {
+		return err
+	}
+	w := tr.challenge()
+	u := make([]kyber.Scalar, rounds)
+	for j := range u {
+		if err := tr.append(proof.L[j], proof.R[j]); err != nil {
+			return err
+		}
+		u[j] = tr.challenge()
+	}
+
+	// s_i = prod_j u_j^(+1 or -1) according to the half of round j the
+	// index i was in, i.e. the bit rounds-1-j of i
+	uInv := make([]kyber.Scalar, rounds)
+	for j := range u {
+		uInv[j] = suite.Scalar().Inv(u[j])
+	}
+	s := make([]kyber.Scalar, N)
+	sInv := make([]kyber.Scalar, N)
+	for i := range s {
+		s[i] = suite.Scalar().One()
+		sInv[i] = suite.Scalar().One()
+		for j := 0; j < rounds; j++ {
+			if (i>>uint(rounds-1-j))&1 == 1 {
+				s[i].Mul(s[i], u[j])
+				sInv[i].Mul(sInv[i], uInv[j])
+			} else {
+				s[i].Mul(s[i], uInv[j])
+				sInv[i].Mul(sInv[i], u[j])
+			}
+		}
+	}
+
+	yPow := powers(suite, y, N)
+	yInv := powers(suite, suite.Scalar().Inv(y), N)
+	zPow := powers(suite, z, m+3)
+	two := powers(suite, p.scalar(2), n)
+
+	// delta(y, z) = (z - z^2)*<1, y^N> - sum_j z^(3+j)*<1, 2^n>
+	sumY := sum(suite, yPow)
+	sumTwo := sum(suite, two)
+	delta := suite.Scalar().Sub(z, zPow[2])
+	delta.Mul(delta, sumY)
+	for j := 0; j < m; j++ {
+		delta.Sub(delta, suite.Scalar().Mul(zPow[3+j], sumTwo))
+	}
+
+	// The proof is valid if both
+	//   tHat*G + tauX*H = sum_j z^(2+j)*V_j + delta*G + x*T1 + x^2*T2
+	//   A + x*S - mu*H - z*<1, gs> + <z*y^N + z^(2+j)*2^n, hs'> + tHat*Q
+	//     + sum_j (u_j^2*L_j + u_j^-2*R_j) = <a*s, gs> + <b*s^-1, hs'> + a*b*Q
+	// hold. They are checked at once as the sum of a random multiple c of
+	// the first one and of the second one.
+	c := suite.Scalar().Pick(suite.RandomStream())
+	x2 := suite.Scalar().Mul(x, x)
+	ab := suite.Scalar().Mul(proof.InnerA, proof.InnerB)
+
+	var scalars []kyber.Scalar
+	var points []kyber.Point
+	add := func(s kyber.Scalar, P kyber.Point) {
+		scalars = append(scalars, s)
+		points = append(points, P)
+	}
+	// G: c*(tHat - delta) + w*(tHat - a*b)
+	gc := suite.Scalar().Sub(proof.THat, delta)
+	gc.Mul(gc, c)
+	add(gc.Add(gc, suite.Scalar().Mul(w, suite.Scalar().Sub(proof.THat, ab))), p.G)
+	// H: c*tauX - mu
+	hc := suite.Scalar().Mul(c, proof.TauX)
+	add(hc.Sub(hc, proof.Mu), p.H)
+	for j, V := range commits {
+		add(suite.Scalar().Neg(suite.Scalar().Mul(c, zPow[2+j])), V)
+	}
+	add(suite.Scalar().Neg(suite.Scalar().Mul(c, x)), proof.T1)
+	add(suite.Scalar().Neg(suite.Scalar().Mul(c, x2)), proof.T2)
+	add(suite.Scalar().One(), proof.A)
+	add(x, proof.S)
+	for j := range u {
+		add(suite.Scalar().Mul(u[j], u[j]), proof.L[j])
+		add(suite.Scalar().Mul(uInv[j], uInv[j]), proof.R[j])
+	}
+	for i := 0; i < N; i++ {
+		// gs_i: -z - a*s_i
+		gi := suite.Scalar().Mul(proof.InnerA, s[i])
+		add(gi.Neg(gi).Sub(gi, z), p.gs[i])
+		// hs_i: z + y^-i*(z^(2+j)*2^k - b*s_i^-1)
+		hi := suite.Scalar().Mul(zPow[2+i/n], two[i%n])
+		hi.Sub(hi, suite.Scalar().Mul(proof.InnerB, sInv[i]))
+		hi.Mul(hi, yInv[i])
+		add(hi.Add(hi, z), p.hs[i])
+	}
+	res, err := msm.MultiScalarMult(suite, points, scalars)
+	if err != nil {
+		return err
+	}
+	if !res.Equal(suite.Point().Null()) {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// MarshalBinary encodes the proof as A || S || T1 || T2 || TauX || Mu || THat
+// || InnerA || InnerB followed by the points L and R of each round.
+func (proof *Proof) MarshalBinary() ([]byte, error) {
+	ms := []kyber.Marshaling{proof.A, proof.S, proof.T1, proof.T2, proof.TauX, proof.Mu, proof.THat, proof.InnerA, proof.InnerB}
+	for j := range proof.L {
+		ms = append(ms, proof.L[j], proof.R[j])
+	}
+	var b []byte
+	for _, m := range ms {
+		buf, err := m.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		b = append(b, buf...)
+	}
+	return b, nil
+}
+
+// UnmarshalProof decodes a proof encoded with MarshalBinary.
+func (p *Params) UnmarshalProof(b []byte) (*Proof, error) {
+	suite := p.suite
+	pl, sl := suite.PointLen(), suite.ScalarLen()
+	fixed := 4*pl + 5*sl
+	if len(b) < fixed || (len(b)-fixed)%(2*pl) != 0 {
+		return nil, errors.New("rangeproof: proof of invalid length")
+	}
+	rounds := (len(b) - fixed) / (2 * pl)
+	proof := &Proof{
+		A: suite.Point(), S: suite.Point(), T1: suite.Point(), T2: suite.Point(),
+		TauX: suite.Scalar(), Mu: suite.Scalar(), THat: suite.Scalar(),
+		InnerA: suite.Scalar(), InnerB: suite.Scalar(),
+		L: make([]kyber.Point, rounds), R: make([]kyber.Point, rounds),
+	}
+	ms := []kyber.Marshaling{proof.A, proof.S, proof.T1, proof.T2, proof.TauX, proof.Mu, proof.THat, proof.InnerA, proof.InnerB}
+	for j := 0; j < rounds; j++ {
+		proof.L[j], proof.R[j] = suite.Point(), suite.Point()
+		ms = append(ms, proof.L[j], proof.R[j])
+	}
+	for _, m := range ms {
+		l := m.MarshalSize()
+		if err := m.UnmarshalBinary(b[:l]); err != nil {
+			return nil, err
+		}
+		b = b[l:]
+	}
+	return proof, nil
+}
+
+// transcript is the Fiat-Shamir transcript of a proof: each challenge is
+// derived from all the elements and challenges before it.
+type transcript struct {
+	suite Suite
+	h     hash.Hash
+}
+
+// newTranscript starts the transcript of the proof of the commitments, padded
+// with the identity to m commitments.
+func (p *Params) newTranscript(commits []kyber.Point, m int) (*transcript, error) {
+	tr := &transcript{suite: p.suite, h: p.suite.Hash()}
+	_, _ = tr.h.Write(proofDomain)
+	var buf [8]byte
+	binary.BigEndian.PutUint32(buf[:4], uint32(p.bits))
+	binary.BigEndian.PutUint32(buf[4:], uint32(m))
+	_, _ = tr.h.Write(buf[:])
+	for j := 0; j < m; j++ {
+		V := p.suite.Point().Null()
+		if j < len(commits) {
+			V = commits[j]
+		}
+		if V == nil {
+			return nil, errors.New("rangeproof: nil commitment")
+		}
+		if err := tr.append(V); err != nil {
+			return nil, err
+		}
+	}
+	return tr, nil
+}
+
+func (tr *transcript) append(ms ...kyber.Marshaling) error {
+	for _, m := range ms {
+		if _, err := m.MarshalTo(tr.h); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (tr *transcript) challenge() kyber.Scalar {
+	c := tr.suite.Scalar().Pick(tr.suite.XOF(tr.h.Sum(nil)))
+	_, _ = c.MarshalTo(tr.h)
+	return c
+}
+
+// scalar returns the scalar of the integer v.
+func (p *Params) scalar(v uint64) kyber.Scalar {
+	// SetInt64 of the two halves, as v may not fit in an int64
+	s := p.suite.Scalar().SetInt64(int64(v >> 32))
+	s.Mul(s, p.suite.Scalar().SetInt64(1<<32))
+	return s.Add(s, p.suite.Scalar().SetInt64(int64(v&0xffffffff)))
+}
+
+func nextPow2(n int) int {
+	p := 1
+	for p < n {
+		p *= 2
+	}
+	return p
+}
+
+// powers returns x^0, ..., x^(n-1).
+func powers(g kyber.Group, x kyber.Scalar, n int) []kyber.Scalar {
+	p := make([]kyber.Scalar, n)
+	if n == 0 {
+		return p
+	}
+	p[0] = g.Scalar().One()
+	for i := 1; i < n; i++ {
+		p[i] = g.Scalar().Mul(p[i-1], x)
+	}
+	return p
+}
+
+func sum(g kyber.Group, xs []kyber.Scalar) kyber.Scalar {
+	s := g.Scalar().Zero()
+	for _, x := range xs {
+		s.Add(s, x)
+	}
+	return s
+}
+
+// mulSum returns sum_i scalars[i]*points[i] with the scalar multiplications
+// of the group, for secret scalars.
+func mulSum(g kyber.Group, scalars []kyber.Scalar, points []kyber.Point) kyber.Point {
+	res := g.Point().Null()
+	tmp := g.Point()
+	for i, P := range points {
+		res.Add(res, tmp.Mul(scalars[i], P))
+	}
+	return res
+}
+
+func innerProduct(g kyber.Group, a, b []kyber.Scalar) kyber.Scalar {
+	s := g.Scalar().Zero()
+	tmp := g.Scalar()
+	for i := range a {
+		s.Add(s, tmp.Mul(a[i], b[i]))
+	}
+	return s
+}
+
+func randomScalars(suite Suite, n int) []kyber.Scalar {
+	s := make([]kyber.Scalar, n)
+	for i := range s {
+		s[i] = suite.Scalar().Pick(suite.RandomStream())
+	}
+	return s
+}
+
+func concat(vs ...[]kyber.Scalar) []kyber.Scalar {
+	var r []kyber.Scalar
+	for _, v := range vs {
+		r = append(r, v...)
+	}
+	return r
+}
+
+func concatPoints(vs ...[]kyber.Point) []kyber.Point {
+	var r []kyber.Point
+	for _, v := range vs {
+		r = append(r, v...)
+	}
+	return r
+}
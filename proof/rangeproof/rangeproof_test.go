@@ -0,0 +1,127 @@
+package rangeproof
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+)
+
+var suite = edwards25519.NewBlakeSHA256Ed25519()
+
+func TestRangeProof(t *testing.T) {
+	params, err := NewParams(suite, 32, 1)
+	require.NoError(t, err)
+	for _, v := range []uint64{0, 1, 42, 1<<32 - 1} {
+		gamma := suite.Scalar().Pick(suite.RandomStream())
+		proof, commits, err := params.Prove([]uint64{v}, []kyber.Scalar{gamma})
+		require.NoError(t, err)
+		require.True(t, commits[0].Equal(params.Commit(v, gamma)))
+		require.NoError(t, params.Verify(commits, proof), "v = %d", v)
+		require.Len(t, proof.L, 5)
+
+		b, err := proof.MarshalBinary()
+		require.NoError(t, err)
+		require.Len(t, b, (4+2*5)*suite.PointLen()+5*suite.ScalarLen())
+		decoded, err := params.UnmarshalProof(b)
+		require.NoError(t, err)
+		require.NoError(t, params.Verify(commits, decoded))
+	}
+
+	_, _, err = params.Prove([]uint64{1 << 32}, []kyber.Scalar{suite.Scalar().One()})
+	require.Error(t, err)
+}
+
+func TestRangeProof64(t *testing.T) {
+	params, err := NewParams(suite, 64, 1)
+	require.NoError(t, err)
+	gamma := suite.Scalar().Pick(suite.RandomStream())
+	proof, commits, err := params.Prove([]uint64{1<<64 - 1}, []kyber.Scalar{gamma})
+	require.NoError(t, err)
+	require.NoError(t, params.Verify(commits, proof))
+}
+
+func TestAggregatedRangeProof(t *testing.T) {
+	params, err := NewParams(suite, 16, 4)
+	require.NoError(t, err)
+	// 3 values are padded to 4
+	values := []uint64{7, 0, 1<<16 - 1}
+	gammas := []kyber.Scalar{
+		suite.Scalar().Pick(suite.RandomStream()),
+		suite.Scalar().Pick(suite.RandomStream()),
+		suite.Scalar().Pick(suite.RandomStream()),
+	}
+	proof, commits, err := params.Prove(values, gammas)
+	require.NoError(t, err)
+	require.NoError(t, params.Verify(commits, proof))
+	require.Len(t, proof.L, 6)
+
+	// the commitments must be in the same order
+	swapped := []kyber.Point{commits[1], commits[0], commits[2]}
+	require.Equal(t, ErrInvalidProof, params.Verify(swapped, proof))
+	require.Error(t, params.Verify(commits[:2], proof))
+
+	_, _, err = params.Prove([]uint64{1, 2, 3, 4, 5}, make([]kyber.Scalar, 5))
+	require.Error(t, err)
+}
+
+func TestRangeProofInvalid(t *testing.T) {
+	params, err := NewParams(suite, 8, 1)
+	require.NoError(t, err)
+	gamma := suite.Scalar().Pick(suite.RandomStream())
+	proof, commits, err := params.Prove([]uint64{200}, []kyber.Scalar{gamma})
+	require.NoError(t, err)
+
+	// another commitment
+	require.Equal(t, ErrInvalidProof, params.Verify([]kyber.Point{params.Commit(201, gamma)}, proof))
+
+	// a commitment to a value out of range cannot be proven: shift the
+	// commitment of 200 by 2^8 * G, i.e. commit to 456
+	out := suite.Point().Add(commits[0], suite.Point().Mul(suite.Scalar().SetInt64(256), params.G))
+	require.Equal(t, ErrInvalidProof, params.Verify([]kyber.Point{out}, proof))
+
+	// tampered proofs
+	bad := *proof
+	bad.THat = suite.Scalar().Add(proof.THat, suite.Scalar().One())
+	require.Equal(t, ErrInvalidProof, params.Verify(commits, &bad))
+	bad = *proof
+	bad.InnerA = suite.Scalar().Add(proof.InnerA, suite.Scalar().One())
+	require.Equal(t, ErrInvalidProof, params.Verify(commits, &bad))
+	bad = *proof
+	bad.L = append([]kyber.Point{proof.R[0]}, proof.L[1:]...)
+	require.Equal(t, ErrInvalidProof, params.Verify(commits, &bad))
+	bad = *proof
+	bad.L = proof.L[1:]
+	require.Equal(t, ErrInvalidProof, params.Verify(commits, &bad))
+	bad = *proof
+	bad.Mu = nil
+	require.Equal(t, ErrInvalidProof, params.Verify(commits, &bad))
+
+	_, err = params.UnmarshalProof(make([]byte, 10))
+	require.Error(t, err)
+
+	_, err = NewParams(suite, 12, 1)
+	require.Error(t, err)
+	_, err = NewParams(suite, 128, 1)
+	require.Error(t, err)
+}
+
+func BenchmarkProve64(b *testing.B) {
+	params, _ := NewParams(suite, 64, 1)
+	gamma := suite.Scalar().Pick(suite.RandomStream())
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _, _ = params.Prove([]uint64{1234}, []kyber.Scalar{gamma})
+	}
+}
+
+func BenchmarkVerify64(b *testing.B) {
+	params, _ := NewParams(suite, 64, 1)
+	gamma := suite.Scalar().Pick(suite.RandomStream())
+	proof, commits, _ := params.Prove([]uint64{1234}, []kyber.Scalar{gamma})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = params.Verify(commits, proof)
+	}
+}
@@ -11,13 +11,20 @@
 package dkg
 
 import (
+	"bytes"
 	"crypto/rand"
+	"encoding"
 	"errors"
 	"fmt"
 	"io"
+	"runtime"
+	"sort"
+	"sync"
 
 	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/util/key"
 	"go.dedis.ch/kyber/v3/util/random"
+	"go.dedis.ch/kyber/v3/util/wipe"
 
 	"go.dedis.ch/kyber/v3/share"
 	vss "go.dedis.ch/kyber/v3/share/vss/pedersen"
@@ -36,7 +43,18 @@ type Suite vss.Suite
 // with the current share of the node. If the node using this config is a new
 // addition and thus has no current share, the PublicCoeffs field be must be
 // filled in.
+//
+// When resharing, the role of a node depends on the lists it belongs to. A
+// node only in OldNodes is dealer-only (see DistKeyGenerator.DealerOnly): it
+// issues deals and processes the responses to learn whether the new nodes
+// accepted them, but never receives a deal nor a share. A node only in
+// NewNodes never issues deals and only receives a new share. A node in both
+// lists does both. The lists may be completely disjoint, so that the whole
+// group is replaced.
 type Config struct {
+	// Suite is the group of the longterm keys and of the commitments of the
+	// distributed key, see DistKeyShare.Group. With a pairing suite, the
+	// group is chosen with pairing.G1Suite or pairing.G2Suite.
 	Suite Suite
 
 	// Longterm is the longterm secret key.
@@ -63,14 +81,17 @@ type Config struct {
 	// Share to refresh. It must be nil for a new node wishing to
 	// join or create a group. To be able to issue new fresh shares to a new group,
 	// one's share must be specified here, along with the public key inside the
-	// OldNodes field.
+	// OldNodes field. NewDistKeyHandler checks the share against its
+	// commitments, and against PublicCoeffs if both are given, and fails
+	// with ErrInconsistentShare if it is corrupted.
 	Share *DistKeyShare
 
 	// The threshold to use in order to reconstruct the secret with the produced
 	// shares. This threshold is with respect to the number of nodes in the
-	// NewNodes list. If unspecified, default is set to
-	// `vss.MinimumT(len(NewNodes))`. This threshold indicates the degree of the
-	// polynomials used to create the shares, and the minimum number of
+	// NewNodes list, or to the total weight of the nodes with Weights. If
+	// unspecified, default is set to `vss.MinimumT(len(NewNodes))`, or to
+	// vss.MinimumT of the total weight. This threshold indicates the degree of
+	// the polynomials used to create the shares, and the minimum number of
 	// verification required for each deal.
 	Threshold int
 
@@ -92,10 +113,68 @@ type Config struct {
 	// When UserReaderOnly it set to true, only the user-specified entropy source
 	// Reader will be used. This should only be used in tests, allowing reproducibility.
 	UserReaderOnly bool
+
+	// UseHashedIndices indicates that the shares must be evaluated at an index
+	// derived by hashing the public key of each node (see share.HashIndex)
+	// instead of at its position in the NewNodes list. The resulting shares
+	// must be used with the XShare functions of the share package, e.g.
+	// share.RecoverSecretFromXShares. It is not supported for resharing.
+	UseHashedIndices bool
+
+	// Weights optionally gives the number of shares of each node of
+	// NewNodes, e.g. in proportion to its voting power: the node at index i
+	// holds Weights[i] shares of the distributed secret instead of one, see
+	// DistKeyShare.Shares. The shares are numbered in the order of the nodes,
+	// so that the shares of the node at index i follow the ones of the node
+	// at index i-1, as given by share.Weights, and the threshold is a
+	// threshold on the total weight of the nodes, see
+	// share.RecoverSecretWeighted. Each dealer issues one deal per share,
+	// all the deals of a node being encrypted under its longterm key. A nil
+	// list gives a weight of 1 to every node. It is not supported for
+	// resharing nor with UseHashedIndices.
+	Weights []int
+
+	// Nonce optionally binds the run of the protocol to a value agreed on by
+	// all the nodes, e.g. a counter or the identifier of an epoch, which must
+	// differ between the runs with the same keys. The session IDs of the
+	// deals are derived from it (see vss.Dealer.SetNonce), and the responses
+	// and justifications sign them, so that the messages of a previous or
+	// concurrent run cannot be replayed in this one: they are rejected as
+	// belonging to another session. All the nodes must be given the same
+	// nonce, including for ResumeDistKeyGenerator.
+	Nonce []byte
+
+	// AcceptLegacyDeals makes the node accept the deals of the previous
+	// releases, whose encryption does not authenticate their session ID and
+	// their index (see vss.EncryptedDealVersion), e.g. while some dealers are
+	// not upgraded yet. It is only meant for the migration and will be
+	// removed in the next release.
+	AcceptLegacyDeals bool
+
+	// OnJustification, if not nil, is called with each justification that
+	// ProcessResponse and ProcessResponses return, e.g. to broadcast it to
+	// the other nodes as soon as it exists instead of collecting the
+	// returned ones. It is called once the method has released the lock of
+	// the DistKeyGenerator, so that it may call its methods, and not for the
+	// messages replayed by ResumeDistKeyGenerator. A complaint received twice
+	// gives its justification to the callback twice.
+	OnJustification func(*Justification)
 }
 
 // DistKeyGenerator is the struct that runs the DKG protocol.
+//
+// A DistKeyGenerator is safe for concurrent use: its methods can be called
+// from several goroutines, e.g. one per peer of the transport, and are
+// serialized internally, so that the outcome only depends on the set of
+// messages processed and not on the order of the concurrent calls. The only
+// exception is Verifiers, which exposes the internal state. Receiving the
+// same deal or response twice, e.g. when several peers relay it, gives back
+// the result of the first call (see ProcessDeal and ProcessResponse).
 type DistKeyGenerator struct {
+	// serializes the calls to the exported methods, which call the
+	// unexported ones holding it
+	mu sync.Mutex
+
 	// config driving the behavior of DistKeyGenerator
 	c     *Config
 	suite Suite
@@ -130,13 +209,129 @@ type DistKeyGenerator struct {
 	processed bool
 	// did the timeout / period / already occured or not
 	timeout bool
+	// indicates whether Finish has been called, freezing the qualified set
+	finished bool
+	// dealer indexes of the qualified set frozen by Finish
+	qual map[uint32]bool
+	// number of deals received from other dealers and answered with a
+	// response, approval or complaint
+	receivedDeals int
+	// hashed evaluation points of the new nodes, nil for the default ones
+	xs []kyber.Scalar
+	// public keys of the holders of the shares, i.e. the new nodes each
+	// repeated as many times as its weight
+	holders []kyber.Point
+	// indices of the shares held by each new node
+	shareIndices [][]int
+	// verifiers of the shares of this node but the first one, indexed by
+	// dealer index. They follow the verifiers of the first share, which
+	// decide of the qualified set.
+	extraVerifiers map[uint32][]*vss.Verifier
+	// signed messages showing the misbehaviors seen so far, see Report
+	evidence *evidence
+	// deals processed without error, by dealer index, so that a deal
+	// received twice gives back the same response
+	processedDeals map[uint32]*processedDeal
+	// justifications of the complaints about the deal of this node, by index
+	// of the complaining verifier, so that a complaint received twice gives
+	// back the same justification
+	justified map[uint32]*justifiedComplaint
+	// distributed key share returned by DistKeyShare, computed only once
+	// since the secret material it is computed from is then erased
+	dks *DistKeyShare
+	// encoded deals returned by Deals, by share index, so that the same
+	// deals are sent again after a restart, see ExportState
+	issued map[int][]byte
+	// encoded messages which changed the state, in the order they were
+	// processed, replayed by ResumeDistKeyGenerator
+	log [][]byte
+}
+
+// processedDeal is a deal processed by ProcessDeal, in its binary encoding,
+// and the response returned for it, also in the encoding it had when it was
+// signed since a justification turns a complaint into an approval in place.
+type processedDeal struct {
+	deal     []byte
+	resp     *Response
+	response []byte
+}
+
+// justifiedComplaint is a complaint about the deal of this node and the
+// justification returned for it by ProcessResponse.
+type justifiedComplaint struct {
+	complaint *vss.Response
+	j         *Justification
+}
+
+// Errors returned by NewDistKeyHandler and NewDistKeyGenerator when the
+// configuration is invalid.
+var (
+	ErrNilSuite            = errors.New("dkg: nil suite")
+	ErrNilLongterm         = errors.New("dkg: nil longterm key")
+	ErrEmptyNodes          = errors.New("dkg: can't run with empty node list")
+	ErrDuplicateKey        = errors.New("dkg: duplicate public key")
+	ErrNilKey              = errors.New("dkg: nil public key")
+	ErrKeyNotFound         = errors.New("dkg: public key not found in old list or new list")
+	ErrInvalidThreshold    = errors.New("dkg: invalid threshold")
+	ErrInvalidOldThreshold = errors.New("dkg: invalid old threshold")
+	ErrInconsistentShare   = errors.New("dkg: share inconsistent with the old nodes")
+	ErrInvalidWeights      = errors.New("dkg: invalid weights")
+)
+
+// Errors returned when processing deals, responses and justifications. The
+// errors of the vss package are wrapped as well, so that both can be checked
+// with errors.Is.
+var (
+	// ErrMalformed is returned for a message missing one of its fields.
+	ErrMalformed = errors.New("dkg: malformed message")
+	// ErrDealerIndex is returned for a message designating a dealer which
+	// is not in the list of dealers.
+	ErrDealerIndex = errors.New("dkg: dealer index out of bounds")
+)
+
+// DuplicateKeyError is returned when a list of nodes of the configuration
+// contains the same public key twice. It matches ErrDuplicateKey with
+// errors.Is.
+type DuplicateKeyError struct {
+	List  string // "old" or "new"
+	First int    // index of the first occurrence of the key
+	Index int    // index of the duplicate
+}
+
+func (e *DuplicateKeyError) Error() string {
+	return fmt.Sprintf("%s at indices %d and %d of %s nodes list", ErrDuplicateKey, e.First, e.Index, e.List)
+}
+
+// Is returns true for ErrDuplicateKey.
+func (e *DuplicateKeyError) Is(target error) bool {
+	return target == ErrDuplicateKey
 }
 
 // NewDistKeyHandler takes a Config and returns a DistKeyGenerator that is able
-// to drive the DKG or resharing protocol.
+// to drive the DKG or resharing protocol. It returns one of the exported
+// errors above if the configuration is invalid.
 func NewDistKeyHandler(c *Config) (*DistKeyGenerator, error) {
+	return newDistKeyHandler(c, nil)
+}
+
+// newDistKeyHandler implements NewDistKeyHandler. If poly is not nil, the
+// dealer of the node deals it instead of a random polynomial, see
+// ResumeDistKeyGenerator.
+func newDistKeyHandler(c *Config, poly *share.PriPoly) (*DistKeyGenerator, error) {
+	if c.Suite == nil {
+		return nil, ErrNilSuite
+	}
+	if c.Longterm == nil {
+		return nil, ErrNilLongterm
+	}
 	if len(c.NewNodes) == 0 && len(c.OldNodes) == 0 {
-		return nil, errors.New("dkg: can't run with empty node list")
+		return nil, ErrEmptyNodes
+	}
+	if err := checkDuplicates("old", c.OldNodes); err != nil {
+		return nil, err
+	}
+	if err := checkDuplicates("new", c.NewNodes); err != nil {
+		return nil, err
 	}
 
 	var isResharing bool
@@ -150,6 +345,9 @@ func NewDistKeyHandler(c *Config) (*DistKeyGenerator, error) {
 		if c.OldThreshold == 0 {
 			return nil, errors.New("dkg: resharing case needs old threshold field")
 		}
+		if c.OldThreshold < 2 || c.OldThreshold > len(c.OldNodes) {
+			return nil, fmt.Errorf("%w %d for %d old nodes", ErrInvalidOldThreshold, c.OldThreshold, len(c.OldNodes))
+		}
 	}
 	// canReceive is true by default since in the default DKG mode everyone
 	// participates
@@ -158,24 +356,79 @@ func NewDistKeyHandler(c *Config) (*DistKeyGenerator, error) {
 	oidx, oldPresent := findPub(c.OldNodes, pub)
 	nidx, newPresent := findPub(c.NewNodes, pub)
 	if !oldPresent && !newPresent {
-		return nil, errors.New("dkg: public key not found in old list or new list")
+		return nil, ErrKeyNotFound
+	}
+	if c.Share != nil {
+		if !oldPresent || c.Share.Share == nil || c.Share.Share.I != oidx {
+			return nil, fmt.Errorf("%w: share index does not match the position in the old nodes list", ErrInconsistentShare)
+		}
+		if len(c.Share.Commits) != c.OldThreshold {
+			return nil, fmt.Errorf("%w: %d commitments for old threshold %d", ErrInconsistentShare, len(c.Share.Commits), c.OldThreshold)
+		}
+		// a corrupted share would make this node deal a fresh secret and
+		// change the distributed public key
+		if !share.NewPubPoly(c.Suite, nil, c.Share.Commits).Check(c.Share.Share) {
+			return nil, fmt.Errorf("%w: share does not match the old commitments", ErrInconsistentShare)
+		}
+		if c.PublicCoeffs != nil && (len(c.PublicCoeffs) == 0 || !c.PublicCoeffs[0].Equal(c.Share.Commits[0])) {
+			return nil, fmt.Errorf("%w: public coefficients of another distributed key", ErrInconsistentShare)
+		}
+	}
+
+	if c.Weights != nil && isResharing {
+		return nil, errors.New("dkg: weights are not supported for resharing")
+	}
+	if c.Weights != nil && c.UseHashedIndices {
+		return nil, errors.New("dkg: weights are not supported with hashed indices")
+	}
+	holders, shareIndices, err := expandWeights(c.NewNodes, c.Weights)
+	if err != nil {
+		return nil, err
 	}
 
 	var newThreshold int
 	if c.Threshold != 0 {
 		newThreshold = c.Threshold
 	} else {
-		newThreshold = vss.MinimumT(len(c.NewNodes))
+		newThreshold = vss.MinimumT(len(holders))
+	}
+	if newThreshold < 2 || newThreshold > len(holders) {
+		return nil, fmt.Errorf("%w %d for %d shares", ErrInvalidThreshold, newThreshold, len(holders))
+	}
+	if poly != nil && poly.Threshold() != newThreshold {
+		return nil, fmt.Errorf("%w: private polynomial of threshold %d instead of %d", ErrStateMismatch, poly.Threshold(), newThreshold)
+	}
+
+	var xs []kyber.Scalar
+	if c.UseHashedIndices {
+		if isResharing {
+			return nil, errors.New("dkg: hashed indices are not supported for resharing")
+		}
+		var err error
+		if xs, err = share.HashIndices(c.Suite, c.Suite, c.NewNodes); err != nil {
+			return nil, err
+		}
 	}
 
 	var dealer *vss.Dealer
-	var err error
 	var canIssue bool
-	if c.Share != nil {
-		// resharing case
-		secretCoeff := c.Share.Share.V
-		dealer, err = vss.NewDealer(c.Suite, c.Longterm, secretCoeff, c.NewNodes, newThreshold)
+	if c.Share != nil && poly != nil {
+		if !poly.Secret().Equal(c.Share.Share.V) {
+			return nil, fmt.Errorf("%w: private polynomial of another share", ErrStateMismatch)
+		}
+		dealer, err = vss.NewDealerFromPoly(c.Suite, c.Longterm, poly, holders, nil)
+		canIssue = true
+	} else if c.Share != nil {
+		// resharing case, with a copy of the share since the dealer erases
+		// its secret once the new shares are computed
+		secretCoeff := c.Share.Share.V.Clone()
+		dealer, err = vss.NewDealer(c.Suite, c.Longterm, secretCoeff, holders, newThreshold)
 		canIssue = true
+	} else if !isResharing && newPresent && poly != nil {
+		dealer, err = vss.NewDealerFromPoly(c.Suite, c.Longterm, poly, holders, xs)
+		canIssue = true
+		c.OldNodes = c.NewNodes
+		oidx, oldPresent = findPub(c.OldNodes, pub)
 	} else if !isResharing && newPresent {
 		// fresh DKG case
 		randomStream := random.New()
@@ -186,7 +439,11 @@ func NewDistKeyHandler(c *Config) (*DistKeyGenerator, error) {
 			randomStream = random.New(c.Reader)
 		}
 		secretCoeff := c.Suite.Scalar().Pick(randomStream)
-		dealer, err = vss.NewDealer(c.Suite, c.Longterm, secretCoeff, c.NewNodes, newThreshold)
+		if xs != nil {
+			dealer, err = vss.NewDealerWithPoints(c.Suite, c.Longterm, secretCoeff, c.NewNodes, xs, newThreshold)
+		} else {
+			dealer, err = vss.NewDealer(c.Suite, c.Longterm, secretCoeff, holders, newThreshold)
+		}
 		canIssue = true
 		c.OldNodes = c.NewNodes
 		oidx, oldPresent = findPub(c.OldNodes, pub)
@@ -195,6 +452,11 @@ func NewDistKeyHandler(c *Config) (*DistKeyGenerator, error) {
 	if err != nil {
 		return nil, err
 	}
+	if dealer != nil {
+		if err := dealer.SetNonce(c.Nonce); err != nil {
+			return nil, err
+		}
+	}
 
 	var dpub *share.PubPoly
 	var oldThreshold int
@@ -234,6 +496,12 @@ func NewDistKeyHandler(c *Config) (*DistKeyGenerator, error) {
 		newT:           newThreshold,
 		newPresent:     newPresent,
 		oldPresent:     oldPresent,
+		xs:             xs,
+		holders:        holders,
+		shareIndices:   shareIndices,
+		evidence:       newEvidence(),
+		processedDeals: make(map[uint32]*processedDeal),
+		justified:      make(map[uint32]*justifiedComplaint),
 	}
 	if newPresent {
 		err = dkg.initVerifiers(c)
@@ -241,6 +509,51 @@ func NewDistKeyHandler(c *Config) (*DistKeyGenerator, error) {
 	return dkg, err
 }
 
+// checkDuplicates returns a DuplicateKeyError if the given list of nodes holds
+// the same public key twice, or ErrNilKey if it holds a nil key.
+func checkDuplicates(list string, nodes []kyber.Point) error {
+	seen := make(map[string]int, len(nodes))
+	for i, n := range nodes {
+		if n == nil {
+			return fmt.Errorf("%w at index %d of the %s nodes", ErrNilKey, i, list)
+		}
+		key := n.String()
+		if first, exists := seen[key]; exists {
+			return &DuplicateKeyError{List: list, First: first, Index: i}
+		}
+		seen[key] = i
+	}
+	return nil
+}
+
+// expandWeights returns the public keys of the holders of the shares, where
+// each node is repeated as many times as its weight, and the indices of the
+// shares of each node, see share.Weights. A nil list of weights gives a weight
+// of 1 to every node.
+func expandWeights(nodes []kyber.Point, weights []int) ([]kyber.Point, [][]int, error) {
+	if weights != nil && len(weights) != len(nodes) {
+		return nil, nil, fmt.Errorf("%w: %d weights for %d new nodes", ErrInvalidWeights, len(weights), len(nodes))
+	}
+	w := share.Weights(weights)
+	if weights == nil {
+		w = make(share.Weights, len(nodes))
+		for i := range w {
+			w[i] = 1
+		}
+	}
+	if err := w.Check(); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidWeights, err)
+	}
+	var holders []kyber.Point
+	indices := w.Indices()
+	for i, idx := range indices {
+		for range idx {
+			holders = append(holders, nodes[i])
+		}
+	}
+	return holders, indices, nil
+}
+
 // NewDistKeyGenerator returns a dist key generator ready to create a fresh
 // distributed key with the regular DKG protocol.
 func NewDistKeyGenerator(suite Suite, longterm kyber.Scalar, participants []kyber.Point, t int) (*DistKeyGenerator, error) {
@@ -255,39 +568,51 @@ func NewDistKeyGenerator(suite Suite, longterm kyber.Scalar, participants []kybe
 
 // Deals returns all the deals that must be broadcasted to all participants in
 // the new list. The deal corresponding to this DKG is already added to this DKG
-// and is ommitted from the returned map. To know which participant a deal
+// and is ommitted from the returned map. The next calls return the same deals,
+// e.g. to send them again to a participant which missed them. To know which participant a deal
 // belongs to, loop over the keys as indices in the list of new participants:
 //
 //	for i,dd := range distDeals {
 //	   sendTo(participants[i],dd)
 //	}
 //
+// With Config.Weights, there is one deal per share, and the keys are the
+// indices of the shares, whose holders are given by ShareHolder:
+//
+//	for i,dd := range distDeals {
+//	   sendTo(participants[d.ShareHolder(i)],dd)
+//	}
+//
 // If this method cannot process its own Deal, that indicates a
 // severe problem with the configuration or implementation and
 // results in a panic.
 func (d *DistKeyGenerator) Deals() (map[int]*Deal, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
 	if !d.canIssue {
 		// We do not hold a share, so we cannot make a deal, so
 		// return an empty map and no error. This makes callers not
 		// need to care if they are in a resharing context or not.
 		return nil, nil
 	}
+	if d.issued != nil {
+		return d.issuedDeals()
+	}
 	deals, err := d.dealer.EncryptedDeals()
 	if err != nil {
 		return nil, err
 	}
 	dd := make(map[int]*Deal)
-	for i := range d.c.NewNodes {
+	issued := make(map[int][]byte)
+	processOwn := !d.processed
+	d.processed = true
+	for i := range d.holders {
 		distd := &Deal{
 			Index: uint32(d.oidx),
 			Deal:  deals[i],
 		}
 		// sign the deal
-		buff, err := distd.MarshalBinary()
-		if err != nil {
-			return nil, err
-		}
-		distd.Signature, err = schnorr.Sign(d.suite, d.long, buff)
+		distd.Signature, err = schnorr.Sign(d.suite, d.long, distd.signatureMessage())
 		if err != nil {
 			return nil, err
 		}
@@ -295,28 +620,119 @@ func (d *DistKeyGenerator) Deals() (map[int]*Deal, error) {
 		// if there is a resharing in progress, nodes that stay must send their
 		// deals to the old nodes, otherwise old nodes won't get responses from
 		// staying nodes and won't be certified.
-		if i == int(d.nidx) && d.newPresent && !d.isResharing {
-			if d.processed {
+		if d.newPresent && !d.isResharing && d.ShareHolder(i) == d.nidx {
+			if !processOwn {
 				continue
 			}
-			d.processed = true
-			if resp, err := d.ProcessDeal(distd); err != nil {
+			if resp, err := d.processDeal(distd); err != nil {
 				panic("dkg: cannot process own deal: " + err.Error())
 			} else if resp.Response.Status != vss.StatusApproval {
 				panic("dkg: own deal gave a complaint")
 			}
 			continue
 		}
+		if issued[i], err = distd.MarshalBinary(); err != nil {
+			return nil, err
+		}
 		dd[i] = distd
 	}
+	d.issued = issued
+	return dd, nil
+}
+
+// issuedDeals returns a copy of the deals returned by the first call to
+// Deals.
+func (d *DistKeyGenerator) issuedDeals() (map[int]*Deal, error) {
+	dd := make(map[int]*Deal, len(d.issued))
+	for i, buff := range d.issued {
+		dd[i] = &Deal{}
+		if err := dd[i].UnmarshalBinary(d.suite, buff); err != nil {
+			return nil, err
+		}
+	}
 	return dd, nil
 }
 
 // ProcessDeal takes a Deal created by Deals() and stores and verifies it. It
 // returns a Response to broadcast to every other participant, including the old
-// participants. It returns an error in case the deal has already been stored,
-// or if the deal is incorrect (see vss.Verifier.ProcessEncryptedDeal).
+// participants. It returns an error if the deal is incorrect (see
+// vss.Verifier.ProcessEncryptedDeal), or if another deal of the same dealer
+// has already been stored (vss.ErrDealAlreadyProcessed). The errors of the vss
+// package are wrapped and can be checked with errors.Is.
+//
+// Processing the exact same deal again, e.g. when it is received twice, is a
+// no-op returning the response of the first call. ProcessDeal can be called
+// concurrently with the other methods.
 func (d *DistKeyGenerator) ProcessDeal(dd *Deal) (*Response, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.processDeal(dd)
+}
+
+// processDeal implements ProcessDeal.
+func (d *DistKeyGenerator) processDeal(dd *Deal) (*Response, error) {
+	if dd == nil || dd.Deal == nil {
+		return nil, fmt.Errorf("%w: nil deal", ErrMalformed)
+	}
+	// a deal which cannot be encoded is rejected by verifyDeal anyway
+	buff, err := dd.MarshalBinary()
+	if err != nil {
+		return d.verifyDeal(dd)
+	}
+	if p, ok := d.processedDeals[dd.Index]; ok && bytes.Equal(p.deal, buff) {
+		return p.resp, nil
+	}
+	resp, err := d.verifyDeal(dd)
+	if err != nil {
+		return nil, err
+	}
+	// the response is valid, so that it can be encoded
+	respBuff, _ := resp.MarshalBinary()
+	d.processedDeals[dd.Index] = &processedDeal{deal: buff, resp: resp, response: respBuff}
+	d.log = append(d.log, buff)
+	return resp, nil
+}
+
+// VerifyDeal runs the checks of ProcessDeal on the deal without processing
+// it: the signature of the dealer, and the decryption, the session ID and the
+// share against the commitments of the deal of every share of this node (see
+// vss.Verifier.VerifyDealOnly), as well as the commitment of a resharing deal
+// to the share of the dealer. It returns nil if ProcessDeal would accept the
+// deal with an approval, so that a network layer can drop the other deals
+// before feeding the protocol; the errors wrap the ones of the vss package. It
+// does not modify the DistKeyGenerator and can be called concurrently with
+// the other methods.
+func (d *DistKeyGenerator) VerifyDeal(dd *Deal) error {
+	if dd == nil || dd.Deal == nil {
+		return fmt.Errorf("%w: nil deal", ErrMalformed)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, err := d.dealerKey(dd); err != nil {
+		return err
+	}
+	vers := append([]*vss.Verifier{d.verifiers[dd.Index]}, d.extraVerifiers[dd.Index]...)
+	var deal *vss.Deal
+	var err error
+	for _, ver := range vers {
+		if deal, err = ver.VerifyDealOnly(dd.Deal); !errors.Is(err, vss.ErrDealOutOfIndex) {
+			break
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("dkg: deal from dealer %s: %w", d.dealerID(dd.Index), err)
+	}
+	defer wipe.Scalar(deal.SecShare.V)
+	if d.isResharing && d.canReceive && !d.dpub.Eval(int(dd.Index)).V.Equal(deal.Commitments[0]) {
+		return fmt.Errorf("dkg: deal from dealer %s: %w: commitment differs from the share of the dealer",
+			d.dealerID(dd.Index), vss.ErrInvalidDeal)
+	}
+	return nil
+}
+
+// dealerKey returns the public key of the dealer of the deal after checking
+// that this node expects it and that its signature is valid.
+func (d *DistKeyGenerator) dealerKey(dd *Deal) (kyber.Point, error) {
 	if !d.newPresent {
 		return nil, errors.New("dkg: unexpected deal for unlisted dealer in new list")
 	}
@@ -329,24 +745,39 @@ func (d *DistKeyGenerator) ProcessDeal(dd *Deal) (*Response, error) {
 	}
 	// public key of the dealer
 	if !ok {
-		return nil, errors.New("dkg: dist deal out of bounds index")
+		return nil, fmt.Errorf("%w: deal from dealer %d", ErrDealerIndex, dd.Index)
 	}
 
 	// verify signature
-	buff, err := dd.MarshalBinary()
-	if err != nil {
-		return nil, err
-	}
-	if err := schnorr.Verify(d.suite, pub, buff, dd.Signature); err != nil {
-		return nil, err
+	if err := schnorr.Verify(d.suite, pub, dd.signatureMessage(), dd.Signature); err != nil {
+		return nil, fmt.Errorf("dkg: deal from dealer %s: %w: %v", d.dealerID(dd.Index), vss.ErrInvalidSignature, err)
 	}
 
-	ver, _ := d.verifiers[dd.Index]
+	if _, ok := d.verifiers[dd.Index]; !ok {
+		return nil, fmt.Errorf("%w: deal from dealer %d", ErrDealerIndex, dd.Index)
+	}
+	return pub, nil
+}
 
-	resp, err := ver.ProcessEncryptedDeal(dd.Deal)
+// verifyDeal verifies the deal, which has not been processed yet, and returns
+// the response of this node.
+func (d *DistKeyGenerator) verifyDeal(dd *Deal) (*Response, error) {
+	pub, err := d.dealerKey(dd)
 	if err != nil {
 		return nil, err
 	}
+	ver := d.verifiers[dd.Index]
+
+	resp, err := d.processEncryptedDeal(dd.Index, dd.Deal)
+	if err != nil {
+		if reason, ok := rejectionReason(err); ok {
+			d.evidence.rejectDeal(dd, reason)
+		}
+		return nil, fmt.Errorf("dkg: deal from dealer %s: %w", d.dealerID(dd.Index), err)
+	}
+	if !pub.Equal(d.pub) {
+		d.receivedDeals++
+	}
 
 	reject := func() (*Response, error) {
 		idx, present := findPub(d.c.NewNodes, pub)
@@ -364,6 +795,8 @@ func (d *DistKeyGenerator) ProcessDeal(dd *Deal) (*Response, error) {
 			return nil, err
 		}
 		resp.Signature = s
+		d.evidence.rejectDeal(dd, ReasonInconsistentShare)
+		d.evidence.response(dd.Index, resp)
 		return &Response{
 			Index:    dd.Index,
 			Response: resp,
@@ -387,9 +820,19 @@ func (d *DistKeyGenerator) ProcessDeal(dd *Deal) (*Response, error) {
 	// In the case of resharing the dealer will issue his own response in order
 	// for the old comities to get responses and be certified, which is why we
 	// don't add it manually there.
+	// The deals of this node for its own shares give their responses anyway.
+	if resp.Status == vss.StatusComplaint {
+		d.evidence.rejectDeal(dd, ReasonInvalidShare)
+	}
+	d.evidence.response(dd.Index, resp)
 	newIdx, found := findPub(d.c.NewNodes, pub)
-	if found && !d.isResharing {
-		d.verifiers[dd.Index].UnsafeSetResponseDKG(uint32(newIdx), vss.StatusApproval)
+	if found && !d.isResharing && !pub.Equal(d.pub) {
+		for _, i := range d.shareIndices[newIdx] {
+			d.verifiers[dd.Index].UnsafeSetResponseDKG(uint32(i), vss.StatusApproval)
+			for _, extra := range d.extraVerifiers[dd.Index] {
+				extra.UnsafeSetResponseDKG(uint32(i), vss.StatusApproval)
+			}
+		}
 	}
 
 	return &Response{
@@ -398,23 +841,129 @@ func (d *DistKeyGenerator) ProcessDeal(dd *Deal) (*Response, error) {
 	}, nil
 }
 
+// processEncryptedDeal gives the deal to the verifier of the share it holds,
+// among the shares of this node, and the response of that verifier to the
+// verifiers of the other shares.
+func (d *DistKeyGenerator) processEncryptedDeal(dealer uint32, e *vss.EncryptedDeal) (*vss.Response, error) {
+	vers := append([]*vss.Verifier{d.verifiers[dealer]}, d.extraVerifiers[dealer]...)
+	var err error
+	for _, ver := range vers {
+		var resp *vss.Response
+		resp, err = ver.ProcessEncryptedDeal(e)
+		if errors.Is(err, vss.ErrDealOutOfIndex) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, other := range vers {
+			if other == ver {
+				continue
+			}
+			// the other verifiers may not have received their deal yet, and
+			// the signature of the response has just been made
+			err := other.Aggregator.UnsafeProcessResponse(resp)
+			if err != nil && !errors.Is(err, vss.ErrDuplicateResponse) {
+				return nil, err
+			}
+		}
+		return resp, nil
+	}
+	return nil, err
+}
+
+// followFirstVerifier gives a response, whose signature has been checked, or a
+// justification accepted by the verifier of the first share of this node for
+// the deal of the dealer to the verifiers of the other shares. Their errors
+// are ignored since the verifier of the first share decides.
+func (d *DistKeyGenerator) followFirstVerifier(dealer uint32, r *vss.Response, j *vss.Justification) {
+	for _, extra := range d.extraVerifiers[dealer] {
+		if r != nil {
+			_ = extra.Aggregator.UnsafeProcessResponse(r)
+		}
+		if j != nil {
+			_ = extra.ProcessJustification(j)
+		}
+	}
+}
+
+// ShareHolder returns the index in the list of new nodes of the holder of the
+// share at index i, which is i itself unless Config.Weights is set. It returns
+// -1 if there is no share at index i.
+func (d *DistKeyGenerator) ShareHolder(i int) int {
+	for n, indices := range d.shareIndices {
+		if len(indices) > 0 && i >= indices[0] && i <= indices[len(indices)-1] {
+			return n
+		}
+	}
+	return -1
+}
+
 // ProcessResponse takes a response from every other peer.  If the response
 // designates the deal of another participant than this dkg, this dkg stores it
 // and returns nil with a possible error regarding the validity of the response.
 // If the response designates a deal this dkg has issued, then the dkg will process
-// the response, and returns a justification.
+// the response, and returns a justification. A copy of a response that has
+// already been processed, e.g. because it has been received twice, is ignored,
+// but another response of the same verifier about the same deal is rejected
+// with vss.ErrDuplicateResponse. The errors of the vss package are wrapped and
+// can be checked with errors.Is.
+//
+// A justification is only returned by the dealer of the deal, when the
+// response is a complaint: it reveals the share of the complaining node so
+// that everybody can check it. The caller must broadcast it to all the other
+// nodes, which give it to ProcessJustification. An approval, or a response
+// about the deal of another dealer, never yields a justification. A complaint
+// received twice gives back the same justification.
+//
+// ProcessResponse can be called concurrently with the other methods. The
+// justification is also given to Config.OnJustification, if set.
 func (d *DistKeyGenerator) ProcessResponse(resp *Response) (*Justification, error) {
-	if d.isResharing && d.canIssue && !d.newPresent {
-		return d.processResharingResponse(resp)
+	d.mu.Lock()
+	j, err := d.processResponse(resp, false)
+	d.mu.Unlock()
+	d.notifyJustification(j)
+	return j, err
+}
+
+// notifyJustification gives the justification, if any, to
+// Config.OnJustification.
+func (d *DistKeyGenerator) notifyJustification(j *Justification) {
+	if j != nil && d.c.OnJustification != nil {
+		d.c.OnJustification(j)
+	}
+}
+
+// processResponse implements ProcessResponse. If verified is true, the
+// signature of the response has already been checked by ProcessResponses.
+func (d *DistKeyGenerator) processResponse(resp *Response, verified bool) (*Justification, error) {
+	if d.finished {
+		return nil, ErrFinished
+	}
+	if resp == nil || resp.Response == nil {
+		return nil, fmt.Errorf("%w: nil response", ErrMalformed)
+	}
+	if d.DealerOnly() {
+		return d.processResharingResponse(resp, verified)
 	}
 	v, ok := d.verifiers[resp.Index]
 	if !ok {
-		return nil, fmt.Errorf("dkg: responses received for unknown dealer %d", resp.Index)
+		return nil, fmt.Errorf("%w: response for dealer %d", ErrDealerIndex, resp.Index)
 	}
 
-	if err := v.ProcessResponse(resp.Response); err != nil {
-		return nil, err
+	process := v.ProcessResponse
+	if verified {
+		process = v.UnsafeProcessResponse
 	}
+	if err := process(resp.Response); err != nil {
+		if errors.Is(err, vss.ErrDuplicateResponse) && d.evidence.duplicate(resp.Index, resp.Response) {
+			return d.justification(resp), nil
+		}
+		return nil, fmt.Errorf("dkg: response for dealer %s: %w", d.dealerID(resp.Index), err)
+	}
+	d.record(resp)
+	d.evidence.response(resp.Index, resp.Response)
+	d.followFirstVerifier(resp.Index, resp.Response, nil)
 
 	myIdx := uint32(d.oidx)
 	if !d.canIssue || resp.Index != myIdx {
@@ -422,9 +971,13 @@ func (d *DistKeyGenerator) ProcessResponse(resp *Response) (*Justification, erro
 		return nil, nil
 	}
 
+	complaint := *resp.Response
 	j, err := d.dealer.ProcessResponse(resp.Response)
 	if err != nil {
-		return nil, err
+		if errors.Is(err, vss.ErrDuplicateResponse) && sameResponse(d.dealer.Responses()[resp.Response.Index], resp.Response) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("dkg: response for own deal: %w", err)
 	}
 	if j == nil {
 		return nil, nil
@@ -432,27 +985,68 @@ func (d *DistKeyGenerator) ProcessResponse(resp *Response) (*Justification, erro
 	if err := v.ProcessJustification(j); err != nil {
 		return nil, err
 	}
+	d.followFirstVerifier(resp.Index, nil, j)
 
-	return &Justification{
+	justification := &Justification{
 		Index:         uint32(d.oidx),
 		Justification: j,
-	}, nil
+	}
+	d.justify(complaint, justification)
+	return justification, nil
+}
+
+// justify records the justification returned for the complaint about the deal
+// of this node. A copy of the complaint is kept since the verifiers turn the
+// complaints they store into approvals once they are justified.
+func (d *DistKeyGenerator) justify(complaint vss.Response, j *Justification) {
+	d.justified[complaint.Index] = &justifiedComplaint{complaint: &complaint, j: j}
+}
+
+// justification returns the justification already returned for the response,
+// if it is a complaint about the deal of this node received again, or nil.
+func (d *DistKeyGenerator) justification(resp *Response) *Justification {
+	if int(resp.Index) != d.oidx || !d.canIssue {
+		return nil
+	}
+	c, ok := d.justified[resp.Response.Index]
+	if !ok || !bytes.Equal(c.complaint.Signature, resp.Response.Signature) ||
+		!bytes.Equal(c.complaint.Hash(d.suite), resp.Response.Hash(d.suite)) {
+		return nil
+	}
+	return c.j
 }
 
 // special case when an node that is present in the old list but not in the
 // new,i.e. leaving the group. This node does not have any verifiers since it
 // can't receive shares. This function makes some check on the response and
 // returns a justification if the response is invalid.
-func (d *DistKeyGenerator) processResharingResponse(resp *Response) (*Justification, error) {
+func (d *DistKeyGenerator) processResharingResponse(resp *Response, verified bool) (*Justification, error) {
+	// the empty aggregators do not know the session ids of the other dealers,
+	// but responses about our deal must belong to this session
+	if int(resp.Index) == d.oidx && !bytes.Equal(resp.Response.SessionID, d.dealer.SessionID()) {
+		return nil, fmt.Errorf("dkg: response for own deal: %w", vss.ErrInvalidSessionID)
+	}
 	agg, present := d.oldAggregators[resp.Index]
 	if !present {
 		agg = vss.NewEmptyAggregator(d.suite, d.c.NewNodes)
 		d.oldAggregators[resp.Index] = agg
 	}
 
-	err := agg.ProcessResponse(resp.Response)
+	process := agg.ProcessResponse
+	if verified {
+		process = agg.UnsafeProcessResponse
+	}
+	err := process(resp.Response)
+	if errors.Is(err, vss.ErrDuplicateResponse) && d.evidence.duplicate(resp.Index, resp.Response) {
+		return d.justification(resp), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("dkg: response for dealer %s: %w", d.dealerID(resp.Index), err)
+	}
+	d.record(resp)
+	d.evidence.response(resp.Index, resp.Response)
 	if int(resp.Index) != d.oidx {
-		return nil, err
+		return nil, nil
 	}
 
 	if resp.Response.Status == vss.StatusApproval {
@@ -472,26 +1066,231 @@ func (d *DistKeyGenerator) processResharingResponse(resp *Response) (*Justificat
 			Deal:      deal,
 		},
 	}
+	sig, err := schnorr.Sign(d.suite, d.long, j.Justification.Hash(d.suite))
+	if err != nil {
+		return nil, err
+	}
+	j.Justification.Signature = sig
+	d.justify(*resp.Response, j)
 	return j, nil
 }
 
+// ProcessResponses processes a batch of responses as if they were given one by
+// one to ProcessResponse in the order of the slice, and returns the same
+// justifications, to broadcast in the returned order. The signatures of the
+// responses, which are independent, are first verified in parallel, and only
+// once for the copies of a response received several times. The error of a
+// response does not stop the processing of the batch: it is stored in errs
+// at the position of the response in resps.
+//
+// ProcessResponses can be called concurrently with the other methods. The
+// signatures are verified before taking the turn of the batch. The
+// justifications are also given in order to Config.OnJustification, if set,
+// once the whole batch is processed.
+func (d *DistKeyGenerator) ProcessResponses(resps []*Response) (justifications []*Justification, errs map[int]error) {
+	errs = make(map[int]error)
+	verified := d.verifyResponses(resps)
+	d.mu.Lock()
+	for i, resp := range resps {
+		j, err := d.processResponse(resp, verified[i])
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		if j != nil {
+			justifications = append(justifications, j)
+		}
+	}
+	d.mu.Unlock()
+	for _, j := range justifications {
+		d.notifyJustification(j)
+	}
+	return justifications, errs
+}
+
+// verifyResponses verifies the signatures of the responses in parallel, and
+// returns for each of them whether the signature is valid. A response that
+// cannot be checked here is left to the verification of processResponse.
+func (d *DistKeyGenerator) verifyResponses(resps []*Response) []bool {
+	verified := make([]bool, len(resps))
+	// position of the first copy of each response
+	first := make(map[string]int)
+	copies := make(map[int]int)
+	var todo []int
+	for i, resp := range resps {
+		if resp == nil || resp.Response == nil {
+			continue
+		}
+		id := fmt.Sprintf("%d:%x:%x", resp.Index, resp.Response.Hash(d.suite), resp.Response.Signature)
+		if f, ok := first[id]; ok {
+			copies[i] = f
+			continue
+		}
+		first[id] = i
+		todo = append(todo, i)
+	}
+
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.GOMAXPROCS(0); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				r := resps[i].Response
+				pub, ok := getPub(d.holders, r.Index)
+				verified[i] = ok && schnorr.Verify(d.suite, pub, r.Hash(d.suite), r.Signature) == nil
+			}
+		}()
+	}
+	for _, i := range todo {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+
+	for i, f := range copies {
+		verified[i] = verified[f]
+	}
+	return verified
+}
+
 // ProcessJustification takes a justification and validates it. It returns an
 // error in case the justification is wrong.
+//
+// The justification must answer a complaint already given to ProcessResponse,
+// must be signed by the dealer of the deal, and must reveal a share of the
+// complaining node that verifies against the commitments of the deal received
+// at first. If so, the complaint turns into an approval and the dealer can be
+// in the qualified set again (see QUAL). If the dealer signed a wrong
+// justification, its deal is disqualified for good. A justification that
+// arrives before the complaint is rejected with vss.ErrUnexpectedJustification
+// and may be given again later; one that does not come from the dealer is
+// rejected with vss.ErrInvalidSignature without any consequence for the
+// dealer.
+//
+// A dealer-only node (see DealerOnly) has received no deal to check the
+// justification against, so it ignores it and returns nil.
+//
+// ProcessJustification can be called concurrently with the other methods.
 func (d *DistKeyGenerator) ProcessJustification(j *Justification) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.processJustification(j)
+}
+
+// processJustification implements ProcessJustification.
+func (d *DistKeyGenerator) processJustification(j *Justification) error {
+	if d.finished {
+		return ErrFinished
+	}
+	if j == nil || j.Justification == nil {
+		return fmt.Errorf("%w: nil justification", ErrMalformed)
+	}
+	if d.DealerOnly() {
+		return nil
+	}
 	v, ok := d.verifiers[j.Index]
 	if !ok {
-		return errors.New("dkg: Justification received but no deal for it")
+		return fmt.Errorf("%w: justification for dealer %d", ErrDealerIndex, j.Index)
+	}
+	if err := v.ProcessJustification(j.Justification); err != nil {
+		if justificationFailed(err, j.Justification, v) {
+			// the dealer is disqualified for good
+			d.record(j)
+			d.evidence.failedJustification(j)
+		}
+		return fmt.Errorf("dkg: justification for dealer %s: %w", d.dealerID(j.Index), err)
+	}
+	d.record(j)
+	d.followFirstVerifier(j.Index, nil, j.Justification)
+	return nil
+}
+
+// record appends the encoding of a message which changed the state to the
+// log replayed by ResumeDistKeyGenerator.
+func (d *DistKeyGenerator) record(m encoding.BinaryMarshaler) {
+	if buff, err := m.MarshalBinary(); err == nil {
+		d.log = append(d.log, buff)
 	}
-	return v.ProcessJustification(j.Justification)
 }
 
 // SetTimeout triggers the timeout on all verifiers, and thus makes sure
 // all verifiers have either responded, or have a StatusComplaint response.
+// It can be called concurrently with the other methods.
 func (d *DistKeyGenerator) SetTimeout() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.setTimeout()
+}
+
+func (d *DistKeyGenerator) setTimeout() {
 	d.timeout = true
 	for _, v := range d.verifiers {
 		v.SetTimeout()
 	}
+	for _, extras := range d.extraVerifiers {
+		for _, v := range extras {
+			v.SetTimeout()
+		}
+	}
+	for _, agg := range d.oldAggregators {
+		agg.SetTimeout()
+	}
+}
+
+// ErrDealerOnly is returned by DistKeyShare for a node which only deals in a
+// resharing, see DealerOnly.
+var ErrDealerOnly = errors.New("dkg: dealer-only node does not receive a share")
+
+// ErrNotCertified is returned by DistKeyShare before the deals of enough
+// dealers are certified, see Certified and ThresholdCertified.
+var ErrNotCertified = errors.New("dkg: distributed key not certified")
+
+// ErrPublicKeyChanged is returned by DistKeyShare at the end of a resharing
+// when the distributed public key of the new shares is not the one of the old
+// shares, e.g. when an old node dealt a fresh secret instead of its share and
+// justified the complaints about its deal.
+var ErrPublicKeyChanged = errors.New("dkg: resharing changed the distributed public key")
+
+// ErrFinished is returned when a response or a justification is given to a
+// DistKeyGenerator after Finish has been called.
+var ErrFinished = errors.New("dkg: protocol already finished")
+
+// Finish ends the protocol for this node: it triggers the timeout on all
+// verifiers (see SetTimeout) and freezes the qualified set as it is at this
+// point. Any later call to ProcessResponse or ProcessJustification is rejected
+// with ErrFinished, so that the distributed key share returned by
+// DistKeyShare cannot change anymore. Calling Finish is required before
+// calling DistKeyShare unless all deals are certified (see Certified).
+//
+// Finish can be called concurrently with the other methods: the calls to
+// ProcessResponse and ProcessJustification either complete before it, and
+// count in the qualified set, or fail with ErrFinished.
+func (d *DistKeyGenerator) Finish() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.finish()
+}
+
+func (d *DistKeyGenerator) finish() {
+	if d.finished {
+		return
+	}
+	d.setTimeout()
+	qual := make(map[uint32]bool)
+	for _, i := range d.qualified() {
+		qual[uint32(i)] = true
+	}
+	d.qual = qual
+	d.finished = true
+}
+
+// Finished returns true if Finish has been called.
+func (d *DistKeyGenerator) Finished() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.finished
 }
 
 // ThresholdCertified returns true if a THRESHOLD of deals are certified. To know the
@@ -505,42 +1304,87 @@ func (d *DistKeyGenerator) SetTimeout() {
 // aggregated shares from 1, 2, 3 and node 2 could have aggregated shares from
 // 2, 3 and 4.
 func (d *DistKeyGenerator) ThresholdCertified() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.thresholdCertified()
+}
+
+func (d *DistKeyGenerator) thresholdCertified() bool {
 	if d.isResharing {
 		// in resharing case, we have two threshold. Here we want the number of
 		// deals to be at least what the old threshold was. (and for each deal,
 		// we want the number of approval to be a least what the new threshold
 		// is).
-		return len(d.QUAL()) >= d.c.OldThreshold
+		return len(d.qualified()) >= d.c.OldThreshold
 	}
-	// in dkg case, the threshold is symmetric -> # verifiers = # dealers
-	return len(d.QUAL()) >= d.c.Threshold
+	// in dkg case, the threshold is symmetric -> # verifiers = # dealers, or
+	// the weight of the dealers with weights
+	var weight int
+	for _, i := range d.qualified() {
+		weight += len(d.shareIndices[i])
+	}
+	return weight >= d.c.Threshold
 }
 
 // Certified returns true if *all* deals are certified. This method should
 // be called before the timeout occurs, as to pre-emptively stop the DKG
 // protocol if it is already finished before the timeout.
+//
+// A dealer-only node (see DealerOnly) does not receive any deal. For such a
+// node, Certified returns true once all the new nodes have accepted its own
+// deal, or, after the timeout, once at least the new threshold of them have.
+// A new node accepts the deal with an approval, or with a complaint that
+// ProcessResponse answered with a justification.
 func (d *DistKeyGenerator) Certified() bool {
-	var good []int
-	if d.isResharing && d.canIssue && !d.newPresent {
-		d.oldQualIter(func(i uint32, v *vss.Aggregator) bool {
-			if len(v.MissingResponses()) > 0 {
-				return false
-			}
-			good = append(good, int(i))
-			return true
-		})
-	} else {
-		d.qualIter(func(i uint32, v *vss.Verifier) bool {
-			if len(v.MissingResponses()) > 0 {
-				return false
-			}
-			good = append(good, int(i))
-			return true
-		})
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.certified()
+}
+
+func (d *DistKeyGenerator) certified() bool {
+	if d.DealerOnly() {
+		return d.dealAccepted()
 	}
+	var good []int
+	d.qualIter(func(i uint32, v *vss.Verifier) bool {
+		if len(v.MissingResponses()) > 0 {
+			return false
+		}
+		good = append(good, int(i))
+		return true
+	})
 	return len(good) >= len(d.c.OldNodes)
 }
 
+// dealAccepted returns true if enough new nodes accepted the deal of this
+// dealer-only node, see Certified.
+func (d *DistKeyGenerator) dealAccepted() bool {
+	agg, ok := d.oldAggregators[uint32(d.oidx)]
+	if !ok {
+		return false
+	}
+	var accepted int
+	for i, r := range agg.Responses() {
+		if r.Status == vss.StatusApproval {
+			accepted++
+		} else if _, ok := d.justified[i]; ok {
+			accepted++
+		}
+	}
+	if d.timeout {
+		return accepted >= d.newT
+	}
+	return accepted == len(d.c.NewNodes)
+}
+
+// DealerOnly returns true if this node is in the old list of nodes of a
+// resharing but not in the new one. Such a node only issues deals and
+// processes the responses to them: it never receives a deal, and
+// DistKeyShare returns ErrDealerOnly.
+func (d *DistKeyGenerator) DealerOnly() bool {
+	return d.isResharing && d.canIssue && !d.newPresent
+}
+
 // QualifiedShares returns the set of shares holder index that are considered
 // valid. In particular, it computes the list of common share holders that
 // replied with an approval (or with a complaint later on justified) for each
@@ -554,6 +1398,8 @@ func (d *DistKeyGenerator) Certified() bool {
 // 2. if there are no response from a share holder, the share holder is
 // removed from the list.
 func (d *DistKeyGenerator) QualifiedShares() []int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
 	var invalidSh = make(map[int]bool)
 	var invalidDeals = make(map[int]bool)
 	// compute list of invalid deals according to 1.
@@ -564,7 +1410,7 @@ func (d *DistKeyGenerator) QualifiedShares() []int {
 			// their deal in the first place.
 			invalidDeals[int(dealerIndex)] = true
 		}
-		for holderIndex := range d.c.NewNodes {
+		for holderIndex := range d.holders {
 			resp, ok := responses[uint32(holderIndex)]
 			if ok && resp.Status == vss.StatusComplaint {
 				// 1. rule
@@ -581,7 +1427,7 @@ func (d *DistKeyGenerator) QualifiedShares() []int {
 			continue
 		}
 		responses := verifier.Responses()
-		for holderIndex := range d.c.NewNodes {
+		for holderIndex := range d.holders {
 			_, ok := responses[uint32(holderIndex)]
 			if !ok {
 				// 2. rule - absent response
@@ -591,7 +1437,7 @@ func (d *DistKeyGenerator) QualifiedShares() []int {
 	}
 
 	var validHolders []int
-	for i := range d.c.NewNodes {
+	for i := range d.holders {
 		if _, included := invalidSh[i]; included {
 			continue
 		}
@@ -605,7 +1451,7 @@ func (d *DistKeyGenerator) QualifiedShares() []int {
 func (d *DistKeyGenerator) ExpectedDeals() int {
 	switch {
 	case d.newPresent && d.oldPresent:
-		return len(d.c.OldNodes) - 1
+		return (len(d.c.OldNodes) - 1) * len(d.shareIndices[d.nidx])
 	case d.newPresent && !d.oldPresent:
 		return len(d.c.OldNodes)
 	default:
@@ -613,13 +1459,30 @@ func (d *DistKeyGenerator) ExpectedDeals() int {
 	}
 }
 
+// ReceivedDeals returns the number of deals that this node has received from
+// the other participants and answered with a response so far, whether an
+// approval or a complaint: it is not the number of valid deals. The deals
+// rejected with an error, e.g. because they cannot be decrypted, are not
+// counted. Once it reaches ExpectedDeals(), no more deals are to be expected.
+func (d *DistKeyGenerator) ReceivedDeals() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.receivedDeals
+}
+
 // QUAL returns the index in the list of participants that forms the QUALIFIED
 // set, i.e. the list of Certified deals.
 // It does NOT take into account any malicious share holder which share may have
-// been revealed, due to invalid complaint.
+// been revealed, due to invalid complaint. The indices are in increasing order.
 func (d *DistKeyGenerator) QUAL() []int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.qualified()
+}
+
+func (d *DistKeyGenerator) qualified() []int {
 	var good []int
-	if d.isResharing && d.canIssue && !d.newPresent {
+	if d.DealerOnly() {
 		d.oldQualIter(func(i uint32, v *vss.Aggregator) bool {
 			good = append(good, int(i))
 			return true
@@ -645,8 +1508,19 @@ func (d *DistKeyGenerator) isInQUAL(idx uint32) bool {
 	return found
 }
 
+// qualIter calls fn on the certified deals of the qualified set, in increasing
+// order of dealer index, until fn returns false.
 func (d *DistKeyGenerator) qualIter(fn func(idx uint32, v *vss.Verifier) bool) {
-	for i, v := range d.verifiers {
+	dealers := make([]int, 0, len(d.verifiers))
+	for i := range d.verifiers {
+		dealers = append(dealers, int(i))
+	}
+	sort.Ints(dealers)
+	for _, dealer := range dealers {
+		i, v := uint32(dealer), d.verifiers[uint32(dealer)]
+		if d.finished && !d.qual[i] {
+			continue
+		}
 		if v.DealCertified() {
 			if !fn(i, v) {
 				break
@@ -655,8 +1529,18 @@ func (d *DistKeyGenerator) qualIter(fn func(idx uint32, v *vss.Verifier) bool) {
 	}
 }
 
+// oldQualIter is the qualIter of a dealer-only node.
 func (d *DistKeyGenerator) oldQualIter(fn func(idx uint32, v *vss.Aggregator) bool) {
-	for i, v := range d.oldAggregators {
+	dealers := make([]int, 0, len(d.oldAggregators))
+	for i := range d.oldAggregators {
+		dealers = append(dealers, int(i))
+	}
+	sort.Ints(dealers)
+	for _, dealer := range dealers {
+		i, v := uint32(dealer), d.oldAggregators[uint32(dealer)]
+		if d.finished && !d.qual[i] {
+			continue
+		}
 		if v.DealCertified() {
 			if !fn(i, v) {
 				break
@@ -667,35 +1551,87 @@ func (d *DistKeyGenerator) oldQualIter(fn func(idx uint32, v *vss.Aggregator) bo
 
 // DistKeyShare generates the distributed key relative to this receiver.
 // It throws an error if something is wrong such as not enough deals received.
+// It can only be called once all deals are certified (see Certified) or after
+// Finish has been called, so that the qualified set it relies upon cannot
+// change afterwards.
 // The shared secret can be computed when all deals have been sent and
 // basically consists of a public point and a share. The public point is the sum
 // of all aggregated individual public commits of each individual secrets.
 // The share is evaluated from the global Private Polynomial, basically SUM of
 // fj(i) for a receiver i.
+//
+// Once the share is computed, the generator erases the secret material it
+// does not need anymore: the private polynomial and deals of its dealer, and
+// the shares of the deals it received (see vss.Dealer.Zero and
+// vss.Verifier.Zero). The next calls return a copy of the same share.
 func (d *DistKeyGenerator) DistKeyShare() (*DistKeyShare, error) {
-	if !d.ThresholdCertified() {
-		return nil, errors.New("dkg: distributed key not certified")
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.dks != nil {
+		return d.dks.clone(), nil
+	}
+	if d.DealerOnly() {
+		return nil, ErrDealerOnly
+	}
+	if !d.finished && !d.certified() {
+		return nil, fmt.Errorf("%w: not finished nor fully certified", ErrNotCertified)
+	}
+	if !d.thresholdCertified() {
+		return nil, ErrNotCertified
 	}
 	if !d.canReceive {
 		return nil, errors.New("dkg: should not expect to compute any dist. share")
 	}
 
+	var dks *DistKeyShare
+	var err error
 	if d.isResharing {
-		return d.resharingKey()
+		dks, err = d.resharingKey()
+	} else {
+		dks, err = d.dkgKey()
+	}
+	if err != nil {
+		return nil, err
 	}
+	d.dks = dks
+	d.wipe()
+	return dks.clone(), nil
+}
 
-	return d.dkgKey()
+// wipe erases the secret material of the dealer and of the verifiers once the
+// distributed key share has been computed.
+func (d *DistKeyGenerator) wipe() {
+	if d.dealer != nil {
+		d.dealer.Zero()
+	}
+	for i, v := range d.verifiers {
+		v.Zero()
+		for _, extra := range d.extraVerifiers[i] {
+			extra.Zero()
+		}
+	}
 }
 
 func (d *DistKeyGenerator) dkgKey() (*DistKeyShare, error) {
-	sh := d.suite.Scalar().Zero()
+	own := d.shareIndices[d.nidx]
+	shares := make([]*share.PriShare, len(own))
+	for k, i := range own {
+		shares[k] = &share.PriShare{I: i, V: d.suite.Scalar().Zero()}
+	}
 	var pub *share.PubPoly
 	var err error
 	d.qualIter(func(i uint32, v *vss.Verifier) bool {
 		// share of dist. secret = sum of all share received.
 		deal := v.Deal()
-		s := deal.SecShare.V
-		sh = sh.Add(sh, s)
+		shares[0].V.Add(shares[0].V, deal.SecShare.V)
+		for k, extra := range d.extraVerifiers[i] {
+			extraDeal := extra.Deal()
+			if extraDeal == nil {
+				err = fmt.Errorf("dkg: no deal from dealer %s for share %d", d.dealerID(i), extra.Index())
+				return false
+			}
+			shares[k+1].V.Add(shares[k+1].V, extraDeal.SecShare.V)
+		}
 		// Dist. public key = sum of all revealed commitments
 		poly := share.NewPubPoly(d.suite, d.suite.Point().Base(), deal.Commitments)
 		if pub == nil {
@@ -712,13 +1648,17 @@ func (d *DistKeyGenerator) dkgKey() (*DistKeyShare, error) {
 	}
 	_, commits := pub.Info()
 
+	var x kyber.Scalar
+	if d.xs != nil {
+		x = d.xs[d.nidx]
+	}
 	return &DistKeyShare{
-		Commits: commits,
-		Share: &share.PriShare{
-			I: int(d.nidx),
-			V: sh,
-		},
-		PrivatePoly: d.dealer.PrivatePoly().Coefficients(),
+		Commits:     commits,
+		Share:       shares[0],
+		Shares:      shares,
+		X:           x,
+		PrivatePoly: cloneScalars(d.dealer.PrivatePoly().Coefficients()),
+		group:       d.suite,
 	}, nil
 
 }
@@ -772,47 +1712,99 @@ func (d *DistKeyGenerator) resharingKey() (*DistKeyShare, error) {
 		finalCoeffs[i] = coeff
 	}
 
+	if !finalCoeffs[0].Equal(d.dpub.Commit()) {
+		return nil, fmt.Errorf("%w: %s instead of %s", ErrPublicKeyChanged, finalCoeffs[0], d.dpub.Commit())
+	}
+
 	// Reconstruct the final public polynomial
 	pubPoly := share.NewPubPoly(d.suite, nil, finalCoeffs)
 
 	if !pubPoly.Check(privateShare) {
-		return nil, errors.New("dkg: share do not correspond to public polynomial ><")
+		return nil, fmt.Errorf("%w: new share does not match the new commitments", ErrInconsistentShare)
 	}
 	return &DistKeyShare{
 		Commits:     finalCoeffs,
 		Share:       privateShare,
+		Shares:      []*share.PriShare{privateShare},
 		PrivatePoly: priPoly.Coefficients(),
+		group:       d.suite,
 	}, nil
 }
 
-// Verifiers returns the verifiers keeping state of each deals
+// PublicKey returns the distributed public key which a resharing must
+// preserve, i.e. the one of Config.Share or Config.PublicCoeffs, before the
+// protocol completes. DistKeyShare fails with ErrPublicKeyChanged if the new
+// shares do not have this public key. It returns nil for a fresh DKG, whose
+// public key is only known at the end.
+func (d *DistKeyGenerator) PublicKey() kyber.Point {
+	switch {
+	case d.dpub != nil:
+		return d.dpub.Commit()
+	case d.c.Share != nil:
+		return d.c.Share.Commits[0]
+	case len(d.c.PublicCoeffs) > 0:
+		return d.c.PublicCoeffs[0]
+	default:
+		return nil
+	}
+}
+
+// Verifiers returns the verifiers keeping state of each deals. Unlike the
+// other methods, the verifiers must not be used concurrently with the
+// DistKeyGenerator.
 func (d *DistKeyGenerator) Verifiers() map[uint32]*vss.Verifier {
 	return d.verifiers
 }
 
 func (d *DistKeyGenerator) initVerifiers(c *Config) error {
 	var alreadyTaken = make(map[string]bool)
-	verifierList := c.NewNodes
 	dealerList := c.OldNodes
+	own := d.shareIndices[d.nidx]
 	verifiers := make(map[uint32]*vss.Verifier)
+	extraVerifiers := make(map[uint32][]*vss.Verifier)
 	for i, pub := range dealerList {
 		if _, exists := alreadyTaken[pub.String()]; exists {
 			return errors.New("duplicate public key in NewNodes list")
 		}
 		alreadyTaken[pub.String()] = true
-		ver, err := vss.NewVerifier(c.Suite, c.Longterm, pub, verifierList)
+		ver, err := d.newVerifier(c, pub, own[0])
 		if err != nil {
 			return err
 		}
-		// set that the number of approval for this deal must be at the given
-		// threshold regarding the new nodes. (see config.
-		ver.SetThreshold(c.Threshold)
 		verifiers[uint32(i)] = ver
+		for _, j := range own[1:] {
+			extra, err := d.newVerifier(c, pub, j)
+			if err != nil {
+				return err
+			}
+			extraVerifiers[uint32(i)] = append(extraVerifiers[uint32(i)], extra)
+		}
 	}
 	d.verifiers = verifiers
+	d.extraVerifiers = extraVerifiers
 	return nil
 }
 
+// newVerifier returns the verifier of the share at the given index for the
+// deal of the dealer.
+func (d *DistKeyGenerator) newVerifier(c *Config, dealer kyber.Point, index int) (*vss.Verifier, error) {
+	ver, err := vss.NewVerifierAtIndex(c.Suite, c.Longterm, dealer, d.holders, index)
+	if err != nil {
+		return nil, err
+	}
+	// set that the number of approval for this deal must be at the given
+	// threshold regarding the new nodes. (see config.
+	ver.SetThreshold(c.Threshold)
+	ver.SetAcceptLegacyDeals(c.AcceptLegacyDeals)
+	ver.SetNonce(c.Nonce)
+	if d.xs != nil {
+		if err := ver.SetEvaluationPoints(d.xs); err != nil {
+			return nil, err
+		}
+	}
+	return ver, nil
+}
+
 // Renew adds the new distributed key share g (with secret 0) to the distributed key share d.
 func (d *DistKeyShare) Renew(suite Suite, g *DistKeyShare) (*DistKeyShare, error) {
 	// Check G(0) = 0*G.
@@ -825,6 +1817,20 @@ func (d *DistKeyShare) Renew(suite Suite, g *DistKeyShare) (*DistKeyShare, error
 		return nil, errors.New("not the same party")
 	}
 
+	if len(d.Shares) != len(g.Shares) {
+		return nil, errors.New("not the same number of shares")
+	}
+	var newShares []*share.PriShare
+	for i := range d.Shares {
+		if d.Shares[i].I != g.Shares[i].I {
+			return nil, errors.New("not the same party")
+		}
+		newShares = append(newShares, &share.PriShare{
+			I: d.Shares[i].I,
+			V: suite.Scalar().Add(d.Shares[i].V, g.Shares[i].V),
+		})
+	}
+
 	newShare := suite.Scalar().Add(d.Share.V, g.Share.V)
 	newCommits := make([]kyber.Point, len(d.Commits))
 	for i := range newCommits {
@@ -836,9 +1842,27 @@ func (d *DistKeyShare) Renew(suite Suite, g *DistKeyShare) (*DistKeyShare, error
 			I: d.Share.I,
 			V: newShare,
 		},
+		Shares: newShares,
+		X:      d.X,
+		group:  suite,
 	}, nil
 }
 
+// dealerID formats the index of a dealer for the error messages, followed by
+// the fingerprint of its public key (see key.Fingerprint) if the index is in
+// the list of dealers.
+func (d *DistKeyGenerator) dealerID(i uint32) string {
+	dealers := d.c.NewNodes
+	if d.isResharing {
+		dealers = d.c.OldNodes
+	}
+	pub, ok := getPub(dealers, i)
+	if !ok {
+		return fmt.Sprint(i)
+	}
+	return fmt.Sprintf("%d (%s)", i, key.Fingerprint(d.suite, pub))
+}
+
 func getPub(list []kyber.Point, i uint32) (kyber.Point, bool) {
 	if i >= uint32(len(list)) {
 		return nil, false
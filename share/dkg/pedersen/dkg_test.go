@@ -2,16 +2,26 @@ package dkg
 
 import (
 	"crypto/rand"
+	"errors"
 	"fmt"
 	mathRand "math/rand"
+	"runtime"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 	"go.dedis.ch/kyber/v3"
 	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/pairing/bn256"
 	"go.dedis.ch/kyber/v3/share"
 	vss "go.dedis.ch/kyber/v3/share/vss/pedersen"
+	"go.dedis.ch/kyber/v3/sign/bls"
+	"go.dedis.ch/kyber/v3/sign/schnorr"
+	"go.dedis.ch/kyber/v3/sign/tbls"
+	"go.dedis.ch/kyber/v3/util/key"
+	"go.dedis.ch/kyber/v3/util/random"
 )
 
 // Note: if you are looking for a complete scenario that shows DKG in action
@@ -65,12 +75,85 @@ func TestDKGNewDistKeyGenerator(t *testing.T) {
 	require.EqualError(t, err, "dkg: can't run with empty node list")
 }
 
+func TestDKGConfigValidation(t *testing.T) {
+	partPubs, partSec, dkgs := generate(defaultN, defaultT)
+	long := partSec[0]
+
+	_, err := NewDistKeyGenerator(nil, long, partPubs, defaultT)
+	require.Equal(t, ErrNilSuite, err)
+	_, err = NewDistKeyGenerator(suite, nil, partPubs, defaultT)
+	require.Equal(t, ErrNilLongterm, err)
+	sec, _ := genPair()
+	_, err = NewDistKeyGenerator(suite, sec, partPubs, defaultT)
+	require.Equal(t, ErrKeyNotFound, err)
+
+	// duplicate keys
+	dup := append([]kyber.Point{}, partPubs...)
+	dup[3] = dup[1]
+	_, err = NewDistKeyGenerator(suite, long, dup, defaultT)
+	require.True(t, errors.Is(err, ErrDuplicateKey))
+	dupErr, ok := err.(*DuplicateKeyError)
+	require.True(t, ok)
+	require.Equal(t, DuplicateKeyError{List: "new", First: 1, Index: 3}, *dupErr)
+
+	// nil keys
+	dup[3] = nil
+	_, err = NewDistKeyGenerator(suite, long, dup, defaultT)
+	require.True(t, errors.Is(err, ErrNilKey))
+
+	// thresholds out of bounds, the boundary threshold == n is valid
+	for _, th := range []int{1, -1, defaultN + 1} {
+		_, err = NewDistKeyGenerator(suite, long, partPubs, th)
+		require.True(t, errors.Is(err, ErrInvalidThreshold), "threshold %d", th)
+	}
+	_, err = NewDistKeyGenerator(suite, long, partPubs[:2], 0)
+	require.True(t, errors.Is(err, ErrInvalidThreshold))
+	_, err = NewDistKeyGenerator(suite, long, partPubs, defaultN)
+	require.NoError(t, err)
+
+	// resharing configurations
+	fullExchange(t, dkgs, true)
+	dks, err := dkgs[0].DistKeyShare()
+	require.NoError(t, err)
+	newPubs, _, _ := generate(defaultN, defaultT)
+	reshare := func(modify func(c *Config)) error {
+		c := &Config{
+			Suite:        suite,
+			Longterm:     long,
+			OldNodes:     partPubs,
+			NewNodes:     newPubs,
+			Share:        dks,
+			Threshold:    defaultT,
+			OldThreshold: defaultT,
+		}
+		modify(c)
+		_, err := NewDistKeyHandler(c)
+		return err
+	}
+	require.NoError(t, reshare(func(c *Config) {}))
+	err = reshare(func(c *Config) { c.OldNodes = append(partPubs, partPubs[2]) })
+	require.True(t, errors.Is(err, ErrDuplicateKey))
+	require.Equal(t, "old", err.(*DuplicateKeyError).List)
+	err = reshare(func(c *Config) { c.OldNodes = append(partPubs, nil) })
+	require.True(t, errors.Is(err, ErrNilKey))
+	err = reshare(func(c *Config) { c.OldThreshold = defaultN + 1 })
+	require.True(t, errors.Is(err, ErrInvalidOldThreshold))
+	err = reshare(func(c *Config) { c.OldThreshold = defaultT + 1 })
+	require.True(t, errors.Is(err, ErrInconsistentShare))
+	err = reshare(func(c *Config) {
+		c.Share = &DistKeyShare{Commits: dks.Commits, Share: &share.PriShare{I: 1, V: dks.Share.V}}
+	})
+	require.True(t, errors.Is(err, ErrInconsistentShare))
+	err = reshare(func(c *Config) { c.Threshold = defaultN + 1 })
+	require.True(t, errors.Is(err, ErrInvalidThreshold))
+}
+
 func TestDKGDeal(t *testing.T) {
 	_, _, dkgs := generate(defaultN, defaultT)
 	dkg := dkgs[0]
 
 	dks, err := dkg.DistKeyShare()
-	require.Error(t, err)
+	require.True(t, errors.Is(err, ErrNotCertified))
 	require.Nil(t, dks)
 
 	deals, err := dkg.Deals()
@@ -115,10 +198,19 @@ func TestDKGProcessDeal(t *testing.T) {
 	require.True(t, ok)
 	require.Equal(t, uint32(0), resp.Index)
 
-	// duplicate
-	resp, err = rec.ProcessDeal(deal)
+	// duplicate, which gives the same response
+	again, err := rec.ProcessDeal(deal)
+	require.NoError(t, err)
+	require.Equal(t, resp, again)
+
+	// another encryption of the deal of the same dealer, since Deals
+	// returns the same deals again
+	enc, err := dkg.dealer.EncryptedDeal(1)
+	require.NoError(t, err)
+	other := signDeal(t, dkg, &Deal{Index: deal.Index, Deal: enc})
+	resp, err = rec.ProcessDeal(other)
 	require.Nil(t, resp)
-	require.Error(t, err)
+	require.True(t, errors.Is(err, vss.ErrDealAlreadyProcessed))
 
 	// wrong index
 	goodIdx := deal.Index
@@ -138,6 +230,203 @@ func TestDKGProcessDeal(t *testing.T) {
 
 }
 
+func TestDKGVerifyDeal(t *testing.T) {
+	_, _, dkgs := generate(defaultN, defaultT)
+	deals, err := dkgs[0].Deals()
+	require.NoError(t, err)
+	rec := dkgs[1]
+	deal := deals[1]
+
+	// nothing is stored by the verification
+	require.NoError(t, rec.VerifyDeal(deal))
+	require.Empty(t, rec.processedDeals)
+	require.Empty(t, rec.log)
+	require.Nil(t, rec.verifiers[deal.Index].Deal())
+
+	goodSig := deal.Signature
+	deal.Signature = randomBytes(len(goodSig))
+	require.True(t, errors.Is(rec.VerifyDeal(deal), vss.ErrInvalidSignature))
+	deal.Signature = goodSig
+	require.True(t, errors.Is(rec.VerifyDeal(nil), ErrMalformed))
+	require.True(t, errors.Is(rec.VerifyDeal(&Deal{Index: defaultN, Deal: deal.Deal}), ErrDealerIndex))
+
+	// a deal which would give a complaint is rejected
+	bad := malformedDeal(t, dkgs, func(d *vss.Deal) {
+		d.SecShare.V = suite.Scalar().Pick(suite.RandomStream())
+	})
+	require.True(t, errors.Is(rec.VerifyDeal(bad), vss.ErrInvalidDeal))
+
+	resp, err := rec.ProcessDeal(deal)
+	require.NoError(t, err)
+	require.Equal(t, vss.StatusApproval, resp.Response.Status)
+	require.NoError(t, rec.VerifyDeal(deal))
+}
+
+// malformedDeal returns the deal of the first dkg to the second one, after
+// applying mutate to its plaintext. The deal is encrypted and signed by the
+// dealer as usual.
+func malformedDeal(t *testing.T, dkgs []*DistKeyGenerator, mutate func(*vss.Deal)) *Deal {
+	dealer := dkgs[0].dealer
+	plain, err := dealer.PlaintextDeal(1)
+	require.NoError(t, err)
+	saved := *plain
+	saved.SecShare = &share.PriShare{I: plain.SecShare.I, V: plain.SecShare.V}
+	mutate(plain)
+	// keep the session id consistent with the content of the deal
+	plain.SessionID, err = vss.SessionID(suite, dkgs[0].pub, dkgs[0].c.NewNodes, plain.Commitments, int(plain.T))
+	require.NoError(t, err)
+	enc, err := dealer.EncryptedDeal(1)
+	*plain = saved
+	require.NoError(t, err)
+	return signDeal(t, dkgs[0], &Deal{Index: 0, Deal: enc})
+}
+
+// signDeal signs the deal with the longterm key of the dkg.
+func signDeal(t *testing.T, d *DistKeyGenerator, dd *Deal) *Deal {
+	var err error
+	dd.Signature, err = schnorr.Sign(suite, d.long, dd.signatureMessage())
+	require.NoError(t, err)
+	return dd
+}
+
+func TestDKGProcessDealMalformed(t *testing.T) {
+	cases := []struct {
+		name string
+		// deal returns the deal to give to the second dkg
+		deal func(dkgs []*DistKeyGenerator, dd *Deal) *Deal
+		// err is the error expected, or nil if a complaint is expected
+		err error
+	}{
+		{"nil deal", func(_ []*DistKeyGenerator, _ *Deal) *Deal {
+			return nil
+		}, ErrMalformed},
+		{"nil encrypted deal", func(_ []*DistKeyGenerator, dd *Deal) *Deal {
+			dd.Deal = nil
+			return dd
+		}, ErrMalformed},
+		{"dealer index out of bounds", func(_ []*DistKeyGenerator, dd *Deal) *Deal {
+			dd.Index = uint32(defaultN)
+			return dd
+		}, ErrDealerIndex},
+		{"signature from another dealer", func(dkgs []*DistKeyGenerator, dd *Deal) *Deal {
+			return signDeal(t, dkgs[2], dd)
+		}, vss.ErrInvalidSignature},
+		{"truncated signature", func(_ []*DistKeyGenerator, dd *Deal) *Deal {
+			dd.Signature = dd.Signature[:len(dd.Signature)-1]
+			return dd
+		}, vss.ErrInvalidSignature},
+		{"invalid DH key signature", func(_ []*DistKeyGenerator, dd *Deal) *Deal {
+			dd.Deal.Signature = randomBytes(len(dd.Deal.Signature))
+			return dd
+		}, vss.ErrInvalidSignature},
+		{"truncated DH key", func(dkgs []*DistKeyGenerator, dd *Deal) *Deal {
+			var err error
+			dd.Deal.DHKey = dd.Deal.DHKey[:len(dd.Deal.DHKey)-1]
+			dd.Deal.Signature, err = schnorr.Sign(suite, dkgs[0].long, dd.Deal.DHKey)
+			require.NoError(t, err)
+			return dd
+		}, vss.ErrMalformed},
+		{"truncated nonce", func(_ []*DistKeyGenerator, dd *Deal) *Deal {
+			dd.Deal.Nonce = dd.Deal.Nonce[:len(dd.Deal.Nonce)-1]
+			return dd
+		}, vss.ErrMalformed},
+		{"truncated cipher", func(dkgs []*DistKeyGenerator, dd *Deal) *Deal {
+			dd.Deal.Cipher = dd.Deal.Cipher[:len(dd.Deal.Cipher)/2]
+			return signDeal(t, dkgs[0], dd)
+		}, vss.ErrMalformed},
+		{"nil share", func(dkgs []*DistKeyGenerator, _ *Deal) *Deal {
+			return malformedDeal(t, dkgs, func(d *vss.Deal) { d.SecShare.V = nil })
+		}, vss.ErrMalformed},
+		{"no commitments", func(dkgs []*DistKeyGenerator, _ *Deal) *Deal {
+			return malformedDeal(t, dkgs, func(d *vss.Deal) { d.Commitments = nil })
+		}, vss.ErrMalformed},
+		{"share index", func(dkgs []*DistKeyGenerator, _ *Deal) *Deal {
+			return malformedDeal(t, dkgs, func(d *vss.Deal) { d.SecShare.I = defaultN })
+		}, vss.ErrDealOutOfIndex},
+		{"legacy deal", func(_ []*DistKeyGenerator, dd *Deal) *Deal {
+			dd.Deal.Version, dd.Deal.SessionID, dd.Deal.Index = 0, nil, 0
+			return dd
+		}, vss.ErrLegacyDeal},
+		{"deal of another session", func(_ []*DistKeyGenerator, dd *Deal) *Deal {
+			dd.Deal.SessionID = randomBytes(len(dd.Deal.SessionID))
+			return dd
+		}, vss.ErrMalformed},
+		{"deal of another verifier", func(_ []*DistKeyGenerator, dd *Deal) *Deal {
+			dd.Deal.Index = 2
+			return dd
+		}, vss.ErrDealOutOfIndex},
+		{"too few commitments", func(dkgs []*DistKeyGenerator, _ *Deal) *Deal {
+			return malformedDeal(t, dkgs, func(d *vss.Deal) { d.Commitments = d.Commitments[:1] })
+		}, nil},
+		{"too many commitments", func(dkgs []*DistKeyGenerator, _ *Deal) *Deal {
+			return malformedDeal(t, dkgs, func(d *vss.Deal) {
+				d.Commitments = append(d.Commitments[:len(d.Commitments):len(d.Commitments)], suite.Point().Pick(suite.RandomStream()))
+			})
+		}, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, _, dkgs := generate(defaultN, defaultT)
+			deals, err := dkgs[0].Deals()
+			require.NoError(t, err)
+
+			resp, err := dkgs[1].ProcessDeal(c.deal(dkgs, deals[1]))
+			if c.err == nil {
+				require.NoError(t, err)
+				require.Equal(t, vss.StatusComplaint, resp.Response.Status)
+				return
+			}
+			require.Nil(t, resp)
+			require.True(t, errors.Is(err, c.err), "unexpected error: %v", err)
+		})
+	}
+}
+
+func TestDKGAcceptLegacyDeals(t *testing.T) {
+	pubs, secs, dkgs := generate(defaultN, defaultT)
+	deals, err := dkgs[0].Deals()
+	require.NoError(t, err)
+	d, err := NewDistKeyHandler(&Config{
+		Suite:             suite,
+		Longterm:          secs[1],
+		NewNodes:          pubs,
+		Threshold:         defaultT,
+		AcceptLegacyDeals: true,
+	})
+	require.NoError(t, err)
+
+	// the version is authenticated: a new deal does not pass for a legacy one
+	legacy := *deals[1].Deal
+	legacy.Version, legacy.SessionID, legacy.Index = 0, nil, 0
+	_, err = d.ProcessDeal(&Deal{Index: 0, Deal: &legacy, Signature: deals[1].Signature})
+	require.True(t, errors.Is(err, vss.ErrMalformed))
+	_, err = dkgs[1].ProcessDeal(&Deal{Index: 0, Deal: &legacy, Signature: deals[1].Signature})
+	require.True(t, errors.Is(err, vss.ErrLegacyDeal))
+
+	resp, err := d.ProcessDeal(deals[1])
+	require.NoError(t, err)
+	require.Equal(t, vss.StatusApproval, resp.Response.Status)
+}
+
+func TestDKGProcessMalformedMessages(t *testing.T) {
+	_, _, dkgs := generate(defaultN, defaultT)
+
+	j, err := dkgs[0].ProcessResponse(nil)
+	require.Nil(t, j)
+	require.True(t, errors.Is(err, ErrMalformed))
+	j, err = dkgs[0].ProcessResponse(&Response{Index: 1})
+	require.Nil(t, j)
+	require.True(t, errors.Is(err, ErrMalformed))
+	j, err = dkgs[0].ProcessResponse(&Response{Index: defaultN, Response: &vss.Response{}})
+	require.Nil(t, j)
+	require.True(t, errors.Is(err, ErrDealerIndex))
+
+	require.True(t, errors.Is(dkgs[0].ProcessJustification(nil), ErrMalformed))
+	require.True(t, errors.Is(dkgs[0].ProcessJustification(&Justification{Index: 1}), ErrMalformed))
+	err = dkgs[0].ProcessJustification(&Justification{Index: defaultN, Justification: &vss.Justification{}})
+	require.True(t, errors.Is(err, ErrDealerIndex))
+}
+
 func TestDKGProcessResponse(t *testing.T) {
 	// first peer generates wrong deal
 	// second peer processes it and returns a complaint
@@ -180,14 +469,22 @@ func TestDKGProcessResponse(t *testing.T) {
 	resp.Response.Signature = randomBytes(len(goodSig))
 	j, err = dkg.ProcessResponse(resp)
 	require.Nil(t, j)
-	require.Error(t, err)
+	require.True(t, errors.Is(err, vss.ErrInvalidSignature))
+	require.Contains(t, err.Error(), key.Fingerprint(suite, dkg.c.NewNodes[resp.Index]))
 	resp.Response.Signature = goodSig
 
 	// valid complaint from our deal
+	retry := &Response{Index: resp.Index, Response: &vss.Response{}}
+	*retry.Response = *resp.Response
 	j, err = dkg.ProcessResponse(resp)
 	require.NotNil(t, j)
 	require.Nil(t, err)
 
+	// the same complaint received again gives back the same justification
+	j2, err := dkg.ProcessResponse(retry)
+	require.Equal(t, j, j2)
+	require.Nil(t, err)
+
 	// valid complaint from another deal from another peer
 	dkg2 := dkgs[2]
 	require.Nil(t, err)
@@ -222,6 +519,10 @@ func TestDKGProcessResponse(t *testing.T) {
 	j, err = dkg.ProcessResponse(resp12)
 	require.Nil(t, j)
 	require.Nil(t, err)
+	// duplicate response
+	j, err = dkg.ProcessResponse(resp12)
+	require.Nil(t, j)
+	require.Nil(t, err)
 
 	// Justification part:
 	// give the complaint to the dealer
@@ -244,6 +545,302 @@ func TestDKGProcessResponse(t *testing.T) {
 
 }
 
+// transmitResponse and transmitJustification return a copy of the message
+// going through its encoding, as if it were sent over the network.
+func transmitResponse(t *testing.T, resp *Response) *Response {
+	buff, err := resp.MarshalBinary()
+	require.NoError(t, err)
+	r := &Response{}
+	require.NoError(t, r.UnmarshalBinary(suite, buff))
+	return r
+}
+
+func transmitJustification(t *testing.T, j *Justification) *Justification {
+	buff, err := j.MarshalBinary()
+	require.NoError(t, err)
+	r := &Justification{}
+	require.NoError(t, r.UnmarshalBinary(suite, buff))
+	return r
+}
+
+// TestDKGWrongfulComplaint checks that a dealer wrongfully complained about
+// is qualified again by every node once it justified its deal.
+func TestDKGWrongfulComplaint(t *testing.T) {
+	_, secs, dkgs := generate(defaultN, defaultT)
+	// late receives the justification before the complaint
+	const dealer, accuser, late = 0, 1, defaultN - 1
+
+	var resps []*Response
+	var complaint *Response
+	for _, d := range dkgs {
+		deals, err := d.Deals()
+		require.NoError(t, err)
+		for i, deal := range deals {
+			resp, err := dkgs[i].ProcessDeal(deal)
+			require.NoError(t, err)
+			require.Equal(t, vss.StatusApproval, resp.Response.Status)
+			if resp.Index == dealer && resp.Response.Index == accuser {
+				// the accuser complains although the deal is valid
+				resp = transmitResponse(t, resp)
+				resp.Response.Status = vss.StatusComplaint
+				resp.Response.Signature, err = schnorr.Sign(suite, secs[accuser], resp.Response.Hash(suite))
+				require.NoError(t, err)
+				complaint = resp
+			}
+			resps = append(resps, resp)
+		}
+	}
+
+	var justifications []*Justification
+	for _, resp := range resps {
+		for i, d := range dkgs {
+			if resp.Response.Index == uint32(i) || (i == late && resp == complaint) {
+				continue
+			}
+			j, err := d.ProcessResponse(transmitResponse(t, resp))
+			require.NoError(t, err)
+			if j != nil {
+				// only the dealer justifies, and only the complaint
+				require.Equal(t, dealer, i)
+				require.Equal(t, complaint, resp)
+				justifications = append(justifications, j)
+			}
+		}
+	}
+	require.Len(t, justifications, 1)
+	j := justifications[0]
+	require.Equal(t, uint32(dealer), j.Index)
+	require.Equal(t, uint32(accuser), j.Justification.Index)
+
+	// until the justification, the dealer is out of QUAL
+	for i, d := range dkgs {
+		if i == dealer || i == accuser || i == late {
+			continue
+		}
+		require.False(t, d.Certified())
+		require.False(t, d.isInQUAL(dealer))
+		require.Len(t, d.QUAL(), defaultN-1)
+	}
+
+	// a justification that is not signed by the dealer is rejected, and does
+	// not disqualify the dealer
+	forged := transmitJustification(t, j)
+	sig, err := schnorr.Sign(suite, secs[2], forged.Justification.Hash(suite))
+	require.NoError(t, err)
+	forged.Justification.Signature = sig
+	require.True(t, errors.Is(dkgs[2].ProcessJustification(forged), vss.ErrInvalidSignature))
+	// as well as a justification of another dealer than the accused one
+	forged = transmitJustification(t, j)
+	forged.Index = 2
+	require.True(t, errors.Is(dkgs[3].ProcessJustification(forged), vss.ErrInvalidSessionID))
+
+	// a justification received before the complaint can be given again later
+	require.True(t, errors.Is(dkgs[late].ProcessJustification(transmitJustification(t, j)),
+		vss.ErrUnexpectedJustification))
+	_, err = dkgs[late].ProcessResponse(transmitResponse(t, complaint))
+	require.NoError(t, err)
+	require.False(t, dkgs[late].isInQUAL(dealer))
+
+	for i, d := range dkgs {
+		if i == dealer {
+			continue
+		}
+		err := d.ProcessJustification(transmitJustification(t, j))
+		if i == accuser {
+			// the accuser stored its own approval and not the complaint
+			require.True(t, errors.Is(err, vss.ErrUnexpectedJustification))
+			continue
+		}
+		require.NoError(t, err)
+	}
+
+	var public kyber.Point
+	for _, d := range dkgs {
+		require.True(t, d.Certified())
+		require.Len(t, d.QUAL(), defaultN)
+		require.Len(t, d.QualifiedShares(), defaultN)
+		dks, err := d.DistKeyShare()
+		require.NoError(t, err)
+		if public == nil {
+			public = dks.Public()
+		}
+		require.True(t, public.Equal(dks.Public()))
+	}
+
+	// the justification answers no complaint anymore
+	require.True(t, errors.Is(dkgs[2].ProcessJustification(j), vss.ErrUnexpectedJustification))
+}
+
+// TestDKGOnJustification checks that Config.OnJustification receives the
+// justifications as they are produced, and can broadcast them right away.
+func TestDKGOnJustification(t *testing.T) {
+	_, secs, dkgs := generate(defaultN, defaultT)
+	const dealer, accuser = 1, 2
+
+	var notified []*Justification
+	dkgs[dealer].c.OnJustification = func(j *Justification) {
+		// the lock of the dealer is released
+		require.Len(t, dkgs[dealer].QUAL(), defaultN)
+		notified = append(notified, j)
+		for i, d := range dkgs {
+			if i != dealer && i != accuser {
+				require.NoError(t, d.ProcessJustification(transmitJustification(t, j)))
+			}
+		}
+	}
+
+	var resps []*Response
+	for _, d := range dkgs {
+		deals, err := d.Deals()
+		require.NoError(t, err)
+		for i, deal := range deals {
+			resp, err := dkgs[i].ProcessDeal(deal)
+			require.NoError(t, err)
+			if resp.Index == dealer && resp.Response.Index == accuser {
+				resp = transmitResponse(t, resp)
+				resp.Response.Status = vss.StatusComplaint
+				resp.Response.Signature, err = schnorr.Sign(suite, secs[accuser], resp.Response.Hash(suite))
+				require.NoError(t, err)
+			}
+			resps = append(resps, resp)
+		}
+	}
+
+	// the dealer receives the responses last, in a batch, so that the others
+	// hold the complaint when the justification arrives
+	for i, d := range dkgs {
+		if i == dealer {
+			continue
+		}
+		for _, resp := range resps {
+			if resp.Response.Index != uint32(i) {
+				_, err := d.ProcessResponse(transmitResponse(t, resp))
+				require.NoError(t, err)
+			}
+		}
+	}
+	var received []*Response
+	for _, resp := range resps {
+		if resp.Response.Index != dealer {
+			received = append(received, transmitResponse(t, resp))
+		}
+	}
+	justifications, errs := dkgs[dealer].ProcessResponses(received)
+	require.Empty(t, errs)
+	require.Len(t, justifications, 1)
+	require.Equal(t, justifications, notified)
+
+	for i, d := range dkgs {
+		if i == accuser {
+			continue
+		}
+		require.True(t, d.Certified())
+		require.Len(t, d.QUAL(), defaultN)
+	}
+}
+
+// TestDKGProcessResponses checks that processing the responses in a batch
+// yields the same errors, justifications and qualified set as processing them
+// one by one.
+func TestDKGProcessResponses(t *testing.T) {
+	_, secs, dkgs := generate(defaultN, defaultT)
+	const dealer, accuser = 1, 2
+
+	var resps []*Response
+	var complaint int
+	for _, d := range dkgs {
+		deals, err := d.Deals()
+		require.NoError(t, err)
+		for i, deal := range deals {
+			resp, err := dkgs[i].ProcessDeal(deal)
+			require.NoError(t, err)
+			if resp.Index == dealer && resp.Response.Index == accuser {
+				complaint = len(resps)
+				resp = transmitResponse(t, resp)
+				resp.Response.Status = vss.StatusComplaint
+				resp.Response.Signature, err = schnorr.Sign(suite, secs[accuser], resp.Response.Hash(suite))
+				require.NoError(t, err)
+			}
+			resps = append(resps, resp)
+		}
+	}
+	forged := transmitResponse(t, resps[1])
+	forged.Response.Signature = randomBytes(len(forged.Response.Signature))
+	outOfBounds := transmitResponse(t, resps[2])
+	outOfBounds.Index = defaultN
+	resps = append(resps, transmitResponse(t, resps[0]), forged, nil, outOfBounds, transmitResponse(t, resps[0]))
+
+	// even nodes process the responses one by one, odd nodes in a batch
+	var expected map[int]error
+	var justification *Justification
+	for i, d := range dkgs {
+		// every node needs its own copy since the justification turns the
+		// stored complaint into an approval in place
+		received := make([]*Response, len(resps))
+		for k, resp := range resps {
+			if resp != nil {
+				received[k] = transmitResponse(t, resp)
+			}
+		}
+		var justifications []*Justification
+		var errs map[int]error
+		if i%2 == 0 {
+			errs = make(map[int]error)
+			for k, resp := range received {
+				j, err := d.ProcessResponse(resp)
+				if err != nil {
+					errs[k] = err
+				} else if j != nil {
+					justifications = append(justifications, j)
+				}
+			}
+		} else {
+			justifications, errs = d.ProcessResponses(received)
+		}
+		if expected == nil {
+			expected = errs
+			require.Len(t, expected, 3)
+			require.True(t, errors.Is(expected[len(resps)-4], vss.ErrInvalidSignature))
+			require.True(t, errors.Is(expected[len(resps)-3], ErrMalformed))
+			require.True(t, errors.Is(expected[len(resps)-2], ErrDealerIndex))
+		}
+		if i == accuser {
+			// the accuser approved the deal, and rejects the complaint made
+			// in its name
+			require.True(t, errors.Is(errs[complaint], vss.ErrDuplicateResponse))
+			delete(errs, complaint)
+		}
+		require.Equal(t, len(expected), len(errs))
+		for k, err := range expected {
+			require.EqualError(t, errs[k], err.Error())
+		}
+		if i != dealer {
+			require.Empty(t, justifications)
+			continue
+		}
+		require.Len(t, justifications, 1)
+		require.Equal(t, uint32(accuser), justifications[0].Justification.Index)
+		justification = justifications[0]
+	}
+
+	var public kyber.Point
+	for i, d := range dkgs {
+		if i != dealer && i != accuser {
+			require.False(t, d.Certified())
+			require.False(t, d.isInQUAL(dealer))
+			require.NoError(t, d.ProcessJustification(transmitJustification(t, justification)))
+		}
+		require.True(t, d.Certified())
+		require.ElementsMatch(t, dkgs[0].QUAL(), d.QUAL())
+		dks, err := d.DistKeyShare()
+		require.NoError(t, err)
+		if public == nil {
+			public = dks.Public()
+		}
+		require.True(t, public.Equal(dks.Public()))
+	}
+}
+
 // Test Resharing to a group with one mode node BUT only a threshold of dealers
 // are present during the resharing.
 func TestDKGResharingThreshold(t *testing.T) {
@@ -353,7 +950,7 @@ func TestDKGResharingThreshold(t *testing.T) {
 	}
 
 	for _, dkg := range selectedDkgs {
-		dkg.SetTimeout()
+		dkg.Finish()
 	}
 
 	dkss := make([]*DistKeyShare, 0, len(selectedDkgs))
@@ -490,7 +1087,7 @@ func TestDKGThreshold(t *testing.T) {
 				require.Equal(t, 0, app)
 			}
 		}
-		dkg.SetTimeout()
+		dkg.Finish()
 	}
 
 	for _, dkg := range thrDKGs {
@@ -551,6 +1148,474 @@ func TestDistKeyShare(t *testing.T) {
 
 	commitSecret := suite.Point().Mul(secret, nil)
 	require.Equal(t, dkss[0].Public().String(), commitSecret.String())
+
+	pubShares := PublicShares(suite, dkss[0].Commits, defaultN)
+	for i, dks := range dkss {
+		pub := suite.Point().Mul(dks.Share.V, nil)
+		require.Equal(t, i, pubShares[i].I)
+		require.True(t, pub.Equal(pubShares[i].V))
+		for _, other := range dkss {
+			require.True(t, pub.Equal(other.PublicShare(i).V))
+		}
+	}
+	require.Nil(t, (&DistKeyShare{Commits: dkss[0].Commits}).PublicShare(0))
+}
+
+func TestDistKeyShareWipe(t *testing.T) {
+	_, _, dkgs := generate(defaultN, defaultT)
+	fullExchange(t, dkgs, true)
+	dkg := dkgs[0]
+	deal, err := dkg.dealer.PlaintextDeal(1)
+	require.NoError(t, err)
+
+	dks, err := dkg.DistKeyShare()
+	require.NoError(t, err)
+	zero := suite.Scalar().Zero()
+	// the secret material of the generator is erased...
+	require.True(t, dkg.dealer.PrivatePoly().Secret().Equal(zero))
+	require.True(t, deal.SecShare.V.Equal(zero))
+	_, err = dkg.dealer.PlaintextDeal(1)
+	require.True(t, errors.Is(err, vss.ErrWiped))
+	for _, v := range dkg.verifiers {
+		require.True(t, v.Deal().SecShare.V.Equal(zero))
+	}
+	// ...but not the share nor the private polynomial given to the caller
+	require.False(t, dks.PrivatePoly[0].Equal(zero))
+	pub := dks.PublicShare(dks.Share.I)
+	require.True(t, suite.Point().Mul(dks.Share.V, nil).Equal(pub.V))
+
+	// the caller erasing its copy does not change the next one
+	dks.Share.Zero()
+	share.CoefficientsToPriPoly(suite, dks.PrivatePoly).Zero()
+	again, err := dkg.DistKeyShare()
+	require.NoError(t, err)
+	require.True(t, again.Share == again.Shares[0])
+	require.True(t, suite.Point().Mul(again.Share.V, nil).Equal(pub.V))
+	require.False(t, again.PrivatePoly[0].Equal(zero))
+}
+
+// TestDKGFinish reproduces the divergence of the qualified set when a
+// straggler response is processed after the distributed key has been computed,
+// and checks that Finish freezes the qualified set.
+// TestDKGPairingGroup checks that the distributed key share records the group
+// of its commitments, so that the threshold signatures are recovered with the
+// scheme having its public keys in that group.
+func TestDKGPairingGroup(t *testing.T) {
+	pairingSuite := bn256.NewSuite()
+	g1 := pairing.G1Suite(pairingSuite)
+	n, thr := defaultN, defaultT
+	secs := make([]kyber.Scalar, n)
+	pubs := make([]kyber.Point, n)
+	for i := range secs {
+		secs[i] = g1.Scalar().Pick(random.New())
+		pubs[i] = g1.Point().Mul(secs[i], nil)
+	}
+	dkgs := make([]*DistKeyGenerator, n)
+	for i := range dkgs {
+		d, err := NewDistKeyGenerator(g1, secs[i], pubs, thr)
+		require.NoError(t, err)
+		dkgs[i] = d
+	}
+	fullExchange(t, dkgs, true)
+
+	msg := []byte("Hello threshold Boneh-Lynn-Shacham")
+	var sigs [][]byte
+	var dks *DistKeyShare
+	for _, d := range dkgs[:thr] {
+		var err error
+		dks, err = d.DistKeyShare()
+		require.NoError(t, err)
+		require.Equal(t, pairingSuite.G1().String(), dks.Group().String())
+		sig, err := tbls.NewSchemeOnG2(pairingSuite).Sign(dks.PriShare(), msg)
+		require.NoError(t, err)
+		sigs = append(sigs, sig)
+	}
+
+	// assuming the commitments are on G2 used to fail obscurely
+	_, err := tbls.Recover(pairingSuite, share.NewPubPoly(pairingSuite.G2(), nil, dks.Commitments()), msg, sigs, thr, n)
+	require.True(t, errors.Is(err, tbls.ErrGroupMismatch))
+
+	pub := share.NewPubPoly(dks.Group(), nil, dks.Commitments())
+	sig, err := tbls.NewSchemeOnG2(pairingSuite).Recover(pub, msg, sigs, thr, n)
+	require.NoError(t, err)
+	require.NoError(t, bls.NewSchemeOnG2(pairingSuite).Verify(dks.Public(), msg, sig))
+}
+
+// TestDistKeyShareFromShamir imports the shares of a trusted dealer, reshares
+// them so that the dealer does not know the secret anymore, and signs with
+// the new shares under the public key of the dealer.
+func TestDistKeyShareFromShamir(t *testing.T) {
+	pairingSuite := bn256.NewSuite()
+	g2 := pairing.G2Suite(pairingSuite)
+	n, thr := 5, 3
+	secs := make([]kyber.Scalar, n)
+	pubs := make([]kyber.Point, n)
+	for i := range secs {
+		secs[i] = g2.Scalar().Pick(random.New())
+		pubs[i] = g2.Point().Mul(secs[i], nil)
+	}
+
+	// the trusted dealer shares its secret
+	secret := g2.Scalar().Pick(random.New())
+	priPoly := share.NewPriPoly(g2, thr, secret, random.New())
+	commits := share.CommitmentsFromPriPoly(priPoly)
+	public := g2.Point().Mul(secret, nil)
+	shares := priPoly.Shares(n)
+
+	imported := make([]*DistKeyShare, n)
+	for i, sh := range shares {
+		dks, err := DistKeyShareFromShamir(g2, sh, commits)
+		require.NoError(t, err)
+		require.True(t, public.Equal(dks.Public()))
+		require.True(t, dks.PublicShare(i).V.Equal(g2.Point().Mul(sh.V, nil)))
+		imported[i] = dks
+	}
+	_, err := DistKeyShareFromShamir(g2, shares[1], commits[1:])
+	require.Error(t, err)
+	_, err = DistKeyShareFromShamir(g2, &share.PriShare{I: 0, V: shares[1].V}, commits)
+	require.True(t, errors.Is(err, ErrInconsistentShare))
+	_, err = DistKeyShareFromShamir(g2, shares[0], commits[:1])
+	require.True(t, errors.Is(err, ErrInvalidThreshold))
+	// the length of the commitments is the old threshold of the resharing
+	_, err = NewDistKeyHandler(&Config{
+		Suite:        g2,
+		Longterm:     secs[0],
+		OldNodes:     pubs,
+		NewNodes:     pubs,
+		Share:        imported[0],
+		OldThreshold: thr + 1,
+	})
+	require.True(t, errors.Is(err, ErrInconsistentShare))
+
+	// the holders reshare the secret among themselves
+	dkgs := make([]*DistKeyGenerator, n)
+	for i := range dkgs {
+		dkgs[i], err = NewDistKeyHandler(&Config{
+			Suite:        g2,
+			Longterm:     secs[i],
+			OldNodes:     pubs,
+			NewNodes:     pubs,
+			Share:        imported[i],
+			Threshold:    thr,
+			OldThreshold: thr,
+		})
+		require.NoError(t, err)
+	}
+	fullExchange(t, dkgs, true)
+
+	msg := []byte("Hello threshold Boneh-Lynn-Shacham")
+	scheme := tbls.NewSchemeOnG1(pairingSuite)
+	var sigs [][]byte
+	var dks *DistKeyShare
+	for i, d := range dkgs {
+		dks, err = d.DistKeyShare()
+		require.NoError(t, err)
+		require.True(t, public.Equal(dks.Public()))
+		require.False(t, dks.Share.V.Equal(shares[i].V))
+		sig, err := scheme.Sign(dks.PriShare(), msg)
+		require.NoError(t, err)
+		sigs = append(sigs, sig)
+	}
+	pubPoly := share.NewPubPoly(dks.Group(), nil, dks.Commitments())
+	sig, err := scheme.Recover(pubPoly, msg, sigs[n-thr:], thr, n)
+	require.NoError(t, err)
+	require.NoError(t, bls.NewSchemeOnG1(pairingSuite).Verify(public, msg, sig))
+}
+
+// TestDKGNonce checks that the messages of a run cannot be replayed in
+// another run with the same keys and another nonce.
+func TestDKGNonce(t *testing.T) {
+	pubs, secs, _ := generate(defaultN, defaultT)
+	run := func(nonce string) []*DistKeyGenerator {
+		dkgs := make([]*DistKeyGenerator, defaultN)
+		for i := range dkgs {
+			var err error
+			dkgs[i], err = NewDistKeyHandler(&Config{
+				Suite:     suite,
+				Longterm:  secs[i],
+				NewNodes:  pubs,
+				Threshold: defaultT,
+				Nonce:     []byte(nonce),
+			})
+			require.NoError(t, err)
+		}
+		return dkgs
+	}
+	previous, current := run("epoch 1"), run("epoch 2")
+
+	old, err := previous[0].Deals()
+	require.NoError(t, err)
+	oldResp, err := previous[1].ProcessDeal(old[1])
+	require.NoError(t, err)
+	_, err = current[1].ProcessDeal(old[1])
+	require.True(t, errors.Is(err, vss.ErrSessionIDMismatch))
+
+	deals, err := current[0].Deals()
+	require.NoError(t, err)
+	_, err = current[2].ProcessDeal(deals[2])
+	require.NoError(t, err)
+	_, err = current[2].ProcessResponse(oldResp)
+	require.True(t, errors.Is(err, vss.ErrInvalidSessionID))
+
+	current = run("epoch 2")
+	fullExchange(t, current, true)
+	tr, err := current[0].Transcript()
+	require.NoError(t, err)
+	require.Equal(t, []byte("epoch 2"), tr.Nonce)
+	require.NoError(t, VerifyTranscript(suite, tr))
+	tr.Nonce = []byte("epoch 1")
+	require.True(t, errors.Is(VerifyTranscript(suite, tr), ErrInvalidTranscript))
+}
+
+func TestDKGWeights(t *testing.T) {
+	pairingSuite := bn256.NewSuite()
+	g1 := pairing.G1Suite(pairingSuite)
+	weights := []int{3, 2, 1, 1}
+	n, thr, total := len(weights), 4, 7
+	secs := make([]kyber.Scalar, n)
+	pubs := make([]kyber.Point, n)
+	for i := range secs {
+		secs[i] = g1.Scalar().Pick(random.New())
+		pubs[i] = g1.Point().Mul(secs[i], nil)
+	}
+	dkgs := make([]*DistKeyGenerator, n)
+	for i := range dkgs {
+		d, err := NewDistKeyHandler(&Config{
+			Suite:     g1,
+			Longterm:  secs[i],
+			NewNodes:  pubs,
+			Threshold: thr,
+			Weights:   weights,
+		})
+		require.NoError(t, err)
+		dkgs[i] = d
+	}
+
+	// one deal per share, sent to the holder of the share
+	var resps []*Response
+	for i, d := range dkgs {
+		deals, err := d.Deals()
+		require.NoError(t, err)
+		require.Len(t, deals, total-weights[i])
+		for s, deal := range deals {
+			resp, err := dkgs[d.ShareHolder(s)].ProcessDeal(deal)
+			require.NoError(t, err)
+			require.Equal(t, vss.StatusApproval, resp.Response.Status)
+			resps = append(resps, resp)
+		}
+	}
+	for _, resp := range resps {
+		for _, d := range dkgs {
+			j, err := d.ProcessResponse(resp)
+			require.NoError(t, err)
+			require.Nil(t, j)
+		}
+	}
+	for _, d := range dkgs {
+		require.Equal(t, d.ExpectedDeals(), d.ReceivedDeals())
+		require.True(t, d.Certified())
+		require.Len(t, d.QualifiedShares(), total)
+	}
+
+	// the weight-3 node and the weight-2 node hold enough shares to sign
+	scheme := tbls.NewSchemeOnG2(pairingSuite)
+	msg := []byte("Hello weighted threshold signature")
+	var sigs [][]byte
+	var dks *DistKeyShare
+	var shares [][]*share.PriShare
+	for i, d := range dkgs {
+		var err error
+		dks, err = d.DistKeyShare()
+		require.NoError(t, err)
+		require.Len(t, dks.Shares, weights[i])
+		require.Equal(t, dks.Share, dks.Shares[0])
+		require.Equal(t, share.Weights(weights).Indices()[i][0], dks.Share.I)
+		shares = append(shares, dks.Shares)
+		for _, sh := range dks.Shares {
+			sig, err := scheme.Sign(sh, msg)
+			require.NoError(t, err)
+			sigs = append(sigs, sig)
+		}
+	}
+	pub := share.NewPubPoly(dks.Group(), nil, dks.Commitments())
+	for _, sig := range sigs {
+		require.NoError(t, scheme.VerifyPartial(pub, msg, sig))
+	}
+	sig, err := scheme.Recover(pub, msg, sigs[:5], thr, total)
+	require.NoError(t, err)
+	require.NoError(t, bls.NewSchemeOnG2(pairingSuite).Verify(dks.Public(), msg, sig))
+
+	// the other nodes only hold 2+1+1 shares without the weight-3 node, which
+	// is just enough, but not with the weight-2 node missing too
+	_, err = scheme.Recover(pub, msg, sigs[3:], thr, total)
+	require.NoError(t, err)
+	_, err = scheme.Recover(pub, msg, sigs[5:], thr, total)
+	require.Error(t, err)
+
+	// likewise for the secret
+	secret, err := share.RecoverSecretWeighted(g1, weights, append(shares[0], shares[1]...), thr)
+	require.NoError(t, err)
+	require.True(t, dks.Public().Equal(g1.Point().Mul(secret, nil)))
+	_, err = share.RecoverSecretWeighted(g1, weights, append(shares[2], shares[3]...), thr)
+	require.Error(t, err)
+
+	for _, w := range [][]int{{3, 2, 1}, {3, 0, 1, 1}} {
+		_, err = NewDistKeyHandler(&Config{
+			Suite:    g1,
+			Longterm: secs[0],
+			NewNodes: pubs,
+			Weights:  w,
+		})
+		require.True(t, errors.Is(err, ErrInvalidWeights))
+	}
+	_, err = NewDistKeyHandler(&Config{
+		Suite:     g1,
+		Longterm:  secs[0],
+		NewNodes:  pubs,
+		Threshold: total + 1,
+		Weights:   weights,
+	})
+	require.True(t, errors.Is(err, ErrInvalidThreshold))
+}
+
+func TestDKGFinish(t *testing.T) {
+	_, _, dkgs := generate(defaultN, defaultT)
+	n := len(dkgs)
+	// 1. broadcast deals
+	resps := make([]*Response, 0, n*n)
+	for _, dkg := range dkgs {
+		deals, err := dkg.Deals()
+		require.Nil(t, err)
+		for i, d := range deals {
+			resp, err := dkgs[i].ProcessDeal(d)
+			require.Nil(t, err)
+			resps = append(resps, resp)
+		}
+	}
+	for _, dkg := range dkgs {
+		require.Equal(t, dkg.ExpectedDeals(), dkg.ReceivedDeals())
+	}
+
+	// 2. broadcast responses, but the first node misses one response about
+	// the deal of the last node
+	late := dkgs[0]
+	var straggler *Response
+	for _, resp := range resps {
+		for _, dkg := range dkgs {
+			if resp.Response.Index == uint32(dkg.nidx) {
+				continue
+			}
+			if dkg == late && straggler == nil && resp.Index == uint32(n-1) {
+				straggler = resp
+				continue
+			}
+			_, err := dkg.ProcessResponse(resp)
+			require.Nil(t, err)
+		}
+	}
+	require.NotNil(t, straggler)
+
+	// the straggling node does not have a fully certified set so it can not
+	// compute its share before finishing the protocol
+	require.True(t, late.ThresholdCertified())
+	require.False(t, late.Certified())
+	require.Equal(t, n-1, len(late.QUAL()))
+	_, err := late.DistKeyShare()
+	require.Error(t, err)
+
+	late.Finish()
+	require.True(t, late.Finished())
+	require.Equal(t, n, len(late.QUAL()))
+	dks, err := late.DistKeyShare()
+	require.Nil(t, err)
+
+	// the straggler response is rejected and the key does not change
+	_, err = late.ProcessResponse(straggler)
+	require.Equal(t, ErrFinished, err)
+	require.Equal(t, ErrFinished, late.ProcessJustification(&Justification{}))
+	dks2, err := late.DistKeyShare()
+	require.Nil(t, err)
+	require.True(t, checkDks(dks, dks2))
+	require.True(t, dks.Share.V.Equal(dks2.Share.V))
+
+	// the key is the same as the one of the nodes that got all responses
+	for _, dkg := range dkgs[1:] {
+		require.True(t, dkg.Certified())
+		other, err := dkg.DistKeyShare()
+		require.Nil(t, err)
+		require.True(t, checkDks(dks, other))
+	}
+}
+
+func TestDKGHashedIndices(t *testing.T) {
+	n := 7
+	thr := vss.MinimumT(n)
+	publics := make([]kyber.Point, n)
+	secrets := make([]kyber.Scalar, n)
+	for i := range publics {
+		secrets[i], publics[i] = genPair()
+	}
+	dkgs := make([]*DistKeyGenerator, n)
+	for i := range dkgs {
+		dkg, err := NewDistKeyHandler(&Config{
+			Suite:            suite,
+			Longterm:         secrets[i],
+			NewNodes:         publics,
+			Threshold:        thr,
+			UseHashedIndices: true,
+		})
+		require.NoError(t, err)
+		dkgs[i] = dkg
+	}
+	fullExchange(t, dkgs, true)
+
+	xs, err := share.HashIndices(suite, suite, publics)
+	require.NoError(t, err)
+	dkss := make([]*DistKeyShare, n)
+	xshares := make([]*share.XShare, n)
+	for i, dkg := range dkgs {
+		dks, err := dkg.DistKeyShare()
+		require.NoError(t, err)
+		require.True(t, dks.X.Equal(xs[i]))
+		pubPoly := share.NewPubPoly(suite, nil, dks.Commits)
+		require.True(t, pubPoly.CheckX(dks.XShare()))
+		dkss[i] = dks
+		xshares[i] = dks.XShare()
+	}
+
+	secret, err := share.RecoverSecretFromXShares(suite, xshares[n-thr:], thr)
+	require.NoError(t, err)
+	require.True(t, suite.Point().Mul(secret, nil).Equal(dkss[0].Public()))
+
+	// default DKG shares do not carry an evaluation point
+	_, _, defaults := generate(defaultN, defaultT)
+	fullExchange(t, defaults, true)
+	dks, err := defaults[0].DistKeyShare()
+	require.NoError(t, err)
+	require.Nil(t, dks.X)
+	require.Nil(t, dks.XShare())
+
+	// duplicate keys, whose hashed indices collide, are detected when
+	// creating the generator
+	_, err = NewDistKeyHandler(&Config{
+		Suite:            suite,
+		Longterm:         secrets[0],
+		NewNodes:         append(publics, publics[1]),
+		UseHashedIndices: true,
+	})
+	require.True(t, errors.Is(err, ErrDuplicateKey))
+
+	// hashed indices are not supported for resharing
+	_, err = NewDistKeyHandler(&Config{
+		Suite:            suite,
+		Longterm:         secrets[0],
+		OldNodes:         publics,
+		NewNodes:         publics,
+		Share:            dkss[0],
+		OldThreshold:     thr,
+		UseHashedIndices: true,
+	})
+	require.Error(t, err)
 }
 
 func genPair() (kyber.Scalar, kyber.Point) {
@@ -670,6 +1735,105 @@ func TestDKGResharing(t *testing.T) {
 }
 
 // Test resharing functionality with one node less
+// TestDKGResharingPublicKey checks that a corrupted old share is rejected
+// before the resharing starts, and that a dealer dealing a fresh secret
+// instead of its share cannot change the distributed public key.
+func TestDKGResharingPublicKey(t *testing.T) {
+	n, oldT := 5, 3
+	publics, secrets, dkgs := generate(n, oldT)
+	fullExchange(t, dkgs, true)
+	shares := make([]*DistKeyShare, n)
+	for i, d := range dkgs {
+		dks, err := d.DistKeyShare()
+		require.NoError(t, err)
+		shares[i] = dks
+	}
+	config := func(i int) *Config {
+		return &Config{
+			Suite:        suite,
+			Longterm:     secrets[i],
+			OldNodes:     publics,
+			NewNodes:     publics,
+			Share:        shares[i],
+			OldThreshold: oldT,
+		}
+	}
+
+	// a corrupted share is detected right away
+	c := config(0)
+	c.Share = &DistKeyShare{
+		Commits: shares[0].Commits,
+		Share:   &share.PriShare{I: 0, V: suite.Scalar().Pick(random.New())},
+	}
+	_, err := NewDistKeyHandler(c)
+	require.True(t, errors.Is(err, ErrInconsistentShare))
+	c = config(0)
+	c.PublicCoeffs = shares[1].Commits[1:]
+	_, err = NewDistKeyHandler(c)
+	require.True(t, errors.Is(err, ErrInconsistentShare))
+
+	// the first dealer leaves the group, deals a fresh secret instead of its
+	// share and justifies the complaints about its deal
+	nodes := make([]*DistKeyGenerator, n)
+	for i := range nodes {
+		c := config(i)
+		c.NewNodes = publics[1:]
+		nodes[i], err = NewDistKeyHandler(c)
+		require.NoError(t, err)
+		require.True(t, shares[0].Public().Equal(nodes[i].PublicKey()))
+	}
+	require.True(t, nodes[0].DealerOnly())
+	nodes[0].dealer, err = vss.NewDealer(suite, secrets[0], suite.Scalar().Pick(random.New()), publics[1:], nodes[0].newT)
+	require.NoError(t, err)
+
+	var resps []*Response
+	for _, d := range nodes {
+		deals, err := d.Deals()
+		require.NoError(t, err)
+		for i, deal := range deals {
+			resp, err := nodes[i+1].ProcessDeal(deal)
+			require.NoError(t, err)
+			require.Equal(t, deal.Index != 0, resp.Response.Status == vss.StatusApproval)
+			resps = append(resps, resp)
+		}
+	}
+	var justifs []*Justification
+	for _, resp := range resps {
+		for _, d := range nodes {
+			if d.newPresent && resp.Response.Index == uint32(d.nidx) {
+				continue
+			}
+			j, err := d.ProcessResponse(transmitResponse(t, resp))
+			require.NoError(t, err)
+			if j != nil {
+				justifs = append(justifs, j)
+			}
+		}
+	}
+	require.Len(t, justifs, n-1)
+	for _, j := range justifs {
+		for _, d := range nodes[1:] {
+			require.NoError(t, d.ProcessJustification(transmitJustification(t, j)))
+		}
+	}
+
+	// the dealer only counts the complaints it justified
+	require.True(t, nodes[0].Certified())
+	c0 := nodes[0].justified[0]
+	delete(nodes[0].justified, 0)
+	require.False(t, nodes[0].Certified())
+	nodes[0].justified[0] = c0
+
+	// the deal is valid as far as the VSS goes, but the new shares are not
+	// shares of the old distributed key anymore
+	for _, d := range nodes[1:] {
+		require.True(t, d.isInQUAL(0))
+		require.True(t, d.Certified())
+		_, err := d.DistKeyShare()
+		require.True(t, errors.Is(err, ErrPublicKeyChanged))
+	}
+}
+
 func TestDKGResharingRemoveNode(t *testing.T) {
 	oldT := vss.MinimumT(defaultN)
 	publics, secrets, dkgs := generate(defaultN, oldT)
@@ -883,6 +2047,7 @@ func TestDKGResharingNewNodesThreshold(t *testing.T) {
 	newShares := make([]*DistKeyShare, newN)
 	newSShares := make([]*share.PriShare, newN)
 	for i := range newDkgs {
+		newDkgs[i].Finish()
 		dks, err := newDkgs[i].DistKeyShare()
 		require.NoError(t, err)
 		newShares[i] = dks
@@ -1108,6 +2273,158 @@ func TestDKGResharingNewNodes(t *testing.T) {
 	require.Equal(t, oldSecret.String(), newSecret.String())
 }
 
+// TestDKGResharingDisjoint replaces the whole group: the old nodes only deal
+// and the new nodes only receive.
+func TestDKGResharingDisjoint(t *testing.T) {
+	oldN, oldT := 7, vss.MinimumT(7)
+	newN, newT := 9, 5
+	oldPubs, oldPrivs, dkgs := generate(oldN, oldT)
+	fullExchange(t, dkgs, true)
+	shares := make([]*DistKeyShare, oldN)
+	sshares := make([]*share.PriShare, oldN)
+	for i, dkg := range dkgs {
+		dks, err := dkg.DistKeyShare()
+		require.NoError(t, err)
+		shares[i] = dks
+		sshares[i] = dks.Share
+	}
+
+	newPrivs := make([]kyber.Scalar, newN)
+	newPubs := make([]kyber.Point, newN)
+	for i := range newPubs {
+		newPrivs[i], newPubs[i] = genPair()
+	}
+
+	oldDkgs := make([]*DistKeyGenerator, oldN)
+	for i := range oldDkgs {
+		var err error
+		oldDkgs[i], err = NewDistKeyHandler(&Config{
+			Suite:        suite,
+			Longterm:     oldPrivs[i],
+			OldNodes:     oldPubs,
+			NewNodes:     newPubs,
+			Share:        shares[i],
+			Threshold:    newT,
+			OldThreshold: oldT,
+		})
+		require.NoError(t, err)
+		require.True(t, oldDkgs[i].DealerOnly())
+		require.False(t, oldDkgs[i].Certified())
+		require.Equal(t, 0, oldDkgs[i].ExpectedDeals())
+	}
+	newDkgs := make([]*DistKeyGenerator, newN)
+	for i := range newDkgs {
+		var err error
+		newDkgs[i], err = NewDistKeyHandler(&Config{
+			Suite:        suite,
+			Longterm:     newPrivs[i],
+			OldNodes:     oldPubs,
+			NewNodes:     newPubs,
+			PublicCoeffs: shares[0].Commits,
+			Threshold:    newT,
+			OldThreshold: oldT,
+		})
+		require.NoError(t, err)
+		require.False(t, newDkgs[i].DealerOnly())
+		require.Equal(t, oldN, newDkgs[i].ExpectedDeals())
+		// new nodes never deal
+		deals, err := newDkgs[i].Deals()
+		require.NoError(t, err)
+		require.Nil(t, deals)
+	}
+
+	// 1. the old nodes deal to the new nodes
+	var resps []*Response
+	for _, dkg := range oldDkgs {
+		deals, err := dkg.Deals()
+		require.NoError(t, err)
+		require.Len(t, deals, newN)
+		for dest, d := range deals {
+			resp, err := newDkgs[dest].ProcessDeal(d)
+			require.NoError(t, err)
+			require.Equal(t, vss.StatusApproval, resp.Response.Status)
+			// the response is routed by the index in the new group
+			require.Equal(t, uint32(dest), resp.Response.Index)
+			resps = append(resps, resp)
+		}
+	}
+	for _, dkg := range newDkgs {
+		require.Equal(t, oldN, dkg.ReceivedDeals())
+	}
+
+	// a response from another session is not counted for the dealer
+	stale := *resps[0]
+	staleResp := *stale.Response
+	staleResp.SessionID = []byte("another session")
+	var err error
+	staleResp.Signature, err = schnorr.Sign(suite, newPrivs[staleResp.Index], staleResp.Hash(suite))
+	require.NoError(t, err)
+	stale.Response = &staleResp
+	_, err = oldDkgs[stale.Index].ProcessResponse(&stale)
+	require.True(t, errors.Is(err, vss.ErrInvalidSessionID))
+
+	// 2. broadcast the responses, the ones of the last new node at the end
+	broadcast := func(resp *Response) {
+		for _, dkg := range append(append([]*DistKeyGenerator{}, oldDkgs...), newDkgs...) {
+			j, err := dkg.ProcessResponse(resp)
+			require.NoError(t, err)
+			require.Nil(t, j)
+		}
+	}
+	var last []*Response
+	for _, resp := range resps {
+		if resp.Response.Index == uint32(newN-1) {
+			last = append(last, resp)
+			continue
+		}
+		broadcast(resp)
+	}
+	for _, dkg := range oldDkgs {
+		require.False(t, dkg.Certified())
+	}
+	// after the timeout, the responses of the threshold of new nodes are
+	// enough for a dealer
+	oldDkgs[0].SetTimeout()
+	require.True(t, oldDkgs[0].Certified())
+	for _, resp := range last {
+		broadcast(resp)
+	}
+	for _, dkg := range oldDkgs {
+		require.True(t, dkg.Certified(), "old dkg %d can't certify", dkg.oidx)
+		require.Len(t, dkg.QUAL(), oldN)
+		_, err := dkg.DistKeyShare()
+		require.Equal(t, ErrDealerOnly, err)
+	}
+
+	newShares := make([]*share.PriShare, newN)
+	var newCommits []kyber.Point
+	for i, dkg := range newDkgs {
+		require.True(t, dkg.Certified(), "new dkg %d can't certify", i)
+		dks, err := dkg.DistKeyShare()
+		require.NoError(t, err)
+		require.True(t, shares[0].Public().Equal(dks.Public()))
+		require.Len(t, dks.Commits, newT)
+		newShares[i] = dks.Share
+		newCommits = dks.Commits
+	}
+	oldSecret, err := share.RecoverSecret(suite, sshares, oldT, oldN)
+	require.NoError(t, err)
+	newSecret, err := share.RecoverSecret(suite, newShares[:newT], newT, newN)
+	require.NoError(t, err)
+	require.True(t, oldSecret.Equal(newSecret))
+
+	// the old shares are not shares of the new polynomial, so that they are
+	// useless together with the new ones
+	newPoly := share.NewPubPoly(suite, nil, newCommits)
+	for _, s := range sshares {
+		require.False(t, newPoly.Check(s))
+	}
+	mixed := append(append([]*share.PriShare{}, newShares[:newT-1]...), sshares[newT-1])
+	mixedSecret, err := share.RecoverSecret(suite, mixed, newT, newN)
+	require.NoError(t, err)
+	require.False(t, oldSecret.Equal(mixedSecret))
+}
+
 func TestDKGResharingPartialNewNodes(t *testing.T) {
 	oldPubs, oldPrivs, dkgs := generate(defaultN, vss.MinimumT(defaultN))
 	fullExchange(t, dkgs, true)
@@ -1369,3 +2686,178 @@ func TestUserOnlyFlagFalseBehavior(t *testing.T) {
 
 	require.False(t, dkg1.dealer.PrivatePoly().Secret().Equal(dkg2.dealer.PrivatePoly().Secret()))
 }
+
+func BenchmarkDKGProcessResponses(b *testing.B) {
+	for _, n := range []int{64, 128} {
+		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+			benchProcessResponses(b, n)
+		})
+	}
+}
+
+func benchProcessResponses(b *testing.B, n int) {
+	pubs, secs, dkgs := generate(n, vss.MinimumT(n))
+	// the deals and the responses about the deals of the other dealers
+	// received by the first node
+	var deals []*Deal
+	for _, d := range dkgs[1:] {
+		dd, err := d.Deals()
+		require.NoError(b, err)
+		deals = append(deals, dd[0])
+	}
+	var resps []*Response
+	for dealer, v := range receiveDeals(b, secs[0], pubs, deals).verifiers {
+		if dealer == 0 {
+			continue
+		}
+		for i := 1; i < n; i++ {
+			r := &vss.Response{SessionID: v.SessionID(), Index: uint32(i), Status: vss.StatusApproval}
+			sig, err := schnorr.Sign(suite, secs[i], r.Hash(suite))
+			require.NoError(b, err)
+			r.Signature = sig
+			resps = append(resps, &Response{Index: dealer, Response: r})
+		}
+	}
+
+	b.Run("sequential", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			d := receiveDeals(b, secs[0], pubs, deals)
+			b.StartTimer()
+			for _, resp := range resps {
+				_, _ = d.ProcessResponse(resp)
+			}
+		}
+	})
+	b.Run("batch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			d := receiveDeals(b, secs[0], pubs, deals)
+			b.StartTimer()
+			_, errs := d.ProcessResponses(resps)
+			require.Empty(b, errs)
+		}
+	})
+}
+
+func receiveDeals(b *testing.B, long kyber.Scalar, pubs []kyber.Point, deals []*Deal) *DistKeyGenerator {
+	d, err := NewDistKeyGenerator(suite, long, pubs, vss.MinimumT(len(pubs)))
+	require.NoError(b, err)
+	for _, deal := range deals {
+		_, err := d.ProcessDeal(deal)
+		require.NoError(b, err)
+	}
+	return d
+}
+
+func TestDKGProcessResponseDuplicateComplaint(t *testing.T) {
+	_, _, dkgs := generate(defaultN, defaultT)
+	_, err := dkgs[0].Deals()
+	require.NoError(t, err)
+	bad := suite.Scalar().Pick(suite.RandomStream())
+	resp, err := dkgs[1].ProcessDeal(malformedDeal(t, dkgs, func(d *vss.Deal) { d.SecShare.V = bad }))
+	require.NoError(t, err)
+	require.Equal(t, vss.StatusComplaint, resp.Response.Status)
+
+	j, err := dkgs[0].ProcessResponse(transmitResponse(t, resp))
+	require.NoError(t, err)
+	require.NotNil(t, j)
+	again, err := dkgs[0].ProcessResponse(transmitResponse(t, resp))
+	require.NoError(t, err)
+	require.Equal(t, j, again)
+}
+
+// TestDKGConcurrentDelivery delivers the deals and the responses of a DKG,
+// shuffled and duplicated, from several goroutines as a transport with one
+// goroutine per peer does, and checks that all the nodes end up with the same
+// distributed key. It is meant to be run with -race as well.
+func TestDKGConcurrentDelivery(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	const n, goroutines = 16, 8
+	_, _, dkgs := generate(n, vss.MinimumT(n))
+	rnd := mathRand.New(mathRand.NewSource(1))
+
+	// deliver calls fn on the indices of m messages from the goroutines
+	deliver := func(m int, fn func(i int) error) []error {
+		errs := make([]error, m)
+		indices := make(chan int)
+		var wg sync.WaitGroup
+		for g := 0; g < goroutines; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range indices {
+					errs[i] = fn(i)
+				}
+			}()
+		}
+		for _, i := range rnd.Perm(m) {
+			indices <- i
+		}
+		close(indices)
+		wg.Wait()
+		return errs
+	}
+
+	type dealDelivery struct {
+		to   int
+		deal *Deal
+	}
+	var deals []dealDelivery
+	for _, d := range dkgs {
+		ds, err := d.Deals()
+		require.NoError(t, err)
+		for to, deal := range ds {
+			deals = append(deals, dealDelivery{to, deal}, dealDelivery{to, deal})
+		}
+	}
+	resps := make([]*Response, len(deals))
+	for _, err := range deliver(len(deals), func(i int) error {
+		var err error
+		resps[i], err = dkgs[deals[i].to].ProcessDeal(deals[i].deal)
+		return err
+	}) {
+		require.NoError(t, err)
+	}
+
+	type respDelivery struct {
+		to   int
+		resp *Response
+	}
+	var responses []respDelivery
+	for i := 0; i < len(deals); i += 2 {
+		// both copies of a deal give the same response
+		require.Equal(t, resps[i], resps[i+1])
+		require.Equal(t, vss.StatusApproval, resps[i].Response.Status)
+		for to := range dkgs {
+			if to == deals[i].to {
+				continue
+			}
+			responses = append(responses, respDelivery{to, resps[i]}, respDelivery{to, resps[i]})
+		}
+	}
+	for _, err := range deliver(len(responses), func(i int) error {
+		j, err := dkgs[responses[i].to].ProcessResponse(responses[i].resp)
+		if err == nil && j != nil {
+			err = errors.New("unexpected justification")
+		}
+		return err
+	}) {
+		require.NoError(t, err)
+	}
+
+	var first *DistKeyShare
+	for _, d := range dkgs {
+		require.True(t, d.Certified())
+		require.Len(t, d.QUAL(), n)
+		dks, err := d.DistKeyShare()
+		require.NoError(t, err)
+		if first == nil {
+			first = dks
+		}
+		require.Len(t, dks.Commits, len(first.Commits))
+		for i := range first.Commits {
+			require.True(t, first.Commits[i].Equal(dks.Commits[i]))
+		}
+	}
+}
@@ -0,0 +1,385 @@
+package dkg
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/share"
+	vss "go.dedis.ch/kyber/v3/share/vss/pedersen"
+)
+
+// MessageVersion1 is the version byte of the current encoding of the deals,
+// responses and justifications.
+//
+// An encoded message consists of the version byte, one byte giving the kind of
+// the message, and the fields of the message in the order of their
+// declaration, nested structures included. Integers are encoded as 4 bytes in
+// little endian and booleans as one byte 0 or 1. Byte slices and lists of
+// points are prefixed by their length as an integer. Points and scalars are
+// encoded with MarshalBinary, and their length is given by the suite. An
+// optional field is prefixed by one boolean telling whether it is present.
+// The encoding of a message is therefore unique, and decoding rejects any
+// other one.
+const MessageVersion1 byte = 1
+
+// MessageVersion2 is the version byte of the encoding of the deals whose
+// encrypted deal has a version (see vss.EncryptedDealVersion). The version,
+// the session ID and the index of the encrypted deal are encoded after the
+// index of the dealer, and the rest follows the format of MessageVersion1.
+// The deals of version 0, the responses and the justifications are still
+// encoded with MessageVersion1.
+const MessageVersion2 byte = 2
+
+const (
+	kindDeal          byte = 1
+	kindResponse      byte = 2
+	kindJustification byte = 3
+	kindState         byte = 4
+)
+
+// ErrEncoding is returned when decoding a message which is not a valid
+// encoding.
+var ErrEncoding = errors.New("dkg: invalid message encoding")
+
+// MarshalBinary returns the canonical encoding of the deal, following the
+// format described for MessageVersion2, or for MessageVersion1 if the
+// encrypted deal is of version 0.
+func (d *Deal) MarshalBinary() ([]byte, error) {
+	if d.Deal == nil {
+		return nil, fmt.Errorf("%w: nil encrypted deal", ErrMalformed)
+	}
+	if d.Deal.Version == 0 {
+		if d.Deal.SessionID != nil || d.Deal.Index != 0 {
+			return nil, fmt.Errorf("%w: session id or index in a deal of version 0", ErrMalformed)
+		}
+		e := newEncoder(MessageVersion1, kindDeal)
+		e.uint32(d.Index)
+		return d.marshalEnd(e)
+	}
+	e := newEncoder(MessageVersion2, kindDeal)
+	e.uint32(d.Index)
+	e.uint32(d.Deal.Version)
+	e.bytes(d.Deal.SessionID)
+	e.uint32(d.Deal.Index)
+	return d.marshalEnd(e)
+}
+
+// marshalEnd encodes the fields of the deal common to all the versions.
+func (d *Deal) marshalEnd(e *encoder) ([]byte, error) {
+	e.bytes(d.Deal.DHKey)
+	e.bytes(d.Deal.Signature)
+	e.bytes(d.Deal.Nonce)
+	e.bytes(d.Deal.Cipher)
+	e.bytes(d.Signature)
+	return e.buf.Bytes(), e.err
+}
+
+// UnmarshalBinary decodes a deal encoded by MarshalBinary.
+func (d *Deal) UnmarshalBinary(suite Suite, data []byte) error {
+	r := newDecoder(suite, kindDeal, data)
+	dd := Deal{Index: r.uint32(), Deal: &vss.EncryptedDeal{}}
+	if r.version == MessageVersion2 {
+		dd.Deal.Version = r.uint32()
+		dd.Deal.SessionID = r.bytes()
+		dd.Deal.Index = r.uint32()
+		if dd.Deal.Version == 0 {
+			r.fail("deal of version 0 encoded with message version %d", MessageVersion2)
+		}
+	}
+	dd.Deal.DHKey = r.bytes()
+	dd.Deal.Signature = r.bytes()
+	dd.Deal.Nonce = r.bytes()
+	dd.Deal.Cipher = r.bytes()
+	dd.Signature = r.bytes()
+	if err := r.done(); err != nil {
+		return err
+	}
+	*d = dd
+	return nil
+}
+
+// MarshalBinary returns the canonical encoding of the response, following the
+// format described for MessageVersion1.
+func (r *Response) MarshalBinary() ([]byte, error) {
+	if r.Response == nil {
+		return nil, fmt.Errorf("%w: nil response", ErrMalformed)
+	}
+	e := newEncoder(MessageVersion1, kindResponse)
+	e.uint32(r.Index)
+	e.bytes(r.Response.SessionID)
+	e.uint32(r.Response.Index)
+	e.bool(r.Response.Status)
+	e.bytes(r.Response.Signature)
+	return e.buf.Bytes(), e.err
+}
+
+// UnmarshalBinary decodes a response encoded by MarshalBinary.
+func (r *Response) UnmarshalBinary(suite Suite, data []byte) error {
+	d := newDecoder(suite, kindResponse, data)
+	resp := Response{Index: d.uint32(), Response: &vss.Response{}}
+	resp.Response.SessionID = d.bytes()
+	resp.Response.Index = d.uint32()
+	resp.Response.Status = d.bool()
+	resp.Response.Signature = d.bytes()
+	if err := d.done(); err != nil {
+		return err
+	}
+	*r = resp
+	return nil
+}
+
+// MarshalBinary returns the canonical encoding of the justification, following
+// the format described for MessageVersion1.
+func (j *Justification) MarshalBinary() ([]byte, error) {
+	vj := j.Justification
+	if vj == nil || vj.Deal == nil || vj.Deal.SecShare == nil {
+		return nil, fmt.Errorf("%w: incomplete justification", ErrMalformed)
+	}
+	deal := vj.Deal
+	if deal.SecShare.I < 0 || uint64(deal.SecShare.I) > uint64(^uint32(0)) {
+		return nil, fmt.Errorf("%w: share index %d", ErrMalformed, deal.SecShare.I)
+	}
+	e := newEncoder(MessageVersion1, kindJustification)
+	e.uint32(j.Index)
+	e.bytes(vj.SessionID)
+	e.uint32(vj.Index)
+	e.bytes(deal.SessionID)
+	e.uint32(uint32(deal.SecShare.I))
+	e.marshal(deal.SecShare.V)
+	e.uint32(deal.T)
+	e.uint32(uint32(len(deal.Commitments)))
+	for _, c := range deal.Commitments {
+		e.marshal(c)
+	}
+	e.bool(deal.X != nil)
+	if deal.X != nil {
+		e.marshal(deal.X)
+	}
+	e.bytes(vj.Signature)
+	return e.buf.Bytes(), e.err
+}
+
+// UnmarshalBinary decodes a justification encoded by MarshalBinary. The points
+// and scalars must be canonically encoded, the points being on the curve of
+// the suite.
+func (j *Justification) UnmarshalBinary(suite Suite, data []byte) error {
+	d := newDecoder(suite, kindJustification, data)
+	just := Justification{Index: d.uint32(), Justification: &vss.Justification{}}
+	vj := just.Justification
+	vj.SessionID = d.bytes()
+	vj.Index = d.uint32()
+	deal := &vss.Deal{SessionID: d.bytes(), SecShare: &share.PriShare{}}
+	deal.SecShare.I = int(d.uint32())
+	deal.SecShare.V = d.scalar()
+	deal.T = d.uint32()
+	deal.Commitments = d.points()
+	if d.bool() {
+		deal.X = d.scalar()
+	}
+	vj.Deal = deal
+	vj.Signature = d.bytes()
+	if err := d.done(); err != nil {
+		return err
+	}
+	*j = just
+	return nil
+}
+
+type encoder struct {
+	buf bytes.Buffer
+	err error
+}
+
+func newEncoder(version, kind byte) *encoder {
+	e := &encoder{}
+	e.buf.WriteByte(version)
+	e.buf.WriteByte(kind)
+	return e
+}
+
+func (e *encoder) uint32(v uint32) {
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], v)
+	e.buf.Write(b[:])
+}
+
+func (e *encoder) bool(v bool) {
+	if v {
+		e.buf.WriteByte(1)
+	} else {
+		e.buf.WriteByte(0)
+	}
+}
+
+func (e *encoder) bytes(b []byte) {
+	if uint64(len(b)) > uint64(^uint32(0)) {
+		e.err = fmt.Errorf("%w: field too long", ErrMalformed)
+		return
+	}
+	e.uint32(uint32(len(b)))
+	e.buf.Write(b)
+}
+
+func (e *encoder) marshal(m encoding.BinaryMarshaler) {
+	if e.err != nil {
+		return
+	}
+	if m == nil {
+		e.err = fmt.Errorf("%w: nil point or scalar", ErrMalformed)
+		return
+	}
+	b, err := m.MarshalBinary()
+	if err != nil {
+		e.err = err
+		return
+	}
+	e.buf.Write(b)
+}
+
+// decoder reads the fields of an encoded message. The first error is kept and
+// returned by done, later reads returning zero values.
+type decoder struct {
+	suite   Suite
+	version byte
+	buf     []byte
+	err     error
+}
+
+// newDecoder checks the version and the kind of the message. Only the deals
+// may be of MessageVersion2.
+func newDecoder(suite Suite, kind byte, data []byte) *decoder {
+	d := &decoder{suite: suite, buf: data}
+	switch {
+	case len(data) < 2:
+		d.fail("message too short")
+	case data[0] != MessageVersion1 && !(data[0] == MessageVersion2 && kind == kindDeal):
+		d.fail("unknown version %d", data[0])
+	case data[1] != kind:
+		d.fail("unexpected message kind %d", data[1])
+	default:
+		d.version = data[0]
+		d.buf = data[2:]
+	}
+	return d
+}
+
+func (d *decoder) fail(format string, args ...interface{}) {
+	if d.err == nil {
+		d.err = fmt.Errorf("%w: %s", ErrEncoding, fmt.Sprintf(format, args...))
+		d.buf = nil
+	}
+}
+
+func (d *decoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n > len(d.buf) {
+		d.fail("truncated message")
+		return nil
+	}
+	b := d.buf[:n]
+	d.buf = d.buf[n:]
+	return b
+}
+
+func (d *decoder) uint32() uint32 {
+	b := d.next(4)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(b)
+}
+
+func (d *decoder) bool() bool {
+	b := d.next(1)
+	if b == nil {
+		return false
+	}
+	if b[0] > 1 {
+		d.fail("invalid boolean %d", b[0])
+	}
+	return b[0] == 1
+}
+
+func (d *decoder) bytes() []byte {
+	l := d.uint32()
+	if uint64(l) > uint64(len(d.buf)) {
+		d.fail("truncated message")
+		return nil
+	}
+	b := d.next(int(l))
+	if len(b) == 0 {
+		return nil
+	}
+	return append([]byte{}, b...)
+}
+
+// count reads the number of elements of a list, each of at least size bytes.
+// It checks the count against the remaining bytes first, so that a bogus
+// count does not allocate.
+func (d *decoder) count(size int) int {
+	n := d.uint32()
+	if uint64(n)*uint64(size) > uint64(len(d.buf)) {
+		d.fail("truncated message")
+		return 0
+	}
+	return int(n)
+}
+
+func (d *decoder) point() kyber.Point {
+	p := d.suite.Point()
+	if !d.unmarshal(p, d.next(p.MarshalSize())) {
+		return nil
+	}
+	return p
+}
+
+func (d *decoder) points() []kyber.Point {
+	n := d.uint32()
+	// check the length first, so that a bogus count does not allocate
+	if uint64(n)*uint64(d.suite.PointLen()) > uint64(len(d.buf)) {
+		d.fail("truncated message")
+		return nil
+	}
+	ps := make([]kyber.Point, n)
+	for i := range ps {
+		ps[i] = d.point()
+	}
+	return ps
+}
+
+func (d *decoder) scalar() kyber.Scalar {
+	s := d.suite.Scalar()
+	if !d.unmarshal(s, d.next(s.MarshalSize())) {
+		return nil
+	}
+	return s
+}
+
+// unmarshal decodes b into m and checks that b is the canonical encoding of m.
+func (d *decoder) unmarshal(m kyber.Marshaling, b []byte) bool {
+	if d.err != nil {
+		return false
+	}
+	if err := m.UnmarshalBinary(b); err != nil {
+		d.fail("%v", err)
+		return false
+	}
+	if c, err := m.MarshalBinary(); err != nil || !bytes.Equal(b, c) {
+		d.fail("non canonical point or scalar")
+		return false
+	}
+	return true
+}
+
+func (d *decoder) done() error {
+	if d.err == nil && len(d.buf) != 0 {
+		d.fail("%d trailing bytes", len(d.buf))
+	}
+	return d.err
+}
@@ -0,0 +1,242 @@
+package dkg
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/share"
+	vss "go.dedis.ch/kyber/v3/share/vss/pedersen"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files of the message encodings")
+
+type message interface {
+	MarshalBinary() ([]byte, error)
+	UnmarshalBinary(Suite, []byte) error
+}
+
+// goldenMessages returns messages whose fields are derived from a fixed seed.
+func goldenMessages() map[string]message {
+	xof := suite.XOF([]byte("dkg message encoding"))
+	rnd := func(n int) []byte {
+		b := make([]byte, n)
+		xof.XORKeyStream(b, b)
+		return b
+	}
+	commits := make([]kyber.Point, 3)
+	for i := range commits {
+		commits[i] = suite.Point().Pick(xof)
+	}
+	justification := func(x kyber.Scalar) *Justification {
+		return &Justification{
+			Index: 2,
+			Justification: &vss.Justification{
+				SessionID: rnd(32),
+				Index:     4,
+				Deal: &vss.Deal{
+					SessionID:   rnd(32),
+					SecShare:    &share.PriShare{I: 4, V: suite.Scalar().Pick(xof)},
+					T:           3,
+					Commitments: commits,
+					X:           x,
+				},
+				Signature: rnd(64),
+			},
+		}
+	}
+	return map[string]message{
+		"deal": &Deal{
+			Index: 1,
+			Deal: &vss.EncryptedDeal{
+				DHKey:     rnd(32),
+				Signature: rnd(64),
+				Nonce:     rnd(12),
+				Cipher:    rnd(80),
+			},
+			Signature: rnd(64),
+		},
+		"response": &Response{
+			Index: 3,
+			Response: &vss.Response{
+				SessionID: rnd(32),
+				Index:     2,
+				Status:    vss.StatusComplaint,
+				Signature: rnd(64),
+			},
+		},
+		"justification":   justification(nil),
+		"justification_x": justification(suite.Scalar().Pick(xof)),
+		// last, so that the fields of the other messages do not change
+		"deal_v2": &Deal{
+			Index: 1,
+			Deal: &vss.EncryptedDeal{
+				Version:   vss.EncryptedDealVersion,
+				SessionID: rnd(32),
+				Index:     3,
+				DHKey:     rnd(32),
+				Signature: rnd(64),
+				Nonce:     rnd(12),
+				Cipher:    rnd(80),
+			},
+			Signature: rnd(64),
+		},
+	}
+}
+
+func newMessage(name string) message {
+	switch {
+	case strings.HasPrefix(name, "deal"):
+		return &Deal{}
+	case name == "response":
+		return &Response{}
+	case strings.HasPrefix(name, "justification"):
+		return &Justification{}
+	}
+	panic("unknown message " + name)
+}
+
+func TestMessageEncodingGolden(t *testing.T) {
+	for name, msg := range goldenMessages() {
+		t.Run(name, func(t *testing.T) {
+			buff, err := msg.MarshalBinary()
+			require.NoError(t, err)
+			path := filepath.Join("testdata", name+".golden")
+			if *updateGolden {
+				require.NoError(t, ioutil.WriteFile(path, []byte(hex.EncodeToString(buff)+"\n"), 0644))
+			}
+			golden, err := ioutil.ReadFile(path)
+			require.NoError(t, err)
+			require.Equal(t, strings.TrimSpace(string(golden)), hex.EncodeToString(buff))
+
+			// points are compared through their encoding, as their internal
+			// representations may differ
+			decoded := newMessage(name)
+			require.NoError(t, decoded.UnmarshalBinary(suite, buff))
+			again, err := decoded.MarshalBinary()
+			require.NoError(t, err)
+			require.Equal(t, buff, again)
+		})
+	}
+}
+
+func TestMessageDecodingErrors(t *testing.T) {
+	for name, msg := range goldenMessages() {
+		t.Run(name, func(t *testing.T) {
+			buff, err := msg.MarshalBinary()
+			require.NoError(t, err)
+
+			for i := 0; i < len(buff); i++ {
+				err := newMessage(name).UnmarshalBinary(suite, buff[:i])
+				require.True(t, errors.Is(err, ErrEncoding), "truncated at %d: %v", i, err)
+			}
+			err = newMessage(name).UnmarshalBinary(suite, append(buff, 0))
+			require.True(t, errors.Is(err, ErrEncoding))
+
+			wrong := append([]byte{}, buff...)
+			wrong[0] = MessageVersion2 + 1
+			require.True(t, errors.Is(newMessage(name).UnmarshalBinary(suite, wrong), ErrEncoding))
+			if name != "deal_v2" {
+				// only the deals of version 1 are encoded with MessageVersion2
+				wrong[0] = MessageVersion2
+				require.True(t, errors.Is(newMessage(name).UnmarshalBinary(suite, wrong), ErrEncoding))
+			}
+			wrong[0] = buff[0]
+			wrong[1] ^= 0xff
+			require.True(t, errors.Is(newMessage(name).UnmarshalBinary(suite, wrong), ErrEncoding))
+		})
+	}
+
+	// a deal of version 0 has a unique encoding
+	msgs := goldenMessages()
+	buff, err := msgs["deal_v2"].MarshalBinary()
+	require.NoError(t, err)
+	wrong := append([]byte{}, buff...)
+	// version, kind, index of the dealer
+	binary.LittleEndian.PutUint32(wrong[2+4:], 0)
+	require.True(t, errors.Is((&Deal{}).UnmarshalBinary(suite, wrong), ErrEncoding))
+	legacy := *msgs["deal"].(*Deal)
+	legacyDeal := *legacy.Deal
+	legacyDeal.Index = 1
+	legacy.Deal = &legacyDeal
+	_, err = legacy.MarshalBinary()
+	require.True(t, errors.Is(err, ErrMalformed))
+
+	// points off the curve, non canonical scalars and booleans
+	buff, err = msgs["justification"].MarshalBinary()
+	require.NoError(t, err)
+	sl, pl := suite.ScalarLen(), suite.PointLen()
+	// version, kind, index, session id, index, session id, share index
+	shareOff := 2 + 4 + 4 + 32 + 4 + 4 + 32 + 4
+	commitOff := shareOff + sl + 4 + 4
+	xOff := commitOff + 3*pl
+
+	wrong = append([]byte{}, buff...)
+	for i := 0; i < sl; i++ {
+		wrong[shareOff+i] = 0xff
+	}
+	require.True(t, errors.Is((&Justification{}).UnmarshalBinary(suite, wrong), ErrEncoding))
+
+	wrong = append([]byte{}, buff...)
+	for i := 0; i < pl; i++ {
+		wrong[commitOff+i] = 0xff
+	}
+	require.True(t, errors.Is((&Justification{}).UnmarshalBinary(suite, wrong), ErrEncoding))
+
+	wrong = append([]byte{}, buff...)
+	wrong[xOff] = 2
+	require.True(t, errors.Is((&Justification{}).UnmarshalBinary(suite, wrong), ErrEncoding))
+
+	// a count of commitments larger than the message
+	wrong = append([]byte{}, buff...)
+	wrong[commitOff-1] = 0xff
+	require.True(t, errors.Is((&Justification{}).UnmarshalBinary(suite, wrong), ErrEncoding))
+}
+
+func TestMessageEncodingProcess(t *testing.T) {
+	pubs, secs, dkgs := generate(defaultN, defaultT)
+	deals, err := dkgs[0].Deals()
+	require.NoError(t, err)
+	deal := deals[1]
+
+	buff, err := deal.MarshalBinary()
+	require.NoError(t, err)
+	decoded := &Deal{}
+	require.NoError(t, decoded.UnmarshalBinary(suite, buff))
+	require.Equal(t, deal, decoded)
+
+	// the same receiver processes the original and the decoded deal
+	twin, err := NewDistKeyGenerator(suite, secs[1], pubs, defaultT)
+	require.NoError(t, err)
+	resp, err := dkgs[1].ProcessDeal(deal)
+	require.NoError(t, err)
+	twinResp, err := twin.ProcessDeal(decoded)
+	require.NoError(t, err)
+	require.Equal(t, resp.Index, twinResp.Index)
+	require.Equal(t, resp.Response.SessionID, twinResp.Response.SessionID)
+	require.Equal(t, resp.Response.Index, twinResp.Response.Index)
+	require.Equal(t, resp.Response.Status, twinResp.Response.Status)
+	require.Equal(t, dkgs[1].verifiers[0].Deal(), twin.verifiers[0].Deal())
+
+	// the dealer processes the decoded response
+	buff, err = resp.MarshalBinary()
+	require.NoError(t, err)
+	decodedResp := &Response{}
+	require.NoError(t, decodedResp.UnmarshalBinary(suite, buff))
+	require.Equal(t, resp, decodedResp)
+	j, err := dkgs[0].ProcessResponse(decodedResp)
+	require.NoError(t, err)
+	require.Nil(t, j)
+
+	// a tampered deal is rejected like the original one would be
+	decoded.Deal.Cipher[0] ^= 1
+	_, err = twin.ProcessDeal(decoded)
+	require.True(t, errors.Is(err, vss.ErrInvalidSignature))
+}
@@ -0,0 +1,41 @@
+//go:build go1.18
+// +build go1.18
+
+package dkg
+
+import (
+	"testing"
+
+	vss "go.dedis.ch/kyber/v3/share/vss/pedersen"
+)
+
+// FuzzProcessDeal checks that ProcessDeal never panics, whatever the content
+// of the deal it is given. The corpus is seeded with a valid deal.
+func FuzzProcessDeal(f *testing.F) {
+	pubs, secs, dkgs := generate(defaultN, defaultT)
+	deals, err := dkgs[0].Deals()
+	if err != nil {
+		f.Fatal(err)
+	}
+	d := deals[1]
+	f.Add(d.Index, d.Signature, d.Deal.DHKey, d.Deal.Signature, d.Deal.Nonce, d.Deal.Cipher)
+
+	f.Fuzz(func(t *testing.T, index uint32, sig, dhKey, dhSig, nonce, cipher []byte) {
+		// a fresh receiver for every input, so that a deal accepted once
+		// does not shadow the following ones
+		rec, err := NewDistKeyGenerator(suite, secs[1], pubs, defaultT)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = rec.ProcessDeal(&Deal{
+			Index: index,
+			Deal: &vss.EncryptedDeal{
+				DHKey:     dhKey,
+				Signature: dhSig,
+				Nonce:     nonce,
+				Cipher:    cipher,
+			},
+			Signature: sig,
+		})
+	})
+}
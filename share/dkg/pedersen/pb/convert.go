@@ -0,0 +1,398 @@
+package pb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/share"
+	dkg "go.dedis.ch/kyber/v3/share/dkg/pedersen"
+	vss "go.dedis.ch/kyber/v3/share/vss/pedersen"
+)
+
+// ErrVerifierIndex is returned for a message designating a verifier which is
+// not in the list of verifiers. The other errors of the conversions wrap
+// dkg.ErrMalformed or dkg.ErrDealerIndex.
+var ErrVerifierIndex = errors.New("pb: verifier index out of bounds")
+
+// Bounds are the numbers of dealers and of verifiers of a DKG, against which
+// the FromProto conversions check the indices of the messages.
+type Bounds struct {
+	// Dealers is the number of old nodes of a resharing, or the number of
+	// nodes of a fresh DKG.
+	Dealers int
+	// Verifiers is the number of shares, i.e. the number of new nodes, or
+	// the sum of their weights with Config.Weights.
+	Verifiers int
+}
+
+// BoundsOf returns the bounds of the DKG run with the configuration c.
+func BoundsOf(c *dkg.Config) Bounds {
+	b := Bounds{Dealers: len(c.OldNodes), Verifiers: len(c.NewNodes)}
+	if b.Dealers == 0 {
+		b.Dealers = len(c.NewNodes)
+	}
+	if c.Weights != nil {
+		b.Verifiers = 0
+		for _, w := range c.Weights {
+			b.Verifiers += w
+		}
+	}
+	return b
+}
+
+func (b Bounds) checkDealer(i uint32) error {
+	if uint64(i) >= uint64(b.Dealers) {
+		return fmt.Errorf("%w: %d for %d dealers", dkg.ErrDealerIndex, i, b.Dealers)
+	}
+	return nil
+}
+
+func (b Bounds) checkVerifier(i uint32) error {
+	if uint64(i) >= uint64(b.Verifiers) {
+		return fmt.Errorf("%w: %d for %d verifiers", ErrVerifierIndex, i, b.Verifiers)
+	}
+	return nil
+}
+
+// DealToProto returns the protobuf message of the deal.
+func DealToProto(d *dkg.Deal) (*Deal, error) {
+	if d == nil {
+		return nil, fmt.Errorf("%w: nil deal", dkg.ErrMalformed)
+	}
+	e, err := EncryptedDealToProto(d.Deal)
+	if err != nil {
+		return nil, err
+	}
+	return &Deal{
+		Index:     d.Index,
+		Deal:      e,
+		Signature: clone(d.Signature),
+	}, nil
+}
+
+// DealFromProto returns the deal of the protobuf message p. It checks that
+// the index of the dealer is within the bounds, and the encrypted deal as
+// EncryptedDealFromProto does.
+func DealFromProto(suite dkg.Suite, b Bounds, p *Deal) (*dkg.Deal, error) {
+	if p == nil {
+		return nil, fmt.Errorf("%w: nil deal", dkg.ErrMalformed)
+	}
+	if err := b.checkDealer(p.Index); err != nil {
+		return nil, err
+	}
+	e, err := EncryptedDealFromProto(suite, b, p.Deal)
+	if err != nil {
+		return nil, err
+	}
+	return &dkg.Deal{
+		Index:     p.Index,
+		Deal:      e,
+		Signature: clone(p.Signature),
+	}, nil
+}
+
+// EncryptedDealToProto returns the protobuf message of the encrypted deal,
+// e.g. for a transport which signs the deals itself.
+func EncryptedDealToProto(e *vss.EncryptedDeal) (*EncryptedDeal, error) {
+	if e == nil {
+		return nil, fmt.Errorf("%w: nil encrypted deal", dkg.ErrMalformed)
+	}
+	if e.Version == 0 && (e.SessionID != nil || e.Index != 0) {
+		return nil, fmt.Errorf("%w: session id or index in a deal of version 0", dkg.ErrMalformed)
+	}
+	return &EncryptedDeal{
+		Version:   e.Version,
+		SessionID: clone(e.SessionID),
+		Index:     e.Index,
+		DHKey:     clone(e.DHKey),
+		Signature: clone(e.Signature),
+		Nonce:     clone(e.Nonce),
+		Cipher:    clone(e.Cipher),
+	}, nil
+}
+
+// EncryptedDealFromProto returns the encrypted deal of the protobuf message
+// p. It checks that the index of the verifier is within the bounds, and that
+// the Diffie-Hellman key is the canonical encoding of a point of the suite.
+func EncryptedDealFromProto(suite dkg.Suite, b Bounds, p *EncryptedDeal) (*vss.EncryptedDeal, error) {
+	if p == nil {
+		return nil, fmt.Errorf("%w: nil encrypted deal", dkg.ErrMalformed)
+	}
+	if p.Version == 0 && (len(p.SessionID) != 0 || p.Index != 0) {
+		return nil, fmt.Errorf("%w: session id or index in a deal of version 0", dkg.ErrMalformed)
+	}
+	if err := b.checkVerifier(p.Index); err != nil {
+		return nil, err
+	}
+	if _, err := decodePoint(suite, p.DHKey); err != nil {
+		return nil, fmt.Errorf("%w: DH key: %v", dkg.ErrMalformed, err)
+	}
+	return &vss.EncryptedDeal{
+		Version:   p.Version,
+		SessionID: clone(p.SessionID),
+		Index:     p.Index,
+		DHKey:     clone(p.DHKey),
+		Signature: clone(p.Signature),
+		Nonce:     clone(p.Nonce),
+		Cipher:    clone(p.Cipher),
+	}, nil
+}
+
+// ResponseToProto returns the protobuf message of the response.
+func ResponseToProto(r *dkg.Response) (*Response, error) {
+	if r == nil || r.Response == nil {
+		return nil, fmt.Errorf("%w: nil response", dkg.ErrMalformed)
+	}
+	return &Response{
+		Index:         r.Index,
+		SessionID:     clone(r.Response.SessionID),
+		VerifierIndex: r.Response.Index,
+		Status:        r.Response.Status,
+		Signature:     clone(r.Response.Signature),
+	}, nil
+}
+
+// ResponseFromProto returns the response of the protobuf message p. It
+// checks that the indices of the dealer and of the verifier are within the
+// bounds.
+func ResponseFromProto(b Bounds, p *Response) (*dkg.Response, error) {
+	if p == nil {
+		return nil, fmt.Errorf("%w: nil response", dkg.ErrMalformed)
+	}
+	if err := b.checkDealer(p.Index); err != nil {
+		return nil, err
+	}
+	if err := b.checkVerifier(p.VerifierIndex); err != nil {
+		return nil, err
+	}
+	return &dkg.Response{
+		Index: p.Index,
+		Response: &vss.Response{
+			SessionID: clone(p.SessionID),
+			Index:     p.VerifierIndex,
+			Status:    p.Status,
+			Signature: clone(p.Signature),
+		},
+	}, nil
+}
+
+// JustificationToProto returns the protobuf message of the justification.
+func JustificationToProto(j *dkg.Justification) (*Justification, error) {
+	if j == nil || j.Justification == nil || j.Justification.Deal == nil || j.Justification.Deal.SecShare == nil {
+		return nil, fmt.Errorf("%w: incomplete justification", dkg.ErrMalformed)
+	}
+	vj := j.Justification
+	deal := vj.Deal
+	if deal.SecShare.I < 0 || uint64(deal.SecShare.I) > uint64(^uint32(0)) {
+		return nil, fmt.Errorf("%w: share index %d", dkg.ErrMalformed, deal.SecShare.I)
+	}
+	pd := &PlainDeal{
+		SessionID:  clone(deal.SessionID),
+		ShareIndex: uint32(deal.SecShare.I),
+		T:          deal.T,
+	}
+	var err error
+	if pd.Share, err = encode(deal.SecShare.V); err != nil {
+		return nil, err
+	}
+	for _, c := range deal.Commitments {
+		buff, err := encode(c)
+		if err != nil {
+			return nil, err
+		}
+		pd.Commitments = append(pd.Commitments, buff)
+	}
+	if deal.X != nil {
+		if pd.X, err = encode(deal.X); err != nil {
+			return nil, err
+		}
+	}
+	return &Justification{
+		Index:         j.Index,
+		SessionID:     clone(vj.SessionID),
+		VerifierIndex: vj.Index,
+		Deal:          pd,
+		Signature:     clone(vj.Signature),
+	}, nil
+}
+
+// JustificationFromProto returns the justification of the protobuf message
+// p. It checks that the indices of the dealer, of the verifier and of the
+// share are within the bounds, that there are as many commitments as the
+// threshold of the deal, and that the points and scalars are canonically
+// encoded in the suite.
+func JustificationFromProto(suite dkg.Suite, b Bounds, p *Justification) (*dkg.Justification, error) {
+	if p == nil || p.Deal == nil {
+		return nil, fmt.Errorf("%w: incomplete justification", dkg.ErrMalformed)
+	}
+	if err := b.checkDealer(p.Index); err != nil {
+		return nil, err
+	}
+	if err := b.checkVerifier(p.VerifierIndex); err != nil {
+		return nil, err
+	}
+	pd := p.Deal
+	if err := b.checkVerifier(pd.ShareIndex); err != nil {
+		return nil, err
+	}
+	if uint64(len(pd.Commitments)) != uint64(pd.T) {
+		return nil, fmt.Errorf("%w: %d commitments for threshold %d", dkg.ErrMalformed, len(pd.Commitments), pd.T)
+	}
+	deal := &vss.Deal{
+		SessionID: clone(pd.SessionID),
+		SecShare:  &share.PriShare{I: int(pd.ShareIndex)},
+		T:         pd.T,
+	}
+	var err error
+	if deal.SecShare.V, err = decodeScalar(suite, pd.Share); err != nil {
+		return nil, fmt.Errorf("%w: share: %v", dkg.ErrMalformed, err)
+	}
+	if deal.Commitments, err = decodePoints(suite, pd.Commitments); err != nil {
+		return nil, fmt.Errorf("%w: commitment %v", dkg.ErrMalformed, err)
+	}
+	if len(pd.X) != 0 {
+		if deal.X, err = decodeScalar(suite, pd.X); err != nil {
+			return nil, fmt.Errorf("%w: evaluation point: %v", dkg.ErrMalformed, err)
+		}
+	}
+	return &dkg.Justification{
+		Index: p.Index,
+		Justification: &vss.Justification{
+			SessionID: clone(p.SessionID),
+			Index:     p.VerifierIndex,
+			Deal:      deal,
+			Signature: clone(p.Signature),
+		},
+	}, nil
+}
+
+// ReshareConfigToProto returns the protobuf message of the public parameters
+// of the resharing configuration c.
+func ReshareConfigToProto(c *dkg.Config) (*ReshareConfig, error) {
+	if c.OldThreshold < 0 || c.Threshold < 0 {
+		return nil, fmt.Errorf("%w: negative threshold", dkg.ErrMalformed)
+	}
+	p := &ReshareConfig{
+		OldThreshold: uint32(c.OldThreshold),
+		Threshold:    uint32(c.Threshold),
+	}
+	var err error
+	if p.OldNodes, err = encodePoints(c.OldNodes); err != nil {
+		return nil, err
+	}
+	if p.NewNodes, err = encodePoints(c.NewNodes); err != nil {
+		return nil, err
+	}
+	if p.PublicCoeffs, err = encodePoints(c.PublicCoeffs); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ReshareConfigFromProto returns the configuration of the public parameters
+// of the protobuf message p, over the given suite. The node still has to set
+// its longterm key, and its share if it is an old node, before calling
+// dkg.NewDistKeyHandler. It checks that the points are canonically encoded
+// in the suite, that the old threshold is within the bounds of the old
+// nodes, that there are as many public coefficients, and that the new
+// threshold is within the bounds of the new nodes.
+func ReshareConfigFromProto(suite dkg.Suite, p *ReshareConfig) (*dkg.Config, error) {
+	if p == nil {
+		return nil, fmt.Errorf("%w: nil configuration", dkg.ErrMalformed)
+	}
+	if p.OldThreshold < 2 || uint64(p.OldThreshold) > uint64(len(p.OldNodes)) {
+		return nil, fmt.Errorf("%w %d for %d old nodes", dkg.ErrInvalidOldThreshold, p.OldThreshold, len(p.OldNodes))
+	}
+	if uint64(p.Threshold) > uint64(len(p.NewNodes)) || p.Threshold == 1 {
+		return nil, fmt.Errorf("%w %d for %d new nodes", dkg.ErrInvalidThreshold, p.Threshold, len(p.NewNodes))
+	}
+	if len(p.PublicCoeffs) != 0 && uint64(len(p.PublicCoeffs)) != uint64(p.OldThreshold) {
+		return nil, fmt.Errorf("%w: %d public coefficients for old threshold %d", dkg.ErrMalformed, len(p.PublicCoeffs), p.OldThreshold)
+	}
+	c := &dkg.Config{
+		Suite:        suite,
+		OldThreshold: int(p.OldThreshold),
+		Threshold:    int(p.Threshold),
+	}
+	var err error
+	if c.OldNodes, err = decodePoints(suite, p.OldNodes); err != nil {
+		return nil, fmt.Errorf("%w: old node %v", dkg.ErrMalformed, err)
+	}
+	if c.NewNodes, err = decodePoints(suite, p.NewNodes); err != nil {
+		return nil, fmt.Errorf("%w: new node %v", dkg.ErrMalformed, err)
+	}
+	if c.PublicCoeffs, err = decodePoints(suite, p.PublicCoeffs); err != nil {
+		return nil, fmt.Errorf("%w: public coefficient %v", dkg.ErrMalformed, err)
+	}
+	return c, nil
+}
+
+// clone returns a copy of b, or nil if b is empty, as the protobuf encoding
+// does not tell an empty slice from a nil one.
+func clone(b []byte) []byte {
+	if len(b) == 0 {
+		return nil
+	}
+	return append([]byte{}, b...)
+}
+
+func encode(m kyber.Marshaling) ([]byte, error) {
+	if m == nil {
+		return nil, fmt.Errorf("%w: nil point or scalar", dkg.ErrMalformed)
+	}
+	return m.MarshalBinary()
+}
+
+func encodePoints(ps []kyber.Point) ([][]byte, error) {
+	var bs [][]byte
+	for _, p := range ps {
+		b, err := encode(p)
+		if err != nil {
+			return nil, err
+		}
+		bs = append(bs, b)
+	}
+	return bs, nil
+}
+
+func decodePoint(suite dkg.Suite, b []byte) (kyber.Point, error) {
+	p := suite.Point()
+	return p, decodeCanonical(p, b)
+}
+
+func decodeScalar(suite dkg.Suite, b []byte) (kyber.Scalar, error) {
+	s := suite.Scalar()
+	return s, decodeCanonical(s, b)
+}
+
+// decodePoints decodes the points of bs, and returns an error giving the
+// position of the first invalid one.
+func decodePoints(suite dkg.Suite, bs [][]byte) ([]kyber.Point, error) {
+	var ps []kyber.Point
+	for i, b := range bs {
+		p, err := decodePoint(suite, b)
+		if err != nil {
+			return nil, fmt.Errorf("%d: %v", i, err)
+		}
+		ps = append(ps, p)
+	}
+	return ps, nil
+}
+
+// decodeCanonical decodes b into m and checks that b is the canonical
+// encoding of m, the decoding of the points of the suite checking that they
+// are in its group.
+func decodeCanonical(m kyber.Marshaling, b []byte) error {
+	if len(b) != m.MarshalSize() {
+		return fmt.Errorf("%d bytes instead of %d", len(b), m.MarshalSize())
+	}
+	if err := m.UnmarshalBinary(b); err != nil {
+		return err
+	}
+	if c, err := m.MarshalBinary(); err != nil || !bytes.Equal(b, c) {
+		return errors.New("non canonical encoding")
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+// Package pb defines the protobuf messages of the deals, responses and
+// justifications of kyber/share/dkg/pedersen, and of the public parameters
+// that the nodes of a resharing agree on, so that every transport uses the
+// same schema. The schema is in dkg.proto, which is generated from the types
+// of this package with go.dedis.ch/protobuf, the library encoding them: it
+// must be regenerated with "go test -run TestProtoDefinition -update" when
+// they change.
+//
+// The messages are encoded with protobuf.Encode and decoded with
+// protobuf.Decode of go.dedis.ch/protobuf, or with any implementation of
+// protobuf from dkg.proto. The points and scalars are held in their binary
+// encoding. The FromProto conversions decode and check them, along with the
+// indices of the messages, so that a message received from the network is
+// rejected before reaching the DKG. The conversions are lossless: a message
+// converted to protobuf and back is equal to the original one.
+//
+// The field numbers of dkg.proto are the positions of the fields in the types
+// of this package, and they are part of the wire format shared with the other
+// implementations of the DKG: the fields must never be reordered nor removed,
+// and new fields must be appended as optional ones. TestWireFormat checks the
+// encoding of each message against fixed bytes.
+package pb
+
+// Deal is the protobuf message of a dkg.Deal.
+type Deal struct {
+	// Index of the dealer
+	Index uint32
+	// Deal encrypted for its verifier
+	Deal *EncryptedDeal
+	// Signature of the dealer over the deal
+	Signature []byte
+}
+
+// EncryptedDeal is the protobuf message of a vss.EncryptedDeal.
+// Its first fields are the ones of the deals of version 0, which keep their
+// field numbers.
+type EncryptedDeal struct {
+	// Ephemeral Diffie-Hellman key, a point of the suite
+	DHKey []byte `protobuf:"dh_key"`
+	// Signature of the DH key by the dealer
+	Signature []byte
+	// Nonce of the encryption
+	Nonce []byte
+	// Cipher is the encrypted deal
+	Cipher []byte
+	// Version of the encryption, see vss.EncryptedDealVersion
+	Version uint32 `protobuf:"opt"`
+	// SessionID of the deal, empty for version 0
+	SessionID []byte `protobuf:"opt"`
+	// Index of the verifier of the deal, zero for version 0
+	Index uint32 `protobuf:"opt"`
+}
+
+// Response is the protobuf message of a dkg.Response, with the fields of its
+// vss.Response inlined.
+type Response struct {
+	// Index of the dealer of the deal
+	Index uint32
+	// SessionID of the deal
+	SessionID []byte
+	// VerifierIndex is the index of the verifier issuing the response
+	VerifierIndex uint32
+	// Status is true for an approval and false for a complaint
+	Status bool
+	// Signature of the verifier over the response
+	Signature []byte
+}
+
+// Justification is the protobuf message of a dkg.Justification, with the
+// fields of its vss.Justification inlined.
+type Justification struct {
+	// Index of the dealer justifying its deal
+	Index uint32
+	// SessionID of the deal
+	SessionID []byte
+	// VerifierIndex is the index of the verifier who complained
+	VerifierIndex uint32
+	// Deal revealed in clear
+	Deal *PlainDeal
+	// Signature of the dealer over the justification
+	Signature []byte
+}
+
+// PlainDeal is the protobuf message of a vss.Deal, as revealed by a
+// justification.
+type PlainDeal struct {
+	// SessionID of the deal
+	SessionID []byte
+	// ShareIndex is the index of the private share
+	ShareIndex uint32
+	// Share is the value of the private share, a scalar of the suite
+	Share []byte
+	// T is the threshold of the deal
+	T uint32
+	// Commitments are the points of the suite committing to the polynomial
+	Commitments [][]byte
+	// X is the evaluation point of the share, a scalar of the suite, or
+	// empty for the default evaluation point
+	X []byte `protobuf:"opt"`
+}
+
+// ReshareConfig is the protobuf message of the public parameters of a
+// resharing, i.e. the fields of a dkg.Config which must be the same for all
+// the nodes: the ones exchanged when the old nodes hand over to the new ones.
+type ReshareConfig struct {
+	// OldNodes are the longterm public keys of the old nodes
+	OldNodes [][]byte
+	// NewNodes are the longterm public keys of the new nodes
+	NewNodes [][]byte
+	// PublicCoeffs are the coefficients of the distributed public
+	// polynomial, required by the new nodes
+	PublicCoeffs [][]byte
+	// OldThreshold is the threshold of the distributed key
+	OldThreshold uint32
+	// Threshold is the threshold of the new shares, or zero for the default
+	Threshold uint32
+}
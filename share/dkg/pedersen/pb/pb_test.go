@@ -0,0 +1,315 @@
+package pb
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+	dkg "go.dedis.ch/kyber/v3/share/dkg/pedersen"
+	"go.dedis.ch/protobuf"
+)
+
+var updateProto = flag.Bool("update", false, "update dkg.proto")
+
+var suite = edwards25519.NewBlakeSHA256Ed25519()
+
+// smallOrder is the encoding of a point of order 8 of edwards25519.
+var smallOrder, _ = hex.DecodeString("c7176a703d4dd84fba3c0b760d10670f2a2053fa2c39ccc64ec7fd7792ac037a")
+
+func generate(t *testing.T, n int) ([]kyber.Point, []*dkg.DistKeyGenerator) {
+	pubs := make([]kyber.Point, n)
+	secs := make([]kyber.Scalar, n)
+	for i := range pubs {
+		secs[i] = suite.Scalar().Pick(suite.RandomStream())
+		pubs[i] = suite.Point().Mul(secs[i], nil)
+	}
+	dkgs := make([]*dkg.DistKeyGenerator, n)
+	for i := range dkgs {
+		d, err := dkg.NewDistKeyGenerator(suite, secs[i], pubs, n/2+1)
+		require.NoError(t, err)
+		dkgs[i] = d
+	}
+	return pubs, dkgs
+}
+
+// transmit sends the message m through its protobuf encoding into decoded.
+func transmit(t *testing.T, m, decoded interface{}) {
+	buff, err := protobuf.Encode(m)
+	require.NoError(t, err)
+	require.NoError(t, protobuf.Decode(buff, decoded))
+}
+
+// TestDKGOverProto runs a DKG whose deals and responses go through their
+// protobuf messages.
+func TestDKGOverProto(t *testing.T) {
+	n := 5
+	pubs, dkgs := generate(t, n)
+	bounds := BoundsOf(&dkg.Config{NewNodes: pubs})
+	require.Equal(t, Bounds{Dealers: n, Verifiers: n}, bounds)
+
+	var resps []*dkg.Response
+	for _, d := range dkgs {
+		deals, err := d.Deals()
+		require.NoError(t, err)
+		for i, deal := range deals {
+			p, err := DealToProto(deal)
+			require.NoError(t, err)
+			decoded := &Deal{}
+			transmit(t, p, decoded)
+			got, err := DealFromProto(suite, bounds, decoded)
+			require.NoError(t, err)
+			require.Equal(t, deal, got)
+			resp, err := dkgs[i].ProcessDeal(got)
+			require.NoError(t, err)
+			resps = append(resps, resp)
+		}
+	}
+	for _, resp := range resps {
+		p, err := ResponseToProto(resp)
+		require.NoError(t, err)
+		decoded := &Response{}
+		transmit(t, p, decoded)
+		got, err := ResponseFromProto(bounds, decoded)
+		require.NoError(t, err)
+		require.Equal(t, resp, got)
+		for i, d := range dkgs {
+			if uint32(i) == resp.Response.Index {
+				continue
+			}
+			j, err := d.ProcessResponse(got)
+			require.NoError(t, err)
+			require.Nil(t, j)
+		}
+	}
+
+	var public kyber.Point
+	for _, d := range dkgs {
+		require.True(t, d.Certified())
+		dks, err := d.DistKeyShare()
+		require.NoError(t, err)
+		if public == nil {
+			public = dks.Public()
+		}
+		require.True(t, public.Equal(dks.Public()))
+	}
+}
+
+// TestJustificationProto converts the golden justifications of the dkg
+// package.
+func TestJustificationProto(t *testing.T) {
+	bounds := Bounds{Dealers: 5, Verifiers: 5}
+	for _, name := range []string{"justification", "justification_x"} {
+		golden, err := ioutil.ReadFile(filepath.Join("..", "testdata", name+".golden"))
+		require.NoError(t, err)
+		buff, err := hex.DecodeString(strings.TrimSpace(string(golden)))
+		require.NoError(t, err)
+		j := &dkg.Justification{}
+		require.NoError(t, j.UnmarshalBinary(suite, buff))
+
+		p, err := JustificationToProto(j)
+		require.NoError(t, err)
+		require.Equal(t, name == "justification_x", p.Deal.X != nil)
+		decoded := &Justification{}
+		transmit(t, p, decoded)
+		got, err := JustificationFromProto(suite, bounds, decoded)
+		require.NoError(t, err)
+		require.Equal(t, j, got)
+		again, err := got.MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, buff, again)
+
+		// the points and scalars are checked
+		bad := *decoded
+		deal := *decoded.Deal
+		bad.Deal = &deal
+		deal.Commitments = append([][]byte{smallOrder}, decoded.Deal.Commitments[1:]...)
+		_, err = JustificationFromProto(suite, bounds, &bad)
+		require.True(t, errors.Is(err, dkg.ErrMalformed))
+		deal.Commitments = decoded.Deal.Commitments[1:]
+		_, err = JustificationFromProto(suite, bounds, &bad)
+		require.True(t, errors.Is(err, dkg.ErrMalformed))
+		deal.Commitments = decoded.Deal.Commitments
+		deal.Share = bytes.Repeat([]byte{0xff}, 32)
+		_, err = JustificationFromProto(suite, bounds, &bad)
+		require.True(t, errors.Is(err, dkg.ErrMalformed))
+		deal.Share = decoded.Deal.Share
+		deal.ShareIndex = 5
+		_, err = JustificationFromProto(suite, bounds, &bad)
+		require.True(t, errors.Is(err, ErrVerifierIndex))
+		_, err = JustificationFromProto(suite, Bounds{Dealers: 2, Verifiers: 5}, decoded)
+		require.True(t, errors.Is(err, dkg.ErrDealerIndex))
+	}
+}
+
+func TestFromProtoBounds(t *testing.T) {
+	pubs, dkgs := generate(t, 3)
+	deals, err := dkgs[2].Deals()
+	require.NoError(t, err)
+	deal, err := DealToProto(deals[1])
+	require.NoError(t, err)
+	bounds := BoundsOf(&dkg.Config{NewNodes: pubs})
+	_, err = DealFromProto(suite, bounds, deal)
+	require.NoError(t, err)
+
+	_, err = DealFromProto(suite, Bounds{Dealers: 2, Verifiers: 3}, deal)
+	require.True(t, errors.Is(err, dkg.ErrDealerIndex))
+	_, err = DealFromProto(suite, Bounds{Dealers: 3, Verifiers: 1}, deal)
+	require.True(t, errors.Is(err, ErrVerifierIndex))
+	_, err = DealFromProto(suite, bounds, &Deal{Index: 1})
+	require.True(t, errors.Is(err, dkg.ErrMalformed))
+	_, err = DealFromProto(suite, bounds, nil)
+	require.True(t, errors.Is(err, dkg.ErrMalformed))
+
+	// the encrypted deal alone
+	e, err := EncryptedDealToProto(deals[1].Deal)
+	require.NoError(t, err)
+	require.Equal(t, deal.Deal, e)
+	got, err := EncryptedDealFromProto(suite, bounds, e)
+	require.NoError(t, err)
+	require.Equal(t, deals[1].Deal, got)
+	_, err = EncryptedDealFromProto(suite, Bounds{Dealers: 3, Verifiers: 1}, e)
+	require.True(t, errors.Is(err, ErrVerifierIndex))
+	_, err = EncryptedDealToProto(nil)
+	require.True(t, errors.Is(err, dkg.ErrMalformed))
+	dhKey := deal.Deal.DHKey
+	for _, key := range [][]byte{smallOrder, dhKey[1:], nil} {
+		deal.Deal.DHKey = key
+		_, err = DealFromProto(suite, bounds, deal)
+		require.True(t, errors.Is(err, dkg.ErrMalformed))
+	}
+	deal.Deal.DHKey = dhKey
+	deal.Deal.Version = 0
+	_, err = DealFromProto(suite, bounds, deal)
+	require.True(t, errors.Is(err, dkg.ErrMalformed))
+
+	resp := &Response{Index: 2, VerifierIndex: 3}
+	_, err = ResponseFromProto(bounds, resp)
+	require.True(t, errors.Is(err, ErrVerifierIndex))
+	resp.Index, resp.VerifierIndex = 3, 2
+	_, err = ResponseFromProto(bounds, resp)
+	require.True(t, errors.Is(err, dkg.ErrDealerIndex))
+	_, err = ResponseFromProto(bounds, nil)
+	require.True(t, errors.Is(err, dkg.ErrMalformed))
+
+	weighted := BoundsOf(&dkg.Config{OldNodes: pubs[:2], NewNodes: pubs, Weights: []int{1, 2, 3}})
+	require.Equal(t, Bounds{Dealers: 2, Verifiers: 6}, weighted)
+}
+
+func TestReshareConfigProto(t *testing.T) {
+	pubs, _ := generate(t, 5)
+	coeffs := []kyber.Point{suite.Point().Pick(suite.RandomStream()), suite.Point().Pick(suite.RandomStream())}
+	c := &dkg.Config{
+		Suite:        suite,
+		OldNodes:     pubs[:3],
+		NewNodes:     pubs[1:],
+		PublicCoeffs: coeffs,
+		OldThreshold: 2,
+		Threshold:    3,
+	}
+	p, err := ReshareConfigToProto(c)
+	require.NoError(t, err)
+	decoded := &ReshareConfig{}
+	transmit(t, p, decoded)
+	got, err := ReshareConfigFromProto(suite, decoded)
+	require.NoError(t, err)
+	require.Equal(t, suite, got.Suite)
+	require.Equal(t, c.OldThreshold, got.OldThreshold)
+	require.Equal(t, c.Threshold, got.Threshold)
+	for _, ps := range [][2][]kyber.Point{{c.OldNodes, got.OldNodes}, {c.NewNodes, got.NewNodes}, {c.PublicCoeffs, got.PublicCoeffs}} {
+		require.Len(t, ps[1], len(ps[0]))
+		for i := range ps[0] {
+			require.True(t, ps[0][i].Equal(ps[1][i]))
+		}
+	}
+
+	bad := *decoded
+	bad.OldThreshold = 4
+	_, err = ReshareConfigFromProto(suite, &bad)
+	require.True(t, errors.Is(err, dkg.ErrInvalidOldThreshold))
+	bad = *decoded
+	bad.Threshold = 5
+	_, err = ReshareConfigFromProto(suite, &bad)
+	require.True(t, errors.Is(err, dkg.ErrInvalidThreshold))
+	bad = *decoded
+	bad.PublicCoeffs = bad.PublicCoeffs[:1]
+	_, err = ReshareConfigFromProto(suite, &bad)
+	require.True(t, errors.Is(err, dkg.ErrMalformed))
+	bad = *decoded
+	bad.NewNodes = append([][]byte{smallOrder}, bad.NewNodes[1:]...)
+	_, err = ReshareConfigFromProto(suite, &bad)
+	require.True(t, errors.Is(err, dkg.ErrMalformed))
+}
+
+// TestWireFormat checks the protobuf encoding of each message, which any
+// implementation of dkg.proto must produce, so that the field numbers and
+// types cannot change unnoticed.
+func TestWireFormat(t *testing.T) {
+	vectors := []struct {
+		msg, decoded interface{}
+		wire         string
+	}{
+		{
+			&Deal{Index: 1, Deal: &EncryptedDeal{Version: 1, SessionID: []byte{0xaa}, Index: 2, DHKey: []byte{0xd1},
+				Signature: []byte{0x51}, Nonce: []byte{0x4e}, Cipher: []byte{0xc1}}, Signature: []byte{0x52}},
+			&Deal{},
+			"080112130a01d11201511a014e2201c128013201aa38021a0152",
+		},
+		{
+			&Response{Index: 1, SessionID: []byte{0xaa}, VerifierIndex: 2, Status: true, Signature: []byte{0x51}},
+			&Response{},
+			"08011201aa180220012a0151",
+		},
+		{
+			&Justification{Index: 1, SessionID: []byte{0xaa}, VerifierIndex: 2, Deal: &PlainDeal{SessionID: []byte{0xaa},
+				ShareIndex: 2, Share: []byte{0x5a}, T: 3, Commitments: [][]byte{{0xc1}, {0xc2}}, X: []byte{0x0f}},
+				Signature: []byte{0x51}},
+			&Justification{},
+			"08011201aa180222130a01aa10021a015a20032a01c12a01c232010f2a0151",
+		},
+		{
+			&ReshareConfig{OldNodes: [][]byte{{0x01}}, NewNodes: [][]byte{{0x02}, {0x03}}, PublicCoeffs: [][]byte{{0xc1}},
+				OldThreshold: 2, Threshold: 3},
+			&ReshareConfig{},
+			"0a01011201021201031a01c120022803",
+		},
+	}
+	for _, v := range vectors {
+		buff, err := protobuf.Encode(v.msg)
+		require.NoError(t, err)
+		require.Equal(t, v.wire, hex.EncodeToString(buff))
+		wire, err := hex.DecodeString(v.wire)
+		require.NoError(t, err)
+		require.NoError(t, protobuf.Decode(wire, v.decoded))
+		require.Equal(t, v.msg, v.decoded)
+	}
+}
+
+// TestProtoDefinition checks that dkg.proto is the schema of the messages.
+func TestProtoDefinition(t *testing.T) {
+	var buf bytes.Buffer
+	buf.WriteString(protoHeader)
+	types := []interface{}{Deal{}, EncryptedDeal{}, Response{}, Justification{}, PlainDeal{}, ReshareConfig{}}
+	require.NoError(t, protobuf.GenerateProtobufDefinition(&buf, types, nil, nil))
+	if *updateProto {
+		require.NoError(t, ioutil.WriteFile("dkg.proto", buf.Bytes(), 0644))
+	}
+	golden, err := ioutil.ReadFile("dkg.proto")
+	require.NoError(t, err)
+	require.Equal(t, string(golden), buf.String())
+}
+
+const protoHeader = `// Code generated by TestProtoDefinition of go.dedis.ch/kyber/v3/share/dkg/pedersen/pb. DO NOT EDIT.
+// Regenerate with: go test -run TestProtoDefinition -update
+
+syntax = "proto2";
+
+package kyber.dkg.pedersen;
+`
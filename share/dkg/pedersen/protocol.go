@@ -0,0 +1,339 @@
+package dkg
+
+import (
+	"fmt"
+	"time"
+)
+
+// Board is the network layer of a Protocol: it sends the messages of the local
+// node to the other participants and delivers theirs. The Protocol does not
+// retry the sending, so that a Board on top of an unreliable network should
+// handle the retries itself. The incoming channels are read until the end of
+// the protocol, and messages which are delivered twice are harmless.
+type Board interface {
+	// SendDeals sends each deal to the participant which must process it:
+	// the deal of key i goes to the holder of the share i among the new
+	// nodes, see DistKeyGenerator.ShareHolder. Without Config.Weights, it
+	// is the new node of index i.
+	SendDeals(deals map[int]*Deal) error
+	// IncomingDeals delivers the deals sent to the local node.
+	IncomingDeals() <-chan *Deal
+	// SendResponses broadcasts the responses to all the other participants,
+	// old and new nodes.
+	SendResponses(resps []*Response) error
+	// IncomingResponses delivers the responses of the other participants.
+	IncomingResponses() <-chan *Response
+	// SendJustifications broadcasts the justifications to all the other
+	// participants, old and new nodes.
+	SendJustifications(js []*Justification) error
+	// IncomingJustifications delivers the justifications of the other
+	// participants.
+	IncomingJustifications() <-chan *Justification
+}
+
+// Phase is a phase of a Protocol.
+type Phase int
+
+const (
+	// DealPhase is the first phase, during which the nodes send their deals
+	// and process the deals they receive.
+	DealPhase Phase = iota
+	// ResponsePhase is the phase during which the nodes send the responses
+	// to the deals they received and process the responses of the others.
+	ResponsePhase
+	// JustificationPhase is the phase during which the dealers send the
+	// justifications of the complaints about their deals and the nodes
+	// process them.
+	JustificationPhase
+	// FinishPhase is the end of the protocol: the nodes stop processing
+	// messages and compute their share of the distributed key.
+	FinishPhase
+)
+
+func (p Phase) String() string {
+	switch p {
+	case DealPhase:
+		return "deal"
+	case ResponsePhase:
+		return "response"
+	case JustificationPhase:
+		return "justification"
+	case FinishPhase:
+		return "finish"
+	}
+	return fmt.Sprintf("phase(%d)", int(p))
+}
+
+// Phaser tells a Protocol when to move to the next phase, i.e. when the
+// messages of the current phase are not worth waiting for anymore. The
+// protocol moves to the next phase earlier when it has received all the
+// messages it expects.
+type Phaser interface {
+	// NextPhase delivers the phases in increasing order. The phases which
+	// the protocol has already reached are ignored.
+	NextPhase() <-chan Phase
+}
+
+type timePhaser struct {
+	phases chan Phase
+}
+
+// NewTimePhaser returns a Phaser moving to the next phase every period,
+// starting from its creation, so that the protocol finishes after at most
+// three periods. The period must be long enough for all the nodes to send and
+// process the messages of a phase, including the clock skew between the nodes
+// starting the protocol.
+func NewTimePhaser(period time.Duration) Phaser {
+	t := &timePhaser{phases: make(chan Phase, 3)}
+	for i, phase := range []Phase{ResponsePhase, JustificationPhase, FinishPhase} {
+		phase := phase
+		time.AfterFunc(time.Duration(i+1)*period, func() { t.phases <- phase })
+	}
+	return t
+}
+
+func (t *timePhaser) NextPhase() <-chan Phase {
+	return t.phases
+}
+
+// Result is the outcome of a Protocol. Key is the share of the distributed key
+// of the local node, or nil if Err is not nil. It is nil as well for a
+// dealer-only node (see DistKeyGenerator.DealerOnly), which holds no share.
+type Result struct {
+	Key *DistKeyShare
+	Err error
+}
+
+// Protocol runs a DistKeyGenerator over a Board, phase by phase, so that the
+// application only provides the network layer. It runs in its own goroutine
+// from NewProtocol on, and delivers its Result on WaitEnd.
+//
+// In the deal phase, the node sends its deals and processes the deals it
+// receives. In the response phase, it sends the responses to these deals and
+// processes the responses of the other nodes. In the justification phase, it
+// sends the justifications of the complaints about its deal and processes the
+// justifications of the other dealers. Finally, it calls Finish on the
+// generator and computes its share of the distributed key. The messages
+// received before their phase are kept until it starts, the ones received
+// after their phase are dropped. The protocol moves to the next phase when
+// the Phaser says so, or as soon as it received all the messages it expects:
+// all the deals during the deal phase, all the responses to them during the
+// response phase. It finishes as soon as all the deals are certified (see
+// DistKeyGenerator.Certified).
+//
+// The DistKeyGenerator must not be used by the caller while the protocol runs.
+type Protocol struct {
+	dkg    *DistKeyGenerator
+	board  Board
+	phaser Phaser
+	done   chan Result
+
+	phase Phase
+	// responses of the local node, sent at the start of the response phase
+	responses []*Response
+	// justifications of the local node, sent at the start of the
+	// justification phase
+	justifications []*Justification
+	// messages of the other nodes received before their phase
+	earlyResponses      []*Response
+	earlyJustifications []*Justification
+}
+
+// NewProtocol starts running the DistKeyGenerator d over the board, moving to
+// the next phases as told by the phaser, e.g. NewTimePhaser.
+func NewProtocol(d *DistKeyGenerator, board Board, phaser Phaser) *Protocol {
+	p := &Protocol{
+		dkg:    d,
+		board:  board,
+		phaser: phaser,
+		done:   make(chan Result, 1),
+	}
+	go p.run()
+	return p
+}
+
+// WaitEnd returns the channel delivering the Result of the protocol once it
+// has finished.
+func (p *Protocol) WaitEnd() <-chan Result {
+	return p.done
+}
+
+func (p *Protocol) run() {
+	deals, err := p.dkg.Deals()
+	if err != nil {
+		p.done <- Result{Err: err}
+		return
+	}
+	if len(deals) > 0 {
+		if err := p.board.SendDeals(deals); err != nil {
+			p.done <- Result{Err: fmt.Errorf("dkg: sending the deals: %w", err)}
+			return
+		}
+	}
+	incomingDeals := p.board.IncomingDeals()
+	incomingResponses := p.board.IncomingResponses()
+	incomingJustifications := p.board.IncomingJustifications()
+	phases := p.phaser.NextPhase()
+
+	p.phase = DealPhase
+	err = p.advance()
+	for err == nil && p.phase != FinishPhase {
+		select {
+		case deal, ok := <-incomingDeals:
+			if !ok {
+				incomingDeals = nil
+				continue
+			}
+			p.processDeal(deal)
+		case resp, ok := <-incomingResponses:
+			if !ok {
+				incomingResponses = nil
+				continue
+			}
+			p.processResponse(resp)
+		case j, ok := <-incomingJustifications:
+			if !ok {
+				incomingJustifications = nil
+				continue
+			}
+			p.processJustification(j)
+		case next, ok := <-phases:
+			if !ok {
+				phases = nil
+				continue
+			}
+			for err == nil && p.phase < next && p.phase < FinishPhase {
+				err = p.moveTo(p.phase + 1)
+			}
+		}
+		if err == nil {
+			err = p.advance()
+		}
+	}
+	if err != nil {
+		p.done <- Result{Err: err}
+		return
+	}
+	p.done <- p.finish()
+}
+
+// advance moves to the next phases as long as the current one has received
+// all the messages it expects. The phases are never skipped, so that the
+// justifications of a dealer which is certified in its own view are still
+// sent to the nodes which complained.
+func (p *Protocol) advance() error {
+	for {
+		var next bool
+		switch p.phase {
+		case DealPhase:
+			next = p.dkg.ReceivedDeals() >= p.dkg.ExpectedDeals()
+		case ResponsePhase:
+			next = p.dkg.Certified() || p.responded()
+		case JustificationPhase:
+			next = p.dkg.Certified()
+		}
+		if !next {
+			return nil
+		}
+		if err := p.moveTo(p.phase + 1); err != nil {
+			return err
+		}
+	}
+}
+
+// responded returns true if all the deals received have a response of every
+// node, i.e. if the only thing left to wait for are the justifications of the
+// complaints. A dealer-only node has no deal to wait for, but the responses to
+// its own deal, which Certified already tells.
+func (p *Protocol) responded() bool {
+	if p.dkg.DealerOnly() {
+		return false
+	}
+	for _, v := range p.dkg.Verifiers() {
+		if len(v.MissingResponses()) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// moveTo starts the phase, sending the messages of the local node and
+// processing the ones of the other nodes which were waiting for it.
+func (p *Protocol) moveTo(phase Phase) error {
+	p.phase = phase
+	switch phase {
+	case ResponsePhase:
+		if len(p.responses) > 0 {
+			if err := p.board.SendResponses(p.responses); err != nil {
+				return fmt.Errorf("dkg: sending the responses: %w", err)
+			}
+		}
+		early := p.earlyResponses
+		p.earlyResponses = nil
+		for _, resp := range early {
+			p.processResponse(resp)
+		}
+	case JustificationPhase:
+		if len(p.justifications) > 0 {
+			if err := p.board.SendJustifications(p.justifications); err != nil {
+				return fmt.Errorf("dkg: sending the justifications: %w", err)
+			}
+		}
+		early := p.earlyJustifications
+		p.earlyJustifications = nil
+		for _, j := range early {
+			p.processJustification(j)
+		}
+	}
+	return nil
+}
+
+// processDeal processes a deal received during the deal phase. Invalid deals
+// are dropped, as the missing response of the local node counts against the
+// dealer at the end.
+func (p *Protocol) processDeal(deal *Deal) {
+	if p.phase != DealPhase {
+		return
+	}
+	if resp, err := p.dkg.ProcessDeal(deal); err == nil {
+		p.responses = append(p.responses, resp)
+	}
+}
+
+func (p *Protocol) processResponse(resp *Response) {
+	switch {
+	case p.phase < ResponsePhase:
+		p.earlyResponses = append(p.earlyResponses, resp)
+		return
+	case p.phase > ResponsePhase:
+		return
+	}
+	j, err := p.dkg.ProcessResponse(resp)
+	if err == nil && j != nil {
+		p.justifications = append(p.justifications, j)
+	}
+}
+
+func (p *Protocol) processJustification(j *Justification) {
+	switch {
+	case p.phase < JustificationPhase:
+		p.earlyJustifications = append(p.earlyJustifications, j)
+		return
+	case p.phase > JustificationPhase:
+		return
+	}
+	_ = p.dkg.ProcessJustification(j)
+}
+
+// finish freezes the qualified set and computes the share of the local node.
+func (p *Protocol) finish() Result {
+	p.dkg.Finish()
+	if p.dkg.DealerOnly() {
+		return Result{}
+	}
+	key, err := p.dkg.DistKeyShare()
+	if err != nil {
+		return Result{Err: err}
+	}
+	return Result{Key: key}
+}
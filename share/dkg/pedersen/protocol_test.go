@@ -0,0 +1,238 @@
+package dkg
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/share"
+	vss "go.dedis.ch/kyber/v3/share/vss/pedersen"
+)
+
+// testNetwork connects the boards of the nodes in memory. The messages are
+// copied through their encoding, as if they were sent over the network.
+type testNetwork struct {
+	boards []*testBoard
+}
+
+type testBoard struct {
+	net            *testNetwork
+	index          int
+	offline        bool
+	tamper         func(deals map[int]*Deal)
+	deals          chan *Deal
+	responses      chan *Response
+	justifications chan *Justification
+}
+
+func newTestNetwork(n int) *testNetwork {
+	net := &testNetwork{boards: make([]*testBoard, n)}
+	for i := range net.boards {
+		net.boards[i] = &testBoard{
+			net:            net,
+			index:          i,
+			deals:          make(chan *Deal, n*n),
+			responses:      make(chan *Response, n*n),
+			justifications: make(chan *Justification, n*n),
+		}
+	}
+	return net
+}
+
+func (b *testBoard) SendDeals(deals map[int]*Deal) error {
+	if b.offline {
+		return nil
+	}
+	if b.tamper != nil {
+		b.tamper(deals)
+	}
+	for i, deal := range deals {
+		b.net.boards[i].deals <- deal
+	}
+	return nil
+}
+
+func (b *testBoard) SendResponses(resps []*Response) error {
+	for _, to := range b.net.boards {
+		if b.offline || to == b {
+			continue
+		}
+		for _, resp := range resps {
+			buff, err := resp.MarshalBinary()
+			if err != nil {
+				return err
+			}
+			r := &Response{}
+			if err := r.UnmarshalBinary(suite, buff); err != nil {
+				return err
+			}
+			to.responses <- r
+		}
+	}
+	return nil
+}
+
+func (b *testBoard) SendJustifications(js []*Justification) error {
+	for _, to := range b.net.boards {
+		if b.offline || to == b {
+			continue
+		}
+		for _, j := range js {
+			buff, err := j.MarshalBinary()
+			if err != nil {
+				return err
+			}
+			r := &Justification{}
+			if err := r.UnmarshalBinary(suite, buff); err != nil {
+				return err
+			}
+			to.justifications <- r
+		}
+	}
+	return nil
+}
+
+func (b *testBoard) IncomingDeals() <-chan *Deal                   { return b.deals }
+func (b *testBoard) IncomingResponses() <-chan *Response           { return b.responses }
+func (b *testBoard) IncomingJustifications() <-chan *Justification { return b.justifications }
+
+// runProtocols runs the protocol on the nodes which are not offline and
+// returns their results.
+func runProtocols(t *testing.T, dkgs []*DistKeyGenerator, net *testNetwork, period time.Duration) []Result {
+	protocols := make([]*Protocol, len(dkgs))
+	for i, d := range dkgs {
+		if !net.boards[i].offline {
+			protocols[i] = NewProtocol(d, net.boards[i], NewTimePhaser(period))
+		}
+	}
+	results := make([]Result, len(dkgs))
+	for i, p := range protocols {
+		if p == nil {
+			continue
+		}
+		select {
+		case results[i] = <-p.WaitEnd():
+		case <-time.After(4 * period):
+			t.Fatalf("protocol of node %d did not finish", i)
+		}
+	}
+	return results
+}
+
+// checkResults checks that the nodes of the results share the same
+// distributed key, and returns its secret.
+func checkResults(t *testing.T, results []Result, skip int) kyber.Scalar {
+	var shares []*share.PriShare
+	var first *DistKeyShare
+	for i, res := range results {
+		if i == skip {
+			continue
+		}
+		require.NoError(t, res.Err)
+		require.NotNil(t, res.Key)
+		if first == nil {
+			first = res.Key
+		}
+		require.True(t, checkDks(first, res.Key))
+		shares = append(shares, res.Key.Share)
+	}
+	secret, err := share.RecoverSecret(suite, shares, len(first.Commits), len(results))
+	require.NoError(t, err)
+	require.True(t, suite.Point().Mul(secret, nil).Equal(first.Public()))
+	return secret
+}
+
+func TestProtocol(t *testing.T) {
+	_, _, dkgs := generate(defaultN, defaultT)
+	net := newTestNetwork(defaultN)
+
+	// the nodes finish as soon as all the deals are certified, long before
+	// the phaser moves to the next phase
+	start := time.Now()
+	results := runProtocols(t, dkgs, net, time.Minute)
+	require.True(t, time.Since(start) < time.Minute)
+	checkResults(t, results, -1)
+	for _, d := range dkgs {
+		require.True(t, d.Finished())
+		require.Len(t, d.QUAL(), defaultN)
+	}
+}
+
+func TestProtocolOfflineNode(t *testing.T) {
+	_, _, dkgs := generate(defaultN, defaultT)
+	net := newTestNetwork(defaultN)
+	offline := defaultN - 1
+	net.boards[offline].offline = true
+
+	results := runProtocols(t, dkgs, net, 500*time.Millisecond)
+	checkResults(t, results, offline)
+	for i, d := range dkgs {
+		if i != offline {
+			require.Len(t, d.QUAL(), defaultN-1)
+			require.NotContains(t, d.QUAL(), offline)
+		}
+	}
+}
+
+func TestProtocolComplaint(t *testing.T) {
+	_, _, dkgs := generate(defaultN, defaultT)
+	net := newTestNetwork(defaultN)
+	// the dealer 0 gives a wrong share to the node 1, which complains, and
+	// reveals the right one in its justification: all the messages arrive,
+	// so that the nodes do not wait for the phaser
+	bad := malformedDeal(t, dkgs, func(d *vss.Deal) {
+		d.SecShare.V = suite.Scalar().Pick(suite.RandomStream())
+	})
+	net.boards[0].tamper = func(deals map[int]*Deal) {
+		deals[1] = bad
+	}
+
+	results := runProtocols(t, dkgs, net, 10*time.Second)
+	checkResults(t, results, -1)
+	for _, d := range dkgs {
+		require.Len(t, d.QUAL(), defaultN)
+	}
+}
+
+type failingBoard struct {
+	*testBoard
+}
+
+var errBoard = errors.New("board: network down")
+
+func (f failingBoard) SendDeals(map[int]*Deal) error {
+	return errBoard
+}
+
+func TestProtocolBoardError(t *testing.T) {
+	_, _, dkgs := generate(defaultN, defaultT)
+	net := newTestNetwork(defaultN)
+	p := NewProtocol(dkgs[0], failingBoard{net.boards[0]}, NewTimePhaser(time.Minute))
+	res := <-p.WaitEnd()
+	require.Nil(t, res.Key)
+	require.True(t, errors.Is(res.Err, errBoard))
+}
+
+// stepPhaser moves to the phases it is given.
+type stepPhaser chan Phase
+
+func (s stepPhaser) NextPhase() <-chan Phase {
+	return s
+}
+
+func TestProtocolPhaser(t *testing.T) {
+	_, _, dkgs := generate(defaultN, defaultT)
+	net := newTestNetwork(defaultN)
+	// the node 0 is the only one to run: it cannot reach the end of the
+	// deal phase alone, and fails at the end for lack of certified deals
+	phaser := make(stepPhaser, 1)
+	p := NewProtocol(dkgs[0], net.boards[0], phaser)
+	phaser <- FinishPhase
+	res := <-p.WaitEnd()
+	require.Error(t, res.Err)
+	require.Nil(t, res.Key)
+	require.True(t, dkgs[0].Finished())
+	require.Equal(t, "justification", JustificationPhase.String())
+}
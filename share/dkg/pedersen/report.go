@@ -0,0 +1,618 @@
+package dkg
+
+import (
+	"bytes"
+	"encoding"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/proof/dleq"
+	"go.dedis.ch/kyber/v3/share"
+	vss "go.dedis.ch/kyber/v3/share/vss/pedersen"
+	"go.dedis.ch/kyber/v3/sign/schnorr"
+)
+
+// ReasonCode tells why a node rejected the deal of a dealer.
+type ReasonCode uint8
+
+const (
+	// ReasonInvalidSignature means that the encrypted deal is not signed by
+	// the dealer, although the deal is.
+	ReasonInvalidSignature ReasonCode = iota + 1
+	// ReasonMalformed means that the deal cannot be decrypted or decoded, or
+	// that it misses one of its fields.
+	ReasonMalformed
+	// ReasonWrongIndex means that the deal holds the share of another node.
+	ReasonWrongIndex
+	// ReasonSessionID means that the session ID of the deal does not match
+	// its content, or the one of a previous deal of the same dealer.
+	ReasonSessionID
+	// ReasonInvalidShare means that the share does not verify against the
+	// commitments of the deal, or that the commitments do not match the
+	// threshold. The node complained about the deal.
+	ReasonInvalidShare
+	// ReasonInconsistentShare means that, in a resharing, the deal does not
+	// reshare the share of the dealer. The node complained about the deal.
+	ReasonInconsistentShare
+)
+
+var reasonNames = map[ReasonCode]string{
+	ReasonInvalidSignature:  "invalid signature",
+	ReasonMalformed:         "malformed deal",
+	ReasonWrongIndex:        "wrong index",
+	ReasonSessionID:         "session id mismatch",
+	ReasonInvalidShare:      "invalid share",
+	ReasonInconsistentShare: "inconsistent share",
+}
+
+func (r ReasonCode) String() string {
+	if name, ok := reasonNames[r]; ok {
+		return name
+	}
+	return fmt.Sprintf("reason %d", uint8(r))
+}
+
+// rejectionReason returns the reason code of the error of the verification of
+// a deal signed by its dealer, or false if the error does not show that the
+// dealer misbehaved, e.g. for a deal received twice.
+func rejectionReason(err error) (ReasonCode, bool) {
+	switch {
+	case errors.Is(err, vss.ErrDealAlreadyProcessed):
+		return 0, false
+	case errors.Is(err, vss.ErrInvalidSignature):
+		return ReasonInvalidSignature, true
+	case errors.Is(err, vss.ErrDealOutOfIndex):
+		return ReasonWrongIndex, true
+	case errors.Is(err, vss.ErrInvalidSessionID), errors.Is(err, vss.ErrSessionIDMismatch):
+		return ReasonSessionID, true
+	case errors.Is(err, vss.ErrMalformed), errors.Is(err, vss.ErrLegacyDeal):
+		return ReasonMalformed, true
+	}
+	return 0, false
+}
+
+// MisbehaviorReport is the evidence of the misbehaviors seen by a node during
+// a run of the protocol, returned by DistKeyGenerator.Report. The evidence is
+// made of the messages signed by the misbehaving nodes, in their canonical
+// encoding (see Deal.MarshalBinary, Response.MarshalBinary and
+// Justification.MarshalBinary), so that the report can be serialized, e.g.
+// with encoding/json, and checked by a third party with Verify.
+type MisbehaviorReport struct {
+	// Dealers has the report of each dealer, in the order of their index.
+	Dealers []DealerReport `json:"dealers"`
+	// Verifiers has the report of each holder of a share, in the order of
+	// the index of the shares.
+	Verifiers []VerifierReport `json:"verifiers"`
+	// Nonce is the nonce of the run, see Config.Nonce.
+	Nonce []byte `json:"nonce,omitempty"`
+}
+
+// DealerReport is the part of a MisbehaviorReport about a dealer.
+type DealerReport struct {
+	// Index of the dealer in the list of dealers.
+	Index uint32 `json:"index"`
+	// InQUAL tells whether the deal of the dealer is in the qualified set of
+	// the node at the time of the report.
+	InQUAL bool `json:"in_qual"`
+	// Rejections are the deals of the dealer rejected by the node.
+	Rejections []Rejection `json:"rejections,omitempty"`
+	// Complaints are the complaints about the deal of the dealer, as signed
+	// by their verifiers, even if the dealer justified them since.
+	Complaints [][]byte `json:"complaints,omitempty"`
+	// FailedJustifications are the justifications signed by the dealer which
+	// reveal an invalid share.
+	FailedJustifications [][]byte `json:"failed_justifications,omitempty"`
+}
+
+// Rejection is a deal rejected by a node, signed by its dealer. Only the node
+// can decrypt the deal to check the reason, but it complains about the deals
+// with an invalid share, which the dealer must then justify in public.
+type Rejection struct {
+	Reason ReasonCode `json:"reason"`
+	Deal   []byte     `json:"deal"`
+}
+
+// VerifierReport is the part of a MisbehaviorReport about the holder of a
+// share.
+type VerifierReport struct {
+	// Index of the share of the verifier.
+	Index uint32 `json:"index"`
+	// ComplaintsIssued are the indexes of the dealers the verifier complained
+	// about. The complaints are in the reports of the dealers.
+	ComplaintsIssued []uint32 `json:"complaints_issued,omitempty"`
+	// Contradictions are the responses of the verifier with different
+	// statuses for the same deal.
+	Contradictions []Contradiction `json:"contradictions,omitempty"`
+	// MissingResponses are the indexes of the dealers whose deal the node
+	// received without receiving the response of the verifier about it. They
+	// are only a misbehavior once the timeout is over, and cannot be proven.
+	MissingResponses []uint32 `json:"missing_responses,omitempty"`
+}
+
+// Contradiction is a pair of responses signed by the same verifier about the
+// same deal, one approving it and the other complaining about it.
+type Contradiction struct {
+	First  []byte `json:"first"`
+	Second []byte `json:"second"`
+}
+
+// ErrInvalidEvidence is returned by MisbehaviorReport.Verify for a piece of
+// evidence which does not prove the misbehavior it is given for.
+var ErrInvalidEvidence = errors.New("dkg: invalid evidence")
+
+// evidence gathers the signed messages showing misbehaviors for Report. It
+// keeps at most one message per dealer, verifier and kind of misbehavior, so
+// that replaying messages can neither inflate the accounting nor make it grow
+// without bound.
+type evidence struct {
+	// encoded deals rejected, by dealer and reason
+	rejections map[uint32]map[ReasonCode][]byte
+	// first signed response received, by dealer and verifier
+	responses map[uint32]map[uint32]*vss.Response
+	// contradicting responses, by dealer and verifier
+	contradictions map[uint32]map[uint32]Contradiction
+	// encoded justifications revealing an invalid share, by dealer and
+	// verifier
+	justifications map[uint32]map[uint32][]byte
+}
+
+func newEvidence() *evidence {
+	return &evidence{
+		rejections:     make(map[uint32]map[ReasonCode][]byte),
+		responses:      make(map[uint32]map[uint32]*vss.Response),
+		contradictions: make(map[uint32]map[uint32]Contradiction),
+		justifications: make(map[uint32]map[uint32][]byte),
+	}
+}
+
+// rejectDeal records the deal, whose signature has been checked, as rejected
+// for the reason.
+func (e *evidence) rejectDeal(dd *Deal, reason ReasonCode) {
+	if e.rejections[dd.Index] == nil {
+		e.rejections[dd.Index] = make(map[ReasonCode][]byte)
+	}
+	if _, ok := e.rejections[dd.Index][reason]; ok {
+		return
+	}
+	if buff, err := dd.MarshalBinary(); err == nil {
+		e.rejections[dd.Index][reason] = buff
+	}
+}
+
+// response records a response, whose signature has been checked, about the
+// deal of the dealer. A copy is kept since the verifiers turn the complaints
+// they store into approvals once they are justified.
+func (e *evidence) response(dealer uint32, r *vss.Response) {
+	if e.responses[dealer] == nil {
+		e.responses[dealer] = make(map[uint32]*vss.Response)
+	}
+	if _, ok := e.responses[dealer][r.Index]; ok {
+		return
+	}
+	c := *r
+	e.responses[dealer][r.Index] = &c
+}
+
+// duplicate records a response, whose signature has been checked, rejected
+// because the verifier already responded about the deal of the dealer, as a
+// contradiction if the statuses differ. It returns true if the response is a
+// copy of the first one, which is then only received twice.
+func (e *evidence) duplicate(dealer uint32, r *vss.Response) bool {
+	first, ok := e.responses[dealer][r.Index]
+	if !ok {
+		// the first response was set by the node itself, unsigned
+		e.response(dealer, r)
+		return true
+	}
+	if sameResponse(first, r) {
+		return true
+	}
+	if first.Status == r.Status || !bytes.Equal(first.SessionID, r.SessionID) {
+		return false
+	}
+	if e.contradictions[dealer] == nil {
+		e.contradictions[dealer] = make(map[uint32]Contradiction)
+	}
+	if _, ok := e.contradictions[dealer][r.Index]; ok {
+		return false
+	}
+	a, err1 := (&Response{Index: dealer, Response: first}).MarshalBinary()
+	b, err2 := (&Response{Index: dealer, Response: r}).MarshalBinary()
+	if err1 == nil && err2 == nil {
+		e.contradictions[dealer][r.Index] = Contradiction{First: a, Second: b}
+	}
+	return false
+}
+
+// sameResponse returns true if the two responses of a verifier are the same,
+// signature included.
+func sameResponse(a, b *vss.Response) bool {
+	return a.Index == b.Index && a.Status == b.Status &&
+		bytes.Equal(a.SessionID, b.SessionID) && bytes.Equal(a.Signature, b.Signature)
+}
+
+// failedJustification records a justification signed by its dealer which
+// reveals an invalid share.
+func (e *evidence) failedJustification(j *Justification) {
+	if e.justifications[j.Index] == nil {
+		e.justifications[j.Index] = make(map[uint32][]byte)
+	}
+	if _, ok := e.justifications[j.Index][j.Justification.Index]; ok {
+		return
+	}
+	if buff, err := j.MarshalBinary(); err == nil {
+		e.justifications[j.Index][j.Justification.Index] = buff
+	}
+}
+
+// justificationFailed returns true if the error of the verification of the
+// justification, of the verifier of the dealer, comes from the share it
+// reveals, i.e. if the justification is signed by the dealer and
+// disqualifies it.
+func justificationFailed(err error, j *vss.Justification, v *vss.Verifier) bool {
+	switch {
+	case errors.Is(err, vss.ErrInvalidDeal), errors.Is(err, vss.ErrDealOutOfIndex):
+		return true
+	case errors.Is(err, vss.ErrInvalidSessionID):
+		// the session ID of the justification itself is checked before its
+		// signature, and the one of its deal after
+		return bytes.Equal(j.SessionID, v.SessionID())
+	}
+	return false
+}
+
+// Report returns the evidence of the misbehaviors seen by this node so far,
+// for each dealer and each holder of a share: the deals rejected by this node
+// with the reason, the complaints issued and received, the justifications
+// revealing an invalid share, the contradicting responses, the missing
+// responses and whether the dealer is in the qualified set. It can be called
+// at any point of the protocol, e.g. after a failure, and the evidence can be
+// checked by a third party with MisbehaviorReport.Verify.
+//
+// The accounting is bounded: a node is reported at most once per deal for
+// each kind of misbehavior, however many times its messages are received.
+func (d *DistKeyGenerator) Report() *MisbehaviorReport {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	e := d.evidence
+	r := &MisbehaviorReport{
+		Dealers:   make([]DealerReport, len(d.c.OldNodes)),
+		Verifiers: make([]VerifierReport, len(d.holders)),
+		Nonce:     d.c.Nonce,
+	}
+	for i := range r.Dealers {
+		r.Dealers[i].Index = uint32(i)
+	}
+	for i := range r.Verifiers {
+		r.Verifiers[i].Index = uint32(i)
+	}
+	for _, i := range d.qualified() {
+		if i < len(r.Dealers) {
+			r.Dealers[i].InQUAL = true
+		}
+	}
+
+	for i := range r.Dealers {
+		dealer := &r.Dealers[i]
+		for reason := ReasonInvalidSignature; reason <= ReasonInconsistentShare; reason++ {
+			if buff, ok := e.rejections[dealer.Index][reason]; ok {
+				dealer.Rejections = append(dealer.Rejections, Rejection{Reason: reason, Deal: buff})
+			}
+		}
+		for v := range r.Verifiers {
+			resp, ok := e.responses[dealer.Index][uint32(v)]
+			if ok && resp.Status == vss.StatusComplaint {
+				buff, err := (&Response{Index: dealer.Index, Response: resp}).MarshalBinary()
+				if err == nil {
+					dealer.Complaints = append(dealer.Complaints, buff)
+					r.Verifiers[v].ComplaintsIssued = append(r.Verifiers[v].ComplaintsIssued, dealer.Index)
+				}
+			}
+			if buff, ok := e.justifications[dealer.Index][uint32(v)]; ok {
+				dealer.FailedJustifications = append(dealer.FailedJustifications, buff)
+			}
+			if c, ok := e.contradictions[dealer.Index][uint32(v)]; ok {
+				r.Verifiers[v].Contradictions = append(r.Verifiers[v].Contradictions, c)
+			}
+		}
+
+		// without the deal, the verifier cannot store any response
+		var agg *vss.Aggregator
+		if d.DealerOnly() {
+			agg = d.oldAggregators[dealer.Index]
+		} else if v, ok := d.verifiers[dealer.Index]; ok && v.SessionID() != nil {
+			agg = v.Aggregator
+		}
+		if agg == nil {
+			continue
+		}
+		for _, v := range agg.MissingResponses() {
+			r.Verifiers[v].MissingResponses = append(r.Verifiers[v].MissingResponses, dealer.Index)
+		}
+	}
+	return r
+}
+
+// Verify checks the evidence of the report against the public keys of the
+// dealers and of the holders of the shares, i.e. the new nodes each repeated
+// as many times as its weight, so that a third party does not have to trust
+// the node which made the report. It checks that:
+//   - the rejected deals and the failed justifications are signed by their
+//     dealer, and the complaints and the contradictions by their verifier,
+//   - the complaints are complaints about the deal of their dealer,
+//   - the failed justifications reveal a share that does not verify against
+//     their commitments, or commitments that do not match their session ID,
+//   - the contradictions are two responses of the same session with
+//     different statuses.
+//
+// It returns an error wrapping ErrInvalidEvidence for the first piece of
+// evidence which does not prove its misbehavior. The reasons of the rejected
+// deals, the missing responses and the qualified set cannot be checked this
+// way: they are the view of the node. The nonce is the one of the report: the
+// caller must check that it is the nonce of the run.
+func (r *MisbehaviorReport) Verify(suite Suite, dealers, holders []kyber.Point) error {
+	for _, dr := range r.Dealers {
+		dealer, ok := getPub(dealers, dr.Index)
+		if !ok {
+			return fmt.Errorf("%w: dealer %d out of bounds", ErrInvalidEvidence, dr.Index)
+		}
+		for _, rej := range dr.Rejections {
+			dd := &Deal{}
+			if err := dd.UnmarshalBinary(suite, rej.Deal); err != nil {
+				return fmt.Errorf("%w: deal of dealer %d: %v", ErrInvalidEvidence, dr.Index, err)
+			}
+			if dd.Index != dr.Index || schnorr.Verify(suite, dealer, dd.signatureMessage(), dd.Signature) != nil {
+				return fmt.Errorf("%w: deal not signed by dealer %d", ErrInvalidEvidence, dr.Index)
+			}
+		}
+		for _, buff := range dr.Complaints {
+			resp, err := verifyResponseEvidence(suite, holders, buff)
+			if err != nil {
+				return err
+			}
+			if resp.Index != dr.Index || resp.Response.Status != vss.StatusComplaint {
+				return fmt.Errorf("%w: not a complaint about dealer %d", ErrInvalidEvidence, dr.Index)
+			}
+		}
+		for _, buff := range dr.FailedJustifications {
+			j := &Justification{}
+			if err := j.UnmarshalBinary(suite, buff); err != nil {
+				return fmt.Errorf("%w: justification of dealer %d: %v", ErrInvalidEvidence, dr.Index, err)
+			}
+			if j.Index != dr.Index || schnorr.Verify(suite, dealer, j.Justification.Hash(suite), j.Justification.Signature) != nil {
+				return fmt.Errorf("%w: justification not signed by dealer %d", ErrInvalidEvidence, dr.Index)
+			}
+			if !invalidJustification(suite, dealer, holders, r.Nonce, j.Justification) {
+				return fmt.Errorf("%w: valid justification of dealer %d", ErrInvalidEvidence, dr.Index)
+			}
+		}
+	}
+
+	for _, vr := range r.Verifiers {
+		for _, c := range vr.Contradictions {
+			first, err := verifyResponseEvidence(suite, holders, c.First)
+			if err != nil {
+				return err
+			}
+			second, err := verifyResponseEvidence(suite, holders, c.Second)
+			if err != nil {
+				return err
+			}
+			if first.Index != second.Index || first.Response.Index != vr.Index || second.Response.Index != vr.Index ||
+				!bytes.Equal(first.Response.SessionID, second.Response.SessionID) ||
+				first.Response.Status == second.Response.Status {
+				return fmt.Errorf("%w: no contradiction of verifier %d", ErrInvalidEvidence, vr.Index)
+			}
+		}
+	}
+	return nil
+}
+
+// verifyResponseEvidence decodes the response and checks that it is signed by
+// its verifier.
+func verifyResponseEvidence(suite Suite, holders []kyber.Point, buff []byte) (*Response, error) {
+	resp := &Response{}
+	if err := resp.UnmarshalBinary(suite, buff); err != nil {
+		return nil, fmt.Errorf("%w: response: %v", ErrInvalidEvidence, err)
+	}
+	pub, ok := getPub(holders, resp.Response.Index)
+	if !ok || schnorr.Verify(suite, pub, resp.Response.Hash(suite), resp.Response.Signature) != nil {
+		return nil, fmt.Errorf("%w: response not signed by verifier %d", ErrInvalidEvidence, resp.Response.Index)
+	}
+	return resp, nil
+}
+
+// invalidJustification returns true if the deal revealed by the justification
+// is not the valid share of the complaining verifier.
+func invalidJustification(suite Suite, dealer kyber.Point, holders []kyber.Point, nonce []byte, j *vss.Justification) bool {
+	return invalidDeal(suite, dealer, holders, nonce, j.Deal, j.Index, j.SessionID)
+}
+
+// invalidDeal returns true if the deal is not the valid share of index i of
+// the session sid. The commitments are checked against the session ID only
+// for the deals without evaluation points, whose session ID does not depend
+// on the points of all the shares.
+func invalidDeal(suite Suite, dealer kyber.Point, holders []kyber.Point, nonce []byte, d *vss.Deal, i uint32, sid []byte) bool {
+	if d.SecShare.I < 0 || uint32(d.SecShare.I) != i || int(i) >= len(holders) ||
+		int(d.T) != len(d.Commitments) || !bytes.Equal(d.SessionID, sid) {
+		return true
+	}
+	poly := share.NewPubPoly(suite, nil, d.Commitments)
+	var pubShare kyber.Point
+	if d.X != nil {
+		pubShare = poly.EvalScalar(d.X).V
+	} else {
+		expected, err := vss.SessionIDWithNonce(suite, dealer, holders, d.Commitments, int(d.T), nonce)
+		if err != nil || !bytes.Equal(expected, sid) {
+			return true
+		}
+		pubShare = poly.Eval(d.SecShare.I).V
+	}
+	return !suite.Point().Mul(d.SecShare.V, nil).Equal(pubShare)
+}
+
+// Accusation is the proof that a dealer dealt an invalid share, which a third
+// party can check with VerifyAccusation without trusting the accuser, e.g. to
+// slash the dealer on a ledger. It is returned by DistKeyGenerator.Accuse. The
+// deal is signed by the dealer, and the accuser reveals the Diffie-Hellman key
+// of its encryption along with a proof of the key (see vss.DecryptionProof),
+// so that anybody can decrypt the deal and see that the share does not verify
+// against the commitments of the deal. The other deals, and the longterm key
+// of the accuser, remain secret. Like MisbehaviorReport, it can be serialized
+// with encoding/json.
+type Accusation struct {
+	// Dealer is the index of the accused dealer.
+	Dealer uint32 `json:"dealer"`
+	// Deal is the deal of the dealer for the accuser, in its canonical
+	// encoding.
+	Deal []byte `json:"deal"`
+	// Key is the Diffie-Hellman key of the encryption of the deal.
+	Key []byte `json:"key"`
+	// Proof is the DLEQ proof of the key: the challenge, the response, and
+	// the commitments to the base point and to the ephemeral key of the deal.
+	Proof [][]byte `json:"proof"`
+	// Index is the index of the share of the accuser, whose evaluation point
+	// is Index+1, or the evaluation point of the deal with
+	// Config.UseHashedIndices.
+	Index uint32 `json:"index"`
+	// Share is the share decrypted from the deal, which does not verify
+	// against the commitments of the deal at the evaluation point.
+	Share []byte `json:"share"`
+	// Nonce is the nonce of the run, see Config.Nonce.
+	Nonce []byte `json:"nonce,omitempty"`
+}
+
+var errNoAccusation = errors.New("dkg: no invalid share to accuse the dealer of")
+
+// Accuse returns the proof that the dealer of the given index dealt an invalid
+// share to this node, once ProcessDeal answered its deal with a complaint. It
+// returns an error if the node did not process any deal of the dealer, and
+// one wrapping errNoAccusation if the share is valid, e.g. when the node
+// complained for a reason which cannot be proven to a third party. It is not
+// supported with Config.Weights. Accuse can be called concurrently with the
+// other methods.
+func (d *DistKeyGenerator) Accuse(dealer uint32) (*Accusation, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.c.Weights != nil {
+		return nil, errors.New("dkg: no accusation with weights")
+	}
+	p, ok := d.processedDeals[dealer]
+	v, okv := d.verifiers[dealer]
+	pub, okp := getPub(d.c.OldNodes, dealer)
+	if !ok || !okv || !okp {
+		return nil, fmt.Errorf("dkg: no deal of dealer %s", d.dealerID(dealer))
+	}
+	dd := &Deal{}
+	if err := dd.UnmarshalBinary(d.suite, p.deal); err != nil {
+		return nil, err
+	}
+	proof, err := v.ProveDecryption(dd.Deal)
+	if err != nil {
+		return nil, fmt.Errorf("dkg: deal of dealer %s: %w", d.dealerID(dealer), err)
+	}
+	deal, err := vss.DecryptDeal(d.suite, pub, d.holders, dd.Deal, proof)
+	if err != nil {
+		return nil, fmt.Errorf("dkg: deal of dealer %s: %w", d.dealerID(dealer), err)
+	}
+	defer deal.SecShare.V.Zero()
+	if !invalidDeal(d.suite, pub, d.holders, d.c.Nonce, deal, dd.Deal.Index, dd.Deal.SessionID) {
+		return nil, fmt.Errorf("%w: valid share of dealer %s", errNoAccusation, d.dealerID(dealer))
+	}
+	a := &Accusation{
+		Dealer: dealer,
+		Deal:   p.deal,
+		Index:  dd.Deal.Index,
+		Nonce:  d.c.Nonce,
+	}
+	if a.Key, a.Proof, err = marshalDecryptionProof(proof); err != nil {
+		return nil, err
+	}
+	if a.Share, err = deal.SecShare.V.MarshalBinary(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// VerifyAccusation checks the accusation against the public keys of the
+// dealers and of the holders of the shares, see MisbehaviorReport.Verify. It
+// checks that the deal is signed by the dealer, that the key is the one of the
+// deal of the holder of the share, that the deal decrypts to the share of the
+// accusation, and that the share does not verify against the commitments of
+// the deal, or that the commitments do not match the session ID of the deal.
+// It returns an error wrapping ErrInvalidEvidence for the first check which
+// fails, and nil if the dealer is to blame. The nonce is the one of the
+// accusation: the caller must check that it is the nonce of the run.
+func VerifyAccusation(suite Suite, dealers, holders []kyber.Point, a *Accusation) error {
+	dd := &Deal{}
+	if err := dd.UnmarshalBinary(suite, a.Deal); err != nil {
+		return fmt.Errorf("%w: deal of dealer %d: %v", ErrInvalidEvidence, a.Dealer, err)
+	}
+	dealer, ok := getPub(dealers, a.Dealer)
+	if !ok || dd.Index != a.Dealer || schnorr.Verify(suite, dealer, dd.signatureMessage(), dd.Signature) != nil {
+		return fmt.Errorf("%w: deal not signed by dealer %d", ErrInvalidEvidence, a.Dealer)
+	}
+	if dd.Deal.Index != a.Index {
+		return fmt.Errorf("%w: deal for the share %d", ErrInvalidEvidence, dd.Deal.Index)
+	}
+	proof, err := unmarshalDecryptionProof(suite, a.Key, a.Proof)
+	if err != nil {
+		return fmt.Errorf("%w: decryption proof: %v", ErrInvalidEvidence, err)
+	}
+	deal, err := vss.DecryptDeal(suite, dealer, holders, dd.Deal, proof)
+	if err != nil {
+		return fmt.Errorf("%w: deal of dealer %d: %v", ErrInvalidEvidence, a.Dealer, err)
+	}
+	v := suite.Scalar()
+	if err := v.UnmarshalBinary(a.Share); err != nil || !v.Equal(deal.SecShare.V) {
+		return fmt.Errorf("%w: share not decrypted from the deal", ErrInvalidEvidence)
+	}
+	if !invalidDeal(suite, dealer, holders, a.Nonce, deal, a.Index, dd.Deal.SessionID) {
+		return fmt.Errorf("%w: valid share of dealer %d", ErrInvalidEvidence, a.Dealer)
+	}
+	return nil
+}
+
+// marshalDecryptionProof returns the encoding of the key and of the DLEQ
+// proof of the decryption proof, see Accusation.
+func marshalDecryptionProof(p *vss.DecryptionProof) ([]byte, [][]byte, error) {
+	key, err := p.Key.MarshalBinary()
+	if err != nil {
+		return nil, nil, err
+	}
+	parts := []encoding.BinaryMarshaler{p.Proof.C, p.Proof.R, p.Proof.VG, p.Proof.VH}
+	proof := make([][]byte, len(parts))
+	for i, part := range parts {
+		if proof[i], err = part.MarshalBinary(); err != nil {
+			return nil, nil, err
+		}
+	}
+	return key, proof, nil
+}
+
+// unmarshalDecryptionProof decodes a proof encoded by marshalDecryptionProof.
+func unmarshalDecryptionProof(suite Suite, key []byte, proof [][]byte) (*vss.DecryptionProof, error) {
+	p := &vss.DecryptionProof{
+		Key: suite.Point(),
+		Proof: &dleq.Proof{
+			C:  suite.Scalar(),
+			R:  suite.Scalar(),
+			VG: suite.Point(),
+			VH: suite.Point(),
+		},
+	}
+	if err := p.Key.UnmarshalBinary(key); err != nil {
+		return nil, err
+	}
+	parts := []encoding.BinaryUnmarshaler{p.Proof.C, p.Proof.R, p.Proof.VG, p.Proof.VH}
+	if len(proof) != len(parts) {
+		return nil, fmt.Errorf("%d parts instead of %d", len(proof), len(parts))
+	}
+	for i, part := range parts {
+		if err := part.UnmarshalBinary(proof[i]); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
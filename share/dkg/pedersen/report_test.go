@@ -0,0 +1,261 @@
+package dkg
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/share"
+	vss "go.dedis.ch/kyber/v3/share/vss/pedersen"
+	"go.dedis.ch/kyber/v3/sign/schnorr"
+)
+
+// TestDKGReport checks that the report pins an inconsistent deal, sent by the
+// dealer to a single verifier, on the dealer, with evidence that a third party
+// can verify.
+func TestDKGReport(t *testing.T) {
+	pubs, secs, dkgs := generate(defaultN, defaultT)
+	const dealer, victim, witness = 0, 1, 2
+
+	// the victim receives a share inconsistent with the commitments
+	bad := suite.Scalar().Pick(suite.RandomStream())
+	badDeal := malformedDeal(t, dkgs, func(d *vss.Deal) { d.SecShare.V = bad })
+
+	var resps []*Response
+	for i, d := range dkgs {
+		deals, err := d.Deals()
+		require.NoError(t, err)
+		for j, deal := range deals {
+			if i == dealer && j == victim {
+				deal = badDeal
+			}
+			resp, err := dkgs[j].ProcessDeal(deal)
+			require.NoError(t, err)
+			require.Equal(t, i != dealer || j != victim, resp.Response.Status)
+			resps = append(resps, resp)
+		}
+	}
+
+	// before the responses are broadcast, the witness misses the ones of the
+	// victim, but about the own deal of the victim
+	report := dkgs[witness].Report()
+	require.Len(t, report.Dealers, defaultN)
+	require.Len(t, report.Verifiers, defaultN)
+	require.Equal(t, []uint32{0, 2, 3, 4}, report.Verifiers[victim].MissingResponses)
+	require.Empty(t, report.Verifiers[witness].MissingResponses)
+
+	for _, resp := range resps {
+		for i, d := range dkgs {
+			if resp.Response.Index == uint32(i) {
+				continue
+			}
+			_, err := d.ProcessResponse(transmitResponse(t, resp))
+			require.NoError(t, err)
+		}
+	}
+
+	// the dealer justifies the complaint with the share it sent
+	plain, err := dkgs[dealer].dealer.PlaintextDeal(victim)
+	require.NoError(t, err)
+	revealed := *plain
+	revealed.SecShare = &share.PriShare{I: victim, V: bad}
+	j := &Justification{
+		Index: dealer,
+		Justification: &vss.Justification{
+			SessionID: plain.SessionID,
+			Index:     victim,
+			Deal:      &revealed,
+		},
+	}
+	j.Justification.Signature, err = schnorr.Sign(suite, secs[dealer], j.Justification.Hash(suite))
+	require.NoError(t, err)
+	for i, d := range dkgs {
+		if i == dealer {
+			continue
+		}
+		require.True(t, errors.Is(d.ProcessJustification(transmitJustification(t, j)), vss.ErrInvalidDeal))
+	}
+
+	for _, i := range []int{victim, witness} {
+		report := dkgs[i].Report()
+		for _, dr := range report.Dealers {
+			require.Equal(t, dr.Index != dealer, dr.InQUAL)
+			if dr.Index != dealer {
+				require.Empty(t, dr.Complaints)
+				require.Empty(t, dr.FailedJustifications)
+				require.Empty(t, dr.Rejections)
+			}
+		}
+		dr := report.Dealers[dealer]
+		require.Len(t, dr.Complaints, 1)
+		complaint := &Response{}
+		require.NoError(t, complaint.UnmarshalBinary(suite, dr.Complaints[0]))
+		require.Equal(t, uint32(victim), complaint.Response.Index)
+		require.Equal(t, vss.StatusComplaint, complaint.Response.Status)
+		require.Len(t, dr.FailedJustifications, 1)
+		for _, vr := range report.Verifiers {
+			if vr.Index == victim {
+				require.Equal(t, []uint32{dealer}, vr.ComplaintsIssued)
+			} else {
+				require.Empty(t, vr.ComplaintsIssued)
+			}
+			require.Empty(t, vr.MissingResponses)
+			require.Empty(t, vr.Contradictions)
+		}
+		require.NoError(t, report.Verify(suite, pubs, pubs))
+	}
+	// only the victim could decrypt the deal
+	require.Empty(t, dkgs[witness].Report().Dealers[dealer].Rejections)
+	rejections := dkgs[victim].Report().Dealers[dealer].Rejections
+	require.Len(t, rejections, 1)
+	require.Equal(t, ReasonInvalidShare, rejections[0].Reason)
+	require.Equal(t, "invalid share", rejections[0].Reason.String())
+
+	// the report goes through JSON and is checked by a third party
+	buff, err := json.Marshal(dkgs[victim].Report())
+	require.NoError(t, err)
+	var decoded MisbehaviorReport
+	require.NoError(t, json.Unmarshal(buff, &decoded))
+	require.Equal(t, dkgs[victim].Report(), &decoded)
+	require.NoError(t, decoded.Verify(suite, pubs, pubs))
+
+	// the evidence cannot be pinned on another node
+	require.True(t, errors.Is(decoded.Verify(suite, pubs[1:], pubs), ErrInvalidEvidence))
+	decoded.Dealers[3].Complaints = decoded.Dealers[dealer].Complaints
+	require.True(t, errors.Is(decoded.Verify(suite, pubs, pubs), ErrInvalidEvidence))
+	decoded.Dealers[3].Complaints = nil
+	decoded.Dealers[dealer].Rejections[0].Deal[len(decoded.Dealers[dealer].Rejections[0].Deal)-1] ^= 1
+	require.True(t, errors.Is(decoded.Verify(suite, pubs, pubs), ErrInvalidEvidence))
+
+	// a valid justification does not incriminate the dealer
+	report = dkgs[witness].Report()
+	honest := *j.Justification
+	honest.Deal = plain
+	honest.Signature, err = schnorr.Sign(suite, secs[dealer], honest.Hash(suite))
+	require.NoError(t, err)
+	report.Dealers[dealer].FailedJustifications[0], err = (&Justification{Index: dealer, Justification: &honest}).MarshalBinary()
+	require.NoError(t, err)
+	require.True(t, errors.Is(report.Verify(suite, pubs, pubs), ErrInvalidEvidence))
+}
+
+// TestDKGReportContradiction checks that a verifier approving and complaining
+// about the same deal is reported once, however many times it does so.
+func TestDKGReportContradiction(t *testing.T) {
+	pubs, secs, dkgs := generate(defaultN, defaultT)
+	const dealer, liar, witness = 3, 2, 4
+
+	var approval *Response
+	for i, d := range dkgs {
+		deals, err := d.Deals()
+		require.NoError(t, err)
+		for j, deal := range deals {
+			resp, err := dkgs[j].ProcessDeal(deal)
+			require.NoError(t, err)
+			if i == dealer && j == liar {
+				approval = resp
+			}
+		}
+	}
+	_, err := dkgs[witness].ProcessResponse(transmitResponse(t, approval))
+	require.NoError(t, err)
+
+	complaint := transmitResponse(t, approval)
+	complaint.Response.Status = vss.StatusComplaint
+	complaint.Response.Signature, err = schnorr.Sign(suite, secs[liar], complaint.Response.Hash(suite))
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		j, err := dkgs[witness].ProcessResponse(transmitResponse(t, complaint))
+		require.True(t, errors.Is(err, vss.ErrDuplicateResponse))
+		require.Nil(t, j)
+	}
+	// a forged complaint is not evidence
+	forged := transmitResponse(t, complaint)
+	forged.Response.Index = 1
+	_, err = dkgs[witness].ProcessResponse(forged)
+	require.True(t, errors.Is(err, vss.ErrInvalidSignature))
+
+	report := dkgs[witness].Report()
+	for _, vr := range report.Verifiers {
+		if vr.Index != liar {
+			require.Empty(t, vr.Contradictions)
+		}
+	}
+	contradictions := report.Verifiers[liar].Contradictions
+	require.Len(t, contradictions, 1)
+	require.Empty(t, report.Verifiers[liar].ComplaintsIssued)
+	require.NoError(t, report.Verify(suite, pubs, pubs))
+
+	contradictions[0].Second = contradictions[0].First
+	require.True(t, errors.Is(report.Verify(suite, pubs, pubs), ErrInvalidEvidence))
+}
+
+// TestDKGAccusation checks that a node which received an invalid share can
+// prove it to a third party, and only then.
+func TestDKGAccusation(t *testing.T) {
+	pubs, _, dkgs := generate(defaultN, defaultT)
+	const dealer, accuser, other = 0, 1, 2
+	deals, err := dkgs[dealer].Deals()
+	require.NoError(t, err)
+
+	bad := malformedDeal(t, dkgs, func(d *vss.Deal) { d.SecShare.V = suite.Scalar().Pick(suite.RandomStream()) })
+	resp, err := dkgs[accuser].ProcessDeal(bad)
+	require.NoError(t, err)
+	require.Equal(t, vss.StatusComplaint, resp.Response.Status)
+	a, err := dkgs[accuser].Accuse(dealer)
+	require.NoError(t, err)
+	require.Equal(t, uint32(dealer), a.Dealer)
+	require.Equal(t, uint32(accuser), a.Index)
+	require.NoError(t, VerifyAccusation(suite, pubs, pubs, a))
+
+	buff, err := json.Marshal(a)
+	require.NoError(t, err)
+	decoded := &Accusation{}
+	require.NoError(t, json.Unmarshal(buff, decoded))
+	require.NoError(t, VerifyAccusation(suite, pubs, pubs, decoded))
+
+	tamper := func(f func(a *Accusation)) error {
+		c := *a
+		c.Proof = append([][]byte(nil), a.Proof...)
+		f(&c)
+		return VerifyAccusation(suite, pubs, pubs, &c)
+	}
+	otherKey, err := suite.Point().Pick(suite.RandomStream()).MarshalBinary()
+	require.NoError(t, err)
+	cases := map[string]func(a *Accusation){
+		"other dealer": func(a *Accusation) { a.Dealer = other },
+		"other index":  func(a *Accusation) { a.Index = other },
+		"other share":  func(a *Accusation) { a.Share = a.Proof[0] },
+		"other key":    func(a *Accusation) { a.Key = otherKey },
+		"short proof":  func(a *Accusation) { a.Proof = a.Proof[1:] },
+		"forged deal": func(a *Accusation) {
+			a.Deal = append([]byte(nil), a.Deal...)
+			a.Deal[len(a.Deal)-1] ^= 1
+		},
+	}
+	for name, f := range cases {
+		require.True(t, errors.Is(tamper(f), ErrInvalidEvidence), name)
+	}
+
+	// a valid share cannot be turned into an accusation
+	_, err = dkgs[other].ProcessDeal(deals[other])
+	require.NoError(t, err)
+	_, err = dkgs[other].Accuse(dealer)
+	require.True(t, errors.Is(err, errNoAccusation))
+	_, err = dkgs[other].Accuse(3)
+	require.Error(t, err)
+
+	dd := &Deal{}
+	p := dkgs[other].processedDeals[dealer]
+	require.NoError(t, dd.UnmarshalBinary(suite, p.deal))
+	proof, err := dkgs[other].verifiers[dealer].ProveDecryption(dd.Deal)
+	require.NoError(t, err)
+	forged := &Accusation{Dealer: dealer, Deal: p.deal, Index: other}
+	forged.Key, forged.Proof, err = marshalDecryptionProof(proof)
+	require.NoError(t, err)
+	deal, err := vss.DecryptDeal(suite, pubs[dealer], pubs, dd.Deal, proof)
+	require.NoError(t, err)
+	forged.Share, err = deal.SecShare.V.MarshalBinary()
+	require.NoError(t, err)
+	require.True(t, errors.Is(VerifyAccusation(suite, pubs, pubs, forged), ErrInvalidEvidence))
+}
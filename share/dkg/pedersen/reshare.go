@@ -0,0 +1,128 @@
+package dkg
+
+import (
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+)
+
+// Resharing drives the resharing of a distributed key from a group of old
+// nodes to a group of new nodes, which may differ in size and threshold, some
+// old nodes leaving and some new nodes joining. It is a DistKeyGenerator
+// created from a configuration checked by Reshare, which also tracks the
+// deals of the old nodes: every old node deals, and a new node needs the
+// deals of at least the old threshold of them, see MissingDeals and
+// EnoughDeals.
+type Resharing struct {
+	*DistKeyGenerator
+	oldNodes []kyber.Point
+	newNodes []kyber.Point
+}
+
+// Reshare returns the Resharing of the distributed key of oldShare from the
+// old nodes to the new nodes, with the threshold newT, or vss.MinimumT of the
+// new nodes if newT is 0. The old threshold is the number of commitments of
+// oldShare.
+//
+// An old node gives its DistKeyShare. A new node, which holds no share, gives
+// a DistKeyShare holding only the commitments of the distributed key, e.g.
+// &DistKeyShare{Commits: commits}, which all the nodes must agree on. It
+// returns an error if the node is in none of the lists, if an old node has
+// no share, or if the configuration is invalid (see NewDistKeyHandler).
+func Reshare(suite Suite, longterm kyber.Scalar, oldShare *DistKeyShare, oldNodes, newNodes []kyber.Point, newT int) (*Resharing, error) {
+	if oldShare == nil || len(oldShare.Commits) == 0 {
+		return nil, fmt.Errorf("%w: no commitments of the distributed key", ErrInconsistentShare)
+	}
+	if suite == nil {
+		return nil, ErrNilSuite
+	}
+	if longterm == nil {
+		return nil, ErrNilLongterm
+	}
+	c := &Config{
+		Suite:        suite,
+		Longterm:     longterm,
+		OldNodes:     oldNodes,
+		NewNodes:     newNodes,
+		Threshold:    newT,
+		OldThreshold: len(oldShare.Commits),
+	}
+	if oldShare.Share != nil {
+		c.Share = oldShare
+	} else {
+		if _, old := findPub(oldNodes, suite.Point().Mul(longterm, nil)); old {
+			return nil, fmt.Errorf("%w: old node without its share", ErrInconsistentShare)
+		}
+		c.PublicCoeffs = oldShare.Commits
+	}
+	d, err := NewDistKeyHandler(c)
+	if err != nil {
+		return nil, err
+	}
+	return &Resharing{
+		DistKeyGenerator: d,
+		oldNodes:         oldNodes,
+		newNodes:         newNodes,
+	}, nil
+}
+
+// OldThreshold returns the threshold of the distributed key, i.e. the number
+// of old nodes whose deals are needed.
+func (r *Resharing) OldThreshold() int {
+	return r.c.OldThreshold
+}
+
+// Leaving returns the indices in the list of old nodes of the nodes which are
+// not in the list of new nodes. They deal, but do not receive a new share.
+func (r *Resharing) Leaving() []int {
+	var leaving []int
+	for i, p := range r.oldNodes {
+		if _, ok := findPub(r.newNodes, p); !ok {
+			leaving = append(leaving, i)
+		}
+	}
+	return leaving
+}
+
+// Joining returns the indices in the list of new nodes of the nodes which are
+// not in the list of old nodes. They receive a share, but do not deal.
+func (r *Resharing) Joining() []int {
+	var joining []int
+	for i, p := range r.newNodes {
+		if _, ok := findPub(r.oldNodes, p); !ok {
+			joining = append(joining, i)
+		}
+	}
+	return joining
+}
+
+// MissingDeals returns the indices in the list of old nodes of the dealers
+// whose deal this node has not processed yet, its own one included. It
+// returns nil for a node which only deals (see DealerOnly), since it receives
+// no deal.
+func (r *Resharing) MissingDeals() []int {
+	if r.DealerOnly() {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var missing []int
+	for i := range r.oldNodes {
+		if _, ok := r.processedDeals[uint32(i)]; !ok {
+			missing = append(missing, i)
+		}
+	}
+	return missing
+}
+
+// EnoughDeals returns true once this node has processed the deals of at
+// least the old threshold of old nodes, so that it may compute its new share
+// if they are certified, see ThresholdCertified. The other deals may still
+// arrive until the end of the protocol. It returns false for a node which
+// only deals.
+func (r *Resharing) EnoughDeals() bool {
+	if r.DealerOnly() {
+		return false
+	}
+	return len(r.oldNodes)-len(r.MissingDeals()) >= r.c.OldThreshold
+}
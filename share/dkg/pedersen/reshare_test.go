@@ -0,0 +1,129 @@
+package dkg
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/share"
+	vss "go.dedis.ch/kyber/v3/share/vss/pedersen"
+)
+
+func TestReshare(t *testing.T) {
+	oldPubs, oldPrivs, dkgs := generate(defaultN, vss.MinimumT(defaultN))
+	fullExchange(t, dkgs, true)
+	shares := make([]*DistKeyShare, defaultN)
+	for i, d := range dkgs {
+		dks, err := d.DistKeyShare()
+		require.NoError(t, err)
+		shares[i] = dks
+	}
+	commits := shares[0].Commits
+	oldT := len(commits)
+
+	// the old nodes 0 to 2 leave, the other ones stay, and three nodes join
+	// a larger group with a higher threshold
+	newN := defaultN
+	newT := oldT + 1
+	newPubs := append([]kyber.Point{}, oldPubs[3:]...)
+	newPrivs := append([]kyber.Scalar{}, oldPrivs[3:]...)
+	for len(newPubs) < newN {
+		priv, pub := genPair()
+		newPubs = append(newPubs, pub)
+		newPrivs = append(newPrivs, priv)
+	}
+
+	olds := make([]*Resharing, defaultN)
+	for i := range olds {
+		r, err := Reshare(suite, oldPrivs[i], shares[i], oldPubs, newPubs, newT)
+		require.NoError(t, err)
+		require.Equal(t, oldT, r.OldThreshold())
+		olds[i] = r
+	}
+	news := make([]*Resharing, newN)
+	for i := range news {
+		if i < defaultN-3 {
+			news[i] = olds[i+3]
+			continue
+		}
+		r, err := Reshare(suite, newPrivs[i], &DistKeyShare{Commits: commits}, oldPubs, newPubs, newT)
+		require.NoError(t, err)
+		news[i] = r
+	}
+	require.Equal(t, []int{0, 1, 2}, news[0].Leaving())
+	require.Equal(t, []int{newN - 3, newN - 2, newN - 1}, news[0].Joining())
+	require.True(t, olds[0].DealerOnly())
+	require.Nil(t, olds[0].MissingDeals())
+	require.False(t, olds[0].EnoughDeals())
+
+	var resps []*Response
+	for i, old := range olds {
+		deals, err := old.Deals()
+		require.NoError(t, err)
+		for j, dd := range deals {
+			resp, err := news[j].ProcessDeal(dd)
+			require.NoError(t, err)
+			resps = append(resps, resp)
+		}
+		// the new nodes need the deals of oldT old nodes
+		for _, n := range news {
+			require.Equal(t, i+1 >= oldT, n.EnoughDeals())
+			if i < defaultN-1 {
+				require.Equal(t, i+1, n.MissingDeals()[0])
+			}
+		}
+	}
+	for _, n := range news {
+		require.Empty(t, n.MissingDeals())
+	}
+
+	for _, resp := range resps {
+		for _, old := range olds[:3] {
+			_, err := old.ProcessResponse(resp)
+			require.NoError(t, err)
+		}
+		for i, n := range news {
+			if resp.Response.Index == uint32(i) {
+				continue
+			}
+			_, err := n.ProcessResponse(resp)
+			require.NoError(t, err)
+		}
+	}
+
+	newShares := make([]*share.PriShare, newN)
+	for i, n := range news {
+		require.True(t, n.Certified())
+		dks, err := n.DistKeyShare()
+		require.NoError(t, err)
+		require.Len(t, dks.Commits, newT)
+		require.True(t, dks.Public().Equal(shares[0].Public()))
+		newShares[i] = dks.Share
+	}
+	secret, err := share.RecoverSecret(suite, newShares, newT, newN)
+	require.NoError(t, err)
+	require.True(t, suite.Point().Mul(secret, nil).Equal(shares[0].Public()))
+}
+
+func TestReshareInvalid(t *testing.T) {
+	oldPubs, oldPrivs, dkgs := generate(defaultN, vss.MinimumT(defaultN))
+	fullExchange(t, dkgs, true)
+	dks, err := dkgs[0].DistKeyShare()
+	require.NoError(t, err)
+
+	_, err = Reshare(suite, oldPrivs[0], nil, oldPubs, oldPubs, 0)
+	require.True(t, errors.Is(err, ErrInconsistentShare))
+	// an old node must deal with its share
+	_, err = Reshare(suite, oldPrivs[0], &DistKeyShare{Commits: dks.Commits}, oldPubs, oldPubs, 0)
+	require.True(t, errors.Is(err, ErrInconsistentShare))
+	// there are fewer old nodes than the threshold
+	_, err = Reshare(suite, oldPrivs[0], dks, oldPubs[:1], oldPubs, 0)
+	require.Error(t, err)
+
+	r, err := Reshare(suite, oldPrivs[0], dks, oldPubs, oldPubs, 0)
+	require.NoError(t, err)
+	require.Empty(t, r.Leaving())
+	require.Empty(t, r.Joining())
+	require.Equal(t, vss.MinimumT(defaultN), r.newT)
+}
@@ -0,0 +1,199 @@
+package dkg
+
+import (
+	"crypto/cipher"
+	"errors"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/share"
+)
+
+var errSimulationInput = errors.New("dkg: invalid simulation parameters")
+var errSimulationCertified = errors.New("dkg: simulated protocol did not certify")
+
+// SimulateDKG runs a complete DKG between n nodes holding fresh longterm keys,
+// in-process and without any network, and returns the public polynomial of
+// the distributed key together with the private shares of the nodes, ordered
+// by index. It is meant for tests and simulations which need a threshold t
+// sharing of a distributed key: it runs the real DistKeyGenerator, so that the
+// shares are the ones the protocol would produce.
+//
+// If seed is not nil, all the randomness is derived from it and the outcome is
+// deterministic. Such a sharing provides no security at all, as anyone
+// knowing the seed can recompute the shares.
+func SimulateDKG(suite Suite, n, t int, seed []byte) (*share.PubPoly, []*share.PriShare, error) {
+	if n < 2 || t < 2 || t > n {
+		return nil, nil, errSimulationInput
+	}
+	s, reader := simulationSuite(suite, seed)
+	privs, pubs := simulationKeys(s, n)
+
+	dkgs := make([]*DistKeyGenerator, n)
+	for i := range dkgs {
+		d, err := NewDistKeyHandler(&Config{
+			Suite:          s,
+			Longterm:       privs[i],
+			NewNodes:       pubs,
+			Threshold:      t,
+			Reader:         reader,
+			UserReaderOnly: reader != nil,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		dkgs[i] = d
+	}
+	if err := simulateExchange(dkgs, nil, dkgs); err != nil {
+		return nil, nil, err
+	}
+	return simulationShares(suite, dkgs)
+}
+
+// SimulateReshare runs a complete resharing of the distributed key having the
+// public polynomial pub in-process, like SimulateDKG does for a fresh DKG.
+// The shares must be the ones of all the current nodes, which are given fresh
+// longterm keys. The returned public polynomial and shares are the ones of
+// newN new nodes, for the new threshold newT, and share the same distributed
+// key.
+func SimulateReshare(suite Suite, pub *share.PubPoly, shares []*share.PriShare, newN, newT int, seed []byte) (*share.PubPoly, []*share.PriShare, error) {
+	if pub == nil || newN < 2 || newT < 2 || newT > newN {
+		return nil, nil, errSimulationInput
+	}
+	_, commits := pub.Info()
+	oldN := len(shares)
+	oldT := len(commits)
+	ordered := make([]*share.PriShare, oldN)
+	for _, sh := range shares {
+		if sh == nil || sh.I < 0 || sh.I >= oldN || ordered[sh.I] != nil {
+			return nil, nil, errSimulationInput
+		}
+		ordered[sh.I] = sh
+	}
+
+	s, _ := simulationSuite(suite, seed)
+	oldPrivs, oldPubs := simulationKeys(s, oldN)
+	newPrivs, newPubs := simulationKeys(s, newN)
+
+	oldDkgs := make([]*DistKeyGenerator, oldN)
+	for i := range oldDkgs {
+		d, err := NewDistKeyHandler(&Config{
+			Suite:        s,
+			Longterm:     oldPrivs[i],
+			OldNodes:     oldPubs,
+			NewNodes:     newPubs,
+			Share:        &DistKeyShare{Commits: commits, Share: ordered[i]},
+			Threshold:    newT,
+			OldThreshold: oldT,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		oldDkgs[i] = d
+	}
+	newDkgs := make([]*DistKeyGenerator, newN)
+	for i := range newDkgs {
+		d, err := NewDistKeyHandler(&Config{
+			Suite:        s,
+			Longterm:     newPrivs[i],
+			OldNodes:     oldPubs,
+			NewNodes:     newPubs,
+			PublicCoeffs: commits,
+			Threshold:    newT,
+			OldThreshold: oldT,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		newDkgs[i] = d
+	}
+	if err := simulateExchange(oldDkgs, oldDkgs, newDkgs); err != nil {
+		return nil, nil, err
+	}
+	return simulationShares(suite, newDkgs)
+}
+
+// simulateExchange delivers the deals of the dealers to the receivers, and the
+// resulting responses to the receivers and to the old nodes, in a fixed order.
+func simulateExchange(dealers, old, receivers []*DistKeyGenerator) error {
+	var resps []*Response
+	for _, d := range dealers {
+		deals, err := d.Deals()
+		if err != nil {
+			return err
+		}
+		for i := range receivers {
+			deal, ok := deals[i]
+			if !ok {
+				continue
+			}
+			resp, err := receivers[i].ProcessDeal(deal)
+			if err != nil {
+				return err
+			}
+			resps = append(resps, resp)
+		}
+	}
+	for _, resp := range resps {
+		for _, d := range old {
+			if _, err := d.ProcessResponse(resp); err != nil {
+				return err
+			}
+		}
+		for i, d := range receivers {
+			if uint32(i) == resp.Response.Index {
+				continue
+			}
+			if _, err := d.ProcessResponse(resp); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func simulationShares(suite Suite, dkgs []*DistKeyGenerator) (*share.PubPoly, []*share.PriShare, error) {
+	shares := make([]*share.PriShare, len(dkgs))
+	var commits []kyber.Point
+	for i, d := range dkgs {
+		if !d.Certified() {
+			return nil, nil, errSimulationCertified
+		}
+		dks, err := d.DistKeyShare()
+		if err != nil {
+			return nil, nil, err
+		}
+		shares[i] = dks.PriShare()
+		commits = dks.Commitments()
+	}
+	return share.NewPubPoly(suite, suite.Point().Base(), commits), shares, nil
+}
+
+func simulationKeys(suite Suite, n int) ([]kyber.Scalar, []kyber.Point) {
+	privs := make([]kyber.Scalar, n)
+	pubs := make([]kyber.Point, n)
+	for i := range privs {
+		privs[i] = suite.Scalar().Pick(suite.RandomStream())
+		pubs[i] = suite.Point().Mul(privs[i], nil)
+	}
+	return privs, pubs
+}
+
+// seededSuite draws all its randomness from a single deterministic stream.
+type seededSuite struct {
+	Suite
+	stream cipher.Stream
+}
+
+func (s *seededSuite) RandomStream() cipher.Stream {
+	return s.stream
+}
+
+// simulationSuite returns the suite to run a simulation with and, if seed is
+// not nil, the reader to pick the secrets of the dealers from.
+func simulationSuite(suite Suite, seed []byte) (Suite, kyber.XOF) {
+	if seed == nil {
+		return suite, nil
+	}
+	x := suite.XOF(seed)
+	return &seededSuite{Suite: suite, stream: x}, x
+}
@@ -0,0 +1,90 @@
+package dkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/share"
+	"go.dedis.ch/kyber/v3/sign/bls"
+	"go.dedis.ch/kyber/v3/sign/tbls"
+)
+
+func TestSimulateDKG(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	n, thr := 5, 3
+	pub, shares, err := SimulateDKG(suite, n, thr, nil)
+	require.NoError(t, err)
+	require.Len(t, shares, n)
+	for i, s := range shares {
+		require.Equal(t, i, s.I)
+		require.True(t, pub.Check(s))
+	}
+
+	msg := []byte("Hello simulated DKG")
+	var sigs [][]byte
+	for _, s := range shares[1 : thr+1] {
+		sig, err := tbls.Sign(suite, s, msg)
+		require.NoError(t, err)
+		sigs = append(sigs, sig)
+	}
+	sig, err := tbls.Recover(suite, pub, msg, sigs, thr, n)
+	require.NoError(t, err)
+	require.NoError(t, bls.Verify(suite, pub.Commit(), msg, sig))
+
+	// resharing to a larger group keeps the distributed key
+	newPub, newShares, err := SimulateReshare(suite, pub, shares, n+2, thr+1, nil)
+	require.NoError(t, err)
+	require.Len(t, newShares, n+2)
+	require.True(t, newPub.Commit().Equal(pub.Commit()))
+	for _, s := range newShares {
+		require.True(t, newPub.Check(s))
+	}
+	sigs = sigs[:0]
+	for _, s := range newShares[2 : thr+3] {
+		sig, err := tbls.Sign(suite, s, msg)
+		require.NoError(t, err)
+		sigs = append(sigs, sig)
+	}
+	sig, err = tbls.Recover(suite, newPub, msg, sigs, thr+1, n+2)
+	require.NoError(t, err)
+	require.NoError(t, bls.Verify(suite, pub.Commit(), msg, sig))
+}
+
+func TestSimulateDKGSeed(t *testing.T) {
+	seed := []byte("simulation seed")
+	pub1, shares1, err := SimulateDKG(suite, 4, 3, seed)
+	require.NoError(t, err)
+	pub2, shares2, err := SimulateDKG(suite, 4, 3, seed)
+	require.NoError(t, err)
+	require.True(t, pub1.Equal(pub2))
+	for i := range shares1 {
+		require.True(t, shares1[i].V.Equal(shares2[i].V))
+	}
+	pub3, _, err := SimulateDKG(suite, 4, 3, []byte("other seed"))
+	require.NoError(t, err)
+	require.False(t, pub1.Equal(pub3))
+
+	re1, _, err := SimulateReshare(suite, pub1, shares1, 3, 2, seed)
+	require.NoError(t, err)
+	re2, _, err := SimulateReshare(suite, pub1, shares1, 3, 2, seed)
+	require.NoError(t, err)
+	require.True(t, re1.Equal(re2))
+	secret, err := share.RecoverSecret(suite, shares1, 3, 4)
+	require.NoError(t, err)
+	require.True(t, re1.Commit().Equal(suite.Point().Mul(secret, nil)))
+}
+
+func TestSimulateDKGInvalid(t *testing.T) {
+	_, _, err := SimulateDKG(suite, 3, 4, nil)
+	require.Equal(t, errSimulationInput, err)
+	_, _, err = SimulateDKG(suite, 1, 1, nil)
+	require.Equal(t, errSimulationInput, err)
+
+	pub, shares, err := SimulateDKG(suite, 3, 2, nil)
+	require.NoError(t, err)
+	_, _, err = SimulateReshare(suite, pub, shares[1:], 3, 2, nil)
+	require.Equal(t, errSimulationInput, err)
+	_, _, err = SimulateReshare(suite, nil, shares, 3, 2, nil)
+	require.Equal(t, errSimulationInput, err)
+}
@@ -0,0 +1,194 @@
+package dkg
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/share"
+)
+
+// ErrStateMismatch is returned by ResumeDistKeyGenerator when the state was
+// exported by another node, or with another configuration.
+var ErrStateMismatch = errors.New("dkg: state of another node or configuration")
+
+// ErrWiped is returned by ExportState once the distributed key share has been
+// computed, since the secret material of the protocol is then erased (see
+// DistKeyShare). The DistKeyShare itself must be saved instead.
+var ErrWiped = errors.New("dkg: state erased once the distributed key share is computed")
+
+// ExportState returns the state of the protocol for this node, so that a node
+// which restarts in the middle of the protocol can resume it with
+// ResumeDistKeyGenerator instead of forcing the whole group to restart the
+// DKG. The state is meant to be saved after each call which changes it, e.g.
+// after each deal, response or justification processed, and before sending
+// the messages returned by the call.
+//
+// The state holds the private polynomial of the dealer of this node, the
+// deals returned by Deals, so that the same ones are sent again, and the
+// messages processed so far, which ResumeDistKeyGenerator replays. It does not
+// hold the configuration, in particular the longterm key, which must be given
+// again to ResumeDistKeyGenerator. The state is secret: anybody reading it
+// learns the secret dealt by this node, so it must be stored as safely as the
+// longterm key. ExportState returns ErrWiped once DistKeyShare has been
+// called.
+//
+// The state is encoded like the messages (see MessageVersion1) as the public
+// key of the node, the optional list of the coefficients of the private
+// polynomial, whether the own deal was processed, the list of the indices
+// and encodings of the deals returned by Deals in increasing order of index,
+// the list of the encodings of the processed messages, and whether
+// SetTimeout and Finish were called.
+func (d *DistKeyGenerator) ExportState() ([]byte, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.dks != nil {
+		return nil, ErrWiped
+	}
+	e := newEncoder(MessageVersion1, kindState)
+	e.marshal(d.pub)
+	e.bool(d.canIssue)
+	if d.canIssue {
+		coeffs := d.dealer.PrivatePoly().Coefficients()
+		e.uint32(uint32(len(coeffs)))
+		for _, c := range coeffs {
+			e.marshal(c)
+		}
+	}
+	e.bool(d.processed)
+
+	indices := make([]int, 0, len(d.issued))
+	for i := range d.issued {
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+	e.uint32(uint32(len(indices)))
+	for _, i := range indices {
+		e.uint32(uint32(i))
+		e.bytes(d.issued[i])
+	}
+
+	e.uint32(uint32(len(d.log)))
+	for _, msg := range d.log {
+		e.bytes(msg)
+	}
+	e.bool(d.timeout)
+	e.bool(d.finished)
+	return e.buf.Bytes(), e.err
+}
+
+// ResumeDistKeyGenerator returns the DistKeyGenerator of a node which
+// restarted in the middle of the protocol, from the configuration it was
+// created with and the state it exported last with ExportState. The dealer
+// of the node deals the same polynomial, and Deals returns the same deals as
+// before the restart. The messages processed before the restart are
+// processed again, so that the node only needs the messages sent after the
+// export, e.g. the ones it missed while it was down. Processing again a
+// message it already had is harmless, since the duplicates are ignored.
+//
+// The evidence of the messages which were rejected before the restart, see
+// Report, is not restored. It returns an error wrapping ErrEncoding if the
+// state is not a valid encoding, and ErrStateMismatch if it was exported by
+// another node or with another configuration.
+func ResumeDistKeyGenerator(c *Config, state []byte) (*DistKeyGenerator, error) {
+	if c.Suite == nil {
+		return nil, ErrNilSuite
+	}
+	if c.Longterm == nil {
+		return nil, ErrNilLongterm
+	}
+	r := newDecoder(c.Suite, kindState, state)
+	pub := r.point()
+	var coeffs []kyber.Scalar
+	if r.bool() {
+		coeffs = make([]kyber.Scalar, r.count(c.Suite.ScalarLen()))
+		for i := range coeffs {
+			coeffs[i] = r.scalar()
+		}
+	}
+	processed := r.bool()
+	issued := make(map[int][]byte)
+	for n := r.count(8); n > 0; n-- {
+		i := int(r.uint32())
+		issued[i] = r.bytes()
+	}
+	log := make([][]byte, r.count(4))
+	for i := range log {
+		log[i] = r.bytes()
+	}
+	timeout := r.bool()
+	finished := r.bool()
+	if err := r.done(); err != nil {
+		return nil, err
+	}
+
+	if !pub.Equal(c.Suite.Point().Mul(c.Longterm, nil)) {
+		return nil, fmt.Errorf("%w: state of another public key", ErrStateMismatch)
+	}
+	var poly *share.PriPoly
+	if coeffs != nil {
+		if len(coeffs) == 0 {
+			return nil, fmt.Errorf("%w: empty private polynomial", ErrEncoding)
+		}
+		poly = share.CoefficientsToPriPoly(c.Suite, coeffs)
+	}
+	d, err := newDistKeyHandler(c, poly)
+	if err != nil {
+		return nil, err
+	}
+	if d.canIssue != (poly != nil) {
+		return nil, fmt.Errorf("%w: state of a node which deals %v", ErrStateMismatch, poly != nil)
+	}
+	d.processed = processed
+	if len(issued) > 0 {
+		d.issued = issued
+		if _, err := d.issuedDeals(); err != nil {
+			return nil, err
+		}
+	}
+	for _, msg := range log {
+		if err := d.replay(msg); err != nil {
+			return nil, err
+		}
+	}
+	if timeout {
+		d.setTimeout()
+	}
+	if finished {
+		d.finish()
+	}
+	return d, nil
+}
+
+// replay processes again a message of the log of an exported state. The
+// errors of the processing are ignored, since the messages which changed the
+// state include the justifications which disqualified their dealer.
+func (d *DistKeyGenerator) replay(msg []byte) error {
+	if len(msg) < 2 {
+		return fmt.Errorf("%w: message too short", ErrEncoding)
+	}
+	switch msg[1] {
+	case kindDeal:
+		dd := &Deal{}
+		if err := dd.UnmarshalBinary(d.suite, msg); err != nil {
+			return err
+		}
+		_, _ = d.processDeal(dd)
+	case kindResponse:
+		resp := &Response{}
+		if err := resp.UnmarshalBinary(d.suite, msg); err != nil {
+			return err
+		}
+		_, _ = d.processResponse(resp, false)
+	case kindJustification:
+		j := &Justification{}
+		if err := j.UnmarshalBinary(d.suite, msg); err != nil {
+			return err
+		}
+		_ = d.processJustification(j)
+	default:
+		return fmt.Errorf("%w: unexpected message kind %d", ErrEncoding, msg[1])
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package dkg
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	vss "go.dedis.ch/kyber/v3/share/vss/pedersen"
+)
+
+func TestDKGResumeState(t *testing.T) {
+	partPubs, partSec, dkgs := generate(defaultN, defaultT)
+	deals := make([]map[int]*Deal, defaultN)
+	for i, d := range dkgs {
+		var err error
+		deals[i], err = d.Deals()
+		require.NoError(t, err)
+	}
+	var resps []*Response
+	var first *Response
+	for i := range dkgs {
+		for j, dd := range deals[i] {
+			resp, err := dkgs[j].ProcessDeal(dd)
+			require.NoError(t, err)
+			resps = append(resps, resp)
+			if i == 1 && j == 0 {
+				first = resp
+			}
+		}
+	}
+
+	// the node 0 restarts once it has processed the deals and a response
+	_, err := dkgs[0].ProcessResponse(resps[len(resps)-1])
+	require.NoError(t, err)
+	state, err := dkgs[0].ExportState()
+	require.NoError(t, err)
+	c := &Config{Suite: suite, Longterm: partSec[0], NewNodes: partPubs, Threshold: defaultT}
+	resumed, err := ResumeDistKeyGenerator(c, state)
+	require.NoError(t, err)
+	require.Equal(t, dkgs[0].ReceivedDeals(), resumed.ReceivedDeals())
+	require.Equal(t, dkgs[0].dealer.SessionID(), resumed.dealer.SessionID())
+
+	// it sends the same deals again, and gives back the same responses
+	again, err := resumed.Deals()
+	require.NoError(t, err)
+	require.Len(t, again, len(deals[0]))
+	for i, dd := range deals[0] {
+		want, err := dd.MarshalBinary()
+		require.NoError(t, err)
+		got, err := again[i].MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+	}
+	resp, err := resumed.ProcessDeal(deals[1][0])
+	require.NoError(t, err)
+	require.Equal(t, vss.StatusApproval, resp.Response.Status)
+	require.Equal(t, first.Response.Hash(suite), resp.Response.Hash(suite))
+
+	// the other nodes and the resumed one finish the protocol, the response
+	// processed before the restart being ignored
+	dkgs[0] = resumed
+	for _, resp := range resps {
+		for _, d := range dkgs {
+			if resp.Response.Index == uint32(d.nidx) {
+				continue
+			}
+			_, err := d.ProcessResponse(resp)
+			require.NoError(t, err)
+		}
+	}
+	shares := make([]*DistKeyShare, defaultN)
+	for i, d := range dkgs {
+		require.True(t, d.Certified())
+		shares[i], err = d.DistKeyShare()
+		require.NoError(t, err)
+		require.True(t, checkDks(shares[0], shares[i]))
+	}
+
+	_, err = resumed.ExportState()
+	require.True(t, errors.Is(err, ErrWiped))
+}
+
+func TestDKGResumeStateFinished(t *testing.T) {
+	partPubs, partSec, dkgs := generate(defaultN, defaultT)
+	fullExchange(t, dkgs, true)
+	dkgs[1].Finish()
+	state, err := dkgs[1].ExportState()
+	require.NoError(t, err)
+	c := &Config{Suite: suite, Longterm: partSec[1], NewNodes: partPubs, Threshold: defaultT}
+	resumed, err := ResumeDistKeyGenerator(c, state)
+	require.NoError(t, err)
+	require.True(t, resumed.Finished())
+	require.Equal(t, dkgs[1].QUAL(), resumed.QUAL())
+	want, err := dkgs[1].DistKeyShare()
+	require.NoError(t, err)
+	got, err := resumed.DistKeyShare()
+	require.NoError(t, err)
+	require.True(t, checkDks(want, got))
+	require.True(t, want.Share.V.Equal(got.Share.V))
+}
+
+func TestDKGResumeStateErrors(t *testing.T) {
+	partPubs, partSec, dkgs := generate(defaultN, defaultT)
+	_, err := dkgs[0].Deals()
+	require.NoError(t, err)
+	state, err := dkgs[0].ExportState()
+	require.NoError(t, err)
+
+	// the state of another node
+	c := &Config{Suite: suite, Longterm: partSec[1], NewNodes: partPubs, Threshold: defaultT}
+	_, err = ResumeDistKeyGenerator(c, state)
+	require.True(t, errors.Is(err, ErrStateMismatch))
+
+	// another threshold
+	c = &Config{Suite: suite, Longterm: partSec[0], NewNodes: partPubs, Threshold: defaultT + 1}
+	_, err = ResumeDistKeyGenerator(c, state)
+	require.True(t, errors.Is(err, ErrStateMismatch))
+
+	c = &Config{Suite: suite, Longterm: partSec[0], NewNodes: partPubs, Threshold: defaultT}
+	for _, bad := range [][]byte{nil, state[:len(state)-1], append(state, 0)} {
+		_, err = ResumeDistKeyGenerator(c, bad)
+		require.True(t, errors.Is(err, ErrEncoding))
+	}
+	_, err = ResumeDistKeyGenerator(c, state)
+	require.NoError(t, err)
+}
@@ -3,6 +3,7 @@ package dkg
 import (
 	"bytes"
 	"encoding/binary"
+	"fmt"
 
 	"go.dedis.ch/kyber/v3"
 	"go.dedis.ch/kyber/v3/share"
@@ -15,10 +16,27 @@ type DistKeyShare struct {
 	Commits []kyber.Point
 	// Share of the distributed secret which is private information.
 	Share *share.PriShare
+	// Shares of the distributed secret held by the participant, one per unit
+	// of its weight (see Config.Weights), the first one being Share.
+	Shares []*share.PriShare
 	// Coefficients of the private polynomial generated by the node holding the
 	// share. The final distributed polynomial is the sum of all these
 	// individual polynomials, but it is never computed.
 	PrivatePoly []kyber.Scalar
+	// Evaluation point of the share when the DKG has been run with
+	// Config.UseHashedIndices. It is nil otherwise.
+	X kyber.Scalar
+	// group of the commitments, the one of Config.Suite
+	group kyber.Group
+}
+
+// Group returns the group of the commitments, i.e. the group of the suite of
+// the DKG that created the share. With a pairing suite, it tells whether the
+// public key is on G1 or G2 (see pairing.G1Suite and pairing.G2Suite), and it
+// gives the public polynomial share.NewPubPoly(d.Group(), nil, d.Commits) of
+// the distributed key. It is nil for a share which was not created by a DKG.
+func (d *DistKeyShare) Group() kyber.Group {
+	return d.group
 }
 
 // Public returns the public key associated with the distributed private key.
@@ -32,12 +50,113 @@ func (d *DistKeyShare) PriShare() *share.PriShare {
 	return d.Share
 }
 
+// XShare returns the share of the distributed secret evaluated at the hashed
+// index of the node. It returns nil if the DKG has not been run with
+// Config.UseHashedIndices.
+func (d *DistKeyShare) XShare() *share.XShare {
+	if d.X == nil {
+		return nil
+	}
+	return &share.XShare{X: d.X, V: d.Share.V}
+}
+
 // Commitments implements the dss.DistKeyShare interface so either pedersen or
 // rabin dkg can be used with dss.
 func (d *DistKeyShare) Commitments() []kyber.Point {
 	return d.Commits
 }
 
+// PublicShare returns the public share g^{s_i} of the share of index i of the
+// distributed key, i.e. the public polynomial of the commitments evaluated at
+// i, e.g. to verify the signature share of participant i with
+// tbls.VerifyShareAgainst. With Config.Weights, i is the index of a share and
+// not of a participant (see DistKeyGenerator.ShareHolder). With
+// Config.UseHashedIndices, the public share of the evaluation point X is
+// share.NewPubPoly(d.Group(), nil, d.Commits).EvalScalar(X) instead. It
+// returns nil for a share which was not created by a DKG (see Group).
+func (d *DistKeyShare) PublicShare(i int) *share.PubShare {
+	if d.group == nil {
+		return nil
+	}
+	return share.NewPubPoly(d.group, nil, d.Commits).Eval(i)
+}
+
+// clone returns a copy of d which shares none of its scalars, so that erasing
+// one copy leaves the other one intact.
+func (d *DistKeyShare) clone() *DistKeyShare {
+	c := *d
+	c.Commits = append([]kyber.Point{}, d.Commits...)
+	c.Shares = make([]*share.PriShare, len(d.Shares))
+	for i, s := range d.Shares {
+		c.Shares[i] = &share.PriShare{I: s.I, V: s.V.Clone()}
+		if s == d.Share {
+			c.Share = c.Shares[i]
+		}
+	}
+	if c.Share == d.Share && d.Share != nil {
+		c.Share = &share.PriShare{I: d.Share.I, V: d.Share.V.Clone()}
+	}
+	c.PrivatePoly = cloneScalars(d.PrivatePoly)
+	if d.X != nil {
+		c.X = d.X.Clone()
+	}
+	return &c
+}
+
+// cloneScalars returns a copy of the scalars of ss.
+func cloneScalars(ss []kyber.Scalar) []kyber.Scalar {
+	if ss == nil {
+		return nil
+	}
+	c := make([]kyber.Scalar, len(ss))
+	for i, s := range ss {
+		c[i] = s.Clone()
+	}
+	return c
+}
+
+// PublicShares returns the public shares of the n first shares of the
+// distributed key whose public polynomial has the given commitments, e.g. the
+// ones of DistKeyShare.Commits, over the group of the suite of the DKG. The
+// public share of index i is the commitment of the private share of index i
+// with the standard base point of the group.
+func PublicShares(suite Suite, commitments []kyber.Point, n int) []*share.PubShare {
+	return share.NewPubPoly(suite, nil, commitments).Shares(n)
+}
+
+// DistKeyShareFromShamir imports a share of a secret shared by other means
+// than a DKG, e.g. by a trusted dealer or with a standalone Shamir tool, so
+// that it can be used with the threshold schemes of Kyber and be reshared as
+// Config.Share, e.g. to get rid of the dealer. The commitments are the ones
+// of the coefficients of the sharing polynomial with the standard base point
+// of the group of the suite (see share.CommitmentsFromPriPoly), so that there
+// are as many of them as the threshold, at least 2, and the first one is the
+// public key. It returns an error if the share does not match the
+// commitments.
+func DistKeyShareFromShamir(suite Suite, priShare *share.PriShare, commitments []kyber.Point) (*DistKeyShare, error) {
+	if priShare == nil || priShare.V == nil || priShare.I < 0 {
+		return nil, fmt.Errorf("%w: invalid share", ErrInconsistentShare)
+	}
+	if len(commitments) < 2 {
+		return nil, fmt.Errorf("%w %d: need at least 2 commitments", ErrInvalidThreshold, len(commitments))
+	}
+	for i, c := range commitments {
+		if c == nil {
+			return nil, fmt.Errorf("%w: nil commitment %d", ErrInconsistentShare, i)
+		}
+	}
+	commits := append([]kyber.Point{}, commitments...)
+	if !share.NewPubPoly(suite, nil, commits).Check(priShare) {
+		return nil, fmt.Errorf("%w: share %d does not match the commitments", ErrInconsistentShare, priShare.I)
+	}
+	return &DistKeyShare{
+		Commits: commits,
+		Share:   priShare,
+		Shares:  []*share.PriShare{priShare},
+		group:   suite,
+	}, nil
+}
+
 // Deal holds the Deal for one participant as well as the index of the issuing
 // Dealer.
 type Deal struct {
@@ -49,13 +168,12 @@ type Deal struct {
 	Signature []byte
 }
 
-// MarshalBinary returns a binary representation of this deal, which is the
-// message  signed in a dkg deal.
-func (d *Deal) MarshalBinary() ([]byte, error) {
+// signatureMessage returns the message signed in a dkg deal.
+func (d *Deal) signatureMessage() []byte {
 	var b bytes.Buffer
 	binary.Write(&b, binary.LittleEndian, d.Index)
 	b.Write(d.Deal.Cipher)
-	return b.Bytes(), nil
+	return b.Bytes()
 }
 
 // Response holds the Response from another participant as well as the index of
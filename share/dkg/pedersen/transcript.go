@@ -0,0 +1,329 @@
+package dkg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/share"
+	vss "go.dedis.ch/kyber/v3/share/vss/pedersen"
+	"go.dedis.ch/kyber/v3/sign/schnorr"
+)
+
+// Transcript is the public record of a run of the protocol as seen by a node,
+// returned by DistKeyGenerator.Transcript. It holds the messages signed by the
+// nodes in their canonical encoding (see Deal.MarshalBinary,
+// Response.MarshalBinary and Justification.MarshalBinary), the commitments of
+// the dealers, the qualified set and the resulting public polynomial, so that
+// a third party which did not take part in the protocol can check with
+// VerifyTranscript that the distributed public key follows from the signed
+// messages. Like MisbehaviorReport, it can be serialized with encoding/json.
+//
+// The deals are encrypted for their verifiers, so that a third party cannot
+// check the shares. The approval of a verifier vouches instead for the
+// commitments of the deal, which are bound to the session ID of its signed
+// response, and the justification of a complaint reveals the share in public.
+// Transcripts only cover the generation of a fresh distributed key, without
+// resharing, Config.Weights or Config.UseHashedIndices.
+type Transcript struct {
+	// Nodes are the public keys of the nodes, which are both the dealers and
+	// the holders of the shares, in their canonical encoding.
+	Nodes [][]byte `json:"nodes"`
+	// Threshold of the distributed key.
+	Threshold uint32 `json:"threshold"`
+	// Nonce is the nonce of the run, see Config.Nonce.
+	Nonce []byte `json:"nonce,omitempty"`
+	// Deals are the deals received by the node, one per dealer.
+	Deals [][]byte `json:"deals"`
+	// Commitments are the commitments of the deals received by the node, in
+	// the order of the index of their dealer.
+	Commitments []DealerCommitments `json:"commitments"`
+	// Responses are the responses about the deals, including the ones of
+	// the node.
+	Responses [][]byte `json:"responses"`
+	// Justifications are the justifications of the complaints, including
+	// the failed ones.
+	Justifications [][]byte `json:"justifications,omitempty"`
+	// QUAL is the qualified set of the node, see DistKeyGenerator.QUAL.
+	QUAL []uint32 `json:"qual"`
+	// Public is the public polynomial of the distributed key, i.e. the sum
+	// of the commitments of the qualified dealers, whose first point is the
+	// distributed public key.
+	Public [][]byte `json:"public"`
+}
+
+// DealerCommitments are the commitments of the deal of a dealer, in their
+// canonical encoding.
+type DealerCommitments struct {
+	Index       uint32   `json:"index"`
+	Commitments [][]byte `json:"commitments"`
+}
+
+// ErrInvalidTranscript is returned by VerifyTranscript for a transcript
+// whose result does not follow from its messages.
+var ErrInvalidTranscript = errors.New("dkg: invalid transcript")
+
+var errTranscriptConfig = errors.New("dkg: no transcript for resharing, weights or hashed indices")
+
+// Transcript returns the transcript of the protocol as seen by this node so
+// far. The qualified set must not change anymore, i.e. all the deals must be
+// certified (see Certified) or the timeout must have been triggered, e.g. by
+// Finish. It returns an error wrapping ErrNotCertified otherwise. Transcript
+// can be called concurrently with the other methods.
+func (d *DistKeyGenerator) Transcript() (*Transcript, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.isResharing || d.c.Weights != nil || d.xs != nil {
+		return nil, errTranscriptConfig
+	}
+	if !d.timeout && !d.certified() {
+		return nil, fmt.Errorf("%w: not finished nor fully certified", ErrNotCertified)
+	}
+	if !d.thresholdCertified() {
+		return nil, ErrNotCertified
+	}
+
+	nodes, err := marshalPoints(d.c.NewNodes)
+	if err != nil {
+		return nil, err
+	}
+	t := &Transcript{Nodes: nodes, Threshold: uint32(d.newT), Nonce: d.c.Nonce}
+	dealers := make([]int, 0, len(d.processedDeals))
+	for i := range d.processedDeals {
+		dealers = append(dealers, int(i))
+	}
+	sort.Ints(dealers)
+	for _, i := range dealers {
+		p := d.processedDeals[uint32(i)]
+		commits, err := marshalPoints(d.verifiers[uint32(i)].Commits())
+		if err != nil {
+			return nil, err
+		}
+		t.Deals = append(t.Deals, p.deal)
+		t.Commitments = append(t.Commitments, DealerCommitments{Index: uint32(i), Commitments: commits})
+		if p.response != nil {
+			t.Responses = append(t.Responses, p.response)
+		}
+	}
+	for _, msg := range d.log {
+		switch msg[1] {
+		case kindResponse:
+			t.Responses = append(t.Responses, msg)
+		case kindJustification:
+			t.Justifications = append(t.Justifications, msg)
+		}
+	}
+
+	var pub *share.PubPoly
+	for _, i := range d.qualified() {
+		t.QUAL = append(t.QUAL, uint32(i))
+		poly := share.NewPubPoly(d.suite, nil, d.verifiers[uint32(i)].Commits())
+		if pub == nil {
+			pub = poly
+		} else if pub, err = pub.Add(poly); err != nil {
+			return nil, err
+		}
+	}
+	_, commits := pub.Info()
+	if t.Public, err = marshalPoints(commits); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// VerifyTranscript checks that the qualified set and the public polynomial of
+// the transcript follow from its messages, so that a third party does not
+// have to trust the node which made the transcript. It checks that:
+//   - the deals and the justifications are signed by their dealer, and the
+//     responses by their verifier,
+//   - the responses and the justifications have the session ID of the
+//     commitments of their dealer, and a verifier does not both approve and
+//     complain about a deal,
+//   - the qualified dealers are the ones with the approvals of at least the
+//     threshold of the nodes, themselves included, and no complaint left
+//     unanswered by a valid justification,
+//   - the public polynomial is the sum of the commitments of the qualified
+//     dealers.
+//
+// It returns an error wrapping ErrInvalidTranscript for the first check which
+// fails. The public keys of the nodes and the nonce are the ones of the
+// transcript: the caller must check that they are the keys of the
+// participants and the nonce of the run.
+func VerifyTranscript(suite Suite, t *Transcript) error {
+	nodes, err := unmarshalPoints(suite, t.Nodes)
+	if err != nil {
+		return fmt.Errorf("%w: public keys: %v", ErrInvalidTranscript, err)
+	}
+	threshold := int(t.Threshold)
+	if threshold < 1 || threshold > len(nodes) {
+		return fmt.Errorf("%w: threshold %d for %d nodes", ErrInvalidTranscript, threshold, len(nodes))
+	}
+
+	commits := make(map[uint32][]kyber.Point)
+	sids := make(map[uint32][]byte)
+	for _, dc := range t.Commitments {
+		dealer, ok := getPub(nodes, dc.Index)
+		if !ok || commits[dc.Index] != nil {
+			return fmt.Errorf("%w: commitments of dealer %d", ErrInvalidTranscript, dc.Index)
+		}
+		points, err := unmarshalPoints(suite, dc.Commitments)
+		if err != nil || len(points) != threshold {
+			return fmt.Errorf("%w: commitments of dealer %d", ErrInvalidTranscript, dc.Index)
+		}
+		if sids[dc.Index], err = vss.SessionIDWithNonce(suite, dealer, nodes, points, threshold, t.Nonce); err != nil {
+			return fmt.Errorf("%w: commitments of dealer %d: %v", ErrInvalidTranscript, dc.Index, err)
+		}
+		commits[dc.Index] = points
+	}
+
+	for _, buff := range t.Deals {
+		dd := &Deal{}
+		if err := dd.UnmarshalBinary(suite, buff); err != nil {
+			return fmt.Errorf("%w: deal: %v", ErrInvalidTranscript, err)
+		}
+		dealer, ok := getPub(nodes, dd.Index)
+		if !ok || schnorr.Verify(suite, dealer, dd.signatureMessage(), dd.Signature) != nil {
+			return fmt.Errorf("%w: deal not signed by dealer %d", ErrInvalidTranscript, dd.Index)
+		}
+		if dd.Deal.Version != 0 && !bytes.Equal(dd.Deal.SessionID, sids[dd.Index]) {
+			return fmt.Errorf("%w: deal of dealer %d of another session", ErrInvalidTranscript, dd.Index)
+		}
+	}
+
+	// approvals by dealer and verifier
+	approvals := make(map[uint32]map[uint32]bool)
+	for _, buff := range t.Responses {
+		resp := &Response{}
+		if err := resp.UnmarshalBinary(suite, buff); err != nil {
+			return fmt.Errorf("%w: response: %v", ErrInvalidTranscript, err)
+		}
+		r := resp.Response
+		verifier, ok := getPub(nodes, r.Index)
+		if !ok || schnorr.Verify(suite, verifier, r.Hash(suite), r.Signature) != nil {
+			return fmt.Errorf("%w: response not signed by verifier %d", ErrInvalidTranscript, r.Index)
+		}
+		sid, ok := sids[resp.Index]
+		if !ok || !bytes.Equal(r.SessionID, sid) {
+			return fmt.Errorf("%w: response of verifier %d for another deal of dealer %d", ErrInvalidTranscript, r.Index, resp.Index)
+		}
+		if approvals[resp.Index] == nil {
+			approvals[resp.Index] = make(map[uint32]bool)
+		}
+		approval := r.Status == vss.StatusApproval
+		if prev, ok := approvals[resp.Index][r.Index]; ok && prev != approval {
+			return fmt.Errorf("%w: contradicting responses of verifier %d", ErrInvalidTranscript, r.Index)
+		}
+		approvals[resp.Index][r.Index] = approval
+	}
+
+	disqualified := make(map[uint32]bool)
+	for _, buff := range t.Justifications {
+		j := &Justification{}
+		if err := j.UnmarshalBinary(suite, buff); err != nil {
+			return fmt.Errorf("%w: justification: %v", ErrInvalidTranscript, err)
+		}
+		dealer, ok := getPub(nodes, j.Index)
+		if !ok || schnorr.Verify(suite, dealer, j.Justification.Hash(suite), j.Justification.Signature) != nil {
+			return fmt.Errorf("%w: justification not signed by dealer %d", ErrInvalidTranscript, j.Index)
+		}
+		if !bytes.Equal(j.Justification.SessionID, sids[j.Index]) {
+			return fmt.Errorf("%w: justification of dealer %d for another deal", ErrInvalidTranscript, j.Index)
+		}
+		if invalidJustification(suite, dealer, nodes, t.Nonce, j.Justification) {
+			disqualified[j.Index] = true
+		} else if approval, ok := approvals[j.Index][j.Justification.Index]; ok && !approval {
+			approvals[j.Index][j.Justification.Index] = true
+		}
+	}
+
+	var qual []uint32
+	var pub *share.PubPoly
+	for i := uint32(0); i < uint32(len(nodes)); i++ {
+		if commits[i] == nil || disqualified[i] {
+			continue
+		}
+		// a dealer does not respond to its own deal
+		count := 1
+		complaint := false
+		for v, approval := range approvals[i] {
+			if v == i {
+				continue
+			}
+			if approval {
+				count++
+			} else {
+				complaint = true
+			}
+		}
+		if complaint || count < threshold {
+			continue
+		}
+		qual = append(qual, i)
+		poly := share.NewPubPoly(suite, nil, commits[i])
+		if pub == nil {
+			pub = poly
+		} else if pub, err = pub.Add(poly); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidTranscript, err)
+		}
+	}
+	if !equalIndices(qual, t.QUAL) {
+		return fmt.Errorf("%w: qualified set %v instead of %v", ErrInvalidTranscript, t.QUAL, qual)
+	}
+	if pub == nil {
+		return fmt.Errorf("%w: no qualified dealer", ErrInvalidTranscript)
+	}
+	public, err := unmarshalPoints(suite, t.Public)
+	if err != nil {
+		return fmt.Errorf("%w: public polynomial: %v", ErrInvalidTranscript, err)
+	}
+	_, want := pub.Info()
+	if len(public) != len(want) {
+		return fmt.Errorf("%w: public polynomial of %d points instead of %d", ErrInvalidTranscript, len(public), len(want))
+	}
+	for k := range want {
+		if !public[k].Equal(want[k]) {
+			return fmt.Errorf("%w: public polynomial differs at coefficient %d", ErrInvalidTranscript, k)
+		}
+	}
+	return nil
+}
+
+// equalIndices returns true if both lists hold the same indices, in the same
+// order.
+func equalIndices(a, b []uint32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k := range a {
+		if a[k] != b[k] {
+			return false
+		}
+	}
+	return true
+}
+
+// marshalPoints returns the canonical encodings of the points.
+func marshalPoints(points []kyber.Point) ([][]byte, error) {
+	buffs := make([][]byte, len(points))
+	for i, p := range points {
+		var err error
+		if buffs[i], err = p.MarshalBinary(); err != nil {
+			return nil, err
+		}
+	}
+	return buffs, nil
+}
+
+// unmarshalPoints decodes the points encoded by marshalPoints.
+func unmarshalPoints(suite Suite, buffs [][]byte) ([]kyber.Point, error) {
+	points := make([]kyber.Point, len(buffs))
+	for i, buff := range buffs {
+		points[i] = suite.Point()
+		if err := points[i].UnmarshalBinary(buff); err != nil {
+			return nil, err
+		}
+	}
+	return points, nil
+}
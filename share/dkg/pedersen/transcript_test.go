@@ -0,0 +1,138 @@
+package dkg
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/share"
+	vss "go.dedis.ch/kyber/v3/share/vss/pedersen"
+	"go.dedis.ch/kyber/v3/sign/schnorr"
+)
+
+func TestDKGTranscript(t *testing.T) {
+	_, _, dkgs := generate(defaultN, defaultT)
+	_, err := dkgs[0].Transcript()
+	require.True(t, errors.Is(err, ErrNotCertified))
+	fullExchange(t, dkgs, true)
+
+	for _, d := range dkgs {
+		tr, err := d.Transcript()
+		require.NoError(t, err)
+		require.Len(t, tr.Deals, defaultN)
+		require.Len(t, tr.QUAL, defaultN)
+		require.NoError(t, VerifyTranscript(suite, tr))
+
+		dks, err := d.DistKeyShare()
+		require.NoError(t, err)
+		public, err := dks.Public().MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, public, tr.Public[0])
+
+		buff, err := json.Marshal(tr)
+		require.NoError(t, err)
+		decoded := &Transcript{}
+		require.NoError(t, json.Unmarshal(buff, decoded))
+		require.NoError(t, VerifyTranscript(suite, decoded))
+	}
+
+	tr, err := dkgs[0].Transcript()
+	require.NoError(t, err)
+	tamper := func(f func(tr *Transcript)) error {
+		buff, err := json.Marshal(tr)
+		require.NoError(t, err)
+		copied := &Transcript{}
+		require.NoError(t, json.Unmarshal(buff, copied))
+		f(copied)
+		return VerifyTranscript(suite, copied)
+	}
+	cases := map[string]func(tr *Transcript){
+		"dealer left out of QUAL": func(tr *Transcript) { tr.QUAL = tr.QUAL[1:] },
+		"other public polynomial": func(tr *Transcript) { tr.Public[0] = tr.Nodes[0] },
+		"too few approvals":       func(tr *Transcript) { tr.Responses = tr.Responses[:defaultN] },
+		"other commitments":       func(tr *Transcript) { tr.Commitments[1].Commitments[0] = tr.Nodes[0] },
+		"forged response": func(tr *Transcript) {
+			tr.Responses[0][len(tr.Responses[0])-1] ^= 1
+		},
+		"other threshold": func(tr *Transcript) { tr.Threshold = defaultN + 1 },
+	}
+	for name, f := range cases {
+		require.True(t, errors.Is(tamper(f), ErrInvalidTranscript), name)
+	}
+}
+
+// TestDKGTranscriptJustification checks that the transcript accounts for the
+// justifications, whether they answer the complaint or disqualify the dealer.
+func TestDKGTranscriptJustification(t *testing.T) {
+	for _, valid := range []bool{true, false} {
+		_, secs, dkgs := generate(defaultN, defaultT)
+		const dealer, accuser, witness = 0, 1, 2
+
+		// the accuser receives an invalid share, or complains about a valid
+		// one, see malformedDeal
+		bad := suite.Scalar().Pick(suite.RandomStream())
+		var resps []*Response
+		for i, d := range dkgs {
+			deals, err := d.Deals()
+			require.NoError(t, err)
+			for j, deal := range deals {
+				if i == dealer && j == accuser && !valid {
+					deal = malformedDeal(t, dkgs, func(d *vss.Deal) { d.SecShare.V = bad })
+				}
+				resp, err := dkgs[j].ProcessDeal(deal)
+				require.NoError(t, err)
+				if i == dealer && j == accuser && valid {
+					resp.Response.Status = vss.StatusComplaint
+					resp.Response.Signature, err = schnorr.Sign(suite, secs[accuser], resp.Response.Hash(suite))
+					require.NoError(t, err)
+				}
+				resps = append(resps, resp)
+			}
+		}
+		var justification *Justification
+		for _, resp := range resps {
+			for i, d := range dkgs {
+				if resp.Response.Index == uint32(i) {
+					continue
+				}
+				j, err := d.ProcessResponse(transmitResponse(t, resp))
+				require.NoError(t, err)
+				if j != nil && valid {
+					justification = j
+				}
+			}
+		}
+		if !valid {
+			// the dealer reveals the share it sent
+			plain, err := dkgs[dealer].dealer.PlaintextDeal(accuser)
+			require.NoError(t, err)
+			revealed := *plain
+			revealed.SecShare = &share.PriShare{I: accuser, V: bad}
+			justification = &Justification{
+				Index: dealer,
+				Justification: &vss.Justification{
+					SessionID: plain.SessionID,
+					Index:     accuser,
+					Deal:      &revealed,
+				},
+			}
+			justification.Justification.Signature, err = schnorr.Sign(suite, secs[dealer], justification.Justification.Hash(suite))
+			require.NoError(t, err)
+		}
+		require.NotNil(t, justification)
+		_ = dkgs[witness].ProcessJustification(transmitJustification(t, justification))
+		dkgs[witness].SetTimeout()
+
+		tr, err := dkgs[witness].Transcript()
+		require.NoError(t, err)
+		require.Len(t, tr.Justifications, 1)
+		require.Equal(t, valid, dkgs[witness].isInQUAL(dealer))
+		require.Len(t, tr.QUAL, len(dkgs[witness].QUAL()))
+		require.NoError(t, VerifyTranscript(suite, tr))
+
+		// without the justification, the complaint stands
+		tr.Justifications = nil
+		require.Equal(t, valid, errors.Is(VerifyTranscript(suite, tr), ErrInvalidTranscript))
+	}
+}
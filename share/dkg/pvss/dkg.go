@@ -0,0 +1,348 @@
+// Package dkg implements a non-interactive distributed key generation (DKG)
+// built on the publicly verifiable secret sharing of the share/pvss package.
+// Unlike the pedersen and rabin DKGs, there are no responses nor
+// justifications: every dealer publishes a single Deal on a broadcast channel,
+// whose encrypted shares and NIZK proofs anyone can check, so that the
+// protocol is one round long when the dealers are honest. This makes it easy
+// to run over a blockchain or a gossip network.
+//
+// The protocol works as follows:
+//  1. Each participant creates its DistKeyGenerator and publishes the Deal
+//     returned by Deal() on the broadcast channel.
+//  2. Each participant processes every Deal of the channel, its own one
+//     included, with ProcessDeal(). An invalid deal is discarded by all the
+//     participants alike. If a dealer encrypted a wrong share to the
+//     participant, ProcessDeal returns a Complaint which must be published on
+//     the channel, and which anyone can check: it is the only message besides
+//     the deals, and only a cheating dealer causes one.
+//  3. Each participant processes every Complaint of the channel with
+//     ProcessComplaint(). A valid complaint disqualifies its dealer.
+//  4. Once the channel is closed, e.g. at a given height of the blockchain,
+//     each participant computes its share of the distributed key with
+//     DistKeyShare(), from the qualified deals (see QUAL).
+//
+// All the participants must process the same deals and complaints to compute
+// the same distributed key, which is the guarantee of the broadcast channel.
+package dkg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/share"
+	"go.dedis.ch/kyber/v3/share/pvss"
+	"go.dedis.ch/kyber/v3/sign/schnorr"
+)
+
+// Suite wraps the functionalities needed by the dkg package
+type Suite pvss.Suite
+
+// DistKeyShare holds the share of a distributed key for a participant.
+type DistKeyShare struct {
+	// Coefficients of the public polynomial holding the public key
+	Commits []kyber.Point
+	// Share of the distributed secret
+	Share *share.PriShare
+}
+
+// Public returns the public key associated with the distributed private key.
+func (d *DistKeyShare) Public() kyber.Point {
+	return d.Commits[0]
+}
+
+// PriShare implements the dss.DistKeyShare interface so that this dkg can be
+// used with dss.
+func (d *DistKeyShare) PriShare() *share.PriShare {
+	return d.Share
+}
+
+// Commitments implements the dss.DistKeyShare interface so that this dkg can
+// be used with dss.
+func (d *DistKeyShare) Commitments() []kyber.Point {
+	return d.Commits
+}
+
+// Deal holds the dealing of a dealer, signed with its longterm key.
+type Deal struct {
+	// Index of the dealer in the list of participants
+	Index uint32
+	// Dealing of the dealer, holding the encrypted shares of all the
+	// participants
+	Dealing *pvss.Dealing
+	// Signature of the dealer over the hash of the deal
+	Signature []byte
+}
+
+// Complaint is published by a participant whose share of a deal does not
+// match the public polynomial of the dealer. Its Dealer and Index are the
+// indices of the dealer and of the participant in the list of participants.
+type Complaint = pvss.Complaint
+
+var (
+	// ErrDuplicateDeal is returned when a dealer published two different
+	// deals.
+	ErrDuplicateDeal = errors.New("dkg: dealer published two different deals")
+	// ErrInvalidDeal is returned for a deal whose signature, encrypted shares
+	// or proofs are invalid.
+	ErrInvalidDeal = errors.New("dkg: invalid deal")
+	// ErrInvalidComplaint is returned for a complaint which does not prove
+	// that its dealer cheated.
+	ErrInvalidComplaint = errors.New("dkg: invalid complaint")
+	// ErrTooFewDeals is returned by DistKeyShare when less than t deals are
+	// qualified.
+	ErrTooFewDeals = errors.New("dkg: not enough qualified deals")
+)
+
+var errIncompleteDealing = errors.New("dkg: incomplete dealing")
+
+// DistKeyGenerator is the struct that runs the DKG protocol.
+type DistKeyGenerator struct {
+	suite Suite
+
+	index uint32
+	long  kyber.Scalar
+	pub   kyber.Point
+
+	participants []kyber.Point
+
+	t int
+
+	// own deal, created by the first call to Deal
+	deal *Deal
+	// deals processed, by index of their dealer
+	deals []*Deal
+	// own shares of the deals processed, by index of their dealer, nil for
+	// the deals which gave a complaint
+	shares []*share.PriShare
+	// dealers disqualified by a valid complaint
+	disqualified map[uint32]bool
+}
+
+// NewDistKeyGenerator returns a DistKeyGenerator out of the suite,
+// the longterm secret key, the list of participants, and the
+// threshold t parameter. It returns an error if the secret key's
+// commitment can't be found in the list of participants.
+func NewDistKeyGenerator(suite Suite, longterm kyber.Scalar, participants []kyber.Point, t int) (*DistKeyGenerator, error) {
+	if t < 1 || t > len(participants) {
+		return nil, fmt.Errorf("dkg: invalid threshold %d for %d participants", t, len(participants))
+	}
+	pub := suite.Point().Mul(longterm, nil)
+	index := -1
+	for i, p := range participants {
+		if p.Equal(pub) {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, errors.New("dkg: own public key not found in list of participants")
+	}
+	return &DistKeyGenerator{
+		suite:        suite,
+		index:        uint32(index),
+		long:         longterm,
+		pub:          pub,
+		participants: participants,
+		t:            t,
+		deals:        make([]*Deal, len(participants)),
+		shares:       make([]*share.PriShare, len(participants)),
+		disqualified: make(map[uint32]bool),
+	}, nil
+}
+
+// Deal returns the signed deal of this participant, to publish on the
+// broadcast channel. It creates the dealing of a random secret on the first
+// call, and returns the same deal afterwards.
+func (d *DistKeyGenerator) Deal() (*Deal, error) {
+	if d.deal != nil {
+		return d.deal, nil
+	}
+	dealing, err := pvss.NewDealing(d.suite, d.participants, nil, d.t)
+	if err != nil {
+		return nil, err
+	}
+	deal := &Deal{Index: d.index, Dealing: dealing}
+	h, err := deal.Hash(d.suite)
+	if err != nil {
+		return nil, err
+	}
+	if deal.Signature, err = schnorr.Sign(d.suite, d.long, h); err != nil {
+		return nil, err
+	}
+	d.deal = deal
+	return deal, nil
+}
+
+// ProcessDeal checks the signature, the encrypted shares and the proofs of
+// the deal, stores it, and decrypts the share of this participant. It returns
+// the complaint to publish if the share does not match the public polynomial
+// of the dealer, and nil otherwise. It returns an error wrapping
+// ErrInvalidDeal for an invalid deal, which must be discarded, and
+// ErrDuplicateDeal if another deal of the same dealer was processed before.
+// Processing the same deal again is a no-op.
+func (d *DistKeyGenerator) ProcessDeal(dd *Deal) (*Complaint, error) {
+	if dd == nil || dd.Dealing == nil {
+		return nil, fmt.Errorf("%w: nil dealing", ErrInvalidDeal)
+	}
+	pub, ok := findPub(d.participants, dd.Index)
+	if !ok {
+		return nil, fmt.Errorf("%w: dealer index %d out of range", ErrInvalidDeal, dd.Index)
+	}
+	h, err := dd.Hash(d.suite)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDeal, err)
+	}
+	if err := schnorr.Verify(d.suite, pub, h, dd.Signature); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDeal, err)
+	}
+	if prev := d.deals[dd.Index]; prev != nil {
+		if ph, err := prev.Hash(d.suite); err == nil && bytes.Equal(ph, h) {
+			return nil, nil
+		}
+		return nil, ErrDuplicateDeal
+	}
+	if err := pvss.VerifyDealing(d.suite, d.participants, dd.Dealing, d.t); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDeal, err)
+	}
+	d.deals[dd.Index] = dd
+	s, err := pvss.DecryptShare(d.suite, dd.Dealing, int(d.index), d.long)
+	if err == nil {
+		d.shares[dd.Index] = s
+		return nil, nil
+	}
+	c, err := pvss.NewComplaint(d.suite, int(dd.Index), dd.Dealing, int(d.index), d.long)
+	if err != nil {
+		return nil, err
+	}
+	d.disqualified[dd.Index] = true
+	return c, nil
+}
+
+// ProcessComplaint checks the complaint against the deal of its dealer, which
+// must have been processed before, and disqualifies the dealer if it is
+// valid. It returns an error wrapping ErrInvalidComplaint otherwise.
+func (d *DistKeyGenerator) ProcessComplaint(c *Complaint) error {
+	if c == nil || c.Dealer < 0 || c.Dealer >= len(d.deals) || d.deals[c.Dealer] == nil {
+		return fmt.Errorf("%w: no deal of the dealer", ErrInvalidComplaint)
+	}
+	if err := pvss.VerifyComplaint(d.suite, d.participants, d.dealings(), c); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidComplaint, err)
+	}
+	d.disqualified[uint32(c.Dealer)] = true
+	return nil
+}
+
+// QUAL returns the indices of the dealers whose deal is qualified, i.e. valid
+// and without a valid complaint, in increasing order.
+func (d *DistKeyGenerator) QUAL() []int {
+	var qual []int
+	for i, dd := range d.deals {
+		if dd != nil && !d.disqualified[uint32(i)] {
+			qual = append(qual, i)
+		}
+	}
+	return qual
+}
+
+// Certified returns true if at least t deals are qualified, so that
+// DistKeyShare can compute the distributed key. More deals may still be
+// published until the broadcast channel is closed.
+func (d *DistKeyGenerator) Certified() bool {
+	return len(d.QUAL()) >= d.t
+}
+
+// DistKeyShare returns the share of the distributed key of this participant,
+// which is the sum of its shares of the qualified deals, and the public
+// polynomial of the distributed key, which is the sum of the public
+// polynomials of the qualified dealers. It returns an error wrapping
+// ErrTooFewDeals if less than t deals are qualified.
+func (d *DistKeyGenerator) DistKeyShare() (*DistKeyShare, error) {
+	qual := d.QUAL()
+	if len(qual) < d.t {
+		return nil, fmt.Errorf("%w: %d deals for threshold %d", ErrTooFewDeals, len(qual), d.t)
+	}
+	sh := d.suite.Scalar().Zero()
+	var pub *share.PubPoly
+	for _, i := range qual {
+		sh.Add(sh, d.shares[i].V)
+		poly := share.NewPubPoly(d.suite, nil, d.deals[i].Dealing.Commits)
+		if pub == nil {
+			pub = poly
+			continue
+		}
+		var err error
+		if pub, err = pub.Add(poly); err != nil {
+			return nil, err
+		}
+	}
+	_, commits := pub.Info()
+	return &DistKeyShare{
+		Commits: commits,
+		Share:   &share.PriShare{I: int(d.index), V: sh},
+	}, nil
+}
+
+// dealings returns the dealings processed by index of their dealer, nil for
+// the missing ones.
+func (d *DistKeyGenerator) dealings() []*pvss.Dealing {
+	dealings := make([]*pvss.Dealing, len(d.deals))
+	for i, dd := range d.deals {
+		if dd != nil {
+			dealings[i] = dd.Dealing
+		}
+	}
+	return dealings
+}
+
+// Hash returns the hash value of this struct used in the signature process.
+// It returns an error if the dealing misses a field.
+func (dd *Deal) Hash(s Suite) ([]byte, error) {
+	dl := dd.Dealing
+	if dl == nil || dl.R == nil || len(dl.EncShares) != len(dl.Masked) {
+		return nil, errIncompleteDealing
+	}
+	h := s.Hash()
+	_, _ = h.Write([]byte("pvssdeal"))
+	_ = binary.Write(h, binary.LittleEndian, dd.Index)
+	_ = binary.Write(h, binary.LittleEndian, uint32(len(dl.Commits)))
+	_ = binary.Write(h, binary.LittleEndian, uint32(len(dl.EncShares)))
+	write := func(ms ...kyber.Marshaling) error {
+		for _, m := range ms {
+			if m == nil {
+				return errIncompleteDealing
+			}
+			if _, err := m.MarshalTo(h); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, c := range dl.Commits {
+		if err := write(c); err != nil {
+			return nil, err
+		}
+	}
+	for i, es := range dl.EncShares {
+		if es == nil {
+			return nil, errIncompleteDealing
+		}
+		_ = binary.Write(h, binary.LittleEndian, uint32(es.S.I))
+		if err := write(es.S.V, es.P.C, es.P.R, es.P.VG, es.P.VH, dl.Masked[i]); err != nil {
+			return nil, err
+		}
+	}
+	if err := write(dl.R); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+func findPub(list []kyber.Point, i uint32) (kyber.Point, bool) {
+	if i >= uint32(len(list)) {
+		return nil, false
+	}
+	return list[i], true
+}
@@ -0,0 +1,204 @@
+package dkg
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/share"
+	"go.dedis.ch/kyber/v3/sign/bls"
+	"go.dedis.ch/kyber/v3/sign/schnorr"
+	"go.dedis.ch/kyber/v3/sign/tbls"
+)
+
+var suite = edwards25519.NewBlakeSHA256Ed25519()
+
+const defaultN = 7
+
+var defaultT = defaultN/2 + 1
+
+func generate(s Suite, n, t int) ([]kyber.Scalar, []*DistKeyGenerator) {
+	privs := make([]kyber.Scalar, n)
+	pubs := make([]kyber.Point, n)
+	for i := range privs {
+		privs[i] = s.Scalar().Pick(s.RandomStream())
+		pubs[i] = s.Point().Mul(privs[i], nil)
+	}
+	dkgs := make([]*DistKeyGenerator, n)
+	for i := range dkgs {
+		d, err := NewDistKeyGenerator(s, privs[i], pubs, t)
+		if err != nil {
+			panic(err)
+		}
+		dkgs[i] = d
+	}
+	return privs, dkgs
+}
+
+// broadcast delivers the deals to all the participants and returns the
+// complaints they published.
+func broadcast(t *testing.T, dkgs []*DistKeyGenerator, deals []*Deal) []*Complaint {
+	var complaints []*Complaint
+	for _, d := range dkgs {
+		for _, dd := range deals {
+			c, err := d.ProcessDeal(dd)
+			require.NoError(t, err)
+			if c != nil {
+				complaints = append(complaints, c)
+			}
+		}
+	}
+	return complaints
+}
+
+func deals(t *testing.T, dkgs []*DistKeyGenerator) []*Deal {
+	deals := make([]*Deal, len(dkgs))
+	for i, d := range dkgs {
+		dd, err := d.Deal()
+		require.NoError(t, err)
+		deals[i] = dd
+	}
+	return deals
+}
+
+// checkShares checks that the participants hold shares of the same
+// distributed key, and returns them.
+func checkShares(t *testing.T, s Suite, dkgs []*DistKeyGenerator, tt int) []*DistKeyShare {
+	dkss := make([]*DistKeyShare, len(dkgs))
+	shares := make([]*share.PriShare, len(dkgs))
+	for i, d := range dkgs {
+		dks, err := d.DistKeyShare()
+		require.NoError(t, err)
+		require.Len(t, dks.Commits, tt)
+		dkss[i] = dks
+		require.True(t, dks.Public().Equal(dkss[0].Public()))
+		shares[i] = dks.PriShare()
+		require.True(t, share.NewPubPoly(s, nil, dks.Commitments()).Check(dks.Share))
+	}
+	secret, err := share.RecoverSecret(s, shares, tt, len(dkgs))
+	require.NoError(t, err)
+	require.True(t, s.Point().Mul(secret, nil).Equal(dkss[0].Public()))
+	return dkss
+}
+
+func TestDKG(t *testing.T) {
+	_, dkgs := generate(suite, defaultN, defaultT)
+	dd := deals(t, dkgs)
+	require.Empty(t, broadcast(t, dkgs, dd))
+	for _, d := range dkgs {
+		require.True(t, d.Certified())
+		require.Len(t, d.QUAL(), defaultN)
+	}
+	checkShares(t, suite, dkgs, defaultT)
+
+	// the deal is created once, and processing it again is a no-op
+	again, err := dkgs[0].Deal()
+	require.NoError(t, err)
+	require.Equal(t, dd[0], again)
+	c, err := dkgs[1].ProcessDeal(dd[0])
+	require.NoError(t, err)
+	require.Nil(t, c)
+}
+
+func TestDKGComplaint(t *testing.T) {
+	privs, dkgs := generate(suite, defaultN, defaultT)
+	dd := deals(t, dkgs)
+
+	// the last dealer encrypts a wrong share to the participant 0, and signs
+	// the deal again so that it is valid
+	bad := dd[defaultN-1]
+	masked := bad.Dealing.Masked
+	masked[0] = suite.Scalar().Add(masked[0], suite.Scalar().One())
+	h, err := bad.Hash(suite)
+	require.NoError(t, err)
+	bad.Signature, err = schnorr.Sign(suite, privs[defaultN-1], h)
+	require.NoError(t, err)
+
+	complaints := broadcast(t, dkgs, dd)
+	require.Len(t, complaints, 1)
+	require.Equal(t, defaultN-1, complaints[0].Dealer)
+	require.Equal(t, 0, complaints[0].Index)
+	for _, d := range dkgs {
+		require.NoError(t, d.ProcessComplaint(complaints[0]))
+		require.NotContains(t, d.QUAL(), defaultN-1)
+		require.Len(t, d.QUAL(), defaultN-1)
+	}
+	checkShares(t, suite, dkgs, defaultT)
+
+	// a complaint against an honest dealer is rejected
+	unfounded := *complaints[0]
+	unfounded.Dealer = 0
+	err = dkgs[1].ProcessComplaint(&unfounded)
+	require.True(t, errors.Is(err, ErrInvalidComplaint))
+}
+
+func TestDKGInvalidDeals(t *testing.T) {
+	_, dkgs := generate(suite, defaultN, defaultT)
+	dd := deals(t, dkgs)
+	d := dkgs[1]
+
+	// a deal signed by another dealer
+	forged := *dd[0]
+	forged.Index = 2
+	_, err := d.ProcessDeal(&forged)
+	require.True(t, errors.Is(err, ErrInvalidDeal))
+
+	forged = *dd[0]
+	forged.Index = defaultN
+	_, err = d.ProcessDeal(&forged)
+	require.True(t, errors.Is(err, ErrInvalidDeal))
+
+	_, err = d.ProcessDeal(&Deal{})
+	require.True(t, errors.Is(err, ErrInvalidDeal))
+
+	// a second deal of the same dealer
+	_, err = d.ProcessDeal(dd[0])
+	require.NoError(t, err)
+	other, err := generateDeal(dkgs[0])
+	require.NoError(t, err)
+	_, err = d.ProcessDeal(other)
+	require.True(t, errors.Is(err, ErrDuplicateDeal))
+
+	// a complaint about a deal which was not processed
+	require.True(t, errors.Is(d.ProcessComplaint(&Complaint{Dealer: 3}), ErrInvalidComplaint))
+
+	// not enough deals
+	require.False(t, d.Certified())
+	_, err = d.DistKeyShare()
+	require.True(t, errors.Is(err, ErrTooFewDeals))
+
+	_, err = NewDistKeyGenerator(suite, suite.Scalar().One(), []kyber.Point{suite.Point().Base()}, 2)
+	require.Error(t, err)
+	_, err = NewDistKeyGenerator(suite, suite.Scalar().Zero(), []kyber.Point{suite.Point().Base()}, 1)
+	require.Error(t, err)
+}
+
+// generateDeal creates another deal of the dealer.
+func generateDeal(d *DistKeyGenerator) (*Deal, error) {
+	d.deal = nil
+	return d.Deal()
+}
+
+func TestDKGThresholdBLS(t *testing.T) {
+	s := pairing.NewSuiteBn256()
+	n, th := 5, 3
+	_, dkgs := generate(s, n, th)
+	require.Empty(t, broadcast(t, dkgs, deals(t, dkgs)))
+	dkss := checkShares(t, s, dkgs, th)
+
+	// threshold BLS signature with the distributed key
+	msg := []byte("Hello non-interactive DKG")
+	pubPoly := share.NewPubPoly(s, nil, dkss[0].Commits)
+	sigs := make([][]byte, 0, th)
+	for _, dks := range dkss[n-th:] {
+		sig, err := tbls.Sign(s, dks.Share, msg)
+		require.NoError(t, err)
+		sigs = append(sigs, sig)
+	}
+	sig, err := tbls.Recover(s, pubPoly, msg, sigs, th, n)
+	require.NoError(t, err)
+	require.NoError(t, bls.Verify(s, pubPoly.Commit(), msg, sig))
+}
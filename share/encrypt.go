@@ -0,0 +1,69 @@
+package share
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/encrypt/ecies"
+)
+
+// encryptedShareInfo binds the keys derived for the encrypted shares to this
+// use, so that an encrypted share cannot be passed off as another ECIES
+// ciphertext for the same recipient, and conversely.
+var encryptedShareInfo = []byte("kyber encrypted private share v1")
+
+// EncryptPriShare encrypts the private share to the owner of the public key,
+// e.g. to back it up or to move it to another machine, with ECIES over the
+// group g (see package encrypt/ecies). The ephemeral key of ECIES is picked
+// from rand, or from random.New() if rand is nil. The index of the share is
+// sent in the clear at the beginning of the returned blob, but it is
+// authenticated as the associated data of the AEAD, so that it cannot be
+// changed without DecryptPriShare failing.
+func EncryptPriShare(g kyber.Group, s *PriShare, public kyber.Point, rand cipher.Stream) ([]byte, error) {
+	if s == nil || s.V == nil {
+		return nil, errors.New("share: nil share")
+	}
+	if s.I < 0 || int64(s.I) > math.MaxUint32 {
+		return nil, fmt.Errorf("share: index %d out of range", s.I)
+	}
+	index := make([]byte, 4)
+	binary.BigEndian.PutUint32(index, uint32(s.I))
+	v, err := s.V.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := ecies.EncryptWithOptions(g, public, v,
+		ecies.WithKDFInfo(encryptedShareInfo),
+		ecies.WithAssociatedData(index),
+		ecies.WithEphemeralRand(rand))
+	if err != nil {
+		return nil, err
+	}
+	return append(index, ciphertext...), nil
+}
+
+// DecryptPriShare decrypts the private share encrypted by EncryptPriShare with
+// the private key of the recipient. It returns an error if the key is not the
+// one of the recipient, or if the blob has been tampered with, including its
+// index.
+func DecryptPriShare(g kyber.Group, blob []byte, private kyber.Scalar) (*PriShare, error) {
+	if len(blob) < 4 {
+		return nil, errors.New("share: encrypted share too short")
+	}
+	index := blob[:4]
+	v, err := ecies.DecryptWithOptions(g, private, blob[4:],
+		ecies.WithKDFInfo(encryptedShareInfo),
+		ecies.WithAssociatedData(index))
+	if err != nil {
+		return nil, fmt.Errorf("share: cannot decrypt share: %v", err)
+	}
+	s := g.Scalar()
+	if err := s.UnmarshalBinary(v); err != nil {
+		return nil, fmt.Errorf("share: invalid decrypted share: %v", err)
+	}
+	return &PriShare{I: int(binary.BigEndian.Uint32(index)), V: s}, nil
+}
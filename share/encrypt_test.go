@@ -0,0 +1,58 @@
+package share
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/pairing/bn256"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+func TestEncryptPriShare(test *testing.T) {
+	for _, g := range []kyber.Group{
+		edwards25519.NewBlakeSHA256Ed25519(),
+		bn256.NewSuiteG1(),
+	} {
+		test.Run(g.String(), func(test *testing.T) {
+			shares := NewPriPoly(g, 3, nil, random.New()).Shares(5)
+			private := g.Scalar().Pick(random.New())
+			public := g.Point().Mul(private, nil)
+
+			blob, err := EncryptPriShare(g, shares[2], public, nil)
+			require.NoError(test, err)
+			s, err := DecryptPriShare(g, blob, private)
+			require.NoError(test, err)
+			require.Equal(test, shares[2].I, s.I)
+			require.True(test, shares[2].V.Equal(s.V))
+
+			// wrong key
+			_, err = DecryptPriShare(g, blob, g.Scalar().Pick(random.New()))
+			require.Error(test, err)
+
+			// the index is authenticated
+			tampered := append([]byte{}, blob...)
+			tampered[3] ^= 1
+			_, err = DecryptPriShare(g, tampered, private)
+			require.Error(test, err)
+
+			// the indices cannot be swapped between blobs
+			other, err := EncryptPriShare(g, shares[3], public, nil)
+			require.NoError(test, err)
+			swapped := append(append([]byte{}, other[:4]...), blob[4:]...)
+			_, err = DecryptPriShare(g, swapped, private)
+			require.Error(test, err)
+
+			tampered = append([]byte{}, blob...)
+			tampered[len(tampered)-1] ^= 1
+			_, err = DecryptPriShare(g, tampered, private)
+			require.Error(test, err)
+			_, err = DecryptPriShare(g, blob[:3], private)
+			require.Error(test, err)
+
+			_, err = EncryptPriShare(g, &PriShare{I: -1, V: shares[0].V}, public, nil)
+			require.Error(test, err)
+		})
+	}
+}
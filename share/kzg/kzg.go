@@ -0,0 +1,332 @@
+// Package kzg implements the polynomial commitments of "Constant-Size
+// Commitments to Polynomials and Their Applications" by Kate, Zaverucha and
+// Goldberg, on top of a pairing. The commitment to a polynomial of the share
+// package is a single point of G1, whatever its degree, and the proof that a
+// share, or any evaluation of the polynomial, is correct is a single point of
+// G1 as well: a dealer of a VSS or a DKG can then publish one commitment
+// instead of the t commitments of share.PubPoly, and prove each share with a
+// constant size proof.
+//
+// The commitments rely on a structured reference string (SRS) made of the
+// powers of a secret τ in G1 and G2, which nobody must know: it is the output
+// of a trusted setup, e.g. a multi-party ceremony, loaded with NewSRS. Setup
+// generates one locally for tests and for the applications trusting the
+// party running it.
+//
+// The share of index i of a polynomial is its evaluation at i+1, as in the
+// share package, see OpenShare and VerifyShare.
+package kzg
+
+import (
+	"crypto/cipher"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/share"
+	"go.dedis.ch/kyber/v3/util/wipe"
+)
+
+// ErrInvalidProof is returned when an opening proof does not verify.
+var ErrInvalidProof = errors.New("kzg: invalid opening proof")
+
+// ErrDegree is returned for a polynomial or a batch opening too large for the
+// SRS.
+var ErrDegree = errors.New("kzg: degree too large for the reference string")
+
+// SRS is the structured reference string of the commitments: the points
+// τ^i·B1 of G1 and τ^i·B2 of G2 for the base points B1 and B2 and a secret τ.
+// The points of G1 bound the degree of the polynomials which can be committed
+// to, and the ones of G2 the number of evaluations which can be opened at
+// once with BatchOpen.
+type SRS struct {
+	suite pairing.Suite
+	g1    []kyber.Point
+	g2    []kyber.Point
+}
+
+// Setup returns a new SRS for the polynomials of degree at most degree, from
+// a secret τ picked from rand and erased before Setup returns. Whoever runs
+// it could forge the opening proofs if they kept τ, so that the SRS of an
+// application must come from a trusted setup otherwise. The degree is at
+// least 1.
+func Setup(suite pairing.Suite, degree int, rand cipher.Stream) *SRS {
+	if degree < 1 {
+		degree = 1
+	}
+	tau := suite.G1().Scalar().Pick(rand)
+	defer wipe.Scalar(tau)
+	s := &SRS{suite: suite}
+	pow := suite.G1().Scalar().One()
+	defer wipe.Scalar(pow)
+	for i := 0; i <= degree; i++ {
+		s.g1 = append(s.g1, suite.G1().Point().Mul(pow, nil))
+		s.g2 = append(s.g2, suite.G2().Point().Mul(pow, nil))
+		pow.Mul(pow, tau)
+	}
+	return s
+}
+
+// NewSRS returns the SRS of the points τ^i·B1 of g1 and τ^i·B2 of g2, e.g.
+// the output of a ceremony. It checks with pairings that the points are
+// consecutive powers of a same τ, and returns an error otherwise. Both lists
+// must hold at least two points.
+func NewSRS(suite pairing.Suite, g1, g2 []kyber.Point) (*SRS, error) {
+	if len(g1) < 2 || len(g2) < 2 {
+		return nil, errors.New("kzg: reference string too short")
+	}
+	if !g1[0].Equal(suite.G1().Point().Base()) || !g2[0].Equal(suite.G2().Point().Base()) {
+		return nil, errors.New("kzg: reference string with another base point")
+	}
+	// e(τ^(i+1)·B1, B2) == e(τ^i·B1, τ·B2) and e(B1, τ^(i+1)·B2) == e(τ·B1, τ^i·B2)
+	for i := 1; i < len(g1); i++ {
+		if !suite.Pair(g1[i], g2[0]).Equal(suite.Pair(g1[i-1], g2[1])) {
+			return nil, fmt.Errorf("kzg: inconsistent point %d of G1", i)
+		}
+	}
+	for i := 1; i < len(g2); i++ {
+		if !suite.Pair(g1[0], g2[i]).Equal(suite.Pair(g1[1], g2[i-1])) {
+			return nil, fmt.Errorf("kzg: inconsistent point %d of G2", i)
+		}
+	}
+	return &SRS{suite: suite, g1: g1, g2: g2}, nil
+}
+
+// Degree returns the maximum degree of the polynomials the SRS commits to.
+func (s *SRS) Degree() int {
+	return len(s.g1) - 1
+}
+
+// Points returns the points of the SRS in G1 and G2, to be stored or sent
+// and loaded with NewSRS.
+func (s *SRS) Points() (g1, g2 []kyber.Point) {
+	return s.g1, s.g2
+}
+
+// Commit returns the commitment p(τ)·B1 to the polynomial. It returns
+// ErrDegree if the degree of the polynomial is larger than the one of the
+// SRS.
+func (s *SRS) Commit(p *share.PriPoly) (kyber.Point, error) {
+	return s.commit(p.Coefficients())
+}
+
+// Open returns the evaluation y = p(x) of the polynomial and the proof of
+// the evaluation, which is the commitment to the quotient
+// (p(X) - y) / (X - x).
+func (s *SRS) Open(p *share.PriPoly, x kyber.Scalar) (kyber.Scalar, kyber.Point, error) {
+	coeffs := p.Coefficients()
+	y := eval(s.suite.G1(), coeffs, x)
+	proof, err := s.commit(divLinear(s.suite.G1(), coeffs, x))
+	if err != nil {
+		return nil, nil, err
+	}
+	return y, proof, nil
+}
+
+// Verify checks the proof that the polynomial of the commitment evaluates to
+// y at x, i.e. that e(proof, τ·B2 - x·B2) == e(commit - y·B1, B2). It returns
+// ErrInvalidProof if it does not.
+func (s *SRS) Verify(commit kyber.Point, x, y kyber.Scalar, proof kyber.Point) error {
+	g2 := s.suite.G2()
+	left := s.suite.Pair(proof, g2.Point().Sub(s.g2[1], g2.Point().Mul(x, nil)))
+	right := s.suite.Pair(s.suite.G1().Point().Sub(commit, s.suite.G1().Point().Mul(y, nil)), s.g2[0])
+	if !left.Equal(right) {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// OpenShare returns the share of index i of the polynomial, i.e. its
+// evaluation at i+1 as share.PriPoly.Eval, and the proof of the share.
+func (s *SRS) OpenShare(p *share.PriPoly, i int) (*share.PriShare, kyber.Point, error) {
+	y, proof, err := s.Open(p, s.shareX(i))
+	if err != nil {
+		return nil, nil, err
+	}
+	return &share.PriShare{I: i, V: y}, proof, nil
+}
+
+// VerifyShare checks the proof of the share of the polynomial of the
+// commitment.
+func (s *SRS) VerifyShare(commit kyber.Point, sh *share.PriShare, proof kyber.Point) error {
+	return s.Verify(commit, s.shareX(sh.I), sh.V, proof)
+}
+
+// BatchOpen returns the evaluations ys of the polynomial at the distinct
+// points xs and a single proof of all of them, which is the commitment to
+// the quotient (p(X) - I(X)) / Z(X), where I interpolates the evaluations
+// and Z vanishes at the points. It returns ErrDegree if there are more points
+// than the points of the SRS in G2, minus one.
+func (s *SRS) BatchOpen(p *share.PriPoly, xs []kyber.Scalar) ([]kyber.Scalar, kyber.Point, error) {
+	if len(xs) >= len(s.g2) {
+		return nil, nil, fmt.Errorf("%w: %d points for %d in G2", ErrDegree, len(xs), len(s.g2))
+	}
+	g := s.suite.G1()
+	coeffs := p.Coefficients()
+	ys := make([]kyber.Scalar, len(xs))
+	for i, x := range xs {
+		ys[i] = eval(g, coeffs, x)
+	}
+	// the quotient of p by Z is the one of p - I, whose degree is smaller
+	proof, err := s.commit(divPoly(g, coeffs, vanishing(g, xs)))
+	if err != nil {
+		return nil, nil, err
+	}
+	return ys, proof, nil
+}
+
+// BatchVerify checks the proof of BatchOpen that the polynomial of the
+// commitment evaluates to ys[i] at xs[i] for every i, i.e. that
+// e(proof, Z(τ)·B2) == e(commit - I(τ)·B1, B2). It returns ErrInvalidProof if
+// it does not.
+func (s *SRS) BatchVerify(commit kyber.Point, xs, ys []kyber.Scalar, proof kyber.Point) error {
+	if len(xs) != len(ys) {
+		return errors.New("kzg: different numbers of points and evaluations")
+	}
+	if len(xs) >= len(s.g2) {
+		return fmt.Errorf("%w: %d points for %d in G2", ErrDegree, len(xs), len(s.g2))
+	}
+	g := s.suite.G1()
+	I, err := interpolate(g, xs, ys)
+	if err != nil {
+		return err
+	}
+	Icommit, err := s.commit(I)
+	if err != nil {
+		return err
+	}
+	Z := s.suite.G2().Point().Null()
+	for i, c := range vanishing(g, xs) {
+		Z.Add(Z, s.suite.G2().Point().Mul(c, s.g2[i]))
+	}
+	left := s.suite.Pair(proof, Z)
+	right := s.suite.Pair(g.Point().Sub(commit, Icommit), s.g2[0])
+	if !left.Equal(right) {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// commit returns the commitment to the polynomial of the coefficients.
+func (s *SRS) commit(coeffs []kyber.Scalar) (kyber.Point, error) {
+	if len(coeffs) > len(s.g1) {
+		return nil, fmt.Errorf("%w: degree %d for %d", ErrDegree, len(coeffs)-1, s.Degree())
+	}
+	g := s.suite.G1()
+	C := g.Point().Null()
+	for i, c := range coeffs {
+		C.Add(C, g.Point().Mul(c, s.g1[i]))
+	}
+	return C, nil
+}
+
+// shareX returns the point of the share of index i.
+func (s *SRS) shareX(i int) kyber.Scalar {
+	return s.suite.G1().Scalar().SetInt64(1 + int64(i))
+}
+
+// eval returns the evaluation at x of the polynomial of the coefficients.
+func eval(g kyber.Group, coeffs []kyber.Scalar, x kyber.Scalar) kyber.Scalar {
+	v := g.Scalar().Zero()
+	for j := len(coeffs) - 1; j >= 0; j-- {
+		v.Mul(v, x)
+		v.Add(v, coeffs[j])
+	}
+	return v
+}
+
+// divLinear returns the quotient of the division of the polynomial of the
+// coefficients by X - x, with synthetic division.
+func divLinear(g kyber.Group, coeffs []kyber.Scalar, x kyber.Scalar) []kyber.Scalar {
+	if len(coeffs) < 2 {
+		return nil
+	}
+	q := make([]kyber.Scalar, len(coeffs)-1)
+	q[len(q)-1] = coeffs[len(coeffs)-1].Clone()
+	for i := len(q) - 1; i > 0; i-- {
+		q[i-1] = g.Scalar().Mul(q[i], x)
+		q[i-1].Add(q[i-1], coeffs[i])
+	}
+	return q
+}
+
+// divPoly returns the quotient of the division of the polynomial num by the
+// monic polynomial den. As the remainder has a degree smaller than the one of
+// den, it is also the quotient of num minus any such polynomial by den.
+func divPoly(g kyber.Group, num, den []kyber.Scalar) []kyber.Scalar {
+	d := len(den) - 1
+	if len(num) <= d {
+		return nil
+	}
+	r := make([]kyber.Scalar, len(num))
+	for i, c := range num {
+		r[i] = c.Clone()
+	}
+	q := make([]kyber.Scalar, len(num)-d)
+	tmp := g.Scalar()
+	for i := len(q) - 1; i >= 0; i-- {
+		q[i] = r[i+d].Clone()
+		for j := 0; j <= d; j++ {
+			r[i+j].Sub(r[i+j], tmp.Mul(q[i], den[j]))
+		}
+	}
+	return q
+}
+
+// vanishing returns the coefficients of the monic polynomial whose roots are
+// the points xs.
+func vanishing(g kyber.Group, xs []kyber.Scalar) []kyber.Scalar {
+	z := []kyber.Scalar{g.Scalar().One()}
+	for _, x := range xs {
+		z = mulLinear(g, z, x)
+	}
+	return z
+}
+
+// mulLinear returns the product of the polynomial of the coefficients by
+// X - x.
+func mulLinear(g kyber.Group, coeffs []kyber.Scalar, x kyber.Scalar) []kyber.Scalar {
+	r := make([]kyber.Scalar, len(coeffs)+1)
+	r[len(coeffs)] = g.Scalar().Zero()
+	for i := range coeffs {
+		r[i] = g.Scalar().Zero()
+	}
+	tmp := g.Scalar()
+	for i, c := range coeffs {
+		r[i+1].Add(r[i+1], c)
+		r[i].Sub(r[i], tmp.Mul(c, x))
+	}
+	return r
+}
+
+// interpolate returns the coefficients of the polynomial of degree less than
+// len(xs) evaluating to ys[i] at xs[i], with the Lagrange basis. It returns
+// an error if two points are equal.
+func interpolate(g kyber.Group, xs, ys []kyber.Scalar) ([]kyber.Scalar, error) {
+	res := make([]kyber.Scalar, len(xs))
+	for i := range res {
+		res[i] = g.Scalar().Zero()
+	}
+	tmp := g.Scalar()
+	for i := range xs {
+		basis := []kyber.Scalar{g.Scalar().One()}
+		denom := g.Scalar().One()
+		for j := range xs {
+			if i == j {
+				continue
+			}
+			basis = mulLinear(g, basis, xs[j])
+			d := g.Scalar().Sub(xs[i], xs[j])
+			if d.Equal(g.Scalar().Zero()) {
+				return nil, errors.New("kzg: duplicate evaluation point")
+			}
+			denom.Mul(denom, d)
+		}
+		f := g.Scalar().Div(ys[i], denom)
+		for k, c := range basis {
+			res[k].Add(res[k], tmp.Mul(c, f))
+		}
+	}
+	return res, nil
+}
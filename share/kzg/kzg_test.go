@@ -0,0 +1,120 @@
+package kzg
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing/bn256"
+	"go.dedis.ch/kyber/v3/share"
+)
+
+var suite = bn256.NewSuite()
+
+func TestCommitOpen(t *testing.T) {
+	th := 4
+	srs := Setup(suite, th-1, suite.RandomStream())
+	require.Equal(t, th-1, srs.Degree())
+	poly := share.NewPriPoly(suite.G1(), th, nil, suite.RandomStream())
+	C, err := srs.Commit(poly)
+	require.NoError(t, err)
+
+	x := suite.G1().Scalar().Pick(suite.RandomStream())
+	y, proof, err := srs.Open(poly, x)
+	require.NoError(t, err)
+	require.NoError(t, srs.Verify(C, x, y, proof))
+
+	wrong := suite.G1().Scalar().Add(y, suite.G1().Scalar().One())
+	require.True(t, errors.Is(srs.Verify(C, x, wrong, proof), ErrInvalidProof))
+	require.True(t, errors.Is(srs.Verify(C, wrong, y, proof), ErrInvalidProof))
+	other, otherProof, err := srs.Open(poly, wrong)
+	require.NoError(t, err)
+	require.True(t, errors.Is(srs.Verify(C, x, y, otherProof), ErrInvalidProof))
+	require.NoError(t, srs.Verify(C, wrong, other, otherProof))
+
+	// a polynomial of a larger degree cannot be committed to
+	_, err = srs.Commit(share.NewPriPoly(suite.G1(), th+1, nil, suite.RandomStream()))
+	require.True(t, errors.Is(err, ErrDegree))
+}
+
+func TestShares(t *testing.T) {
+	n, th := 7, 4
+	srs := Setup(suite, th-1, suite.RandomStream())
+	poly := share.NewPriPoly(suite.G1(), th, nil, suite.RandomStream())
+	C, err := srs.Commit(poly)
+	require.NoError(t, err)
+
+	var shares []*share.PriShare
+	for i := 0; i < n; i++ {
+		sh, proof, err := srs.OpenShare(poly, i)
+		require.NoError(t, err)
+		require.True(t, poly.Eval(i).V.Equal(sh.V))
+		require.NoError(t, srs.VerifyShare(C, sh, proof))
+		require.Error(t, srs.VerifyShare(C, &share.PriShare{I: i + 1, V: sh.V}, proof))
+		shares = append(shares, sh)
+	}
+	secret, err := share.RecoverSecret(suite.G1(), shares, th, n)
+	require.NoError(t, err)
+	require.True(t, poly.Secret().Equal(secret))
+}
+
+func TestBatchOpen(t *testing.T) {
+	th := 5
+	srs := Setup(suite, th-1, suite.RandomStream())
+	poly := share.NewPriPoly(suite.G1(), th, nil, suite.RandomStream())
+	C, err := srs.Commit(poly)
+	require.NoError(t, err)
+
+	for k := 1; k <= th-1; k++ {
+		xs := make([]kyber.Scalar, k)
+		for i := range xs {
+			xs[i] = suite.G1().Scalar().SetInt64(int64(3*i + 1))
+		}
+		ys, proof, err := srs.BatchOpen(poly, xs)
+		require.NoError(t, err)
+		for i := range xs {
+			y, _, err := srs.Open(poly, xs[i])
+			require.NoError(t, err)
+			require.True(t, y.Equal(ys[i]))
+		}
+		require.NoError(t, srs.BatchVerify(C, xs, ys, proof))
+
+		ys[0] = suite.G1().Scalar().Add(ys[0], suite.G1().Scalar().One())
+		require.True(t, errors.Is(srs.BatchVerify(C, xs, ys, proof), ErrInvalidProof))
+		require.Error(t, srs.BatchVerify(C, xs, ys[1:], proof))
+	}
+
+	// duplicate points
+	xs := []kyber.Scalar{suite.G1().Scalar().One(), suite.G1().Scalar().One()}
+	ys, proof, err := srs.BatchOpen(poly, xs)
+	require.NoError(t, err)
+	require.Error(t, srs.BatchVerify(C, xs, ys, proof))
+
+	// too many points for the SRS
+	xs = make([]kyber.Scalar, th)
+	for i := range xs {
+		xs[i] = suite.G1().Scalar().SetInt64(int64(i))
+	}
+	_, _, err = srs.BatchOpen(poly, xs)
+	require.True(t, errors.Is(err, ErrDegree))
+}
+
+func TestNewSRS(t *testing.T) {
+	g1, g2 := Setup(suite, 3, suite.RandomStream()).Points()
+	srs, err := NewSRS(suite, g1, g2)
+	require.NoError(t, err)
+	require.Equal(t, 3, srs.Degree())
+
+	other, _ := Setup(suite, 3, suite.RandomStream()).Points()
+	_, err = NewSRS(suite, append([]kyber.Point{}, g1[:3]...), g2)
+	require.NoError(t, err)
+	_, err = NewSRS(suite, append(append([]kyber.Point{}, g1[:3]...), other[3]), g2)
+	require.Error(t, err)
+	_, err = NewSRS(suite, g1, append(append([]kyber.Point{}, g2[:2]...), g2[3], g2[2]))
+	require.Error(t, err)
+	_, err = NewSRS(suite, g1[1:], g2)
+	require.Error(t, err)
+	_, err = NewSRS(suite, g1[:1], g2)
+	require.Error(t, err)
+}
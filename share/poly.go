@@ -17,8 +17,11 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
 
 	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/util/msm"
+	"go.dedis.ch/kyber/v3/util/wipe"
 )
 
 // Some error definitions
@@ -43,6 +46,12 @@ func (p *PriShare) String() string {
 	return fmt.Sprintf("{%d:%s}", p.I, p.V)
 }
 
+// Zero erases the value of the share once it is no longer needed, see
+// wipe.Scalar. The share keeps its index and is then equal to zero.
+func (p *PriShare) Zero() {
+	wipe.Scalar(p.V)
+}
+
 // PriPoly represents a secret sharing polynomial.
 type PriPoly struct {
 	g      kyber.Group    // Cryptographic group
@@ -143,7 +152,17 @@ func (p *PriPoly) Commit(b kyber.Point) *PubPoly {
 	for i := range commits {
 		commits[i] = p.g.Point().Mul(p.coeffs[i], b)
 	}
-	return &PubPoly{p.g, b, commits}
+	return NewPubPoly(p.g, b, commits)
+}
+
+// CommitmentsFromPriPoly returns the commitments of the coefficients of the
+// polynomial with the standard base point, i.e. the coefficients of
+// p.Commit(nil), e.g. for a trusted dealer to publish along with the shares of
+// p. The commitment of the secret, the first one, is the public key of the
+// shared secret.
+func CommitmentsFromPriPoly(p *PriPoly) []kyber.Point {
+	_, commits := p.Commit(nil).Info()
+	return commits
 }
 
 // Mul multiples p and q together. The result is a polynomial of the sum of
@@ -159,15 +178,25 @@ func (p *PriPoly) Mul(q *PriPoly) *PriPoly {
 	for i := range coeffs {
 		coeffs[i] = p.g.Scalar().Zero()
 	}
+	tmp := p.g.Scalar()
 	for i := range p.coeffs {
 		for j := range q.coeffs {
-			tmp := p.g.Scalar().Mul(p.coeffs[i], q.coeffs[j])
-			coeffs[i+j] = tmp.Add(coeffs[i+j], tmp)
+			tmp.Mul(p.coeffs[i], q.coeffs[j])
+			coeffs[i+j].Add(coeffs[i+j], tmp)
 		}
 	}
 	return &PriPoly{p.g, coeffs}
 }
 
+// Zero erases the coefficients of the polynomial once it is no longer needed,
+// see wipe.Scalar. The polynomial is then the zero polynomial: its secret and
+// all its shares are zero. The secret given to NewPriPoly and the scalars
+// given to CoefficientsToPriPoly or returned by Coefficients are erased as
+// well, since the polynomial shares them.
+func (p *PriPoly) Zero() {
+	wipe.Scalars(p.coeffs)
+}
+
 // Coefficients return the list of coefficients representing p. This
 // information is generally PRIVATE and should not be revealed to a third party
 // lightly.
@@ -178,28 +207,25 @@ func (p *PriPoly) Coefficients() []kyber.Scalar {
 // RecoverSecret reconstructs the shared secret p(0) from a list of private
 // shares using Lagrange interpolation.
 func RecoverSecret(g kyber.Group, shares []*PriShare, t, n int) (kyber.Scalar, error) {
-	x, y := xyScalar(g, shares, t, n)
-	if len(x) < t {
+	s := getScratch()
+	defer putScratch(s)
+	for pos, share := range shares {
+		if share != nil && share.V != nil && share.I >= 0 {
+			s.add(pos, share.I)
+		}
+	}
+	if s.selectShares(t) < t {
 		return nil, errors.New("share: not enough shares to recover secret")
 	}
 
 	acc := g.Scalar().Zero()
-	num := g.Scalar()
+	c := g.Scalar()
 	den := g.Scalar()
 	tmp := g.Scalar()
 
-	for i, xi := range x {
-		yi := y[i]
-		num.Set(yi)
-		den.One()
-		for j, xj := range x {
-			if i == j {
-				continue
-			}
-			num.Mul(num, xj)
-			den.Mul(den, tmp.Sub(xj, xi))
-		}
-		acc.Add(acc, num.Div(num, den))
+	for i, pos := range s.pos {
+		s.lagrange(c, den, tmp, i)
+		acc.Add(acc, c.Mul(c, shares[pos].V))
 	}
 
 	return acc, nil
@@ -313,11 +339,24 @@ type PubPoly struct {
 	g       kyber.Group   // Cryptographic group
 	b       kyber.Point   // Base point, nil for standard base
 	commits []kyber.Point // Commitments to coefficients of the secret sharing polynomial
+	pows    *sync.Pool    // Scratch powers of the evaluation point, see Eval
 }
 
 // NewPubPoly creates a new public commitment polynomial.
 func NewPubPoly(g kyber.Group, b kyber.Point, commits []kyber.Point) *PubPoly {
-	return &PubPoly{g, b, commits}
+	p := &PubPoly{g: g, b: b, commits: commits}
+	// the powers are scalars of the group of the polynomial, so that they
+	// are recycled by polynomial rather than through a global pool
+	p.pows = &sync.Pool{
+		New: func() interface{} {
+			pows := make([]kyber.Scalar, len(commits))
+			for i := range pows {
+				pows[i] = g.Scalar()
+			}
+			return &pows
+		},
+	}
+	return p
 }
 
 // Info returns the base point and the commitments to the polynomial coefficients.
@@ -325,6 +364,11 @@ func (p *PubPoly) Info() (base kyber.Point, commits []kyber.Point) {
 	return p.b, p.commits
 }
 
+// Group returns the group of the commitments.
+func (p *PubPoly) Group() kyber.Group {
+	return p.g
+}
+
 // Threshold returns the secret sharing threshold.
 func (p *PubPoly) Threshold() int {
 	return len(p.commits)
@@ -338,6 +382,19 @@ func (p *PubPoly) Commit() kyber.Point {
 // Eval computes the public share v = p(i).
 func (p *PubPoly) Eval(i int) *PubShare {
 	xi := p.g.Scalar().SetInt64(1 + int64(i)) // x-coordinate of this share
+	if p.Threshold() >= msm.MinPoints && p.pows != nil {
+		// sum_j xi^j * commits[j], as a multi-scalar multiplication
+		buf := p.pows.Get().(*[]kyber.Scalar)
+		defer p.pows.Put(buf)
+		pows := *buf
+		pows[0].One()
+		for j := 1; j < len(pows); j++ {
+			pows[j].Mul(pows[j-1], xi)
+		}
+		if v, err := msm.MultiScalarMult(p.g, p.commits, pows); err == nil {
+			return &PubShare{i, v}
+		}
+	}
 	v := p.g.Point().Null()
 	for j := p.Threshold() - 1; j >= 0; j-- {
 		v.Mul(xi, v)
@@ -375,7 +432,7 @@ func (p *PubPoly) Add(q *PubPoly) (*PubPoly, error) {
 		commits[i] = p.g.Point().Add(p.commits[i], q.commits[i])
 	}
 
-	return &PubPoly{p.g, p.b, commits}, nil
+	return NewPubPoly(p.g, p.b, commits), nil
 }
 
 // Equal checks equality of two public commitment polynomials p and q. If p and
@@ -441,32 +498,51 @@ func xyCommit(g kyber.Group, shares []*PubShare, t, n int) (map[int]kyber.Scalar
 // RecoverCommit reconstructs the secret commitment p(0) from a list of public
 // shares using Lagrange interpolation.
 func RecoverCommit(g kyber.Group, shares []*PubShare, t, n int) (kyber.Point, error) {
-	x, y := xyCommit(g, shares, t, n)
-	if len(x) < t {
+	return RecoverCommitInto(g.Point(), g, shares, t, n)
+}
+
+// RecoverCommitInto works like RecoverCommit but writes the secret commitment
+// into dst, which is returned, instead of allocating a new point. dst must not
+// be the value of one of the shares.
+func RecoverCommitInto(dst kyber.Point, g kyber.Group, shares []*PubShare, t, n int) (kyber.Point, error) {
+	s := getScratch()
+	defer putScratch(s)
+	for pos, share := range shares {
+		if share != nil && share.V != nil && share.I >= 0 {
+			s.add(pos, share.I)
+		}
+	}
+	if s.selectShares(t) < t {
 		return nil, errors.New("share: not enough good public shares to reconstruct secret commitment")
 	}
 
-	num := g.Scalar()
 	den := g.Scalar()
 	tmp := g.Scalar()
-	Acc := g.Point().Null()
+	if len(s.pos) >= msm.MinPoints {
+		// the commitments and the coefficients are public
+		coeffs := make([]kyber.Scalar, len(s.pos))
+		points := make([]kyber.Point, len(s.pos))
+		for i, pos := range s.pos {
+			coeffs[i] = s.lagrange(g.Scalar(), den, tmp, i)
+			points[i] = shares[pos].V
+		}
+		res, err := msm.MultiScalarMult(g, points, coeffs)
+		if err != nil {
+			return nil, err
+		}
+		return dst.Set(res), nil
+	}
+
+	c := g.Scalar()
 	Tmp := g.Point()
+	dst.Null()
 
-	for i, xi := range x {
-		num.One()
-		den.One()
-		for j, xj := range x {
-			if i == j {
-				continue
-			}
-			num.Mul(num, xj)
-			den.Mul(den, tmp.Sub(xj, xi))
-		}
-		Tmp.Mul(num.Div(num, den), y[i])
-		Acc.Add(Acc, Tmp)
+	for i, pos := range s.pos {
+		s.lagrange(c, den, tmp, i)
+		dst.Add(dst, Tmp.Mul(c, shares[pos].V))
 	}
 
-	return Acc, nil
+	return dst, nil
 }
 
 // RecoverPubPoly reconstructs the full public polynomial from a set of public
@@ -137,6 +137,21 @@ func TestPublicCheck(test *testing.T) {
 	}
 }
 
+func TestCommitmentsFromPriPoly(test *testing.T) {
+	g := edwards25519.NewBlakeSHA256Ed25519()
+	n := 10
+	t := n/2 + 1
+
+	priPoly := NewPriPoly(g, t, nil, g.RandomStream())
+	commits := CommitmentsFromPriPoly(priPoly)
+	require.Len(test, commits, t)
+	require.True(test, commits[0].Equal(g.Point().Mul(priPoly.Secret(), nil)))
+	pubPoly := NewPubPoly(g, nil, commits)
+	for _, share := range priPoly.Shares(n) {
+		require.True(test, pubPoly.Check(share))
+	}
+}
+
 func TestPublicRecovery(test *testing.T) {
 	g := edwards25519.NewBlakeSHA256Ed25519()
 	n := 10
@@ -163,6 +178,23 @@ func TestPublicRecovery(test *testing.T) {
 	require.True(test, pubPoly.Equal(polyRecovered))
 }
 
+func TestPublicRecoveryLarge(test *testing.T) {
+	// thresholds from msm.MinPoints use multi-scalar multiplications
+	g := edwards25519.NewBlakeSHA256Ed25519()
+	n := 50
+	t := n/2 + 1
+
+	priPoly := NewPriPoly(g, t, nil, g.RandomStream())
+	pubPoly := priPoly.Commit(nil)
+	for _, share := range priPoly.Shares(n) {
+		require.True(test, pubPoly.Check(share))
+	}
+
+	recovered, err := RecoverCommit(g, pubPoly.Shares(n), t, n)
+	require.NoError(test, err)
+	require.True(test, recovered.Equal(pubPoly.Commit()))
+}
+
 func TestPublicRecoveryOutIndex(test *testing.T) {
 	g := edwards25519.NewBlakeSHA256Ed25519()
 	n := 10
@@ -387,6 +419,33 @@ func TestPriPolyCoefficients(test *testing.T) {
 
 }
 
+func TestPriPolyZero(test *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	n := 10
+	t := n/2 + 1
+	secret := suite.Scalar().Pick(suite.RandomStream())
+	poly := NewPriPoly(suite, t, secret, suite.RandomStream())
+	shares := poly.Shares(n)
+
+	poly.Zero()
+	zero := suite.Scalar().Zero()
+	for _, c := range poly.Coefficients() {
+		require.True(test, c.Equal(zero))
+	}
+	require.True(test, secret.Equal(zero))
+	require.True(test, poly.Secret().Equal(zero))
+	require.True(test, poly.Eval(3).V.Equal(zero))
+	// the shares are not tied to the polynomial
+	require.False(test, shares[3].V.Equal(zero))
+
+	shares[3].Zero()
+	require.Equal(test, 3, shares[3].I)
+	require.True(test, shares[3].V.Equal(zero))
+	recovered, err := RecoverSecret(suite, shares, t, n)
+	require.NoError(test, err)
+	require.False(test, recovered.Equal(zero))
+}
+
 func TestRefreshDKG(test *testing.T) {
 	g := edwards25519.NewBlakeSHA256Ed25519()
 	n := 10
@@ -0,0 +1,242 @@
+package pvss
+
+import (
+	"errors"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/proof/dleq"
+	"go.dedis.ch/kyber/v3/share"
+)
+
+// The functions below turn PVSS into a one-round non-interactive distributed
+// key generation (DKG):
+//  1. Every dealer publishes a Dealing created with NewDealing. A Dealing is a
+//     PVSS instance of a random secret with respect to the base point of the
+//     suite, so that its commitments form the public polynomial of the
+//     secret, together with an encryption of the scalar shares to the
+//     participants.
+//  2. Anyone can check the dealings offline with VerifyDealing. Each
+//     participant decrypts its scalar share of every valid dealing with
+//     DecryptShare, which checks it against the public polynomial. If a
+//     dealer sent a wrong scalar share, the participant publishes a Complaint
+//     created with NewComplaint that anyone can check with VerifyComplaint.
+//  3. AggregateDealings sums the qualified dealings, i.e. the valid ones
+//     without a valid complaint, into the distributed public polynomial and
+//     AggregateShare computes the share of the distributed secret of a
+//     participant. All participants must use the same lists of dealings and
+//     complaints to compute the same distributed key.
+
+var errorDealing = errors.New("invalid dealing")
+var errorComplaint = errors.New("invalid complaint")
+var errorIndex = errors.New("index out of bounds")
+var errorScalarShare = errors.New("scalar share does not match commitments")
+var errorTooFewDealings = errors.New("not enough qualified dealings")
+
+// Dealing is the public transcript of one dealer of the non-interactive DKG.
+type Dealing struct {
+	// Coefficients of the public polynomial of the dealer's secret. The first
+	// coefficient is the contribution of the dealer to the distributed public
+	// key.
+	Commits []kyber.Point
+	// PVSS encrypted shares s(i)*X_i with their encryption consistency proofs.
+	EncShares []*PubVerShare
+	// Ephemeral Diffie-Hellman key R = r*G of the scalar shares encryption.
+	R kyber.Point
+	// Scalar shares s(i) masked with a pad derived from r*X_i.
+	Masked []kyber.Scalar
+}
+
+// Complaint is published by a participant whose scalar share of a dealing
+// does not match the public polynomial of the dealer. It reveals the
+// Diffie-Hellman key x*R of the participant with a proof of its correctness
+// so that anyone can check the claim.
+type Complaint struct {
+	Dealer int         // Index of the dealing in the list of dealings
+	Index  int         // Index of the complaining participant
+	K      kyber.Point // Diffie-Hellman key x*R
+	P      dleq.Proof  // Proof that log_G(X) == log_R(K)
+}
+
+// NewDealing creates the dealing of the given secret among the participants
+// having the public keys X using the sharing threshold t. If the secret is
+// nil, a random one is picked, as required by the DKG.
+func NewDealing(suite Suite, X []kyber.Point, secret kyber.Scalar, t int) (*Dealing, error) {
+	G := suite.Point().Base()
+	priPoly := share.NewPriPoly(suite, t, secret, suite.RandomStream())
+	encShares, pubPoly, err := encShares(suite, G, X, priPoly)
+	if err != nil {
+		return nil, err
+	}
+	_, commits := pubPoly.Info()
+
+	r := suite.Scalar().Pick(suite.RandomStream())
+	R := suite.Point().Mul(r, nil)
+	masked := make([]kyber.Scalar, len(X))
+	for i, Xi := range X {
+		p, err := pad(suite, suite.Point().Mul(r, Xi), R, i)
+		if err != nil {
+			return nil, err
+		}
+		masked[i] = p.Add(p, priPoly.Eval(i).V)
+	}
+	return &Dealing{
+		Commits:   commits,
+		EncShares: encShares,
+		R:         R,
+		Masked:    masked,
+	}, nil
+}
+
+// VerifyDealing checks that the dealing is well formed for the participants
+// having the public keys X and the threshold t, and that every encrypted
+// share is consistent with the public polynomial of the dealer.
+func VerifyDealing(suite Suite, X []kyber.Point, d *Dealing, t int) error {
+	n := len(X)
+	if d == nil || d.R == nil || len(d.Commits) != t || len(d.EncShares) != n || len(d.Masked) != n {
+		return errorDealing
+	}
+	for _, c := range d.Commits {
+		if c == nil {
+			return errorDealing
+		}
+	}
+	G := suite.Point().Base()
+	pubPoly := share.NewPubPoly(suite, G, d.Commits)
+	for i, es := range d.EncShares {
+		if es == nil || es.S.I != i || es.S.V == nil || d.Masked[i] == nil {
+			return errorDealing
+		}
+		if err := VerifyEncShare(suite, G, X[i], pubPoly.Eval(i).V, es); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecryptShare decrypts the scalar share of the participant at index i
+// holding the private key x and checks it against the public polynomial of
+// the dealer. The dealing must have been verified with VerifyDealing.
+func DecryptShare(suite Suite, d *Dealing, i int, x kyber.Scalar) (*share.PriShare, error) {
+	if i < 0 || i >= len(d.Masked) {
+		return nil, errorIndex
+	}
+	return unmaskShare(suite, d, i, suite.Point().Mul(x, d.R))
+}
+
+// NewComplaint creates the complaint of the participant at index i holding
+// the private key x against the dealing d at index dealer in the list of
+// dealings.
+func NewComplaint(suite Suite, dealer int, d *Dealing, i int, x kyber.Scalar) (*Complaint, error) {
+	proof, _, K, err := dleq.NewDLEQProof(suite, suite.Point().Base(), d.R, x)
+	if err != nil {
+		return nil, err
+	}
+	return &Complaint{Dealer: dealer, Index: i, K: K, P: *proof}, nil
+}
+
+// VerifyComplaint returns nil if the complaint is valid, i.e. if the dealer
+// sent a scalar share to the complaining participant that does not match its
+// public polynomial. It returns an error otherwise.
+func VerifyComplaint(suite Suite, X []kyber.Point, dealings []*Dealing, c *Complaint) error {
+	if c.Dealer < 0 || c.Dealer >= len(dealings) || c.Index < 0 || c.Index >= len(X) {
+		return errorIndex
+	}
+	d := dealings[c.Dealer]
+	if d == nil || d.R == nil || c.Index >= len(d.Masked) {
+		return errorComplaint
+	}
+	if err := c.P.Verify(suite, suite.Point().Base(), d.R, X[c.Index], c.K); err != nil {
+		return errorComplaint
+	}
+	if _, err := unmaskShare(suite, d, c.Index, c.K); err != errorScalarShare {
+		return errorComplaint
+	}
+	return nil
+}
+
+// AggregateDealings verifies the dealings and the complaints and sums the
+// qualified dealings into the distributed public polynomial. It returns the
+// indices of the qualified dealings together with the distributed public
+// polynomial, or an error if less than t dealings are qualified.
+func AggregateDealings(suite Suite, X []kyber.Point, dealings []*Dealing, complaints []*Complaint, t int) ([]int, *share.PubPoly, error) {
+	disqualified := make(map[int]bool)
+	for _, c := range complaints {
+		if VerifyComplaint(suite, X, dealings, c) == nil {
+			disqualified[c.Dealer] = true
+		}
+	}
+
+	var qual []int
+	var pubPoly *share.PubPoly
+	for j, d := range dealings {
+		if disqualified[j] || VerifyDealing(suite, X, d, t) != nil {
+			continue
+		}
+		qual = append(qual, j)
+		poly := share.NewPubPoly(suite, suite.Point().Base(), d.Commits)
+		if pubPoly == nil {
+			pubPoly = poly
+			continue
+		}
+		var err error
+		if pubPoly, err = pubPoly.Add(poly); err != nil {
+			return nil, nil, err
+		}
+	}
+	if len(qual) < t {
+		return nil, nil, errorTooFewDealings
+	}
+	return qual, pubPoly, nil
+}
+
+// AggregateShare computes the share of the distributed secret of the
+// participant at index i holding the private key x by summing its scalar
+// shares of the qualified dealings returned by AggregateDealings.
+func AggregateShare(suite Suite, dealings []*Dealing, qual []int, i int, x kyber.Scalar) (*share.PriShare, error) {
+	sum := suite.Scalar().Zero()
+	for _, j := range qual {
+		if j < 0 || j >= len(dealings) {
+			return nil, errorIndex
+		}
+		s, err := DecryptShare(suite, dealings[j], i, x)
+		if err != nil {
+			return nil, err
+		}
+		sum.Add(sum, s.V)
+	}
+	return &share.PriShare{I: i, V: sum}, nil
+}
+
+// unmaskShare removes the pad derived from the Diffie-Hellman key K from the
+// scalar share at index i and checks the result against the public
+// polynomial of the dealing.
+func unmaskShare(suite Suite, d *Dealing, i int, K kyber.Point) (*share.PriShare, error) {
+	if d.Masked[i] == nil || len(d.Commits) == 0 {
+		return nil, errorDealing
+	}
+	p, err := pad(suite, K, d.R, i)
+	if err != nil {
+		return nil, err
+	}
+	s := &share.PriShare{I: i, V: p.Sub(d.Masked[i], p)}
+	pubPoly := share.NewPubPoly(suite, suite.Point().Base(), d.Commits)
+	if !pubPoly.Check(s) {
+		return nil, errorScalarShare
+	}
+	return s, nil
+}
+
+// pad derives the mask of the scalar share at index i from the
+// Diffie-Hellman key K and the ephemeral key R of the dealing.
+func pad(suite Suite, K, R kyber.Point, i int) (kyber.Scalar, error) {
+	h := suite.Hash()
+	_, _ = h.Write([]byte("pvss-dkg-pad"))
+	if _, err := K.MarshalTo(h); err != nil {
+		return nil, err
+	}
+	if _, err := R.MarshalTo(h); err != nil {
+		return nil, err
+	}
+	_, _ = h.Write([]byte{byte(i >> 24), byte(i >> 16), byte(i >> 8), byte(i)})
+	return suite.Scalar().Pick(suite.XOF(h.Sum(nil))), nil
+}
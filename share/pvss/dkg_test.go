@@ -0,0 +1,114 @@
+package pvss
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/share"
+	"go.dedis.ch/kyber/v3/sign/bls"
+	"go.dedis.ch/kyber/v3/sign/tbls"
+)
+
+func dkgKeys(suite Suite, n int) ([]kyber.Scalar, []kyber.Point) {
+	x := make([]kyber.Scalar, n)
+	X := make([]kyber.Point, n)
+	for i := 0; i < n; i++ {
+		x[i] = suite.Scalar().Pick(suite.RandomStream())
+		X[i] = suite.Point().Mul(x[i], nil)
+	}
+	return x, X
+}
+
+func TestDKGThresholdBLS(test *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	n := 7
+	t := n/2 + 1
+	x, X := dkgKeys(suite, n)
+
+	// (1) Every participant publishes a dealing
+	dealings := make([]*Dealing, n)
+	for j := range dealings {
+		d, err := NewDealing(suite, X, nil, t)
+		require.NoError(test, err)
+		require.NoError(test, VerifyDealing(suite, X, d, t))
+		dealings[j] = d
+	}
+
+	// (2) The last dealer sends a wrong scalar share to the first participant
+	bad := dealings[n-1]
+	bad.Masked[0] = suite.Scalar().Add(bad.Masked[0], suite.Scalar().One())
+	require.NoError(test, VerifyDealing(suite, X, bad, t))
+	_, err := DecryptShare(suite, bad, 0, x[0])
+	require.Error(test, err)
+	complaint, err := NewComplaint(suite, n-1, bad, 0, x[0])
+	require.NoError(test, err)
+	require.NoError(test, VerifyComplaint(suite, X, dealings, complaint))
+
+	// a complaint against an honest dealer is rejected
+	unfounded, err := NewComplaint(suite, 0, dealings[0], 1, x[1])
+	require.NoError(test, err)
+	require.Error(test, VerifyComplaint(suite, X, dealings, unfounded))
+	// so is a complaint with a wrong Diffie-Hellman key
+	forged := *complaint
+	forged.K = suite.Point().Pick(suite.RandomStream())
+	require.Error(test, VerifyComplaint(suite, X, dealings, &forged))
+
+	// (3) Aggregate the qualified dealings
+	complaints := []*Complaint{complaint, unfounded, &forged}
+	qual, pubPoly, err := AggregateDealings(suite, X, dealings, complaints, t)
+	require.NoError(test, err)
+	require.Len(test, qual, n-1)
+	require.NotContains(test, qual, n-1)
+
+	shares := make([]*share.PriShare, n)
+	for i := range shares {
+		shares[i], err = AggregateShare(suite, dealings, qual, i, x[i])
+		require.NoError(test, err)
+		require.True(test, pubPoly.Check(shares[i]))
+	}
+
+	// the distributed key is the sum of the qualified secrets
+	secret, err := share.RecoverSecret(suite, shares, t, n)
+	require.NoError(test, err)
+	require.True(test, suite.Point().Mul(secret, nil).Equal(pubPoly.Commit()))
+
+	// Threshold BLS signature with the distributed key
+	msg := []byte("Hello non-interactive DKG")
+	sigShares := make([][]byte, 0, t)
+	for _, s := range shares[n-t:] {
+		sig, err := tbls.Sign(suite, s, msg)
+		require.NoError(test, err)
+		sigShares = append(sigShares, sig)
+	}
+	sig, err := tbls.Recover(suite, pubPoly, msg, sigShares, t, n)
+	require.NoError(test, err)
+	require.NoError(test, bls.Verify(suite, pubPoly.Commit(), msg, sig))
+}
+
+func TestDKGInvalidDealing(test *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	n := 5
+	t := 3
+	_, X := dkgKeys(suite, n)
+
+	d, err := NewDealing(suite, X, nil, t)
+	require.NoError(test, err)
+	require.Error(test, VerifyDealing(suite, X, d, t+1))
+	require.Error(test, VerifyDealing(suite, X[1:], d, t))
+
+	// encrypted shares swapped between participants
+	d.EncShares[0], d.EncShares[1] = d.EncShares[1], d.EncShares[0]
+	require.Error(test, VerifyDealing(suite, X, d, t))
+	d.EncShares[0], d.EncShares[1] = d.EncShares[1], d.EncShares[0]
+	require.NoError(test, VerifyDealing(suite, X, d, t))
+
+	// tampered encrypted share
+	d.EncShares[2].S.V = suite.Point().Pick(suite.RandomStream())
+	require.Error(test, VerifyDealing(suite, X, d, t))
+
+	// invalid dealings are not qualified
+	_, _, err = AggregateDealings(suite, X, []*Dealing{d, d, d}, nil, t)
+	require.Error(test, err)
+}
@@ -10,6 +10,10 @@
 //  3. Once a threshold of decrypted shares has been released, anyone can
 //     verify them and, if enough shares are valid, recover the shared secret
 //     using RecoverSecret().
+//
+// The package also provides a one-round non-interactive distributed key
+// generation built on top of PVSS, see NewDealing() and AggregateDealings(),
+// which the share/dkg/pvss package runs over a broadcast channel.
 package pvss
 
 import (
@@ -47,11 +51,14 @@ type PubVerShare struct {
 // t and the base point H. The function returns the list of shares and the
 // public commitment polynomial.
 func EncShares(suite Suite, H kyber.Point, X []kyber.Point, secret kyber.Scalar, t int) (shares []*PubVerShare, commit *share.PubPoly, err error) {
-	n := len(X)
-	encShares := make([]*PubVerShare, n)
-
 	// Create secret sharing polynomial
 	priPoly := share.NewPriPoly(suite, t, secret, suite.RandomStream())
+	return encShares(suite, H, X, priPoly)
+}
+
+func encShares(suite Suite, H kyber.Point, X []kyber.Point, priPoly *share.PriPoly) ([]*PubVerShare, *share.PubPoly, error) {
+	n := len(X)
+	encShares := make([]*PubVerShare, n)
 
 	// Create secret set of shares
 	priShares := priPoly.Shares(n)
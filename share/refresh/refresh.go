@@ -0,0 +1,305 @@
+// Package refresh implements the proactive refresh of a Shamir secret sharing,
+// as described in "Proactive Secret Sharing Or: How to Cope With Perpetual
+// Leakage" by Herzberg et al. The shares of the nodes are re-randomized
+// without changing the shared secret nor its public key, so that the shares an
+// adversary stole before a refresh are useless together with the ones it steals
+// after it: a long-lived threshold key then resists the gradual compromise of
+// its nodes, as long as less than t of them are compromised between two
+// refreshes.
+//
+// A refresh is a round of zero-sharings: every node deals the shares of a
+// random polynomial of the threshold t whose constant term is zero, with the
+// verifiable secret sharing of the vss/pedersen package, and every node adds
+// the shares it received from the certified dealers to its own share. The
+// nodes must deliver the messages of the round as in the DKG:
+//  1. Every node sends the deals of Deals to the other nodes, and its own deal
+//     to itself.
+//  2. Every node processes the deals with ProcessDeal, which rejects the deals
+//     of a polynomial whose constant term is not zero, and broadcasts the
+//     responses it returns to the other nodes.
+//  3. Every node processes the responses with ProcessResponse, and broadcasts
+//     the justifications it returns, which the other nodes process with
+//     ProcessJustification.
+//  4. Once the deals are certified, or after a timeout (see SetTimeout),
+//     every node computes its new share with Refresh.
+//
+// The result holds the new share, the new public polynomial of the sharing,
+// and the Proof of the refresh, i.e. the commitments of the zero-sharings
+// which were added, so that anyone can check that the new public polynomial
+// shares the same secret as the old one.
+package refresh
+
+import (
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/share"
+	vss "go.dedis.ch/kyber/v3/share/vss/pedersen"
+)
+
+// Suite describes the functionalities needed by this package.
+type Suite interface {
+	kyber.Group
+	kyber.HashFactory
+	kyber.XOFFactory
+	kyber.Random
+}
+
+// ErrNonZeroSharing is returned for a deal or a proof sharing a polynomial
+// whose constant term is not zero, which would change the shared secret.
+var ErrNonZeroSharing = errors.New("refresh: sharing of a non-zero secret")
+
+// ErrTooFewDeals is returned by Refresh when less than t deals are certified:
+// at least t dealers are needed so that one of them is honest, and the new
+// shares are independent of the old ones.
+var ErrTooFewDeals = errors.New("refresh: not enough certified deals")
+
+var errInvalidShare = errors.New("refresh: share does not match the public polynomial")
+var errUnknownDealer = errors.New("refresh: unknown dealer index")
+
+// Deal is the zero-sharing deal of the dealer of index Index for one node.
+type Deal struct {
+	Index uint32
+	Deal  *vss.EncryptedDeal
+}
+
+// Response is the response of a node to the deal of the dealer of index
+// Index.
+type Response struct {
+	Index    uint32
+	Response *vss.Response
+}
+
+// Justification is the justification of the dealer of index Index against a
+// complaint about its deal.
+type Justification struct {
+	Index         uint32
+	Justification *vss.Justification
+}
+
+// Proof is the public record of a refresh: the commitments of the
+// zero-sharings of the dealers which were added to the shares, in the order
+// of the indices of the dealers.
+type Proof struct {
+	Dealers []int
+	Commits [][]kyber.Point
+}
+
+// Verify checks that the zero-sharings of the proof have the threshold of the
+// old public polynomial and a constant term equal to zero, and returns the
+// public polynomial of the refreshed sharing. The public share of the node i
+// after the refresh is the evaluation of the returned polynomial at i.
+func (p *Proof) Verify(suite Suite, old *share.PubPoly) (*share.PubPoly, error) {
+	if len(p.Dealers) != len(p.Commits) {
+		return nil, errors.New("refresh: dealers and commitments of different lengths")
+	}
+	b, _ := old.Info()
+	pub := old
+	for i, commits := range p.Commits {
+		if len(commits) != old.Threshold() {
+			return nil, fmt.Errorf("refresh: %d commitments of dealer %d for threshold %d", len(commits), p.Dealers[i], old.Threshold())
+		}
+		if !commits[0].Equal(suite.Point().Null()) {
+			return nil, fmt.Errorf("%w: dealer %d", ErrNonZeroSharing, p.Dealers[i])
+		}
+		var err error
+		if pub, err = pub.Add(share.NewPubPoly(suite, b, commits)); err != nil {
+			return nil, err
+		}
+	}
+	return pub, nil
+}
+
+// Result is the outcome of a refresh for one node.
+type Result struct {
+	// Share is the new share of the node, with the same index as the old one.
+	Share *share.PriShare
+	// Public is the public polynomial of the refreshed sharing. Its constant
+	// term is the public key of the secret, which is unchanged.
+	Public *share.PubPoly
+	// Proof is the record of the zero-sharings added to the shares.
+	Proof *Proof
+}
+
+// Refresher runs a refresh round for one node. It is not safe for concurrent
+// use.
+type Refresher struct {
+	suite     Suite
+	long      kyber.Scalar
+	nodes     []kyber.Point
+	share     *share.PriShare
+	public    *share.PubPoly
+	dealer    *vss.Dealer
+	verifiers []*vss.Verifier
+	// dealers whose deal shares a non-zero secret
+	bad map[int]bool
+}
+
+// NewRefresher returns the Refresher of the node of the longterm key, whose
+// public key is at the index of its share priShare in the list of nodes, for
+// the sharing of the public polynomial public, whose base point must be the
+// standard one. The threshold is the one of the public polynomial. It returns
+// an error if the share does not match the public polynomial.
+func NewRefresher(suite Suite, longterm kyber.Scalar, nodes []kyber.Point, priShare *share.PriShare, public *share.PubPoly) (*Refresher, error) {
+	if priShare == nil || priShare.V == nil || public == nil {
+		return nil, errInvalidShare
+	}
+	if b, _ := public.Info(); b != nil && !b.Equal(suite.Point().Base()) {
+		return nil, errors.New("refresh: public polynomial with another base point")
+	}
+	i := priShare.I
+	if i < 0 || i >= len(nodes) || !nodes[i].Equal(suite.Point().Mul(longterm, nil)) {
+		return nil, fmt.Errorf("refresh: public key not found at index %d of the nodes", i)
+	}
+	if !public.Check(priShare) {
+		return nil, errInvalidShare
+	}
+	zero := share.NewPriPoly(suite, public.Threshold(), suite.Scalar().Zero(), suite.RandomStream())
+	dealer, err := vss.NewDealerFromPoly(suite, longterm, zero, nodes, nil)
+	if err != nil {
+		return nil, err
+	}
+	r := &Refresher{
+		suite:     suite,
+		long:      longterm,
+		nodes:     nodes,
+		share:     priShare,
+		public:    public,
+		dealer:    dealer,
+		verifiers: make([]*vss.Verifier, len(nodes)),
+		bad:       make(map[int]bool),
+	}
+	for j, pub := range nodes {
+		if r.verifiers[j], err = vss.NewVerifierAtIndex(suite, longterm, pub, nodes, i); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Index returns the index of the node in the list of nodes.
+func (r *Refresher) Index() int {
+	return r.share.I
+}
+
+// Deals returns the deals of the zero-sharing of this node, by index of their
+// recipient in the list of nodes. The deal of the node itself is among them,
+// and must be processed by the node as the other ones.
+func (r *Refresher) Deals() (map[int]*Deal, error) {
+	deals, err := r.dealer.EncryptedDeals()
+	if err != nil {
+		return nil, err
+	}
+	dd := make(map[int]*Deal, len(deals))
+	for i, e := range deals {
+		dd[i] = &Deal{Index: uint32(r.Index()), Deal: e}
+	}
+	return dd, nil
+}
+
+// ProcessDeal verifies the deal and returns the response to broadcast to the
+// other nodes. It returns ErrNonZeroSharing without any response if the deal
+// shares a non-zero secret: the node then never adds the deal to its share,
+// and its missing approval prevents the certification of the deal by the
+// other nodes.
+func (r *Refresher) ProcessDeal(d *Deal) (*Response, error) {
+	if d == nil || d.Deal == nil {
+		return nil, errors.New("refresh: nil deal")
+	}
+	v, err := r.verifier(d.Index)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := v.ProcessEncryptedDeal(d.Deal)
+	if err != nil {
+		return nil, err
+	}
+	if !v.Commits()[0].Equal(r.suite.Point().Null()) {
+		r.bad[int(d.Index)] = true
+		return nil, fmt.Errorf("%w: dealer %d", ErrNonZeroSharing, d.Index)
+	}
+	return &Response{Index: d.Index, Response: resp}, nil
+}
+
+// ProcessResponse processes the response of another node. If it is a
+// complaint about the deal of this node, it returns the justification to
+// broadcast to the other nodes.
+func (r *Refresher) ProcessResponse(resp *Response) (*Justification, error) {
+	if resp == nil || resp.Response == nil {
+		return nil, errors.New("refresh: nil response")
+	}
+	v, err := r.verifier(resp.Index)
+	if err != nil {
+		return nil, err
+	}
+	if err := v.ProcessResponse(resp.Response); err != nil {
+		return nil, err
+	}
+	if int(resp.Index) != r.Index() {
+		return nil, nil
+	}
+	j, err := r.dealer.ProcessResponse(resp.Response)
+	if err != nil || j == nil {
+		return nil, err
+	}
+	return &Justification{Index: resp.Index, Justification: j}, nil
+}
+
+// ProcessJustification processes the justification of another dealer.
+func (r *Refresher) ProcessJustification(j *Justification) error {
+	if j == nil || j.Justification == nil {
+		return errors.New("refresh: nil justification")
+	}
+	v, err := r.verifier(j.Index)
+	if err != nil {
+		return err
+	}
+	return v.ProcessJustification(j.Justification)
+}
+
+// SetTimeout marks the end of the round, so that the deals can be certified
+// even if some responses are missing (see vss.Aggregator.DealCertified).
+func (r *Refresher) SetTimeout() {
+	for _, v := range r.verifiers {
+		v.SetTimeout()
+	}
+}
+
+// Refresh returns the refreshed share of the node, the public polynomial of
+// the refreshed sharing and the proof of the refresh, from the deals which
+// are certified. It returns ErrTooFewDeals if less than t deals are certified.
+// The caller should erase the old share with its Zero method once the new one
+// is stored.
+func (r *Refresher) Refresh() (*Result, error) {
+	proof := &Proof{}
+	v := r.share.V.Clone()
+	for i, ver := range r.verifiers {
+		deal := ver.Deal()
+		if deal == nil || r.bad[i] {
+			continue
+		}
+		proof.Dealers = append(proof.Dealers, i)
+		proof.Commits = append(proof.Commits, deal.Commitments)
+		v.Add(v, deal.SecShare.V)
+	}
+	if len(proof.Dealers) < r.public.Threshold() {
+		return nil, fmt.Errorf("%w: %d deals for threshold %d", ErrTooFewDeals, len(proof.Dealers), r.public.Threshold())
+	}
+	public, err := proof.Verify(r.suite, r.public)
+	if err != nil {
+		return nil, err
+	}
+	s := &share.PriShare{I: r.Index(), V: v}
+	if !public.Check(s) {
+		return nil, errInvalidShare
+	}
+	return &Result{Share: s, Public: public, Proof: proof}, nil
+}
+
+func (r *Refresher) verifier(index uint32) (*vss.Verifier, error) {
+	if int(index) >= len(r.verifiers) {
+		return nil, errUnknownDealer
+	}
+	return r.verifiers[index], nil
+}
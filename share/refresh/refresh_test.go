@@ -0,0 +1,150 @@
+package refresh
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/share"
+	vss "go.dedis.ch/kyber/v3/share/vss/pedersen"
+)
+
+var suite = edwards25519.NewBlakeSHA256Ed25519()
+
+const defaultN, defaultT = 5, 3
+
+// setup shares a random secret among the nodes and returns their refreshers.
+func setup(t *testing.T) (kyber.Scalar, []*share.PriShare, *share.PubPoly, []*Refresher) {
+	secret := suite.Scalar().Pick(suite.RandomStream())
+	poly := share.NewPriPoly(suite, defaultT, secret, suite.RandomStream())
+	public := poly.Commit(nil)
+	privs := make([]kyber.Scalar, defaultN)
+	pubs := make([]kyber.Point, defaultN)
+	for i := range privs {
+		privs[i] = suite.Scalar().Pick(suite.RandomStream())
+		pubs[i] = suite.Point().Mul(privs[i], nil)
+	}
+	shares := poly.Shares(defaultN)
+	refreshers := make([]*Refresher, defaultN)
+	for i := range refreshers {
+		r, err := NewRefresher(suite, privs[i], pubs, shares[i], public)
+		require.NoError(t, err)
+		refreshers[i] = r
+	}
+	return secret, shares, public, refreshers
+}
+
+// run delivers the deals and the responses of the round.
+func run(t *testing.T, refreshers []*Refresher, bad int) {
+	var resps []*Response
+	for _, r := range refreshers {
+		deals, err := r.Deals()
+		require.NoError(t, err)
+		for i, d := range deals {
+			resp, err := refreshers[i].ProcessDeal(d)
+			if int(d.Index) == bad {
+				require.True(t, errors.Is(err, ErrNonZeroSharing))
+				continue
+			}
+			require.NoError(t, err)
+			resps = append(resps, resp)
+		}
+	}
+	for _, resp := range resps {
+		for i, r := range refreshers {
+			if uint32(i) == resp.Response.Index {
+				continue
+			}
+			j, err := r.ProcessResponse(resp)
+			require.NoError(t, err)
+			require.Nil(t, j)
+		}
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	secret, shares, public, refreshers := setup(t)
+	run(t, refreshers, -1)
+
+	newShares := make([]*share.PriShare, defaultN)
+	for i, r := range refreshers {
+		res, err := r.Refresh()
+		require.NoError(t, err)
+		require.Equal(t, i, res.Share.I)
+		require.False(t, res.Share.V.Equal(shares[i].V))
+		require.True(t, res.Public.Commit().Equal(public.Commit()))
+		require.True(t, res.Public.Check(res.Share))
+		require.Len(t, res.Proof.Dealers, defaultN)
+		pub, err := res.Proof.Verify(suite, public)
+		require.NoError(t, err)
+		require.True(t, pub.Equal(res.Public))
+		newShares[i] = res.Share
+	}
+
+	recovered, err := share.RecoverSecret(suite, newShares, defaultT, defaultN)
+	require.NoError(t, err)
+	require.True(t, recovered.Equal(secret))
+
+	// old and new shares do not mix
+	mixed := []*share.PriShare{shares[0], shares[1], newShares[2]}
+	recovered, err = share.RecoverSecret(suite, mixed, defaultT, defaultN)
+	require.NoError(t, err)
+	require.False(t, recovered.Equal(secret))
+}
+
+func TestRefreshNonZeroDealer(t *testing.T) {
+	secret, _, public, refreshers := setup(t)
+	// the dealer 0 shares a non-zero secret to change the shared one
+	bad := 0
+	r := refreshers[bad]
+	nonZero := share.NewPriPoly(suite, defaultT, suite.Scalar().One(), suite.RandomStream())
+	var err error
+	r.dealer, err = vss.NewDealerFromPoly(suite, r.long, nonZero, r.nodes, nil)
+	require.NoError(t, err)
+
+	run(t, refreshers, bad)
+	for _, r := range refreshers {
+		r.SetTimeout()
+	}
+	newShares := make([]*share.PriShare, 0, defaultN)
+	for i, r := range refreshers[1:] {
+		res, err := r.Refresh()
+		require.NoError(t, err)
+		require.NotContains(t, res.Proof.Dealers, bad, "node %d", i+1)
+		newShares = append(newShares, res.Share)
+	}
+	recovered, err := share.RecoverSecret(suite, newShares, defaultT, defaultN)
+	require.NoError(t, err)
+	require.True(t, recovered.Equal(secret))
+
+	_, commits := nonZero.Commit(nil).Info()
+	proof := &Proof{Dealers: []int{bad}, Commits: [][]kyber.Point{commits}}
+	_, err = proof.Verify(suite, public)
+	require.True(t, errors.Is(err, ErrNonZeroSharing))
+}
+
+func TestRefreshTooFewDeals(t *testing.T) {
+	_, _, _, refreshers := setup(t)
+	deals, err := refreshers[0].Deals()
+	require.NoError(t, err)
+	_, err = refreshers[1].ProcessDeal(deals[1])
+	require.NoError(t, err)
+	refreshers[1].SetTimeout()
+	_, err = refreshers[1].Refresh()
+	require.True(t, errors.Is(err, ErrTooFewDeals))
+}
+
+func TestNewRefresherInvalid(t *testing.T) {
+	_, shares, public, refreshers := setup(t)
+	r := refreshers[0]
+	wrong := &share.PriShare{I: 0, V: suite.Scalar().Pick(suite.RandomStream())}
+	_, err := NewRefresher(suite, r.long, r.nodes, wrong, public)
+	require.Error(t, err)
+	// the share is not the one of the node
+	_, err = NewRefresher(suite, r.long, r.nodes, shares[1], public)
+	require.Error(t, err)
+	_, err = NewRefresher(suite, r.long, r.nodes, nil, public)
+	require.Error(t, err)
+}
@@ -0,0 +1,287 @@
+// Package repair implements the repair of a lost share of a Shamir secret
+// sharing, as described in "Repairable Threshold Schemes" by Laing and
+// Stinson: a set of at least t helper nodes recomputes the share of the node
+// of index r, e.g. a node re-provisioned after a disk failure, without any
+// other node learning the share, and without resharing the secret to all the
+// nodes.
+//
+// The share of r is the evaluation f(r) of the polynomial f of the sharing,
+// which is the sum of the shares s_j of the helpers j weighted by their
+// Lagrange coefficients l_j at r. Every helper splits its term l_j*s_j into
+// random parts, one per helper, so that a helper only sees a random part of
+// the terms of the other helpers. The protocol runs in two steps:
+//  1. Every helper sends the parts of Deltas to the other helpers, and its own
+//     part to itself. Every helper checks the parts it receives with
+//     ProcessDelta, against the public polynomial of the sharing.
+//  2. Every helper sends the sum of the parts it received, returned by Sigma,
+//     to the repaired node, which adds them up with Repair and checks the
+//     resulting share against the public polynomial.
+//
+// The parts and their sums are encrypted to the public keys of their
+// recipients with share.EncryptPriShare, where the repaired node has a fresh
+// key pair. The messages must however come through authenticated channels so
+// that a helper sending wrong parts can be told apart, since Repair only tells
+// that the share is wrong. Only all the helpers together, or t nodes of the
+// sharing, can recompute the repaired share.
+package repair
+
+import (
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/share"
+)
+
+// Suite describes the functionalities needed by this package.
+type Suite interface {
+	kyber.Group
+	kyber.Random
+}
+
+var errInvalidShare = errors.New("repair: share does not match the public polynomial")
+var errInvalidDelta = errors.New("repair: part does not match its commitments")
+var errMissingDeltas = errors.New("repair: parts of some helpers are missing")
+
+// Delta is a part of the term of the helper From, for the helper To.
+type Delta struct {
+	From int
+	To   int
+	// Share is the part, encrypted to the helper To with
+	// share.EncryptPriShare, at the index From.
+	Share []byte
+	// Commits are the commitments p*G of the parts p of the helper From for
+	// all the helpers, in the order of the helpers. They add up to l*S, where
+	// l is the Lagrange coefficient of From and S its public share.
+	Commits []kyber.Point
+}
+
+// Sigma is the sum of the parts received by the helper From, encrypted to the
+// repaired node with share.EncryptPriShare at the index From.
+type Sigma struct {
+	From  int
+	Share []byte
+}
+
+// Helper runs the repair for one helper. It is not safe for concurrent use.
+type Helper struct {
+	suite     Suite
+	long      kyber.Scalar
+	nodes     []kyber.Point
+	share     *share.PriShare
+	public    *share.PubPoly
+	helpers   []int
+	target    int
+	targetKey kyber.Point
+	// parts received, by index of their helper
+	received map[int]kyber.Scalar
+}
+
+// NewHelper returns the Helper of the node of the longterm key, whose public
+// key is at the index of its share priShare in the list of nodes, for the
+// repair of the share at index target of the sharing of the public polynomial
+// public. The helpers are the indices of the nodes which take part in the
+// repair, including this node, and targetKey is the public key of the
+// repaired node. It returns an error if the share does not match the public
+// polynomial, or if there are less helpers than the threshold of the
+// polynomial.
+func NewHelper(suite Suite, longterm kyber.Scalar, nodes []kyber.Point, priShare *share.PriShare, public *share.PubPoly, helpers []int, target int, targetKey kyber.Point) (*Helper, error) {
+	if priShare == nil || priShare.V == nil || public == nil || !public.Check(priShare) {
+		return nil, errInvalidShare
+	}
+	i := priShare.I
+	if i < 0 || i >= len(nodes) || !nodes[i].Equal(suite.Point().Mul(longterm, nil)) {
+		return nil, fmt.Errorf("repair: public key not found at index %d of the nodes", i)
+	}
+	if err := checkHelpers(helpers, target, len(nodes), public.Threshold()); err != nil {
+		return nil, err
+	}
+	if position(helpers, i) < 0 {
+		return nil, fmt.Errorf("repair: node %d is not a helper", i)
+	}
+	return &Helper{
+		suite:     suite,
+		long:      longterm,
+		nodes:     nodes,
+		share:     priShare,
+		public:    public,
+		helpers:   helpers,
+		target:    target,
+		targetKey: targetKey,
+		received:  make(map[int]kyber.Scalar),
+	}, nil
+}
+
+// Deltas returns the parts of the term of this helper, by index of the helper
+// they must be sent to. The part of the helper itself is among them, and must
+// be processed by the helper as the other ones.
+func (h *Helper) Deltas() (map[int]*Delta, error) {
+	term := lagrange(h.suite, h.helpers, h.share.I, h.target)
+	term.Mul(term, h.share.V)
+	parts := make([]kyber.Scalar, len(h.helpers))
+	commits := make([]kyber.Point, len(h.helpers))
+	last := term.Clone()
+	for k := range h.helpers {
+		if k == len(h.helpers)-1 {
+			parts[k] = last
+		} else {
+			parts[k] = h.suite.Scalar().Pick(h.suite.RandomStream())
+			last.Sub(last, parts[k])
+		}
+		commits[k] = h.suite.Point().Mul(parts[k], nil)
+	}
+	deltas := make(map[int]*Delta, len(h.helpers))
+	for k, to := range h.helpers {
+		p := &share.PriShare{I: h.share.I, V: parts[k]}
+		buff, err := share.EncryptPriShare(h.suite, p, h.nodes[to], h.suite.RandomStream())
+		p.Zero()
+		if err != nil {
+			return nil, err
+		}
+		deltas[to] = &Delta{From: h.share.I, To: to, Share: buff, Commits: commits}
+	}
+	return deltas, nil
+}
+
+// ProcessDelta decrypts the part of the helper From for this helper and checks
+// it against its commitments, and the commitments against the public share of
+// From. It returns an error if the part is not for this helper, is invalid,
+// or if a part of From has already been processed.
+func (h *Helper) ProcessDelta(d *Delta) error {
+	pos := position(h.helpers, d.From)
+	switch {
+	case pos < 0:
+		return fmt.Errorf("repair: part of node %d which is not a helper", d.From)
+	case d.To != h.share.I:
+		return fmt.Errorf("repair: part for helper %d", d.To)
+	case h.received[d.From] != nil:
+		return fmt.Errorf("repair: part of helper %d already processed", d.From)
+	case len(d.Commits) != len(h.helpers):
+		return fmt.Errorf("%w: %d commitments for %d helpers", errInvalidDelta, len(d.Commits), len(h.helpers))
+	}
+	p, err := share.DecryptPriShare(h.suite, d.Share, h.long)
+	if err != nil {
+		return err
+	}
+	if p.I != d.From {
+		return fmt.Errorf("repair: part of helper %d sent by helper %d", p.I, d.From)
+	}
+	if !h.suite.Point().Mul(p.V, nil).Equal(d.Commits[position(h.helpers, h.share.I)]) {
+		return errInvalidDelta
+	}
+	sum := h.suite.Point().Null()
+	for _, c := range d.Commits {
+		sum.Add(sum, c)
+	}
+	lambda := lagrange(h.suite, h.helpers, d.From, h.target)
+	if !sum.Equal(h.suite.Point().Mul(lambda, h.public.Eval(d.From).V)) {
+		return fmt.Errorf("%w: commitments do not match the public share of helper %d", errInvalidDelta, d.From)
+	}
+	h.received[d.From] = p.V
+	return nil
+}
+
+// Sigma returns the sum of the parts received from all the helpers, encrypted
+// to the repaired node. It returns an error if the part of a helper is
+// missing.
+func (h *Helper) Sigma() (*Sigma, error) {
+	if len(h.received) != len(h.helpers) {
+		return nil, errMissingDeltas
+	}
+	sum := &share.PriShare{I: h.share.I, V: h.suite.Scalar().Zero()}
+	for _, p := range h.received {
+		sum.V.Add(sum.V, p)
+	}
+	buff, err := share.EncryptPriShare(h.suite, sum, h.targetKey, h.suite.RandomStream())
+	sum.Zero()
+	if err != nil {
+		return nil, err
+	}
+	return &Sigma{From: h.share.I, Share: buff}, nil
+}
+
+// Repair returns the share at index target of the sharing of the public
+// polynomial, from the sums of all the helpers, decrypted with the private
+// key of the repaired node. It returns an error if the sum of a helper is
+// missing or cannot be decrypted, or if the share does not match the public
+// polynomial.
+func Repair(suite Suite, private kyber.Scalar, public *share.PubPoly, helpers []int, target int, sigmas []*Sigma) (*share.PriShare, error) {
+	if err := checkHelpers(helpers, target, -1, public.Threshold()); err != nil {
+		return nil, err
+	}
+	seen := make(map[int]bool)
+	v := suite.Scalar().Zero()
+	for _, s := range sigmas {
+		if s == nil || position(helpers, s.From) < 0 || seen[s.From] {
+			continue
+		}
+		p, err := share.DecryptPriShare(suite, s.Share, private)
+		if err != nil {
+			return nil, err
+		}
+		if p.I != s.From {
+			return nil, fmt.Errorf("repair: sum of helper %d sent by helper %d", p.I, s.From)
+		}
+		seen[s.From] = true
+		v.Add(v, p.V)
+		p.Zero()
+	}
+	if len(seen) != len(helpers) {
+		return nil, errors.New("repair: sums of some helpers are missing")
+	}
+	repaired := &share.PriShare{I: target, V: v}
+	if !public.Check(repaired) {
+		return nil, errInvalidShare
+	}
+	return repaired, nil
+}
+
+// checkHelpers returns an error if there are less than t helpers, or if they
+// are not distinct indices of the n nodes other than the target. A negative n
+// skips the upper bound.
+func checkHelpers(helpers []int, target, n, t int) error {
+	if len(helpers) < t {
+		return fmt.Errorf("repair: %d helpers for threshold %d", len(helpers), t)
+	}
+	if target < 0 || (n >= 0 && target >= n) {
+		return fmt.Errorf("repair: target %d out of bounds", target)
+	}
+	seen := make(map[int]bool)
+	for _, i := range helpers {
+		if i < 0 || (n >= 0 && i >= n) || i == target || seen[i] {
+			return fmt.Errorf("repair: invalid helper %d", i)
+		}
+		seen[i] = true
+	}
+	return nil
+}
+
+// position returns the position of the index i among the helpers, or -1.
+func position(helpers []int, i int) int {
+	for k, j := range helpers {
+		if j == i {
+			return k
+		}
+	}
+	return -1
+}
+
+// lagrange returns the Lagrange coefficient of the helper j for the
+// evaluation at the index target of the polynomial interpolated from the
+// shares of the helpers, where the share of index i is evaluated at i+1.
+func lagrange(suite Suite, helpers []int, j, target int) kyber.Scalar {
+	num := suite.Scalar().One()
+	den := suite.Scalar().One()
+	xj := suite.Scalar().SetInt64(int64(j + 1))
+	xr := suite.Scalar().SetInt64(int64(target + 1))
+	tmp := suite.Scalar()
+	for _, k := range helpers {
+		if k == j {
+			continue
+		}
+		xk := suite.Scalar().SetInt64(int64(k + 1))
+		num.Mul(num, tmp.Sub(xr, xk))
+		den.Mul(den, tmp.Sub(xj, xk))
+	}
+	return num.Div(num, den)
+}
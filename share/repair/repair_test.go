@@ -0,0 +1,112 @@
+package repair
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/share"
+)
+
+var suite = edwards25519.NewBlakeSHA256Ed25519()
+
+// setup returns the keys of n nodes and a sharing of threshold t between them.
+func setup(n, t int) ([]kyber.Scalar, []kyber.Point, []*share.PriShare, *share.PubPoly) {
+	secs := make([]kyber.Scalar, n)
+	pubs := make([]kyber.Point, n)
+	for i := range secs {
+		secs[i] = suite.Scalar().Pick(suite.RandomStream())
+		pubs[i] = suite.Point().Mul(secs[i], nil)
+	}
+	poly := share.NewPriPoly(suite, t, nil, suite.RandomStream())
+	return secs, pubs, poly.Shares(n), poly.Commit(nil)
+}
+
+func TestRepair(t *testing.T) {
+	n, thr := 7, 4
+	secs, pubs, shares, public := setup(n, thr)
+	const target = 2
+	targetSec := suite.Scalar().Pick(suite.RandomStream())
+	targetKey := suite.Point().Mul(targetSec, nil)
+
+	for _, helpers := range [][]int{{0, 1, 3, 4}, {6, 5, 4, 3, 1}} {
+		hs := make([]*Helper, len(helpers))
+		deltas := make(map[int][]*Delta)
+		for k, i := range helpers {
+			var err error
+			hs[k], err = NewHelper(suite, secs[i], pubs, shares[i], public, helpers, target, targetKey)
+			require.NoError(t, err)
+			dd, err := hs[k].Deltas()
+			require.NoError(t, err)
+			require.Len(t, dd, len(helpers))
+			for to, d := range dd {
+				deltas[to] = append(deltas[to], d)
+			}
+		}
+		var sigmas []*Sigma
+		for k, h := range hs {
+			_, err := h.Sigma()
+			require.Equal(t, errMissingDeltas, err)
+			for _, d := range deltas[helpers[k]] {
+				require.NoError(t, h.ProcessDelta(d))
+			}
+			require.Error(t, h.ProcessDelta(deltas[helpers[k]][0]))
+			sigma, err := h.Sigma()
+			require.NoError(t, err)
+			sigmas = append(sigmas, sigma)
+		}
+
+		repaired, err := Repair(suite, targetSec, public, helpers, target, sigmas)
+		require.NoError(t, err)
+		require.Equal(t, target, repaired.I)
+		require.True(t, shares[target].V.Equal(repaired.V))
+
+		_, err = Repair(suite, targetSec, public, helpers, target, sigmas[1:])
+		require.Error(t, err)
+		_, err = Repair(suite, secs[0], public, helpers, target, sigmas)
+		require.Error(t, err)
+	}
+}
+
+func TestRepairInvalid(t *testing.T) {
+	n, thr := 5, 3
+	secs, pubs, shares, public := setup(n, thr)
+	const target = 4
+	targetSec := suite.Scalar().Pick(suite.RandomStream())
+	targetKey := suite.Point().Mul(targetSec, nil)
+	helpers := []int{0, 1, 2}
+
+	for _, bad := range [][]int{{0, 1}, {0, 1, 1}, {0, 1, 4}, {0, 1, 5}} {
+		_, err := NewHelper(suite, secs[0], pubs, shares[0], public, bad, target, targetKey)
+		require.Error(t, err)
+	}
+	_, err := NewHelper(suite, secs[3], pubs, shares[3], public, helpers, target, targetKey)
+	require.Error(t, err)
+	_, err = NewHelper(suite, secs[1], pubs, shares[0], public, helpers, target, targetKey)
+	require.Error(t, err)
+	wrong := &share.PriShare{I: 0, V: suite.Scalar().Pick(suite.RandomStream())}
+	_, err = NewHelper(suite, secs[0], pubs, wrong, public, helpers, target, targetKey)
+	require.Equal(t, errInvalidShare, err)
+
+	// a helper splits a wrong term: the commitments do not add up to it
+	h0, err := NewHelper(suite, secs[0], pubs, shares[0], public, helpers, target, targetKey)
+	require.NoError(t, err)
+	h1, err := NewHelper(suite, secs[1], pubs, shares[1], public, helpers, target, targetKey)
+	require.NoError(t, err)
+	h0.share = wrong
+	dd, err := h0.Deltas()
+	require.NoError(t, err)
+	require.Error(t, h1.ProcessDelta(dd[1]))
+
+	// or sends a part which does not match its commitment
+	h0.share = shares[0]
+	dd, err = h0.Deltas()
+	require.NoError(t, err)
+	other, err := h0.Deltas()
+	require.NoError(t, err)
+	dd[1].Share = other[1].Share
+	require.Error(t, h1.ProcessDelta(dd[1]))
+	require.Error(t, h1.ProcessDelta(other[2]))
+	require.NoError(t, h1.ProcessDelta(other[1]))
+}
@@ -0,0 +1,88 @@
+package share
+
+import (
+	"sort"
+	"sync"
+
+	"go.dedis.ch/kyber/v3"
+)
+
+// scratch holds the temporary buffers used to select the shares of a Lagrange
+// interpolation. They do not depend on the group, so they are recycled across
+// calls through scratchPool instead of being allocated on every recovery.
+type scratch struct {
+	pos []int // position of the selected shares in the input list
+	idx []int // index of the selected shares
+}
+
+var scratchPool = sync.Pool{
+	New: func() interface{} {
+		return new(scratch)
+	},
+}
+
+func getScratch() *scratch {
+	s := scratchPool.Get().(*scratch)
+	s.pos = s.pos[:0]
+	s.idx = s.idx[:0]
+	return s
+}
+
+func putScratch(s *scratch) {
+	scratchPool.Put(s)
+}
+
+// add registers the share at position pos in the input list having the index
+// idx.
+func (s *scratch) add(pos, idx int) {
+	s.pos = append(s.pos, pos)
+	s.idx = append(s.idx, idx)
+}
+
+func (s *scratch) Len() int           { return len(s.idx) }
+func (s *scratch) Less(i, j int) bool { return s.idx[i] < s.idx[j] }
+func (s *scratch) Swap(i, j int) {
+	s.idx[i], s.idx[j] = s.idx[j], s.idx[i]
+	s.pos[i], s.pos[j] = s.pos[j], s.pos[i]
+}
+
+// selectShares keeps the t shares with the smallest distinct indices, so that
+// all participants interpolate on the exact same shares, and returns how many
+// shares have been kept.
+func (s *scratch) selectShares(t int) int {
+	sort.Stable(s)
+	n := 0
+	for i := range s.idx {
+		if n == t {
+			break
+		}
+		if n > 0 && s.idx[i] == s.idx[n-1] {
+			// duplicate share
+			continue
+		}
+		s.idx[n] = s.idx[i]
+		s.pos[n] = s.pos[i]
+		n++
+	}
+	s.idx = s.idx[:n]
+	s.pos = s.pos[:n]
+	return n
+}
+
+// lagrange sets c to the Lagrange basis polynomial of the i-th selected share
+// evaluated at zero, i.e. the product of x_j / (x_j - x_i) for all j != i,
+// using den and tmp as temporaries.
+func (s *scratch) lagrange(c, den, tmp kyber.Scalar, i int) kyber.Scalar {
+	c.One()
+	den.One()
+	xi := int64(s.idx[i]) + 1
+	for j, idx := range s.idx {
+		if i == j {
+			continue
+		}
+		xj := int64(idx) + 1
+		c.Mul(c, tmp.SetInt64(xj))
+		den.Mul(den, tmp.SetInt64(xj-xi))
+	}
+	return c.Div(c, den)
+}
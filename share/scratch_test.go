@@ -0,0 +1,183 @@
+package share
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/pairing/bn256"
+	"go.dedis.ch/kyber/v3/util/msm"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+// recoverSecretReference is the previous implementation of RecoverSecret,
+// based on maps of x and y coordinates.
+func recoverSecretReference(g kyber.Group, shares []*PriShare, t, n int) kyber.Scalar {
+	x, y := xyScalar(g, shares, t, n)
+	acc := g.Scalar().Zero()
+	num := g.Scalar()
+	den := g.Scalar()
+	tmp := g.Scalar()
+	for i, xi := range x {
+		num.Set(y[i])
+		den.One()
+		for j, xj := range x {
+			if i == j {
+				continue
+			}
+			num.Mul(num, xj)
+			den.Mul(den, tmp.Sub(xj, xi))
+		}
+		acc.Add(acc, num.Div(num, den))
+	}
+	return acc
+}
+
+// recoverCommitReference is the previous implementation of RecoverCommit,
+// based on maps of x and y coordinates.
+func recoverCommitReference(g kyber.Group, shares []*PubShare, t, n int) kyber.Point {
+	x, y := xyCommit(g, shares, t, n)
+	num := g.Scalar()
+	den := g.Scalar()
+	tmp := g.Scalar()
+	Acc := g.Point().Null()
+	Tmp := g.Point()
+	for i, xi := range x {
+		num.One()
+		den.One()
+		for j, xj := range x {
+			if i == j {
+				continue
+			}
+			num.Mul(num, xj)
+			den.Mul(den, tmp.Sub(xj, xi))
+		}
+		Tmp.Mul(num.Div(num, den), y[i])
+		Acc.Add(Acc, Tmp)
+	}
+	return Acc
+}
+
+// randomSubset returns a shuffled subset of at least t of the shares mixed with
+// nil entries and duplicates.
+func randomSubset(rnd *rand.Rand, n, t int) []int {
+	perm := rnd.Perm(n)[:t+rnd.Intn(n-t+1)]
+	for i := rnd.Intn(3); i > 0; i-- {
+		perm = append(perm, perm[rnd.Intn(len(perm))], -1)
+	}
+	rnd.Shuffle(len(perm), func(i, j int) { perm[i], perm[j] = perm[j], perm[i] })
+	return perm
+}
+
+func TestRecoverMatchesReference(test *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	groups := []kyber.Group{
+		edwards25519.NewBlakeSHA256Ed25519(),
+		bn256.NewSuiteG1(),
+	}
+	for _, g := range groups {
+		for k := 0; k < 10; k++ {
+			n := 2 + rnd.Intn(15)
+			t := 1 + rnd.Intn(n)
+			poly := NewPriPoly(g, t, nil, random.New())
+			pub := poly.Commit(nil)
+			priShares := poly.Shares(n)
+			pubShares := pub.Shares(n)
+
+			subset := randomSubset(rnd, n, t)
+			pris := make([]*PriShare, len(subset))
+			pubs := make([]*PubShare, len(subset))
+			for i, j := range subset {
+				if j >= 0 {
+					pris[i] = priShares[j]
+					pubs[i] = pubShares[j]
+				}
+			}
+
+			secret, err := RecoverSecret(g, pris, t, n)
+			require.NoError(test, err)
+			require.True(test, secret.Equal(recoverSecretReference(g, pris, t, n)))
+			require.True(test, secret.Equal(poly.Secret()))
+
+			commit, err := RecoverCommit(g, pubs, t, n)
+			require.NoError(test, err)
+			require.True(test, commit.Equal(recoverCommitReference(g, pubs, t, n)))
+			require.True(test, commit.Equal(pub.Commit()))
+		}
+	}
+}
+
+func TestRecoverCommitInto(test *testing.T) {
+	g := edwards25519.NewBlakeSHA256Ed25519()
+	n := 10
+	t := n/2 + 1
+	pub := NewPriPoly(g, t, nil, g.RandomStream()).Commit(nil)
+	shares := pub.Shares(n)
+
+	dst := g.Point().Pick(g.RandomStream())
+	commit, err := RecoverCommitInto(dst, g, shares, t, n)
+	require.NoError(test, err)
+	require.True(test, commit == dst)
+	require.True(test, dst.Equal(pub.Commit()))
+
+	_, err = RecoverCommitInto(dst, g, shares[:t-1], t, n)
+	require.Error(test, err)
+}
+
+func TestRecoverAllocs(test *testing.T) {
+	g := edwards25519.NewBlakeSHA256Ed25519()
+	n := 20
+	t := n/2 + 1
+	poly := NewPriPoly(g, t, nil, g.RandomStream())
+	priShares := poly.Shares(n)
+	pubShares := poly.Commit(nil).Shares(n)
+
+	refSecret := testing.AllocsPerRun(20, func() {
+		recoverSecretReference(g, priShares, t, n)
+	})
+	secret := testing.AllocsPerRun(20, func() {
+		_, _ = RecoverSecret(g, priShares, t, n)
+	})
+	require.True(test, secret < refSecret/2, "%v allocations instead of %v", secret, refSecret)
+
+	refCommit := testing.AllocsPerRun(20, func() {
+		recoverCommitReference(g, pubShares, t, n)
+	})
+	dst := g.Point()
+	commit := testing.AllocsPerRun(20, func() {
+		_, _ = RecoverCommitInto(dst, g, pubShares, t, n)
+	})
+	require.True(test, commit < refCommit/2, "%v allocations instead of %v", commit, refCommit)
+}
+
+func TestEvalAllocs(test *testing.T) {
+	g := edwards25519.NewBlakeSHA256Ed25519()
+	t := 2 * msm.MinPoints
+	pub := NewPriPoly(g, t, nil, g.RandomStream()).Commit(nil)
+
+	// the previous implementation, allocating the powers on every call
+	evalReference := func(i int) kyber.Point {
+		xi := g.Scalar().SetInt64(1 + int64(i))
+		pows := make([]kyber.Scalar, t)
+		pows[0] = g.Scalar().One()
+		for j := 1; j < t; j++ {
+			pows[j] = g.Scalar().Mul(pows[j-1], xi)
+		}
+		v, err := msm.MultiScalarMult(g, pub.commits, pows)
+		require.NoError(test, err)
+		return v
+	}
+	for i := 0; i < 5; i++ {
+		require.True(test, evalReference(i).Equal(pub.Eval(i).V))
+	}
+
+	ref := testing.AllocsPerRun(20, func() {
+		evalReference(3)
+	})
+	eval := testing.AllocsPerRun(20, func() {
+		pub.Eval(3)
+	})
+	require.True(test, eval < ref, "%v allocations instead of %v", eval, ref)
+}
@@ -13,8 +13,10 @@ import (
 	"reflect"
 
 	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/proof/dleq"
 	"go.dedis.ch/kyber/v3/share"
 	"go.dedis.ch/kyber/v3/sign/schnorr"
+	"go.dedis.ch/kyber/v3/util/wipe"
 	"go.dedis.ch/protobuf"
 )
 
@@ -39,11 +41,13 @@ type Dealer struct {
 	secretPoly    *share.PriPoly
 	verifiers     []kyber.Point
 	hkdfContext   []byte
+	// evaluation points of the verifiers' shares, nil for the default ones
+	xs []kyber.Scalar
 	// threshold of shares that is needed to reconstruct the secret
 	t int
 	// sessionID is a unique identifier for the whole session of the scheme
 	sessionID []byte
-	// list of deals this Dealer has generated
+	// list of deals this Dealer has generated, nil once Zero erased them
 	deals []*Deal
 	*Aggregator
 }
@@ -58,12 +62,33 @@ type Deal struct {
 	T uint32
 	// Commitments are the coefficients used to verify the shares against
 	Commitments []kyber.Point
+	// X is the evaluation point of the private share when the dealer uses
+	// arbitrary evaluation points (see NewDealerWithPoints). It is nil when
+	// the default evaluation point SecShare.I+1 is used.
+	X kyber.Scalar
 }
 
+// EncryptedDealVersion is the version of the encryption of the deals made by
+// the dealers. The deals of version 1 authenticate their session ID and the
+// index of their verifier as associated data of the AEAD, so that a deal
+// cannot be replayed in another session nor to another share of the same
+// verifier. The deals of version 0, made by the previous releases, only
+// authenticate the public keys of the dealer and of the verifiers.
+//
+// MIGRATION: the verifiers reject the deals of version 0 unless
+// Verifier.SetAcceptLegacyDeals is called, which is only meant for the
+// release introducing version 1, while the dealers are upgraded. It will be
+// removed in the next release.
+const EncryptedDealVersion uint32 = 1
+
 // EncryptedDeal contains the deal in a encrypted form only decipherable by the
 // correct recipient. The encryption is performed in a similar manner as what is
 // done in TLS. The dealer generates a temporary key pair, signs it with its
 // longterm secret key.
+//
+// The fields are encoded by protobuf in their order: the fields added with
+// version 1 come after the ones of version 0, so that the deals of both
+// versions keep the same field numbers.
 type EncryptedDeal struct {
 	// Ephemeral Diffie Hellman key
 	DHKey []byte
@@ -73,6 +98,13 @@ type EncryptedDeal struct {
 	Nonce []byte
 	// AEAD encryption of the deal marshalled by protobuf
 	Cipher []byte
+	// Version of the encryption, see EncryptedDealVersion. It is 0 for the
+	// deals of the previous releases, which have neither SessionID nor Index.
+	Version uint32
+	// SessionID of the deal, authenticated by the AEAD
+	SessionID []byte
+	// Index of the verifier of the deal, authenticated by the AEAD
+	Index uint32
 }
 
 // Response is sent by the verifiers to all participants and holds each
@@ -118,18 +150,57 @@ type Justification struct {
 // MinimumT() returns, otherwise it breaks the security assumptions of the whole
 // scheme. It returns an error if the t is less than or equal to 2.
 func NewDealer(suite Suite, longterm, secret kyber.Scalar, verifiers []kyber.Point, t int) (*Dealer, error) {
-	d := &Dealer{
-		suite:     suite,
-		long:      longterm,
-		secret:    secret,
-		verifiers: verifiers,
+	return newDealer(suite, longterm, secret, verifiers, nil, t)
+}
+
+// NewDealerWithPoints returns a Dealer like NewDealer, except that the share
+// of the i-th verifier is evaluated at the arbitrary point xs[i] instead of
+// at i+1, e.g. at an index derived from the verifier's identity with
+// share.HashIndex. The verifiers must be told about these points with
+// SetEvaluationPoints. It returns an error if there is not exactly one
+// non-zero and distinct evaluation point per verifier.
+func NewDealerWithPoints(suite Suite, longterm, secret kyber.Scalar, verifiers []kyber.Point, xs []kyber.Scalar, t int) (*Dealer, error) {
+	if err := validPoints(suite, xs, verifiers); err != nil {
+		return nil, err
+	}
+	return newDealer(suite, longterm, secret, verifiers, xs, t)
+}
+
+// NewDealerFromPoly returns a Dealer like NewDealer or NewDealerWithPoints,
+// whose private polynomial is the given one instead of a random polynomial,
+// e.g. to restore a dealer from the private polynomial it saved before a
+// restart (see PrivatePoly). Its deals then have the same commitments and
+// session ID as the ones of the former dealer. The threshold is the one of
+// the polynomial, and xs may be nil for the default evaluation points.
+func NewDealerFromPoly(suite Suite, longterm kyber.Scalar, f *share.PriPoly, verifiers []kyber.Point, xs []kyber.Scalar) (*Dealer, error) {
+	if !validT(f.Threshold(), verifiers) {
+		return nil, fmt.Errorf("dealer: t %d invalid", f.Threshold())
 	}
+	if xs != nil {
+		if err := validPoints(suite, xs, verifiers); err != nil {
+			return nil, err
+		}
+	}
+	return newDealerFromPoly(suite, longterm, f, verifiers, xs)
+}
+
+func newDealer(suite Suite, longterm, secret kyber.Scalar, verifiers []kyber.Point, xs []kyber.Scalar, t int) (*Dealer, error) {
 	if !validT(t, verifiers) {
 		return nil, fmt.Errorf("dealer: t %d invalid", t)
 	}
-	d.t = t
+	f := share.NewPriPoly(suite, t, secret, suite.RandomStream())
+	return newDealerFromPoly(suite, longterm, f, verifiers, xs)
+}
 
-	f := share.NewPriPoly(d.suite, d.t, d.secret, suite.RandomStream())
+func newDealerFromPoly(suite Suite, longterm kyber.Scalar, f *share.PriPoly, verifiers []kyber.Point, xs []kyber.Scalar) (*Dealer, error) {
+	d := &Dealer{
+		suite:     suite,
+		long:      longterm,
+		secret:    f.Secret(),
+		verifiers: verifiers,
+		xs:        xs,
+		t:         f.Threshold(),
+	}
 	d.pub = d.suite.Point().Mul(d.long, nil)
 
 	// Compute public polynomial coefficients
@@ -137,22 +208,29 @@ func NewDealer(suite Suite, longterm, secret kyber.Scalar, verifiers []kyber.Poi
 	_, d.secretCommits = F.Info()
 
 	var err error
-	d.sessionID, err = sessionID(d.suite, d.pub, d.verifiers, d.secretCommits, d.t)
+	d.sessionID, err = sessionID(d.suite, d.pub, d.verifiers, d.secretCommits, d.xs, d.t, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	d.Aggregator = newAggregator(d.suite, d.pub, d.verifiers, d.secretCommits, d.t, d.sessionID)
+	d.Aggregator.xs = d.xs
 	// C = F + G
 	d.deals = make([]*Deal, len(d.verifiers))
 	for i := range d.verifiers {
-		fi := f.Eval(i)
-		d.deals[i] = &Deal{
+		deal := &Deal{
 			SessionID:   d.sessionID,
-			SecShare:    fi,
 			Commitments: d.secretCommits,
 			T:           uint32(d.t),
 		}
+		if d.xs != nil {
+			fi := f.EvalScalar(d.xs[i])
+			deal.SecShare = &share.PriShare{I: i, V: fi.V}
+			deal.X = fi.X
+		} else {
+			deal.SecShare = f.Eval(i)
+		}
+		d.deals[i] = deal
 	}
 	d.hkdfContext = context(suite, d.pub, verifiers)
 	d.secretPoly = f
@@ -162,8 +240,11 @@ func NewDealer(suite Suite, longterm, secret kyber.Scalar, verifiers []kyber.Poi
 // PlaintextDeal returns the plaintext version of the deal destined for peer i.
 // Use this only for testing.
 func (d *Dealer) PlaintextDeal(i int) (*Deal, error) {
+	if d.deals == nil {
+		return nil, ErrWiped
+	}
 	if i >= len(d.deals) {
-		return nil, errors.New("dealer: PlaintextDeal given wrong index")
+		return nil, fmt.Errorf("%w: %d", ErrWrongIndex, i)
 	}
 	return d.deals[i], nil
 }
@@ -176,9 +257,12 @@ func (d *Dealer) PlaintextDeal(i int) (*Deal, error) {
 // This shared key is then fed into a HKDF whose output is the key to a AEAD
 // (AES256-GCM) scheme to encrypt the deal.
 func (d *Dealer) EncryptedDeal(i int) (*EncryptedDeal, error) {
+	if d.deals == nil {
+		return nil, ErrWiped
+	}
 	vPub, ok := findPub(d.verifiers, uint32(i))
 	if !ok {
-		return nil, errors.New("dealer: wrong index to generate encrypted deal")
+		return nil, fmt.Errorf("%w: %d", ErrWrongIndex, i)
 	}
 	// gen ephemeral key
 	dhSecret := d.suite.Scalar().Pick(d.suite.RandomStream())
@@ -191,6 +275,7 @@ func (d *Dealer) EncryptedDeal(i int) (*EncryptedDeal, error) {
 	}
 	// AES128-GCM
 	pre := dhExchange(d.suite, dhSecret, vPub)
+	wipe.Scalar(dhSecret)
 	gcm, err := newAEAD(d.suite.Hash, pre, d.hkdfContext)
 	if err != nil {
 		return nil, err
@@ -201,9 +286,14 @@ func (d *Dealer) EncryptedDeal(i int) (*EncryptedDeal, error) {
 	if err != nil {
 		return nil, err
 	}
-	encrypted := gcm.Seal(nil, nonce, dealBuff, d.hkdfContext)
+	sid := d.deals[i].SessionID
+	encrypted := gcm.Seal(nil, nonce, dealBuff, dealAD(d.hkdfContext, sid, uint32(i)))
+	wipe.Bytes(dealBuff)
 	dhBytes, _ := dhPublic.MarshalBinary()
 	return &EncryptedDeal{
+		Version:   EncryptedDealVersion,
+		SessionID: sid,
+		Index:     uint32(i),
 		DHKey:     dhBytes,
 		Signature: signature,
 		Nonce:     nonce,
@@ -211,6 +301,17 @@ func (d *Dealer) EncryptedDeal(i int) (*EncryptedDeal, error) {
 	}, nil
 }
 
+// dealAD returns the associated data of the AEAD encrypting a deal of
+// version 1 for the verifier at the given index.
+func dealAD(context, sid []byte, index uint32) []byte {
+	var b bytes.Buffer
+	_, _ = b.Write(context)
+	_ = binary.Write(&b, binary.LittleEndian, uint32(len(sid)))
+	_, _ = b.Write(sid)
+	_ = binary.Write(&b, binary.LittleEndian, index)
+	return b.Bytes()
+}
+
 // EncryptedDeals calls `EncryptedDeal` for each index of the verifier and
 // returns the list of encrypted deals. Each index in the returned slice
 // corresponds to the index in the list of verifiers.
@@ -237,12 +338,18 @@ func (d *Dealer) ProcessResponse(r *Response) (*Justification, error) {
 	if r.Status == StatusApproval {
 		return nil, nil
 	}
+	if d.deals == nil {
+		return nil, ErrWiped
+	}
 
+	// the justification reveals a copy of the deal, which Zero leaves intact
+	deal := *d.deals[int(r.Index)]
+	deal.SecShare = &share.PriShare{I: deal.SecShare.I, V: deal.SecShare.V.Clone()}
 	j := &Justification{
 		SessionID: d.sessionID,
 		// index is guaranteed to be good because of d.verifyResponse before
 		Index: r.Index,
-		Deal:  d.deals[int(r.Index)],
+		Deal:  &deal,
 	}
 	sig, err := schnorr.Sign(d.suite, d.long, j.Hash(d.suite))
 	if err != nil {
@@ -259,6 +366,10 @@ func (d *Dealer) SecretCommit() kyber.Point {
 	if !d.DealCertified() {
 		return nil
 	}
+	if d.deals == nil {
+		// the secret has been erased by Zero, but not its commitment
+		return d.suite.Point().Set(d.secretCommits[0])
+	}
 	return d.suite.Point().Mul(d.secret, nil)
 }
 
@@ -279,6 +390,26 @@ func (d *Dealer) SessionID() []byte {
 	return d.sessionID
 }
 
+// SetNonce binds the session ID of the deals to the nonce, e.g. an identifier
+// of the run agreed on by all the participants. Since the responses and the
+// justifications sign the session ID, the messages of another run with the
+// same keys, even a previous run with the same polynomial, are rejected by
+// the verifiers, which must be given the same nonce with Verifier.SetNonce.
+// It must be called before the deals are issued. An empty nonce gives the
+// session ID of a dealer without nonce.
+func (d *Dealer) SetNonce(nonce []byte) error {
+	sid, err := sessionID(d.suite, d.pub, d.verifiers, d.secretCommits, d.xs, d.t, nonce)
+	if err != nil {
+		return err
+	}
+	d.sessionID = sid
+	d.Aggregator.sid = sid
+	for _, deal := range d.deals {
+		deal.SessionID = sid
+	}
+	return nil
+}
+
 // SetTimeout marks the end of a round, invalidating any missing (or future) response
 // for this DKG protocol round. The caller is expected to call this after a long timeout
 // so each DKG node can still compute its share if enough Deals are valid.
@@ -294,6 +425,22 @@ func (d *Dealer) PrivatePoly() *share.PriPoly {
 	return d.secretPoly
 }
 
+// Zero erases the secret material of the dealer once it is no longer needed,
+// i.e. its private polynomial and the shares of its deals. This is typically
+// the case once the deal is certified, since there is no complaint left to
+// justify. The secret given to NewDealer is erased as well, since it is the
+// constant term of the polynomial. Afterwards, PlaintextDeal, EncryptedDeal
+// and ProcessResponse, for a complaint, return ErrWiped, and PrivatePoly
+// returns the zero polynomial. The erasure is best-effort, see the wipe
+// package.
+func (d *Dealer) Zero() {
+	d.secretPoly.Zero()
+	for _, deal := range d.deals {
+		deal.SecShare.Zero()
+	}
+	d.deals = nil
+}
+
 // Verifier receives a Deal from a Dealer, can reply with a Complaint, and can
 // collaborate with other Verifiers to reconstruct a secret.
 type Verifier struct {
@@ -304,6 +451,10 @@ type Verifier struct {
 	index       int
 	verifiers   []kyber.Point
 	hkdfContext []byte
+	// acceptLegacy is true if the deals of version 0 are accepted
+	acceptLegacy bool
+	// nonce bound into the session ID, see SetNonce
+	nonce []byte
 	*Aggregator
 }
 
@@ -319,17 +470,25 @@ func NewVerifier(suite Suite, longterm kyber.Scalar, dealerKey kyber.Point,
 	verifiers []kyber.Point) (*Verifier, error) {
 
 	pub := suite.Point().Mul(longterm, nil)
-	var ok bool
-	var index int
 	for i, v := range verifiers {
 		if v.Equal(pub) {
-			ok = true
-			index = i
-			break
+			return NewVerifierAtIndex(suite, longterm, dealerKey, verifiers, i)
 		}
 	}
-	if !ok {
-		return nil, errors.New("vss: public key not found in the list of verifiers")
+	return nil, errors.New("vss: public key not found in the list of verifiers")
+}
+
+// NewVerifierAtIndex works like NewVerifier for the verifier at the given index
+// in the list of verifiers, whose public key must be the one of the longterm
+// key. It allows the same public key to appear several times in the list,
+// e.g. for a participant holding several shares, where NewVerifier always
+// returns the verifier of its first share.
+func NewVerifierAtIndex(suite Suite, longterm kyber.Scalar, dealerKey kyber.Point,
+	verifiers []kyber.Point, index int) (*Verifier, error) {
+
+	pub := suite.Point().Mul(longterm, nil)
+	if index < 0 || index >= len(verifiers) || !verifiers[index].Equal(pub) {
+		return nil, fmt.Errorf("vss: public key not found at index %d of the list of verifiers", index)
 	}
 	v := &Verifier{
 		suite:       suite,
@@ -341,6 +500,8 @@ func NewVerifier(suite Suite, longterm kyber.Scalar, dealerKey kyber.Point,
 		hkdfContext: context(suite, dealerKey, verifiers),
 		Aggregator:  NewEmptyAggregator(suite, verifiers),
 	}
+	// the dealer key authenticates the justifications
+	v.Aggregator.dealer = dealerKey
 	return v, nil
 }
 
@@ -353,18 +514,14 @@ func NewVerifier(suite Suite, longterm kyber.Scalar, dealerKey kyber.Point,
 // broadcasted to every other participants including the dealer.
 // If the deal has already been received, or the signature generation of the
 // response failed, it returns an error without any responses.
+//
+// The session ID of the deal is computed from its commitments and compared to
+// the one it embeds, and to the one of the first deal processed if any. If they
+// differ, a SessionIDMismatchError is returned without any response: in
+// particular, a second deal with other commitments from the same dealer is
+// rejected this way.
 func (v *Verifier) ProcessEncryptedDeal(e *EncryptedDeal) (*Response, error) {
-	d, err := v.decryptDeal(e)
-	if err != nil {
-		return nil, err
-	}
-	if d.SecShare.I != v.index {
-		return nil, errors.New("vss: verifier got wrong index from deal")
-	}
-
-	t := int(d.T)
-
-	sid, err := sessionID(v.suite, v.dealer, v.verifiers, d.Commitments, t)
+	d, sid, err := v.openDeal(e)
 	if err != nil {
 		return nil, err
 	}
@@ -378,7 +535,7 @@ func (v *Verifier) ProcessEncryptedDeal(e *EncryptedDeal) (*Response, error) {
 		r.Status = StatusComplaint
 	}
 
-	if err == errDealAlreadyProcessed {
+	if errors.Is(err, ErrDealAlreadyProcessed) {
 		return nil, err
 	}
 
@@ -392,29 +549,227 @@ func (v *Verifier) ProcessEncryptedDeal(e *EncryptedDeal) (*Response, error) {
 	return r, nil
 }
 
+// VerifyDealOnly runs the checks of ProcessEncryptedDeal on the encrypted deal
+// without storing it nor issuing any response, so that it can be called before
+// ProcessEncryptedDeal, e.g. to filter the messages of a network layer. It
+// returns the decrypted deal, which holds the share of the verifier, if the
+// signature, the encryption, the session ID, the threshold and the share
+// against the commitments are all valid. Otherwise, it returns the error that
+// ProcessEncryptedDeal would return, or, for a deal that would give a
+// complaint, an error wrapping ErrInvalidDeal or ErrInvalidSessionID. It does
+// not check whether a deal has already been processed.
+func (v *Verifier) VerifyDealOnly(e *EncryptedDeal) (*Deal, error) {
+	d, sid, err := v.openDeal(e)
+	if err != nil {
+		return nil, err
+	}
+	t := int(d.T)
+	if v.deal != nil {
+		t, sid = v.t, v.sid
+	}
+	if err := v.Aggregator.verifyDeal(d, t, sid); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// openDeal decrypts the deal and checks that it is well formed, for the index
+// of the verifier, and of the expected session. It returns the deal and the
+// session ID computed from its commitments.
+func (v *Verifier) openDeal(e *EncryptedDeal) (*Deal, []byte, error) {
+	d, err := v.decryptDeal(e)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := checkDeal(d); err != nil {
+		return nil, nil, err
+	}
+	if d.SecShare.I != v.index {
+		return nil, nil, fmt.Errorf("%w: verifier got wrong index from deal", ErrDealOutOfIndex)
+	}
+
+	sid, err := sessionID(v.suite, v.dealer, v.verifiers, d.Commitments, v.Aggregator.xs, int(d.T), v.nonce)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !bytes.Equal(sid, d.SessionID) {
+		return nil, nil, &SessionIDMismatchError{Expected: sid, Got: d.SessionID}
+	}
+	if v.sid != nil && !bytes.Equal(sid, v.sid) {
+		// the dealer equivocates with another deal for the same verifiers
+		return nil, nil, &SessionIDMismatchError{Expected: v.sid, Got: sid}
+	}
+	return d, sid, nil
+}
+
+// DecryptionProof reveals the Diffie-Hellman key of an encrypted deal, i.e. the
+// ephemeral key of the dealer multiplied by the longterm key of the verifier,
+// along with a DLEQ proof that it is, so that anybody can decrypt the deal
+// with DecryptDeal, e.g. to check the complaint of the verifier about its
+// share. Since the dealer picks an ephemeral key per deal, the other deals
+// remain secret, as does the longterm key.
+type DecryptionProof struct {
+	Key   kyber.Point
+	Proof *dleq.Proof
+}
+
+// ProveDecryption returns the proof revealing the key of the encrypted deal of
+// this verifier. It returns an error if the deal is not for this verifier, or
+// is not signed by the dealer. Only the deals of EncryptedDealVersion, whose
+// encryption authenticates the session ID and the index, can be proven.
+func (v *Verifier) ProveDecryption(e *EncryptedDeal) (*DecryptionProof, error) {
+	dhKey, err := dealKey(v.suite, v.dealer, e)
+	if err != nil {
+		return nil, err
+	}
+	if e.Index != uint32(v.index) {
+		return nil, fmt.Errorf("%w: deal for verifier %d", ErrDealOutOfIndex, e.Index)
+	}
+	proof, _, key, err := dleq.NewDLEQProof(v.suite, v.suite.Point().Base(), dhKey, v.longterm)
+	if err != nil {
+		return nil, err
+	}
+	return &DecryptionProof{Key: key, Proof: proof}, nil
+}
+
+// DecryptDeal decrypts the encrypted deal of the dealer for the verifier of
+// its index, among the given verifiers, with the key revealed by the proof of
+// the verifier. It returns an error if the deal is not signed by the dealer,
+// if the proof is not valid, or if the deal cannot be decrypted, in which case
+// the verifier may have given the wrong proof. The share of the deal is not
+// checked.
+func DecryptDeal(suite Suite, dealer kyber.Point, verifiers []kyber.Point, e *EncryptedDeal, p *DecryptionProof) (*Deal, error) {
+	dhKey, err := dealKey(suite, dealer, e)
+	if err != nil {
+		return nil, err
+	}
+	pub, ok := findPub(verifiers, e.Index)
+	if !ok {
+		return nil, fmt.Errorf("%w: deal for verifier %d", ErrDealOutOfIndex, e.Index)
+	}
+	if p == nil || p.Key == nil || p.Proof == nil {
+		return nil, fmt.Errorf("%w: nil decryption proof", ErrMalformed)
+	}
+	if err := p.Proof.Verify(suite, suite.Point().Base(), dhKey, pub, p.Key); err != nil {
+		return nil, fmt.Errorf("vss: invalid decryption proof: %w", err)
+	}
+	hkdfContext := context(suite, dealer, verifiers)
+	return decryptWithKey(suite, p.Key, hkdfContext, dealAD(hkdfContext, e.SessionID, e.Index), e)
+}
+
+// dealKey returns the ephemeral key of the encrypted deal, once checked that
+// the dealer signed it.
+func dealKey(suite Suite, dealer kyber.Point, e *EncryptedDeal) (kyber.Point, error) {
+	switch {
+	case e == nil:
+		return nil, fmt.Errorf("%w: nil encrypted deal", ErrMalformed)
+	case e.Version == 0:
+		return nil, ErrLegacyDeal
+	case e.Version != EncryptedDealVersion:
+		return nil, fmt.Errorf("%w: unknown deal version %d", ErrMalformed, e.Version)
+	}
+	if err := schnorr.Verify(suite, dealer, e.DHKey, e.Signature); err != nil {
+		return nil, fmt.Errorf("%w of encrypted deal: %v", ErrInvalidSignature, err)
+	}
+	dhKey := suite.Point()
+	if err := dhKey.UnmarshalBinary(e.DHKey); err != nil {
+		return nil, fmt.Errorf("%w: invalid DH key: %v", ErrMalformed, err)
+	}
+	return dhKey, nil
+}
+
+// SetAcceptLegacyDeals makes the verifier accept, or not, the deals of version
+// 0, whose encryption does not authenticate their session ID and their index,
+// see EncryptedDealVersion. They are rejected with ErrLegacyDeal by default.
+// It is only meant for the migration to version 1 and will be removed in the
+// next release.
+func (v *Verifier) SetAcceptLegacyDeals(accept bool) {
+	v.acceptLegacy = accept
+}
+
+// SetNonce makes the verifier expect the session ID bound to the nonce given
+// to Dealer.SetNonce, and reject the deals of any other nonce with a
+// SessionIDMismatchError. It must be called before processing the deal.
+func (v *Verifier) SetNonce(nonce []byte) {
+	v.nonce = append([]byte(nil), nonce...)
+}
+
 func (v *Verifier) decryptDeal(e *EncryptedDeal) (*Deal, error) {
+	if e == nil {
+		return nil, fmt.Errorf("%w: nil encrypted deal", ErrMalformed)
+	}
+	var ad []byte
+	switch {
+	case e.Version == 0 && v.acceptLegacy:
+		ad = v.hkdfContext
+	case e.Version == 0:
+		return nil, ErrLegacyDeal
+	case e.Version == EncryptedDealVersion:
+		if e.Index != uint32(v.index) {
+			return nil, fmt.Errorf("%w: deal for verifier %d", ErrDealOutOfIndex, e.Index)
+		}
+		ad = dealAD(v.hkdfContext, e.SessionID, e.Index)
+	default:
+		return nil, fmt.Errorf("%w: unknown deal version %d", ErrMalformed, e.Version)
+	}
 	// verify signature
 	if err := schnorr.Verify(v.suite, v.dealer, e.DHKey, e.Signature); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w of encrypted deal: %v", ErrInvalidSignature, err)
 	}
 
 	// compute shared key and AES526-GCM cipher
 	dhKey := v.suite.Point()
 	if err := dhKey.UnmarshalBinary(e.DHKey); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: invalid DH key: %v", ErrMalformed, err)
 	}
 	pre := dhExchange(v.suite, v.longterm, dhKey)
-	gcm, err := newAEAD(v.suite.Hash, pre, v.hkdfContext)
+	return decryptWithKey(v.suite, pre, v.hkdfContext, ad, e)
+}
+
+// decryptWithKey decrypts the deal with the Diffie-Hellman key pre, and checks
+// its session ID against the one of the encrypted deal.
+func decryptWithKey(suite Suite, pre kyber.Point, context, ad []byte, e *EncryptedDeal) (*Deal, error) {
+	gcm, err := newAEAD(suite.Hash, pre, context)
 	if err != nil {
 		return nil, err
 	}
-	decrypted, err := gcm.Open(nil, e.Nonce, e.Cipher, v.hkdfContext)
+	if len(e.Nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("%w: invalid nonce length %d", ErrMalformed, len(e.Nonce))
+	}
+	decrypted, err := gcm.Open(nil, e.Nonce, e.Cipher, ad)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %v", ErrDealDecryption, err)
 	}
+	// the plaintext holds the share, which the decoded deal holds from now on
+	defer wipe.Bytes(decrypted)
 	deal := &Deal{}
-	err = deal.decode(v.suite, decrypted)
-	return deal, err
+	if err := deal.decode(suite, decrypted); err != nil {
+		return nil, fmt.Errorf("%w: cannot decode deal: %v", ErrMalformed, err)
+	}
+	// the decoded byte slices point into the plaintext
+	deal.SessionID = append([]byte(nil), deal.SessionID...)
+	if e.Version != 0 && !bytes.Equal(deal.SessionID, e.SessionID) {
+		return nil, fmt.Errorf("%w: encrypted deal of another session", ErrInvalidSessionID)
+	}
+	return deal, nil
+}
+
+// checkDeal returns an error if the deal misses its share or its commitments.
+func checkDeal(d *Deal) error {
+	switch {
+	case d == nil:
+		return fmt.Errorf("%w: nil deal", ErrMalformed)
+	case d.SecShare == nil || d.SecShare.V == nil:
+		return fmt.Errorf("%w: deal without share", ErrMalformed)
+	case len(d.Commitments) == 0:
+		return fmt.Errorf("%w: deal without commitments", ErrMalformed)
+	}
+	for _, c := range d.Commitments {
+		if c == nil {
+			return fmt.Errorf("%w: nil commitment", ErrMalformed)
+		}
+	}
+	return nil
 }
 
 // ErrNoDealBeforeResponse is an error returned if a verifier receives a
@@ -433,6 +788,16 @@ func (v *Verifier) ProcessResponse(resp *Response) error {
 	return v.Aggregator.verifyResponse(resp)
 }
 
+// UnsafeProcessResponse is like ProcessResponse but does not verify the
+// signature of the response, which the caller MUST have checked beforehand
+// (see Aggregator.UnsafeProcessResponse).
+func (v *Verifier) UnsafeProcessResponse(resp *Response) error {
+	if v.Aggregator.deal == nil {
+		return ErrNoDealBeforeResponse
+	}
+	return v.Aggregator.UnsafeProcessResponse(resp)
+}
+
 // Commits returns the commitments of the coefficients of the polynomial
 // contained in the Deal received. It is public information. The private
 // information in the deal must be retrieved through Deal().
@@ -449,12 +814,30 @@ func (v *Verifier) Deal() *Deal {
 	return v.deal
 }
 
+// Zero erases the share of the deal received by the verifier once it is no
+// longer needed, e.g. once the share of a distributed key has been computed
+// from it. Deal then returns the deal with a zero share. The erasure is
+// best-effort, see the wipe package.
+func (v *Verifier) Zero() {
+	if v.deal != nil && v.deal.SecShare != nil {
+		v.deal.SecShare.Zero()
+	}
+}
+
 // ProcessJustification takes a DealerResponse and returns an error if
 // something went wrong during the verification. If it is the case, that
 // probably means the Dealer is acting maliciously. In order to be sure, call
-// `v.DealCertified()`.
+// `v.DealCertified()`. A valid justification of the complaint of the verifier
+// itself replaces the wrong share it received with the revealed one.
 func (v *Verifier) ProcessJustification(dr *Justification) error {
-	return v.Aggregator.verifyJustification(dr)
+	if err := v.Aggregator.verifyJustification(dr); err != nil {
+		return err
+	}
+	if dr.Index == uint32(v.index) && v.deal != nil {
+		v.deal.SecShare.Zero()
+		v.deal.SecShare = &share.PriShare{I: dr.Deal.SecShare.I, V: dr.Deal.SecShare.V.Clone()}
+	}
+	return nil
 }
 
 // Key returns the longterm key pair this verifier is using during this protocol
@@ -469,25 +852,32 @@ func (v *Verifier) Index() int {
 	return v.index
 }
 
-// SessionID returns the session id generated by the Dealer. It returns
-// an nil slice if the verifier has not received the Deal yet.
+// SessionID returns the session id generated by the Dealer, which is the one
+// computed by SessionID from the deal received. It returns a nil slice if the
+// verifier has not received the Deal yet.
 func (v *Verifier) SessionID() []byte {
 	return v.sid
 }
 
 // RecoverSecret recovers the secret shared by a Dealer by gathering at least t
 // Deals from the verifiers. It returns an error if there is not enough Deals or
-// if all Deals don't have the same SessionID.
+// if all Deals don't have the same SessionID. Deals issued with arbitrary
+// evaluation points are interpolated at these points.
 func RecoverSecret(suite Suite, deals []*Deal, n, t int) (kyber.Scalar, error) {
 	shares := make([]*share.PriShare, len(deals))
+	xshares := make([]*share.XShare, len(deals))
 	for i, deal := range deals {
 		// all sids the same
 		if bytes.Equal(deal.SessionID, deals[0].SessionID) {
 			shares[i] = deal.SecShare
+			xshares[i] = &share.XShare{X: deal.X, V: deal.SecShare.V}
 		} else {
 			return nil, errors.New("vss: all deals need to have same session id")
 		}
 	}
+	if len(deals) > 0 && deals[0].X != nil {
+		return share.RecoverSecretFromXShares(suite, xshares, t)
+	}
 	return share.RecoverSecret(suite, shares, t, n)
 }
 
@@ -525,6 +915,8 @@ type Aggregator struct {
 	t         int
 	badDealer bool
 	timeout   bool
+	// evaluation points of the verifiers' shares, nil for the default ones
+	xs []kyber.Scalar
 }
 
 func newAggregator(suite Suite, dealer kyber.Point, verifiers, commitments []kyber.Point, t int, sid []byte) *Aggregator {
@@ -550,14 +942,99 @@ func NewEmptyAggregator(suite Suite, verifiers []kyber.Point) *Aggregator {
 	}
 }
 
-var errDealAlreadyProcessed = errors.New("vss: verifier already received a deal")
+// Errors returned when processing deals, responses and justifications. They
+// may be wrapped with more context, so they must be checked with errors.Is.
+var (
+	// ErrDealAlreadyProcessed is returned when a verifier receives a second
+	// deal.
+	ErrDealAlreadyProcessed = errors.New("vss: verifier already received a deal")
+	// ErrDealOutOfIndex is returned when a deal holds the share of a wrong or
+	// unknown verifier.
+	ErrDealOutOfIndex = errors.New("vss: index out of bounds in Deal")
+	// ErrInvalidDeal is returned when a deal is inconsistent with the
+	// protocol parameters or its share does not verify.
+	ErrInvalidDeal = errors.New("vss: invalid deal")
+	// ErrResponseOutOfIndex is returned when a response comes from an unknown
+	// verifier.
+	ErrResponseOutOfIndex = errors.New("vss: index out of bounds in response")
+	// ErrDuplicateResponse is returned when a response from the same verifier
+	// has already been stored, e.g. when a response is received twice. It is
+	// harmless and can be ignored by the caller.
+	ErrDuplicateResponse = errors.New("vss: already existing response from same origin")
+	// ErrInvalidSessionID is returned when a deal or a response belongs to
+	// another session.
+	ErrInvalidSessionID = errors.New("vss: inconsistent session id")
+	// ErrSessionIDMismatch is matched by the SessionIDMismatchError returned
+	// when the session ID of a deal differs from the one computed by the
+	// verifier.
+	ErrSessionIDMismatch = errors.New("vss: session id mismatch")
+	// ErrInvalidSignature is returned when the signature of an encrypted deal
+	// or of a response does not verify.
+	ErrInvalidSignature = errors.New("vss: invalid signature")
+	// ErrUnexpectedJustification is returned when a justification does not
+	// answer a stored complaint.
+	ErrUnexpectedJustification = errors.New("vss: unexpected justification")
+	// ErrMalformed is returned when a deal, a response or a justification
+	// misses a field, or when an encrypted deal cannot be decrypted and
+	// decoded.
+	ErrMalformed = errors.New("vss: malformed message")
+	// ErrDealDecryption is returned when an encrypted deal does not decrypt,
+	// e.g. when it is meant for another verifier or has been tampered with.
+	// It wraps ErrMalformed.
+	ErrDealDecryption = fmt.Errorf("%w: cannot decrypt deal", ErrMalformed)
+	// ErrWrongIndex is returned by a dealer asked for the deal of a verifier
+	// it does not know.
+	ErrWrongIndex = errors.New("vss: no verifier at this index")
+	// ErrLegacyDeal is returned for a deal of version 0 by a verifier which
+	// does not accept them, see EncryptedDealVersion.
+	ErrLegacyDeal = errors.New("vss: legacy deal encryption")
+	// ErrWiped is returned by a dealer asked for a deal after Zero erased
+	// them.
+	ErrWiped = errors.New("vss: dealer secret material wiped")
+)
+
+// SessionIDMismatchError is returned by ProcessEncryptedDeal for a deal whose
+// session ID is not the expected one. It matches both ErrSessionIDMismatch and
+// ErrInvalidSessionID with errors.Is.
+type SessionIDMismatchError struct {
+	Expected []byte // session id computed by the verifier or of the first deal
+	Got      []byte // session id of the rejected deal
+}
+
+func (e *SessionIDMismatchError) Error() string {
+	return fmt.Sprintf("%s: expected %x, got %x", ErrSessionIDMismatch, e.Expected, e.Got)
+}
+
+// Is returns true for ErrSessionIDMismatch and ErrInvalidSessionID.
+func (e *SessionIDMismatchError) Is(target error) bool {
+	return target == ErrSessionIDMismatch || target == ErrInvalidSessionID
+}
+
+// InvalidShareError is returned when the share of a deal does not verify
+// against its commitments, which gives a complaint against the dealer. It
+// matches ErrInvalidDeal with errors.Is.
+type InvalidShareError struct {
+	Index int // index of the share
+}
+
+func (e *InvalidShareError) Error() string {
+	return fmt.Sprintf("%s: share %d does not verify against commitments", ErrInvalidDeal, e.Index)
+}
+
+// Is returns true for ErrInvalidDeal.
+func (e *InvalidShareError) Is(target error) bool {
+	return target == ErrInvalidDeal
+}
 
 // VerifyDeal analyzes the deal and returns an error if it's incorrect. If
 // inclusion is true, it also returns an error if it is the second time this struct
 // analyzes a Deal.
 func (a *Aggregator) VerifyDeal(d *Deal, inclusion bool) error {
+	if err := checkDeal(d); err != nil {
+		return err
+	}
 	if a.deal != nil && inclusion {
-		return errDealAlreadyProcessed
+		return ErrDealAlreadyProcessed
 
 	}
 	if a.deal == nil {
@@ -566,32 +1043,66 @@ func (a *Aggregator) VerifyDeal(d *Deal, inclusion bool) error {
 		a.deal = d
 		a.t = int(d.T)
 	}
+	return a.verifyDeal(d, a.t, a.sid)
+}
 
+// verifyDeal checks the deal, which must have passed checkDeal, against the
+// threshold t and the session ID sid of the first deal, without modifying the
+// aggregator.
+func (a *Aggregator) verifyDeal(d *Deal, t int, sid []byte) error {
 	if !validT(int(d.T), a.verifiers) {
-		return errors.New("vss: invalid t received in Deal")
+		return fmt.Errorf("%w: invalid t %d", ErrInvalidDeal, d.T)
 	}
 
-	if int(d.T) != a.t {
-		return errors.New("vss: incompatible threshold - potential attack")
+	if int(d.T) != t {
+		return fmt.Errorf("%w: incompatible threshold - potential attack", ErrInvalidDeal)
 	}
 
-	if !bytes.Equal(a.sid, d.SessionID) {
-		return errors.New("vss: find different sessionIDs from Deal")
+	if len(d.Commitments) != t {
+		return fmt.Errorf("%w: %d commitments for threshold %d", ErrInvalidDeal, len(d.Commitments), t)
+	}
+
+	if !bytes.Equal(sid, d.SessionID) {
+		return fmt.Errorf("%w in Deal", ErrInvalidSessionID)
 	}
 
 	fi := d.SecShare
 	if fi.I < 0 || fi.I >= len(a.verifiers) {
-		return errors.New("vss: index out of bounds in Deal")
+		return ErrDealOutOfIndex
 	}
 	// compute fi * G
 	fig := a.suite.Point().Base().Mul(fi.V, nil)
 
 	commitPoly := share.NewPubPoly(a.suite, nil, d.Commitments)
 
-	pubShare := commitPoly.Eval(fi.I)
-	if !fig.Equal(pubShare.V) {
-		return errors.New("vss: share does not verify against commitments in Deal")
+	var pubShare kyber.Point
+	if a.xs != nil {
+		if d.X == nil || !d.X.Equal(a.xs[fi.I]) {
+			return fmt.Errorf("%w: wrong evaluation point", ErrInvalidDeal)
+		}
+		pubShare = commitPoly.EvalScalar(a.xs[fi.I]).V
+	} else {
+		if d.X != nil {
+			return fmt.Errorf("%w: unexpected evaluation point", ErrInvalidDeal)
+		}
+		pubShare = commitPoly.Eval(fi.I).V
+	}
+	if !fig.Equal(pubShare) {
+		return &InvalidShareError{Index: fi.I}
+	}
+	return nil
+}
+
+// SetEvaluationPoints is used to specify *before* the verifier receives
+// anything that the share of the i-th verifier is evaluated at the arbitrary
+// point xs[i], as done by a dealer created with NewDealerWithPoints. It
+// returns an error if there is not exactly one non-zero and distinct
+// evaluation point per verifier.
+func (a *Aggregator) SetEvaluationPoints(xs []kyber.Scalar) error {
+	if err := validPoints(a.suite, xs, a.verifiers); err != nil {
+		return err
 	}
+	a.xs = xs
 	return nil
 }
 
@@ -611,36 +1122,73 @@ func (a *Aggregator) ProcessResponse(r *Response) error {
 	return a.verifyResponse(r)
 }
 
+// UnsafeProcessResponse is an UNSAFE bypass method to allow DKG to verify the
+// signatures of many responses in parallel. It stores the response like
+// ProcessResponse but does not verify its signature, which the caller MUST
+// have checked beforehand.
+func (a *Aggregator) UnsafeProcessResponse(r *Response) error {
+	return a.processResponse(r, false)
+}
+
 func (a *Aggregator) verifyResponse(r *Response) error {
+	return a.processResponse(r, true)
+}
+
+func (a *Aggregator) processResponse(r *Response, checkSig bool) error {
+	if r == nil {
+		return fmt.Errorf("%w: nil response", ErrMalformed)
+	}
 	if a.sid != nil && !bytes.Equal(r.SessionID, a.sid) {
-		return errors.New("vss: receiving inconsistent sessionID in response")
+		return fmt.Errorf("%w in response", ErrInvalidSessionID)
 	}
 
 	pub, ok := findPub(a.verifiers, r.Index)
 	if !ok {
-		return errors.New("vss: index out of bounds in response")
+		return ErrResponseOutOfIndex
 	}
 
-	if err := schnorr.Verify(a.suite, pub, r.Hash(a.suite), r.Signature); err != nil {
-		return err
+	if checkSig {
+		if err := schnorr.Verify(a.suite, pub, r.Hash(a.suite), r.Signature); err != nil {
+			return fmt.Errorf("%w of response: %v", ErrInvalidSignature, err)
+		}
 	}
 
 	return a.addResponse(r)
 }
 
+// verifyJustification checks that the justification answers a stored
+// complaint and that it is correctly signed by the dealer, before verifying
+// the deal it reveals. The dealer is only flagged as malicious once the
+// justification is known to come from it, so that nobody else can disqualify
+// it with a forged justification.
 func (a *Aggregator) verifyJustification(j *Justification) error {
+	if j == nil {
+		return fmt.Errorf("%w: nil justification", ErrMalformed)
+	}
+	if a.deal == nil {
+		return fmt.Errorf("%w: justification received before the deal", ErrUnexpectedJustification)
+	}
+	if !bytes.Equal(j.SessionID, a.sid) {
+		return fmt.Errorf("%w in justification", ErrInvalidSessionID)
+	}
 	if _, ok := findPub(a.verifiers, j.Index); !ok {
-		return errors.New("vss: index out of bounds in justification")
+		return fmt.Errorf("%w: index out of bounds", ErrUnexpectedJustification)
 	}
 	r, ok := a.responses[j.Index]
 	if !ok {
-		return errors.New("vss: no complaints received for this justification")
+		return fmt.Errorf("%w: no complaints received for this justification", ErrUnexpectedJustification)
 	}
 	if r.Status != StatusComplaint {
-		return errors.New("vss: justification received for an approval")
+		return fmt.Errorf("%w: justification received for an approval", ErrUnexpectedJustification)
+	}
+	if err := checkDeal(j.Deal); err != nil {
+		return err
+	}
+	if err := schnorr.Verify(a.suite, a.dealer, j.Hash(a.suite), j.Signature); err != nil {
+		return fmt.Errorf("%w of justification: %v", ErrInvalidSignature, err)
 	}
 
-	if err := a.VerifyDeal(j.Deal, false); err != nil {
+	if err := a.verifyJustifiedDeal(j); err != nil {
 		// if one justification is bad, then flag the dealer as malicious
 		a.badDealer = true
 		return err
@@ -649,12 +1197,32 @@ func (a *Aggregator) verifyJustification(j *Justification) error {
 	return nil
 }
 
+// verifyJustifiedDeal checks that the deal of a justification is the share of
+// the complaining verifier, and that it is consistent with the commitments of
+// the deal received at first.
+func (a *Aggregator) verifyJustifiedDeal(j *Justification) error {
+	d := j.Deal
+	if d.SecShare.I < 0 || uint32(d.SecShare.I) != j.Index {
+		return fmt.Errorf("%w: justification reveals the share of verifier %d instead of %d",
+			ErrInvalidDeal, d.SecShare.I, j.Index)
+	}
+	if len(d.Commitments) != len(a.commits) {
+		return fmt.Errorf("%w: justification with different commitments", ErrInvalidDeal)
+	}
+	for i, c := range d.Commitments {
+		if !c.Equal(a.commits[i]) {
+			return fmt.Errorf("%w: justification with different commitments", ErrInvalidDeal)
+		}
+	}
+	return a.VerifyDeal(d, false)
+}
+
 func (a *Aggregator) addResponse(r *Response) error {
 	if _, ok := findPub(a.verifiers, r.Index); !ok {
-		return errors.New("vss: index out of bounds in Complaint")
+		return ErrResponseOutOfIndex
 	}
 	if _, ok := a.responses[r.Index]; ok {
-		return errors.New("vss: already existing response from same origin")
+		return ErrDuplicateResponse
 	}
 	a.responses[r.Index] = r
 	return nil
@@ -703,6 +1271,13 @@ func (a *Aggregator) DealCertified() bool {
 	return baseCondition && !(absentVerifiers > 0)
 }
 
+// SetTimeout marks the end of the protocol for this aggregator, so that the
+// deal can be certified even if some responses are missing (see
+// DealCertified).
+func (a *Aggregator) SetTimeout() {
+	a.timeout = true
+}
+
 // MissingResponses returns the indexes of the expected but missing responses.
 func (a *Aggregator) MissingResponses() []int {
 	var absents []int
@@ -727,6 +1302,24 @@ func validT(t int, verifiers []kyber.Point) bool {
 	return t >= 2 && t <= len(verifiers) && int(uint32(t)) == t
 }
 
+func validPoints(suite Suite, xs []kyber.Scalar, verifiers []kyber.Point) error {
+	if len(xs) != len(verifiers) {
+		return errors.New("vss: need one evaluation point per verifier")
+	}
+	zero := suite.Scalar().Zero()
+	seen := make(map[string]bool)
+	for _, x := range xs {
+		if x == nil || x.Equal(zero) {
+			return errors.New("vss: invalid evaluation point")
+		}
+		if seen[x.String()] {
+			return errors.New("vss: duplicate evaluation point")
+		}
+		seen[x.String()] = true
+	}
+	return nil
+}
+
 func deriveH(suite Suite, verifiers []kyber.Point) kyber.Point {
 	var b bytes.Buffer
 	for _, v := range verifiers {
@@ -744,7 +1337,31 @@ func findPub(verifiers []kyber.Point, idx uint32) (kyber.Point, bool) {
 	return verifiers[iidx], true
 }
 
-func sessionID(suite Suite, dealer kyber.Point, verifiers, commitments []kyber.Point, t int) ([]byte, error) {
+// SessionID returns the session ID of the deals of the given dealer for the
+// verifiers, with the given commitments and threshold, as embedded in the
+// deals and the responses. Two deals of the same dealer for the same verifiers
+// with different session IDs belong to two different sharings. The dealers
+// created with NewDealerWithPoints use SessionIDWithPoints instead.
+func SessionID(suite Suite, dealer kyber.Point, verifiers, commitments []kyber.Point, t int) ([]byte, error) {
+	return sessionID(suite, dealer, verifiers, commitments, nil, t, nil)
+}
+
+// SessionIDWithNonce works like SessionID for the deals of a dealer bound to
+// the nonce, see Dealer.SetNonce.
+func SessionIDWithNonce(suite Suite, dealer kyber.Point, verifiers, commitments []kyber.Point, t int, nonce []byte) ([]byte, error) {
+	return sessionID(suite, dealer, verifiers, commitments, nil, t, nonce)
+}
+
+// SessionIDWithPoints works like SessionID for the deals evaluated at the
+// arbitrary points xs, see NewDealerWithPoints.
+func SessionIDWithPoints(suite Suite, dealer kyber.Point, verifiers, commitments []kyber.Point, xs []kyber.Scalar, t int) ([]byte, error) {
+	return sessionID(suite, dealer, verifiers, commitments, xs, t, nil)
+}
+
+// sessionID hashes the parameters of the sharing. The nonce, if any, is
+// appended with its length, so that the session IDs without nonce do not
+// change.
+func sessionID(suite Suite, dealer kyber.Point, verifiers, commitments []kyber.Point, xs []kyber.Scalar, t int, nonce []byte) ([]byte, error) {
 	h := suite.Hash()
 	_, _ = dealer.MarshalTo(h)
 
@@ -755,7 +1372,15 @@ func sessionID(suite Suite, dealer kyber.Point, verifiers, commitments []kyber.P
 	for _, c := range commitments {
 		_, _ = c.MarshalTo(h)
 	}
+
+	for _, x := range xs {
+		_, _ = x.MarshalTo(h)
+	}
 	_ = binary.Write(h, binary.LittleEndian, uint32(t))
+	if len(nonce) > 0 {
+		_ = binary.Write(h, binary.LittleEndian, uint32(len(nonce)))
+		_, _ = h.Write(nonce)
+	}
 
 	return h.Sum(nil), nil
 }
@@ -1,6 +1,7 @@
 package vss
 
 import (
+	"errors"
 	"math/rand"
 	"testing"
 
@@ -8,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.dedis.ch/kyber/v3"
 	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/share"
 	"go.dedis.ch/kyber/v3/sign/schnorr"
 	"go.dedis.ch/kyber/v3/xof/blake2xb"
 	"go.dedis.ch/protobuf"
@@ -86,6 +88,116 @@ func TestVSSWhole(t *testing.T) {
 	require.Equal(t, secret.String(), priCoeffs[0].String())
 }
 
+func TestVSSZero(t *testing.T) {
+	// the dealer erases the secret it is given
+	dealer, err := NewDealer(suite, dealerSec, secret.Clone(), verifiersPub, vssThreshold)
+	require.NoError(t, err)
+	_, verifiers := genAll()
+	encDeals, err := dealer.EncryptedDeals()
+	require.NoError(t, err)
+	resps := make([]*Response, nbVerifiers)
+	for i, d := range encDeals {
+		resps[i], err = verifiers[i].ProcessEncryptedDeal(d)
+		require.NoError(t, err)
+	}
+	for i, resp := range resps {
+		_, err = dealer.ProcessResponse(resp)
+		require.NoError(t, err)
+		for j, v := range verifiers {
+			if i != j {
+				require.NoError(t, v.ProcessResponse(resp))
+			}
+		}
+	}
+	require.True(t, dealer.DealCertified())
+	commit := dealer.SecretCommit()
+	deal, err := dealer.PlaintextDeal(0)
+	require.NoError(t, err)
+
+	zero := suite.Scalar().Zero()
+	dealer.Zero()
+	require.True(t, dealer.PrivatePoly().Secret().Equal(zero))
+	require.True(t, deal.SecShare.V.Equal(zero))
+	require.True(t, dealer.SecretCommit().Equal(commit))
+	_, err = dealer.PlaintextDeal(0)
+	require.True(t, errors.Is(err, ErrWiped))
+	_, err = dealer.EncryptedDeal(0)
+	require.True(t, errors.Is(err, ErrWiped))
+	complaint := &Response{SessionID: dealer.SessionID(), Index: 1, Status: StatusComplaint}
+	complaint.Signature, err = schnorr.Sign(suite, verifiersSec[1], complaint.Hash(suite))
+	require.NoError(t, err)
+	dealer.Aggregator.responses = make(map[uint32]*Response)
+	_, err = dealer.ProcessResponse(complaint)
+	require.True(t, errors.Is(err, ErrWiped))
+
+	// the verifiers keep the session ID of the deal they erase
+	deal = verifiers[0].Deal()
+	verifiers[0].Zero()
+	require.True(t, deal.SecShare.V.Equal(zero))
+	require.Equal(t, dealer.SessionID(), deal.SessionID)
+}
+
+func TestVSSWholeWithPoints(t *testing.T) {
+	xs, err := share.HashIndices(suite, suite, verifiersPub)
+	require.NoError(t, err)
+	dealer, err := NewDealerWithPoints(suite, dealerSec, secret, verifiersPub, xs, vssThreshold)
+	require.NoError(t, err)
+	verifiers := make([]*Verifier, nbVerifiers)
+	for i := 0; i < nbVerifiers; i++ {
+		v, err := NewVerifier(suite, verifiersSec[i], dealerPub, verifiersPub)
+		require.NoError(t, err)
+		require.NoError(t, v.SetEvaluationPoints(xs))
+		verifiers[i] = v
+	}
+
+	encDeals, err := dealer.EncryptedDeals()
+	require.NoError(t, err)
+	resps := make([]*Response, nbVerifiers)
+	for i, d := range encDeals {
+		resp, err := verifiers[i].ProcessEncryptedDeal(d)
+		require.NoError(t, err)
+		require.Equal(t, StatusApproval, resp.Status)
+		resps[i] = resp
+	}
+	for _, resp := range resps {
+		for i, v := range verifiers {
+			if resp.Index == uint32(i) {
+				continue
+			}
+			require.NoError(t, v.ProcessResponse(resp))
+		}
+	}
+
+	deals := make([]*Deal, nbVerifiers)
+	for i, v := range verifiers {
+		require.True(t, v.DealCertified())
+		deals[i] = v.Deal()
+		require.True(t, deals[i].X.Equal(xs[i]))
+	}
+	sec, err := RecoverSecret(suite, deals, nbVerifiers, vssThreshold)
+	require.NoError(t, err)
+	require.True(t, sec.Equal(secret))
+	sid, err := SessionIDWithPoints(suite, dealerPub, verifiersPub, dealer.Commits(), xs, vssThreshold)
+	require.NoError(t, err)
+	require.Equal(t, sid, verifiers[0].SessionID())
+
+	// a verifier not aware of the evaluation points rejects the deal
+	v, err := NewVerifier(suite, verifiersSec[0], dealerPub, verifiersPub)
+	require.NoError(t, err)
+	resp, err := v.ProcessEncryptedDeal(encDeals[0])
+	require.True(t, errors.Is(err, ErrSessionIDMismatch))
+	require.Nil(t, resp)
+
+	// invalid evaluation points
+	_, err = NewDealerWithPoints(suite, dealerSec, secret, verifiersPub, xs[1:], vssThreshold)
+	require.Error(t, err)
+	dup := append([]kyber.Scalar{xs[1]}, xs[1:]...)
+	_, err = NewDealerWithPoints(suite, dealerSec, secret, verifiersPub, dup, vssThreshold)
+	require.Error(t, err)
+	zero := append([]kyber.Scalar{suite.Scalar().Zero()}, xs[1:]...)
+	require.Error(t, v.SetEvaluationPoints(zero))
+}
+
 func TestVSSDealerNew(t *testing.T) {
 	goodT := MinimumT(nbVerifiers)
 	dealer, err := NewDealer(suite, dealerSec, secret, verifiersPub, goodT)
@@ -99,6 +211,23 @@ func TestVSSDealerNew(t *testing.T) {
 
 }
 
+func TestVSSDealerFromPoly(t *testing.T) {
+	dealer := genDealer()
+	restored, err := NewDealerFromPoly(suite, dealerSec, dealer.PrivatePoly(), verifiersPub, nil)
+	require.NoError(t, err)
+	require.Equal(t, dealer.SessionID(), restored.SessionID())
+	require.Equal(t, dealer.Commits(), restored.Commits())
+	d, err := restored.PlaintextDeal(2)
+	require.NoError(t, err)
+	require.True(t, d.SecShare.V.Equal(dealer.deals[2].SecShare.V))
+
+	short := share.NewPriPoly(suite, 1, secret, rng)
+	_, err = NewDealerFromPoly(suite, dealerSec, short, verifiersPub, nil)
+	require.Error(t, err)
+	_, err = NewDealerFromPoly(suite, dealerSec, dealer.PrivatePoly(), verifiersPub, make([]kyber.Scalar, 2))
+	require.Error(t, err)
+}
+
 func TestVSSVerifierNew(t *testing.T) {
 	randIdx := rand.Int() % len(verifiersPub)
 	v, err := NewVerifier(suite, verifiersSec[randIdx], dealerPub, verifiersPub)
@@ -207,9 +336,130 @@ func TestVSSVerifierDecryptDeal(t *testing.T) {
 	goodCipher := encD.Cipher
 	encD.Cipher = randomBytes(len(goodCipher))
 	decD, err = v.decryptDeal(encD)
-	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrDealDecryption))
+	assert.True(t, errors.Is(err, ErrMalformed))
 	assert.Nil(t, decD)
 	encD.Cipher = goodCipher
+
+	// no verifier at this index
+	_, err = dealer.EncryptedDeal(nbVerifiers)
+	assert.True(t, errors.Is(err, ErrWrongIndex))
+	_, err = dealer.PlaintextDeal(nbVerifiers)
+	assert.True(t, errors.Is(err, ErrWrongIndex))
+}
+
+// legacyEncryptedDeal encrypts the deal of the verifier at index i like the
+// dealers of version 0, which only authenticate the context.
+func legacyEncryptedDeal(t *testing.T, d *Dealer, i int) *EncryptedDeal {
+	dhSecret := suite.Scalar().Pick(suite.RandomStream())
+	dhPublic, err := suite.Point().Mul(dhSecret, nil).MarshalBinary()
+	require.NoError(t, err)
+	signature, err := schnorr.Sign(suite, d.long, dhPublic)
+	require.NoError(t, err)
+	gcm, err := newAEAD(suite.Hash, dhExchange(suite, dhSecret, d.verifiers[i]), d.hkdfContext)
+	require.NoError(t, err)
+	nonce := make([]byte, gcm.NonceSize())
+	dealBuff, err := protobuf.Encode(d.deals[i])
+	require.NoError(t, err)
+	return &EncryptedDeal{
+		DHKey:     dhPublic,
+		Signature: signature,
+		Nonce:     nonce,
+		Cipher:    gcm.Seal(nil, nonce, dealBuff, d.hkdfContext),
+	}
+}
+
+func TestVSSVerifierDecryptDealVersion(t *testing.T) {
+	dealer, verifiers := genAll()
+	v := verifiers[0]
+
+	encD, err := dealer.EncryptedDeal(0)
+	require.NoError(t, err)
+	require.Equal(t, EncryptedDealVersion, encD.Version)
+	require.Equal(t, dealer.sid, encD.SessionID)
+
+	// the session id and the index are authenticated
+	encD.SessionID = randomBytes(len(encD.SessionID))
+	_, err = v.decryptDeal(encD)
+	require.True(t, errors.Is(err, ErrMalformed))
+	encD.SessionID = dealer.sid
+	encD.Index = 1
+	_, err = v.decryptDeal(encD)
+	require.True(t, errors.Is(err, ErrDealOutOfIndex))
+	encD.Index = 0
+	encD.Version = EncryptedDealVersion + 1
+	_, err = v.decryptDeal(encD)
+	require.True(t, errors.Is(err, ErrMalformed))
+
+	// a verifier holding two shares cannot use the deal of one for the other
+	pubs := append([]kyber.Point{verifiersPub[0]}, verifiersPub...)
+	dealer2, err := NewDealer(suite, dealerSec, secret, pubs, vssThreshold)
+	require.NoError(t, err)
+	v2, err := NewVerifierAtIndex(suite, verifiersSec[0], dealerPub, pubs, 1)
+	require.NoError(t, err)
+	encD, err = dealer2.EncryptedDeal(0)
+	require.NoError(t, err)
+	encD.Index = 1
+	_, err = v2.decryptDeal(encD)
+	require.True(t, errors.Is(err, ErrMalformed))
+
+	// the deals of version 0 are only accepted during the migration
+	legacy := legacyEncryptedDeal(t, dealer, 0)
+	_, err = v.ProcessEncryptedDeal(legacy)
+	require.True(t, errors.Is(err, ErrLegacyDeal))
+	v.SetAcceptLegacyDeals(true)
+	resp, err := v.ProcessEncryptedDeal(legacy)
+	require.NoError(t, err)
+	require.Equal(t, StatusApproval, resp.Status)
+
+	// and a verifier of version 0 cannot decrypt the new deals
+	encD, err = dealer.EncryptedDeal(1)
+	require.NoError(t, err)
+	encD.Version, encD.SessionID, encD.Index = 0, nil, 0
+	verifiers[1].SetAcceptLegacyDeals(true)
+	_, err = verifiers[1].decryptDeal(encD)
+	require.True(t, errors.Is(err, ErrMalformed))
+}
+
+// encryptedDealV0 is the EncryptedDeal of the releases of version 0.
+type encryptedDealV0 struct {
+	DHKey     []byte
+	Signature []byte
+	Nonce     []byte
+	Cipher    []byte
+}
+
+func TestVSSEncryptedDealLegacyEncoding(t *testing.T) {
+	dealer, verifiers := genAll()
+	legacy := legacyEncryptedDeal(t, dealer, 0)
+	buff, err := protobuf.Encode(&encryptedDealV0{
+		DHKey:     legacy.DHKey,
+		Signature: legacy.Signature,
+		Nonce:     legacy.Nonce,
+		Cipher:    legacy.Cipher,
+	})
+	require.NoError(t, err)
+
+	// a deal encoded by a dealer of version 0 decodes to the same fields
+	decoded := &EncryptedDeal{}
+	require.NoError(t, protobuf.Decode(buff, decoded))
+	require.Equal(t, legacy, decoded)
+
+	v := verifiers[0]
+	v.SetAcceptLegacyDeals(true)
+	resp, err := v.ProcessEncryptedDeal(decoded)
+	require.NoError(t, err)
+	require.Equal(t, StatusApproval, resp.Status)
+
+	// and the fields of version 0 keep their numbers in the new deals
+	encD, err := dealer.EncryptedDeal(1)
+	require.NoError(t, err)
+	buff, err = protobuf.Encode(encD)
+	require.NoError(t, err)
+	old := &encryptedDealV0{}
+	require.NoError(t, protobuf.Decode(buff, old))
+	require.Equal(t, encD.DHKey, old.DHKey)
+	require.Equal(t, encD.Cipher, old.Cipher)
 }
 
 func TestVSSVerifierReceiveDeal(t *testing.T) {
@@ -235,7 +485,7 @@ func TestVSSVerifierReceiveDeal(t *testing.T) {
 	encD.Signature = randomBytes(32)
 	resp, err = v.ProcessEncryptedDeal(encD)
 	assert.Nil(t, resp)
-	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrInvalidSignature))
 	encD.Signature = goodSig
 
 	// wrong index
@@ -243,7 +493,7 @@ func TestVSSVerifierReceiveDeal(t *testing.T) {
 	d.SecShare.I = (goodIdx - 1) % nbVerifiers
 	encD, _ = dealer.EncryptedDeal(0)
 	resp, err = v.ProcessEncryptedDeal(encD)
-	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrDealOutOfIndex))
 	assert.Nil(t, resp)
 	d.SecShare.I = goodIdx
 
@@ -252,7 +502,7 @@ func TestVSSVerifierReceiveDeal(t *testing.T) {
 	d.Commitments[0] = suite.Point().Pick(rng)
 	encD, _ = dealer.EncryptedDeal(0)
 	resp, err = v.ProcessEncryptedDeal(encD)
-	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrSessionIDMismatch))
 	assert.Nil(t, resp)
 	d.Commitments[0] = goodCommit
 
@@ -273,11 +523,161 @@ func TestVSSVerifierReceiveDeal(t *testing.T) {
 	// valid complaint
 	v.Aggregator.deal = nil
 	delete(v.Aggregator.responses, uint32(v.index))
-	//d.RndShare.V = suite.Scalar().SetBytes(randomBytes(32))
+	goodShare := d.SecShare.V
+	d.SecShare.V = suite.Scalar().Pick(rng)
+	encD, _ = dealer.EncryptedDeal(0)
 	resp, err = v.ProcessEncryptedDeal(encD)
 	assert.NotNil(t, resp)
 	assert.Equal(t, StatusComplaint, resp.Status)
 	assert.Nil(t, err)
+	d.SecShare.V = goodShare
+}
+
+func TestVSSVerifierVerifyDealOnly(t *testing.T) {
+	dealer, verifiers := genAll()
+	v := verifiers[0]
+	d := dealer.deals[0]
+
+	// a share which does not verify against the commitments
+	goodShare := d.SecShare.V
+	d.SecShare.V = suite.Scalar().Pick(rng)
+	encD, err := dealer.EncryptedDeal(0)
+	require.NoError(t, err)
+	_, err = v.VerifyDealOnly(encD)
+	require.True(t, errors.Is(err, ErrInvalidDeal))
+	var shareErr *InvalidShareError
+	require.True(t, errors.As(err, &shareErr))
+	require.Equal(t, 0, shareErr.Index)
+	d.SecShare.V = goodShare
+
+	encD, err = dealer.EncryptedDeal(0)
+	require.NoError(t, err)
+	goodSig := encD.Signature
+	encD.Signature = randomBytes(32)
+	_, err = v.VerifyDealOnly(encD)
+	require.True(t, errors.Is(err, ErrInvalidSignature))
+	encD.Signature = goodSig
+
+	deal, err := v.VerifyDealOnly(encD)
+	require.NoError(t, err)
+	require.True(t, deal.SecShare.V.Equal(goodShare))
+
+	// nothing is stored until the deal is processed
+	require.Nil(t, v.Deal())
+	require.Nil(t, v.sid)
+	require.Empty(t, v.responses)
+	resp, err := v.ProcessEncryptedDeal(encD)
+	require.NoError(t, err)
+	require.Equal(t, StatusApproval, resp.Status)
+	_, err = v.VerifyDealOnly(encD)
+	require.NoError(t, err)
+	require.Len(t, v.responses, 1)
+}
+
+func TestVSSSessionIDMismatch(t *testing.T) {
+	dealer, verifiers := genAll()
+	v := verifiers[0]
+	encD, err := dealer.EncryptedDeal(0)
+	require.NoError(t, err)
+	resp, err := v.ProcessEncryptedDeal(encD)
+	require.NoError(t, err)
+	require.Equal(t, StatusApproval, resp.Status)
+	require.Equal(t, dealer.sid, v.SessionID())
+
+	// the same dealer shares another secret with the same verifiers: the
+	// second deal has other commitments, hence another session id
+	dealer2, err := NewDealer(suite, dealerSec, suite.Scalar().Pick(rng), verifiersPub, vssThreshold)
+	require.NoError(t, err)
+	require.NotEqual(t, dealer.sid, dealer2.sid)
+	encD2, err := dealer2.EncryptedDeal(0)
+	require.NoError(t, err)
+	resp, err = v.ProcessEncryptedDeal(encD2)
+	require.Nil(t, resp)
+	require.True(t, errors.Is(err, ErrSessionIDMismatch))
+	require.True(t, errors.Is(err, ErrInvalidSessionID))
+	var mismatch *SessionIDMismatchError
+	require.True(t, errors.As(err, &mismatch))
+	require.Equal(t, dealer.sid, mismatch.Expected)
+	require.Equal(t, dealer2.sid, mismatch.Got)
+	require.Equal(t, dealer.sid, v.SessionID())
+
+	// a deal embedding a session id which does not match its content
+	v2 := verifiers[1]
+	dealer2.deals[1].SessionID = dealer.sid
+	encD2, err = dealer2.EncryptedDeal(1)
+	require.NoError(t, err)
+	_, err = v2.ProcessEncryptedDeal(encD2)
+	require.True(t, errors.As(err, &mismatch))
+	require.Equal(t, dealer2.sid, mismatch.Expected)
+	require.Equal(t, dealer.sid, mismatch.Got)
+	require.Nil(t, v2.SessionID())
+}
+
+// TestVSSNonce checks that the deals of a run cannot be replayed in a run
+// with another nonce, even with the same polynomial.
+func TestVSSNonce(t *testing.T) {
+	dealer, verifiers := genAll()
+	sid := dealer.SessionID()
+	require.NoError(t, dealer.SetNonce([]byte("run 1")))
+	require.NotEqual(t, sid, dealer.SessionID())
+	expected, err := SessionIDWithNonce(suite, dealerPub, verifiersPub, dealer.secretCommits, dealer.t, []byte("run 1"))
+	require.NoError(t, err)
+	require.Equal(t, expected, dealer.SessionID())
+
+	replayed, err := NewDealerFromPoly(suite, dealerSec, dealer.PrivatePoly(), verifiersPub, nil)
+	require.NoError(t, err)
+	require.NoError(t, replayed.SetNonce([]byte("run 2")))
+	require.NotEqual(t, dealer.SessionID(), replayed.SessionID())
+
+	v := verifiers[0]
+	v.SetNonce([]byte("run 1"))
+	encD, err := replayed.EncryptedDeal(0)
+	require.NoError(t, err)
+	_, err = v.ProcessEncryptedDeal(encD)
+	require.True(t, errors.Is(err, ErrSessionIDMismatch))
+	encD, err = dealer.EncryptedDeal(0)
+	require.NoError(t, err)
+	resp, err := v.ProcessEncryptedDeal(encD)
+	require.NoError(t, err)
+	require.Equal(t, StatusApproval, resp.Status)
+	require.Equal(t, dealer.SessionID(), resp.SessionID)
+	j, err := dealer.ProcessResponse(resp)
+	require.NoError(t, err)
+	require.Nil(t, j)
+
+	// a verifier without the nonce rejects the deal
+	encD, err = dealer.EncryptedDeal(1)
+	require.NoError(t, err)
+	_, err = verifiers[1].ProcessEncryptedDeal(encD)
+	require.True(t, errors.Is(err, ErrSessionIDMismatch))
+}
+
+func TestVSSDecryptionProof(t *testing.T) {
+	dealer, verifiers := genAll()
+	encD, err := dealer.EncryptedDeal(0)
+	require.NoError(t, err)
+	proof, err := verifiers[0].ProveDecryption(encD)
+	require.NoError(t, err)
+	deal, err := DecryptDeal(suite, dealerPub, verifiersPub, encD, proof)
+	require.NoError(t, err)
+	plain, err := dealer.PlaintextDeal(0)
+	require.NoError(t, err)
+	require.Equal(t, plain.SecShare.I, deal.SecShare.I)
+	require.True(t, plain.SecShare.V.Equal(deal.SecShare.V))
+	require.Equal(t, plain.SessionID, deal.SessionID)
+
+	// the proof only opens the deal of the verifier
+	_, err = verifiers[1].ProveDecryption(encD)
+	require.True(t, errors.Is(err, ErrDealOutOfIndex))
+	encD1, err := dealer.EncryptedDeal(1)
+	require.NoError(t, err)
+	_, err = DecryptDeal(suite, dealerPub, verifiersPub, encD1, proof)
+	require.Error(t, err)
+	wrong := &DecryptionProof{Key: suite.Point().Pick(rng), Proof: proof.Proof}
+	_, err = DecryptDeal(suite, dealerPub, verifiersPub, encD, wrong)
+	require.Error(t, err)
+	_, err = DecryptDeal(suite, verifiersPub[0], verifiersPub, encD, proof)
+	require.True(t, errors.Is(err, ErrInvalidSignature))
 }
 
 func TestVSSAggregatorVerifyJustification(t *testing.T) {
@@ -299,17 +699,61 @@ func TestVSSAggregatorVerifyJustification(t *testing.T) {
 
 	j, err := dealer.ProcessResponse(resp)
 
-	// invalid deal justified
+	resign := func(j *Justification, long kyber.Scalar) {
+		sig, err := schnorr.Sign(suite, long, j.Hash(suite))
+		require.NoError(t, err)
+		j.Signature = sig
+	}
+
+	// modified justification, which does not come from the dealer anymore
 	goodV = j.Deal.SecShare.V
 	j.Deal.SecShare.V = wrongV
 	err = v.ProcessJustification(j)
+	assert.True(t, errors.Is(err, ErrInvalidSignature))
+	assert.False(t, v.Aggregator.badDealer)
+
+	// forged justification signed by another participant
+	resign(j, verifiersSec[1])
+	err = v.ProcessJustification(j)
+	assert.True(t, errors.Is(err, ErrInvalidSignature))
+	assert.False(t, v.Aggregator.badDealer)
+
+	// invalid deal justified
+	resign(j, dealerSec)
+	err = v.ProcessJustification(j)
 	assert.Error(t, err)
 	assert.True(t, v.Aggregator.badDealer)
 	j.Deal.SecShare.V = goodV
 	v.Aggregator.badDealer = false
 
-	// valid complaint
+	// justification revealing the share of another verifier
+	j2 := &Justification{SessionID: j.SessionID, Index: j.Index, Deal: dealer.deals[1]}
+	resign(j2, dealerSec)
+	assert.True(t, errors.Is(v.ProcessJustification(j2), ErrInvalidDeal))
+	assert.True(t, v.Aggregator.badDealer)
+	v.Aggregator.badDealer = false
+
+	// justification with other commitments than the original deal
+	other := *j.Deal
+	other.Commitments = append([]kyber.Point{suite.Point().Pick(rng)}, j.Deal.Commitments[1:]...)
+	j2 = &Justification{SessionID: j.SessionID, Index: j.Index, Deal: &other}
+	resign(j2, dealerSec)
+	assert.True(t, errors.Is(v.ProcessJustification(j2), ErrInvalidDeal))
+	assert.True(t, v.Aggregator.badDealer)
+	v.Aggregator.badDealer = false
+
+	// malformed justifications
+	assert.True(t, errors.Is(v.ProcessJustification(nil), ErrMalformed))
+	j2 = &Justification{SessionID: j.SessionID, Index: j.Index}
+	assert.True(t, errors.Is(v.ProcessJustification(j2), ErrMalformed))
+	j2 = &Justification{SessionID: randomBytes(len(j.SessionID)), Index: j.Index, Deal: j.Deal}
+	assert.True(t, errors.Is(v.ProcessJustification(j2), ErrInvalidSessionID))
+	assert.False(t, v.Aggregator.badDealer)
+	resign(j, dealerSec)
+
+	// valid complaint, which gives the right share to the verifier
 	assert.Nil(t, v.ProcessJustification(j))
+	assert.True(t, v.deal.SecShare.V.Equal(goodV))
 
 	// invalid complaint
 	resp.SessionID = randomBytes(len(resp.SessionID))
@@ -320,7 +764,7 @@ func TestVSSAggregatorVerifyJustification(t *testing.T) {
 
 	// no complaints for this justification before
 	delete(v.Aggregator.responses, uint32(v.index))
-	assert.Error(t, v.ProcessJustification(j))
+	assert.True(t, errors.Is(v.ProcessJustification(j), ErrUnexpectedJustification))
 	v.Aggregator.responses[uint32(v.index)] = resp
 
 }
@@ -351,12 +795,12 @@ func TestVSSAggregatorVerifyResponseDuplicate(t *testing.T) {
 	assert.Equal(t, resp2, r)
 
 	err = v1.ProcessResponse(resp2)
-	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrDuplicateResponse))
 
 	delete(v1.Aggregator.responses, uint32(v2.index))
 	v1.Aggregator.responses[uint32(v2.index)] = &Response{Status: StatusApproval}
 	err = v1.ProcessResponse(resp2)
-	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrDuplicateResponse))
 }
 
 func TestVSSAggregatorVerifyResponse(t *testing.T) {
@@ -384,20 +828,20 @@ func TestVSSAggregatorVerifyResponse(t *testing.T) {
 	resp.Index = uint32(len(verifiersPub))
 	sig, err := schnorr.Sign(suite, v.longterm, resp.Hash(suite))
 	resp.Signature = sig
-	assert.Error(t, aggr.verifyResponse(resp))
+	assert.True(t, errors.Is(aggr.verifyResponse(resp), ErrResponseOutOfIndex))
 	resp.Index = 0
 
 	// wrong signature
 	goodSig := resp.Signature
 	resp.Signature = randomBytes(len(goodSig))
-	assert.Error(t, aggr.verifyResponse(resp))
+	assert.True(t, errors.Is(aggr.verifyResponse(resp), ErrInvalidSignature))
 	resp.Signature = goodSig
 
 	// wrongID
 	wrongID := randomBytes(len(resp.SessionID))
 	goodID := resp.SessionID
 	resp.SessionID = wrongID
-	assert.Error(t, aggr.verifyResponse(resp))
+	assert.True(t, errors.Is(aggr.verifyResponse(resp), ErrInvalidSessionID))
 	resp.SessionID = goodID
 }
 
@@ -478,19 +922,19 @@ func TestVSSAggregatorVerifyDeal(t *testing.T) {
 
 	// already received deal
 	err = aggr.VerifyDeal(deal, true)
-	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrDealAlreadyProcessed))
 
 	// wrong T
 	wrongT := uint32(1)
 	goodT := deal.T
 	deal.T = wrongT
-	assert.Error(t, aggr.VerifyDeal(deal, false))
+	assert.True(t, errors.Is(aggr.VerifyDeal(deal, false), ErrInvalidDeal))
 	deal.T = goodT
 
 	// wrong SessionID
 	goodSid := deal.SessionID
 	deal.SessionID = make([]byte, 32)
-	assert.Error(t, aggr.VerifyDeal(deal, false))
+	assert.True(t, errors.Is(aggr.VerifyDeal(deal, false), ErrInvalidSessionID))
 	deal.SessionID = goodSid
 
 	// index different in one share
@@ -501,14 +945,15 @@ func TestVSSAggregatorVerifyDeal(t *testing.T) {
 
 	// index not in bounds
 	deal.SecShare.I = -1
-	assert.Error(t, aggr.VerifyDeal(deal, false))
+	assert.True(t, errors.Is(aggr.VerifyDeal(deal, false), ErrDealOutOfIndex))
 	deal.SecShare.I = len(verifiersPub)
-	assert.Error(t, aggr.VerifyDeal(deal, false))
+	assert.True(t, errors.Is(aggr.VerifyDeal(deal, false), ErrDealOutOfIndex))
+	deal.SecShare.I = goodI
 
 	// shares invalid in respect to the commitments
 	wrongSec, _ := genPair()
 	deal.SecShare.V = wrongSec
-	assert.Error(t, aggr.VerifyDeal(deal, false))
+	assert.True(t, errors.Is(aggr.VerifyDeal(deal, false), ErrInvalidDeal))
 }
 
 func TestVSSAggregatorAddComplaint(t *testing.T) {
@@ -525,24 +970,29 @@ func TestVSSAggregatorAddComplaint(t *testing.T) {
 	assert.Equal(t, aggr.responses[idx], c)
 
 	// response already there
-	assert.Error(t, aggr.addResponse(c))
+	assert.Equal(t, ErrDuplicateResponse, aggr.addResponse(c))
 	delete(aggr.responses, idx)
 
+	// unknown verifier
+	c.Index = uint32(len(verifiersPub))
+	assert.Equal(t, ErrResponseOutOfIndex, aggr.addResponse(c))
+
 }
 
 func TestVSSSessionID(t *testing.T) {
 	dealer, _ := NewDealer(suite, dealerSec, secret, verifiersPub, vssThreshold)
 	commitments := dealer.deals[0].Commitments
-	sid, err := sessionID(suite, dealerPub, verifiersPub, commitments, dealer.t)
+	sid, err := SessionID(suite, dealerPub, verifiersPub, commitments, dealer.t)
 	assert.NoError(t, err)
+	assert.Equal(t, dealer.sid, sid)
 
-	sid2, err2 := sessionID(suite, dealerPub, verifiersPub, commitments, dealer.t)
+	sid2, err2 := sessionID(suite, dealerPub, verifiersPub, commitments, nil, dealer.t, nil)
 	assert.NoError(t, err2)
 	assert.Equal(t, sid, sid2)
 
 	wrongDealerPub := suite.Point().Add(dealerPub, dealerPub)
 
-	sid3, err3 := sessionID(suite, wrongDealerPub, verifiersPub, commitments, dealer.t)
+	sid3, err3 := sessionID(suite, wrongDealerPub, verifiersPub, commitments, nil, dealer.t, nil)
 	assert.NoError(t, err3)
 	assert.NotEqual(t, sid3, sid2)
 }
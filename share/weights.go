@@ -0,0 +1,98 @@
+package share
+
+import (
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+)
+
+// Weights gives the number of shares held by each node of a weighted secret
+// sharing, e.g. the stake of the members of a committee: node i holds
+// Weights[i] shares. The shares are numbered consecutively from 0 in the order
+// of the nodes, so that a polynomial is evaluated at as many indices as the
+// total weight, and its threshold bounds the total weight of the nodes which
+// can recover the secret together.
+type Weights []int
+
+// Check returns an error if a node has a weight below 1.
+func (w Weights) Check() error {
+	for i, weight := range w {
+		if weight < 1 {
+			return fmt.Errorf("share: weight %d of node %d", weight, i)
+		}
+	}
+	return nil
+}
+
+// Total returns the total weight of the nodes, i.e. the number of shares.
+func (w Weights) Total() int {
+	var total int
+	for _, weight := range w {
+		total += weight
+	}
+	return total
+}
+
+// Indices returns the indices of the shares of each node.
+func (w Weights) Indices() [][]int {
+	indices := make([][]int, len(w))
+	var next int
+	for i, weight := range w {
+		for j := 0; j < weight; j++ {
+			indices[i] = append(indices[i], next)
+			next++
+		}
+	}
+	return indices
+}
+
+// Holder returns the node holding the share at index i, or -1 if there is no
+// share at index i.
+func (w Weights) Holder(i int) int {
+	if i < 0 {
+		return -1
+	}
+	for n, weight := range w {
+		if i < weight {
+			return n
+		}
+		i -= weight
+	}
+	return -1
+}
+
+// Shares returns the shares of each node of the private polynomial.
+func (w Weights) Shares(p *PriPoly) [][]*PriShare {
+	indices := w.Indices()
+	shares := make([][]*PriShare, len(w))
+	for n, idx := range indices {
+		for _, i := range idx {
+			shares[n] = append(shares[n], p.Eval(i))
+		}
+	}
+	return shares
+}
+
+// RecoverSecretWeighted reconstructs the secret shared with the weights from
+// the shares of some of the nodes, each one giving all its shares, so that t
+// is a threshold on the total weight of the nodes. Like RecoverSecret, it
+// uses the first t distinct shares and ignores the nil ones. It returns an
+// error if a share has an index beyond the total weight, or if the shares do
+// not reach the threshold.
+func RecoverSecretWeighted(g kyber.Group, w Weights, shares []*PriShare, t int) (kyber.Scalar, error) {
+	n := w.Total()
+	seen := make(map[int]bool)
+	for _, s := range shares {
+		if s == nil || s.V == nil {
+			continue
+		}
+		if w.Holder(s.I) < 0 {
+			return nil, fmt.Errorf("share: share index %d beyond the total weight %d", s.I, n)
+		}
+		seen[s.I] = true
+	}
+	if len(seen) < t {
+		return nil, fmt.Errorf("share: shares of total weight %d below the threshold %d", len(seen), t)
+	}
+	return RecoverSecret(g, shares, t, n)
+}
@@ -0,0 +1,48 @@
+package share
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+)
+
+func TestWeights(t *testing.T) {
+	w := Weights{3, 1, 2}
+	require.NoError(t, w.Check())
+	require.Error(t, Weights{1, 0}.Check())
+	require.Equal(t, 6, w.Total())
+	require.Equal(t, [][]int{{0, 1, 2}, {3}, {4, 5}}, w.Indices())
+	for n, indices := range w.Indices() {
+		for _, i := range indices {
+			require.Equal(t, n, w.Holder(i))
+		}
+	}
+	require.Equal(t, -1, w.Holder(6))
+	require.Equal(t, -1, w.Holder(-1))
+}
+
+func TestRecoverSecretWeighted(t *testing.T) {
+	g := edwards25519.NewBlakeSHA256Ed25519()
+	w := Weights{3, 1, 2}
+	thr := 4
+	poly := NewPriPoly(g, thr, nil, g.RandomStream())
+	shares := w.Shares(poly)
+	for n, s := range shares {
+		require.Len(t, s, w[n])
+	}
+
+	// the nodes of weight 3 and 1, or 3 and 2, reach the threshold
+	secret, err := RecoverSecretWeighted(g, w, append(shares[0], shares[1]...), thr)
+	require.NoError(t, err)
+	require.True(t, poly.Secret().Equal(secret))
+	secret, err = RecoverSecretWeighted(g, w, append(shares[2], shares[0]...), thr)
+	require.NoError(t, err)
+	require.True(t, poly.Secret().Equal(secret))
+
+	// but not the nodes of weight 1 and 2, even with a share given twice
+	_, err = RecoverSecretWeighted(g, w, append(shares[1], shares[2][0], shares[2][1], shares[2][0]), thr)
+	require.Error(t, err)
+	_, err = RecoverSecretWeighted(g, w, append(shares[0], poly.Eval(w.Total())), thr)
+	require.Error(t, err)
+}
@@ -0,0 +1,210 @@
+package share
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+
+	"go.dedis.ch/kyber/v3"
+)
+
+// XShare represents a private share evaluated at an arbitrary point X instead
+// of the default evaluation point I+1 used by PriShare. It allows to identify
+// share holders by a value derived from their identity (see HashIndex) rather
+// than by their position in a list of participants.
+type XShare struct {
+	X kyber.Scalar // Evaluation point of the private share
+	V kyber.Scalar // Value of the private share
+}
+
+func (p *XShare) String() string {
+	return fmt.Sprintf("{%s:%s}", p.X, p.V)
+}
+
+// PubXShare represents a public share evaluated at an arbitrary point X.
+type PubXShare struct {
+	X kyber.Scalar // Evaluation point of the public share
+	V kyber.Point  // Value of the public share
+}
+
+// HashIndex derives the evaluation point of the share holder having the given
+// public key by hashing it. It returns an error in the negligible case where
+// the hash maps to zero, as evaluating the polynomial at zero would reveal the
+// shared secret.
+func HashIndex(s kyber.HashFactory, g kyber.Group, pub kyber.Point) (kyber.Scalar, error) {
+	h := s.Hash()
+	_, _ = h.Write([]byte("share-index"))
+	if _, err := pub.MarshalTo(h); err != nil {
+		return nil, err
+	}
+	x := g.Scalar().SetBytes(h.Sum(nil))
+	if x.Equal(g.Scalar().Zero()) {
+		return nil, errors.New("share: hashed index is zero")
+	}
+	return x, nil
+}
+
+// HashIndices returns the evaluation points of all the given public keys
+// computed with HashIndex. It returns an error if two public keys map to the
+// same evaluation point.
+func HashIndices(s kyber.HashFactory, g kyber.Group, pubs []kyber.Point) ([]kyber.Scalar, error) {
+	xs := make([]kyber.Scalar, len(pubs))
+	seen := make(map[string]int)
+	for i, pub := range pubs {
+		x, err := HashIndex(s, g, pub)
+		if err != nil {
+			return nil, err
+		}
+		id := x.String()
+		if j, exists := seen[id]; exists {
+			return nil, fmt.Errorf("share: hashed indices of participants %d and %d collide", j, i)
+		}
+		seen[id] = i
+		xs[i] = x
+	}
+	return xs, nil
+}
+
+// EvalScalar computes the private share v = p(x) at an arbitrary point x.
+func (p *PriPoly) EvalScalar(x kyber.Scalar) *XShare {
+	v := p.g.Scalar().Zero()
+	for j := p.Threshold() - 1; j >= 0; j-- {
+		v.Mul(v, x)
+		v.Add(v, p.coeffs[j])
+	}
+	return &XShare{X: x, V: v}
+}
+
+// EvalScalar computes the public share v = p(x) at an arbitrary point x.
+func (p *PubPoly) EvalScalar(x kyber.Scalar) *PubXShare {
+	v := p.g.Point().Null()
+	for j := p.Threshold() - 1; j >= 0; j-- {
+		v.Mul(x, v)
+		v.Add(v, p.commits[j])
+	}
+	return &PubXShare{X: x, V: v}
+}
+
+// CheckX checks a private share evaluated at an arbitrary point against a
+// public commitment polynomial.
+func (p *PubPoly) CheckX(s *XShare) bool {
+	pv := p.EvalScalar(s.X)
+	ps := p.g.Point().Mul(s.V, p.b)
+	return pv.V.Equal(ps)
+}
+
+// RecoverSecretFromXShares reconstructs the shared secret p(0) from a list of
+// private shares evaluated at arbitrary points using Lagrange interpolation.
+// It returns an error if there are less than t shares with distinct non-zero
+// evaluation points.
+func RecoverSecretFromXShares(g kyber.Group, shares []*XShare, t int) (kyber.Scalar, error) {
+	sorted := make([]*XShare, 0, len(shares))
+	for _, s := range shares {
+		if s != nil && s.X != nil && s.V != nil {
+			sorted = append(sorted, s)
+		}
+	}
+	xs := make([]kyber.Scalar, len(sorted))
+	for i, s := range sorted {
+		xs[i] = s.X
+	}
+	idx, err := selectPoints(g, xs, t)
+	if err != nil {
+		return nil, err
+	}
+
+	acc := g.Scalar().Zero()
+	tmp := g.Scalar()
+	for _, i := range idx {
+		tmp.Mul(sorted[i].V, lagrangeAtZero(g, i, idx, xs))
+		acc.Add(acc, tmp)
+	}
+	return acc, nil
+}
+
+// RecoverCommitFromXShares reconstructs the secret commitment p(0) from a list
+// of public shares evaluated at arbitrary points using Lagrange interpolation.
+// It returns an error if there are less than t shares with distinct non-zero
+// evaluation points.
+func RecoverCommitFromXShares(g kyber.Group, shares []*PubXShare, t int) (kyber.Point, error) {
+	sorted := make([]*PubXShare, 0, len(shares))
+	for _, s := range shares {
+		if s != nil && s.X != nil && s.V != nil {
+			sorted = append(sorted, s)
+		}
+	}
+	xs := make([]kyber.Scalar, len(sorted))
+	for i, s := range sorted {
+		xs[i] = s.X
+	}
+	idx, err := selectPoints(g, xs, t)
+	if err != nil {
+		return nil, err
+	}
+
+	Acc := g.Point().Null()
+	Tmp := g.Point()
+	for _, i := range idx {
+		Tmp.Mul(lagrangeAtZero(g, i, idx, xs), sorted[i].V)
+		Acc.Add(Acc, Tmp)
+	}
+	return Acc, nil
+}
+
+// selectPoints returns the positions of the first t distinct non-zero
+// evaluation points of xs, ordered by their binary representation so that all
+// participants interpolate on the exact same set of shares.
+func selectPoints(g kyber.Group, xs []kyber.Scalar, t int) ([]int, error) {
+	type point struct {
+		pos int
+		buf []byte
+	}
+	points := make([]point, 0, len(xs))
+	for i, x := range xs {
+		buf, err := x.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, point{i, buf})
+	}
+	sort.SliceStable(points, func(i, j int) bool {
+		return bytes.Compare(points[i].buf, points[j].buf) < 0
+	})
+
+	zero := g.Scalar().Zero()
+	idx := make([]int, 0, t)
+	for i, p := range points {
+		if len(idx) == t {
+			break
+		}
+		if xs[p.pos].Equal(zero) {
+			return nil, errors.New("share: evaluation point is zero")
+		}
+		if i > 0 && bytes.Equal(points[i-1].buf, p.buf) {
+			// duplicate share
+			continue
+		}
+		idx = append(idx, p.pos)
+	}
+	if len(idx) < t {
+		return nil, errors.New("share: not enough shares with distinct evaluation points")
+	}
+	return idx, nil
+}
+
+// lagrangeAtZero returns the Lagrange coefficient at zero of the i-th point
+// among the points whose positions in xs are given by idx.
+func lagrangeAtZero(g kyber.Group, i int, idx []int, xs []kyber.Scalar) kyber.Scalar {
+	num := g.Scalar().One()
+	den := g.Scalar().One()
+	tmp := g.Scalar()
+	for _, j := range idx {
+		if i == j {
+			continue
+		}
+		num.Mul(num, xs[j])
+		den.Mul(den, tmp.Sub(xs[j], xs[i]))
+	}
+	return num.Div(num, den)
+}
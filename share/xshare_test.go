@@ -0,0 +1,103 @@
+package share
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+)
+
+func hashedIndices(test *testing.T, g *edwards25519.SuiteEd25519, n int) []kyber.Scalar {
+	pubs := make([]kyber.Point, n)
+	for i := range pubs {
+		pubs[i] = g.Point().Pick(g.RandomStream())
+	}
+	xs, err := HashIndices(g, g, pubs)
+	require.NoError(test, err)
+	return xs
+}
+
+func TestXShareSecretRecovery(test *testing.T) {
+	g := edwards25519.NewBlakeSHA256Ed25519()
+	n := 10
+	t := n/2 + 1
+	poly := NewPriPoly(g, t, nil, g.RandomStream())
+	pub := poly.Commit(nil)
+	xs := hashedIndices(test, g, n)
+
+	shares := make([]*XShare, n)
+	pubShares := make([]*PubXShare, n)
+	for i, x := range xs {
+		shares[i] = poly.EvalScalar(x)
+		pubShares[i] = pub.EvalScalar(x)
+		require.True(test, pub.CheckX(shares[i]))
+	}
+
+	recovered, err := RecoverSecretFromXShares(g, shares, t)
+	require.NoError(test, err)
+	require.True(test, recovered.Equal(poly.Secret()))
+
+	// any subset of t shares works
+	recovered, err = RecoverSecretFromXShares(g, shares[n-t:], t)
+	require.NoError(test, err)
+	require.True(test, recovered.Equal(poly.Secret()))
+
+	commit, err := RecoverCommitFromXShares(g, pubShares[1:t+1], t)
+	require.NoError(test, err)
+	require.True(test, commit.Equal(pub.Commit()))
+
+	// a wrong share does not verify
+	bad := &XShare{X: xs[0], V: g.Scalar().Pick(g.RandomStream())}
+	require.False(test, pub.CheckX(bad))
+}
+
+func TestXShareEvalDefaultIndex(test *testing.T) {
+	g := edwards25519.NewBlakeSHA256Ed25519()
+	poly := NewPriPoly(g, 4, nil, g.RandomStream())
+	pub := poly.Commit(nil)
+	for i := 0; i < 5; i++ {
+		x := g.Scalar().SetInt64(int64(i + 1))
+		require.True(test, poly.Eval(i).V.Equal(poly.EvalScalar(x).V))
+		require.True(test, pub.Eval(i).V.Equal(pub.EvalScalar(x).V))
+	}
+}
+
+func TestXShareRecoveryFailures(test *testing.T) {
+	g := edwards25519.NewBlakeSHA256Ed25519()
+	n := 6
+	t := 4
+	poly := NewPriPoly(g, t, nil, g.RandomStream())
+	xs := hashedIndices(test, g, n)
+	shares := make([]*XShare, n)
+	for i, x := range xs {
+		shares[i] = poly.EvalScalar(x)
+	}
+
+	// not enough shares
+	_, err := RecoverSecretFromXShares(g, shares[:t-1], t)
+	require.Error(test, err)
+
+	// duplicated shares do not count twice
+	dup := []*XShare{shares[0], shares[0], shares[1], shares[1], shares[2]}
+	_, err = RecoverSecretFromXShares(g, dup, t)
+	require.Error(test, err)
+
+	// nil shares are ignored
+	withNil := append([]*XShare{nil}, shares[:t]...)
+	recovered, err := RecoverSecretFromXShares(g, withNil, t)
+	require.NoError(test, err)
+	require.True(test, recovered.Equal(poly.Secret()))
+
+	// zero evaluation point is rejected
+	zero := &XShare{X: g.Scalar().Zero(), V: poly.Secret()}
+	_, err = RecoverSecretFromXShares(g, append([]*XShare{zero}, shares[:t]...), t)
+	require.Error(test, err)
+}
+
+func TestHashIndicesCollision(test *testing.T) {
+	g := edwards25519.NewBlakeSHA256Ed25519()
+	p := g.Point().Pick(g.RandomStream())
+	_, err := HashIndices(g, g, []kyber.Point{p, g.Point().Base(), p})
+	require.Error(test, err)
+}
@@ -0,0 +1,192 @@
+package anon
+
+import (
+	"bytes"
+	"errors"
+
+	"go.dedis.ch/kyber/v3"
+)
+
+// TagVersion1 is the version byte of the current linkage tag encoding.
+const TagVersion1 byte = 1
+
+// Tag is the linkage tag of a linkable ring signature, as returned by
+// SignLinkable and VerifyLinkable. Its encoding is versioned: it consists of
+// one version byte, currently TagVersion1, followed by the canonical binary
+// encoding of the tag point x*H where x is the private key of the signer and
+// H the base point derived from the linkage scope. Two signatures produced in
+// the same scope come from the same signer if and only if their tags are
+// equal byte for byte.
+type Tag []byte
+
+var errNoScope = errors.New("anon: linkable signature requires a linkage scope")
+var errSignerIndex = errors.New("anon: signer index out of bounds")
+var errInvalidSignature = errors.New("anon: invalid signature")
+var errTagVersion = errors.New("anon: unknown linkage tag version")
+
+// NewTag returns the linkage tag that the holder of the private key produces
+// when signing within the given scope.
+func NewTag(suite Suite, linkScope []byte, privateKey kyber.Scalar) (Tag, error) {
+	if linkScope == nil {
+		return nil, errNoScope
+	}
+	return encodeTag(suite.Point().Mul(privateKey, linkBase(suite, linkScope)))
+}
+
+// Point decodes the tag into the tag point x*H.
+func (t Tag) Point(suite Suite) (kyber.Point, error) {
+	if len(t) == 0 || t[0] != TagVersion1 {
+		return nil, errTagVersion
+	}
+	P := suite.Point()
+	if err := P.UnmarshalBinary(t[1:]); err != nil {
+		return nil, err
+	}
+	return P, nil
+}
+
+// SignLinkable creates a linkable ring signature on the message within the
+// given linkage scope, which must not be nil, and returns it together with
+// the linkage tag of the signer. The signature is the same as the one produced
+// by Sign, and can be checked by either Verify or VerifyLinkable.
+//
+// SignLinkable is meant for large anonymity sets, up to a thousand keys and
+// more: the challenge chain reuses the pre-hashed message, scope and tag for
+// every position. Every position, including the one of the signer, does the
+// same constant time scalar multiplications, so that the signing time does
+// not depend on the index of the signer.
+func SignLinkable(suite Suite, message []byte, anonymitySet Set,
+	linkScope []byte, mine int, privateKey kyber.Scalar) ([]byte, Tag, error) {
+	if linkScope == nil {
+		return nil, nil, errNoScope
+	}
+	n := len(anonymitySet)
+	if mine < 0 || mine >= n {
+		return nil, nil, errSignerIndex
+	}
+	L := []kyber.Point(anonymitySet)
+	H := linkBase(suite, linkScope)
+	linkTag := suite.Point().Mul(privateKey, H)
+	tag, err := encodeTag(linkTag)
+	if err != nil {
+		return nil, nil, err
+	}
+	H1pre := signH1pre(suite, linkScope, linkTag, message)
+
+	// The commit u*G, u*H of the signer is computed as a link of the chain
+	// with a zero challenge, so that it costs the same as the other links.
+	u := suite.Scalar().Pick(suite.RandomStream())
+	s := make([]kyber.Scalar, n)
+	r := newRing(suite, L, H, linkTag, false)
+	c := r.next(H1pre, mine, u, suite.Scalar().Zero())
+	var c0 kyber.Scalar
+	for i := (mine + 1) % n; i != mine; i = (i + 1) % n {
+		if i == 0 {
+			c0 = c
+		}
+		s[i] = suite.Scalar().Pick(suite.RandomStream())
+		c = r.next(H1pre, i, s[i], c)
+	}
+	if mine == 0 {
+		c0 = c
+	}
+	s[mine] = suite.Scalar()
+	s[mine].Mul(privateKey, c).Sub(u, s[mine]) // s_pi = u - x_pi c_pi
+
+	buf := bytes.Buffer{}
+	if err := suite.Write(&buf, &lSig{c0, s, linkTag}); err != nil {
+		return nil, nil, err
+	}
+	return buf.Bytes(), tag, nil
+}
+
+// VerifyLinkable checks a linkable signature produced by Sign or SignLinkable
+// within the given linkage scope, which must not be nil, and returns the
+// linkage tag of the signer. Verification only processes public values and
+// uses variable time scalar multiplications when the group supports them.
+func VerifyLinkable(suite Suite, message []byte, anonymitySet Set,
+	linkScope []byte, signatureBuffer []byte) (Tag, error) {
+	if linkScope == nil {
+		return nil, errNoScope
+	}
+	n := len(anonymitySet)
+	L := []kyber.Point(anonymitySet)
+
+	sig := lSig{S: make([]kyber.Scalar, n)}
+	if err := suite.Read(bytes.NewBuffer(signatureBuffer), &sig); err != nil {
+		return nil, err
+	}
+	H := linkBase(suite, linkScope)
+	H1pre := signH1pre(suite, linkScope, sig.Tag, message)
+
+	r := newRing(suite, L, H, sig.Tag, true)
+	c := sig.C0
+	for i := 0; i < n; i++ {
+		c = r.next(H1pre, i, sig.S[i], c)
+	}
+	if !c.Equal(sig.C0) {
+		return nil, errInvalidSignature
+	}
+	return encodeTag(sig.Tag)
+}
+
+// ring computes the links c_{i+1} = H1(s_i*G + c_i*L_i, s_i*H + c_i*T) of the
+// challenge chain of a linkable signature, reusing its temporaries from one
+// position to the next. The scalar multiplications are in variable time only
+// for the verification, which only processes public values.
+type ring struct {
+	suite  Suite
+	L      []kyber.Point
+	H, T   kyber.Point
+	PG, PH kyber.Point
+	tmp    kyber.Point
+	vt     kyber.VartimeMulAdder
+}
+
+func newRing(suite Suite, L []kyber.Point, H, T kyber.Point, vartime bool) *ring {
+	r := &ring{suite: suite, L: L, PG: suite.Point(), PH: suite.Point(), tmp: suite.Point()}
+	if !vartime {
+		r.H = suite.Point().Set(H)
+		r.T = suite.Point().Set(T)
+		return r
+	}
+	r.vt, _ = r.PG.(kyber.VartimeMulAdder)
+
+	// Only public values go through these points, so that variable time
+	// multiplications are safe.
+	r.H = allowVarTime(suite.Point().Set(H))
+	r.T = allowVarTime(suite.Point().Set(T))
+	allowVarTime(r.PH)
+	allowVarTime(r.tmp)
+	return r
+}
+
+func (r *ring) next(H1pre kyber.XOF, i int, s, c kyber.Scalar) kyber.Scalar {
+	if r.vt != nil {
+		r.vt.MulAddVartime(c, r.L[i], s)
+	} else {
+		r.PG.Add(r.PG.Mul(s, nil), r.tmp.Mul(c, r.L[i]))
+	}
+	r.PH.Add(r.PH.Mul(s, r.H), r.tmp.Mul(c, r.T))
+	return signH1(r.suite, H1pre, r.PG, r.PH)
+}
+
+func allowVarTime(P kyber.Point) kyber.Point {
+	if vt, ok := P.(kyber.AllowsVarTime); ok {
+		vt.AllowVarTime(true)
+	}
+	return P
+}
+
+// linkBase returns the pseudorandom base point of the linkage scope.
+func linkBase(suite Suite, linkScope []byte) kyber.Point {
+	return suite.Point().Pick(suite.XOF(linkScope))
+}
+
+func encodeTag(P kyber.Point) (Tag, error) {
+	b, err := P.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return append(Tag{TagVersion1}, b...), nil
+}
@@ -0,0 +1,140 @@
+package anon
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/group/nist"
+)
+
+func TestSignLinkable(t *testing.T) {
+	for _, suite := range []Suite{edwards25519.NewBlakeSHA256Ed25519(), nist.NewBlakeSHA256P256()} {
+		X, x := benchGenKeys(suite, 16)
+		scope := []byte("scope")
+		for _, mine := range []int{0, 7, 15} {
+			X[mine] = suite.Point().Mul(x, nil)
+			sig, tag, err := SignLinkable(suite, benchMessage, Set(X), scope, mine, x)
+			require.NoError(t, err)
+			require.Equal(t, TagVersion1, tag[0])
+			require.Len(t, tag, 1+suite.PointLen())
+
+			vtag, err := VerifyLinkable(suite, benchMessage, Set(X), scope, sig)
+			require.NoError(t, err)
+			require.Equal(t, tag, vtag)
+
+			// the signature is compatible with Verify, which returns the
+			// unversioned tag point
+			raw, err := Verify(suite, benchMessage, Set(X), scope, sig)
+			require.NoError(t, err)
+			require.Equal(t, []byte(tag[1:]), raw)
+			vtag, err = VerifyLinkable(suite, benchMessage, Set(X), scope,
+				Sign(suite, benchMessage, Set(X), scope, mine, x))
+			require.NoError(t, err)
+			require.Equal(t, tag, vtag)
+
+			_, err = VerifyLinkable(suite, []byte("other"), Set(X), scope, sig)
+			require.Equal(t, errInvalidSignature, err)
+			_, err = VerifyLinkable(suite, benchMessage, Set(X), []byte("other"), sig)
+			require.Equal(t, errInvalidSignature, err)
+		}
+	}
+}
+
+func TestSignLinkableSameScopeSameTag(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	X, x := benchGenKeys(suite, 8)
+	Y, _ := benchGenKeys(suite, 5)
+	Y[3] = X[0]
+	scope := []byte("election 2024")
+
+	expected, err := NewTag(suite, scope, x)
+	require.NoError(t, err)
+	P, err := expected.Point(suite)
+	require.NoError(t, err)
+	require.True(t, P.Equal(suite.Point().Mul(x, linkBase(suite, scope))))
+
+	// the tag only depends on the signer and the scope, not on the message,
+	// the anonymity set or the position of the signer in it
+	sets := []struct {
+		set  []kyber.Point
+		mine int
+	}{{X, 0}, {Y, 3}}
+	for i, msg := range [][]byte{[]byte("a"), []byte("b"), []byte("c")} {
+		s := sets[i%len(sets)]
+		sig, tag, err := SignLinkable(suite, msg, Set(s.set), scope, s.mine, x)
+		require.NoError(t, err)
+		require.Equal(t, expected, tag)
+		tag, err = VerifyLinkable(suite, msg, Set(s.set), scope, sig)
+		require.NoError(t, err)
+		require.Equal(t, expected, tag)
+	}
+
+	// another scope or another signer gives another tag
+	_, other, err := SignLinkable(suite, benchMessage, Set(X), []byte("election 2025"), 0, x)
+	require.NoError(t, err)
+	require.False(t, bytes.Equal(expected, other))
+	y := suite.Scalar().Pick(suite.RandomStream())
+	X[1] = suite.Point().Mul(y, nil)
+	_, other, err = SignLinkable(suite, benchMessage, Set(X), scope, 1, y)
+	require.NoError(t, err)
+	require.False(t, bytes.Equal(expected, other))
+}
+
+func TestSignLinkableInvalid(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	X, x := benchGenKeys(suite, 4)
+	_, _, err := SignLinkable(suite, benchMessage, Set(X), nil, 0, x)
+	require.Equal(t, errNoScope, err)
+	_, _, err = SignLinkable(suite, benchMessage, Set(X), []byte{}, 4, x)
+	require.Equal(t, errSignerIndex, err)
+	_, err = VerifyLinkable(suite, benchMessage, Set(X), nil, nil)
+	require.Equal(t, errNoScope, err)
+	_, err = VerifyLinkable(suite, benchMessage, Set(X), []byte{}, []byte{1, 2, 3})
+	require.Error(t, err)
+
+	tag, err := NewTag(suite, []byte{}, x)
+	require.NoError(t, err)
+	tag[0] = TagVersion1 + 1
+	_, err = tag.Point(suite)
+	require.Equal(t, errTagVersion, err)
+	_, err = Tag{}.Point(suite)
+	require.Equal(t, errTagVersion, err)
+}
+
+var benchScope = []byte("Benchmark Scope")
+
+func benchSignLinkable(b *testing.B, n int) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	X, x := benchGenKeys(suite, n)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := SignLinkable(suite, benchMessage, Set(X), benchScope, 0, x); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchVerifyLinkable(b *testing.B, n int) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	X, x := benchGenKeys(suite, n)
+	sig, _, err := SignLinkable(suite, benchMessage, Set(X), benchScope, 0, x)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := VerifyLinkable(suite, benchMessage, Set(X), benchScope, sig); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSignLinkable16Ed25519(b *testing.B)     { benchSignLinkable(b, 16) }
+func BenchmarkSignLinkable128Ed25519(b *testing.B)    { benchSignLinkable(b, 128) }
+func BenchmarkSignLinkable1024Ed25519(b *testing.B)   { benchSignLinkable(b, 1024) }
+func BenchmarkVerifyLinkable16Ed25519(b *testing.B)   { benchVerifyLinkable(b, 16) }
+func BenchmarkVerifyLinkable128Ed25519(b *testing.B)  { benchVerifyLinkable(b, 128) }
+func BenchmarkVerifyLinkable1024Ed25519(b *testing.B) { benchVerifyLinkable(b, 1024) }
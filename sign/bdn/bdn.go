@@ -12,6 +12,7 @@ package bdn
 import (
 	"crypto/cipher"
 	"errors"
+	"fmt"
 	"math/big"
 
 	"go.dedis.ch/kyber/v3"
@@ -149,3 +150,61 @@ func AggregatePublicKeys(suite pairing.Suite, mask *sign.Mask) (kyber.Point, err
 
 	return agg, nil
 }
+
+// Scheme is the BDN signature scheme over a pairing suite, whose methods work
+// like the package-level functions, so that it can be used as a sign.Scheme.
+// Its aggregation methods take the mask of the signers among all the public
+// keys, on which the coefficients of the aggregation depend, so that it is not
+// a sign.AggregatableScheme.
+type Scheme struct {
+	suite pairing.Suite
+}
+
+var _ sign.Scheme = (*Scheme)(nil)
+
+func init() {
+	sign.RegisterScheme("bdn", func(suite interface{}) (sign.Scheme, error) {
+		s, ok := suite.(pairing.Suite)
+		if !ok {
+			return nil, fmt.Errorf("bdn: needs a pairing suite, not %T", suite)
+		}
+		return NewScheme(s), nil
+	})
+}
+
+// NewScheme returns the BDN signature scheme over the suite, with the public
+// keys on G2 and the signatures on G1.
+func NewScheme(suite pairing.Suite) *Scheme {
+	return &Scheme{suite: suite}
+}
+
+// NewKeyPair works like the NewKeyPair function with the suite of the scheme.
+func (s *Scheme) NewKeyPair(random cipher.Stream) (kyber.Scalar, kyber.Point) {
+	return NewKeyPair(s.suite, random)
+}
+
+// Sign works like the Sign function with the suite of the scheme.
+func (s *Scheme) Sign(private kyber.Scalar, msg []byte) ([]byte, error) {
+	return Sign(s.suite, private, msg)
+}
+
+// Verify works like the Verify function with the suite of the scheme.
+func (s *Scheme) Verify(public kyber.Point, msg, sig []byte) error {
+	return Verify(s.suite, public, msg, sig)
+}
+
+// AggregateSignatures works like the AggregateSignatures function with the
+// suite of the scheme, and returns the encoding of the aggregate signature.
+func (s *Scheme) AggregateSignatures(sigs [][]byte, mask *sign.Mask) ([]byte, error) {
+	agg, err := AggregateSignatures(s.suite, sigs, mask)
+	if err != nil {
+		return nil, err
+	}
+	return agg.MarshalBinary()
+}
+
+// AggregatePublicKeys works like the AggregatePublicKeys function with the
+// suite of the scheme.
+func (s *Scheme) AggregatePublicKeys(mask *sign.Mask) (kyber.Point, error) {
+	return AggregatePublicKeys(s.suite, mask)
+}
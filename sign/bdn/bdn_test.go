@@ -136,6 +136,66 @@ func TestBDN_RogueAttack(t *testing.T) {
 	require.Error(t, Verify(suite, agg, msg, sig))
 }
 
+func TestBDN_BLSCompatibility(t *testing.T) {
+	msg := []byte("Hello Boneh-Lynn-Shacham")
+	suite := bn256.NewSuite()
+	private1, public1 := bls.NewKeyPair(suite, random.New())
+	private2, public2 := bls.NewKeyPair(suite, random.New())
+
+	// a single BLS signature is a valid BDN signature
+	sig1, err := bls.Sign(suite, private1, msg)
+	require.NoError(t, err)
+	require.NoError(t, Verify(suite, public1, msg, sig1))
+	sig2, err := bls.Sign(suite, private2, msg)
+	require.NoError(t, err)
+
+	// the participation mask travels in its binary form
+	mask, _ := sign.NewMask(suite, []kyber.Point{public1, public2}, nil)
+	mask.SetBit(0, true)
+	mask.SetBit(1, true)
+	received, _ := sign.NewMask(suite, []kyber.Point{public1, public2}, nil)
+	require.NoError(t, received.SetMask(mask.Mask()))
+	require.Equal(t, []kyber.Point{public1, public2}, received.Participants())
+
+	aggregatedSig, err := AggregateSignatures(suite, [][]byte{sig1, sig2}, mask)
+	require.NoError(t, err)
+	sig, err := aggregatedSig.MarshalBinary()
+	require.NoError(t, err)
+	aggregatedKey, err := AggregatePublicKeys(suite, received)
+	require.NoError(t, err)
+	require.NoError(t, Verify(suite, aggregatedKey, msg, sig))
+}
+
+func TestBDN_Scheme(t *testing.T) {
+	msg := []byte("Hello Boneh-Drijvers-Neven")
+	scheme, err := sign.SchemeByName(bn256.NewSuite(), "bdn")
+	require.NoError(t, err)
+	private1, public1 := scheme.NewKeyPair(random.New())
+	private2, public2 := scheme.NewKeyPair(random.New())
+	sig1, err := scheme.Sign(private1, msg)
+	require.NoError(t, err)
+	require.NoError(t, scheme.Verify(public1, msg, sig1))
+	require.Error(t, scheme.Verify(public2, msg, sig1))
+	sig2, err := scheme.Sign(private2, msg)
+	require.NoError(t, err)
+	_, ok := scheme.(sign.AggregatableScheme)
+	require.False(t, ok)
+
+	bdn := scheme.(*Scheme)
+	mask, _ := sign.NewMask(suite, []kyber.Point{public1, public2}, nil)
+	mask.SetBit(0, true)
+	mask.SetBit(1, true)
+	sig, err := bdn.AggregateSignatures([][]byte{sig1, sig2}, mask)
+	require.NoError(t, err)
+	public, err := bdn.AggregatePublicKeys(mask)
+	require.NoError(t, err)
+	require.NoError(t, scheme.Verify(public, msg, sig))
+	require.Error(t, scheme.Verify(public1, msg, sig))
+
+	_, err = sign.SchemeByName(nil, "bdn")
+	require.Error(t, err)
+}
+
 func Benchmark_BDN_AggregateSigs(b *testing.B) {
 	suite := bn256.NewSuite()
 	private1, public1 := NewKeyPair(suite, random.New())
@@ -0,0 +1,165 @@
+// Package beacon implements a distributed randomness beacon in the style of
+// drand (https://drand.love), on top of threshold BLS signatures: the nodes
+// of a group hold the shares of a distributed key, e.g. computed by one of the
+// kyber/share/dkg packages, and the random value of each round is the hash of
+// the BLS signature of the group over the round number. A BLS signature is
+// unique for a given key and message, so that the random values cannot be
+// biased by the nodes, and any t nodes can produce them, while less than t
+// nodes cannot predict them. Anyone can verify the random value of a round
+// with the distributed public key alone.
+//
+// Each round runs as follows:
+//  1. Every node computes its partial signature of the round with
+//     PartialSign and sends it to the aggregator, or to every other node.
+//  2. The aggregator checks the partial signatures as they arrive with
+//     VerifyPartial, and recovers the signature of the round with Aggregate
+//     from t valid ones.
+//  3. Anyone verifies the round with Verify and takes its random value from
+//     Randomness.
+//
+// A beacon is either unchained, where the message of a round is the hash of
+// its number alone, or chained, where it also covers the signature of the
+// previous round, so that the rounds form a chain that cannot be computed
+// ahead. The messages are the ones of drand: the SHA-256 hash of the previous
+// signature, if any, followed by the round number as 8 bytes in big endian.
+package beacon
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/share"
+	"go.dedis.ch/kyber/v3/sign/tbls"
+)
+
+// ErrChain is returned by VerifyChain when the previous signature of a round
+// is not the signature of the round before it.
+var ErrChain = errors.New("beacon: broken chain of rounds")
+
+// Round is the output of the beacon for one round.
+type Round struct {
+	// Number of the round
+	Round uint64
+	// Signature of the previous round, nil for an unchained beacon
+	PreviousSignature []byte
+	// Signature of the group over the message of the round
+	Signature []byte
+}
+
+// Message returns the message signed for the given round, which is the
+// SHA-256 hash of the previous signature, or of nothing for an unchained
+// beacon, followed by the round number as 8 bytes in big endian.
+func Message(round uint64, previous []byte) []byte {
+	h := sha256.New()
+	_, _ = h.Write(previous)
+	_ = binary.Write(h, binary.BigEndian, round)
+	return h.Sum(nil)
+}
+
+// Message returns the message signed for the round.
+func (r *Round) Message() []byte {
+	return Message(r.Round, r.PreviousSignature)
+}
+
+// Randomness returns the random value of the round, which is the SHA-256 hash
+// of its signature. The round must have been verified with Verify before.
+func (r *Round) Randomness() []byte {
+	h := sha256.Sum256(r.Signature)
+	return h[:]
+}
+
+// Beacon produces and verifies the rounds of the beacon of a group of n nodes,
+// sharing the distributed key of the public polynomial public with the
+// threshold of the polynomial.
+type Beacon struct {
+	scheme *tbls.Scheme
+	public *share.PubPoly
+	n      int
+}
+
+// NewBeacon returns the Beacon of the distributed key of the public
+// polynomial, shared among n nodes, whose signatures follow the given
+// threshold BLS scheme. The commitments of the polynomial must be points of
+// the key group of the scheme.
+func NewBeacon(scheme *tbls.Scheme, public *share.PubPoly, n int) *Beacon {
+	return &Beacon{scheme: scheme, public: public, n: n}
+}
+
+// PublicKey returns the distributed public key, which verifies the rounds.
+func (b *Beacon) PublicKey() kyber.Point {
+	return b.public.Commit()
+}
+
+// PartialSign returns the partial signature of the round of the node holding
+// the share private of the distributed key. previous is the signature of the
+// previous round for a chained beacon, and nil otherwise.
+func (b *Beacon) PartialSign(private *share.PriShare, round uint64, previous []byte) ([]byte, error) {
+	return b.scheme.Sign(private, Message(round, previous))
+}
+
+// VerifyPartial checks the partial signature of the round against the public
+// polynomial, so that the aggregator can blame the node of an invalid one.
+func (b *Beacon) VerifyPartial(round uint64, previous, partial []byte) error {
+	return b.scheme.VerifyPartial(b.public, Message(round, previous), partial)
+}
+
+// Aggregate recovers the signature of the round from the partial signatures
+// of at least t nodes. It skips the invalid partial signatures, and returns
+// the indices of their nodes together with the round, see
+// tbls.Scheme.RecoverValid. It returns an error if less than t partial
+// signatures are valid.
+func (b *Beacon) Aggregate(round uint64, previous []byte, partials [][]byte) (*Round, []int, error) {
+	msg := Message(round, previous)
+	sig, invalid, err := b.scheme.RecoverValid(b.public, msg, partials, b.public.Threshold(), b.n)
+	if err != nil {
+		return nil, invalid, err
+	}
+	r := &Round{Round: round, Signature: sig}
+	if previous != nil {
+		r.PreviousSignature = append([]byte{}, previous...)
+	}
+	return r, invalid, nil
+}
+
+// Verify checks the signature of the round against the distributed public
+// key.
+func (b *Beacon) Verify(r *Round) error {
+	return Verify(b.scheme, b.PublicKey(), r)
+}
+
+// Verify checks the signature of the round against the distributed public
+// key with the given threshold BLS scheme. It returns nil if the round is
+// valid.
+func Verify(scheme *tbls.Scheme, public kyber.Point, r *Round) error {
+	if r == nil {
+		return errors.New("beacon: nil round")
+	}
+	if err := scheme.VerifyRecovered(public, r.Message(), r.Signature); err != nil {
+		return fmt.Errorf("beacon: invalid round %d: %w", r.Round, err)
+	}
+	return nil
+}
+
+// VerifyChain checks the consecutive rounds of a chained beacon: every round
+// must be valid, and its previous signature must be the signature of the
+// round before it in the list. The previous signature of the first round is
+// not checked.
+func VerifyChain(scheme *tbls.Scheme, public kyber.Point, rounds []*Round) error {
+	for i, r := range rounds {
+		if err := Verify(scheme, public, r); err != nil {
+			return err
+		}
+		if i == 0 {
+			continue
+		}
+		prev := rounds[i-1]
+		if r.Round != prev.Round+1 || !bytes.Equal(r.PreviousSignature, prev.Signature) {
+			return fmt.Errorf("%w: round %d does not follow round %d", ErrChain, r.Round, prev.Round)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package beacon
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/share"
+	dkg "go.dedis.ch/kyber/v3/share/dkg/pvss"
+	"go.dedis.ch/kyber/v3/sign/tbls"
+)
+
+var suite = pairing.NewSuiteBn256()
+
+// runDKG runs the non-interactive DKG among n nodes with the threshold t,
+// and returns their shares and the public polynomial of the distributed key.
+func runDKG(t *testing.T, n, th int) ([]*share.PriShare, *share.PubPoly) {
+	privs := make([]kyber.Scalar, n)
+	pubs := make([]kyber.Point, n)
+	for i := range privs {
+		privs[i] = suite.Scalar().Pick(suite.RandomStream())
+		pubs[i] = suite.Point().Mul(privs[i], nil)
+	}
+	dkgs := make([]*dkg.DistKeyGenerator, n)
+	deals := make([]*dkg.Deal, n)
+	for i := range dkgs {
+		d, err := dkg.NewDistKeyGenerator(suite, privs[i], pubs, th)
+		require.NoError(t, err)
+		dkgs[i] = d
+		deals[i], err = d.Deal()
+		require.NoError(t, err)
+	}
+	shares := make([]*share.PriShare, n)
+	var commits []kyber.Point
+	for i, d := range dkgs {
+		for _, dd := range deals {
+			c, err := d.ProcessDeal(dd)
+			require.NoError(t, err)
+			require.Nil(t, c)
+		}
+		dks, err := d.DistKeyShare()
+		require.NoError(t, err)
+		shares[i] = dks.Share
+		commits = dks.Commits
+	}
+	return shares, share.NewPubPoly(suite.G2(), suite.G2().Point().Base(), commits)
+}
+
+func TestBeacon(t *testing.T) {
+	n, th := 5, 3
+	shares, public := runDKG(t, n, th)
+	b := NewBeacon(tbls.NewSchemeOnG1(suite), public, n)
+	require.True(t, b.PublicKey().Equal(public.Commit()))
+
+	var rounds []*Round
+	var previous []byte
+	for round := uint64(1); round <= 3; round++ {
+		partials := make([][]byte, n)
+		for i, s := range shares {
+			p, err := b.PartialSign(s, round, previous)
+			require.NoError(t, err)
+			require.NoError(t, b.VerifyPartial(round, previous, p))
+			partials[i] = p
+		}
+		r, invalid, err := b.Aggregate(round, previous, partials[:th])
+		require.NoError(t, err)
+		require.Empty(t, invalid)
+		require.NoError(t, b.Verify(r))
+		require.Len(t, r.Randomness(), 32)
+
+		// any t nodes produce the same random value
+		other, _, err := b.Aggregate(round, previous, partials[n-th:])
+		require.NoError(t, err)
+		require.Equal(t, r.Randomness(), other.Randomness())
+
+		rounds = append(rounds, r)
+		previous = r.Signature
+	}
+	require.NotEqual(t, rounds[0].Randomness(), rounds[1].Randomness())
+	require.NoError(t, VerifyChain(tbls.NewSchemeOnG1(suite), b.PublicKey(), rounds))
+
+	// a round of the chain replaced with an unchained one
+	unchained := make([][]byte, th)
+	for i, s := range shares[:th] {
+		p, err := b.PartialSign(s, 2, nil)
+		require.NoError(t, err)
+		unchained[i] = p
+	}
+	r, _, err := b.Aggregate(2, nil, unchained)
+	require.NoError(t, err)
+	require.NoError(t, b.Verify(r))
+	require.Nil(t, r.PreviousSignature)
+	broken := []*Round{rounds[0], r, rounds[2]}
+	err = VerifyChain(tbls.NewSchemeOnG1(suite), b.PublicKey(), broken)
+	require.True(t, errors.Is(err, ErrChain))
+
+	// a round with another number does not verify
+	forged := *rounds[0]
+	forged.Round = 7
+	require.Error(t, b.Verify(&forged))
+}
+
+func TestBeaconInvalidPartials(t *testing.T) {
+	n, th := 5, 3
+	shares, public := runDKG(t, n, th)
+	b := NewBeacon(tbls.NewSchemeOnG1(suite), public, n)
+
+	partials := make([][]byte, n)
+	for i, s := range shares {
+		p, err := b.PartialSign(s, 42, nil)
+		require.NoError(t, err)
+		partials[i] = p
+	}
+	// the node 1 signs another round
+	bad, err := b.PartialSign(shares[1], 43, nil)
+	require.NoError(t, err)
+	partials[1] = bad
+	require.Error(t, b.VerifyPartial(42, nil, bad))
+
+	r, invalid, err := b.Aggregate(42, nil, partials)
+	require.NoError(t, err)
+	require.Equal(t, []int{1}, invalid)
+	require.NoError(t, b.Verify(r))
+
+	_, invalid, err = b.Aggregate(42, nil, partials[:th])
+	require.Error(t, err)
+	require.Equal(t, []int{1}, invalid)
+}
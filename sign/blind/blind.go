@@ -0,0 +1,148 @@
+/*
+Package blind implements blind signatures, with which a requester obtains the
+signature of a message from a signer without revealing the message to the
+signer, who cannot link the signature to its signing session afterwards, e.g.
+to issue anonymous tokens or credentials. The signatures are regular Schnorr
+signatures of the schnorr package, or BLS signatures of the bls package.
+
+The blind Schnorr signature takes three moves. The signer commits to a nonce k
+with R = k*B. The requester blinds R into R' = R + a*B + b*X for random a and
+b and the public key X of the signer, and sends the challenge c = c' + b of
+the challenge c' = H(R' || X || M) of the message. The signer answers with
+s = k + c*x, which the requester unblinds into the signature R' || s + a of
+the message.
+
+The blind BLS signature takes two moves. The requester sends the hash of the
+message multiplied by a random factor r, r*H(M). The signer multiplies it by
+its private key x, and the requester removes the factor from x*r*H(M) to get
+the signature x*H(M) of the message.
+
+A blind signature gives the signer no information on the message it signs, so
+that the application must limit what a signature grants, e.g. with one key
+per value of the tokens. The blind Schnorr signatures are only secure if the
+signer runs its sessions one at a time: answering the challenges of many
+concurrent sessions allows the ROS attack of Benhamouda et al. (Eurocrypt
+2021), which forges one signature more than the sessions. A SchnorrSigner
+holds a single nonce at a time, so that its sessions are sequential, but a
+signer must also not run several SchnorrSigners with the same key
+concurrently. The blind BLS signatures do not have this limitation.
+*/
+package blind
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"errors"
+
+	"go.dedis.ch/kyber/v3"
+)
+
+// Suite represents the set of functionalities needed by the blind Schnorr
+// signatures.
+type Suite interface {
+	kyber.Group
+	kyber.Random
+}
+
+var errNoNonce = errors.New("blind: no fresh nonce, call Commit before signing")
+
+// SchnorrSigner is the signer of blind Schnorr signatures, holding the
+// private key and the nonce of the current session.
+type SchnorrSigner struct {
+	suite   Suite
+	private kyber.Scalar
+	k       kyber.Scalar // secret nonce, nil once used
+}
+
+// NewSchnorrSigner returns the signer with the private key.
+func NewSchnorrSigner(suite Suite, private kyber.Scalar) *SchnorrSigner {
+	return &SchnorrSigner{suite: suite, private: private}
+}
+
+// Commit generates a fresh nonce, replacing any unused one, and returns its
+// commitment R, the first message of a session.
+func (s *SchnorrSigner) Commit() kyber.Point {
+	s.k = s.suite.Scalar().Pick(s.suite.RandomStream())
+	return s.suite.Point().Mul(s.k, nil)
+}
+
+// Sign returns the answer s = k + c*x to the blinded challenge c of the
+// requester. The nonce is erased, so that Sign returns an error until Commit
+// is called again.
+func (s *SchnorrSigner) Sign(c kyber.Scalar) (kyber.Scalar, error) {
+	if s.k == nil {
+		return nil, errNoNonce
+	}
+	k := s.k
+	s.k = nil
+	res := s.suite.Scalar().Mul(c, s.private)
+	res.Add(res, k)
+	k.Zero()
+	return res, nil
+}
+
+// SchnorrRequest is the state of the requester of a blind Schnorr signature
+// of a message.
+type SchnorrRequest struct {
+	suite  Suite
+	public kyber.Point
+	// commitment of the signer, and its blinded version
+	R, blindR kyber.Point
+	// blinding scalar a of the response, and the blinded challenge
+	a, c kyber.Scalar
+}
+
+// NewSchnorrRequest blinds the commitment R of the signer of public key
+// public for the message, and returns the request whose Challenge is the
+// message to send to the signer.
+func NewSchnorrRequest(suite Suite, public, R kyber.Point, msg []byte) (*SchnorrRequest, error) {
+	if R == nil || public == nil {
+		return nil, errors.New("blind: nil commitment or public key")
+	}
+	rand := suite.RandomStream()
+	a := suite.Scalar().Pick(rand)
+	b := suite.Scalar().Pick(rand)
+	// R' = R + a*B + b*X
+	blindR := suite.Point().Mul(a, nil)
+	blindR.Add(blindR, R)
+	blindR.Add(blindR, suite.Point().Mul(b, public))
+
+	// c = H(R' || X || M) + b
+	h := sha512.New()
+	if _, err := blindR.MarshalTo(h); err != nil {
+		return nil, err
+	}
+	if _, err := public.MarshalTo(h); err != nil {
+		return nil, err
+	}
+	_, _ = h.Write(msg)
+	c := suite.Scalar().SetBytes(h.Sum(nil))
+	c.Add(c, b)
+	return &SchnorrRequest{suite: suite, public: public, R: R, blindR: blindR, a: a, c: c}, nil
+}
+
+// Challenge returns the blinded challenge to send to the signer.
+func (r *SchnorrRequest) Challenge() kyber.Scalar {
+	return r.c
+}
+
+// Unblind checks the answer s of the signer and returns the Schnorr signature
+// of the message, which schnorr.Verify accepts for the public key of the
+// signer. It returns an error if the answer is invalid.
+func (r *SchnorrRequest) Unblind(s kyber.Scalar) ([]byte, error) {
+	// s*B = R + c*X
+	left := r.suite.Point().Mul(s, nil)
+	right := r.suite.Point().Mul(r.c, r.public)
+	right.Add(right, r.R)
+	if !left.Equal(right) {
+		return nil, errors.New("blind: invalid answer of the signer")
+	}
+	var b bytes.Buffer
+	if _, err := r.blindR.MarshalTo(&b); err != nil {
+		return nil, err
+	}
+	if _, err := r.suite.Scalar().Add(s, r.a).MarshalTo(&b); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
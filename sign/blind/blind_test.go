@@ -0,0 +1,116 @@
+package blind
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/group/nist"
+	"go.dedis.ch/kyber/v3/pairing/bn256"
+	"go.dedis.ch/kyber/v3/sign/bls"
+	"go.dedis.ch/kyber/v3/sign/eddsa"
+	"go.dedis.ch/kyber/v3/sign/schnorr"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+func TestBlindSchnorr(t *testing.T) {
+	msg := []byte("one token")
+	for _, suite := range []Suite{
+		edwards25519.NewBlakeSHA256Ed25519(),
+		nist.NewBlakeSHA256P256(),
+	} {
+		x := suite.Scalar().Pick(suite.RandomStream())
+		X := suite.Point().Mul(x, nil)
+		signer := NewSchnorrSigner(suite, x)
+
+		R := signer.Commit()
+		req, err := NewSchnorrRequest(suite, X, R, msg)
+		require.NoError(t, err)
+		s, err := signer.Sign(req.Challenge())
+		require.NoError(t, err)
+		sig, err := req.Unblind(s)
+		require.NoError(t, err)
+		require.NoError(t, schnorr.Verify(suite, X, msg, sig), suite.String())
+		require.Error(t, schnorr.Verify(suite, X, []byte("two tokens"), sig))
+
+		// the signer does not see the commitment of the signature
+		require.False(t, R.Equal(req.blindR))
+
+		// the nonce is used once
+		_, err = signer.Sign(req.Challenge())
+		require.Equal(t, errNoNonce, err)
+
+		// an answer with another key or for another commitment is caught
+		other := NewSchnorrSigner(suite, suite.Scalar().Pick(suite.RandomStream()))
+		req, err = NewSchnorrRequest(suite, X, other.Commit(), msg)
+		require.NoError(t, err)
+		s, err = other.Sign(req.Challenge())
+		require.NoError(t, err)
+		_, err = req.Unblind(s)
+		require.Error(t, err)
+
+		req, err = NewSchnorrRequest(suite, X, signer.Commit(), msg)
+		require.NoError(t, err)
+		signer.Commit()
+		s, err = signer.Sign(req.Challenge())
+		require.NoError(t, err)
+		_, err = req.Unblind(s)
+		require.Error(t, err)
+	}
+}
+
+func TestBlindSchnorrEdDSA(t *testing.T) {
+	// the blind signatures on edwards25519 are Ed25519 signatures
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	ed := eddsa.NewEdDSA(random.New())
+	msg := []byte("one token")
+
+	signer := NewSchnorrSigner(suite, ed.Secret)
+	req, err := NewSchnorrRequest(suite, ed.Public, signer.Commit(), msg)
+	require.NoError(t, err)
+	s, err := signer.Sign(req.Challenge())
+	require.NoError(t, err)
+	sig, err := req.Unblind(s)
+	require.NoError(t, err)
+	require.NoError(t, eddsa.Verify(ed.Public, msg, sig))
+}
+
+func TestBlindBLS(t *testing.T) {
+	suite := bn256.NewSuite()
+	msg := []byte("one token")
+	for _, scheme := range []*bls.Scheme{bls.NewSchemeOnG1(suite), bls.NewSchemeOnG2(suite)} {
+		x, X := scheme.NewKeyPair(random.New())
+
+		blinded, r, err := BlindBLS(scheme, msg, random.New())
+		require.NoError(t, err)
+		HM, err := scheme.HashMessage(msg)
+		require.NoError(t, err)
+		require.False(t, blinded.Equal(HM))
+
+		blindSig, err := SignBLS(scheme, x, blinded)
+		require.NoError(t, err)
+		sig, err := UnblindBLS(scheme, X, msg, r, blindSig)
+		require.NoError(t, err)
+		require.NoError(t, scheme.Verify(X, msg, sig))
+
+		// the signature is the one of the message
+		expected, err := scheme.Sign(x, msg)
+		require.NoError(t, err)
+		require.Equal(t, expected, sig)
+
+		// a signature with another key, another factor or of another message
+		// is caught
+		y, _ := scheme.NewKeyPair(random.New())
+		bad, err := SignBLS(scheme, y, blinded)
+		require.NoError(t, err)
+		_, err = UnblindBLS(scheme, X, msg, r, bad)
+		require.Error(t, err)
+		_, err = UnblindBLS(scheme, X, msg, r.Clone().Add(r, r), blindSig)
+		require.Error(t, err)
+		_, err = UnblindBLS(scheme, X, []byte("two tokens"), r, blindSig)
+		require.Error(t, err)
+
+		_, err = SignBLS(scheme, x, scheme.SignatureGroup().Point().Null())
+		require.Error(t, err)
+	}
+}
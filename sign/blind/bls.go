@@ -0,0 +1,56 @@
+package blind
+
+import (
+	"crypto/cipher"
+	"errors"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/sign/bls"
+)
+
+// BlindBLS returns the blinded hash r*H(M) of the message for the BLS scheme,
+// to send to the signer, and the blinding factor r to unblind its signature.
+func BlindBLS(scheme *bls.Scheme, msg []byte, random cipher.Stream) (kyber.Point, kyber.Scalar, error) {
+	HM, err := scheme.HashMessage(msg)
+	if err != nil {
+		return nil, nil, err
+	}
+	g := scheme.SignatureGroup()
+	r := g.Scalar().Pick(random)
+	for r.Equal(g.Scalar().Zero()) {
+		r.Pick(random)
+	}
+	return g.Point().Mul(r, HM), r, nil
+}
+
+// SignBLS returns the blind signature x*P of the blinded hash P of a
+// requester, with the private key x. The signer learns nothing about the
+// message.
+func SignBLS(scheme *bls.Scheme, private kyber.Scalar, blinded kyber.Point) ([]byte, error) {
+	g := scheme.SignatureGroup()
+	if blinded == nil || blinded.Equal(g.Point().Null()) {
+		return nil, errors.New("blind: invalid blinded message")
+	}
+	return g.Point().Mul(private, blinded).MarshalBinary()
+}
+
+// UnblindBLS removes the blinding factor of BlindBLS from the blind signature
+// of the signer of public key public, and returns the BLS signature of the
+// message, which the Verify method of the scheme accepts. It returns an error
+// if the signature is invalid.
+func UnblindBLS(scheme *bls.Scheme, public kyber.Point, msg []byte, factor kyber.Scalar, blindSig []byte) ([]byte, error) {
+	g := scheme.SignatureGroup()
+	S := g.Point()
+	if err := S.UnmarshalBinary(blindSig); err != nil {
+		return nil, err
+	}
+	S.Mul(g.Scalar().Inv(factor), S)
+	sig, err := S.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	if err := scheme.Verify(public, msg, sig); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
@@ -8,53 +8,345 @@
 // in kyber/sign/bdn. Note that only the aggregation is broken against the
 // attack and a later version will merge bls and asmbls.
 //
+// The package-level functions put the public keys on curve G2 and the
+// signatures on curve G1, and hash the messages with the domain separation tag
+// DefaultDST, none by default, or with the one given to SignWithDST and
+// VerifyWithDST. A Scheme selects the curves holding the public keys and the
+// signatures, see NewSchemeOnG1 and NewSchemeOnG2, and the hashing of the
+// messages: the hash of the points of the signature group with the tag
+// appended to the message by default, or the hash_to_curve function of RFC
+// 9380 with the tag of the ciphersuites of the IETF BLS signature draft, see
+// WithHashToCurve.
+//
 // See the paper: https://crypto.stanford.edu/~dabo/pubs/papers/BLSmultisig.html
 package bls
 
 import (
+	"bytes"
 	"crypto/cipher"
 	"crypto/sha256"
 	"errors"
 	"fmt"
+	"io"
+	"reflect"
 
 	"go.dedis.ch/kyber/v3"
 	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/sign/internal/parallel"
+	"go.dedis.ch/kyber/v3/util/random"
 )
 
+// WireVersion is the version of the format of the signatures, that is of the
+// encoding of the signature points and of the hashing of the messages with
+// their domain separation tag. It changes whenever the signatures of the
+// previous version do not verify anymore.
+const WireVersion = 1
+
+// DefaultDST is the domain separation tag of the package-level functions which
+// do not take one. It is nil by default, so that the messages are hashed as
+// they are, as in the previous versions. An application may set it once at
+// init time, before signing or verifying anything, to separate its
+// signatures from the ones of other protocols using the same keys.
+var DefaultDST []byte
+
+var errKeyGroup = errors.New("bls: public key is not a point of the key group")
+
+// millerPoint is implemented by the points of GT computing the Miller loop
+// and the final exponentiation of a pairing separately, so that a product of
+// pairings needs a single final exponentiation.
+type millerPoint interface {
+	Miller(p1, p2 kyber.Point) kyber.Point
+	Finalize() kyber.Point
+}
+
 type hashablePoint interface {
 	Hash([]byte) kyber.Point
 }
 
+// readerPointHasher is implemented by the groups that can hash a message
+// streamed from a reader to a point, as kyber.PointHasher would hash it in
+// memory.
+type readerPointHasher interface {
+	HashReaderToPoint(dst []byte, r io.Reader) (kyber.Point, error)
+}
+
+// readerHashablePoint is implemented by the points that can hash a message
+// streamed from a reader, as Hash would hash it in memory.
+type readerHashablePoint interface {
+	HashReader(io.Reader) (kyber.Point, error)
+}
+
+// Scheme is a BLS signature scheme with given roles of the curves of the
+// pairing: one holds the public keys and the other the signatures, onto which
+// the messages are hashed along with a domain separation tag.
+type Scheme struct {
+	suite    pairing.Suite
+	keyGroup kyber.Group
+	sigGroup kyber.Group
+	// pair computes the pairing of a point of the signature group and a
+	// point of the key group
+	pair func(sig, key kyber.Point) kyber.Point
+	// domain separation tag followed by its length, see dstPrime
+	dst []byte
+	// domain separation tag, and whether the messages are hashed with the
+	// hash_to_curve function of RFC 9380, see WithHashToCurve
+	tag         []byte
+	hashToCurve bool
+	// maximum number of goroutines computing the pairings of BatchVerify,
+	// see WithConcurrency
+	concurrency int
+}
+
+// NewSchemeOnG1 returns the scheme having its signatures on curve G1 and its
+// public keys on curve G2, which minimizes the size of the signatures. The
+// messages are hashed with the domain separation tag
+// "BLS_SIG_<name of G1>_TAI_NUL_".
+func NewSchemeOnG1(suite pairing.Suite) *Scheme {
+	return &Scheme{
+		suite:    suite,
+		keyGroup: suite.G2(),
+		sigGroup: suite.G1(),
+		pair:     suite.Pair,
+		dst:      dstPrime(schemeDST(suite.G1())),
+		tag:      schemeDST(suite.G1()),
+	}
+}
+
+// NewSchemeOnG2 returns the scheme having its signatures on curve G2 and its
+// public keys on curve G1, which minimizes the size of the public keys. The
+// messages are hashed with the domain separation tag
+// "BLS_SIG_<name of G2>_TAI_NUL_".
+func NewSchemeOnG2(suite pairing.Suite) *Scheme {
+	return &Scheme{
+		suite:    suite,
+		keyGroup: suite.G1(),
+		sigGroup: suite.G2(),
+		pair: func(sig, key kyber.Point) kyber.Point {
+			return suite.Pair(key, sig)
+		},
+		dst: dstPrime(schemeDST(suite.G2())),
+		tag: schemeDST(suite.G2()),
+	}
+}
+
+// WithDST returns a copy of the scheme hashing the messages with the given
+// domain separation tag instead, e.g. to interoperate with another
+// implementation. An empty tag hashes the messages as they are, like the
+// package-level functions do by default.
+func (s *Scheme) WithDST(dst []byte) *Scheme {
+	c := *s
+	c.dst = dstPrime(dst)
+	c.tag = append([]byte{}, dst...)
+	return &c
+}
+
+// WithHashToCurve returns a copy of the scheme hashing the messages to the
+// signature group with its hash_to_curve function of RFC 9380, see
+// kyber.PointHasher, and the domain separation tag dst, which must not be
+// empty, as the ciphersuites of the IETF BLS signature draft do, e.g. with
+// the tag "BLS_SIG_BN256G1_XMD:SHA-256_SVDW_RO_NUL_" on G1 of bn256. The
+// signatures do not verify with the schemes hashing the messages otherwise.
+// The signature group must implement kyber.PointHasher.
+func (s *Scheme) WithHashToCurve(dst []byte) *Scheme {
+	c := s.WithDST(dst)
+	c.hashToCurve = true
+	return c
+}
+
+// WithConcurrency returns a copy of the scheme computing the pairings of
+// BatchVerify over at most n goroutines, and at most runtime.GOMAXPROCS of
+// them. By default, or if n is at most 1, the scheme computes them one after
+// the other in the calling goroutine. The result does not depend on n.
+func (s *Scheme) WithConcurrency(n int) *Scheme {
+	c := *s
+	c.concurrency = n
+	return &c
+}
+
+// KeyGroup returns the group of the public keys.
+func (s *Scheme) KeyGroup() kyber.Group {
+	return s.keyGroup
+}
+
+// SignatureGroup returns the group of the signatures.
+func (s *Scheme) SignatureGroup() kyber.Group {
+	return s.sigGroup
+}
+
+// HashMessage returns the point of the signature group the message is hashed
+// to with the domain separation tag of the scheme, of which the signatures of
+// the message are multiples. It is the identity point of the message in the
+// identity-based encryption of the encrypt/ibe package.
+func (s *Scheme) HashMessage(msg []byte) (kyber.Point, error) {
+	return s.hash(msg)
+}
+
+// Pair returns the pairing of the point sig of the signature group with the
+// point key of the key group.
+func (s *Scheme) Pair(sig, key kyber.Point) kyber.Point {
+	return s.pair(sig, key)
+}
+
+func schemeDST(g kyber.Group) []byte {
+	return []byte("BLS_SIG_" + g.String() + "_TAI_NUL_")
+}
+
+// dstPrime returns the bytes appended to the messages before hashing them,
+// made of the domain separation tag followed by its length on one byte, as
+// DST_prime in the IETF hash-to-curve draft, so that no pair of tag and
+// message hashes like another one. A tag longer than 255 bytes is replaced by
+// its hash as in the draft. An empty tag gives nil.
+func dstPrime(dst []byte) []byte {
+	if len(dst) == 0 {
+		return nil
+	}
+	if len(dst) > 255 {
+		h := sha256.Sum256(append([]byte("H2C-OVERSIZE-DST-"), dst...))
+		dst = h[:]
+	}
+	return append(append([]byte{}, dst...), byte(len(dst)))
+}
+
+// legacy returns the scheme of the package-level functions.
+func legacy(suite pairing.Suite) *Scheme {
+	return NewSchemeOnG1(suite).WithDST(DefaultDST)
+}
+
 // NewKeyPair creates a new BLS signing key pair. The private key x is a scalar
 // and the public key X is a point on curve G2.
 func NewKeyPair(suite pairing.Suite, random cipher.Stream) (kyber.Scalar, kyber.Point) {
-	x := suite.G2().Scalar().Pick(random)
-	X := suite.G2().Point().Mul(x, nil)
-	return x, X
+	return legacy(suite).NewKeyPair(random)
 }
 
 // Sign creates a BLS signature S = x * H(m) on a message m using the private
 // key x. The signature S is a point on curve G1.
 func Sign(suite pairing.Suite, x kyber.Scalar, msg []byte) ([]byte, error) {
-	hashable, ok := suite.G1().Point().(hashablePoint)
-	if !ok {
-		return nil, errors.New("point needs to implement hashablePoint")
+	return legacy(suite).Sign(x, msg)
+}
+
+// SignWithDST works like Sign but hashes the message with the given domain
+// separation tag instead of DefaultDST. The signature only verifies with
+// VerifyWithDST and the same tag.
+func SignWithDST(suite pairing.Suite, x kyber.Scalar, dst, msg []byte) ([]byte, error) {
+	return legacy(suite).WithDST(dst).Sign(x, msg)
+}
+
+// AggregateSignatures combines signatures created using the Sign function
+func AggregateSignatures(suite pairing.Suite, sigs ...[]byte) ([]byte, error) {
+	return legacy(suite).AggregateSignatures(sigs...)
+}
+
+// AggregatePublicKeys takes a slice of public G2 points and returns
+// the sum of those points. This is used to verify multisignatures.
+func AggregatePublicKeys(suite pairing.Suite, Xs ...kyber.Point) kyber.Point {
+	return legacy(suite).AggregatePublicKeys(Xs...)
+}
+
+// BatchVerify verifies a large number of publicKey/msg pairings with a single aggregated signature.
+// Since aggregation is generally much faster than verification, this can be a speed enhancement.
+// Benchmarks show a roughly 50% performance increase over individual signature verification
+// Every msg must be unique or there is the possibility to accept an invalid signature
+// see: https://crypto.stackexchange.com/questions/56288/is-bls-signature-scheme-strongly-unforgeable/56290
+// for a description of why each message must be unique.
+// It is the same as VerifyAggregateDistinct.
+func BatchVerify(suite pairing.Suite, publics []kyber.Point, msgs [][]byte, sig []byte) error {
+	return legacy(suite).BatchVerify(publics, msgs, sig)
+}
+
+// VerifyAggregateDistinct verifies the aggregate signature aggSig, made with
+// AggregateSignatures of the signatures of the distinct messages msgs[i]
+// under publics[i], by checking that e(H(m1), X1) * ... * e(H(mn), Xn) ==
+// e(aggSig, B2) with a single product of pairings. As the messages are
+// distinct, the aggregate cannot be forged with rogue public keys, so that
+// this aggregation is safe without the proofs of possession or the
+// coefficients of the bdn package. It returns an error if two messages are
+// equal.
+func VerifyAggregateDistinct(suite pairing.Suite, publics []kyber.Point, msgs [][]byte, aggSig []byte) error {
+	return legacy(suite).VerifyAggregateDistinct(publics, msgs, aggSig)
+}
+
+// BatchVerifySignatures verifies many independent signatures at once, where
+// sigs[i] is the signature of msgs[i] under publics[i], much faster than
+// calling Verify for each of them: the signatures are combined with random
+// coefficients and checked with a single product of pairings. Unlike
+// BatchVerify, the signatures are not aggregated beforehand and the messages
+// need not be distinct. It returns an error if any signature is invalid,
+// without telling which one, so that the caller must verify the signatures
+// one by one to find the invalid ones.
+func BatchVerifySignatures(suite pairing.Suite, publics []kyber.Point, msgs, sigs [][]byte) error {
+	return legacy(suite).BatchVerifySignatures(publics, msgs, sigs)
+}
+
+// Verify checks the given BLS signature S on the message m using the public
+// key X by verifying that the equality e(H(m), X) == e(H(m), x*B2) ==
+// e(x*H(m), B2) == e(S, B2) holds where e is the pairing operation and B2 is
+// the base point from curve G2.
+func Verify(suite pairing.Suite, X kyber.Point, msg, sig []byte) error {
+	return legacy(suite).Verify(X, msg, sig)
+}
+
+// VerifyWithDST works like Verify for a signature created by SignWithDST with
+// the given domain separation tag.
+func VerifyWithDST(suite pairing.Suite, X kyber.Point, dst, msg, sig []byte) error {
+	return legacy(suite).WithDST(dst).Verify(X, msg, sig)
+}
+
+// SignReader works like Sign on the message read from r until EOF, which is
+// streamed through the hash function instead of being held in memory. The
+// signature is the same as the one Sign returns for the whole message. An
+// error of r is returned instead of a signature of a truncated message.
+func SignReader(suite pairing.Suite, x kyber.Scalar, r io.Reader) ([]byte, error) {
+	return legacy(suite).SignReader(x, r)
+}
+
+// VerifyReader works like Verify on the message read from r until EOF, see
+// SignReader.
+func VerifyReader(suite pairing.Suite, X kyber.Point, r io.Reader, sig []byte) error {
+	return legacy(suite).VerifyReader(X, r, sig)
+}
+
+// NewKeyPair creates a new BLS signing key pair. The private key x is a scalar
+// and the public key X is a point of the key group.
+func (s *Scheme) NewKeyPair(random cipher.Stream) (kyber.Scalar, kyber.Point) {
+	x := s.keyGroup.Scalar().Pick(random)
+	X := s.keyGroup.Point().Mul(x, nil)
+	return x, X
+}
+
+// Sign creates a BLS signature S = x * H(m) on a message m using the private
+// key x. The signature S is a point of the signature group.
+func (s *Scheme) Sign(x kyber.Scalar, msg []byte) ([]byte, error) {
+	HM, err := s.hash(msg)
+	if err != nil {
+		return nil, err
+	}
+	return sign(x, HM)
+}
+
+// SignReader works like the SignReader function with the scheme.
+func (s *Scheme) SignReader(x kyber.Scalar, r io.Reader) ([]byte, error) {
+	HM, err := s.hashReader(r)
+	if err != nil {
+		return nil, err
 	}
-	HM := hashable.Hash(msg)
+	return sign(x, HM)
+}
+
+func sign(x kyber.Scalar, HM kyber.Point) ([]byte, error) {
 	xHM := HM.Mul(x, HM)
 
-	s, err := xHM.MarshalBinary()
+	sig, err := xHM.MarshalBinary()
 	if err != nil {
 		return nil, err
 	}
-	return s, nil
+	return sig, nil
 }
 
-// AggregateSignatures combines signatures created using the Sign function
-func AggregateSignatures(suite pairing.Suite, sigs ...[]byte) ([]byte, error) {
-	sig := suite.G1().Point()
+// AggregateSignatures combines signatures created using the Sign method.
+func (s *Scheme) AggregateSignatures(sigs ...[]byte) ([]byte, error) {
+	sig := s.sigGroup.Point()
 	for _, sigBytes := range sigs {
-		sigToAdd := suite.G1().Point()
+		sigToAdd := s.sigGroup.Point()
 		if err := sigToAdd.UnmarshalBinary(sigBytes); err != nil {
 			return nil, err
 		}
@@ -63,77 +355,251 @@ func AggregateSignatures(suite pairing.Suite, sigs ...[]byte) ([]byte, error) {
 	return sig.MarshalBinary()
 }
 
-// AggregatePublicKeys takes a slice of public G2 points and returns
-// the sum of those points. This is used to verify multisignatures.
-func AggregatePublicKeys(suite pairing.Suite, Xs ...kyber.Point) kyber.Point {
-	aggregated := suite.G2().Point()
+// AggregatePublicKeys returns the sum of the public keys. This is used to
+// verify multisignatures.
+func (s *Scheme) AggregatePublicKeys(Xs ...kyber.Point) kyber.Point {
+	aggregated := s.keyGroup.Point()
 	for _, X := range Xs {
 		aggregated.Add(aggregated, X)
 	}
 	return aggregated
 }
 
-// BatchVerify verifies a large number of publicKey/msg pairings with a single aggregated signature.
-// Since aggregation is generally much faster than verification, this can be a speed enhancement.
-// Benchmarks show a roughly 50% performance increase over individual signature verification
-// Every msg must be unique or there is the possibility to accept an invalid signature
-// see: https://crypto.stackexchange.com/questions/56288/is-bls-signature-scheme-strongly-unforgeable/56290
-// for a description of why each message must be unique.
-func BatchVerify(suite pairing.Suite, publics []kyber.Point, msgs [][]byte, sig []byte) error {
+// BatchVerify works like the BatchVerify function with the groups and the
+// domain separation tag of the scheme. It is the same as
+// VerifyAggregateDistinct.
+func (s *Scheme) BatchVerify(publics []kyber.Point, msgs [][]byte, sig []byte) error {
+	return s.VerifyAggregateDistinct(publics, msgs, sig)
+}
+
+// VerifyAggregateDistinct works like the VerifyAggregateDistinct function
+// with the groups and the domain separation tag of the scheme. The hashes of
+// the messages and the Miller loops of the pairings are spread over the
+// goroutines of WithConcurrency.
+func (s *Scheme) VerifyAggregateDistinct(publics []kyber.Point, msgs [][]byte, aggSig []byte) error {
 	if !distinct(msgs) {
 		return fmt.Errorf("bls: error, messages must be distinct")
 	}
 
-	s := suite.G1().Point()
-	if err := s.UnmarshalBinary(sig); err != nil {
+	S := s.sigGroup.Point()
+	if err := S.UnmarshalBinary(aggSig); err != nil {
 		return err
 	}
 
-	var aggregatedLeft kyber.Point
-	for i := range msgs {
-		hashable, ok := suite.G1().Point().(hashablePoint)
-		if !ok {
-			return errors.New("bls: point needs to implement hashablePoint")
+	if len(msgs) == 0 {
+		return errors.New("bls: no message to verify")
+	}
+	if len(publics) != len(msgs) {
+		return errors.New("bls: different numbers of public keys and messages")
+	}
+	for _, X := range publics {
+		if !s.isKey(X) {
+			return errKeyGroup
 		}
-		hm := hashable.Hash(msgs[i])
-		pair := suite.Pair(hm, publics[i])
+	}
+
+	// the last pair is the one of the aggregate signature
+	hms, err := s.hashAll(msgs)
+	if err != nil {
+		return err
+	}
+	sigPoints := append(hms, S.Neg(S))
+	keys := append(append([]kyber.Point{}, publics...), s.keyGroup.Point().Base())
+	if !s.pairProduct(sigPoints, keys).Equal(s.suite.GT().Point().Null()) {
+		return errors.New("bls: invalid signature")
+	}
+	return nil
+}
+
+// BatchVerifySignatures works like the BatchVerifySignatures function with
+// the groups and the domain separation tag of the scheme. It checks that
+// e(r1*H(m1), X1) * ... * e(rn*H(mn), Xn) * e(-(r1*S1 + ... + rn*Sn), B) == 1
+// for random 128-bit coefficients ri, so that invalid signatures cannot
+// compensate each other but with a negligible probability. The hashes of the
+// messages and the Miller loops of the pairings are spread over the
+// goroutines of WithConcurrency.
+func (s *Scheme) BatchVerifySignatures(publics []kyber.Point, msgs, sigs [][]byte) error {
+	if len(msgs) == 0 {
+		return errors.New("bls: no message to verify")
+	}
+	if len(publics) != len(msgs) || len(sigs) != len(msgs) {
+		return errors.New("bls: different numbers of public keys, messages and signatures")
+	}
+	for _, X := range publics {
+		if !s.isKey(X) {
+			return errKeyGroup
+		}
+	}
 
-		if i == 0 {
-			aggregatedLeft = pair
-		} else {
-			aggregatedLeft.Add(aggregatedLeft, pair)
+	rand := s.suite.RandomStream()
+	coeffs := make([]kyber.Scalar, len(msgs))
+	S := s.sigGroup.Point().Null()
+	for i, sig := range sigs {
+		Si := s.sigGroup.Point()
+		if err := Si.UnmarshalBinary(sig); err != nil {
+			return err
 		}
+		coeffs[i] = s.sigGroup.Scalar().SetBytes(random.Bits(128, false, rand))
+		S.Add(S, Si.Mul(coeffs[i], Si))
 	}
 
-	right := suite.Pair(s, suite.G2().Point().Base())
-	if !aggregatedLeft.Equal(right) {
+	// the last pair is the one of the combined signatures
+	hms, err := s.hashAll(msgs)
+	if err != nil {
+		return err
+	}
+	parallel.ForEach(len(hms), s.concurrency, func(i int) {
+		hms[i].Mul(coeffs[i], hms[i])
+	})
+	sigPoints := append(hms, S.Neg(S))
+	keys := append(append([]kyber.Point{}, publics...), s.keyGroup.Point().Base())
+	if !s.pairProduct(sigPoints, keys).Equal(s.suite.GT().Point().Null()) {
 		return errors.New("bls: invalid signature")
 	}
 	return nil
 }
 
+// hashAll hashes the messages onto the signature group, over the goroutines
+// of WithConcurrency.
+func (s *Scheme) hashAll(msgs [][]byte) ([]kyber.Point, error) {
+	hms := make([]kyber.Point, len(msgs))
+	errs := make([]error, len(msgs))
+	parallel.ForEach(len(msgs), s.concurrency, func(i int) {
+		hms[i], errs[i] = s.hash(msgs[i])
+	})
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return hms, nil
+}
+
+// pairProduct returns the product of the pairings of sigs[i] with keys[i]. If
+// the points of GT implement millerPoint, it computes the Miller loops of the
+// pairings separately and a single final exponentiation of their product.
+func (s *Scheme) pairProduct(sigs, keys []kyber.Point) kyber.Point {
+	_, miller := s.suite.GT().Point().(millerPoint)
+	sigOnG1 := reflect.TypeOf(s.sigGroup.Point()) == reflect.TypeOf(s.suite.G1().Point())
+	pairs := make([]kyber.Point, len(sigs))
+	parallel.ForEach(len(sigs), s.concurrency, func(i int) {
+		switch {
+		case !miller:
+			pairs[i] = s.pair(sigs[i], keys[i])
+		case sigs[i].Equal(s.sigGroup.Point().Null()) || keys[i].Equal(s.keyGroup.Point().Null()):
+			// the Miller loop does not handle the identity, whose
+			// pairing is one anyway
+		case sigOnG1:
+			pairs[i] = s.suite.GT().Point().(millerPoint).Miller(sigs[i], keys[i])
+		default:
+			pairs[i] = s.suite.GT().Point().(millerPoint).Miller(keys[i], sigs[i])
+		}
+	})
+
+	product := s.suite.GT().Point().Null()
+	for _, pair := range pairs {
+		if pair != nil {
+			product.Add(product, pair)
+		}
+	}
+	if miller {
+		return product.(millerPoint).Finalize()
+	}
+	return product
+}
+
 // Verify checks the given BLS signature S on the message m using the public
-// key X by verifying that the equality e(H(m), X) == e(H(m), x*B2) ==
-// e(x*H(m), B2) == e(S, B2) holds where e is the pairing operation and B2 is
-// the base point from curve G2.
-func Verify(suite pairing.Suite, X kyber.Point, msg, sig []byte) error {
-	hashable, ok := suite.G1().Point().(hashablePoint)
-	if !ok {
-		return errors.New("bls: point needs to implement hashablePoint")
+// key X by verifying that the equality e(H(m), X) == e(S, B) holds where e is
+// the pairing operation and B is the base point of the key group.
+func (s *Scheme) Verify(X kyber.Point, msg, sig []byte) error {
+	if !s.isKey(X) {
+		return errKeyGroup
+	}
+	HM, err := s.hash(msg)
+	if err != nil {
+		return err
 	}
-	HM := hashable.Hash(msg)
-	left := suite.Pair(HM, X)
-	s := suite.G1().Point()
-	if err := s.UnmarshalBinary(sig); err != nil {
+	return s.verify(X, HM, sig)
+}
+
+// VerifyReader works like the VerifyReader function with the scheme.
+func (s *Scheme) VerifyReader(X kyber.Point, r io.Reader, sig []byte) error {
+	if !s.isKey(X) {
+		return errKeyGroup
+	}
+	HM, err := s.hashReader(r)
+	if err != nil {
+		return err
+	}
+	return s.verify(X, HM, sig)
+}
+
+func (s *Scheme) verify(X, HM kyber.Point, sig []byte) error {
+	left := s.pair(HM, X)
+	S := s.sigGroup.Point()
+	if err := S.UnmarshalBinary(sig); err != nil {
 		return err
 	}
-	right := suite.Pair(s, suite.G2().Point().Base())
+	right := s.pair(S, s.keyGroup.Point().Base())
 	if !left.Equal(right) {
 		return errors.New("bls: invalid signature")
 	}
 	return nil
 }
 
+// isKey returns true if the point belongs to the key group, so that the
+// pairing does not mix up the groups.
+func (s *Scheme) isKey(X kyber.Point) bool {
+	return reflect.TypeOf(X) == reflect.TypeOf(s.keyGroup.Point())
+}
+
+// hash hashes the message followed by the domain separation tag onto the
+// signature group.
+func (s *Scheme) hash(msg []byte) (kyber.Point, error) {
+	if s.hashToCurve {
+		hasher, ok := s.sigGroup.(kyber.PointHasher)
+		if !ok {
+			return nil, errors.New("bls: group needs to implement kyber.PointHasher")
+		}
+		return hasher.HashToPoint(s.tag, msg)
+	}
+	hashable, ok := s.sigGroup.Point().(hashablePoint)
+	if !ok {
+		return nil, errors.New("bls: point needs to implement hashablePoint")
+	}
+	if s.dst == nil {
+		return hashable.Hash(msg), nil
+	}
+	return hashable.Hash(append(append([]byte{}, msg...), s.dst...)), nil
+}
+
+// hashReader works like hash on the message read from r.
+func (s *Scheme) hashReader(r io.Reader) (kyber.Point, error) {
+	if s.hashToCurve {
+		hasher, ok := s.sigGroup.(readerPointHasher)
+		if !ok {
+			return nil, errors.New("bls: group needs to implement readerPointHasher")
+		}
+		HM, err := hasher.HashReaderToPoint(s.tag, r)
+		if err != nil {
+			return nil, fmt.Errorf("bls: reading message: %w", err)
+		}
+		return HM, nil
+	}
+	hashable, ok := s.sigGroup.Point().(readerHashablePoint)
+	if !ok {
+		return nil, errors.New("bls: point needs to implement readerHashablePoint")
+	}
+	if s.dst != nil {
+		r = io.MultiReader(r, bytes.NewReader(s.dst))
+	}
+	HM, err := hashable.HashReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("bls: reading message: %w", err)
+	}
+	return HM, nil
+}
+
 func distinct(msgs [][]byte) bool {
 	m := make(map[[32]byte]bool)
 	for _, msg := range msgs {
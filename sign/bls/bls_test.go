@@ -1,11 +1,18 @@
 package bls
 
 import (
+	"bytes"
 	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"runtime"
 	"testing"
+	"testing/iotest"
 
 	"github.com/stretchr/testify/require"
 	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
 	"go.dedis.ch/kyber/v3/pairing/bn256"
 	"go.dedis.ch/kyber/v3/util/random"
 )
@@ -148,6 +155,117 @@ func TestBLSFailBatchVerify(t *testing.T) {
 
 }
 
+func TestBLSBatchVerifyConcurrency(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	suite := bn256.NewSuite()
+	for _, scheme := range []*Scheme{NewSchemeOnG1(suite), NewSchemeOnG2(suite)} {
+		var publics []kyber.Point
+		var msgs, sigs [][]byte
+		for i := 0; i < 6; i++ {
+			private, public := scheme.NewKeyPair(random.New())
+			msg := []byte{byte(i)}
+			sig, err := scheme.Sign(private, msg)
+			require.NoError(t, err)
+			publics = append(publics, public)
+			msgs = append(msgs, msg)
+			sigs = append(sigs, sig)
+		}
+		sig, err := scheme.AggregateSignatures(sigs...)
+		require.NoError(t, err)
+		wrongKeys := append([]kyber.Point{publics[1], publics[0]}, publics[2:]...)
+
+		for _, n := range []int{0, 1, 3, 8} {
+			concurrent := scheme.WithConcurrency(n)
+			require.NoError(t, concurrent.BatchVerify(publics, msgs, sig))
+			require.Error(t, concurrent.BatchVerify(wrongKeys, msgs, sig))
+			require.Error(t, concurrent.BatchVerify(nil, nil, sig))
+		}
+	}
+}
+
+func TestBLSVerifyAggregateDistinct(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	suite := bn256.NewSuite()
+	for _, scheme := range []*Scheme{NewSchemeOnG1(suite), NewSchemeOnG2(suite)} {
+		var publics []kyber.Point
+		var msgs, sigs [][]byte
+		for i := 0; i < 5; i++ {
+			private, public := scheme.NewKeyPair(random.New())
+			msg := []byte{byte(i)}
+			sig, err := scheme.Sign(private, msg)
+			require.NoError(t, err)
+			publics = append(publics, public)
+			msgs = append(msgs, msg)
+			sigs = append(sigs, sig)
+		}
+		agg, err := scheme.AggregateSignatures(sigs...)
+		require.NoError(t, err)
+		partial, err := scheme.AggregateSignatures(sigs[1:]...)
+		require.NoError(t, err)
+		duplicate := append([][]byte{msgs[1]}, msgs[1:]...)
+
+		for _, n := range []int{0, 1, 3} {
+			concurrent := scheme.WithConcurrency(n)
+			require.NoError(t, concurrent.VerifyAggregateDistinct(publics, msgs, agg))
+			require.NoError(t, concurrent.VerifyAggregateDistinct(publics[1:], msgs[1:], partial))
+			require.Error(t, concurrent.VerifyAggregateDistinct(publics, msgs, partial))
+			require.Error(t, concurrent.VerifyAggregateDistinct(publics, duplicate, agg))
+			require.Error(t, concurrent.VerifyAggregateDistinct(publics[1:], msgs, agg))
+			require.Error(t, concurrent.VerifyAggregateDistinct(nil, nil, agg))
+		}
+	}
+
+	private, public := NewKeyPair(suite, random.New())
+	msg := []byte("Hello Boneh-Lynn-Shacham")
+	sig, err := Sign(suite, private, msg)
+	require.NoError(t, err)
+	require.NoError(t, VerifyAggregateDistinct(suite, []kyber.Point{public}, [][]byte{msg}, sig))
+	require.Error(t, VerifyAggregateDistinct(suite, []kyber.Point{public}, [][]byte{msg}, sig[1:]))
+}
+
+func TestBLSBatchVerifySignatures(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	suite := bn256.NewSuite()
+	for _, scheme := range []*Scheme{NewSchemeOnG1(suite), NewSchemeOnG2(suite)} {
+		var publics []kyber.Point
+		var msgs, sigs [][]byte
+		for i := 0; i < 6; i++ {
+			private, public := scheme.NewKeyPair(random.New())
+			// the messages need not be distinct
+			msg := []byte{byte(i % 3)}
+			sig, err := scheme.Sign(private, msg)
+			require.NoError(t, err)
+			publics = append(publics, public)
+			msgs = append(msgs, msg)
+			sigs = append(sigs, sig)
+		}
+		// swapping two signatures keeps their sum but not their pairings
+		swapped := append([][]byte{sigs[1], sigs[0]}, sigs[2:]...)
+		wrongMsgs := append([][]byte{[]byte("other")}, msgs[1:]...)
+
+		for _, n := range []int{0, 1, 3, 8} {
+			concurrent := scheme.WithConcurrency(n)
+			require.NoError(t, concurrent.BatchVerifySignatures(publics, msgs, sigs))
+			require.Error(t, concurrent.BatchVerifySignatures(publics, msgs, swapped))
+			require.Error(t, concurrent.BatchVerifySignatures(publics, wrongMsgs, sigs))
+			require.Error(t, concurrent.BatchVerifySignatures(publics[1:], msgs, sigs))
+			require.Error(t, concurrent.BatchVerifySignatures(nil, nil, nil))
+		}
+
+		null, err := scheme.SignatureGroup().Point().Null().MarshalBinary()
+		require.NoError(t, err)
+		require.Error(t, scheme.BatchVerifySignatures(publics, msgs, append([][]byte{null}, sigs[1:]...)))
+		require.Error(t, scheme.BatchVerifySignatures(publics, msgs, append([][]byte{sigs[0][1:]}, sigs[1:]...)))
+	}
+
+	private, public := NewKeyPair(suite, random.New())
+	msg := []byte("Hello Boneh-Lynn-Shacham")
+	sig, err := Sign(suite, private, msg)
+	require.NoError(t, err)
+	require.NoError(t, BatchVerifySignatures(suite, []kyber.Point{public}, [][]byte{msg}, [][]byte{sig}))
+	require.Error(t, BatchVerifySignatures(suite, []kyber.Point{suite.G1().Point()}, [][]byte{msg}, [][]byte{sig}))
+}
+
 func BenchmarkBLSKeyCreation(b *testing.B) {
 	suite := bn256.NewSuite()
 	b.ResetTimer()
@@ -251,3 +369,280 @@ func TestBinaryMarshalAfterAggregation_issue400(t *testing.T) {
 	err = point.UnmarshalBinary(bits)
 	require.Nil(t, err)
 }
+
+func TestBLSSchemes(t *testing.T) {
+	msg := []byte("Hello Boneh-Lynn-Shacham")
+	suite := bn256.NewSuite()
+	for _, scheme := range []*Scheme{NewSchemeOnG1(suite), NewSchemeOnG2(suite)} {
+		private1, public1 := scheme.NewKeyPair(random.New())
+		private2, public2 := scheme.NewKeyPair(random.New())
+		require.True(t, public1.Equal(scheme.KeyGroup().Point().Mul(private1, nil)))
+
+		sig1, err := scheme.Sign(private1, msg)
+		require.NoError(t, err)
+		require.Equal(t, scheme.SignatureGroup().PointLen(), len(sig1))
+		require.NoError(t, scheme.Verify(public1, msg, sig1))
+		require.Error(t, scheme.Verify(public2, msg, sig1))
+		require.Error(t, scheme.Verify(public1, []byte("other"), sig1))
+
+		sig2, err := scheme.Sign(private2, msg)
+		require.NoError(t, err)
+		aggregatedSig, err := scheme.AggregateSignatures(sig1, sig2)
+		require.NoError(t, err)
+		aggregatedKey := scheme.AggregatePublicKeys(public1, public2)
+		require.NoError(t, scheme.Verify(aggregatedKey, msg, aggregatedSig))
+
+		msg2 := []byte("Hello again")
+		sig2, err = scheme.Sign(private2, msg2)
+		require.NoError(t, err)
+		aggregatedSig, err = scheme.AggregateSignatures(sig1, sig2)
+		require.NoError(t, err)
+		require.NoError(t, scheme.BatchVerify([]kyber.Point{public1, public2}, [][]byte{msg, msg2}, aggregatedSig))
+		require.Error(t, scheme.BatchVerify([]kyber.Point{public2, public1}, [][]byte{msg, msg2}, aggregatedSig))
+	}
+}
+
+func TestBLSSchemesCross(t *testing.T) {
+	msg := []byte("Hello Boneh-Lynn-Shacham")
+	suite := bn256.NewSuite()
+	onG1 := NewSchemeOnG1(suite)
+	onG2 := NewSchemeOnG2(suite)
+
+	// same private key in both schemes
+	private, public1 := onG1.NewKeyPair(random.New())
+	public2 := onG2.KeyGroup().Point().Mul(private, nil)
+	sig1, err := onG1.Sign(private, msg)
+	require.NoError(t, err)
+	sig2, err := onG2.Sign(private, msg)
+	require.NoError(t, err)
+	require.Error(t, onG2.Verify(public2, msg, sig1))
+	require.Error(t, onG1.Verify(public1, msg, sig2))
+	// keys of the other scheme are rejected instead of mixing up the groups
+	require.Equal(t, errKeyGroup, onG2.Verify(public1, msg, sig2))
+	require.Equal(t, errKeyGroup, onG1.Verify(public2, msg, sig1))
+
+	// the package-level functions hash without domain separation tag
+	legacySig, err := Sign(suite, private, msg)
+	require.NoError(t, err)
+	require.NotEqual(t, legacySig, sig1)
+	require.Error(t, onG1.Verify(public1, msg, legacySig))
+	require.Error(t, Verify(suite, public1, msg, sig1))
+	sig, err := onG1.WithDST(nil).Sign(private, msg)
+	require.NoError(t, err)
+	require.Equal(t, legacySig, sig)
+
+	// another tag gives other signatures
+	other := onG1.WithDST([]byte("OTHER_DST_"))
+	sig, err = other.Sign(private, msg)
+	require.NoError(t, err)
+	require.NoError(t, other.Verify(public1, msg, sig))
+	require.Error(t, onG1.Verify(public1, msg, sig))
+}
+
+func TestBLSDST(t *testing.T) {
+	msg := []byte("Hello Boneh-Lynn-Shacham")
+	suite := bn256.NewSuite()
+	private, public := NewKeyPair(suite, random.New())
+
+	// tags and messages that would hash the same if the tag were only
+	// prefixed or appended
+	pairs := []struct{ dst, msg []byte }{
+		{nil, msg},
+		{[]byte("PROTOCOL_A_"), msg},
+		{[]byte("PROTOCOL_B_"), msg},
+		{[]byte("PROTOCOL_A"), append([]byte("_"), msg...)},
+		{[]byte("_PROTOCOL_A_"), msg[1:]},
+		{bytes.Repeat([]byte("A"), 300), msg},
+	}
+	sigs := make([][]byte, len(pairs))
+	for i, p := range pairs {
+		sig, err := SignWithDST(suite, private, p.dst, p.msg)
+		require.NoError(t, err)
+		require.NoError(t, VerifyWithDST(suite, public, p.dst, p.msg, sig))
+		sigs[i] = sig
+	}
+	for i, p := range pairs {
+		for j, sig := range sigs {
+			if i != j {
+				require.Error(t, VerifyWithDST(suite, public, p.dst, p.msg, sig))
+			}
+		}
+	}
+	legacySig, err := Sign(suite, private, msg)
+	require.NoError(t, err)
+	require.Equal(t, legacySig, sigs[0])
+
+	// the package-level functions hash with the default tag
+	DefaultDST = []byte("PROTOCOL_A_")
+	defer func() { DefaultDST = nil }()
+	sig, err := Sign(suite, private, msg)
+	require.NoError(t, err)
+	require.Equal(t, sigs[1], sig)
+	require.NoError(t, Verify(suite, public, msg, sigs[1]))
+	require.Error(t, Verify(suite, public, msg, legacySig))
+}
+
+func TestBLSHashToCurve(t *testing.T) {
+	msg := []byte("Hello Boneh-Lynn-Shacham")
+	suite := bn256.NewSuite()
+	for _, c := range []struct {
+		scheme *Scheme
+		dst    string
+	}{
+		{NewSchemeOnG1(suite), "BLS_SIG_BN256G1_XMD:SHA-256_SVDW_RO_NUL_"},
+		{NewSchemeOnG2(suite), "BLS_SIG_BN256G2_XMD:SHA-256_SVDW_RO_NUL_"},
+	} {
+		scheme := c.scheme.WithHashToCurve([]byte(c.dst))
+		private, public := scheme.NewKeyPair(random.New())
+		sig, err := scheme.Sign(private, msg)
+		require.NoError(t, err)
+		require.NoError(t, scheme.Verify(public, msg, sig))
+
+		// the message is hashed with hash_to_curve and the tag
+		HM, err := scheme.SignatureGroup().(kyber.PointHasher).HashToPoint([]byte(c.dst), msg)
+		require.NoError(t, err)
+		expected, err := HM.Clone().Mul(private, HM).MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, expected, sig)
+		HM2, err := scheme.HashMessage(msg)
+		require.NoError(t, err)
+		require.True(t, HM.Equal(HM2))
+
+		// the signatures do not verify with another tag or hashing
+		require.Error(t, c.scheme.WithDST([]byte(c.dst)).Verify(public, msg, sig))
+		require.Error(t, c.scheme.Verify(public, msg, sig))
+		require.Error(t, c.scheme.WithHashToCurve([]byte("other")).Verify(public, msg, sig))
+		require.NoError(t, scheme.VerifyReader(public, bytes.NewReader(msg), sig))
+
+		_, err = c.scheme.WithHashToCurve(nil).Sign(private, msg)
+		require.Error(t, err)
+	}
+}
+
+// failingReader returns err once its data has been read.
+type failingReader struct {
+	r   io.Reader
+	err error
+}
+
+func (f *failingReader) Read(p []byte) (int, error) {
+	n, err := f.r.Read(p)
+	if err == io.EOF {
+		return n, f.err
+	}
+	return n, err
+}
+
+func TestBLSSignReader(t *testing.T) {
+	suite := bn256.NewSuite()
+	// larger than the chunks read at once
+	msg := make([]byte, 100000)
+	_, err := rand.Read(msg)
+	require.NoError(t, err)
+	errRead := errors.New("read failure")
+
+	schemes := []*Scheme{legacy(suite), NewSchemeOnG1(suite), NewSchemeOnG2(suite),
+		NewSchemeOnG1(suite).WithHashToCurve([]byte("BLS_SIG_BN256G1_XMD:SHA-256_SVDW_RO_NUL_")),
+		NewSchemeOnG2(suite).WithHashToCurve([]byte("BLS_SIG_BN256G2_XMD:SHA-256_SVDW_RO_NUL_"))}
+	for _, scheme := range schemes {
+		private, public := scheme.NewKeyPair(random.New())
+		sig, err := scheme.Sign(private, msg)
+		require.NoError(t, err)
+		sigReader, err := scheme.SignReader(private, iotest.HalfReader(bytes.NewReader(msg)))
+		require.NoError(t, err)
+		require.Equal(t, sig, sigReader)
+
+		require.NoError(t, scheme.VerifyReader(public, bytes.NewReader(msg), sig))
+		require.Error(t, scheme.VerifyReader(public, bytes.NewReader(msg[1:]), sig))
+
+		_, err = scheme.SignReader(private, &failingReader{bytes.NewReader(msg), errRead})
+		require.True(t, errors.Is(err, errRead))
+		err = scheme.VerifyReader(public, &failingReader{bytes.NewReader(msg), errRead}, sig)
+		require.True(t, errors.Is(err, errRead))
+	}
+
+	private, public := NewKeyPair(suite, random.New())
+	sig, err := Sign(suite, private, msg)
+	require.NoError(t, err)
+	sigReader, err := SignReader(suite, private, bytes.NewReader(msg))
+	require.NoError(t, err)
+	require.Equal(t, sig, sigReader)
+	require.NoError(t, VerifyReader(suite, public, iotest.OneByteReader(bytes.NewReader(msg[:100])),
+		mustSign(t, suite, private, msg[:100])))
+}
+
+func mustSign(t *testing.T, suite pairing.Suite, private kyber.Scalar, msg []byte) []byte {
+	sig, err := Sign(suite, private, msg)
+	require.NoError(t, err)
+	return sig
+}
+
+func TestBLSGolden(t *testing.T) {
+	// signatures of the default suite must not change with the pluggable hash
+	msg := []byte("Hello Boneh-Lynn-Shacham")
+	suite := bn256.NewSuite()
+	private := suite.G2().Scalar().SetInt64(42)
+
+	sig, err := Sign(suite, private, msg)
+	require.NoError(t, err)
+	require.Equal(t, "3f5a812ee8f9eaf673b81f4fcdb31c8ccd1c221d8e57b16b751f2c74267c0f80"+
+		"078f818d95ac42d1bb384f9fe30558094cbf306f6a350eff030a1a57c0825a9a", hex.EncodeToString(sig))
+
+	sig, err = NewSchemeOnG2(suite).Sign(private, msg)
+	require.NoError(t, err)
+	require.Equal(t, "5ae037f0a3bed1f196b32d912869d060706d762f98190519c96352f74eaa1f80"+
+		"7927f7929a65d7ecd65fb3a2a4ed44c010b195c7871569ba0183100e9f61efc4"+
+		"29266bacc3443766bee7c57764e9218967a5e118fa8fcdfda532a6e2aa5720e6"+
+		"4d5e877085a8c6c5e5ae75eadf0394cbb8c94da5775b5688577dab1e892219b6", hex.EncodeToString(sig))
+}
+
+func TestBLSSuiteHash(t *testing.T) {
+	msg := []byte("Hello Boneh-Lynn-Shacham")
+	suite := bn256.NewSuite()
+	for _, other := range []pairing.Suite{pairing.NewSuiteBn256SHA3(), pairing.NewSuiteBn256BLAKE2b()} {
+		for _, newScheme := range []func(pairing.Suite) *Scheme{NewSchemeOnG1, NewSchemeOnG2} {
+			scheme, defaultScheme := newScheme(other), newScheme(suite)
+			private, public := scheme.NewKeyPair(random.New())
+			sig, err := scheme.Sign(private, msg)
+			require.NoError(t, err)
+			require.NoError(t, scheme.Verify(public, msg, sig))
+			require.Error(t, defaultScheme.Verify(public, msg, sig))
+
+			sig, err = defaultScheme.Sign(private, msg)
+			require.NoError(t, err)
+			require.Error(t, scheme.Verify(public, msg, sig))
+		}
+	}
+}
+
+func BenchmarkBLSBatchVerifySignatures(b *testing.B) {
+	suite := bn256.NewSuite()
+
+	numSigs := 100
+	publics := make([]kyber.Point, numSigs)
+	msgs := make([][]byte, numSigs)
+	sigs := make([][]byte, numSigs)
+	for i := 0; i < numSigs; i++ {
+		private, public := NewKeyPair(suite, random.New())
+		publics[i] = public
+		msg := make([]byte, 64)
+		rand.Read(msg)
+		msgs[i] = msg
+		sig, err := Sign(suite, private, msg)
+		require.Nil(b, err)
+		sigs[i] = sig
+	}
+
+	b.Run("batch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			BatchVerifySignatures(suite, publics, msgs, sigs)
+		}
+	})
+	b.Run("loop", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j := range sigs {
+				Verify(suite, publics[j], msgs[j], sigs[j])
+			}
+		}
+	})
+}
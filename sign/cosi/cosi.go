@@ -71,7 +71,7 @@ func AggregateCommitments(suite Suite, commitments []kyber.Point, masks [][]byte
 	aggMask := make([]byte, len(masks[0]))
 
 	for i := range commitments {
-		aggCom = suite.Point().Add(aggCom, commitments[i])
+		aggCom.Add(aggCom, commitments[i])
 		aggMask, err = AggregateMasks(aggMask, masks[i])
 		if err != nil {
 			return nil, nil, err
@@ -4,7 +4,9 @@
 // https://dl.acm.org/citation.cfm?id=678297
 // To generate a distributed signature from a group of participants, the group
 // must first generate one longterm distributed secret with the share/dkg
-// package, and then one random secret to be used only once.
+// packages (either rabin or pedersen), and then one random secret to be used
+// only once. For an example with the pedersen dkg, please have a look at
+// examples/dkg_dss_test.go.
 // Each participant then creates a DSS struct, that can issue partial signatures
 // with `dss.PartialSignature()`. These partial signatures can be broadcasted to
 // the whole group or to a trusted combiner. Once one has collected enough
@@ -17,6 +19,7 @@ package dss
 import (
 	"bytes"
 	"crypto/sha512"
+	"encoding/binary"
 	"errors"
 
 	"go.dedis.ch/kyber/v3"
@@ -34,7 +37,8 @@ type Suite interface {
 
 // DistKeyShare is an abstraction to allow one to use distributed key share
 // from different schemes easily into this distributed threshold Schnorr
-// signature framework.
+// signature framework. It is implemented by the DistKeyShare of both the
+// share/dkg/rabin and share/dkg/pedersen packages.
 type DistKeyShare interface {
 	PriShare() *share.PriShare
 	Commitments() []kyber.Point
@@ -88,6 +92,14 @@ func NewDSS(suite Suite, secret kyber.Scalar, participants []kyber.Point,
 	if !found {
 		return nil, errors.New("dss: public key not found in list of participants")
 	}
+	longPoly, err := checkDistKeyShare(suite, long, i)
+	if err != nil {
+		return nil, err
+	}
+	randomPoly, err := checkDistKeyShare(suite, random, i)
+	if err != nil {
+		return nil, err
+	}
 	return &DSS{
 		suite:        suite,
 		secret:       secret,
@@ -95,9 +107,9 @@ func NewDSS(suite Suite, secret kyber.Scalar, participants []kyber.Point,
 		index:        i,
 		participants: participants,
 		long:         long,
-		longPoly:     share.NewPubPoly(suite, suite.Point().Base(), long.Commitments()),
+		longPoly:     longPoly,
 		random:       random,
-		randomPoly:   share.NewPubPoly(suite, suite.Point().Base(), random.Commitments()),
+		randomPoly:   randomPoly,
 		msg:          msg,
 		T:            T,
 		partialsIdx:  make(map[int]bool),
@@ -184,7 +196,7 @@ func (d *DSS) EnoughPartialSig() bool {
 // alrogithm.
 func (d *DSS) Signature() ([]byte, error) {
 	if !d.EnoughPartialSig() {
-		return nil, errors.New("dkg: not enough partial signatures to sign")
+		return nil, errors.New("dss: not enough partial signatures to sign")
 	}
 	gamma, err := share.RecoverSecret(d.suite, d.partials, d.T, len(d.participants))
 	if err != nil {
@@ -224,13 +236,74 @@ func (ps *PartialSig) Hash(s Suite) []byte {
 	return h.Sum(nil)
 }
 
+// MarshalBinary encodes the PartialSig as i || v || sid || sig where the
+// 4-byte big-endian value i is the index of the partial signature, v its
+// value, sid the session identifier and sig the Schnorr signature of the
+// issuer.
+func (ps *PartialSig) MarshalBinary() ([]byte, error) {
+	if ps.Partial == nil || ps.Partial.V == nil {
+		return nil, errors.New("dss: empty partial signature")
+	}
+	var buff bytes.Buffer
+	if err := binary.Write(&buff, binary.BigEndian, uint32(ps.Partial.I)); err != nil {
+		return nil, err
+	}
+	if _, err := ps.Partial.V.MarshalTo(&buff); err != nil {
+		return nil, err
+	}
+	_, _ = buff.Write(ps.SessionID)
+	_, _ = buff.Write(ps.Signature)
+	return buff.Bytes(), nil
+}
+
+// UnmarshalPartialSig decodes a PartialSig encoded with MarshalBinary. It
+// returns an error if the encoding has not the expected length for the suite.
+func UnmarshalPartialSig(suite Suite, buff []byte) (*PartialSig, error) {
+	scalarLen := suite.ScalarLen()
+	sidLen := suite.Hash().Size()
+	sigLen := suite.PointLen() + scalarLen
+	if len(buff) != 4+scalarLen+sidLen+sigLen {
+		return nil, errors.New("dss: partial signature of invalid length")
+	}
+	i := binary.BigEndian.Uint32(buff[:4])
+	buff = buff[4:]
+	v := suite.Scalar()
+	if err := v.UnmarshalBinary(buff[:scalarLen]); err != nil {
+		return nil, err
+	}
+	buff = buff[scalarLen:]
+	return &PartialSig{
+		Partial:   &share.PriShare{I: int(i), V: v},
+		SessionID: append([]byte{}, buff[:sidLen]...),
+		Signature: append([]byte{}, buff[sidLen:]...),
+	}, nil
+}
+
 func findPub(list []kyber.Point, i int) (kyber.Point, bool) {
-	if i >= len(list) {
+	if i < 0 || i >= len(list) {
 		return nil, false
 	}
 	return list[i], true
 }
 
+// checkDistKeyShare returns the public polynomial of the distributed key
+// share. It returns an error if the private share is not the one of the
+// participant at index i or does not correspond to the public polynomial.
+func checkDistKeyShare(s Suite, dks DistKeyShare, i int) (*share.PubPoly, error) {
+	pri := dks.PriShare()
+	if pri == nil || len(dks.Commitments()) == 0 {
+		return nil, errors.New("dss: empty distributed key share")
+	}
+	if pri.I != i {
+		return nil, errors.New("dss: share index does not match index in list of participants")
+	}
+	poly := share.NewPubPoly(s, s.Point().Base(), dks.Commitments())
+	if !poly.Check(pri) {
+		return nil, errors.New("dss: share does not match commitments")
+	}
+	return poly, nil
+}
+
 func sessionID(s Suite, a, b DistKeyShare) []byte {
 	h := s.Hash()
 	for _, p := range a.Commitments() {
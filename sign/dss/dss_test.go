@@ -8,6 +8,8 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.dedis.ch/kyber/v3"
 	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/share"
+	pedersen "go.dedis.ch/kyber/v3/share/dkg/pedersen"
 	dkg "go.dedis.ch/kyber/v3/share/dkg/rabin"
 	"go.dedis.ch/kyber/v3/sign/eddsa"
 	"go.dedis.ch/kyber/v3/sign/schnorr"
@@ -46,6 +48,44 @@ func TestDSSNew(t *testing.T) {
 	dss, err = NewDSS(suite, suite.Scalar().Zero(), partPubs, longterms[0], randoms[0], []byte("hello"), 4)
 	assert.Nil(t, dss)
 	assert.Error(t, err)
+
+	// share of another participant
+	dss, err = NewDSS(suite, partSec[0], partPubs, longterms[1], randoms[0], []byte("hello"), 4)
+	assert.Nil(t, dss)
+	assert.Error(t, err)
+
+	// share not matching the commitments
+	wrong := &pedersen.DistKeyShare{
+		Commits: longterms[0].Commitments(),
+		Share:   &share.PriShare{I: 0, V: suite.Scalar().Pick(suite.RandomStream())},
+	}
+	dss, err = NewDSS(suite, partSec[0], partPubs, wrong, randoms[0], []byte("hello"), 4)
+	assert.Nil(t, dss)
+	assert.Error(t, err)
+}
+
+func TestDSSPartialSigEncoding(t *testing.T) {
+	dss0 := getDSS(0)
+	ps, err := dss0.PartialSig()
+	require.NoError(t, err)
+	buff, err := ps.MarshalBinary()
+	require.NoError(t, err)
+	ps2, err := UnmarshalPartialSig(suite, buff)
+	require.NoError(t, err)
+	require.Equal(t, ps.Partial.I, ps2.Partial.I)
+	require.True(t, ps.Partial.V.Equal(ps2.Partial.V))
+	require.Equal(t, ps.SessionID, ps2.SessionID)
+	require.Equal(t, ps.Signature, ps2.Signature)
+	require.NoError(t, getDSS(1).ProcessPartialSig(ps2))
+
+	_, err = UnmarshalPartialSig(suite, buff[1:])
+	require.Error(t, err)
+	_, err = (&PartialSig{}).MarshalBinary()
+	require.Error(t, err)
+
+	// out of bounds index
+	ps2.Partial.I = -1
+	require.Error(t, getDSS(1).ProcessPartialSig(ps2))
 }
 
 func TestDSSPartialSigs(t *testing.T) {
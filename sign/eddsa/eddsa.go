@@ -10,6 +10,9 @@ import (
 
 	"go.dedis.ch/kyber/v3"
 	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/util/msm"
+	"go.dedis.ch/kyber/v3/util/random"
+	"go.dedis.ch/kyber/v3/util/wipe"
 )
 
 var group = new(edwards25519.Curve)
@@ -79,18 +82,27 @@ func (e *EdDSA) Sign(msg []byte) ([]byte, error) {
 	_, _ = hash.Write(e.prefix)
 	_, _ = hash.Write(msg)
 
-	// deterministic random secret and its commit
-	r := group.Scalar().SetBytes(hash.Sum(nil))
+	// deterministic random secret
+	digest := hash.Sum(nil)
+	r := group.Scalar().SetBytes(digest)
+	wipe.Bytes(digest)
+	return sign(e.Secret, e.Public, r, msg)
+}
+
+// sign returns the signature of msg with the secret and the random secret r,
+// which it erases since it reveals the secret together with the signature.
+func sign(secret kyber.Scalar, public kyber.Point, r kyber.Scalar, msg []byte) ([]byte, error) {
+	// commit of the random secret
 	R := group.Point().Mul(r, nil)
 
 	// challenge
 	// H( R || Public || Msg)
-	hash.Reset()
+	hash := sha512.New()
 	Rbuff, err := R.MarshalBinary()
 	if err != nil {
 		return nil, err
 	}
-	Abuff, err := e.Public.MarshalBinary()
+	Abuff, err := public.MarshalBinary()
 	if err != nil {
 		return nil, err
 	}
@@ -103,8 +115,9 @@ func (e *EdDSA) Sign(msg []byte) ([]byte, error) {
 
 	// response
 	// s = r + h * s
-	s := group.Scalar().Mul(e.Secret, h)
+	s := group.Scalar().Mul(secret, h)
 	s.Add(r, s)
+	wipe.Scalar(r)
 
 	sBuff, err := s.MarshalBinary()
 	if err != nil {
@@ -124,9 +137,86 @@ func (e *EdDSA) Sign(msg []byte) ([]byte, error) {
 // key public, or an error otherwise. Compared to `Verify`, it performs
 // additional checks around the canonicality and ensures the public key
 // does not have a small order.
+//
+// It rejects the non-canonical encodings of R and of the public key, S not
+// lower than the group order, and R and public keys having a small order or
+// a small-order component, i.e. outside of the prime-order subgroup, see
+// ValidatePublicKey. It then checks the cofactorless equation [S]B = R + [h]A
+// of RFC 8032. As R and A are in the prime-order subgroup, the cofactored
+// equation [8][S]B = [8]R + [8][h]A accepts exactly the same signatures, so
+// that a batch verification, which is cofactored, agrees with it.
 func VerifyWithChecks(pub, msg, sig []byte) error {
+	R, s, public, h, err := decodeSignature(pub, msg, sig)
+	if err != nil {
+		return err
+	}
+	// reconstruct R == s*B - h*A using a variable time double-base
+	// multiplication since only public values are involved
+	vt := group.Point().(kyber.VartimeMulAdder)
+	Rp := vt.MulAddVartime(group.Scalar().Neg(h), public, s)
+
+	if !Rp.Equal(R) {
+		return errors.New("reconstructed S is not equal to signature")
+	}
+	return nil
+}
+
+// BatchVerify verifies many Ed25519 signatures at once, where sigs[i] is the
+// signature of msgs[i] under publics[i], faster than calling Verify for each
+// of them. With random coefficients zi of 128 bits, it checks the cofactored
+// batch equation of Ed25519
+//
+//	[8](-(Σ zi*Si)*B + Σ zi*Ri + Σ (zi*hi)*Ai) = 0
+//
+// with a single multi-scalar multiplication, after the checks of
+// VerifyWithChecks on each signature, so that it accepts exactly the batches
+// of signatures that Verify accepts. It returns an error if any signature is
+// invalid, without telling which one, so that the caller must verify the
+// signatures one by one to find the invalid ones.
+func BatchVerify(publics []kyber.Point, msgs, sigs [][]byte) error {
+	if len(msgs) == 0 {
+		return errors.New("eddsa: no signature to verify")
+	}
+	if len(publics) != len(msgs) || len(sigs) != len(msgs) {
+		return errors.New("eddsa: different numbers of public keys, messages and signatures")
+	}
+	points := make([]kyber.Point, 0, 2*len(msgs)+1)
+	scalars := make([]kyber.Scalar, 0, 2*len(msgs)+1)
+	sum := group.Scalar().Zero()
+	rand := random.New()
+	for i := range msgs {
+		pub, err := publics[i].MarshalBinary()
+		if err != nil {
+			return err
+		}
+		R, s, public, h, err := decodeSignature(pub, msgs[i], sigs[i])
+		if err != nil {
+			return err
+		}
+		z := group.Scalar().SetBytes(random.Bits(128, false, rand))
+		sum.Add(sum, s.Mul(z, s))
+		points = append(points, R, public)
+		scalars = append(scalars, z, h.Mul(z, h))
+	}
+	points = append(points, group.Point().Base())
+	scalars = append(scalars, sum.Neg(sum))
+	res, err := msm.MultiScalarMult(group, points, scalars)
+	if err != nil {
+		return err
+	}
+	if !res.Mul(group.Scalar().SetInt64(8), res).Equal(group.Point().Null()) {
+		return errors.New("eddsa: invalid signature")
+	}
+	return nil
+}
+
+// decodeSignature decodes the signature R || S and the public key with the
+// checks of VerifyWithChecks, and returns them with the hash h of R, the
+// public key and the message, so that the signature is valid iff
+// S*B = R + h*A.
+func decodeSignature(pub, msg, sig []byte) (R kyber.Point, s kyber.Scalar, public kyber.Point, h kyber.Scalar, err error) {
 	if len(sig) != 64 {
-		return fmt.Errorf("signature length invalid, expect 64 but got %v", len(sig))
+		return nil, nil, nil, nil, fmt.Errorf("signature length invalid, expect 64 but got %v", len(sig))
 	}
 
 	type scalarCanCheckCanonical interface {
@@ -134,39 +224,28 @@ func VerifyWithChecks(pub, msg, sig []byte) error {
 	}
 
 	if !group.Scalar().(scalarCanCheckCanonical).IsCanonical(sig[32:]) {
-		return fmt.Errorf("signature is not canonical")
+		return nil, nil, nil, nil, fmt.Errorf("signature is not canonical")
 	}
 
-	type pointCanCheckCanonicalAndSmallOrder interface {
-		HasSmallOrder() bool
-		IsCanonical(b []byte) bool
-	}
-
-	R := group.Point()
+	R = group.Point()
 	if !R.(pointCanCheckCanonicalAndSmallOrder).IsCanonical(sig[:32]) {
-		return fmt.Errorf("R is not canonical")
+		return nil, nil, nil, nil, fmt.Errorf("R is not canonical")
 	}
 	if err := R.UnmarshalBinary(sig[:32]); err != nil {
-		return fmt.Errorf("got R invalid point: %s", err)
+		return nil, nil, nil, nil, fmt.Errorf("got R invalid point: %s", err)
 	}
 	if R.(pointCanCheckCanonicalAndSmallOrder).HasSmallOrder() {
-		return fmt.Errorf("R has small order")
+		return nil, nil, nil, nil, fmt.Errorf("R has small order")
 	}
 
-	s := group.Scalar()
+	s = group.Scalar()
 	if err := s.UnmarshalBinary(sig[32:]); err != nil {
-		return fmt.Errorf("schnorr: s invalid scalar %s", err)
+		return nil, nil, nil, nil, fmt.Errorf("schnorr: s invalid scalar %s", err)
 	}
 
-	public := group.Point()
-	if !public.(pointCanCheckCanonicalAndSmallOrder).IsCanonical(pub) {
-		return fmt.Errorf("public key is not canonical")
-	}
-	if err := public.UnmarshalBinary(pub); err != nil {
-		return fmt.Errorf("invalid public key: %s", err)
-	}
-	if public.(pointCanCheckCanonicalAndSmallOrder).HasSmallOrder() {
-		return fmt.Errorf("public key has small order")
+	public, err = decodePublicKey(pub)
+	if err != nil {
+		return nil, nil, nil, nil, err
 	}
 
 	// reconstruct h = H(R || Public || Msg)
@@ -175,16 +254,40 @@ func VerifyWithChecks(pub, msg, sig []byte) error {
 	_, _ = hash.Write(pub)
 	_, _ = hash.Write(msg)
 
-	h := group.Scalar().SetBytes(hash.Sum(nil))
-	// reconstruct S == k*A + R
-	S := group.Point().Mul(s, nil)
-	hA := group.Point().Mul(h, public)
-	RhA := group.Point().Add(R, hA)
+	h = group.Scalar().SetBytes(hash.Sum(nil))
+	return R, s, public, h, nil
+}
 
-	if !RhA.Equal(S) {
-		return errors.New("reconstructed S is not equal to signature")
+// ValidatePublicKey returns nil if pub is a public key that VerifyWithChecks
+// accepts, the canonical encoding of a point of the prime-order subgroup
+// other than the identity. Otherwise, it returns the error VerifyWithChecks
+// would return, so that the keys can be vetted once, e.g. when they are
+// registered, instead of failing every verification.
+func ValidatePublicKey(pub []byte) error {
+	_, err := decodePublicKey(pub)
+	return err
+}
+
+type pointCanCheckCanonicalAndSmallOrder interface {
+	HasSmallOrder() bool
+	IsCanonical(b []byte) bool
+}
+
+// decodePublicKey returns the point of the public key pub, checked as
+// documented by ValidatePublicKey. The decoding of the points rejects the
+// ones with a small-order component, and the small-order check the identity.
+func decodePublicKey(pub []byte) (kyber.Point, error) {
+	public := group.Point()
+	if !public.(pointCanCheckCanonicalAndSmallOrder).IsCanonical(pub) {
+		return nil, fmt.Errorf("public key is not canonical")
 	}
-	return nil
+	if err := public.UnmarshalBinary(pub); err != nil {
+		return nil, fmt.Errorf("invalid public key: %s", err)
+	}
+	if public.(pointCanCheckCanonicalAndSmallOrder).HasSmallOrder() {
+		return nil, fmt.Errorf("public key has small order")
+	}
+	return public, nil
 }
 
 // Verify uses a public key, a message and a signature. It will return nil if
@@ -196,3 +299,33 @@ func Verify(public kyber.Point, msg, sig []byte) error {
 	}
 	return VerifyWithChecks(PBuf, msg, sig)
 }
+
+// Scheme is the Ed25519 signature scheme working on the private keys as
+// scalars instead of the seeds of RFC8032, so that it can be used like the
+// other signature schemes. As the prefix derived from the seed is not known,
+// the signatures take a random nonce instead of a deterministic one, but they
+// are regular Ed25519 signatures verified by Verify.
+type Scheme struct{}
+
+// NewScheme returns the Ed25519 signature scheme.
+func NewScheme() *Scheme {
+	return &Scheme{}
+}
+
+// NewKeyPair returns a new private key, formatted like the secret of NewEdDSA,
+// and its public key.
+func (s *Scheme) NewKeyPair(random cipher.Stream) (kyber.Scalar, kyber.Point) {
+	private, _, _ := group.NewKeyAndSeed(random)
+	return private, group.Point().Mul(private, nil)
+}
+
+// Sign returns an Ed25519 signature of the message with the private key.
+func (s *Scheme) Sign(private kyber.Scalar, msg []byte) ([]byte, error) {
+	r := group.Scalar().Pick(random.New())
+	return sign(private, group.Point().Mul(private, nil), r, msg)
+}
+
+// Verify works like the Verify function.
+func (s *Scheme) Verify(public kyber.Point, msg, sig []byte) error {
+	return Verify(public, msg, sig)
+}
@@ -6,11 +6,13 @@ import (
 	"compress/gzip"
 	"crypto/cipher"
 	"encoding/hex"
+	"fmt"
 	"math/rand"
 	"os"
 	"strings"
 	"testing"
 
+	"go.dedis.ch/kyber/v3"
 	"go.dedis.ch/kyber/v3/group/edwards25519"
 	"go.dedis.ch/kyber/v3/util/random"
 
@@ -214,8 +216,9 @@ func TestEdDSAVerifySmallOrderR(t *testing.T) {
 		sig[i] = smallOrderR[i]
 	}
 
+	// small order points are rejected when decoding R
 	err = Verify(ed.Public, msg, sig)
-	require.EqualError(t, err, "R has small order")
+	require.EqualError(t, err, "got R invalid point: Ed25519 curve point not in the prime-order subgroup")
 }
 
 // Test for small order public key
@@ -234,11 +237,99 @@ func TestEdDSAVerifySmallOrderPK(t *testing.T) {
 	require.Nil(t, err)
 	require.Nil(t, Verify(ed.Public, msg, sig))
 
-	err = ed.Public.UnmarshalBinary(smallOrderPk)
-	require.Nil(t, err)
+	// small order points are rejected when decoding the public key
+	err = VerifyWithChecks(smallOrderPk, msg, sig)
+	require.EqualError(t, err, "invalid public key: Ed25519 curve point not in the prime-order subgroup")
+}
 
-	err = Verify(ed.Public, msg, sig)
-	require.EqualError(t, err, "public key has small order")
+// smallOrderPoints are the canonical encodings of the eight points of small
+// order of edwards25519, the multiples of a point of order 8.
+var smallOrderPoints = []string{
+	"0100000000000000000000000000000000000000000000000000000000000000",
+	"c7176a703d4dd84fba3c0b760d10670f2a2053fa2c39ccc64ec7fd7792ac037a",
+	"0000000000000000000000000000000000000000000000000000000000000080",
+	"26e8958fc2b227b045c3f489f2ef98f0d5dfac05d3c63339b13802886d53fc05",
+	"ecffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f",
+	"26e8958fc2b227b045c3f489f2ef98f0d5dfac05d3c63339b13802886d53fc85",
+	"0000000000000000000000000000000000000000000000000000000000000000",
+	"c7176a703d4dd84fba3c0b760d10670f2a2053fa2c39ccc64ec7fd7792ac03fa",
+}
+
+func TestEdDSAValidatePublicKey(t *testing.T) {
+	for _, vec := range EdDSATestVectors {
+		pub, _ := hex.DecodeString(vec.public)
+		require.NoError(t, ValidatePublicKey(pub))
+	}
+
+	msg := []byte("any message")
+	for _, p := range smallOrderPoints {
+		pub, _ := hex.DecodeString(p)
+		require.Error(t, ValidatePublicKey(pub), p)
+
+		// with the identity as key A, R = [S]B satisfies [S]B = R + [h]A for
+		// any message, and the other small-order keys for some messages
+		s := group.Scalar().Pick(random.New())
+		R, _ := group.Point().Mul(s, nil).MarshalBinary()
+		S, _ := s.MarshalBinary()
+		require.Error(t, VerifyWithChecks(pub, msg, append(R, S...)), p)
+	}
+
+	// the public key of the first vector of RFC8032 plus a point of order 8
+	mixed, _ := hex.DecodeString("9158312a9a8d6e3b34c891d6d61444f8b8211c5117ebad15bdb0bd68b07e0245")
+	require.EqualError(t, ValidatePublicKey(mixed),
+		"invalid public key: Ed25519 curve point not in the prime-order subgroup")
+	require.Error(t, ValidatePublicKey(nil))
+	require.EqualError(t, ValidatePublicKey(bytes.Repeat([]byte{0xff}, 32)), "public key is not canonical")
+}
+
+// Test S equal to the group order and S with its top bits set
+func TestEdDSAVerifyNonCanonicalS(t *testing.T) {
+	L, _ := hex.DecodeString("edd3f55c1a631258d69cf7a2def9de1400000000000000000000000000000010")
+	ed := NewEdDSA(random.New())
+	msg := []byte("any message")
+	sig, err := ed.Sign(msg)
+	require.NoError(t, err)
+	pub, _ := ed.Public.MarshalBinary()
+	require.NoError(t, VerifyWithChecks(pub, msg, sig))
+
+	for _, S := range [][]byte{L, bytes.Repeat([]byte{0xff}, 32)} {
+		forged := append(append([]byte{}, sig[:32]...), S...)
+		require.EqualError(t, VerifyWithChecks(pub, msg, forged), "signature is not canonical")
+	}
+}
+
+func TestEdDSABatchVerify(t *testing.T) {
+	n := 40
+	publics := make([]kyber.Point, n)
+	msgs := make([][]byte, n)
+	sigs := make([][]byte, n)
+	for i := range msgs {
+		ed := NewEdDSA(random.New())
+		publics[i] = ed.Public
+		msgs[i] = []byte(fmt.Sprintf("message %d", i))
+		var err error
+		sigs[i], err = ed.Sign(msgs[i])
+		require.NoError(t, err)
+	}
+	require.NoError(t, BatchVerify(publics, msgs, sigs))
+	require.NoError(t, BatchVerify(publics[:1], msgs[:1], sigs[:1]))
+
+	// a signature of another message, or under another key
+	bad := append([][]byte{}, sigs...)
+	bad[7] = sigs[8]
+	require.Error(t, BatchVerify(publics, msgs, bad))
+	badKeys := append([]kyber.Point{}, publics...)
+	badKeys[3], badKeys[4] = publics[4], publics[3]
+	require.Error(t, BatchVerify(badKeys, msgs, sigs))
+
+	// the checks of VerifyWithChecks apply to each signature
+	L, _ := hex.DecodeString("edd3f55c1a631258d69cf7a2def9de1400000000000000000000000000000010")
+	bad = append([][]byte{}, sigs...)
+	bad[0] = append(append([]byte{}, sigs[0][:32]...), L...)
+	require.EqualError(t, BatchVerify(publics, msgs, bad), "signature is not canonical")
+
+	require.Error(t, BatchVerify(publics[1:], msgs, sigs))
+	require.Error(t, BatchVerify(nil, nil, nil))
 }
 
 // Test the property of a EdDSA signature
@@ -344,3 +435,40 @@ func TestGolden(t *testing.T) {
 		t.Fatalf("error reading test data: %s", err)
 	}
 }
+
+func BenchmarkEdDSAVerify(b *testing.B) {
+	msg := []byte("Hello EdDSA")
+	ed := NewEdDSA(random.New())
+	sig, err := ed.Sign(msg)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := Verify(ed.Public, msg, sig); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEdDSABatchVerify(b *testing.B) {
+	n := 64
+	publics := make([]kyber.Point, n)
+	msgs := make([][]byte, n)
+	sigs := make([][]byte, n)
+	for i := range msgs {
+		ed := NewEdDSA(random.New())
+		publics[i] = ed.Public
+		msgs[i] = []byte("Hello EdDSA")
+		var err error
+		if sigs[i], err = ed.Sign(msgs[i]); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := BatchVerify(publics, msgs, sigs); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,356 @@
+/*
+Package frost implements t-of-n threshold Schnorr signatures following the
+two-round FROST protocol of RFC 9591, "The Flexible Round-Optimized Schnorr
+Threshold (FROST) Protocol for Two-Round Schnorr Signatures".
+
+The signers hold shares of a distributed secret key, e.g. the share.PriShare
+of a DistKeyShare output by the share/dkg packages, and the public polynomial
+of the key. The share at index i is the evaluation at i+1, which FROST calls
+the identifier of the signer. At least t signers run the protocol in two
+rounds:
+
+1. Every signer creates a Signer and broadcasts the Commitment returned by
+Commit, i.e. the points D_i and E_i of its hiding and binding nonces d_i and
+e_i. The commitments do not depend on the message and can be exchanged in
+advance.
+
+2. Once the commitments of the signers are known, every signer computes its
+partial signature with Sign. Each signer gets a binding factor
+rho_i = H1(Y || H4(M) || H5(commitments) || i), the nonces are combined into
+R = \sum{D_i + rho_i*E_i}, and the partial signature is
+z_i = d_i + rho_i*e_i + lambda_i*c*s_i with c = H2(R || Y || M) and lambda_i
+the Lagrange coefficient of the signer among the signing set.
+
+Anyone holding the commitments, e.g. a coordinator, can then create a Session
+to verify the partial signatures and aggregate them into the signature
+R || \sum{z_i}. It is a regular Schnorr signature that schnorr.Verify accepts
+for the distributed public key Y and, when using the edwards25519 group, a
+valid Ed25519 signature.
+
+The hash functions H1, H3, H4 and H5 are the ones of the ciphersuite
+FROST(Ed25519, SHA-512) of the RFC for every group, and H2 is the challenge of
+the schnorr package, so that on edwards25519 the protocol is this ciphersuite.
+A Signer erases its nonces when creating a partial signature, so that signing
+again requires a new Commit: a nonce can never be used for two messages.
+*/
+package frost
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"errors"
+	"sort"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/share"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+// Suite represents the set of functionalities needed by the package frost.
+type Suite interface {
+	kyber.Group
+	kyber.Random
+}
+
+// contextString is the context string of FROST(Ed25519, SHA-512), prefixed to
+// the inputs of H1, H3, H4 and H5.
+const contextString = "FROST-ED25519-SHA512-v1"
+
+var errInvalidShare = errors.New("frost: share does not match the public polynomial")
+var errNoNonces = errors.New("frost: no fresh nonces, call Commit before signing")
+var errCommitments = errors.New("frost: invalid list of commitments")
+var errTooFewSigners = errors.New("frost: fewer commitments than the threshold")
+var errOwnCommitment = errors.New("frost: own commitment does not match")
+var errInvalidPartial = errors.New("frost: invalid partial signature")
+var errIndex = errors.New("frost: index not in the signing set")
+
+// Commitment holds the public nonces of the signer holding the share at
+// Index.
+type Commitment struct {
+	Index   int
+	Hiding  kyber.Point
+	Binding kyber.Point
+}
+
+// PartialSig is the partial signature of the signer holding the share at
+// Index.
+type PartialSig struct {
+	Index int
+	Z     kyber.Scalar
+}
+
+// Session holds the public state of one signing session, i.e. the signing
+// set, the binding factors and the group commitment of a message, from which
+// the partial signatures are verified and aggregated.
+type Session struct {
+	suite   Suite
+	public  *share.PubPoly
+	commits map[int]*Commitment
+	rhos    map[int]kyber.Scalar // binding factors
+	lambdas map[int]kyber.Scalar // Lagrange coefficients
+	msg     []byte
+	R       kyber.Point  // group commitment
+	c       kyber.Scalar // Schnorr challenge
+}
+
+// NewSession returns the session of the given message for the signers whose
+// commitments are given, with the public polynomial of the distributed key.
+// There must be at least as many commitments as the threshold of the
+// polynomial, and one commitment per signer.
+func NewSession(suite Suite, public *share.PubPoly, commits []*Commitment, msg []byte) (*Session, error) {
+	if len(commits) < public.Threshold() {
+		return nil, errTooFewSigners
+	}
+	byIndex := make(map[int]*Commitment, len(commits))
+	indices := make([]int, 0, len(commits))
+	for _, c := range commits {
+		if c == nil || c.Index < 0 || c.Hiding == nil || c.Binding == nil || byIndex[c.Index] != nil {
+			return nil, errCommitments
+		}
+		byIndex[c.Index] = c
+		indices = append(indices, c.Index)
+	}
+	sort.Ints(indices)
+
+	// rho_i = H1(Y || H4(M) || H5(commitments) || i), the commitments being
+	// encoded in increasing order of identifier
+	var prefix bytes.Buffer
+	if _, err := public.Commit().MarshalTo(&prefix); err != nil {
+		return nil, err
+	}
+	prefix.Write(hash("msg", msg))
+	var encoded bytes.Buffer
+	for _, i := range indices {
+		c := byIndex[i]
+		for _, m := range []kyber.Marshaling{identifier(suite, i), c.Hiding, c.Binding} {
+			if _, err := m.MarshalTo(&encoded); err != nil {
+				return nil, err
+			}
+		}
+	}
+	prefix.Write(hash("com", encoded.Bytes()))
+
+	rhos := make(map[int]kyber.Scalar, len(indices))
+	lambdas := make(map[int]kyber.Scalar, len(indices))
+	R := suite.Point().Null()
+	tmp := suite.Point()
+	for _, i := range indices {
+		id, err := identifier(suite, i).MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		input := append(append([]byte{}, prefix.Bytes()...), id...)
+		rhos[i] = suite.Scalar().SetBytes(hash("rho", input))
+		lambdas[i] = lagrange(suite, i, indices)
+		R.Add(R, byIndex[i].Hiding)
+		R.Add(R, tmp.Mul(rhos[i], byIndex[i].Binding))
+	}
+	c, err := challenge(suite, R, public.Commit(), msg)
+	if err != nil {
+		return nil, err
+	}
+	return &Session{
+		suite:   suite,
+		public:  public,
+		commits: byIndex,
+		rhos:    rhos,
+		lambdas: lambdas,
+		msg:     msg,
+		R:       R,
+		c:       c,
+	}, nil
+}
+
+// PublicKey returns the distributed public key the signature is verified
+// against.
+func (s *Session) PublicKey() kyber.Point {
+	return s.public.Commit()
+}
+
+// VerifyPartial checks that z_i*G == D_i + rho_i*E_i + lambda_i*c*Y_i for the
+// given partial signature, Y_i being the public share of the signer, so that
+// the coordinator can identify a misbehaving signer.
+func (s *Session) VerifyPartial(p *PartialSig) error {
+	if p == nil || p.Z == nil {
+		return errInvalidPartial
+	}
+	commit, ok := s.commits[p.Index]
+	if !ok {
+		return errIndex
+	}
+	exp := s.suite.Point().Mul(s.rhos[p.Index], commit.Binding)
+	exp.Add(exp, commit.Hiding)
+	lc := s.suite.Scalar().Mul(s.lambdas[p.Index], s.c)
+	exp.Add(exp, s.suite.Point().Mul(lc, s.public.Eval(p.Index).V))
+	if !s.suite.Point().Mul(p.Z, nil).Equal(exp) {
+		return errInvalidPartial
+	}
+	return nil
+}
+
+// Aggregate verifies the partial signatures of all the signers of the session
+// and returns the final signature R || z, which can be verified by
+// schnorr.Verify with the distributed public key.
+func (s *Session) Aggregate(partials []*PartialSig) ([]byte, error) {
+	if len(partials) != len(s.commits) {
+		return nil, errInvalidPartial
+	}
+	seen := make(map[int]bool, len(partials))
+	z := s.suite.Scalar().Zero()
+	for _, p := range partials {
+		if err := s.VerifyPartial(p); err != nil {
+			return nil, err
+		}
+		if seen[p.Index] {
+			return nil, errInvalidPartial
+		}
+		seen[p.Index] = true
+		z.Add(z, p.Z)
+	}
+
+	var buf bytes.Buffer
+	if _, err := s.R.MarshalTo(&buf); err != nil {
+		return nil, err
+	}
+	if _, err := z.MarshalTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Signer holds the private state of one signer. It must not be shared
+// between concurrent signing sessions.
+type Signer struct {
+	suite  Suite
+	share  *share.PriShare
+	public *share.PubPoly
+	d, e   kyber.Scalar // secret nonces, nil once used
+	commit *Commitment
+}
+
+// NewSigner returns the signer holding the given share of the distributed key
+// of the public polynomial. It returns an error if the share does not match
+// the polynomial.
+func NewSigner(suite Suite, priShare *share.PriShare, public *share.PubPoly) (*Signer, error) {
+	if priShare == nil || priShare.I < 0 || !public.Check(priShare) {
+		return nil, errInvalidShare
+	}
+	return &Signer{
+		suite:  suite,
+		share:  priShare,
+		public: public,
+	}, nil
+}
+
+// Index returns the index of the share of the signer.
+func (s *Signer) Index() int {
+	return s.share.I
+}
+
+// PublicKey returns the distributed public key.
+func (s *Signer) PublicKey() kyber.Point {
+	return s.public.Commit()
+}
+
+// Commit generates fresh nonces, replacing any unused ones, and returns the
+// corresponding commitment to broadcast to the other signers. The nonces are
+// derived from fresh randomness and the share, as nonce_generate of the RFC
+// does, so that a weak random source alone does not reveal them.
+func (s *Signer) Commit() *Commitment {
+	s.d = s.nonce()
+	s.e = s.nonce()
+	s.commit = &Commitment{
+		Index:   s.share.I,
+		Hiding:  s.suite.Point().Mul(s.d, nil),
+		Binding: s.suite.Point().Mul(s.e, nil),
+	}
+	return s.commit
+}
+
+// nonce returns H3(random || share).
+func (s *Signer) nonce() kyber.Scalar {
+	input := make([]byte, 32)
+	random.Bytes(input, s.suite.RandomStream())
+	secret, err := s.share.V.MarshalBinary()
+	if err != nil {
+		panic("frost: cannot encode the share: " + err.Error())
+	}
+	return s.suite.Scalar().SetBytes(hash("nonce", append(input, secret...)))
+}
+
+// Sign returns the partial signature of the message given the commitments of
+// the signing set, including its own one from the last call to Commit. The
+// nonces are erased, so that Sign returns an error until Commit is called
+// again.
+func (s *Signer) Sign(commits []*Commitment, msg []byte) (*PartialSig, error) {
+	if s.d == nil || s.e == nil {
+		return nil, errNoNonces
+	}
+	session, err := NewSession(s.suite, s.public, commits, msg)
+	if err != nil {
+		return nil, err
+	}
+	own, ok := session.commits[s.share.I]
+	if !ok || !own.Hiding.Equal(s.commit.Hiding) || !own.Binding.Equal(s.commit.Binding) {
+		return nil, errOwnCommitment
+	}
+	d, e := s.d, s.e
+	s.d, s.e, s.commit = nil, nil, nil
+
+	// z_i = d_i + rho_i*e_i + lambda_i*c*s_i
+	z := s.suite.Scalar().Mul(session.lambdas[s.share.I], session.c)
+	z.Mul(z, s.share.V)
+	z.Add(z, d)
+	z.Add(z, e.Mul(e, session.rhos[s.share.I]))
+	d.Zero()
+	e.Zero()
+	return &PartialSig{Index: s.share.I, Z: z}, nil
+}
+
+// identifier returns the identifier i+1 of the share at index i.
+func identifier(g kyber.Group, i int) kyber.Scalar {
+	return g.Scalar().SetInt64(int64(i) + 1)
+}
+
+// lagrange returns the Lagrange coefficient at zero of the share at index i
+// among the shares at the given indices.
+func lagrange(g kyber.Group, i int, indices []int) kyber.Scalar {
+	num := g.Scalar().One()
+	den := g.Scalar().One()
+	xi := identifier(g, i)
+	for _, j := range indices {
+		if j == i {
+			continue
+		}
+		xj := identifier(g, j)
+		num.Mul(num, xj)
+		den.Mul(den, g.Scalar().Sub(xj, xi))
+	}
+	return num.Div(num, den)
+}
+
+// hash returns the SHA-512 hash of the context string, the tag and the input,
+// i.e. the functions H1 ("rho"), H3 ("nonce"), H4 ("msg") and H5 ("com") of
+// the RFC.
+func hash(tag string, input []byte) []byte {
+	h := sha512.New()
+	_, _ = h.Write([]byte(contextString))
+	_, _ = h.Write([]byte(tag))
+	_, _ = h.Write(input)
+	return h.Sum(nil)
+}
+
+// challenge returns H2(R || Y || M) computed as in the schnorr package.
+func challenge(g kyber.Group, R, public kyber.Point, msg []byte) (kyber.Scalar, error) {
+	h := sha512.New()
+	if _, err := R.MarshalTo(h); err != nil {
+		return nil, err
+	}
+	if _, err := public.MarshalTo(h); err != nil {
+		return nil, err
+	}
+	if _, err := h.Write(msg); err != nil {
+		return nil, err
+	}
+	return g.Scalar().SetBytes(h.Sum(nil)), nil
+}
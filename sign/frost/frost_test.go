@@ -0,0 +1,151 @@
+package frost
+
+import (
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/group/nist"
+	"go.dedis.ch/kyber/v3/share"
+	"go.dedis.ch/kyber/v3/sign/schnorr"
+)
+
+var testSuite = edwards25519.NewBlakeSHA256Ed25519()
+
+func newSigners(t *testing.T, suite Suite, n, threshold int) ([]*Signer, *share.PubPoly) {
+	priPoly := share.NewPriPoly(suite, threshold, nil, suite.RandomStream())
+	pubPoly := priPoly.Commit(nil)
+	signers := make([]*Signer, n)
+	for i, s := range priPoly.Shares(n) {
+		signer, err := NewSigner(suite, s, pubPoly)
+		require.NoError(t, err)
+		require.Equal(t, i, signer.Index())
+		signers[i] = signer
+	}
+	return signers, pubPoly
+}
+
+// sign runs the protocol with the given signers and returns the signature.
+func sign(t *testing.T, suite Suite, signers []*Signer, pubPoly *share.PubPoly, msg []byte) []byte {
+	commits := make([]*Commitment, len(signers))
+	for i, s := range signers {
+		commits[i] = s.Commit()
+	}
+	partials := make([]*PartialSig, len(signers))
+	for i, s := range signers {
+		p, err := s.Sign(commits, msg)
+		require.NoError(t, err)
+		partials[i] = p
+	}
+	session, err := NewSession(suite, pubPoly, commits, msg)
+	require.NoError(t, err)
+	for _, p := range partials {
+		require.NoError(t, session.VerifyPartial(p))
+	}
+	sig, err := session.Aggregate(partials)
+	require.NoError(t, err)
+	return sig
+}
+
+func TestFROST(t *testing.T) {
+	msg := []byte("Hello FROST")
+	signers, pubPoly := newSigners(t, testSuite, 7, 4)
+	for _, set := range [][]int{{0, 1, 2, 3}, {6, 2, 4, 0}, {1, 2, 3, 4, 5, 6}} {
+		var subset []*Signer
+		for _, i := range set {
+			subset = append(subset, signers[i])
+		}
+		sig := sign(t, testSuite, subset, pubPoly, msg)
+		require.NoError(t, schnorr.Verify(testSuite, pubPoly.Commit(), msg, sig))
+		require.Error(t, schnorr.Verify(testSuite, pubPoly.Commit(), []byte("other"), sig))
+
+		// the signature is a valid Ed25519 signature
+		pub, err := pubPoly.Commit().MarshalBinary()
+		require.NoError(t, err)
+		require.True(t, ed25519.Verify(pub, msg, sig))
+	}
+}
+
+func TestFROSTNIST(t *testing.T) {
+	suite := nist.NewBlakeSHA256P256()
+	msg := []byte("Hello FROST")
+	signers, pubPoly := newSigners(t, suite, 5, 3)
+	sig := sign(t, suite, signers[2:], pubPoly, msg)
+	require.NoError(t, schnorr.Verify(suite, pubPoly.Commit(), msg, sig))
+}
+
+func TestFROSTNonceReuse(t *testing.T) {
+	msg := []byte("Hello FROST")
+	signers, pubPoly := newSigners(t, testSuite, 3, 2)
+	_, err := signers[0].Sign(nil, msg)
+	require.Equal(t, errNoNonces, err)
+
+	commits := []*Commitment{signers[0].Commit(), signers[1].Commit()}
+	_, err = signers[0].Sign(commits, msg)
+	require.NoError(t, err)
+	_, err = signers[0].Sign(commits, msg)
+	require.Equal(t, errNoNonces, err)
+
+	// the commitment of the signer must be its last one
+	old := commits[1]
+	signers[1].Commit()
+	_, err = signers[1].Sign(commits, msg)
+	require.Equal(t, errOwnCommitment, err)
+	_, err = signers[1].Sign([]*Commitment{commits[0], signers[2].Commit()}, msg)
+	require.Equal(t, errOwnCommitment, err)
+	_, err = NewSession(testSuite, pubPoly, []*Commitment{old, old}, msg)
+	require.Equal(t, errCommitments, err)
+}
+
+func TestFROSTMaliciousPartial(t *testing.T) {
+	msg := []byte("Hello FROST")
+	signers, pubPoly := newSigners(t, testSuite, 5, 3)
+	commits := make([]*Commitment, 3)
+	for i := range commits {
+		commits[i] = signers[i].Commit()
+	}
+	partials := make([]*PartialSig, 3)
+	for i := range partials {
+		var err error
+		partials[i], err = signers[i].Sign(commits, msg)
+		require.NoError(t, err)
+	}
+	session, err := NewSession(testSuite, pubPoly, commits, msg)
+	require.NoError(t, err)
+
+	// the coordinator identifies the signer of a wrong partial signature
+	partials[1].Z = testSuite.Scalar().Pick(testSuite.RandomStream())
+	require.Equal(t, errInvalidPartial, session.VerifyPartial(partials[1]))
+	require.NoError(t, session.VerifyPartial(partials[2]))
+	_, err = session.Aggregate(partials)
+	require.Equal(t, errInvalidPartial, err)
+
+	// a signer outside of the signing set, a missing or a duplicate partial
+	require.Equal(t, errIndex, session.VerifyPartial(&PartialSig{Index: 4, Z: partials[2].Z}))
+	_, err = session.Aggregate(partials[:2])
+	require.Equal(t, errInvalidPartial, err)
+	_, err = session.Aggregate([]*PartialSig{partials[0], partials[2], partials[2]})
+	require.Equal(t, errInvalidPartial, err)
+}
+
+func TestFROSTInvalidInputs(t *testing.T) {
+	msg := []byte("Hello FROST")
+	signers, pubPoly := newSigners(t, testSuite, 5, 3)
+
+	// a share of another polynomial
+	other := share.NewPriPoly(testSuite, 3, nil, testSuite.RandomStream())
+	_, err := NewSigner(testSuite, other.Eval(0), pubPoly)
+	require.Equal(t, errInvalidShare, err)
+	_, err = NewSigner(testSuite, nil, pubPoly)
+	require.Equal(t, errInvalidShare, err)
+
+	// fewer signers than the threshold
+	commits := []*Commitment{signers[0].Commit(), signers[1].Commit()}
+	_, err = signers[0].Sign(commits, msg)
+	require.Equal(t, errTooFewSigners, err)
+	_, err = NewSession(testSuite, pubPoly, append(commits, nil), msg)
+	require.Equal(t, errCommitments, err)
+	_, err = NewSession(testSuite, pubPoly, append(commits, &Commitment{Index: 2}), msg)
+	require.Equal(t, errCommitments, err)
+}
@@ -0,0 +1,47 @@
+// Package parallel spreads independent computations, such as the pairings of
+// the verification of many signatures, over a bounded number of goroutines.
+package parallel
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// ForEach calls fn(i) for each i in [0, n). If workers is at most 1, the calls
+// are made in order in the calling goroutine. Otherwise, they are spread over
+// at most workers goroutines, and at most runtime.GOMAXPROCS of them, and
+// ForEach returns once all of them returned. The calls must be independent,
+// e.g. each one writing only the i-th element of its results, so that the
+// results do not depend on the number of workers.
+func ForEach(n, workers int, fn func(i int)) {
+	if max := runtime.GOMAXPROCS(0); workers > max {
+		workers = max
+	}
+	if workers > n {
+		workers = n
+	}
+	if workers <= 1 {
+		for i := 0; i < n; i++ {
+			fn(i)
+		}
+		return
+	}
+
+	var next int64 = -1
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(atomic.AddInt64(&next, 1))
+				if i >= n {
+					return
+				}
+				fn(i)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,31 @@
+package parallel
+
+import (
+	"runtime"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestForEach(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	for _, workers := range []int{-1, 0, 1, 3, 4, 100} {
+		out := make([]int, 50)
+		ForEach(len(out), workers, func(i int) { out[i] = i * i })
+		for i, v := range out {
+			require.Equal(t, i*i, v)
+		}
+	}
+
+	// the calls are sequential with at most one worker
+	var running, max int64
+	ForEach(20, 1, func(int) {
+		if r := atomic.AddInt64(&running, 1); r > max {
+			max = r
+		}
+		atomic.AddInt64(&running, -1)
+	})
+	require.Equal(t, int64(1), max)
+	ForEach(0, 4, func(int) { t.Fatal("no call expected") })
+}
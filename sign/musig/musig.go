@@ -0,0 +1,331 @@
+/*
+Package musig implements n-of-n Schnorr multi-signatures following the MuSig2
+scheme of Nick, Ruffing and Seurin. See https://eprint.iacr.org/2020/1261.pdf.
+
+The public keys X_i of the signers are aggregated into a single key
+X = \sum{a_i*X_i} using coefficients a_i = H(L || X_i) where L is the hash of
+the whole list of keys, which prevents rogue-key attacks. The protocol then
+runs in two rounds:
+
+1. Every signer creates a Signer and broadcasts the Commitment returned by
+Commit, i.e. two nonce points R_{i,1} and R_{i,2}. The commitments do not
+depend on the message and can be exchanged in advance.
+
+2. Once all commitments are known, every signer computes its partial
+signature with Sign. The nonces are combined into R = R_1 + b*R_2 where
+R_j = \sum{R_{i,j}} and b = H(X || R_1 || R_2 || M), and the partial signature
+is s_i = r_{i,1} + b*r_{i,2} + c*a_i*x_i with c = H(R || X || M).
+
+Anyone holding the commitments can then create a Session to verify the
+partial signatures and aggregate them into the signature R || \sum{s_i}. It is
+a regular Schnorr signature that schnorr.Verify accepts for the aggregated key
+in any group, e.g. secp256k1, and, when using the edwards25519 group, a valid
+EdDSA signature.
+
+A Signer erases its nonces when creating a partial signature, so that signing
+again requires a new Commit: a nonce can never be used for two messages.
+*/
+package musig
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"errors"
+
+	"go.dedis.ch/kyber/v3"
+)
+
+// Suite represents the set of functionalities needed by the package musig.
+type Suite interface {
+	kyber.Group
+	kyber.HashFactory
+	kyber.Random
+}
+
+var errNoKeys = errors.New("musig: no public keys")
+var errNilKey = errors.New("musig: nil public key")
+var errUnknownKey = errors.New("musig: private key does not belong to the list of public keys")
+var errNoNonces = errors.New("musig: no fresh nonces, call Commit before signing")
+var errCommitments = errors.New("musig: invalid list of commitments")
+var errOwnCommitment = errors.New("musig: own commitment does not match")
+var errInvalidPartial = errors.New("musig: invalid partial signature")
+var errIndex = errors.New("musig: index out of bounds")
+
+// Commitment holds the public nonces of the signer at position Index in the
+// list of public keys.
+type Commitment struct {
+	Index int
+	R1    kyber.Point
+	R2    kyber.Point
+}
+
+// PartialSig is the partial signature of the signer at position Index in the
+// list of public keys.
+type PartialSig struct {
+	Index int
+	S     kyber.Scalar
+}
+
+// AggregateKeys returns the aggregated public key X = \sum{a_i*X_i} of the
+// given public keys together with the coefficients a_i. The aggregated key
+// depends on the order of the keys.
+func AggregateKeys(suite Suite, publics []kyber.Point) (kyber.Point, []kyber.Scalar, error) {
+	if len(publics) == 0 {
+		return nil, nil, errNoKeys
+	}
+	h := suite.Hash()
+	for _, p := range publics {
+		if p == nil {
+			return nil, nil, errNilKey
+		}
+		if _, err := p.MarshalTo(h); err != nil {
+			return nil, nil, err
+		}
+	}
+	L := h.Sum(nil)
+
+	agg := suite.Point().Null()
+	tmp := suite.Point()
+	coeffs := make([]kyber.Scalar, len(publics))
+	for i, p := range publics {
+		h.Reset()
+		_, _ = h.Write(L)
+		if _, err := p.MarshalTo(h); err != nil {
+			return nil, nil, err
+		}
+		coeffs[i] = suite.Scalar().SetBytes(h.Sum(nil))
+		agg.Add(agg, tmp.Mul(coeffs[i], p))
+	}
+	return agg, coeffs, nil
+}
+
+// Session holds the public state of one signing session, i.e. the aggregated
+// key and nonce of a message, from which the partial signatures are verified
+// and aggregated.
+type Session struct {
+	suite   Suite
+	publics []kyber.Point
+	coeffs  []kyber.Scalar
+	commits []*Commitment
+	key     kyber.Point
+	msg     []byte
+	R       kyber.Point  // aggregated nonce
+	b       kyber.Scalar // nonce coefficient
+	c       kyber.Scalar // Schnorr challenge
+}
+
+// NewSession returns the session of the given message for the signers having
+// the given public keys. There must be exactly one commitment per signer.
+func NewSession(suite Suite, publics []kyber.Point, commits []*Commitment, msg []byte) (*Session, error) {
+	key, coeffs, err := AggregateKeys(suite, publics)
+	if err != nil {
+		return nil, err
+	}
+	return newSession(suite, publics, key, coeffs, commits, msg)
+}
+
+func newSession(suite Suite, publics []kyber.Point, key kyber.Point, coeffs []kyber.Scalar, commits []*Commitment, msg []byte) (*Session, error) {
+	if len(commits) != len(publics) {
+		return nil, errCommitments
+	}
+	sorted := make([]*Commitment, len(publics))
+	R1 := suite.Point().Null()
+	R2 := suite.Point().Null()
+	for _, c := range commits {
+		if c == nil || c.Index < 0 || c.Index >= len(publics) || sorted[c.Index] != nil || c.R1 == nil || c.R2 == nil {
+			return nil, errCommitments
+		}
+		sorted[c.Index] = c
+		R1.Add(R1, c.R1)
+		R2.Add(R2, c.R2)
+	}
+
+	h := suite.Hash()
+	for _, p := range []kyber.Point{key, R1, R2} {
+		if _, err := p.MarshalTo(h); err != nil {
+			return nil, err
+		}
+	}
+	_, _ = h.Write(msg)
+	b := suite.Scalar().SetBytes(h.Sum(nil))
+
+	R := suite.Point().Mul(b, R2)
+	R.Add(R, R1)
+	c, err := challenge(suite, R, key, msg)
+	if err != nil {
+		return nil, err
+	}
+	return &Session{
+		suite:   suite,
+		publics: publics,
+		coeffs:  coeffs,
+		commits: sorted,
+		key:     key,
+		msg:     msg,
+		R:       R,
+		b:       b,
+		c:       c,
+	}, nil
+}
+
+// AggregateKey returns the aggregated public key of the session.
+func (s *Session) AggregateKey() kyber.Point {
+	return s.key
+}
+
+// VerifyPartial checks that s_i*G == R_{i,1} + b*R_{i,2} + c*a_i*X_i for the
+// given partial signature.
+func (s *Session) VerifyPartial(p *PartialSig) error {
+	if p == nil || p.S == nil {
+		return errInvalidPartial
+	}
+	if p.Index < 0 || p.Index >= len(s.publics) {
+		return errIndex
+	}
+	i := p.Index
+	exp := s.suite.Point().Mul(s.b, s.commits[i].R2)
+	exp.Add(exp, s.commits[i].R1)
+	ca := s.suite.Scalar().Mul(s.c, s.coeffs[i])
+	exp.Add(exp, s.suite.Point().Mul(ca, s.publics[i]))
+	if !s.suite.Point().Mul(p.S, nil).Equal(exp) {
+		return errInvalidPartial
+	}
+	return nil
+}
+
+// Aggregate verifies the partial signatures of all signers and returns the
+// final signature R || s, which can be verified by schnorr.Verify with the
+// aggregated public key.
+func (s *Session) Aggregate(partials []*PartialSig) ([]byte, error) {
+	if len(partials) != len(s.publics) {
+		return nil, errInvalidPartial
+	}
+	seen := make([]bool, len(s.publics))
+	sum := s.suite.Scalar().Zero()
+	for _, p := range partials {
+		if err := s.VerifyPartial(p); err != nil {
+			return nil, err
+		}
+		if seen[p.Index] {
+			return nil, errInvalidPartial
+		}
+		seen[p.Index] = true
+		sum.Add(sum, p.S)
+	}
+
+	var buf bytes.Buffer
+	if _, err := s.R.MarshalTo(&buf); err != nil {
+		return nil, err
+	}
+	if _, err := sum.MarshalTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Signer holds the private state of one signer. It must not be shared
+// between concurrent signing sessions.
+type Signer struct {
+	suite   Suite
+	private kyber.Scalar
+	index   int
+	publics []kyber.Point
+	key     kyber.Point
+	coeffs  []kyber.Scalar
+	r1, r2  kyber.Scalar // secret nonces, nil once used
+	commit  *Commitment
+}
+
+// NewSigner returns the signer holding the given private key among the
+// signers having the given public keys.
+func NewSigner(suite Suite, private kyber.Scalar, publics []kyber.Point) (*Signer, error) {
+	key, coeffs, err := AggregateKeys(suite, publics)
+	if err != nil {
+		return nil, err
+	}
+	public := suite.Point().Mul(private, nil)
+	index := -1
+	for i, p := range publics {
+		if p.Equal(public) {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, errUnknownKey
+	}
+	return &Signer{
+		suite:   suite,
+		private: private,
+		index:   index,
+		publics: publics,
+		key:     key,
+		coeffs:  coeffs,
+	}, nil
+}
+
+// Index returns the position of the signer in the list of public keys.
+func (s *Signer) Index() int {
+	return s.index
+}
+
+// AggregateKey returns the aggregated public key of the signers.
+func (s *Signer) AggregateKey() kyber.Point {
+	return s.key
+}
+
+// Commit generates fresh nonces, replacing any unused ones, and returns the
+// corresponding commitment to broadcast to the other signers.
+func (s *Signer) Commit() *Commitment {
+	s.r1 = s.suite.Scalar().Pick(s.suite.RandomStream())
+	s.r2 = s.suite.Scalar().Pick(s.suite.RandomStream())
+	s.commit = &Commitment{
+		Index: s.index,
+		R1:    s.suite.Point().Mul(s.r1, nil),
+		R2:    s.suite.Point().Mul(s.r2, nil),
+	}
+	return s.commit
+}
+
+// Sign returns the partial signature of the message given the commitments of
+// all signers, including its own one from the last call to Commit. The nonces
+// are erased, so that Sign returns an error until Commit is called again.
+func (s *Signer) Sign(commits []*Commitment, msg []byte) (*PartialSig, error) {
+	if s.r1 == nil || s.r2 == nil {
+		return nil, errNoNonces
+	}
+	session, err := newSession(s.suite, s.publics, s.key, s.coeffs, commits, msg)
+	if err != nil {
+		return nil, err
+	}
+	own := session.commits[s.index]
+	if !own.R1.Equal(s.commit.R1) || !own.R2.Equal(s.commit.R2) {
+		return nil, errOwnCommitment
+	}
+	r1, r2 := s.r1, s.r2
+	s.r1, s.r2, s.commit = nil, nil, nil
+
+	// s_i = r_1 + b*r_2 + c*a_i*x_i
+	sig := s.suite.Scalar().Mul(session.c, s.coeffs[s.index])
+	sig.Mul(sig, s.private)
+	sig.Add(sig, r1)
+	sig.Add(sig, r2.Mul(r2, session.b))
+	r1.Zero()
+	r2.Zero()
+	return &PartialSig{Index: s.index, S: sig}, nil
+}
+
+// challenge returns H(R || X || M) computed as in the schnorr package.
+func challenge(g kyber.Group, R, public kyber.Point, msg []byte) (kyber.Scalar, error) {
+	h := sha512.New()
+	if _, err := R.MarshalTo(h); err != nil {
+		return nil, err
+	}
+	if _, err := public.MarshalTo(h); err != nil {
+		return nil, err
+	}
+	if _, err := h.Write(msg); err != nil {
+		return nil, err
+	}
+	return g.Scalar().SetBytes(h.Sum(nil)), nil
+}
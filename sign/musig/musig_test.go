@@ -0,0 +1,173 @@
+package musig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/group/secp256k1"
+	"go.dedis.ch/kyber/v3/sign/eddsa"
+	"go.dedis.ch/kyber/v3/sign/schnorr"
+	"go.dedis.ch/kyber/v3/util/key"
+)
+
+var testSuite = edwards25519.NewBlakeSHA256Ed25519()
+
+func newSigners(t *testing.T, n int) ([]*Signer, []kyber.Point) {
+	return newSuiteSigners(t, testSuite, n)
+}
+
+func newSuiteSigners(t *testing.T, suite Suite, n int) ([]*Signer, []kyber.Point) {
+	privates := make([]kyber.Scalar, n)
+	publics := make([]kyber.Point, n)
+	for i := range privates {
+		kp := key.NewKeyPair(suite)
+		privates[i] = kp.Private
+		publics[i] = kp.Public
+	}
+	signers := make([]*Signer, n)
+	for i := range signers {
+		s, err := NewSigner(suite, privates[i], publics)
+		require.NoError(t, err)
+		require.Equal(t, i, s.Index())
+		signers[i] = s
+	}
+	return signers, publics
+}
+
+func commitAll(signers []*Signer) []*Commitment {
+	commits := make([]*Commitment, len(signers))
+	for i, s := range signers {
+		commits[i] = s.Commit()
+	}
+	return commits
+}
+
+func TestMuSig(t *testing.T) {
+	msg := []byte("Hello MuSig")
+	for _, n := range []int{2, 3, 10} {
+		signers, publics := newSigners(t, n)
+		commits := commitAll(signers)
+
+		partials := make([]*PartialSig, n)
+		for i, s := range signers {
+			p, err := s.Sign(commits, msg)
+			require.NoError(t, err)
+			partials[i] = p
+		}
+
+		session, err := NewSession(testSuite, publics, commits, msg)
+		require.NoError(t, err)
+		require.True(t, session.AggregateKey().Equal(signers[0].AggregateKey()))
+		for _, p := range partials {
+			require.NoError(t, session.VerifyPartial(p))
+		}
+		sig, err := session.Aggregate(partials)
+		require.NoError(t, err)
+		require.NoError(t, schnorr.Verify(testSuite, session.AggregateKey(), msg, sig))
+		require.Error(t, schnorr.Verify(testSuite, session.AggregateKey(), []byte("other"), sig))
+
+		// the signature is a valid EdDSA signature
+		pub, err := session.AggregateKey().MarshalBinary()
+		require.NoError(t, err)
+		require.NoError(t, eddsa.Verify(session.AggregateKey(), msg, sig))
+		require.NoError(t, schnorr.VerifyWithChecks(testSuite, pub, msg, sig))
+	}
+}
+
+// TestMuSigSecp256k1 checks that the aggregated signatures are plain Schnorr
+// signatures in other groups than edwards25519.
+func TestMuSigSecp256k1(t *testing.T) {
+	suite := secp256k1.NewBlakeSHA256Secp256k1()
+	msg := []byte("Hello MuSig")
+	signers, publics := newSuiteSigners(t, suite, 3)
+	commits := commitAll(signers)
+	partials := make([]*PartialSig, len(signers))
+	for i, s := range signers {
+		p, err := s.Sign(commits, msg)
+		require.NoError(t, err)
+		partials[i] = p
+	}
+	session, err := NewSession(suite, publics, commits, msg)
+	require.NoError(t, err)
+	sig, err := session.Aggregate(partials)
+	require.NoError(t, err)
+	require.NoError(t, schnorr.Verify(suite, session.AggregateKey(), msg, sig))
+	require.Error(t, schnorr.Verify(suite, session.AggregateKey(), []byte("other"), sig))
+}
+
+func TestMuSigNonceReuse(t *testing.T) {
+	msg := []byte("Hello MuSig")
+	signers, _ := newSigners(t, 3)
+	_, err := signers[0].Sign([]*Commitment{}, msg)
+	require.Equal(t, errNoNonces, err)
+
+	commits := commitAll(signers)
+	_, err = signers[0].Sign(commits, msg)
+	require.NoError(t, err)
+	_, err = signers[0].Sign(commits, []byte("other"))
+	require.Equal(t, errNoNonces, err)
+
+	// fresh nonces allow signing again
+	commits[0] = signers[0].Commit()
+	_, err = signers[0].Sign(commits, []byte("other"))
+	require.NoError(t, err)
+
+	// a replaced own commitment is detected
+	signers[1].Commit()
+	_, err = signers[1].Sign(commits, msg)
+	require.Equal(t, errOwnCommitment, err)
+}
+
+func TestMuSigMaliciousPartial(t *testing.T) {
+	msg := []byte("Hello MuSig")
+	signers, publics := newSigners(t, 3)
+	commits := commitAll(signers)
+	partials := make([]*PartialSig, len(signers))
+	for i, s := range signers {
+		p, err := s.Sign(commits, msg)
+		require.NoError(t, err)
+		partials[i] = p
+	}
+	session, err := NewSession(testSuite, publics, commits, msg)
+	require.NoError(t, err)
+
+	// a wrong response is rejected
+	bad := &PartialSig{Index: 1, S: testSuite.Scalar().Add(partials[1].S, testSuite.Scalar().One())}
+	require.Equal(t, errInvalidPartial, session.VerifyPartial(bad))
+	_, err = session.Aggregate([]*PartialSig{partials[0], bad, partials[2]})
+	require.Equal(t, errInvalidPartial, err)
+
+	// a valid partial signature cannot be claimed by another signer
+	stolen := &PartialSig{Index: 2, S: partials[1].S}
+	require.Equal(t, errInvalidPartial, session.VerifyPartial(stolen))
+
+	// duplicated and missing partial signatures are rejected
+	_, err = session.Aggregate([]*PartialSig{partials[0], partials[0], partials[2]})
+	require.Error(t, err)
+	_, err = session.Aggregate(partials[:2])
+	require.Error(t, err)
+}
+
+func TestMuSigInvalidInputs(t *testing.T) {
+	_, _, err := AggregateKeys(testSuite, nil)
+	require.Equal(t, errNoKeys, err)
+
+	signers, publics := newSigners(t, 3)
+	_, err = NewSigner(testSuite, testSuite.Scalar().Pick(testSuite.RandomStream()), publics)
+	require.Equal(t, errUnknownKey, err)
+
+	// the order of the keys matters
+	k1, _, err := AggregateKeys(testSuite, publics)
+	require.NoError(t, err)
+	k2, _, err := AggregateKeys(testSuite, []kyber.Point{publics[1], publics[0], publics[2]})
+	require.NoError(t, err)
+	require.False(t, k1.Equal(k2))
+
+	commits := commitAll(signers)
+	_, err = NewSession(testSuite, publics, commits[:2], nil)
+	require.Equal(t, errCommitments, err)
+	_, err = NewSession(testSuite, publics, []*Commitment{commits[0], commits[0], commits[2]}, nil)
+	require.Equal(t, errCommitments, err)
+}
@@ -0,0 +1,134 @@
+package sign
+
+import (
+	"crypto/cipher"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/share"
+	"go.dedis.ch/kyber/v3/sign/bls"
+	"go.dedis.ch/kyber/v3/sign/eddsa"
+	"go.dedis.ch/kyber/v3/sign/schnorr"
+	"go.dedis.ch/kyber/v3/sign/tbls"
+)
+
+// Scheme is a signature scheme, so that an application can be generic over
+// the signature schemes of Kyber, e.g. to select one by name with
+// SchemeByName.
+type Scheme interface {
+	// NewKeyPair returns a new private key and its public key.
+	NewKeyPair(random cipher.Stream) (kyber.Scalar, kyber.Point)
+	// Sign returns the signature of the message with the private key.
+	Sign(private kyber.Scalar, msg []byte) ([]byte, error)
+	// Verify returns nil if sig is a signature of the message with the
+	// private key of the public key, and an error otherwise.
+	Verify(public kyber.Point, msg, sig []byte) error
+}
+
+// AggregatableScheme is a signature scheme whose signatures of the same
+// message can be combined into one, verified against the combination of the
+// public keys.
+type AggregatableScheme interface {
+	Scheme
+	AggregateSignatures(sigs ...[]byte) ([]byte, error)
+	AggregatePublicKeys(publics ...kyber.Point) kyber.Point
+}
+
+// ThresholdScheme is a threshold signature scheme: the holders of the shares
+// of a distributed private key sign shares of the signature, a threshold of
+// which is enough to recover the signature of the distributed key.
+type ThresholdScheme interface {
+	// Sign returns the signature share of the message with the private
+	// share.
+	Sign(private *share.PriShare, msg []byte) ([]byte, error)
+	// VerifyPartial checks a signature share against the public polynomial
+	// of the distributed key.
+	VerifyPartial(public *share.PubPoly, msg, sig []byte) error
+	// Recover returns the signature of the distributed key from t out of the
+	// n signature shares.
+	Recover(public *share.PubPoly, msg []byte, sigs [][]byte, t, n int) ([]byte, error)
+	// VerifyRecovered checks a recovered signature against the distributed
+	// public key.
+	VerifyRecovered(public kyber.Point, msg, sig []byte) error
+}
+
+var (
+	_ AggregatableScheme = (*bls.Scheme)(nil)
+	_ Scheme             = (*schnorr.Scheme)(nil)
+	_ Scheme             = (*eddsa.Scheme)(nil)
+	_ ThresholdScheme    = (*tbls.Scheme)(nil)
+)
+
+// SchemeFactory returns the signature scheme over the given suite. It returns
+// an error if the suite is not suitable for the scheme.
+type SchemeFactory func(suite interface{}) (Scheme, error)
+
+// schemesLock guards the registered schemes, which can be looked up and
+// registered concurrently.
+var schemesLock sync.RWMutex
+
+var schemes = map[string]SchemeFactory{
+	"bls":       blsScheme(func(s pairing.Suite) *bls.Scheme { return bls.NewSchemeOnG1(s).WithDST(bls.DefaultDST) }),
+	"bls-on-g1": blsScheme(bls.NewSchemeOnG1),
+	"bls-on-g2": blsScheme(bls.NewSchemeOnG2),
+	"schnorr": func(suite interface{}) (Scheme, error) {
+		s, ok := suite.(schnorr.Suite)
+		if !ok {
+			return nil, fmt.Errorf("sign: schnorr needs a suite with a group and a random stream, not %T", suite)
+		}
+		return schnorr.NewScheme(s), nil
+	},
+	"eddsa": func(interface{}) (Scheme, error) {
+		return eddsa.NewScheme(), nil
+	},
+}
+
+func blsScheme(newScheme func(pairing.Suite) *bls.Scheme) SchemeFactory {
+	return func(suite interface{}) (Scheme, error) {
+		s, ok := suite.(pairing.Suite)
+		if !ok {
+			return nil, fmt.Errorf("sign: bls needs a pairing suite, not %T", suite)
+		}
+		return newScheme(s), nil
+	}
+}
+
+// RegisterScheme makes the scheme returned by the factory available to
+// SchemeByName under the given name, compared case-insensitively. A scheme
+// registered under the name of another one replaces it.
+func RegisterScheme(name string, factory SchemeFactory) {
+	schemesLock.Lock()
+	defer schemesLock.Unlock()
+	schemes[strings.ToLower(name)] = factory
+}
+
+// ErrUnknownScheme indicates that the scheme was not one of the registered
+// schemes. The errors returned by SchemeByName for unknown names wrap it and
+// can be checked with errors.Is.
+var ErrUnknownScheme = errors.New("sign: unknown scheme")
+
+// SchemeByName returns the signature scheme registered under the name over the
+// suite. An AggregatableScheme can be asserted from the returned scheme. Along
+// with the ones of RegisterScheme, the available schemes are:
+//   - "bls": the scheme of the package-level functions of kyber/sign/bls,
+//   - "bls-on-g1" and "bls-on-g2": bls.NewSchemeOnG1 and bls.NewSchemeOnG2,
+//     which need a pairing.Suite,
+//   - "schnorr": the Schnorr signatures over the group of a schnorr.Suite,
+//   - "eddsa": the Ed25519 signatures, see eddsa.Scheme, which ignores the
+//     suite.
+//
+// Importing kyber/sign/bdn registers its scheme as "bdn", which needs a
+// pairing.Suite.
+func SchemeByName(suite interface{}, name string) (Scheme, error) {
+	schemesLock.RLock()
+	factory, ok := schemes[strings.ToLower(name)]
+	schemesLock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownScheme, name)
+	}
+	return factory(suite)
+}
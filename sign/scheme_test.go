@@ -0,0 +1,96 @@
+package sign
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/share"
+	"go.dedis.ch/kyber/v3/sign/tbls"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+func TestSchemeConformance(t *testing.T) {
+	for _, name := range []string{"bls", "bls-on-g1", "BLS-on-G2", "schnorr", "eddsa"} {
+		t.Run(name, func(t *testing.T) {
+			scheme, err := SchemeByName(suite, name)
+			require.NoError(t, err)
+			testScheme(t, scheme)
+		})
+	}
+	scheme, err := SchemeByName(edwards25519.NewBlakeSHA256Ed25519(), "schnorr")
+	require.NoError(t, err)
+	testScheme(t, scheme)
+}
+
+func testScheme(t *testing.T, scheme Scheme) {
+	msg := []byte("Hello signature scheme")
+	private1, public1 := scheme.NewKeyPair(random.New())
+	private2, public2 := scheme.NewKeyPair(random.New())
+	require.False(t, public1.Equal(public2))
+
+	sig1, err := scheme.Sign(private1, msg)
+	require.NoError(t, err)
+	require.NoError(t, scheme.Verify(public1, msg, sig1))
+	require.Error(t, scheme.Verify(public2, msg, sig1))
+	require.Error(t, scheme.Verify(public1, []byte("other"), sig1))
+	require.Error(t, scheme.Verify(public1, msg, sig1[1:]))
+	tampered := append([]byte{}, sig1...)
+	tampered[len(tampered)-1] ^= 1
+	require.Error(t, scheme.Verify(public1, msg, tampered))
+
+	aggregatable, ok := scheme.(AggregatableScheme)
+	if !ok {
+		return
+	}
+	sig2, err := scheme.Sign(private2, msg)
+	require.NoError(t, err)
+	sig, err := aggregatable.AggregateSignatures(sig1, sig2)
+	require.NoError(t, err)
+	public := aggregatable.AggregatePublicKeys(public1, public2)
+	require.NoError(t, scheme.Verify(public, msg, sig))
+	require.Error(t, scheme.Verify(public1, msg, sig))
+}
+
+func TestThresholdSchemeConformance(t *testing.T) {
+	// the public keys of the scheme on G1 are on G2 and conversely
+	testThresholdScheme(t, tbls.NewSchemeOnG1(suite), suite.G2())
+	testThresholdScheme(t, tbls.NewSchemeOnG2(suite), suite.G1())
+}
+
+func testThresholdScheme(t *testing.T, scheme ThresholdScheme, keyGroup kyber.Group) {
+	msg := []byte("Hello threshold signature scheme")
+	n, thr := 7, 4
+	priPoly := share.NewPriPoly(keyGroup, thr, nil, random.New())
+	pubPoly := priPoly.Commit(nil)
+	var sigs [][]byte
+	for _, sh := range priPoly.Shares(n) {
+		sig, err := scheme.Sign(sh, msg)
+		require.NoError(t, err)
+		require.NoError(t, scheme.VerifyPartial(pubPoly, msg, sig))
+		require.Error(t, scheme.VerifyPartial(pubPoly, []byte("other"), sig))
+		sigs = append(sigs, sig)
+	}
+	sig, err := scheme.Recover(pubPoly, msg, sigs, thr, n)
+	require.NoError(t, err)
+	require.NoError(t, scheme.VerifyRecovered(pubPoly.Commit(), msg, sig))
+	require.Error(t, scheme.VerifyRecovered(pubPoly.Commit(), []byte("other"), sig))
+	_, err = scheme.Recover(pubPoly, msg, sigs[:thr-1], thr, n)
+	require.Error(t, err)
+}
+
+func TestSchemeByName(t *testing.T) {
+	_, err := SchemeByName(suite, "unknown")
+	require.True(t, errors.Is(err, ErrUnknownScheme))
+	_, err = SchemeByName(edwards25519.NewBlakeSHA256Ed25519(), "bls")
+	require.Error(t, err)
+
+	RegisterScheme("Custom", func(interface{}) (Scheme, error) {
+		return SchemeByName(suite, "eddsa")
+	})
+	scheme, err := SchemeByName(nil, "custom")
+	require.NoError(t, err)
+	testScheme(t, scheme)
+}
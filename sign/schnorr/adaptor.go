@@ -0,0 +1,123 @@
+package schnorr
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/util/wipe"
+)
+
+// An adaptor signature, or pre-signature, is a Schnorr signature encrypted
+// under an adaptor point T = t*B: the pre-signature R || s' of a message,
+// with s' = k + h*x and h = H(R + T || X || M), becomes the signature
+// R + T || s' + t of the message once adapted with the secret t, and the
+// pre-signature and the signature together reveal t = s - s'. Anyone can
+// check that a pre-signature adapts into a signature with the secret of T,
+// so that publishing a signature sells the secret, e.g. to swap assets
+// atomically across two chains.
+
+// PreSign returns the pre-signature of the message with the private key for
+// the adaptor point T, which Adapt completes into a signature with the
+// discrete logarithm of T.
+func PreSign(s Suite, private kyber.Scalar, T kyber.Point, msg []byte) ([]byte, error) {
+	k := s.Scalar().Pick(s.RandomStream())
+	R := s.Point().Mul(k, nil)
+	public := s.Point().Mul(private, nil)
+	h, err := hash(s, public, s.Point().Add(R, T), bytes.NewReader(msg))
+	if err != nil {
+		return nil, err
+	}
+
+	// s' = k + x*h, which is not a signature for the commitment R + T
+	xh := s.Scalar().Mul(private, h)
+	S := s.Scalar().Add(k, xh)
+	wipe.Scalar(k)
+	wipe.Scalar(xh)
+	return encodeSignature(R, S)
+}
+
+// VerifyPreSignature returns nil iff preSig is a pre-signature of the message
+// by the public key for the adaptor point T, i.e. Adapt completes it into a
+// valid signature with the discrete logarithm of T.
+func VerifyPreSignature(g kyber.Group, public, T kyber.Point, msg, preSig []byte) error {
+	R, s, err := decodePreSignature(g, preSig)
+	if err != nil {
+		return err
+	}
+	h, err := hash(g, public, g.Point().Add(R, T), bytes.NewReader(msg))
+	if err != nil {
+		return err
+	}
+	// s'*B = R + h*X
+	left := g.Point().Mul(s, nil)
+	right := g.Point().Mul(h, public)
+	right.Add(right, R)
+	if !left.Equal(right) {
+		return errors.New("schnorr: invalid pre-signature")
+	}
+	return nil
+}
+
+// Adapt completes the pre-signature with the discrete logarithm t of its
+// adaptor point, and returns the signature R + t*B || s' + t of the message.
+func Adapt(g kyber.Group, preSig []byte, t kyber.Scalar) ([]byte, error) {
+	R, s, err := decodePreSignature(g, preSig)
+	if err != nil {
+		return nil, err
+	}
+	R.Add(R, g.Point().Mul(t, nil))
+	return encodeSignature(R, s.Add(s, t))
+}
+
+// ExtractSecret returns the discrete logarithm t = s - s' of the adaptor
+// point of the pre-signature from the signature Adapt completed it into. It
+// returns an error if the signature is not the adapted pre-signature.
+func ExtractSecret(g kyber.Group, preSig, sig []byte) (kyber.Scalar, error) {
+	R, s, err := decodePreSignature(g, preSig)
+	if err != nil {
+		return nil, err
+	}
+	adapted, adaptedS, err := decodePreSignature(g, sig)
+	if err != nil {
+		return nil, err
+	}
+	t := adaptedS.Sub(adaptedS, s)
+	// the commitment of the signature must be R + t*B
+	if !adapted.Equal(R.Add(R, g.Point().Mul(t, nil))) {
+		return nil, errors.New("schnorr: signature is not an adapted pre-signature")
+	}
+	return t, nil
+}
+
+// encodeSignature returns the encoding R || s of a signature.
+func encodeSignature(R kyber.Point, s kyber.Scalar) ([]byte, error) {
+	var b bytes.Buffer
+	if _, err := R.MarshalTo(&b); err != nil {
+		return nil, err
+	}
+	if _, err := s.MarshalTo(&b); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// decodePreSignature decodes a pre-signature or signature R || s, without the
+// checks of VerifyWithChecks.
+func decodePreSignature(g kyber.Group, sig []byte) (kyber.Point, kyber.Scalar, error) {
+	R := g.Point()
+	s := g.Scalar()
+	pointSize := R.MarshalSize()
+	sigSize := pointSize + s.MarshalSize()
+	if len(sig) != sigSize {
+		return nil, nil, fmt.Errorf("schnorr: signature of invalid length %d instead of %d", len(sig), sigSize)
+	}
+	if err := R.UnmarshalBinary(sig[:pointSize]); err != nil {
+		return nil, nil, err
+	}
+	if err := s.UnmarshalBinary(sig[pointSize:]); err != nil {
+		return nil, nil, err
+	}
+	return R, s, nil
+}
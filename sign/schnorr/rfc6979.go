@@ -0,0 +1,91 @@
+package schnorr
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"errors"
+	"math/big"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/util/wipe"
+)
+
+// nonceRFC6979 returns the nonce of the private key and the message generated
+// with HMAC-SHA-512 as in section 3.2 of RFC 6979, i.e. the nonce of a DSA or
+// ECDSA signature of the same key and message with SHA-512, with the optional
+// additional data of section 3.6.
+func nonceRFC6979(g kyber.Group, private kyber.Scalar, msg, extra []byte) (kyber.Scalar, error) {
+	x, ok := private.(kyber.ScalarEncoder)
+	if !ok {
+		return nil, errors.New("schnorr: deterministic nonces need scalars implementing kyber.ScalarEncoder")
+	}
+	// the order q gives qlen and rlen of the RFC
+	qMinus1 := g.Scalar().SetInt64(-1).(kyber.ScalarEncoder).BytesBE()
+	qlen := new(big.Int).SetBytes(qMinus1).BitLen()
+	rlen := len(qMinus1)
+
+	// bits2octets(h1) = int2octets(bits2int(h1) mod q)
+	h1 := sha512.Sum512(msg)
+	h, err := g.Scalar().(kyber.ScalarEncoder).SetBytesWide(bits2int(h1[:], qlen, rlen))
+	if err != nil {
+		return nil, err
+	}
+	hb := h.(kyber.ScalarEncoder).BytesBE()
+	xb := x.BytesBE()
+	defer wipe.Bytes(xb)
+
+	V := make([]byte, sha512.Size)
+	K := make([]byte, sha512.Size)
+	for i := range V {
+		V[i] = 0x01
+	}
+	mac := func(K []byte, data ...[]byte) []byte {
+		m := hmac.New(sha512.New, K)
+		for _, d := range data {
+			_, _ = m.Write(d)
+		}
+		return m.Sum(nil)
+	}
+	K = mac(K, V, []byte{0x00}, xb, hb, extra)
+	V = mac(K, V)
+	K = mac(K, V, []byte{0x01}, xb, hb, extra)
+	V = mac(K, V)
+	defer func() { wipe.Bytes(K); wipe.Bytes(V) }()
+
+	for {
+		var T []byte
+		for len(T)*8 < qlen {
+			V = mac(K, V)
+			T = append(T, V...)
+		}
+		kb := bits2int(T, qlen, rlen)
+		wipe.Bytes(T)
+		k, err := g.Scalar().(kyber.ScalarEncoder).SetBytesBE(kb)
+		wipe.Bytes(kb)
+		// k must be in [1, q-1]
+		if err == nil && !k.Equal(g.Scalar().Zero()) {
+			return k, nil
+		}
+		K = mac(K, V, []byte{0x00})
+		V = mac(K, V)
+	}
+}
+
+// bits2int returns the rlen-byte big-endian encoding of the integer of the
+// qlen leftmost bits of b, as in section 2.3.2 of RFC 6979. It runs in
+// constant time.
+func bits2int(b []byte, qlen, rlen int) []byte {
+	z := make([]byte, rlen)
+	if len(b)*8 <= qlen {
+		copy(z[rlen-len(b):], b)
+		return z
+	}
+	copy(z, b)
+	if s := uint(8*rlen - qlen); s > 0 {
+		for i := rlen - 1; i > 0; i-- {
+			z[i] = z[i]>>s | z[i-1]<<(8-s)
+		}
+		z[0] >>= s
+	}
+	return z
+}
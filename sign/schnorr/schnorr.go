@@ -8,16 +8,28 @@ challenge times private key while the Wikipedia article substracts them.
 
 The resulting signature is compatible with EdDSA verification algorithm
 when using the edwards25519 group, and by extension the CoSi verification algorithm.
+
+Sign draws the nonce of the signature from the randomness of the suite,
+while SignDeterministic derives it from the private key and the message as
+in RFC 6979, and SignHedged from both.
+
+PreSign, Adapt and ExtractSecret implement the adaptor signatures, which
+reveal a secret when they are completed into signatures.
 */
 package schnorr
 
 import (
 	"bytes"
+	"crypto/cipher"
 	"crypto/sha512"
 	"errors"
 	"fmt"
+	"io"
 
 	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/util/msm"
+	"go.dedis.ch/kyber/v3/util/random"
+	"go.dedis.ch/kyber/v3/util/wipe"
 )
 
 // Suite represents the set of functionalities needed by the package schnorr.
@@ -30,14 +42,57 @@ type Suite interface {
 // signature can be verified with VerifySchnorr. It's also a valid EdDSA
 // signature when using the edwards25519 Group.
 func Sign(s Suite, private kyber.Scalar, msg []byte) ([]byte, error) {
-	var g kyber.Group = s
-	// create random secret k and public point commitment R
-	k := g.Scalar().Pick(s.RandomStream())
+	return SignReader(s, private, bytes.NewReader(msg))
+}
+
+// SignReader works like Sign on the message read from r until EOF, which is
+// streamed through the hash function instead of being held in memory. The
+// signature is the same as the one Sign returns for the whole message with the
+// same randomness. An error of r is returned instead of a signature of a
+// truncated message.
+func SignReader(s Suite, private kyber.Scalar, r io.Reader) ([]byte, error) {
+	// create random secret k
+	k := s.Scalar().Pick(s.RandomStream())
+	return sign(s, private, k, r)
+}
+
+// SignDeterministic works like Sign with a nonce derived from the private key
+// and the message with the HMAC-SHA-512 generator of RFC 6979 instead of a
+// random one, so that the signatures do not depend on the quality of a
+// random number generator: a message always has the same signature. The
+// scalars of the group must implement kyber.ScalarEncoder.
+func SignDeterministic(g kyber.Group, private kyber.Scalar, msg []byte) ([]byte, error) {
+	k, err := nonceRFC6979(g, private, msg, nil)
+	if err != nil {
+		return nil, err
+	}
+	return sign(g, private, k, bytes.NewReader(msg))
+}
+
+// SignHedged works like SignDeterministic with 32 bytes of the randomness of
+// the suite as the additional data of the nonce, as in section 3.6 of RFC
+// 6979: the signatures are random again, while the nonces stay secret even
+// with a broken random number generator. The scalars of the group must
+// implement kyber.ScalarEncoder.
+func SignHedged(s Suite, private kyber.Scalar, msg []byte) ([]byte, error) {
+	extra := make([]byte, 32)
+	s.RandomStream().XORKeyStream(extra, extra)
+	k, err := nonceRFC6979(s, private, msg, extra)
+	if err != nil {
+		return nil, err
+	}
+	return sign(s, private, k, bytes.NewReader(msg))
+}
+
+// sign returns the signature of the message read from r with the nonce k,
+// which it wipes.
+func sign(g kyber.Group, private, k kyber.Scalar, r io.Reader) ([]byte, error) {
+	// public point commitment R
 	R := g.Point().Mul(k, nil)
 
 	// create hash(public || R || message)
 	public := g.Point().Mul(private, nil)
-	h, err := hash(g, public, R, msg)
+	h, err := hash(g, public, R, r)
 	if err != nil {
 		return nil, err
 	}
@@ -45,16 +100,12 @@ func Sign(s Suite, private kyber.Scalar, msg []byte) ([]byte, error) {
 	// compute response s = k + x*h
 	xh := g.Scalar().Mul(private, h)
 	S := g.Scalar().Add(k, xh)
+	// the nonce reveals the private key together with the signature
+	wipe.Scalar(k)
+	wipe.Scalar(xh)
 
 	// return R || s
-	var b bytes.Buffer
-	if _, err := R.MarshalTo(&b); err != nil {
-		return nil, err
-	}
-	if _, err := S.MarshalTo(&b); err != nil {
-		return nil, err
-	}
-	return b.Bytes(), nil
+	return encodeSignature(R, S)
 }
 
 // VerifyWithChecks uses a public key buffer, a message and a signature.
@@ -63,6 +114,44 @@ func Sign(s Suite, private kyber.Scalar, msg []byte) ([]byte, error) {
 // additional checks around the canonicality and ensures the public key
 // does not have a small order when using `edwards25519` group.
 func VerifyWithChecks(g kyber.Group, pub, msg, sig []byte) error {
+	return verifyWithChecks(g, pub, bytes.NewReader(msg), sig)
+}
+
+func verifyWithChecks(g kyber.Group, pub []byte, msg io.Reader, sig []byte) error {
+	R, s, public, h, err := decodeSignature(g, pub, msg, sig)
+	if err != nil {
+		return err
+	}
+
+	// all values are public, so use the faster variable time
+	// double-base multiplication when the group supports it
+	if vt, ok := g.Point().(kyber.VartimeMulAdder); ok {
+		// compute R' = g^s - A^h, which must be equal to R
+		Rp := vt.MulAddVartime(g.Scalar().Neg(h), public, s)
+		if !Rp.Equal(R) {
+			return errors.New("schnorr: invalid signature")
+		}
+		return nil
+	}
+
+	// compute S = g^s
+	S := g.Point().Mul(s, nil)
+	// compute RAh = R + A^h
+	Ah := g.Point().Mul(h, public)
+	RAs := g.Point().Add(R, Ah)
+
+	if !S.Equal(RAs) {
+		return errors.New("schnorr: invalid signature")
+	}
+
+	return nil
+
+}
+
+// decodeSignature decodes the signature R || s and the public key with the
+// checks of VerifyWithChecks, and returns them with the hash h of the
+// message, so that the signature is valid iff g^s = R + A^h.
+func decodeSignature(g kyber.Group, pub []byte, msg io.Reader, sig []byte) (R kyber.Point, s kyber.Scalar, public kyber.Point, h kyber.Scalar, err error) {
 	type scalarCanCheckCanonical interface {
 		IsCanonical(b []byte) bool
 	}
@@ -72,76 +161,147 @@ func VerifyWithChecks(g kyber.Group, pub, msg, sig []byte) error {
 		IsCanonical(b []byte) bool
 	}
 
-	R := g.Point()
-	s := g.Scalar()
+	R = g.Point()
+	s = g.Scalar()
 	pointSize := R.MarshalSize()
 	scalarSize := s.MarshalSize()
 	sigSize := scalarSize + pointSize
 	if len(sig) != sigSize {
-		return fmt.Errorf("schnorr: signature of invalid length %d instead of %d", len(sig), sigSize)
+		return nil, nil, nil, nil, fmt.Errorf("schnorr: signature of invalid length %d instead of %d", len(sig), sigSize)
 	}
 	if err := R.UnmarshalBinary(sig[:pointSize]); err != nil {
-		return err
+		return nil, nil, nil, nil, err
 	}
 	if p, ok := R.(pointCanCheckCanonicalAndSmallOrder); ok {
 		if !p.IsCanonical(sig[:pointSize]) {
-			return fmt.Errorf("R is not canonical")
+			return nil, nil, nil, nil, fmt.Errorf("R is not canonical")
 		}
 		if p.HasSmallOrder() {
-			return fmt.Errorf("R has small order")
+			return nil, nil, nil, nil, fmt.Errorf("R has small order")
 		}
 	}
 	if s, ok := g.Scalar().(scalarCanCheckCanonical); ok && !s.IsCanonical(sig[pointSize:]) {
-		return fmt.Errorf("signature is not canonical")
+		return nil, nil, nil, nil, fmt.Errorf("signature is not canonical")
 	}
 	if err := s.UnmarshalBinary(sig[pointSize:]); err != nil {
-		return err
+		return nil, nil, nil, nil, err
 	}
 
-	public := g.Point()
-	err := public.UnmarshalBinary(pub)
-	if err != nil {
-		return fmt.Errorf("schnorr: error unmarshalling public key")
+	public = g.Point()
+	if err := public.UnmarshalBinary(pub); err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("schnorr: error unmarshalling public key")
 	}
 	if p, ok := public.(pointCanCheckCanonicalAndSmallOrder); ok {
 		if !p.IsCanonical(pub) {
-			return fmt.Errorf("public key is not canonical")
+			return nil, nil, nil, nil, fmt.Errorf("public key is not canonical")
 		}
 		if p.HasSmallOrder() {
-			return fmt.Errorf("public key has small order")
+			return nil, nil, nil, nil, fmt.Errorf("public key has small order")
 		}
 	}
 	// recompute hash(public || R || msg)
-	h, err := hash(g, public, R, msg)
+	h, err = hash(g, public, R, msg)
 	if err != nil {
-		return err
+		return nil, nil, nil, nil, err
 	}
+	return R, s, public, h, nil
+}
 
-	// compute S = g^s
-	S := g.Point().Mul(s, nil)
-	// compute RAh = R + A^h
-	Ah := g.Point().Mul(h, public)
-	RAs := g.Point().Add(R, Ah)
-
-	if !S.Equal(RAs) {
+// BatchVerify verifies many signatures at once, where sigs[i] is the
+// signature of msgs[i] under publics[i], faster than calling Verify for each
+// of them: with random coefficients zi, it checks the random linear
+// combination (Σ zi*si)*B = Σ zi*Ri + Σ zi*hi*Ai of the equations of the
+// signatures with a single multi-scalar multiplication. It returns an error
+// if any signature is invalid, without telling which one, so that the caller
+// must verify the signatures one by one to find the invalid ones.
+func BatchVerify(g kyber.Group, publics []kyber.Point, msgs, sigs [][]byte) error {
+	if len(msgs) == 0 {
+		return errors.New("schnorr: no signature to verify")
+	}
+	if len(publics) != len(msgs) || len(sigs) != len(msgs) {
+		return errors.New("schnorr: different numbers of public keys, messages and signatures")
+	}
+	// (Σ zi*si)*B - Σ zi*Ri - Σ zi*hi*Ai is the identity for valid signatures
+	points := make([]kyber.Point, 0, 2*len(msgs)+1)
+	scalars := make([]kyber.Scalar, 0, 2*len(msgs)+1)
+	sum := g.Scalar().Zero()
+	rand := random.New()
+	for i := range msgs {
+		pub, err := publics[i].MarshalBinary()
+		if err != nil {
+			return err
+		}
+		R, s, public, h, err := decodeSignature(g, pub, bytes.NewReader(msgs[i]), sigs[i])
+		if err != nil {
+			return err
+		}
+		z := g.Scalar().SetBytes(random.Bits(128, false, rand))
+		sum.Add(sum, s.Mul(z, s))
+		points = append(points, R, public)
+		scalars = append(scalars, g.Scalar().Neg(z), h.Mul(z, h).Neg(h))
+	}
+	points = append(points, g.Point().Base())
+	scalars = append(scalars, sum)
+	res, err := msm.MultiScalarMult(g, points, scalars)
+	if err != nil {
+		return err
+	}
+	if !res.Equal(g.Point().Null()) {
 		return errors.New("schnorr: invalid signature")
 	}
-
 	return nil
-
 }
 
 // Verify verifies a given Schnorr signature. It returns nil iff the
 // given signature is valid.
 func Verify(g kyber.Group, public kyber.Point, msg, sig []byte) error {
+	return VerifyReader(g, public, bytes.NewReader(msg), sig)
+}
+
+// VerifyReader works like Verify on the message read from r until EOF, see
+// SignReader.
+func VerifyReader(g kyber.Group, public kyber.Point, r io.Reader, sig []byte) error {
 	PBuf, err := public.MarshalBinary()
 	if err != nil {
 		return fmt.Errorf("error unmarshalling public key: %s", err)
 	}
-	return VerifyWithChecks(g, PBuf, msg, sig)
+	return verifyWithChecks(g, PBuf, r, sig)
+}
+
+// Scheme is the Schnorr signature scheme over a given suite. Its methods work
+// like the package-level functions.
+type Scheme struct {
+	suite Suite
+}
+
+// NewScheme returns the Schnorr signature scheme over the suite.
+func NewScheme(suite Suite) *Scheme {
+	return &Scheme{suite: suite}
+}
+
+// NewKeyPair returns a new private key and its public key.
+func (s *Scheme) NewKeyPair(random cipher.Stream) (kyber.Scalar, kyber.Point) {
+	private := s.suite.Scalar().Pick(random)
+	return private, s.suite.Point().Mul(private, nil)
+}
+
+// Sign works like the Sign function with the suite of the scheme.
+func (s *Scheme) Sign(private kyber.Scalar, msg []byte) ([]byte, error) {
+	return Sign(s.suite, private, msg)
 }
 
-func hash(g kyber.Group, public, r kyber.Point, msg []byte) (kyber.Scalar, error) {
+// SignDeterministic works like the SignDeterministic function with the suite
+// of the scheme.
+func (s *Scheme) SignDeterministic(private kyber.Scalar, msg []byte) ([]byte, error) {
+	return SignDeterministic(s.suite, private, msg)
+}
+
+// Verify works like the Verify function with the suite of the scheme.
+func (s *Scheme) Verify(public kyber.Point, msg, sig []byte) error {
+	return Verify(s.suite, public, msg, sig)
+}
+
+func hash(g kyber.Group, public, r kyber.Point, msg io.Reader) (kyber.Scalar, error) {
 	h := sha512.New()
 	if _, err := r.MarshalTo(h); err != nil {
 		return nil, err
@@ -149,8 +309,8 @@ func hash(g kyber.Group, public, r kyber.Point, msg []byte) (kyber.Scalar, error
 	if _, err := public.MarshalTo(h); err != nil {
 		return nil, err
 	}
-	if _, err := h.Write(msg); err != nil {
-		return nil, err
+	if _, err := io.Copy(h, msg); err != nil {
+		return nil, fmt.Errorf("schnorr: reading message: %w", err)
 	}
 	return g.Scalar().SetBytes(h.Sum(nil)), nil
 }
@@ -1,13 +1,22 @@
 package schnorr
 
 import (
+	"bytes"
+	"crypto/cipher"
+	"encoding/hex"
+	"errors"
+	"io"
 	"math/rand"
 	"reflect"
 	"testing"
+	"testing/iotest"
 	"testing/quick"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
 	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/group/nist"
 	"go.dedis.ch/kyber/v3/sign/eddsa"
 	"go.dedis.ch/kyber/v3/util/key"
 )
@@ -125,3 +134,173 @@ func TestSchnorrMalleability(t *testing.T) {
 	err = Verify(suite, kp.Public, msg, s)
 	assert.Error(t, err, "schnorr signature malleable")
 }
+
+func BenchmarkSchnorrVerify(b *testing.B) {
+	msg := []byte("Hello Schnorr")
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	kp := key.NewKeyPair(suite)
+	s, err := Sign(suite, kp.Private, msg)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := Verify(suite, kp.Public, msg, s); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// seededSuite picks the same nonces on every signature.
+type seededSuite struct {
+	*edwards25519.SuiteEd25519
+}
+
+func (s seededSuite) RandomStream() cipher.Stream {
+	return s.RandomStreamFromSeed([]byte("schnorr reader"))
+}
+
+// failingReader returns err once its data has been read.
+type failingReader struct {
+	r   io.Reader
+	err error
+}
+
+func (f *failingReader) Read(p []byte) (int, error) {
+	n, err := f.r.Read(p)
+	if err == io.EOF {
+		return n, f.err
+	}
+	return n, err
+}
+
+func TestSchnorrSignReader(t *testing.T) {
+	suite := seededSuite{edwards25519.NewBlakeSHA256Ed25519()}
+	kp := key.NewKeyPair(suite)
+	// larger than the chunks read at once
+	msg := make([]byte, 100000)
+	rand.Read(msg)
+
+	sig, err := Sign(suite, kp.Private, msg)
+	require.NoError(t, err)
+	sigReader, err := SignReader(suite, kp.Private, iotest.HalfReader(bytes.NewReader(msg)))
+	require.NoError(t, err)
+	require.Equal(t, sig, sigReader)
+
+	require.NoError(t, VerifyReader(suite, kp.Public, iotest.OneByteReader(bytes.NewReader(msg[:1000])),
+		mustSign(t, suite, kp, msg[:1000])))
+	require.NoError(t, VerifyReader(suite, kp.Public, bytes.NewReader(msg), sig))
+	require.Error(t, VerifyReader(suite, kp.Public, bytes.NewReader(msg[1:]), sig))
+
+	// errors of the reader are returned instead of a signature of the part
+	// read so far
+	errRead := errors.New("read failure")
+	_, err = SignReader(suite, kp.Private, &failingReader{bytes.NewReader(msg), errRead})
+	require.True(t, errors.Is(err, errRead))
+	err = VerifyReader(suite, kp.Public, &failingReader{bytes.NewReader(msg), errRead}, sig)
+	require.True(t, errors.Is(err, errRead))
+}
+
+func mustSign(t *testing.T, suite Suite, kp *key.Pair, msg []byte) []byte {
+	sig, err := Sign(suite, kp.Private, msg)
+	require.NoError(t, err)
+	return sig
+}
+
+func TestNonceRFC6979(t *testing.T) {
+	// A.2.5 of RFC 6979: P-256 with SHA-512
+	suite := nist.NewBlakeSHA256P256()
+	xb, _ := hex.DecodeString("c9afa9d845ba75166b5c215767b1d6934e50c3db36e89b127b8a622b120f6721")
+	x := suite.Scalar().SetBytes(xb)
+	for msg, exp := range map[string]string{
+		"sample": "5fa81c63109badb88c1f367b47da606da28cad69aa22c4fe6ad7df73a7173aa5",
+		"test":   "6915d11632aca3c40d5d51c08daf9c555933819548784480e93499000d9f0b7f",
+	} {
+		k, err := nonceRFC6979(suite, x, []byte(msg), nil)
+		require.NoError(t, err)
+		require.Equal(t, exp, k.String())
+	}
+}
+
+func TestSchnorrSignDeterministic(t *testing.T) {
+	msg := []byte("Hello Schnorr")
+	for _, suite := range []Suite{edwards25519.NewBlakeSHA256Ed25519(), nist.NewBlakeSHA256P256()} {
+		kp := key.NewKeyPair(suite)
+		scheme := NewScheme(suite)
+
+		s1, err := SignDeterministic(suite, kp.Private, msg)
+		require.NoError(t, err)
+		require.NoError(t, Verify(suite, kp.Public, msg, s1))
+		s2, err := scheme.SignDeterministic(kp.Private, msg)
+		require.NoError(t, err)
+		require.Equal(t, s1, s2)
+		s3, err := SignDeterministic(suite, kp.Private, []byte("Hello Schnorr!"))
+		require.NoError(t, err)
+		require.NotEqual(t, s1[:suite.PointLen()], s3[:suite.PointLen()])
+
+		h1, err := SignHedged(suite, kp.Private, msg)
+		require.NoError(t, err)
+		require.NoError(t, Verify(suite, kp.Public, msg, h1))
+		h2, err := SignHedged(suite, kp.Private, msg)
+		require.NoError(t, err)
+		require.NoError(t, Verify(suite, kp.Public, msg, h2))
+		require.NotEqual(t, h1, h2)
+		require.NotEqual(t, s1, h1)
+	}
+}
+
+func TestSchnorrBatchVerify(t *testing.T) {
+	for _, suite := range []Suite{edwards25519.NewBlakeSHA256Ed25519(), nist.NewBlakeSHA256P256()} {
+		n := 40
+		publics := make([]kyber.Point, n)
+		msgs := make([][]byte, n)
+		sigs := make([][]byte, n)
+		for i := range msgs {
+			kp := key.NewKeyPair(suite)
+			publics[i] = kp.Public
+			msgs[i] = []byte{byte(i)}
+			sigs[i] = mustSign(t, suite, kp, msgs[i])
+		}
+		require.NoError(t, BatchVerify(suite, publics, msgs, sigs))
+
+		bad := append([][]byte{}, msgs...)
+		bad[5] = []byte("another message")
+		require.Error(t, BatchVerify(suite, publics, bad, sigs))
+		require.Error(t, BatchVerify(suite, publics, msgs, sigs[1:]))
+		require.Error(t, BatchVerify(suite, nil, nil, nil))
+	}
+}
+
+func TestSchnorrAdaptor(t *testing.T) {
+	msg := []byte("swap")
+	for _, suite := range []Suite{edwards25519.NewBlakeSHA256Ed25519(), nist.NewBlakeSHA256P256()} {
+		kp := key.NewKeyPair(suite)
+		secret := suite.Scalar().Pick(suite.RandomStream())
+		T := suite.Point().Mul(secret, nil)
+
+		pre, err := PreSign(suite, kp.Private, T, msg)
+		require.NoError(t, err)
+		require.NoError(t, VerifyPreSignature(suite, kp.Public, T, msg, pre))
+		// a pre-signature is not a signature
+		require.Error(t, Verify(suite, kp.Public, msg, pre))
+		require.Error(t, VerifyPreSignature(suite, kp.Public, suite.Point().Base(), msg, pre))
+		require.Error(t, VerifyPreSignature(suite, kp.Public, T, []byte("other"), pre))
+
+		sig, err := Adapt(suite, pre, secret)
+		require.NoError(t, err)
+		require.NoError(t, Verify(suite, kp.Public, msg, sig))
+		extracted, err := ExtractSecret(suite, pre, sig)
+		require.NoError(t, err)
+		require.True(t, secret.Equal(extracted))
+
+		// adapting with another secret gives an invalid signature, and another
+		// signature reveals nothing
+		bad, err := Adapt(suite, pre, suite.Scalar().One())
+		require.NoError(t, err)
+		require.Error(t, Verify(suite, kp.Public, msg, bad))
+		_, err = ExtractSecret(suite, pre, mustSign(t, suite, kp, msg))
+		require.Error(t, err)
+		_, err = ExtractSecret(suite, pre, sig[1:])
+		require.Error(t, err)
+	}
+}
@@ -6,22 +6,114 @@
 // partial (BLS) signatures Si on m using their individual key shares xi which
 // can then be used to recover the full (regular) BLS signature S via Lagrange
 // interpolation. The signature S can be verified with the initially
-// established group key X. Signatures are points on curve G1 and public keys
-// are points on curve G2.
+// established group key X. The package-level functions use the scheme of the
+// package-level functions of kyber/sign/bls: signatures are points on curve G1
+// and public keys are points on curve G2, and the messages are hashed with
+// bls.DefaultDST or with the domain separation tag given to the WithDST
+// functions. A Scheme works on top of any scheme of kyber/sign/bls instead.
+// The recovered signatures follow the format bls.WireVersion.
 package tbls
 
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
 
+	"go.dedis.ch/kyber/v3"
 	"go.dedis.ch/kyber/v3/pairing"
 	"go.dedis.ch/kyber/v3/share"
 	"go.dedis.ch/kyber/v3/sign/bls"
+	"go.dedis.ch/kyber/v3/sign/internal/parallel"
 )
 
+// ErrGroupMismatch is returned when the public polynomial is not over the key
+// group of the scheme, e.g. when it holds the commitments of a DKG run over
+// the signature group. The group of the commitments of a DKG is given by
+// DistKeyShare.Group.
+var ErrGroupMismatch = errors.New("tbls: public polynomial not over the key group")
+
+// ErrWrongIndex is returned by VerifyShareAgainst for a signature share of
+// another index than the public share.
+var ErrWrongIndex = errors.New("tbls: signature share of another index")
+
+// ErrTooFewShares is matched by the TooFewSharesError of RecoverValid with
+// errors.Is.
+var ErrTooFewShares = errors.New("tbls: not enough valid signature shares")
+
+// ShareError is the error of one invalid signature share given to Recover or
+// RecoverX.
+type ShareError struct {
+	// Index is the index of the share for Recover, and its position in the
+	// signatures for RecoverX.
+	Index int
+	Err   error
+}
+
+func (e *ShareError) Error() string {
+	return fmt.Sprintf("tbls: signature share %d: %v", e.Index, e.Err)
+}
+
+func (e *ShareError) Unwrap() error {
+	return e.Err
+}
+
+// ShareErrors is returned by Recover and RecoverX when some of the signature
+// shares they use are invalid. It holds one error per invalid share, in the
+// order of the signatures, whatever the concurrency of the scheme. It unwraps
+// to its first error.
+type ShareErrors []*ShareError
+
+func (e ShareErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e ShareErrors) Unwrap() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e[0]
+}
+
+// TooFewSharesError is returned by RecoverValid when less than the threshold
+// of the signature shares are valid. It unwraps to the ShareErrors of the
+// invalid shares, if any, and matches ErrTooFewShares with errors.Is.
+type TooFewSharesError struct {
+	Valid   int // number of valid shares
+	Needed  int // threshold
+	Invalid ShareErrors
+}
+
+func (e *TooFewSharesError) Error() string {
+	msg := fmt.Sprintf("tbls: %d valid signature shares out of the %d needed", e.Valid, e.Needed)
+	if len(e.Invalid) > 0 {
+		msg += ": " + e.Invalid.Error()
+	}
+	return msg
+}
+
+func (e *TooFewSharesError) Unwrap() error {
+	if len(e.Invalid) == 0 {
+		return nil
+	}
+	return e.Invalid
+}
+
+// Is returns true for ErrTooFewShares.
+func (e *TooFewSharesError) Is(target error) bool {
+	return target == ErrTooFewShares
+}
+
 // SigShare encodes a threshold BLS signature share Si = i || v where the 2-byte
 // big-endian value i corresponds to the share's index and v represents the
-// share's value. The signature share Si is a point on curve G1.
+// share's value. The signature share Si is a point of the signature group.
 type SigShare []byte
 
 // Index returns the index i of the TBLS share Si.
@@ -40,21 +132,86 @@ func (s *SigShare) Value() []byte {
 	return []byte(*s)[2:]
 }
 
+// Scheme is the threshold version of a BLS signature scheme.
+type Scheme struct {
+	bls *bls.Scheme
+	// maximum number of goroutines verifying the signature shares, see
+	// WithConcurrency
+	concurrency int
+}
+
+// NewScheme returns the threshold version of the given BLS scheme. The public
+// polynomials must have their commitments in the key group of the scheme.
+func NewScheme(scheme *bls.Scheme) *Scheme {
+	return &Scheme{bls: scheme}
+}
+
+// NewSchemeOnG1 returns the threshold version of bls.NewSchemeOnG1.
+func NewSchemeOnG1(suite pairing.Suite) *Scheme {
+	return NewScheme(bls.NewSchemeOnG1(suite))
+}
+
+// NewSchemeOnG2 returns the threshold version of bls.NewSchemeOnG2.
+func NewSchemeOnG2(suite pairing.Suite) *Scheme {
+	return NewScheme(bls.NewSchemeOnG2(suite))
+}
+
+// WithConcurrency returns a copy of the scheme verifying the signature shares
+// of Recover and RecoverX, which costs two pairings per share, over at most n
+// goroutines, and at most runtime.GOMAXPROCS of them. The underlying BLS
+// scheme gets the same concurrency. By default, or if n is at most 1, the
+// scheme verifies the shares one after the other in the calling goroutine.
+// The signature, or the error, does not depend on n.
+func (s *Scheme) WithConcurrency(n int) *Scheme {
+	c := *s
+	c.bls = s.bls.WithConcurrency(n)
+	c.concurrency = n
+	return &c
+}
+
+// WithDST returns a copy of the scheme whose underlying BLS scheme hashes the
+// messages with the given domain separation tag, see bls.Scheme.WithDST.
+func (s *Scheme) WithDST(dst []byte) *Scheme {
+	c := *s
+	c.bls = s.bls.WithDST(dst)
+	return &c
+}
+
+// WithHashToCurve returns a copy of the scheme whose underlying BLS scheme
+// hashes the messages with the hash_to_curve function of RFC 9380 and the
+// given domain separation tag, see bls.Scheme.WithHashToCurve.
+func (s *Scheme) WithHashToCurve(dst []byte) *Scheme {
+	c := *s
+	c.bls = s.bls.WithHashToCurve(dst)
+	return &c
+}
+
+// BLS returns the underlying BLS scheme, whose public keys verify the
+// recovered signatures.
+func (s *Scheme) BLS() *bls.Scheme {
+	return s.bls
+}
+
+// legacy returns the scheme of the package-level functions.
+func legacy(suite pairing.Suite) *Scheme {
+	return NewScheme(bls.NewSchemeOnG1(suite).WithDST(bls.DefaultDST))
+}
+
+// legacyWithDST returns the scheme of the WithDST functions.
+func legacyWithDST(suite pairing.Suite, dst []byte) *Scheme {
+	return NewScheme(bls.NewSchemeOnG1(suite).WithDST(dst))
+}
+
 // Sign creates a threshold BLS signature Si = xi * H(m) on the given message m
 // using the provided secret key share xi.
 func Sign(suite pairing.Suite, private *share.PriShare, msg []byte) ([]byte, error) {
-	buf := new(bytes.Buffer)
-	if err := binary.Write(buf, binary.BigEndian, uint16(private.I)); err != nil {
-		return nil, err
-	}
-	s, err := bls.Sign(suite, private.V, msg)
-	if err != nil {
-		return nil, err
-	}
-	if err := binary.Write(buf, binary.BigEndian, s); err != nil {
-		return nil, err
-	}
-	return buf.Bytes(), nil
+	return legacy(suite).Sign(private, msg)
+}
+
+// SignWithDST works like Sign but hashes the message with the given domain
+// separation tag, see bls.SignWithDST.
+func SignWithDST(suite pairing.Suite, private *share.PriShare, dst, msg []byte) ([]byte, error) {
+	return legacyWithDST(suite, dst).Sign(private, msg)
 }
 
 // Verify checks the given threshold BLS signature Si on the message m using
@@ -62,12 +219,42 @@ func Sign(suite pairing.Suite, private *share.PriShare, msg []byte) ([]byte, err
 // public key share Xi can be computed by evaluating the public sharing
 // polynonmial at the share's index i.
 func Verify(suite pairing.Suite, public *share.PubPoly, msg, sig []byte) error {
-	s := SigShare(sig)
-	i, err := s.Index()
-	if err != nil {
-		return err
-	}
-	return bls.Verify(suite, public.Eval(i).V, msg, s.Value())
+	return legacy(suite).Verify(public, msg, sig)
+}
+
+// VerifyWithDST works like Verify for a signature share created by
+// SignWithDST with the given domain separation tag.
+func VerifyWithDST(suite pairing.Suite, public *share.PubPoly, dst, msg, sig []byte) error {
+	return legacyWithDST(suite, dst).Verify(public, msg, sig)
+}
+
+// VerifyPartial checks the signature share Si on the message m against the
+// public polynomial, like Verify, e.g. for a coordinator to check each share
+// as it arrives and to blame the signer of an invalid one before recovering
+// the signature.
+func VerifyPartial(suite pairing.Suite, public *share.PubPoly, msg, sig []byte) error {
+	return legacy(suite).VerifyPartial(public, msg, sig)
+}
+
+// VerifyShareAgainst checks the given threshold BLS signature Si on the
+// message m against the public key share Xi directly, e.g. the one returned
+// by DistKeyShare.PublicShare of kyber/share/dkg/pedersen, instead of
+// evaluating the public polynomial. The index of the signature share must be
+// the one of the public key share.
+func VerifyShareAgainst(suite pairing.Suite, public *share.PubShare, msg, sig []byte) error {
+	return legacy(suite).VerifyShareAgainst(public, msg, sig)
+}
+
+// SignReader works like Sign on the message read from r until EOF, see
+// bls.SignReader.
+func SignReader(suite pairing.Suite, private *share.PriShare, r io.Reader) ([]byte, error) {
+	return legacy(suite).SignReader(private, r)
+}
+
+// VerifyReader works like Verify on the message read from r until EOF, see
+// bls.SignReader.
+func VerifyReader(suite pairing.Suite, public *share.PubPoly, r io.Reader, sig []byte) error {
+	return legacy(suite).VerifyReader(public, r, sig)
 }
 
 // Recover reconstructs the full BLS signature S = x * H(m) from a threshold t
@@ -76,32 +263,323 @@ func Verify(suite pairing.Suite, public *share.PubPoly, msg, sig []byte) error {
 // shared public key X. The shared public key can be computed by evaluating the
 // public sharing polynomial at index 0.
 func Recover(suite pairing.Suite, public *share.PubPoly, msg []byte, sigs [][]byte, t, n int) ([]byte, error) {
-	pubShares := make([]*share.PubShare, 0)
-	for _, sig := range sigs {
-		s := SigShare(sig)
-		i, err := s.Index()
+	return legacy(suite).Recover(public, msg, sigs, t, n)
+}
+
+// RecoverValid works like Recover, except that it skips the invalid signature
+// shares instead of failing, and returns the indexes of their signers, see
+// Scheme.RecoverValid.
+func RecoverValid(suite pairing.Suite, public *share.PubPoly, msg []byte, sigs [][]byte, t, n int) ([]byte, []int, error) {
+	return legacy(suite).RecoverValid(public, msg, sigs, t, n)
+}
+
+// RecoverWithDST works like Recover for the signature shares created by
+// SignWithDST with the given domain separation tag. The full signature
+// verifies with bls.VerifyWithDST and the same tag.
+func RecoverWithDST(suite pairing.Suite, public *share.PubPoly, dst, msg []byte, sigs [][]byte, t, n int) ([]byte, error) {
+	return legacyWithDST(suite, dst).Recover(public, msg, sigs, t, n)
+}
+
+// RecoverX reconstructs the full BLS signature S = x * H(m) from a threshold t
+// of regular BLS signatures Si = xi * H(m) issued with key shares evaluated at
+// arbitrary points, e.g. produced by a DKG run with hashed indices. The
+// signature sigs[i] must have been issued with the share evaluated at xs[i].
+// Each signature is verified against the public key share p(xs[i]) before
+// being used, and the signatures of an evaluation point already given are
+// ignored.
+func RecoverX(suite pairing.Suite, public *share.PubPoly, msg []byte, xs []kyber.Scalar, sigs [][]byte, t int) ([]byte, error) {
+	return legacy(suite).RecoverX(public, msg, xs, sigs, t)
+}
+
+// Sign works like the Sign function with the scheme.
+func (s *Scheme) Sign(private *share.PriShare, msg []byte) ([]byte, error) {
+	sig, err := s.bls.Sign(private.V, msg)
+	if err != nil {
+		return nil, err
+	}
+	return sigShare(private, sig)
+}
+
+// SignReader works like the SignReader function with the scheme.
+func (s *Scheme) SignReader(private *share.PriShare, r io.Reader) ([]byte, error) {
+	sig, err := s.bls.SignReader(private.V, r)
+	if err != nil {
+		return nil, err
+	}
+	return sigShare(private, sig)
+}
+
+// sigShare prefixes the signature by the index of the share.
+func sigShare(private *share.PriShare, sig []byte) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := binary.Write(buf, binary.BigEndian, uint16(private.I)); err != nil {
+		return nil, err
+	}
+	if err := binary.Write(buf, binary.BigEndian, sig); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Verify works like the Verify function with the scheme.
+func (s *Scheme) Verify(public *share.PubPoly, msg, sig []byte) error {
+	if err := s.checkGroup(public); err != nil {
+		return err
+	}
+	sh := SigShare(sig)
+	i, err := sh.Index()
+	if err != nil {
+		return err
+	}
+	return s.bls.Verify(public.Eval(i).V, msg, sh.Value())
+}
+
+// VerifyShareAgainst works like the VerifyShareAgainst function with the
+// scheme.
+func (s *Scheme) VerifyShareAgainst(public *share.PubShare, msg, sig []byte) error {
+	key := s.bls.KeyGroup()
+	if reflect.TypeOf(public.V) != reflect.TypeOf(key.Point()) {
+		return fmt.Errorf("%w: public share is not a point of %s", ErrGroupMismatch, key)
+	}
+	sh := SigShare(sig)
+	i, err := sh.Index()
+	if err != nil {
+		return err
+	}
+	if i != public.I {
+		return fmt.Errorf("%w: %d instead of %d", ErrWrongIndex, i, public.I)
+	}
+	return s.bls.Verify(public.V, msg, sh.Value())
+}
+
+// VerifyPartial is an alias of Verify, which checks a signature share.
+func (s *Scheme) VerifyPartial(public *share.PubPoly, msg, sig []byte) error {
+	return s.Verify(public, msg, sig)
+}
+
+// VerifyRecovered checks a signature returned by Recover against the
+// distributed public key, which is the commitment of the public polynomial.
+func (s *Scheme) VerifyRecovered(public kyber.Point, msg, sig []byte) error {
+	return s.bls.Verify(public, msg, sig)
+}
+
+// VerifyReader works like the VerifyReader function with the scheme.
+func (s *Scheme) VerifyReader(public *share.PubPoly, r io.Reader, sig []byte) error {
+	if err := s.checkGroup(public); err != nil {
+		return err
+	}
+	sh := SigShare(sig)
+	i, err := sh.Index()
+	if err != nil {
+		return err
+	}
+	return s.bls.VerifyReader(public.Eval(i).V, r, sh.Value())
+}
+
+// Recover works like the Recover function with the scheme. Only the first t
+// signature shares are used, and they must all be valid: if some are not,
+// Recover returns ShareErrors.
+func (s *Scheme) Recover(public *share.PubPoly, msg []byte, sigs [][]byte, t, n int) ([]byte, error) {
+	if err := s.checkGroup(public); err != nil {
+		return nil, err
+	}
+	if t >= 0 && len(sigs) > t {
+		sigs = sigs[:t]
+	}
+	indices := make([]int, len(sigs))
+	for k, sig := range sigs {
+		sh := SigShare(sig)
+		i, err := sh.Index()
 		if err != nil {
 			return nil, err
 		}
-		if err = bls.Verify(suite, public.Eval(i).V, msg, s.Value()); err != nil {
-			return nil, err
+		indices[k] = i
+	}
+	points, err := s.verifyShares(len(sigs), func(k int) (kyber.Point, error) {
+		sh := SigShare(sigs[k])
+		return s.verifyShare(public.Eval(indices[k]).V, msg, sh.Value())
+	}, func(k int) int { return indices[k] })
+	if err != nil {
+		return nil, err
+	}
+	// one backing array for the shares instead of one allocation each
+	values := make([]share.PubShare, len(points))
+	pubShares := make([]*share.PubShare, len(points))
+	for k, point := range points {
+		values[k] = share.PubShare{I: indices[k], V: point}
+		pubShares[k] = &values[k]
+	}
+	commit, err := share.RecoverCommit(s.bls.SignatureGroup(), pubShares, t, n)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := commit.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// RecoverValid works like Recover, except that it skips the invalid signature
+// shares instead of failing: it recovers the signature from the first t valid
+// shares of sigs, and returns the indexes of the invalid shares it met along
+// the way, so that the caller can blame and exclude their signers. It only
+// verifies as many shares as needed to find t valid ones, in the order of
+// sigs. The shares too short to hold an index, and the shares of an index
+// which was already verified, are skipped without being verified. If there
+// are fewer than t valid shares, it returns the indexes of the invalid ones
+// along with a TooFewSharesError wrapping their ShareErrors.
+func (s *Scheme) RecoverValid(public *share.PubPoly, msg []byte, sigs [][]byte, t, n int) ([]byte, []int, error) {
+	if err := s.checkGroup(public); err != nil {
+		return nil, nil, err
+	}
+	var pubShares []*share.PubShare
+	var failed ShareErrors
+	seen := make(map[int]bool)
+	for len(pubShares) < t && len(sigs) > 0 {
+		// verify just enough shares to reach t if they are all valid
+		var indices []int
+		var values [][]byte
+		for len(sigs) > 0 && len(indices) < t-len(pubShares) {
+			sh := SigShare(sigs[0])
+			sigs = sigs[1:]
+			i, err := sh.Index()
+			if err != nil || seen[i] {
+				continue
+			}
+			seen[i] = true
+			indices = append(indices, i)
+			values = append(values, sh.Value())
 		}
-		point := suite.G1().Point()
-		if err := point.UnmarshalBinary(s.Value()); err != nil {
-			return nil, err
+		points, errs := s.verifyEach(len(indices), func(k int) (kyber.Point, error) {
+			return s.verifyShare(public.Eval(indices[k]).V, msg, values[k])
+		})
+		for k, i := range indices {
+			if errs[k] != nil {
+				failed = append(failed, &ShareError{Index: i, Err: errs[k]})
+				continue
+			}
+			pubShares = append(pubShares, &share.PubShare{I: i, V: points[k]})
 		}
-		pubShares = append(pubShares, &share.PubShare{I: i, V: point})
-		if len(pubShares) >= t {
-			break
+	}
+	invalid := make([]int, len(failed))
+	for k, err := range failed {
+		invalid[k] = err.Index
+	}
+	if len(pubShares) < t {
+		return nil, invalid, &TooFewSharesError{Valid: len(pubShares), Needed: t, Invalid: failed}
+	}
+	commit, err := share.RecoverCommit(s.bls.SignatureGroup(), pubShares, t, n)
+	if err != nil {
+		return nil, invalid, err
+	}
+	sig, err := commit.MarshalBinary()
+	if err != nil {
+		return nil, invalid, err
+	}
+	return sig, invalid, nil
+}
+
+// RecoverX works like the RecoverX function with the scheme. Only the first
+// signature of each evaluation point is used, and only the first t of them,
+// which must all be valid: if some are not, RecoverX returns ShareErrors.
+func (s *Scheme) RecoverX(public *share.PubPoly, msg []byte, xs []kyber.Scalar, sigs [][]byte, t int) ([]byte, error) {
+	if len(xs) != len(sigs) {
+		return nil, errors.New("tbls: need one evaluation point per signature")
+	}
+	if err := s.checkGroup(public); err != nil {
+		return nil, err
+	}
+	// positions of the first signature of each evaluation point, so that
+	// the duplicates do not count towards t
+	var pos []int
+	seen := make(map[string]bool, len(xs))
+	for k, x := range xs {
+		if x == nil {
+			return nil, errors.New("tbls: nil evaluation point")
 		}
+		if key := x.String(); !seen[key] {
+			seen[key] = true
+			pos = append(pos, k)
+		}
+	}
+	if t >= 0 && len(pos) > t {
+		pos = pos[:t]
 	}
-	commit, err := share.RecoverCommit(suite.G1(), pubShares, t, n)
+	points, err := s.verifyShares(len(pos), func(k int) (kyber.Point, error) {
+		return s.verifyShare(public.EvalScalar(xs[pos[k]]).V, msg, sigs[pos[k]])
+	}, func(k int) int { return pos[k] })
 	if err != nil {
 		return nil, err
 	}
-	sig, err := commit.MarshalBinary()
+	values := make([]share.PubXShare, len(points))
+	pubShares := make([]*share.PubXShare, len(points))
+	for k, point := range points {
+		values[k] = share.PubXShare{X: xs[pos[k]], V: point}
+		pubShares[k] = &values[k]
+	}
+	commit, err := share.RecoverCommitFromXShares(s.bls.SignatureGroup(), pubShares, t)
 	if err != nil {
 		return nil, err
 	}
-	return sig, nil
+	return commit.MarshalBinary()
+}
+
+// verifyShares calls verify for each of the count signature shares, spread
+// over the goroutines of the scheme, and returns the points they return in
+// order, or ShareErrors with the given index of each failing share.
+func (s *Scheme) verifyShares(count int, verify func(k int) (kyber.Point, error), index func(k int) int) ([]kyber.Point, error) {
+	points, errs := s.verifyEach(count, verify)
+	var failed ShareErrors
+	for k, err := range errs {
+		if err != nil {
+			failed = append(failed, &ShareError{Index: index(k), Err: err})
+		}
+	}
+	if failed != nil {
+		return nil, failed
+	}
+	return points, nil
+}
+
+// verifyEach calls verify for each of the count signature shares, spread over
+// the goroutines of the scheme, and returns their points and errors in order.
+func (s *Scheme) verifyEach(count int, verify func(k int) (kyber.Point, error)) ([]kyber.Point, []error) {
+	points := make([]kyber.Point, count)
+	errs := make([]error, count)
+	parallel.ForEach(count, s.concurrency, func(k int) {
+		points[k], errs[k] = verify(k)
+	})
+	return points, errs
+}
+
+// verifyShare checks the signature share against the public key share and
+// returns its point.
+func (s *Scheme) verifyShare(public kyber.Point, msg, sig []byte) (kyber.Point, error) {
+	if err := s.bls.Verify(public, msg, sig); err != nil {
+		return nil, err
+	}
+	point := s.bls.SignatureGroup().Point()
+	if err := point.UnmarshalBinary(sig); err != nil {
+		return nil, err
+	}
+	return point, nil
+}
+
+// checkGroup returns ErrGroupMismatch if the public polynomial is not over the
+// key group of the scheme, or if its commitments are not points of the key
+// group, instead of mixing up the groups when evaluating it.
+func (s *Scheme) checkGroup(public *share.PubPoly) error {
+	key := s.bls.KeyGroup()
+	want := reflect.TypeOf(key.Point())
+	if reflect.TypeOf(public.Group().Point()) != want {
+		return fmt.Errorf("%w: polynomial over %s instead of %s", ErrGroupMismatch, public.Group(), key)
+	}
+	_, commits := public.Info()
+	for i, c := range commits {
+		if reflect.TypeOf(c) != want {
+			return fmt.Errorf("%w: commitment %d is not a point of %s", ErrGroupMismatch, i, key)
+		}
+	}
+	return nil
 }
@@ -1,9 +1,15 @@
 package tbls
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"runtime"
 	"testing"
+	"testing/iotest"
 
 	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
 	"go.dedis.ch/kyber/v3/pairing/bn256"
 	"go.dedis.ch/kyber/v3/share"
 	"go.dedis.ch/kyber/v3/sign/bls"
@@ -29,3 +35,358 @@ func TestTBLS(test *testing.T) {
 	err = bls.Verify(suite, pubPoly.Commit(), msg, sig)
 	require.Nil(test, err)
 }
+
+func TestTBLSVerifyShareAgainst(test *testing.T) {
+	msg := []byte("Hello threshold Boneh-Lynn-Shacham")
+	suite := bn256.NewSuite()
+	n := 10
+	t := n/2 + 1
+	priPoly := share.NewPriPoly(suite.G2(), t, nil, suite.RandomStream())
+	pubShares := priPoly.Commit(nil).Shares(n)
+	for i, x := range priPoly.Shares(n) {
+		sig, err := Sign(suite, x, msg)
+		require.NoError(test, err)
+		require.NoError(test, VerifyShareAgainst(suite, pubShares[i], msg, sig))
+		require.Error(test, VerifyShareAgainst(suite, pubShares[i], []byte("other"), sig))
+		err = VerifyShareAgainst(suite, pubShares[(i+1)%n], msg, sig)
+		require.True(test, errors.Is(err, ErrWrongIndex))
+	}
+
+	sig, err := Sign(suite, priPoly.Shares(n)[0], msg)
+	require.NoError(test, err)
+	wrongGroup := &share.PubShare{I: 0, V: suite.G1().Point().Mul(priPoly.Secret(), nil)}
+	err = VerifyShareAgainst(suite, wrongGroup, msg, sig)
+	require.True(test, errors.Is(err, ErrGroupMismatch))
+}
+
+func TestTBLSDST(test *testing.T) {
+	msg := []byte("Hello threshold Boneh-Lynn-Shacham")
+	dstA, dstB := []byte("PROTOCOL_A_"), []byte("PROTOCOL_B_")
+	suite := bn256.NewSuite()
+	n := 10
+	t := n/2 + 1
+	secret := suite.G1().Scalar().Pick(suite.RandomStream())
+	priPoly := share.NewPriPoly(suite.G2(), t, secret, suite.RandomStream())
+	pubPoly := priPoly.Commit(suite.G2().Point().Base())
+	sigShares := make([][]byte, 0)
+	for _, x := range priPoly.Shares(n) {
+		sig, err := SignWithDST(suite, x, dstA, msg)
+		require.NoError(test, err)
+		require.NoError(test, VerifyWithDST(suite, pubPoly, dstA, msg, sig))
+		require.Error(test, VerifyWithDST(suite, pubPoly, dstB, msg, sig))
+		require.Error(test, Verify(suite, pubPoly, msg, sig))
+		sigShares = append(sigShares, sig)
+	}
+	_, err := RecoverWithDST(suite, pubPoly, dstB, msg, sigShares, t, n)
+	require.Error(test, err)
+	_, err = Recover(suite, pubPoly, msg, sigShares, t, n)
+	require.Error(test, err)
+	sig, err := RecoverWithDST(suite, pubPoly, dstA, msg, sigShares, t, n)
+	require.NoError(test, err)
+	require.NoError(test, bls.VerifyWithDST(suite, pubPoly.Commit(), dstA, msg, sig))
+	require.Error(test, bls.VerifyWithDST(suite, pubPoly.Commit(), dstB, msg, sig))
+	require.Error(test, bls.Verify(suite, pubPoly.Commit(), msg, sig))
+}
+
+func TestTBLSHashToCurve(test *testing.T) {
+	msg := []byte("Hello threshold Boneh-Lynn-Shacham")
+	dst := []byte("BLS_SIG_BN256G1_XMD:SHA-256_SVDW_RO_NUL_")
+	suite := bn256.NewSuite()
+	scheme := NewSchemeOnG1(suite).WithHashToCurve(dst)
+	n := 5
+	t := 3
+	priPoly := share.NewPriPoly(suite.G2(), t, nil, suite.RandomStream())
+	pubPoly := priPoly.Commit(nil)
+	var sigShares [][]byte
+	for _, x := range priPoly.Shares(n) {
+		sig, err := scheme.Sign(x, msg)
+		require.NoError(test, err)
+		require.NoError(test, scheme.Verify(pubPoly, msg, sig))
+		require.Error(test, NewSchemeOnG1(suite).WithDST(dst).Verify(pubPoly, msg, sig))
+		sigShares = append(sigShares, sig)
+	}
+	sig, err := scheme.Recover(pubPoly, msg, sigShares, t, n)
+	require.NoError(test, err)
+	require.NoError(test, bls.NewSchemeOnG1(suite).WithHashToCurve(dst).Verify(pubPoly.Commit(), msg, sig))
+	require.NoError(test, scheme.BLS().Verify(pubPoly.Commit(), msg, sig))
+	require.Error(test, bls.NewSchemeOnG1(suite).Verify(pubPoly.Commit(), msg, sig))
+}
+
+func TestTBLSGroupMismatch(test *testing.T) {
+	msg := []byte("Hello threshold Boneh-Lynn-Shacham")
+	suite := bn256.NewSuite()
+	n := 10
+	t := n/2 + 1
+	// distributed key on G1, as given by a DKG run over pairing.G1Suite
+	secret := suite.G1().Scalar().Pick(suite.RandomStream())
+	priPoly := share.NewPriPoly(suite.G1(), t, secret, suite.RandomStream())
+	pubPoly := priPoly.Commit(nil)
+	onG2 := NewSchemeOnG2(suite)
+	sigShares := make([][]byte, 0)
+	for _, x := range priPoly.Shares(n) {
+		sig, err := onG2.Sign(x, msg)
+		require.NoError(test, err)
+		sigShares = append(sigShares, sig)
+	}
+
+	// the package-level functions expect the public keys on G2
+	_, err := Recover(suite, pubPoly, msg, sigShares, t, n)
+	require.True(test, errors.Is(err, ErrGroupMismatch))
+	require.Contains(test, err.Error(), suite.G1().String())
+	require.True(test, errors.Is(Verify(suite, pubPoly, msg, sigShares[0]), ErrGroupMismatch))
+	// a polynomial over G2 wrongly holding the commitments on G1
+	_, commits := pubPoly.Info()
+	_, err = Recover(suite, share.NewPubPoly(suite.G2(), nil, commits), msg, sigShares, t, n)
+	require.True(test, errors.Is(err, ErrGroupMismatch))
+
+	sig, err := onG2.Recover(pubPoly, msg, sigShares, t, n)
+	require.NoError(test, err)
+	require.NoError(test, bls.NewSchemeOnG2(suite).Verify(pubPoly.Commit(), msg, sig))
+}
+
+func TestTBLSX(test *testing.T) {
+	msg := []byte("Hello threshold Boneh-Lynn-Shacham")
+	suite := bn256.NewSuite()
+	n := 10
+	t := n/2 + 1
+	secret := suite.G1().Scalar().Pick(suite.RandomStream())
+	priPoly := share.NewPriPoly(suite.G2(), t, secret, suite.RandomStream())
+	pubPoly := priPoly.Commit(suite.G2().Point().Base())
+	xs := make([]kyber.Scalar, n)
+	sigs := make([][]byte, n)
+	for i := range xs {
+		xs[i] = suite.G2().Scalar().Pick(suite.RandomStream())
+		sig, err := bls.Sign(suite, priPoly.EvalScalar(xs[i]).V, msg)
+		require.Nil(test, err)
+		sigs[i] = sig
+	}
+	sig, err := RecoverX(suite, pubPoly, msg, xs[n-t:], sigs[n-t:], t)
+	require.Nil(test, err)
+	require.Nil(test, bls.Verify(suite, pubPoly.Commit(), msg, sig))
+
+	// a signature under the wrong evaluation point is rejected
+	_, err = RecoverX(suite, pubPoly, msg, xs[1:t+1], sigs[:t], t)
+	require.Error(test, err)
+	_, err = RecoverX(suite, pubPoly, msg, xs[:t-1], sigs[:t-1], t)
+	require.Error(test, err)
+
+	// the copies of a signature do not count towards t
+	dupXs := append([]kyber.Scalar{xs[0], xs[0]}, xs[1:t]...)
+	dupSigs := append([][]byte{sigs[0], sigs[0]}, sigs[1:t]...)
+	sig, err = RecoverX(suite, pubPoly, msg, dupXs, dupSigs, t)
+	require.Nil(test, err)
+	require.Nil(test, bls.Verify(suite, pubPoly.Commit(), msg, sig))
+	_, err = RecoverX(suite, pubPoly, msg, dupXs[:t], dupSigs[:t], t)
+	require.Error(test, err)
+}
+
+func TestTBLSSchemes(test *testing.T) {
+	msg := []byte("Hello threshold Boneh-Lynn-Shacham")
+	suite := bn256.NewSuite()
+	n := 10
+	t := n/2 + 1
+	for _, scheme := range []*bls.Scheme{bls.NewSchemeOnG1(suite), bls.NewSchemeOnG2(suite)} {
+		tscheme := NewScheme(scheme)
+		keyGroup := scheme.KeyGroup()
+		secret := keyGroup.Scalar().Pick(suite.RandomStream())
+		priPoly := share.NewPriPoly(keyGroup, t, secret, suite.RandomStream())
+		pubPoly := priPoly.Commit(keyGroup.Point().Base())
+		sigShares := make([][]byte, 0)
+		for _, x := range priPoly.Shares(n) {
+			sig, err := tscheme.Sign(x, msg)
+			require.Nil(test, err)
+			require.Nil(test, tscheme.Verify(pubPoly, msg, sig))
+			sigShares = append(sigShares, sig)
+		}
+		sig, err := tscheme.Recover(pubPoly, msg, sigShares, t, n)
+		require.Nil(test, err)
+		require.Nil(test, scheme.Verify(pubPoly.Commit(), msg, sig))
+
+		// the package-level functions do not accept the signature shares
+		_, err = Recover(suite, pubPoly, msg, sigShares, t, n)
+		require.Error(test, err)
+	}
+}
+
+func TestTBLSSignReader(test *testing.T) {
+	msg := bytes.Repeat([]byte("Hello threshold Boneh-Lynn-Shacham"), 3000)
+	suite := bn256.NewSuite()
+	n := 10
+	t := n/2 + 1
+	secret := suite.G1().Scalar().Pick(suite.RandomStream())
+	priPoly := share.NewPriPoly(suite.G2(), t, secret, suite.RandomStream())
+	pubPoly := priPoly.Commit(suite.G2().Point().Base())
+	for _, x := range priPoly.Shares(n) {
+		sig, err := Sign(suite, x, msg)
+		require.Nil(test, err)
+		sigReader, err := SignReader(suite, x, iotest.HalfReader(bytes.NewReader(msg)))
+		require.Nil(test, err)
+		require.Equal(test, sig, sigReader)
+		require.Nil(test, VerifyReader(suite, pubPoly, iotest.OneByteReader(bytes.NewReader(msg)), sig))
+		require.Error(test, VerifyReader(suite, pubPoly, bytes.NewReader(msg[1:]), sig))
+	}
+
+	for _, scheme := range []*bls.Scheme{bls.NewSchemeOnG1(suite), bls.NewSchemeOnG2(suite)} {
+		tscheme := NewScheme(scheme)
+		keyGroup := scheme.KeyGroup()
+		secret := keyGroup.Scalar().Pick(suite.RandomStream())
+		priPoly := share.NewPriPoly(keyGroup, t, secret, suite.RandomStream())
+		pubPoly := priPoly.Commit(keyGroup.Point().Base())
+		x := priPoly.Shares(n)[0]
+		sig, err := tscheme.Sign(x, msg)
+		require.Nil(test, err)
+		sigReader, err := tscheme.SignReader(x, bytes.NewReader(msg))
+		require.Nil(test, err)
+		require.Equal(test, sig, sigReader)
+		require.Nil(test, tscheme.VerifyReader(pubPoly, bytes.NewReader(msg), sig))
+
+		// errors of the reader are returned
+		_, err = tscheme.SignReader(x, iotest.TimeoutReader(iotest.OneByteReader(bytes.NewReader(msg))))
+		require.True(test, errors.Is(err, iotest.ErrTimeout))
+		err = tscheme.VerifyReader(pubPoly, iotest.TimeoutReader(iotest.OneByteReader(bytes.NewReader(msg))), sig)
+		require.True(test, errors.Is(err, iotest.ErrTimeout))
+	}
+}
+
+func TestTBLSConcurrency(test *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	msg := []byte("Hello threshold Boneh-Lynn-Shacham")
+	suite := bn256.NewSuite()
+	n := 10
+	t := n/2 + 1
+	priPoly := share.NewPriPoly(suite.G2(), t, nil, suite.RandomStream())
+	pubPoly := priPoly.Commit(nil)
+	xs := make([]kyber.Scalar, n)
+	sigShares := make([][]byte, n)
+	sigs := make([][]byte, n)
+	// the shares 1 and 3 of other are on another message, and the share 8 is
+	// not used
+	otherShares := make([][]byte, n)
+	other := make([][]byte, n)
+	for i, x := range priPoly.Shares(n) {
+		var err error
+		sigShares[i], err = Sign(suite, x, msg)
+		require.NoError(test, err)
+		xs[i] = suite.G2().Scalar().Pick(suite.RandomStream())
+		sigs[i], err = bls.Sign(suite, priPoly.EvalScalar(xs[i]).V, msg)
+		require.NoError(test, err)
+		otherShares[i], other[i] = sigShares[i], sigs[i]
+		if i == 1 || i == 3 {
+			otherShares[i], err = Sign(suite, x, []byte("other"))
+			require.NoError(test, err)
+			other[i], err = bls.Sign(suite, priPoly.EvalScalar(xs[i]).V, []byte("other"))
+			require.NoError(test, err)
+		}
+	}
+	otherShares[8], other[8] = nil, nil
+
+	want, err := Recover(suite, pubPoly, msg, sigShares, t, n)
+	require.NoError(test, err)
+	wantX, err := RecoverX(suite, pubPoly, msg, xs, sigs, t)
+	require.NoError(test, err)
+	require.Equal(test, want, wantX)
+	for _, workers := range []int{0, 1, 2, 4, 16} {
+		scheme := legacy(suite).WithConcurrency(workers)
+		sig, err := scheme.Recover(pubPoly, msg, sigShares, t, n)
+		require.NoError(test, err)
+		require.Equal(test, want, sig)
+		sig, err = scheme.RecoverX(pubPoly, msg, xs, sigs, t)
+		require.NoError(test, err)
+		require.Equal(test, want, sig)
+
+		_, err = scheme.Recover(pubPoly, msg, otherShares, t, n)
+		var shareErrs ShareErrors
+		require.True(test, errors.As(err, &shareErrs))
+		require.Len(test, shareErrs, 2)
+		require.Equal(test, 1, shareErrs[0].Index)
+		require.Equal(test, 3, shareErrs[1].Index)
+		require.Equal(test, shareErrs[0].Err, errors.Unwrap(errors.Unwrap(err)))
+
+		_, err = scheme.RecoverX(pubPoly, msg, xs, other, t)
+		require.True(test, errors.As(err, &shareErrs))
+		require.Len(test, shareErrs, 2)
+		require.Equal(test, 1, shareErrs[0].Index)
+		require.Equal(test, 3, shareErrs[1].Index)
+	}
+}
+
+func TestTBLSRecoverValid(test *testing.T) {
+	msg := []byte("Hello threshold Boneh-Lynn-Shacham")
+	suite := bn256.NewSuite()
+	n := 10
+	t := n/2 + 1
+	priPoly := share.NewPriPoly(suite.G2(), t, nil, suite.RandomStream())
+	pubPoly := priPoly.Commit(nil)
+	sigShares := make([][]byte, n)
+	for i, x := range priPoly.Shares(n) {
+		var err error
+		if i == 0 || i == 2 {
+			sigShares[i], err = Sign(suite, x, []byte("other"))
+		} else {
+			sigShares[i], err = Sign(suite, x, msg)
+		}
+		require.NoError(test, err)
+	}
+	require.Error(test, VerifyPartial(suite, pubPoly, msg, sigShares[0]))
+	require.NoError(test, VerifyPartial(suite, pubPoly, msg, sigShares[1]))
+	want, err := Recover(suite, pubPoly, msg, sigShares[3:], t, n)
+	require.NoError(test, err)
+
+	// the invalid shares 0 and 2 are skipped and reported, as well as the
+	// short share and the duplicate of the share 1
+	shares := append([][]byte{{1}}, sigShares[:3]...)
+	shares = append(shares, sigShares[1])
+	shares = append(shares, sigShares[3:]...)
+	for _, workers := range []int{1, 4} {
+		scheme := legacy(suite).WithConcurrency(workers)
+		sig, invalid, err := scheme.RecoverValid(pubPoly, msg, shares, t, n)
+		require.NoError(test, err)
+		require.Equal(test, want, sig)
+		require.Equal(test, []int{0, 2}, invalid)
+	}
+
+	// not enough valid shares are left
+	sig, invalid, err := RecoverValid(suite, pubPoly, msg, sigShares[:t], t, n)
+	require.Error(test, err)
+	require.Nil(test, sig)
+	require.Equal(test, []int{0, 2}, invalid)
+	var shareErrs ShareErrors
+	require.True(test, errors.As(err, &shareErrs))
+	require.Len(test, shareErrs, 2)
+	var tooFew *TooFewSharesError
+	require.True(test, errors.As(err, &tooFew))
+	require.Equal(test, t-2, tooFew.Valid)
+	require.Equal(test, t, tooFew.Needed)
+
+	_, invalid, err = RecoverValid(suite, pubPoly, msg, sigShares[3:t+2], t, n)
+	require.True(test, errors.Is(err, ErrTooFewShares))
+	require.False(test, errors.As(err, &shareErrs))
+	require.Empty(test, invalid)
+}
+
+// BenchmarkRecoverConcurrency recovers a signature from t = 64 shares over an
+// increasing number of goroutines, which scales with the number of cores when
+// run with e.g. -cpu 8.
+func BenchmarkRecoverConcurrency(b *testing.B) {
+	msg := []byte("Hello threshold Boneh-Lynn-Shacham")
+	suite := bn256.NewSuite()
+	n, t := 100, 64
+	priPoly := share.NewPriPoly(suite.G2(), t, nil, suite.RandomStream())
+	pubPoly := priPoly.Commit(nil)
+	sigShares := make([][]byte, n)
+	for i, x := range priPoly.Shares(n) {
+		sig, err := Sign(suite, x, msg)
+		require.NoError(b, err)
+		sigShares[i] = sig
+	}
+	for _, workers := range []int{1, 2, 4, 8} {
+		scheme := legacy(suite).WithConcurrency(workers)
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := scheme.Recover(pubPoly, msg, sigShares, t, n); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
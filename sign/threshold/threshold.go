@@ -0,0 +1,391 @@
+// Package threshold is a turnkey threshold BLS signature scheme: a set of n
+// nodes runs the Pedersen DKG of share/dkg/pedersen to create a distributed
+// key, after which any t of them sign messages with tbls and anyone aggregates
+// their signature shares into a regular BLS signature on the distributed key.
+//
+// A Node hides the steps of the DKG behind messages: the caller sends the
+// messages returned by Start to their recipients, gives every message it
+// receives to HandleMessage, and sends the messages it returns in turn, until
+// Done returns true. The node routes the messages to the DKG, holds back the
+// responses and justifications which arrive before the deal or the complaint
+// they refer to, and computes its share and the public polynomial once all the
+// deals are certified. If some nodes do not answer, Finish ends the DKG after
+// a timeout with the deals certified so far, as long as they reach the
+// threshold.
+//
+// The keys are on G1 and the signatures on G2 of the pairing suite, see
+// tbls.NewSchemeOnG2: the longterm keys of the nodes, the distributed key and
+// the public polynomial are points of G1. The messages must come through
+// channels which deliver them eventually, but need not be authenticated nor
+// ordered, since the DKG messages are signed with the longterm keys.
+package threshold
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/share"
+	dkg "go.dedis.ch/kyber/v3/share/dkg/pedersen"
+	vss "go.dedis.ch/kyber/v3/share/vss/pedersen"
+	"go.dedis.ch/kyber/v3/sign/tbls"
+)
+
+// ErrNotReady is returned by the methods of a Node which need the distributed
+// key before the DKG has ended.
+var ErrNotReady = errors.New("threshold: distributed key not ready")
+
+var errMessage = errors.New("threshold: invalid message")
+
+// Kind is the kind of the DKG message carried by a Message.
+type Kind byte
+
+const (
+	// KindDeal is a deal, sent to one node.
+	KindDeal Kind = iota + 1
+	// KindResponse is a response to a deal, sent to all the other nodes.
+	KindResponse
+	// KindJustification is a justification of a complaint, sent to all the
+	// other nodes.
+	KindJustification
+)
+
+// Broadcast is the recipient of the messages for all the nodes but their
+// sender.
+const Broadcast = -1
+
+// Message is a message of the DKG from the node of index From to the node of
+// index To, or to all the other nodes if To is Broadcast. The indices are the
+// positions of the nodes in the list given to NewNode.
+type Message struct {
+	Kind Kind
+	From int
+	To   int
+	// Payload is the binary encoding of the DKG message, see
+	// dkg.Deal.MarshalBinary.
+	Payload []byte
+}
+
+// MarshalBinary returns the encoding of the message: its kind on one byte,
+// its sender and its recipient as big-endian uint32, where Broadcast is
+// 0xffffffff, followed by the payload.
+func (m *Message) MarshalBinary() ([]byte, error) {
+	buff := make([]byte, 9, 9+len(m.Payload))
+	buff[0] = byte(m.Kind)
+	binary.BigEndian.PutUint32(buff[1:], uint32(m.From))
+	binary.BigEndian.PutUint32(buff[5:], uint32(m.To))
+	return append(buff, m.Payload...), nil
+}
+
+// UnmarshalBinary decodes a message encoded with MarshalBinary.
+func (m *Message) UnmarshalBinary(data []byte) error {
+	if len(data) < 9 {
+		return fmt.Errorf("%w: %d bytes", errMessage, len(data))
+	}
+	m.Kind = Kind(data[0])
+	m.From = int(int32(binary.BigEndian.Uint32(data[1:])))
+	m.To = int(int32(binary.BigEndian.Uint32(data[5:])))
+	m.Payload = append([]byte(nil), data[9:]...)
+	return nil
+}
+
+// Node is a participant of the DKG and of the signatures. It is safe for
+// concurrent use.
+type Node struct {
+	mu     sync.Mutex
+	suite  *pairing.GroupSuite
+	scheme *tbls.Scheme
+	index  int
+	n      int
+	dkg    *dkg.DistKeyGenerator
+	// responses and justifications given to HandleMessage too early
+	pendingResps []*dkg.Response
+	pendingJusts []*dkg.Justification
+	// set once the DKG has ended
+	key    *dkg.DistKeyShare
+	public *share.PubPoly
+}
+
+// NewNode returns the node of the longterm private key among the nodes of the
+// given public keys on G1, for a distributed key of threshold t. All the nodes
+// must be given the same list of public keys and threshold.
+func NewNode(suite pairing.Suite, longterm kyber.Scalar, nodes []kyber.Point, t int) (*Node, error) {
+	g1 := pairing.G1Suite(suite)
+	d, err := dkg.NewDistKeyGenerator(g1, longterm, nodes, t)
+	if err != nil {
+		return nil, err
+	}
+	public := g1.Point().Mul(longterm, nil)
+	index := -1
+	for i, p := range nodes {
+		if p.Equal(public) {
+			index = i
+			break
+		}
+	}
+	return &Node{
+		suite:  g1,
+		scheme: tbls.NewSchemeOnG2(suite),
+		index:  index,
+		n:      len(nodes),
+		dkg:    d,
+	}, nil
+}
+
+// Index returns the index of the node in the list of nodes.
+func (n *Node) Index() int {
+	return n.index
+}
+
+// Start returns the deals of the node, one for each other node. Calling it
+// again returns the same deals.
+func (n *Node) Start() ([]*Message, error) {
+	deals, err := n.dkg.Deals()
+	if err != nil {
+		return nil, err
+	}
+	msgs := make([]*Message, 0, len(deals))
+	for i := 0; i < n.n; i++ {
+		deal, ok := deals[i]
+		if !ok {
+			continue
+		}
+		m, err := n.message(KindDeal, n.dkg.ShareHolder(i), deal)
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, m)
+	}
+	return msgs, nil
+}
+
+// HandleMessage processes a message of another node and returns the messages
+// to send in turn: the response to a deal, and the justification of a
+// complaint about the deal of this node. The responses and justifications
+// which come before their deal or complaint are kept until they can be
+// processed. The duplicated messages are harmless, and the messages given
+// once the DKG has ended are ignored. It returns an error if the message is for
+// another node or is invalid, in which case the DKG goes on.
+func (n *Node) HandleMessage(m *Message) ([]*Message, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.key != nil || n.dkg.Finished() {
+		return nil, nil
+	}
+	switch {
+	case m.To != Broadcast && m.To != n.index:
+		return nil, fmt.Errorf("%w: for node %d", errMessage, m.To)
+	case m.To == Broadcast && m.From == n.index:
+		return nil, nil
+	}
+
+	var out []*Message
+	var err error
+	switch m.Kind {
+	case KindDeal:
+		out, err = n.handleDeal(m.Payload)
+	case KindResponse:
+		resp := &dkg.Response{}
+		if err := resp.UnmarshalBinary(n.suite, m.Payload); err != nil {
+			return nil, err
+		}
+		out, err = n.handleResponse(resp, true)
+	case KindJustification:
+		j := &dkg.Justification{}
+		if err := j.UnmarshalBinary(n.suite, m.Payload); err != nil {
+			return nil, err
+		}
+		err = n.handleJustification(j)
+	default:
+		return nil, fmt.Errorf("%w: kind %d", errMessage, m.Kind)
+	}
+	if err != nil {
+		return out, err
+	}
+	if n.dkg.Certified() {
+		return out, n.computeKey()
+	}
+	return out, nil
+}
+
+// handleDeal processes a deal, then the pending responses about it.
+func (n *Node) handleDeal(payload []byte) ([]*Message, error) {
+	deal := &dkg.Deal{}
+	if err := deal.UnmarshalBinary(n.suite, payload); err != nil {
+		return nil, err
+	}
+	resp, err := n.dkg.ProcessDeal(deal)
+	if err != nil {
+		return nil, err
+	}
+	m, err := n.message(KindResponse, Broadcast, resp)
+	if err != nil {
+		return nil, err
+	}
+	return n.retry([]*Message{m})
+}
+
+// handleResponse processes a response, and keeps it for later if its deal has
+// not been received yet. If pending is true, the pending messages are retried
+// once the response is processed.
+func (n *Node) handleResponse(resp *dkg.Response, pending bool) ([]*Message, error) {
+	j, err := n.dkg.ProcessResponse(resp)
+	switch {
+	case errors.Is(err, vss.ErrNoDealBeforeResponse):
+		n.pendingResps = append(n.pendingResps, resp)
+		return nil, nil
+	case errors.Is(err, vss.ErrDuplicateResponse):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+	var out []*Message
+	if j != nil {
+		m, err := n.message(KindJustification, Broadcast, j)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, m)
+	}
+	if !pending {
+		return out, nil
+	}
+	return n.retry(out)
+}
+
+// handleJustification processes a justification, and keeps it for later if
+// its complaint has not been received yet.
+func (n *Node) handleJustification(j *dkg.Justification) error {
+	err := n.dkg.ProcessJustification(j)
+	if errors.Is(err, vss.ErrUnexpectedJustification) {
+		n.pendingJusts = append(n.pendingJusts, j)
+		return nil
+	}
+	return err
+}
+
+// retry processes the pending responses and justifications again until none
+// of them can be processed anymore, and returns the messages to send appended
+// to out. The ones which are still early are kept, the invalid ones dropped.
+func (n *Node) retry(out []*Message) ([]*Message, error) {
+	for progress := true; progress; {
+		progress = false
+		resps := n.pendingResps
+		n.pendingResps = nil
+		for _, resp := range resps {
+			msgs, err := n.handleResponse(resp, false)
+			if err != nil {
+				continue
+			}
+			out = append(out, msgs...)
+		}
+		progress = len(n.pendingResps) < len(resps)
+
+		justs := n.pendingJusts
+		n.pendingJusts = nil
+		for _, j := range justs {
+			err := n.dkg.ProcessJustification(j)
+			if errors.Is(err, vss.ErrUnexpectedJustification) {
+				n.pendingJusts = append(n.pendingJusts, j)
+			}
+		}
+		progress = progress || len(n.pendingJusts) < len(justs)
+	}
+	return out, nil
+}
+
+// message returns the message of the given kind carrying the DKG message.
+func (n *Node) message(kind Kind, to int, payload interface{ MarshalBinary() ([]byte, error) }) (*Message, error) {
+	buff, err := payload.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return &Message{Kind: kind, From: n.index, To: to, Payload: buff}, nil
+}
+
+// computeKey computes the share of the node and the public polynomial.
+func (n *Node) computeKey() error {
+	key, err := n.dkg.DistKeyShare()
+	if err != nil {
+		return err
+	}
+	n.key = key
+	n.public = share.NewPubPoly(key.Group(), nil, key.Commitments())
+	n.pendingResps = nil
+	n.pendingJusts = nil
+	return nil
+}
+
+// Done returns true once the DKG has ended and the node can sign.
+func (n *Node) Done() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.key != nil
+}
+
+// Finish ends the DKG after a timeout, with the deals certified so far, see
+// dkg.DistKeyGenerator.Finish. It returns an error if the certified deals do
+// not reach the threshold, in which case the DKG must be run again.
+func (n *Node) Finish() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.key != nil {
+		return nil
+	}
+	n.dkg.Finish()
+	return n.computeKey()
+}
+
+// PublicKey returns the distributed public key, which verifies the aggregated
+// signatures, or nil before the DKG has ended.
+func (n *Node) PublicKey() kyber.Point {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.public == nil {
+		return nil
+	}
+	return n.public.Commit()
+}
+
+// PublicPoly returns the public polynomial of the distributed key, which
+// verifies the signature shares, or nil before the DKG has ended.
+func (n *Node) PublicPoly() *share.PubPoly {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.public
+}
+
+// Sign returns the signature share of the node on the message. It returns
+// ErrNotReady before the DKG has ended.
+func (n *Node) Sign(msg []byte) ([]byte, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.key == nil {
+		return nil, ErrNotReady
+	}
+	return n.scheme.Sign(n.key.PriShare(), msg)
+}
+
+// Aggregate returns the signature on the message recovered from the first t
+// valid signature shares of the n nodes of the public polynomial, see
+// tbls.Scheme.RecoverValid. The invalid shares are skipped, and listed by the
+// tbls.TooFewSharesError returned when fewer than t shares are valid.
+func Aggregate(suite pairing.Suite, public *share.PubPoly, msg []byte, sigs [][]byte, t, n int) ([]byte, error) {
+	sig, _, err := tbls.NewSchemeOnG2(suite).RecoverValid(public, msg, sigs, t, n)
+	return sig, err
+}
+
+// Verify checks the aggregated signature on the message against the
+// distributed public key.
+func Verify(suite pairing.Suite, public kyber.Point, msg, sig []byte) error {
+	return tbls.NewSchemeOnG2(suite).VerifyRecovered(public, msg, sig)
+}
+
+// VerifyShare checks the signature share on the message against the public
+// polynomial.
+func VerifyShare(suite pairing.Suite, public *share.PubPoly, msg, sig []byte) error {
+	return tbls.NewSchemeOnG2(suite).VerifyPartial(public, msg, sig)
+}
@@ -0,0 +1,157 @@
+package threshold
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing/bn256"
+	"go.dedis.ch/kyber/v3/sign/tbls"
+)
+
+var suite = bn256.NewSuite()
+
+func newNodes(t *testing.T, n, thr int) []*Node {
+	privates := make([]kyber.Scalar, n)
+	publics := make([]kyber.Point, n)
+	for i := range privates {
+		privates[i] = suite.G1().Scalar().Pick(suite.RandomStream())
+		publics[i] = suite.G1().Point().Mul(privates[i], nil)
+	}
+	nodes := make([]*Node, n)
+	for i := range nodes {
+		node, err := NewNode(suite, privates[i], publics, thr)
+		require.NoError(t, err)
+		require.Equal(t, i, node.Index())
+		nodes[i] = node
+	}
+	return nodes
+}
+
+// run delivers the messages of the nodes in a random order, through their
+// encoding, until there are none left. The nodes of down neither send nor
+// receive any message.
+func run(t *testing.T, nodes []*Node, down map[int]bool) {
+	var queue []*Message
+	for i, node := range nodes {
+		if down[i] {
+			continue
+		}
+		msgs, err := node.Start()
+		require.NoError(t, err)
+		queue = append(queue, msgs...)
+	}
+	for len(queue) > 0 {
+		k := rand.Intn(len(queue))
+		m := queue[k]
+		queue = append(queue[:k], queue[k+1:]...)
+		buff, err := m.MarshalBinary()
+		require.NoError(t, err)
+		for i, node := range nodes {
+			if down[i] || (m.To != Broadcast && m.To != i) {
+				continue
+			}
+			received := &Message{}
+			require.NoError(t, received.UnmarshalBinary(buff))
+			msgs, err := node.HandleMessage(received)
+			require.NoError(t, err)
+			queue = append(queue, msgs...)
+		}
+	}
+}
+
+func TestNode(t *testing.T) {
+	n, thr := 5, 3
+	nodes := newNodes(t, n, thr)
+	msg := []byte("Hello threshold")
+	_, err := nodes[0].Sign(msg)
+	require.Equal(t, ErrNotReady, err)
+	require.Nil(t, nodes[0].PublicKey())
+
+	run(t, nodes, nil)
+	public := nodes[0].PublicPoly()
+	var sigs [][]byte
+	for _, node := range nodes {
+		require.True(t, node.Done())
+		require.True(t, node.PublicKey().Equal(nodes[0].PublicKey()))
+		sig, err := node.Sign(msg)
+		require.NoError(t, err)
+		require.NoError(t, VerifyShare(suite, public, msg, sig))
+		sigs = append(sigs, sig)
+	}
+
+	sig, err := Aggregate(suite, public, msg, sigs[n-thr:], thr, n)
+	require.NoError(t, err)
+	require.NoError(t, Verify(suite, public.Commit(), msg, sig))
+	require.Error(t, Verify(suite, public.Commit(), []byte("other"), sig))
+
+	// an invalid share is skipped, as long as enough shares are valid
+	bad := append([]byte(nil), sigs[1]...)
+	bad[len(bad)-1] ^= 1
+	other, err := Aggregate(suite, public, msg, append([][]byte{bad}, sigs...), thr, n)
+	require.NoError(t, err)
+	require.NoError(t, Verify(suite, public.Commit(), msg, other))
+	_, err = Aggregate(suite, public, msg, [][]byte{bad, sigs[2], sigs[3]}, thr, n)
+	require.True(t, errors.Is(err, tbls.ErrTooFewShares))
+}
+
+func TestNodeFinish(t *testing.T) {
+	n, thr := 5, 3
+	nodes := newNodes(t, n, thr)
+	run(t, nodes, map[int]bool{4: true})
+
+	msg := []byte("Hello threshold")
+	var sigs [][]byte
+	for _, node := range nodes[:4] {
+		require.False(t, node.Done())
+		require.NoError(t, node.Finish())
+		require.True(t, node.Done())
+		sig, err := node.Sign(msg)
+		require.NoError(t, err)
+		sigs = append(sigs, sig)
+	}
+	public := nodes[0].PublicPoly()
+	sig, err := Aggregate(suite, public, msg, sigs, thr, n)
+	require.NoError(t, err)
+	require.NoError(t, Verify(suite, public.Commit(), msg, sig))
+
+	// without enough certified deals, the DKG fails
+	nodes = newNodes(t, n, thr)
+	run(t, nodes, map[int]bool{2: true, 3: true, 4: true})
+	require.Error(t, nodes[0].Finish())
+	require.False(t, nodes[0].Done())
+}
+
+func TestNodeHandleMessage(t *testing.T) {
+	nodes := newNodes(t, 3, 2)
+	msgs, err := nodes[0].Start()
+	require.NoError(t, err)
+	require.Len(t, msgs, 2)
+	require.Equal(t, 1, msgs[0].To)
+
+	// a deal for another node is rejected
+	_, err = nodes[2].HandleMessage(msgs[0])
+	require.Error(t, err)
+	_, err = nodes[1].HandleMessage(&Message{Kind: 0, From: 0, To: 1})
+	require.Error(t, err)
+
+	// a response which arrives before its deal is processed once the deal
+	// arrives
+	resps, err := nodes[1].HandleMessage(msgs[0])
+	require.NoError(t, err)
+	require.Len(t, resps, 1)
+	require.Equal(t, Broadcast, resps[0].To)
+	out, err := nodes[2].HandleMessage(resps[0])
+	require.NoError(t, err)
+	require.Empty(t, out)
+	require.Len(t, nodes[2].pendingResps, 1)
+	out, err = nodes[2].HandleMessage(msgs[1])
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	require.Empty(t, nodes[2].pendingResps)
+
+	m := &Message{}
+	require.Error(t, m.UnmarshalBinary([]byte{1, 2}))
+}
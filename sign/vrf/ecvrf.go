@@ -0,0 +1,201 @@
+package vrf
+
+import (
+	"crypto/sha512"
+	"crypto/subtle"
+	"errors"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/util/wipe"
+)
+
+// ECVRF-EDWARDS25519-SHA512-TAI parameters of RFC 9381
+const (
+	ed25519SuiteString  = 0x03
+	ed25519ChallengeLen = 16
+	// Ed25519ProofLen is the length of the proofs of ProveEd25519.
+	Ed25519ProofLen = 32 + ed25519ChallengeLen + 32
+)
+
+// domain separation of the hashes of RFC 9381
+const (
+	h2cFront       = 0x01
+	challengeFront = 0x02
+	outputFront    = 0x03
+	back           = 0x00
+)
+
+var ed25519Group = new(edwards25519.Curve)
+
+type canonicalScalar interface {
+	SetBytesLE([]byte) (kyber.Scalar, error)
+}
+
+// ProveEd25519 computes the ECVRF-EDWARDS25519-SHA512-TAI proof pi of the
+// message alpha with the private key, the 32-byte seed of an Ed25519 key
+// pair as in RFC 8032 and sign/eddsa. The output of the VRF is
+// ProofToHashEd25519(pi).
+func ProveEd25519(private, alpha []byte) ([]byte, error) {
+	if len(private) != 32 {
+		return nil, errors.New("vrf: Ed25519 private key must be 32 bytes")
+	}
+	x, _, prefix := ed25519Group.NewKeyAndSeedWithInput(private)
+	defer wipe.Scalar(x)
+	Y := ed25519Group.Point().Mul(x, nil)
+	pk, err := Y.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	H, err := ed25519HashToCurve(pk, alpha)
+	if err != nil {
+		return nil, err
+	}
+	h, err := H.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	// the nonce of RFC 8032: SHA-512(prefix || H) with the second half of
+	// the hash of the private key as prefix
+	kh := sha512.New()
+	_, _ = kh.Write(prefix)
+	_, _ = kh.Write(h)
+	digest := kh.Sum(nil)
+	k := ed25519Group.Scalar().SetBytes(digest)
+	wipe.Bytes(digest)
+	defer wipe.Scalar(k)
+
+	gamma := ed25519Group.Point().Mul(x, H)
+	U := ed25519Group.Point().Mul(k, nil)
+	V := ed25519Group.Point().Mul(k, H)
+	c, err := ed25519Challenge(Y, H, gamma, U, V)
+	if err != nil {
+		return nil, err
+	}
+	// s = k + c*x
+	s := ed25519Group.Scalar().SetBytes(c)
+	s.Mul(s, x).Add(k, s)
+
+	pi, err := gamma.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	pi = append(pi, c...)
+	sb, err := s.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return append(pi, sb...), nil
+}
+
+// VerifyEd25519 checks the ECVRF-EDWARDS25519-SHA512-TAI proof pi of the
+// message alpha under the Ed25519 public key, and returns the output beta of
+// the VRF if it is valid. The public key must be in the prime-order subgroup,
+// which the points of kyber's edwards25519 group are.
+func VerifyEd25519(public kyber.Point, alpha, pi []byte) ([]byte, error) {
+	gamma, c, s, err := ed25519DecodeProof(pi)
+	if err != nil {
+		return nil, err
+	}
+	pk, err := public.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	H, err := ed25519HashToCurve(pk, alpha)
+	if err != nil {
+		return nil, err
+	}
+
+	cs := ed25519Group.Scalar().SetBytes(c)
+	// U = s*B - c*Y, V = s*H - c*Gamma
+	U := ed25519Group.Point().Mul(s, nil)
+	U.Sub(U, ed25519Group.Point().Mul(cs, public))
+	V := ed25519Group.Point().Mul(s, H)
+	V.Sub(V, ed25519Group.Point().Mul(cs, gamma))
+	cc, err := ed25519Challenge(public, H, gamma, U, V)
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare(c, cc) != 1 {
+		return nil, errInvalidProof
+	}
+	return ed25519GammaToHash(gamma)
+}
+
+// ProofToHashEd25519 returns the output beta of the VRF of the
+// ECVRF-EDWARDS25519-SHA512-TAI proof pi. As in RFC 9381, it does not verify
+// the proof: the output of an unverified proof must not be used, see
+// VerifyEd25519.
+func ProofToHashEd25519(pi []byte) ([]byte, error) {
+	gamma, _, _, err := ed25519DecodeProof(pi)
+	if err != nil {
+		return nil, err
+	}
+	return ed25519GammaToHash(gamma)
+}
+
+// ed25519HashToCurve is the try-and-increment encoding of the message to the
+// prime-order subgroup of RFC 9381: it hashes the message with a counter
+// until the first half of the hash decodes to a point of the curve, which it
+// multiplies by the cofactor. It is not constant time, the message of a VRF
+// being public.
+func ed25519HashToCurve(pk, alpha []byte) (kyber.Point, error) {
+	for ctr := 0; ctr < 256; ctr++ {
+		h := sha512.New()
+		_, _ = h.Write([]byte{ed25519SuiteString, h2cFront})
+		_, _ = h.Write(pk)
+		_, _ = h.Write(alpha)
+		_, _ = h.Write([]byte{byte(ctr), back})
+		H, err := ed25519Group.DecodeClearCofactor(h.Sum(nil)[:32])
+		if err == nil {
+			return H, nil
+		}
+	}
+	return nil, errors.New("vrf: no valid point found in hash to curve")
+}
+
+// ed25519Challenge returns the 16 bytes of the challenge of the points.
+func ed25519Challenge(points ...kyber.Point) ([]byte, error) {
+	h := sha512.New()
+	_, _ = h.Write([]byte{ed25519SuiteString, challengeFront})
+	for _, p := range points {
+		if _, err := p.MarshalTo(h); err != nil {
+			return nil, err
+		}
+	}
+	_, _ = h.Write([]byte{back})
+	return h.Sum(nil)[:ed25519ChallengeLen], nil
+}
+
+func ed25519GammaToHash(gamma kyber.Point) ([]byte, error) {
+	// gamma is in the prime-order subgroup, but the output of the RFC hashes
+	// cofactor*Gamma
+	G := ed25519Group.Point().Mul(ed25519Group.Scalar().SetInt64(8), gamma)
+	h := sha512.New()
+	_, _ = h.Write([]byte{ed25519SuiteString, outputFront})
+	if _, err := G.MarshalTo(h); err != nil {
+		return nil, err
+	}
+	_, _ = h.Write([]byte{back})
+	return h.Sum(nil), nil
+}
+
+// ed25519DecodeProof decodes pi = Gamma || c || s, with c on 16 bytes and s a
+// canonical scalar. Gamma must be in the prime-order subgroup, which it is
+// for the proofs of ProveEd25519.
+func ed25519DecodeProof(pi []byte) (gamma kyber.Point, c []byte, s kyber.Scalar, err error) {
+	if len(pi) != Ed25519ProofLen {
+		return nil, nil, nil, errors.New("vrf: proof of invalid length")
+	}
+	gamma = ed25519Group.Point()
+	if err := gamma.UnmarshalBinary(pi[:32]); err != nil {
+		return nil, nil, nil, err
+	}
+	c = pi[32 : 32+ed25519ChallengeLen]
+	s, err = ed25519Group.Scalar().(canonicalScalar).SetBytesLE(pi[32+ed25519ChallengeLen:])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return gamma, c, s, nil
+}
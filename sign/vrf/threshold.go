@@ -0,0 +1,116 @@
+package vrf
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/share"
+)
+
+// Partial encodes a partial VRF evaluation Pi = i || Gamma_i || c || s where
+// the 2-byte big-endian value i corresponds to the share's index, Gamma_i =
+// xi*H is the partial VRF point and (c, s) is the proof that Gamma_i has been
+// computed with the key share xi. Partials are computed with respect to the
+// group public key so that any threshold of them recovers the same output as
+// the one a single holder of the group private key would compute with Prove.
+type Partial []byte
+
+// Index returns the index i of the partial evaluation Pi.
+func (p Partial) Index() (int, error) {
+	var index uint16
+	buf := bytes.NewReader(p)
+	err := binary.Read(buf, binary.BigEndian, &index)
+	if err != nil {
+		return -1, err
+	}
+	return int(index), nil
+}
+
+// Value returns the proof Gamma_i || c || s of the partial evaluation Pi.
+func (p *Partial) Value() []byte {
+	return []byte(*p)[2:]
+}
+
+// PartialProve computes the partial VRF evaluation of the message msg using
+// the secret key share xi. The public sharing polynomial is required to bind
+// the evaluation to the group public key.
+func PartialProve(suite Suite, public *share.PubPoly, private *share.PriShare, msg []byte) ([]byte, error) {
+	H, err := hashToPoint(suite, public.Commit(), msg)
+	if err != nil {
+		return nil, err
+	}
+	X := suite.Point().Mul(private.V, nil)
+	gamma, c, s, err := prove(suite, private.V, X, H)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	if err := binary.Write(buf, binary.BigEndian, uint16(private.I)); err != nil {
+		return nil, err
+	}
+	for _, m := range []kyber.Marshaling{gamma, c, s} {
+		if _, err := m.MarshalTo(buf); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// PartialVerify checks the given partial VRF evaluation Pi on the message msg
+// using the public key share Xi that is associated to the secret key share xi.
+// This public key share Xi is computed by evaluating the public sharing
+// polynomial at the share's index i.
+func PartialVerify(suite Suite, public *share.PubPoly, msg, partial []byte) error {
+	_, err := partialGamma(suite, public, msg, partial)
+	return err
+}
+
+// Recover reconstructs the VRF output beta of the group key on the message msg
+// from a threshold t of partial evaluations using Lagrange interpolation. Each
+// partial evaluation is verified before being used. The output is the same as
+// the one that Prove would return for the group private key.
+func Recover(suite Suite, public *share.PubPoly, msg []byte, partials [][]byte, t, n int) ([]byte, error) {
+	pubShares := make([]*share.PubShare, 0)
+	for _, partial := range partials {
+		p := Partial(partial)
+		i, err := p.Index()
+		if err != nil {
+			return nil, err
+		}
+		gamma, err := partialGamma(suite, public, msg, partial)
+		if err != nil {
+			return nil, err
+		}
+		pubShares = append(pubShares, &share.PubShare{I: i, V: gamma})
+		if len(pubShares) >= t {
+			break
+		}
+	}
+	gamma, err := share.RecoverCommit(suite, pubShares, t, n)
+	if err != nil {
+		return nil, err
+	}
+	return gammaToHash(suite, gamma)
+}
+
+func partialGamma(suite Suite, public *share.PubPoly, msg, partial []byte) (kyber.Point, error) {
+	p := Partial(partial)
+	i, err := p.Index()
+	if err != nil {
+		return nil, err
+	}
+	gamma, c, s, err := decodeProof(suite, p.Value())
+	if err != nil {
+		return nil, err
+	}
+	H, err := hashToPoint(suite, public.Commit(), msg)
+	if err != nil {
+		return nil, err
+	}
+	if err := verify(suite, public.Eval(i).V, H, gamma, c, s); err != nil {
+		return nil, err
+	}
+	return gamma, nil
+}
@@ -0,0 +1,225 @@
+// Package vrf implements a verifiable random function (VRF) in the style of
+// ECVRF (https://datatracker.ietf.org/doc/draft-irtf-cfrg-vrf/) over any
+// kyber group. A VRF output beta is a pseudo-random value that is
+// deterministic for a given key pair and message, and which comes with a
+// proof that anyone holding the public key can verify.
+//
+// The message is first hashed to a point H on the curve, bound to the public
+// key X. The prover computes Gamma = x*H and proves in zero-knowledge that
+// log_G(X) == log_H(Gamma). The output beta is a hash of Gamma. The challenge
+// of the equality proof covers the public key, H and Gamma so the proof can
+// not be re-used for another key or message.
+//
+// Groups whose points expose a Hash([]byte) kyber.Point method, such as the
+// bn256 G1 group, use it to map messages to points. Other groups, such as
+// edwards25519, map the message to a point via Pick() over the suite's XOF.
+//
+// The hashes of this construction are specific to kyber. ProveEd25519,
+// VerifyEd25519 and ProofToHashEd25519 implement instead the ciphersuite
+// ECVRF-EDWARDS25519-SHA512-TAI of RFC 9381 with Ed25519 keys, whose proofs
+// and outputs are those of the other implementations of the RFC.
+package vrf
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"errors"
+
+	"go.dedis.ch/kyber/v3"
+)
+
+// Suite wraps the functionalities needed by the vrf package.
+type Suite interface {
+	kyber.Group
+	kyber.HashFactory
+	kyber.XOFFactory
+}
+
+type hashablePoint interface {
+	Hash([]byte) kyber.Point
+}
+
+type torsionFreePoint interface {
+	IsTorsionFree() bool
+}
+
+var errInvalidProof = errors.New("vrf: invalid proof")
+var errInvalidOutput = errors.New("vrf: output does not match proof")
+
+// domain separation prefixes for the different hashes used in this package
+var (
+	hashToPointPrefix = []byte("kyber-vrf-h2c")
+	noncePrefix       = []byte("kyber-vrf-nonce")
+	challengePrefix   = []byte("kyber-vrf-challenge")
+	outputPrefix      = []byte("kyber-vrf-output")
+)
+
+// Prove computes the VRF output beta of the message msg under the private key
+// and a proof that beta has been correctly computed. The proof is encoded as
+// Gamma || c || s where Gamma is the VRF point, c the challenge and s the
+// response of the equality proof.
+func Prove(suite Suite, private kyber.Scalar, msg []byte) (beta []byte, proof []byte, err error) {
+	public := suite.Point().Mul(private, nil)
+	H, err := hashToPoint(suite, public, msg)
+	if err != nil {
+		return nil, nil, err
+	}
+	gamma, c, s, err := prove(suite, private, public, H)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var b bytes.Buffer
+	for _, m := range []kyber.Marshaling{gamma, c, s} {
+		if _, err := m.MarshalTo(&b); err != nil {
+			return nil, nil, err
+		}
+	}
+	beta, err = gammaToHash(suite, gamma)
+	if err != nil {
+		return nil, nil, err
+	}
+	return beta, b.Bytes(), nil
+}
+
+// Verify checks that the proof is valid for the message msg under the public
+// key and that beta is the VRF output it proves. It returns nil if and only if
+// both checks pass.
+func Verify(suite Suite, public kyber.Point, msg, beta, proof []byte) error {
+	expected, err := ProofToHash(suite, public, msg, proof)
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare(expected, beta) != 1 {
+		return errInvalidOutput
+	}
+	return nil
+}
+
+// ProofToHash verifies the proof for the message msg under the public key and
+// returns the VRF output beta it proves. It returns an error if the proof is
+// invalid.
+func ProofToHash(suite Suite, public kyber.Point, msg, proof []byte) ([]byte, error) {
+	gamma, c, s, err := decodeProof(suite, proof)
+	if err != nil {
+		return nil, err
+	}
+	H, err := hashToPoint(suite, public, msg)
+	if err != nil {
+		return nil, err
+	}
+	if err := verify(suite, public, H, gamma, c, s); err != nil {
+		return nil, err
+	}
+	return gammaToHash(suite, gamma)
+}
+
+// prove computes Gamma = x*H together with a proof (c, s) that log_G(X) ==
+// log_H(Gamma). The nonce is derived deterministically from the private key
+// and H so that a faulty random source can not leak the private key.
+func prove(suite Suite, x kyber.Scalar, X, H kyber.Point) (gamma kyber.Point, c, s kyber.Scalar, err error) {
+	gamma = suite.Point().Mul(x, H)
+
+	nh := suite.Hash()
+	_, _ = nh.Write(noncePrefix)
+	if _, err := x.MarshalTo(nh); err != nil {
+		return nil, nil, nil, err
+	}
+	if _, err := H.MarshalTo(nh); err != nil {
+		return nil, nil, nil, err
+	}
+	k := suite.Scalar().Pick(suite.XOF(nh.Sum(nil)))
+
+	U := suite.Point().Mul(k, nil)
+	V := suite.Point().Mul(k, H)
+	c, err = challenge(suite, X, H, gamma, U, V)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// s = k + c*x
+	s = suite.Scalar().Mul(c, x)
+	s.Add(k, s)
+	return gamma, c, s, nil
+}
+
+// verify checks the equality proof (c, s) for the statement
+// log_G(X) == log_H(Gamma), i.e. that c == H(X, H, Gamma, s*G - c*X, s*H - c*Gamma).
+func verify(suite Suite, X, H, gamma kyber.Point, c, s kyber.Scalar) error {
+	if p, ok := gamma.(torsionFreePoint); ok && !p.IsTorsionFree() {
+		return errInvalidProof
+	}
+
+	U := suite.Point().Mul(s, nil)
+	U.Sub(U, suite.Point().Mul(c, X))
+	V := suite.Point().Mul(s, H)
+	V.Sub(V, suite.Point().Mul(c, gamma))
+
+	cc, err := challenge(suite, X, H, gamma, U, V)
+	if err != nil {
+		return err
+	}
+	if !cc.Equal(c) {
+		return errInvalidProof
+	}
+	return nil
+}
+
+func challenge(suite Suite, points ...kyber.Point) (kyber.Scalar, error) {
+	h := suite.Hash()
+	_, _ = h.Write(challengePrefix)
+	if _, err := suite.Point().Base().MarshalTo(h); err != nil {
+		return nil, err
+	}
+	for _, p := range points {
+		if _, err := p.MarshalTo(h); err != nil {
+			return nil, err
+		}
+	}
+	return suite.Scalar().Pick(suite.XOF(h.Sum(nil))), nil
+}
+
+// hashToPoint maps the public key and the message to a point of the group
+// whose discrete logarithm is unknown.
+func hashToPoint(suite Suite, public kyber.Point, msg []byte) (kyber.Point, error) {
+	var b bytes.Buffer
+	b.Write(hashToPointPrefix)
+	if _, err := public.MarshalTo(&b); err != nil {
+		return nil, err
+	}
+	b.Write(msg)
+	if hashable, ok := suite.Point().(hashablePoint); ok {
+		return hashable.Hash(b.Bytes()), nil
+	}
+	return suite.Point().Pick(suite.XOF(b.Bytes())), nil
+}
+
+func gammaToHash(suite Suite, gamma kyber.Point) ([]byte, error) {
+	h := suite.Hash()
+	_, _ = h.Write(outputPrefix)
+	if _, err := gamma.MarshalTo(h); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+func decodeProof(suite Suite, proof []byte) (gamma kyber.Point, c, s kyber.Scalar, err error) {
+	gamma = suite.Point()
+	c = suite.Scalar()
+	s = suite.Scalar()
+	pointLen := gamma.MarshalSize()
+	scalarLen := c.MarshalSize()
+	if len(proof) != pointLen+2*scalarLen {
+		return nil, nil, nil, errors.New("vrf: proof of invalid length")
+	}
+	if err := gamma.UnmarshalBinary(proof[:pointLen]); err != nil {
+		return nil, nil, nil, err
+	}
+	if err := c.UnmarshalBinary(proof[pointLen : pointLen+scalarLen]); err != nil {
+		return nil, nil, nil, err
+	}
+	if err := s.UnmarshalBinary(proof[pointLen+scalarLen:]); err != nil {
+		return nil, nil, nil, err
+	}
+	return gamma, c, s, nil
+}
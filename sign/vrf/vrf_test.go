@@ -0,0 +1,230 @@
+package vrf
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/pairing/bn256"
+	"go.dedis.ch/kyber/v3/share"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+var suites = []Suite{
+	edwards25519.NewBlakeSHA256Ed25519(),
+	bn256.NewSuiteG1(),
+}
+
+func TestVRF(t *testing.T) {
+	msg := []byte("Hello Verifiable Random Function")
+	for _, suite := range suites {
+		private := suite.Scalar().Pick(random.New())
+		public := suite.Point().Mul(private, nil)
+
+		beta, proof, err := Prove(suite, private, msg)
+		require.NoError(t, err, suite.String())
+		require.NoError(t, Verify(suite, public, msg, beta, proof), suite.String())
+
+		// deterministic output
+		beta2, proof2, err := Prove(suite, private, msg)
+		require.NoError(t, err)
+		require.Equal(t, beta, beta2)
+		require.Equal(t, proof, proof2)
+
+		out, err := ProofToHash(suite, public, msg, proof)
+		require.NoError(t, err)
+		require.Equal(t, beta, out)
+
+		// different message, different output
+		beta3, _, err := Prove(suite, private, []byte("other message"))
+		require.NoError(t, err)
+		require.NotEqual(t, beta, beta3)
+	}
+}
+
+func TestVRFFailures(t *testing.T) {
+	msg := []byte("Hello Verifiable Random Function")
+	for _, suite := range suites {
+		private := suite.Scalar().Pick(random.New())
+		public := suite.Point().Mul(private, nil)
+		beta, proof, err := Prove(suite, private, msg)
+		require.NoError(t, err)
+
+		// wrong message
+		require.Error(t, Verify(suite, public, []byte("wrong"), beta, proof))
+
+		// wrong public key
+		other := suite.Point().Pick(random.New())
+		require.Error(t, Verify(suite, other, msg, beta, proof))
+
+		// wrong output
+		badBeta := append([]byte{}, beta...)
+		badBeta[0] ^= 0x01
+		require.Error(t, Verify(suite, public, msg, badBeta, proof))
+
+		// tampered response
+		badProof := append([]byte{}, proof...)
+		badProof[len(badProof)-4] ^= 0x01
+		require.Error(t, Verify(suite, public, msg, beta, badProof))
+
+		// invalid length
+		require.Error(t, Verify(suite, public, msg, beta, proof[1:]))
+	}
+}
+
+func TestVRFRejectsTorsion(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	private := suite.Scalar().Pick(random.New())
+	public := suite.Point().Mul(private, nil)
+	msg := []byte("torsion")
+	_, proof, err := Prove(suite, private, msg)
+	require.NoError(t, err)
+
+	// add the point (0, -1) of order 2 to Gamma = (x, y), which gives
+	// (-x, -y), i.e. negate y and flip the sign bit of x in the encoding; the
+	// result cannot be built with the group operations of kyber
+	buff := append([]byte{}, proof[:32]...)
+	sign := buff[31] & 0x80
+	buff[31] &= 0x7f
+	y := new(big.Int).SetBytes(reverse(buff))
+	prime, _ := new(big.Int).SetString("57896044618658097711785492504343953926634992332820282019728792003956564819949", 10)
+	y.Sub(prime, y)
+	buff = reverse(y.FillBytes(make([]byte, 32)))
+	buff[31] |= sign ^ 0x80
+	badProof := append(buff, proof[32:]...)
+	_, err = ProofToHash(suite, public, msg, badProof)
+	require.Error(t, err)
+}
+
+func reverse(b []byte) []byte {
+	r := make([]byte, len(b))
+	for i := range b {
+		r[len(b)-1-i] = b[i]
+	}
+	return r
+}
+
+func TestThresholdVRF(test *testing.T) {
+	msg := []byte("Hello threshold Verifiable Random Function")
+	n := 10
+	t := n/2 + 1
+	for _, suite := range suites {
+		secret := suite.Scalar().Pick(random.New())
+		priPoly := share.NewPriPoly(suite, t, secret, random.New())
+		pubPoly := priPoly.Commit(nil)
+
+		partials := make([][]byte, 0)
+		for _, x := range priPoly.Shares(n) {
+			p, err := PartialProve(suite, pubPoly, x, msg)
+			require.NoError(test, err)
+			require.NoError(test, PartialVerify(suite, pubPoly, msg, p))
+			partials = append(partials, p)
+		}
+
+		beta, err := Recover(suite, pubPoly, msg, partials[n-t:], t, n)
+		require.NoError(test, err)
+
+		// the recovered output matches the one of the group key
+		expected, proof, err := Prove(suite, secret, msg)
+		require.NoError(test, err)
+		require.Equal(test, expected, beta)
+		require.NoError(test, Verify(suite, pubPoly.Commit(), msg, beta, proof))
+
+		// a partial under a wrong index is rejected
+		bad := append([]byte{}, partials[0]...)
+		bad[1] = 0x01
+		require.Error(test, PartialVerify(suite, pubPoly, msg, bad))
+		_, err = Recover(suite, pubPoly, msg, [][]byte{bad}, t, n)
+		require.Error(test, err)
+
+		// not enough partials
+		_, err = Recover(suite, pubPoly, msg, partials[:t-1], t, n)
+		require.Error(test, err)
+	}
+}
+
+// test vectors of ECVRF-EDWARDS25519-SHA512-TAI, RFC 9381 appendix B.3
+var ed25519Vectors = []struct {
+	sk, pk, alpha, pi, beta string
+}{
+	{
+		"9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60",
+		"d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a",
+		"",
+		"8657106690b5526245a92b003bb079ccd1a92130477671f6fc01ad16f26f723f26f8a57ccaed74ee1b190bed1f479d9727d2d0f9b005a6e456a35d4fb0daab1268a1b0db10836d9826a528ca76567805",
+		"90cf1df3b703cce59e2a35b925d411164068269d7b2d29f3301c03dd757876ff66b71dda49d2de59d03450451af026798e8f81cd2e333de5cdf4f3e140fdd8ae",
+	},
+	{
+		"4ccd089b28ff96da9db6c346ec114e0f5b8a319f35aba624da8cf6ed4fb8a6fb",
+		"3d4017c3e843895a92b70aa74d1b7ebc9c982ccf2ec4968cc0cd55f12af4660c",
+		"72",
+		"f3141cd382dc42909d19ec5110469e4feae18300e94f304590abdced48aed5933bf0864a62558b3ed7f2fea45c92a465301b3bbf5e3e54ddf2d935be3b67926da3ef39226bbc355bdc9850112c8f4b02",
+		"eb4440665d3891d668e7e0fcaf587f1b4bd7fbfe99d0eb2211ccec90496310eb5e33821bc613efb94db5e5b54c70a848a0bef4553a41befc57663b56373a5031",
+	},
+}
+
+func TestVRFEd25519Vectors(t *testing.T) {
+	group := new(edwards25519.Curve)
+	for i, v := range ed25519Vectors {
+		sk, _ := hex.DecodeString(v.sk)
+		alpha, _ := hex.DecodeString(v.alpha)
+		pkb, _ := hex.DecodeString(v.pk)
+		public := group.Point()
+		require.NoError(t, public.UnmarshalBinary(pkb))
+
+		pi, err := ProveEd25519(sk, alpha)
+		require.NoError(t, err)
+		require.Equal(t, v.pi, hex.EncodeToString(pi), "vector %d", i)
+
+		beta, err := ProofToHashEd25519(pi)
+		require.NoError(t, err)
+		require.Equal(t, v.beta, hex.EncodeToString(beta), "vector %d", i)
+
+		beta, err = VerifyEd25519(public, alpha, pi)
+		require.NoError(t, err)
+		require.Equal(t, v.beta, hex.EncodeToString(beta), "vector %d", i)
+	}
+}
+
+func TestVRFEd25519Failures(t *testing.T) {
+	v := ed25519Vectors[1]
+	alpha, _ := hex.DecodeString(v.alpha)
+	pi, _ := hex.DecodeString(v.pi)
+	pkb, _ := hex.DecodeString(v.pk)
+	public := new(edwards25519.Curve).Point()
+	require.NoError(t, public.UnmarshalBinary(pkb))
+
+	// wrong message
+	_, err := VerifyEd25519(public, []byte("wrong"), pi)
+	require.Error(t, err)
+
+	// wrong public key
+	_, err = VerifyEd25519(public.Clone().Add(public, public), alpha, pi)
+	require.Error(t, err)
+
+	// tampered challenge and response
+	for _, i := range []int{32, Ed25519ProofLen - 4} {
+		bad := append([]byte{}, pi...)
+		bad[i] ^= 0x01
+		_, err = VerifyEd25519(public, alpha, bad)
+		require.Error(t, err)
+	}
+
+	// non-canonical response s + q
+	bad := append([]byte{}, pi...)
+	s := new(big.Int).SetBytes(reverse(pi[48:]))
+	order, _ := new(big.Int).SetString("7237005577332262213973186563042994240857116359379907606001950938285454250989", 10)
+	copy(bad[48:], reverse(s.Add(s, order).FillBytes(make([]byte, 32))))
+	_, err = VerifyEd25519(public, alpha, bad)
+	require.Error(t, err)
+
+	// invalid length
+	_, err = VerifyEd25519(public, alpha, pi[1:])
+	require.Error(t, err)
+	_, err = ProofToHashEd25519(pi[1:])
+	require.Error(t, err)
+	_, err = ProveEd25519(pi[:31], alpha)
+	require.Error(t, err)
+}
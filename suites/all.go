@@ -3,6 +3,7 @@ package suites
 import (
 	"go.dedis.ch/kyber/v3/group/edwards25519"
 	"go.dedis.ch/kyber/v3/group/nist"
+	"go.dedis.ch/kyber/v3/group/secp256k1"
 	"go.dedis.ch/kyber/v3/pairing"
 	"go.dedis.ch/kyber/v3/pairing/bn256"
 )
@@ -10,13 +11,16 @@ import (
 func init() {
 	// Those are variable time suites that shouldn't be used
 	// in production environment when possible
-	register(nist.NewBlakeSHA256P256())
-	register(nist.NewBlakeSHA256QR512())
-	register(bn256.NewSuiteG1())
-	register(bn256.NewSuiteG2())
-	register(bn256.NewSuiteGT())
-	register(pairing.NewSuiteBn256())
+	Register(nist.NewBlakeSHA256P256())
+	Register(nist.NewBlakeSHA256QR512())
+	Register(bn256.NewSuiteG1())
+	Register(bn256.NewSuiteG2())
+	Register(bn256.NewSuiteGT())
+	Register(pairing.NewSuiteBn256())
+	// The point arithmetic of secp256k1 is constant time, but not the one
+	// of its scalars
+	Register(secp256k1.NewBlakeSHA256Secp256k1())
 	// This is a constant time implementation that should be
 	// used as much as possible
-	register(edwards25519.NewBlakeSHA256Ed25519())
+	Register(edwards25519.NewBlakeSHA256Ed25519())
 }
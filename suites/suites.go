@@ -1,4 +1,7 @@
-// Package suites allows callers to look up Kyber suites by name.
+// Package suites allows callers to look up Kyber suites by name, e.g.
+// "Ed25519", "bn256.G1", "bn256.G2" or "bn256.adapter" for the pairing suite,
+// and to decode the points and scalars of a suite known by its name only.
+// Applications can make their own suites available with Register.
 //
 // Currently, only the "ed25519" suite is available with a constant
 // time implementation and the other ones use variable time algorithms.
@@ -6,7 +9,9 @@ package suites
 
 import (
 	"errors"
+	"fmt"
 	"strings"
+	"sync"
 
 	"go.dedis.ch/kyber/v3"
 )
@@ -20,29 +25,39 @@ type Suite interface {
 	kyber.Random
 }
 
+// registry guards the registered suites, which can be looked up and
+// registered concurrently.
+var registry sync.RWMutex
+
 var suites = map[string]Suite{}
 
 var requireConstTime = false
 
-// register is called by suites to make themselves known to Kyber.
-//
-func register(s Suite) {
+// Register makes the suite available to Find under its name, as returned by
+// String, compared case-insensitively. A suite registered under the name of
+// another one replaces it.
+func Register(s Suite) {
+	registry.Lock()
+	defer registry.Unlock()
 	suites[strings.ToLower(s.String())] = s
 }
 
 // ErrUnknownSuite indicates that the suite was not one of the
-// registered suites.
+// registered suites. The errors returned by Find for unknown names wrap it
+// and can be checked with errors.Is.
 var ErrUnknownSuite = errors.New("unknown suite")
 
 // Find looks up a suite by name.
 func Find(name string) (Suite, error) {
+	registry.RLock()
+	defer registry.RUnlock()
 	if s, ok := suites[strings.ToLower(name)]; ok {
 		if requireConstTime && strings.ToLower(s.String()) != "ed25519" {
 			return nil, errors.New("requested suite exists but is not implemented with constant time algorithms as required by suites.RequireConstantTime")
 		}
 		return s, nil
 	}
-	return nil, ErrUnknownSuite
+	return nil, fmt.Errorf("%w: %q", ErrUnknownSuite, name)
 }
 
 // MustFind looks up a suite by name and panics if it is not found.
@@ -63,5 +78,33 @@ func MustFind(name string) Suite {
 //
 // At this time, the only constant time crypto suite is "Ed25519".
 func RequireConstantTime() {
+	registry.Lock()
+	defer registry.Unlock()
 	requireConstTime = true
 }
+
+// PointFromBytes decodes a point of the suite having the given name.
+func PointFromBytes(name string, b []byte) (kyber.Point, error) {
+	s, err := Find(name)
+	if err != nil {
+		return nil, err
+	}
+	p := s.Point()
+	if err := p.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ScalarFromBytes decodes a scalar of the suite having the given name.
+func ScalarFromBytes(name string, b []byte) (kyber.Scalar, error) {
+	s, err := Find(name)
+	if err != nil {
+		return nil, err
+	}
+	x := s.Scalar()
+	if err := x.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
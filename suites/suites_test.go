@@ -1,9 +1,18 @@
 package suites
 
 import (
+	"errors"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/share"
+	dkg "go.dedis.ch/kyber/v3/share/dkg/pedersen"
+	"go.dedis.ch/kyber/v3/sign/bls"
+	"go.dedis.ch/kyber/v3/sign/tbls"
 )
 
 func TestSuites_Find(t *testing.T) {
@@ -14,6 +23,7 @@ func TestSuites_Find(t *testing.T) {
 		"bn256.GT",
 		"P256",
 		"Residue512",
+		"Secp256k1",
 	}
 
 	for _, name := range ss {
@@ -38,3 +48,137 @@ func TestSuites_ConstTime(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, s)
 }
+
+func TestSuites_Unknown(t *testing.T) {
+	s, err := Find("unknown")
+	require.Nil(t, s)
+	require.True(t, errors.Is(err, ErrUnknownSuite))
+	_, err = PointFromBytes("unknown", nil)
+	require.True(t, errors.Is(err, ErrUnknownSuite))
+	_, err = ScalarFromBytes("unknown", nil)
+	require.True(t, errors.Is(err, ErrUnknownSuite))
+}
+
+func TestSuites_FromBytes(t *testing.T) {
+	for _, name := range []string{"Ed25519", "bn256.G1", "bn256.G2", "bn256.adapter", "P256"} {
+		s := MustFind(name)
+		x := s.Scalar().Pick(s.RandomStream())
+		P := s.Point().Mul(x, nil)
+
+		xb, err := x.MarshalBinary()
+		require.NoError(t, err)
+		x2, err := ScalarFromBytes(name, xb)
+		require.NoError(t, err)
+		require.True(t, x.Equal(x2))
+
+		Pb, err := P.MarshalBinary()
+		require.NoError(t, err)
+		P2, err := PointFromBytes(name, Pb)
+		require.NoError(t, err)
+		require.True(t, P.Equal(P2))
+
+		_, err = PointFromBytes(name, Pb[1:])
+		require.Error(t, err)
+	}
+}
+
+func TestSuites_Concurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			Register(edwards25519.NewBlakeSHA256Ed25519())
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := Find("ed25519")
+			require.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+}
+
+// storedDistKey is the distributed public key written by
+// TestSuites_StoreDistKey and read back by TestSuites_LoadDistKey, which only
+// knows the suite by the name stored alongside.
+var storedDistKey struct {
+	Suite string
+	Key   []byte
+	Msg   []byte
+	Sig   []byte
+}
+
+func TestSuites_StoreDistKey(t *testing.T) {
+	suite := MustFind("bn256.adapter").(*pairing.SuiteBn256)
+	n, thr := 4, 3
+	privates := make([]kyber.Scalar, n)
+	publics := make([]kyber.Point, n)
+	for i := range privates {
+		privates[i] = suite.Scalar().Pick(suite.RandomStream())
+		publics[i] = suite.Point().Mul(privates[i], nil)
+	}
+	dkgs := make([]*dkg.DistKeyGenerator, n)
+	for i := range dkgs {
+		d, err := dkg.NewDistKeyGenerator(suite, privates[i], publics, thr)
+		require.NoError(t, err)
+		dkgs[i] = d
+	}
+	var resps []*dkg.Response
+	for _, d := range dkgs {
+		deals, err := d.Deals()
+		require.NoError(t, err)
+		for i, deal := range deals {
+			resp, err := dkgs[i].ProcessDeal(deal)
+			require.NoError(t, err)
+			resps = append(resps, resp)
+		}
+	}
+	for _, resp := range resps {
+		for i, d := range dkgs {
+			if uint32(i) == resp.Response.Index {
+				continue
+			}
+			_, err := d.ProcessResponse(resp)
+			require.NoError(t, err)
+		}
+	}
+	shares := make([]*dkg.DistKeyShare, n)
+	for i, d := range dkgs {
+		require.True(t, d.Certified())
+		dks, err := d.DistKeyShare()
+		require.NoError(t, err)
+		shares[i] = dks
+	}
+
+	msg := []byte("stored with its suite")
+	var sigs [][]byte
+	for _, dks := range shares[:thr] {
+		sig, err := tbls.Sign(suite, dks.PriShare(), msg)
+		require.NoError(t, err)
+		sigs = append(sigs, sig)
+	}
+	pub := share.NewPubPoly(suite, nil, shares[0].Commitments())
+	sig, err := tbls.Recover(suite, pub, msg, sigs, thr, n)
+	require.NoError(t, err)
+
+	key, err := shares[0].Public().MarshalBinary()
+	require.NoError(t, err)
+	storedDistKey.Suite = suite.String()
+	storedDistKey.Key = key
+	storedDistKey.Msg = msg
+	storedDistKey.Sig = sig
+}
+
+func TestSuites_LoadDistKey(t *testing.T) {
+	if storedDistKey.Suite == "" {
+		TestSuites_StoreDistKey(t)
+	}
+	key, err := PointFromBytes(storedDistKey.Suite, storedDistKey.Key)
+	require.NoError(t, err)
+	s, err := Find(storedDistKey.Suite)
+	require.NoError(t, err)
+	ps, ok := s.(pairing.Suite)
+	require.True(t, ok)
+	require.NoError(t, bls.Verify(ps, key, storedDistKey.Msg, storedDistKey.Sig))
+}
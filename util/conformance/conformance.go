@@ -0,0 +1,339 @@
+// Package conformance emits and checks test vectors of the scalar and point
+// arithmetic of a suite, so that other implementations of the same protocols,
+// e.g. in another language, can check that they encode, reduce and hash
+// exactly like Kyber. Generate emits the vectors of a suite as JSON, in the
+// format of Vectors, and Run checks a suite against such a file. The vectors
+// of the suites Ed25519, bn256.G1, bn256.G2 and bn256.GT are in the testdata
+// directory of this package, and any change to them is a breaking change of
+// the encodings.
+package conformance
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/suites"
+)
+
+// Vectors is the JSON schema of the test vectors of a suite. The scalars and
+// the points are hex strings of their MarshalBinary encoding, e.g. of 32
+// bytes in little-endian for the scalars of Ed25519 and in big-endian for the
+// ones of bn256, and the messages are hex strings of their bytes.
+type Vectors struct {
+	// Suite is the name of the suite, as returned by its String method.
+	Suite string `json:"suite"`
+	// Scalars are the results of the arithmetic on pairs of scalars.
+	Scalars []ScalarVector `json:"scalars"`
+	// BaseMul are the multiples of the standard base point.
+	BaseMul []BaseMulVector `json:"base_mul"`
+	// PointAdd are the sums of pairs of points.
+	PointAdd []PointAddVector `json:"point_add"`
+	// Encodings are specific encodings of points, valid or not.
+	Encodings []EncodingVector `json:"encodings"`
+	// HashToScalar are the scalars derived from messages.
+	HashToScalar []HashVector `json:"hash_to_scalar"`
+}
+
+// ScalarVector holds two scalars a and b and the results of the operations
+// on them.
+type ScalarVector struct {
+	A   string `json:"a"`
+	B   string `json:"b"`
+	Add string `json:"add"` // a + b
+	Sub string `json:"sub"` // a - b
+	Mul string `json:"mul"` // a * b
+	Neg string `json:"neg"` // -a
+	// Inv is the inverse of a, or empty if a is zero.
+	Inv string `json:"inv,omitempty"`
+}
+
+// BaseMulVector holds a scalar s and the point s * B, where B is the standard
+// base point of the group.
+type BaseMulVector struct {
+	Scalar string `json:"scalar"`
+	Point  string `json:"point"`
+}
+
+// PointAddVector holds two points a and b and their sum a + b, written as a
+// multiplication in the groups noted multiplicatively, such as bn256.GT.
+type PointAddVector struct {
+	A   string `json:"a"`
+	B   string `json:"b"`
+	Sum string `json:"sum"`
+}
+
+// EncodingVector holds an encoding of a point. If Valid is true, the encoding
+// must decode to a point which encodes back to the same bytes. Otherwise, it
+// must be rejected, e.g. for a wrong length, a non-canonical encoding or a
+// point outside of the prime-order group.
+type EncodingVector struct {
+	Encoding string `json:"encoding"`
+	Valid    bool   `json:"valid"`
+}
+
+// HashVector holds a message and the scalars derived from it:
+//   - Hash is the digest of the message with the hash function of the suite,
+//     e.g. SHA-256, decoded with the SetBytes method of the scalars, which
+//     reduces it modulo the order of the group, reading it in the byte order
+//     of the encoding of the scalars,
+//   - Pick is the scalar picked from the XOF of the suite seeded with the
+//     message, e.g. BLAKE2Xb, with the Pick method of the scalars.
+type HashVector struct {
+	Message string `json:"message"`
+	Hash    string `json:"hash"`
+	Pick    string `json:"pick"`
+}
+
+// ErrMismatch is returned by Run when the suite does not give the result of a
+// vector. The errors returned by Run for such vectors wrap it and can be
+// checked with errors.Is.
+var ErrMismatch = errors.New("conformance: mismatch")
+
+// Generate returns the test vectors of the suite in JSON, in the format of
+// Vectors. The inputs are fixed and picked from the XOF of the suite, so
+// that Generate always returns the same vectors for the same suite.
+func Generate(suite suites.Suite) ([]byte, error) {
+	v := &Vectors{Suite: suite.String()}
+	xof := suite.XOF([]byte("kyber conformance vectors"))
+	one := suite.Scalar().One()
+	scalars := []kyber.Scalar{
+		suite.Scalar().Zero(),
+		one,
+		suite.Scalar().SetInt64(2),
+		suite.Scalar().Neg(one),
+		suite.Scalar().Pick(xof),
+		suite.Scalar().Pick(xof),
+	}
+
+	for i, a := range scalars {
+		b := scalars[(i+1)%len(scalars)]
+		s := ScalarVector{
+			A:   encode(a),
+			B:   encode(b),
+			Add: encode(suite.Scalar().Add(a, b)),
+			Sub: encode(suite.Scalar().Sub(a, b)),
+			Mul: encode(suite.Scalar().Mul(a, b)),
+			Neg: encode(suite.Scalar().Neg(a)),
+		}
+		if !a.Equal(suite.Scalar().Zero()) {
+			s.Inv = encode(suite.Scalar().Inv(a))
+		}
+		v.Scalars = append(v.Scalars, s)
+	}
+
+	points := make([]kyber.Point, len(scalars))
+	for i, s := range scalars {
+		points[i] = suite.Point().Mul(s, nil)
+		v.BaseMul = append(v.BaseMul, BaseMulVector{Scalar: encode(s), Point: encode(points[i])})
+	}
+
+	// the last pair adds a point and its opposite
+	pairs := [][2]kyber.Point{{points[0], points[1]}, {points[1], points[1]}, {points[2], points[4]},
+		{points[4], points[5]}, {points[1], points[3]}}
+	for _, p := range pairs {
+		v.PointAdd = append(v.PointAdd, PointAddVector{
+			A:   encode(p[0]),
+			B:   encode(p[1]),
+			Sum: encode(suite.Point().Add(p[0], p[1])),
+		})
+	}
+
+	base, err := suite.Point().Base().MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	var encodings [][]byte
+	for _, p := range points {
+		b, err := p.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		encodings = append(encodings, b)
+	}
+	flipped := append([]byte{}, base...)
+	flipped[0] ^= 0x80
+	flippedLast := append([]byte{}, base...)
+	flippedLast[len(flippedLast)-1] ^= 0x80
+	encodings = append(encodings,
+		bytes.Repeat([]byte{0x00}, len(base)),
+		bytes.Repeat([]byte{0xff}, len(base)),
+		flipped,
+		flippedLast,
+		base[:len(base)-1],
+		append(append([]byte{}, base...), 0))
+	for _, e := range encodings {
+		v.Encodings = append(v.Encodings, EncodingVector{
+			Encoding: hex.EncodeToString(e),
+			Valid:    validEncoding(suite, e),
+		})
+	}
+
+	for _, msg := range []string{"", "abc", "kyber conformance", strings.Repeat("a", 200)} {
+		h := suite.Hash()
+		h.Write([]byte(msg))
+		v.HashToScalar = append(v.HashToScalar, HashVector{
+			Message: hex.EncodeToString([]byte(msg)),
+			Hash:    encode(suite.Scalar().SetBytes(h.Sum(nil))),
+			Pick:    encode(suite.Scalar().Pick(suite.XOF([]byte(msg)))),
+		})
+	}
+
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+// Run checks the suite against the test vectors in JSON, in the format of
+// Vectors, e.g. the ones returned by Generate for the same suite. It returns
+// an error wrapping ErrMismatch for the first vector the suite does not
+// reproduce, and another error if the vectors cannot be decoded.
+func Run(suite suites.Suite, vectors []byte) error {
+	var v Vectors
+	if err := json.Unmarshal(vectors, &v); err != nil {
+		return fmt.Errorf("conformance: invalid vectors: %v", err)
+	}
+	if !strings.EqualFold(v.Suite, suite.String()) {
+		return fmt.Errorf("conformance: vectors of suite %s instead of %s", v.Suite, suite)
+	}
+	r := &runner{suite: suite}
+
+	for i, s := range v.Scalars {
+		r.at("scalars[%d]", i)
+		a, b := r.scalar(s.A), r.scalar(s.B)
+		if r.err != nil {
+			return r.err
+		}
+		r.check("add", s.Add, suite.Scalar().Add(a, b))
+		r.check("sub", s.Sub, suite.Scalar().Sub(a, b))
+		r.check("mul", s.Mul, suite.Scalar().Mul(a, b))
+		r.check("neg", s.Neg, suite.Scalar().Neg(a))
+		if s.Inv != "" {
+			r.check("inv", s.Inv, suite.Scalar().Inv(a))
+		}
+		if r.err != nil {
+			return r.err
+		}
+	}
+
+	for i, m := range v.BaseMul {
+		r.at("base_mul[%d]", i)
+		s := r.scalar(m.Scalar)
+		if r.err != nil {
+			return r.err
+		}
+		r.check("point", m.Point, suite.Point().Mul(s, nil))
+		if r.err != nil {
+			return r.err
+		}
+	}
+
+	for i, a := range v.PointAdd {
+		r.at("point_add[%d]", i)
+		p, q := r.point(a.A), r.point(a.B)
+		if r.err != nil {
+			return r.err
+		}
+		r.check("sum", a.Sum, suite.Point().Add(p, q))
+		if r.err != nil {
+			return r.err
+		}
+	}
+
+	for i, e := range v.Encodings {
+		r.at("encodings[%d]", i)
+		b := r.bytes(e.Encoding)
+		if r.err != nil {
+			return r.err
+		}
+		if validEncoding(suite, b) != e.Valid {
+			return fmt.Errorf("%w: %s: encoding valid is %v", ErrMismatch, r.where, !e.Valid)
+		}
+	}
+
+	for i, h := range v.HashToScalar {
+		r.at("hash_to_scalar[%d]", i)
+		msg := r.bytes(h.Message)
+		if r.err != nil {
+			return r.err
+		}
+		digest := suite.Hash()
+		digest.Write(msg)
+		r.check("hash", h.Hash, suite.Scalar().SetBytes(digest.Sum(nil)))
+		r.check("pick", h.Pick, suite.Scalar().Pick(suite.XOF(msg)))
+		if r.err != nil {
+			return r.err
+		}
+	}
+	return nil
+}
+
+// runner decodes and checks the vectors, keeping the first error.
+type runner struct {
+	suite suites.Suite
+	where string
+	err   error
+}
+
+func (r *runner) at(format string, args ...interface{}) {
+	r.where = fmt.Sprintf(format, args...)
+}
+
+func (r *runner) bytes(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil && r.err == nil {
+		r.err = fmt.Errorf("conformance: %s: invalid hex: %v", r.where, err)
+	}
+	return b
+}
+
+func (r *runner) scalar(s string) kyber.Scalar {
+	x := r.suite.Scalar()
+	if err := x.UnmarshalBinary(r.bytes(s)); err != nil && r.err == nil {
+		r.err = fmt.Errorf("conformance: %s: invalid scalar: %v", r.where, err)
+	}
+	return x
+}
+
+func (r *runner) point(s string) kyber.Point {
+	p := r.suite.Point()
+	if err := p.UnmarshalBinary(r.bytes(s)); err != nil && r.err == nil {
+		r.err = fmt.Errorf("conformance: %s: invalid point: %v", r.where, err)
+	}
+	return p
+}
+
+// check compares the encoding of the result with the expected one.
+func (r *runner) check(name, want string, got kyber.Marshaling) {
+	if r.err != nil {
+		return
+	}
+	if enc := encode(got); enc != strings.ToLower(want) {
+		r.err = fmt.Errorf("%w: %s: %s is %s instead of %s", ErrMismatch, r.where, name, enc, want)
+	}
+}
+
+// validEncoding returns true if the encoding decodes to a point of the suite
+// which encodes back to the same bytes.
+func validEncoding(suite suites.Suite, b []byte) bool {
+	p := suite.Point()
+	if err := p.UnmarshalBinary(b); err != nil {
+		return false
+	}
+	again, err := p.MarshalBinary()
+	return err == nil && bytes.Equal(again, b)
+}
+
+func encode(m kyber.Marshaling) string {
+	b, err := m.MarshalBinary()
+	if err != nil {
+		panic("conformance: cannot encode: " + err.Error())
+	}
+	return hex.EncodeToString(b)
+}
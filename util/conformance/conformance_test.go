@@ -0,0 +1,77 @@
+package conformance
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/suites"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden test vectors")
+
+// goldenSuites are the suites whose vectors are in testdata.
+var goldenSuites = []string{"Ed25519", "bn256.G1", "bn256.G2", "bn256.GT"}
+
+func TestGolden(t *testing.T) {
+	for _, name := range goldenSuites {
+		t.Run(name, func(t *testing.T) {
+			suite := suites.MustFind(name)
+			path := filepath.Join("testdata", name+".json")
+			if *updateGolden {
+				vectors, err := Generate(suite)
+				require.NoError(t, err)
+				require.NoError(t, ioutil.WriteFile(path, vectors, 0644))
+			}
+			golden, err := ioutil.ReadFile(path)
+			require.NoError(t, err)
+			require.NoError(t, Run(suite, golden))
+
+			vectors, err := Generate(suite)
+			require.NoError(t, err)
+			require.Equal(t, string(golden), string(vectors))
+		})
+	}
+}
+
+func TestGenerateRun(t *testing.T) {
+	for _, name := range []string{"P256", "bn256.adapter"} {
+		suite := suites.MustFind(name)
+		vectors, err := Generate(suite)
+		require.NoError(t, err)
+		require.NoError(t, Run(suite, vectors))
+	}
+}
+
+func TestRunMismatch(t *testing.T) {
+	suite := suites.MustFind("Ed25519")
+	golden, err := ioutil.ReadFile(filepath.Join("testdata", "Ed25519.json"))
+	require.NoError(t, err)
+
+	tamper := func(modify func(v *Vectors)) error {
+		var v Vectors
+		require.NoError(t, json.Unmarshal(golden, &v))
+		modify(&v)
+		b, err := json.Marshal(&v)
+		require.NoError(t, err)
+		return Run(suite, b)
+	}
+	err = tamper(func(v *Vectors) { v.Scalars[2].Add = v.Scalars[2].A })
+	require.True(t, errors.Is(err, ErrMismatch))
+	err = tamper(func(v *Vectors) { v.BaseMul[1].Point = v.BaseMul[2].Point })
+	require.True(t, errors.Is(err, ErrMismatch))
+	err = tamper(func(v *Vectors) { v.Encodings[len(v.Encodings)-1].Valid = true })
+	require.True(t, errors.Is(err, ErrMismatch))
+	err = tamper(func(v *Vectors) { v.HashToScalar[0].Pick = v.HashToScalar[0].Hash })
+	require.True(t, errors.Is(err, ErrMismatch))
+
+	err = tamper(func(v *Vectors) { v.PointAdd[0].A = "zz" })
+	require.Error(t, err)
+	require.False(t, errors.Is(err, ErrMismatch))
+	require.Error(t, Run(suites.MustFind("bn256.G1"), golden))
+	require.Error(t, Run(suite, []byte("{")))
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/group/mod"
 )
 
 // ReadHexPoint reads a point from r in hex representation.
@@ -78,6 +79,39 @@ func StringHexToScalar(group kyber.Group, str string) (kyber.Scalar, error) {
 	return ReadHexScalar(group, strings.NewReader(str))
 }
 
+// ScalarFromHex decodes a scalar of the group from its canonical hexadecimal
+// encoding in the given byte order, whatever the byte order of the
+// MarshalBinary of the group, e.g. to import a key from another library. It
+// returns an error if the encoding is not smaller than the order, or if the
+// scalars of the group do not implement kyber.ScalarEncoder.
+func ScalarFromHex(group kyber.Group, str string, order mod.ByteOrder) (kyber.Scalar, error) {
+	enc, ok := group.Scalar().(kyber.ScalarEncoder)
+	if !ok {
+		return nil, errors.New("scalar does not implement kyber.ScalarEncoder")
+	}
+	buf, err := hex.DecodeString(str)
+	if err != nil {
+		return nil, err
+	}
+	if order == mod.LittleEndian {
+		return enc.SetBytesLE(buf)
+	}
+	return enc.SetBytesBE(buf)
+}
+
+// ScalarToHex encodes a scalar to hexadecimal in the given byte order, whatever
+// the byte order of its MarshalBinary.
+func ScalarToHex(scalar kyber.Scalar, order mod.ByteOrder) (string, error) {
+	enc, ok := scalar.(kyber.ScalarEncoder)
+	if !ok {
+		return "", errors.New("scalar does not implement kyber.ScalarEncoder")
+	}
+	if order == mod.LittleEndian {
+		return hex.EncodeToString(enc.BytesLE()), nil
+	}
+	return hex.EncodeToString(enc.BytesBE()), nil
+}
+
 func getHex(r io.Reader, l int) ([]byte, error) {
 	bufHex := make([]byte, l*2)
 	bufByte := make([]byte, l)
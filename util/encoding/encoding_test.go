@@ -2,10 +2,14 @@ package encoding
 
 import (
 	"bytes"
+	"encoding/hex"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
 	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/group/mod"
+	"go.dedis.ch/kyber/v3/pairing/bn256"
 )
 
 var s = edwards25519.NewBlakeSHA256Ed25519()
@@ -60,3 +64,75 @@ func TestScalarHexString(t *testing.T) {
 	ErrFatal(err)
 	require.True(t, sc.Equal(s2))
 }
+
+// TestScalarByteOrders checks the explicit encodings of the scalars of
+// edwards25519 and bn256, whose MarshalBinary disagree on the byte order.
+func TestScalarByteOrders(t *testing.T) {
+	const be = "0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f20"
+	const le = "201f1e1d1c1b1a191817161514131211100f0e0d0c0b0a090807060504030201"
+	ed := edwards25519.NewBlakeSHA256Ed25519()
+	bn := bn256.NewSuiteG1()
+	for _, group := range []kyber.Group{ed, bn} {
+		fromBE, err := ScalarFromHex(group, be, mod.BigEndian)
+		require.NoError(t, err)
+		fromLE, err := ScalarFromHex(group, le, mod.LittleEndian)
+		require.NoError(t, err)
+		require.True(t, fromBE.Equal(fromLE))
+		str, err := ScalarToHex(fromBE, mod.LittleEndian)
+		require.NoError(t, err)
+		require.Equal(t, le, str)
+		str, err = ScalarToHex(fromLE, mod.BigEndian)
+		require.NoError(t, err)
+		require.Equal(t, be, str)
+
+		// the bytes of a scalar are carried over to the other suite
+		other := kyber.Group(bn)
+		if group == other {
+			other = ed
+		}
+		moved, err := other.Scalar().(kyber.ScalarEncoder).SetBytesBE(fromBE.(kyber.ScalarEncoder).BytesBE())
+		require.NoError(t, err)
+		str, err = ScalarToHex(moved, mod.BigEndian)
+		require.NoError(t, err)
+		require.Equal(t, be, str)
+
+		// a hash of 64 bytes is reduced
+		hash := make([]byte, 64)
+		for i := range hash {
+			hash[i] = 0xff
+		}
+		_, err = group.Scalar().(kyber.ScalarEncoder).SetBytesWide(hash)
+		require.NoError(t, err)
+
+		// the order is not a canonical encoding
+		_, err = ScalarFromHex(group, "ff"+be[2:], mod.BigEndian)
+		require.Error(t, err)
+		_, err = ScalarFromHex(group, be[2:], mod.BigEndian)
+		require.Error(t, err)
+	}
+
+	// the fixed vectors of MarshalBinary
+	b, err := ScalarFromHex(ed, be, mod.BigEndian)
+	require.NoError(t, err)
+	require.Equal(t, le, b.String())
+	b, err = ScalarFromHex(bn, be, mod.BigEndian)
+	require.NoError(t, err)
+	buf, err := b.MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, be, hex.EncodeToString(buf))
+
+	// the wide reduction of 2^256 + 1, which is the same integer in both
+	// suites, agrees with the arithmetic of the scalars
+	wide := append([]byte{1}, make([]byte, 32)...)
+	wide[32] = 1
+	for _, group := range []kyber.Group{ed, bn} {
+		got, err := group.Scalar().(kyber.ScalarEncoder).SetBytesWide(wide)
+		require.NoError(t, err)
+		two128 := group.Scalar().SetInt64(1 << 62)
+		two128.Mul(two128, group.Scalar().SetInt64(1<<62))
+		two128.Mul(two128, group.Scalar().SetInt64(1<<4))
+		want := group.Scalar().Mul(two128, two128)
+		want.Add(want, group.Scalar().One())
+		require.True(t, want.Equal(got))
+	}
+}
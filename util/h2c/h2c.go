@@ -0,0 +1,151 @@
+// Package h2c implements the building blocks of RFC 9380, "Hashing to Elliptic
+// Curves", which do not depend on the curve: expand_message_xmd and
+// hash_to_field. The groups build their HashToPoint on top of them (see
+// kyber.PointHasher), and protocols may use HashToField to hash messages to
+// scalars as well, by giving the order of the group as the modulus.
+//
+// All the functions hash the messages with a domain separation tag (DST),
+// which must be unique to the protocol and to the suite, e.g.
+// "MYAPP-V01-CS01-with-P256_XMD:SHA-256_SSWU_RO_", so that the outputs for
+// two protocols are independent.
+package h2c
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"math/big"
+)
+
+// SecurityLevel is the target security level k of the suites, in bits. It
+// sets the number of bytes hashed into each field element by HashToField, so
+// that their bias is negligible.
+const SecurityLevel = 128
+
+// ErrEmptyDST is returned when the domain separation tag is empty, which RFC
+// 9380 forbids.
+var ErrEmptyDST = errors.New("h2c: empty domain separation tag")
+
+// oversizeDSTPrefix is prepended to the tags of more than 255 bytes before
+// hashing them, see Section 5.3.3 of RFC 9380.
+const oversizeDSTPrefix = "H2C-OVERSIZE-DST-"
+
+// ExpandMessageXMD returns length uniformly random bytes derived from the
+// message msg and the domain separation tag dst with the hash function
+// newHash, following expand_message_xmd of Section 5.3.1 of RFC 9380. The
+// hash function should be SHA-2 or SHA-3 with an output of at least 2k bits
+// for the security level k of the suite. A tag longer than 255 bytes is
+// replaced by its hash. It returns an error if dst is empty, or if length is
+// more than 255 outputs of the hash function or more than 65535 bytes.
+func ExpandMessageXMD(newHash func() hash.Hash, dst, msg []byte, length int) ([]byte, error) {
+	return expandMessageXMD(newHash, dst, length, func(h hash.Hash) error {
+		_, _ = h.Write(msg)
+		return nil
+	})
+}
+
+// ExpandMessageXMDReader works like ExpandMessageXMD on the message read from
+// r until EOF. The message is hashed as it is read, without being held in
+// memory. It returns the error of r, if any.
+func ExpandMessageXMDReader(newHash func() hash.Hash, dst []byte, r io.Reader, length int) ([]byte, error) {
+	return expandMessageXMD(newHash, dst, length, func(h hash.Hash) error {
+		_, err := io.Copy(h, r)
+		return err
+	})
+}
+
+// expandMessageXMD implements expand_message_xmd, where writeMsg writes the
+// message to the hash function: the message is only hashed once, into b_0.
+func expandMessageXMD(newHash func() hash.Hash, dst []byte, length int, writeMsg func(hash.Hash) error) ([]byte, error) {
+	if len(dst) == 0 {
+		return nil, ErrEmptyDST
+	}
+	h := newHash()
+	if len(dst) > 255 {
+		_, _ = h.Write([]byte(oversizeDSTPrefix))
+		_, _ = h.Write(dst)
+		dst = h.Sum(nil)
+		h.Reset()
+	}
+	bLen := h.Size()
+	ell := (length + bLen - 1) / bLen
+	if length < 0 || ell > 255 || length > 65535 {
+		return nil, fmt.Errorf("h2c: cannot expand to %d bytes with a hash of %d bytes", length, bLen)
+	}
+	dstPrime := append(append([]byte{}, dst...), byte(len(dst)))
+
+	// b_0 = H(Z_pad || msg || l_i_b_str || I2OSP(0, 1) || DST_prime)
+	_, _ = h.Write(make([]byte, h.BlockSize()))
+	if err := writeMsg(h); err != nil {
+		return nil, err
+	}
+	_, _ = h.Write([]byte{byte(length >> 8), byte(length), 0})
+	_, _ = h.Write(dstPrime)
+	b0 := h.Sum(nil)
+
+	// b_i = H(strxor(b_0, b_(i-1)) || I2OSP(i, 1) || DST_prime), with b_1
+	// hashing b_0 alone
+	out := make([]byte, 0, ell*bLen)
+	bi := make([]byte, bLen)
+	for i := 1; i <= ell; i++ {
+		for j := range bi {
+			bi[j] ^= b0[j]
+		}
+		h.Reset()
+		_, _ = h.Write(bi)
+		_, _ = h.Write([]byte{byte(i)})
+		_, _ = h.Write(dstPrime)
+		bi = h.Sum(bi[:0])
+		out = append(out, bi...)
+	}
+	return out[:length], nil
+}
+
+// HashToField hashes the message msg with the domain separation tag dst into
+// count elements of the prime field of the given modulus, following
+// hash_to_field of Section 5.2 of RFC 9380 with expand_message_xmd and the
+// security level SecurityLevel. The elements are uniformly distributed and
+// reduced modulo the modulus.
+//
+// For an extension field of degree m, such as the GF(p²) of the G2 group of
+// pairing-friendly curves, the caller requests count*m elements of the base
+// field: the element i of the extension field is made of the elements i*m to
+// i*m+m-1 of the result, in the order of RFC 9380.
+func HashToField(newHash func() hash.Hash, dst, msg []byte, modulus *big.Int, count int) ([]*big.Int, error) {
+	L := FieldLength(modulus)
+	buff, err := ExpandMessageXMD(newHash, dst, msg, count*L)
+	if err != nil {
+		return nil, err
+	}
+	return toField(buff, L, modulus), nil
+}
+
+// HashToFieldReader works like HashToField on the message read from r until
+// EOF, which is hashed as it is read. It returns the error of r, if any.
+func HashToFieldReader(newHash func() hash.Hash, dst []byte, r io.Reader, modulus *big.Int, count int) ([]*big.Int, error) {
+	L := FieldLength(modulus)
+	buff, err := ExpandMessageXMDReader(newHash, dst, r, count*L)
+	if err != nil {
+		return nil, err
+	}
+	return toField(buff, L, modulus), nil
+}
+
+// toField reduces each chunk of L bytes of buff modulo the modulus.
+func toField(buff []byte, L int, modulus *big.Int) []*big.Int {
+	count := len(buff) / L
+	elements := make([]*big.Int, count)
+	for i := range elements {
+		e := new(big.Int).SetBytes(buff[i*L : (i+1)*L])
+		elements[i] = e.Mod(e, modulus)
+	}
+	return elements
+}
+
+// FieldLength returns the number of bytes L which HashToField hashes into
+// each element of the field of the given modulus, i.e. the length of the
+// modulus plus SecurityLevel bits, rounded up to a whole number of bytes.
+func FieldLength(modulus *big.Int) int {
+	return (modulus.BitLen() + SecurityLevel + 7) / 8
+}
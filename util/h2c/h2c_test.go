@@ -0,0 +1,109 @@
+package h2c
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+)
+
+// expandVectors are the vectors of expand_message_xmd with SHA-256 of
+// Appendix K.1 of RFC 9380.
+var expandVectors = []struct {
+	msg    string
+	length int
+	out    string
+}{
+	{"", 0x20, "68a985b87eb6b46952128911f2a4412bbc302a9d759667f87f7a21d803f07235"},
+	{"abc", 0x20, "d8ccab23b5985ccea865c6c97b6e5b8350e794e603b4b97902f53a8a0d605615"},
+	{"abcdef0123456789", 0x20, "eff31487c770a893cfb36f912fbfcbff40d5661771ca4b2cb4eafe524333f5c1"},
+	{"q128_" + strings.Repeat("q", 128), 0x20, "b23a1d2b4d97b2ef7785562a7e8bac7eed54ed6e97e29aa51bfe3f12ddad1ff9"},
+	{"a512_" + strings.Repeat("a", 512), 0x20, "4623227bcc01293b8c130bf771da8c298dede7383243dc0993d2d94823958c4c"},
+	{"", 0x80, "af84c27ccfd45d41914fdff5df25293e221afc53d8ad2ac06d5e3e29485dadbee0d121587713a3e0dd4d5e69e93eb7cd4f5df4cd103e188cf60cb02edc3edf18eda8576c412b18ffb658e3dd6ec849469b979d444cf7b26911a08e63cf31f9dcc541708d3491184472c2c29bb749d4286b004ceb5ee6b9a7fa5b646c993f0ced"},
+}
+
+func TestExpandMessageXMD(t *testing.T) {
+	dst := []byte("QUUX-V01-CS02-with-expander-SHA256-128")
+	for _, v := range expandVectors {
+		out, err := ExpandMessageXMD(sha256.New, dst, []byte(v.msg), v.length)
+		require.NoError(t, err)
+		require.Equal(t, v.out, hex.EncodeToString(out), "msg %q", v.msg)
+		out, err = ExpandMessageXMDReader(sha256.New, dst, iotest.OneByteReader(strings.NewReader(v.msg)), v.length)
+		require.NoError(t, err)
+		require.Equal(t, v.out, hex.EncodeToString(out), "msg %q", v.msg)
+	}
+
+	// a tag of more than 255 bytes is hashed first, Appendix K.2
+	long := []byte("QUUX-V01-CS02-with-expander-SHA256-128-long-DST-" + strings.Repeat("1", 208))
+	out, err := ExpandMessageXMD(sha256.New, long, nil, 0x20)
+	require.NoError(t, err)
+	require.Equal(t, "e8dc0c8b686b7ef2074086fbdd2f30e3f8bfbd3bdf177f73f04b97ce618a3ed3", hex.EncodeToString(out))
+}
+
+func TestExpandMessageXMDErrors(t *testing.T) {
+	_, err := ExpandMessageXMD(sha256.New, nil, []byte("msg"), 32)
+	require.True(t, errors.Is(err, ErrEmptyDST))
+
+	dst := []byte("QUUX-V01-CS02-with-expander-SHA256-128")
+	_, err = ExpandMessageXMD(sha256.New, dst, nil, 255*32)
+	require.NoError(t, err)
+	_, err = ExpandMessageXMD(sha256.New, dst, nil, 255*32+1)
+	require.Error(t, err)
+	_, err = ExpandMessageXMD(sha512.New, dst, nil, 65536)
+	require.Error(t, err)
+	_, err = ExpandMessageXMD(sha256.New, dst, nil, -1)
+	require.Error(t, err)
+}
+
+func hexInt(t *testing.T, h string) *big.Int {
+	x, ok := new(big.Int).SetString(h, 16)
+	require.True(t, ok)
+	return x
+}
+
+func TestHashToField(t *testing.T) {
+	// the field elements u of the vectors of the msg "" of the suites
+	// P256_XMD:SHA-256_SSWU_RO_ and edwards25519_XMD:SHA-512_ELL2_RO_ of
+	// Appendix J of RFC 9380
+	p256 := hexInt(t, "ffffffff00000001000000000000000000000000ffffffffffffffffffffffff")
+	u, err := HashToField(sha256.New, []byte("QUUX-V01-CS02-with-P256_XMD:SHA-256_SSWU_RO_"), nil, p256, 2)
+	require.NoError(t, err)
+	require.Len(t, u, 2)
+	require.Equal(t, hexInt(t, "ad5342c66a6dd0ff080df1da0ea1c04b96e0330dd89406465eeba11582515009"), u[0])
+	require.Equal(t, hexInt(t, "8c0f1d43204bd6f6ea70ae8013070a1518b43873bcd850aafa0a9e220e2eea5a"), u[1])
+
+	p25519 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(19))
+	u, err = HashToField(sha512.New, []byte("QUUX-V01-CS02-with-edwards25519_XMD:SHA-512_ELL2_RO_"), nil, p25519, 2)
+	require.NoError(t, err)
+	require.Equal(t, hexInt(t, "03fef4813c8cb5f98c6eef88fae174e6e7d5380de2b007799ac7ee712d203f3a"), u[0])
+	require.Equal(t, hexInt(t, "780bdddd137290c8f589dc687795aafae35f6b674668d92bf92ae793e6a60c75"), u[1])
+
+	require.Equal(t, 48, FieldLength(p256))
+	require.Equal(t, 48, FieldLength(p25519))
+
+	_, err = HashToField(sha256.New, nil, nil, p256, 2)
+	require.True(t, errors.Is(err, ErrEmptyDST))
+}
+
+func TestHashToFieldReader(t *testing.T) {
+	dst := []byte("QUUX-V01-CS02-with-P256_XMD:SHA-256_SSWU_RO_")
+	p256 := hexInt(t, "ffffffff00000001000000000000000000000000ffffffffffffffffffffffff")
+	msg := []byte(strings.Repeat("a512_", 1000))
+	u, err := HashToField(sha256.New, dst, msg, p256, 3)
+	require.NoError(t, err)
+	v, err := HashToFieldReader(sha256.New, dst, iotest.HalfReader(bytes.NewReader(msg)), p256, 3)
+	require.NoError(t, err)
+	require.Equal(t, u, v)
+
+	_, err = HashToFieldReader(sha256.New, dst, iotest.TimeoutReader(bytes.NewReader(msg)), p256, 3)
+	require.True(t, errors.Is(err, iotest.ErrTimeout))
+	_, err = HashToFieldReader(sha256.New, nil, bytes.NewReader(msg), p256, 3)
+	require.True(t, errors.Is(err, ErrEmptyDST))
+}
@@ -0,0 +1,72 @@
+package key
+
+import (
+	"bytes"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"strings"
+
+	"go.dedis.ch/kyber/v3"
+	"golang.org/x/crypto/blake2b"
+)
+
+// FingerprintVersion1 prefixes the fingerprints computed by the current
+// version of Fingerprint.
+const FingerprintVersion1 = "kf1-"
+
+// fingerprintSize is the size of the hash, 160 bits.
+const fingerprintSize = 20
+
+var fingerprintEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+var errFingerprintFormat = errors.New("key: malformed fingerprint")
+
+// Fingerprint returns a short identifier of the point of the group g,
+// typically a public key, to refer to it in logs, command line interfaces or
+// configuration files. It is made of the version prefix FingerprintVersion1
+// and of the lowercase base32 encoding of the 160-bit BLAKE2b hash of the name
+// of the group (see Group.String) and of the canonical encoding of the point.
+// For instance, the fingerprint of the base point of edwards25519 is
+//   kf1-62lqxldh2twqwjuvfmnj22npzhoprrve
+// The fingerprint of a point never changes, and the points of different groups
+// have different fingerprints, even for the same private key.
+func Fingerprint(g kyber.Group, p kyber.Point) string {
+	return FingerprintVersion1 + strings.ToLower(fingerprintEncoding.EncodeToString(fingerprintHash(g, p)))
+}
+
+// ParseFingerprint checks that fp is a fingerprint returned by Fingerprint,
+// ignoring the case, and returns the hash it encodes.
+func ParseFingerprint(fp string) ([]byte, error) {
+	if len(fp) != len(FingerprintVersion1)+fingerprintEncoding.EncodedLen(fingerprintSize) ||
+		!strings.EqualFold(fp[:len(FingerprintVersion1)], FingerprintVersion1) {
+		return nil, errFingerprintFormat
+	}
+	h, err := fingerprintEncoding.DecodeString(strings.ToUpper(fp[len(FingerprintVersion1):]))
+	if err != nil {
+		return nil, errFingerprintFormat
+	}
+	return h, nil
+}
+
+// MatchesFingerprint returns true if fp is the fingerprint of the point of the
+// group g.
+func MatchesFingerprint(g kyber.Group, p kyber.Point, fp string) bool {
+	h, err := ParseFingerprint(fp)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(h, fingerprintHash(g, p))
+}
+
+func fingerprintHash(g kyber.Group, p kyber.Point) []byte {
+	h, _ := blake2b.New(fingerprintSize, nil)
+	_, _ = h.Write([]byte("kyber fingerprint v1"))
+	name := g.String()
+	var size [4]byte
+	binary.BigEndian.PutUint32(size[:], uint32(len(name)))
+	_, _ = h.Write(size[:])
+	_, _ = h.Write([]byte(name))
+	_, _ = p.MarshalTo(h)
+	return h.Sum(nil)
+}
@@ -0,0 +1,57 @@
+package key
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/pairing/bn256"
+)
+
+func TestFingerprintGolden(t *testing.T) {
+	ed := edwards25519.NewBlakeSHA256Ed25519()
+	pairing := bn256.NewSuite()
+	golden := []struct {
+		g  kyber.Group
+		p  kyber.Point
+		fp string
+	}{
+		{ed, ed.Point().Base(), "kf1-62lqxldh2twqwjuvfmnj22npzhoprrve"},
+		{ed, ed.Point().Mul(ed.Scalar().SetInt64(42), nil), "kf1-klv5i5nuis4xhnfh5sebcsfygb6fhgai"},
+		{pairing.G1(), pairing.G1().Point().Base(), "kf1-lgs7tn5ows27rigw3ckeidxgg3gukmfp"},
+		{pairing.G2(), pairing.G2().Point().Base(), "kf1-5marw6owfzb7benhag4yk7fd5pdctxxo"},
+		// same scalar in another group
+		{pairing.G1(), pairing.G1().Point().Mul(pairing.G1().Scalar().SetInt64(42), nil), "kf1-usutscufknyj4sd7fgoqgqnwrtbuhrlf"},
+	}
+	for _, g := range golden {
+		require.Equal(t, g.fp, Fingerprint(g.g, g.p))
+		require.True(t, MatchesFingerprint(g.g, g.p, g.fp))
+		require.True(t, MatchesFingerprint(g.g, g.p, strings.ToUpper(g.fp)))
+	}
+	require.False(t, MatchesFingerprint(golden[0].g, golden[0].p, golden[1].fp))
+}
+
+func TestParseFingerprint(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	p := NewKeyPair(suite).Public
+	fp := Fingerprint(suite, p)
+	h, err := ParseFingerprint(fp)
+	require.NoError(t, err)
+	require.Len(t, h, fingerprintSize)
+
+	for _, bad := range []string{
+		"",
+		"kf1-",
+		fp[len(FingerprintVersion1):],
+		"kf2-" + fp[len(FingerprintVersion1):],
+		fp[:len(fp)-1],
+		fp + "a",
+		fp[:len(fp)-1] + "1",
+	} {
+		_, err := ParseFingerprint(bad)
+		require.Equal(t, errFingerprintFormat, err, bad)
+		require.False(t, MatchesFingerprint(suite, p, bad))
+	}
+}
@@ -1,4 +1,5 @@
-// Package key creates asymmetric key pairs.
+// Package key creates asymmetric key pairs, and encrypts private keys under a
+// passphrase so that they can be stored at rest.
 package key
 
 import (
@@ -0,0 +1,207 @@
+package key
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/util/random"
+	"golang.org/x/crypto/argon2"
+)
+
+// WrapVersion1 is the version byte of the current encoding of encrypted
+// scalars.
+const WrapVersion1 byte = 1
+
+// kdfArgon2id identifies Argon2id in the header of an encrypted scalar.
+const kdfArgon2id byte = 1
+
+const (
+	wrapSaltSize = 16
+	wrapKeySize  = 32
+	// bounds on the parameters, so that a modified header cannot make
+	// DecryptScalar run forever or exhaust the memory
+	wrapMaxTime   = 1024
+	wrapMaxMemory = 4 * 1024 * 1024
+)
+
+// WrapParams are the Argon2id parameters used to derive the encryption key
+// of a scalar from a passphrase. Memory is given in KiB, and is at most 4 GiB.
+// Time is at most 1024.
+type WrapParams struct {
+	Time    uint32
+	Memory  uint32
+	Threads uint8
+}
+
+// DefaultWrapParams are the parameters used by EncryptScalar, as recommended
+// by the Argon2 RFC for interactive uses.
+var DefaultWrapParams = WrapParams{Time: 1, Memory: 64 * 1024, Threads: 4}
+
+var errWrapFormat = errors.New("key: malformed encrypted scalar")
+var errWrapParams = errors.New("key: invalid key derivation parameters")
+var errWrapSuite = errors.New("key: encrypted scalar belongs to another suite")
+var errWrapDecrypt = errors.New("key: wrong passphrase or corrupted encrypted scalar")
+
+// EncryptScalar encrypts the scalar, typically a longterm private key, under
+// the passphrase with DefaultWrapParams, so that it can be stored at rest.
+func EncryptScalar(suite Suite, s kyber.Scalar, passphrase []byte) ([]byte, error) {
+	return EncryptScalarWithParams(suite, s, passphrase, DefaultWrapParams)
+}
+
+// EncryptScalarWithParams works like EncryptScalar with the given key
+// derivation parameters.
+//
+// The result starts with a self-describing header made of the version byte
+// WrapVersion1, the identifier of the key derivation function, its parameters,
+// the salt and the name of the suite. It is followed by the nonce and the
+// AES-256-GCM encryption of the scalar, which also authenticates the header.
+// DecryptScalar reads the parameters from the header, so that they can change
+// without breaking the scalars encrypted before.
+func EncryptScalarWithParams(suite Suite, s kyber.Scalar, passphrase []byte, params WrapParams) ([]byte, error) {
+	if err := params.check(); err != nil {
+		return nil, err
+	}
+	name := suite.String()
+	if len(name) > 255 {
+		return nil, errors.New("key: suite name too long")
+	}
+	plain, err := s.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	defer wipe(plain)
+
+	salt := make([]byte, wrapSaltSize)
+	random.Bytes(salt, suite.RandomStream())
+
+	var header bytes.Buffer
+	header.WriteByte(WrapVersion1)
+	header.WriteByte(kdfArgon2id)
+	binary.Write(&header, binary.BigEndian, params.Time)
+	binary.Write(&header, binary.BigEndian, params.Memory)
+	header.WriteByte(params.Threads)
+	header.WriteByte(byte(len(salt)))
+	header.Write(salt)
+	header.WriteByte(byte(len(name)))
+	header.WriteString(name)
+
+	aead, err := wrapAEAD(passphrase, salt, params)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	random.Bytes(nonce, suite.RandomStream())
+
+	out := append(append([]byte{}, header.Bytes()...), nonce...)
+	return aead.Seal(out, nonce, plain, header.Bytes()), nil
+}
+
+// DecryptScalar decrypts a scalar encrypted by EncryptScalar under the same
+// passphrase. It returns an error if the passphrase is wrong, if the encrypted
+// scalar has been modified or if it has been encrypted for another suite. The
+// caller should wipe the scalar with ZeroScalar once done with it.
+func DecryptScalar(suite Suite, blob, passphrase []byte) (kyber.Scalar, error) {
+	r := bytes.NewReader(blob)
+	var version, kdf byte
+	var params WrapParams
+	if err := binary.Read(r, binary.BigEndian, &version); err != nil {
+		return nil, errWrapFormat
+	}
+	if version != WrapVersion1 {
+		return nil, fmt.Errorf("key: unknown encrypted scalar version %d", version)
+	}
+	if err := binary.Read(r, binary.BigEndian, &kdf); err != nil {
+		return nil, errWrapFormat
+	}
+	if kdf != kdfArgon2id {
+		return nil, fmt.Errorf("key: unknown key derivation function %d", kdf)
+	}
+	if binary.Read(r, binary.BigEndian, &params) != nil {
+		return nil, errWrapFormat
+	}
+	if err := params.check(); err != nil {
+		return nil, err
+	}
+	salt, err := readShort(r)
+	if err != nil {
+		return nil, err
+	}
+	name, err := readShort(r)
+	if err != nil {
+		return nil, err
+	}
+	if string(name) != suite.String() {
+		return nil, fmt.Errorf("%w: %q instead of %q", errWrapSuite, name, suite.String())
+	}
+	header := blob[:len(blob)-r.Len()]
+
+	aead, err := wrapAEAD(passphrase, salt, params)
+	if err != nil {
+		return nil, err
+	}
+	rest := blob[len(header):]
+	if len(rest) < aead.NonceSize() {
+		return nil, errWrapFormat
+	}
+	plain, err := aead.Open(nil, rest[:aead.NonceSize()], rest[aead.NonceSize():], header)
+	if err != nil {
+		return nil, errWrapDecrypt
+	}
+	defer wipe(plain)
+
+	s := suite.Scalar()
+	if err := s.UnmarshalBinary(plain); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// ZeroScalar overwrites the scalar with zero. The wiping is best effort only:
+// the Go runtime may have copied the value elsewhere in memory, and some
+// scalar implementations do not overwrite their internal buffers in place.
+func ZeroScalar(s kyber.Scalar) {
+	if s != nil {
+		s.Zero()
+	}
+}
+
+func (p WrapParams) check() error {
+	// Argon2 needs at least 8 KiB of memory per thread
+	if p.Time == 0 || p.Threads == 0 || p.Memory < 8*uint32(p.Threads) ||
+		p.Time > wrapMaxTime || p.Memory > wrapMaxMemory {
+		return errWrapParams
+	}
+	return nil
+}
+
+func wrapAEAD(passphrase, salt []byte, params WrapParams) (cipher.AEAD, error) {
+	key := argon2.IDKey(passphrase, salt, params.Time, params.Memory, params.Threads, wrapKeySize)
+	defer wipe(key)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// readShort reads a byte slice prefixed by its length as one byte.
+func readShort(r *bytes.Reader) ([]byte, error) {
+	l, err := r.ReadByte()
+	if err != nil || int(l) > r.Len() {
+		return nil, errWrapFormat
+	}
+	b := make([]byte, l)
+	_, _ = r.Read(b)
+	return b, nil
+}
+
+func wipe(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
@@ -0,0 +1,68 @@
+package key
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/pairing/bn256"
+)
+
+// testWrapParams keep the tests fast.
+var testWrapParams = WrapParams{Time: 1, Memory: 64, Threads: 1}
+
+func TestEncryptScalar(t *testing.T) {
+	for _, suite := range []Suite{edwards25519.NewBlakeSHA256Ed25519(), bn256.NewSuiteG2()} {
+		t.Run(suite.String(), func(t *testing.T) {
+			s := suite.Scalar().Pick(suite.RandomStream())
+			blob, err := EncryptScalar(suite, s, []byte("passphrase"))
+			require.NoError(t, err)
+			dec, err := DecryptScalar(suite, blob, []byte("passphrase"))
+			require.NoError(t, err)
+			require.True(t, s.Equal(dec))
+
+			ZeroScalar(dec)
+			require.True(t, dec.Equal(suite.Scalar().Zero()))
+
+			_, err = DecryptScalar(suite, blob, []byte("wrong passphrase"))
+			require.Equal(t, errWrapDecrypt, err)
+		})
+	}
+}
+
+func TestEncryptScalarParams(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	s := suite.Scalar().Pick(suite.RandomStream())
+	pass := []byte("passphrase")
+
+	blob, err := EncryptScalarWithParams(suite, s, pass, testWrapParams)
+	require.NoError(t, err)
+	dec, err := DecryptScalar(suite, blob, pass)
+	require.NoError(t, err)
+	require.True(t, s.Equal(dec))
+
+	// same scalar and passphrase, different salt and nonce
+	blob2, err := EncryptScalarWithParams(suite, s, pass, testWrapParams)
+	require.NoError(t, err)
+	require.NotEqual(t, blob, blob2)
+
+	_, err = EncryptScalarWithParams(suite, s, pass, WrapParams{Time: 1, Memory: 7, Threads: 1})
+	require.Equal(t, errWrapParams, err)
+
+	_, err = DecryptScalar(bn256.NewSuiteG2(), blob, pass)
+	require.True(t, errors.Is(err, errWrapSuite))
+
+	// any modification of the header or the ciphertext is detected, or makes
+	// the header unreadable
+	for i := range blob {
+		mod := append([]byte{}, blob...)
+		mod[i] ^= 1
+		_, err := DecryptScalar(suite, mod, pass)
+		require.Error(t, err, "modified byte %d", i)
+	}
+	for i := range blob {
+		_, err := DecryptScalar(suite, blob[:i], pass)
+		require.Error(t, err, "truncated at %d", i)
+	}
+}
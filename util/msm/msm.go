@@ -0,0 +1,114 @@
+// Package msm implements multi-scalar multiplications, i.e. the computation
+// of sum_i s_i*P_i for many scalars s_i and points P_i, with the bucket method
+// of Pippenger. Over n points of b-bit scalars, it needs about b/c*(n + 2^c)
+// point additions for windows of c bits, instead of the b doublings and
+// additions of each of the n scalar multiplications, which makes it an order
+// of magnitude faster for large n, e.g. to interpolate the commitments of a
+// large threshold or to verify proofs over long vectors of points.
+//
+// The running time depends on the scalars: the multi-scalar multiplication
+// must only be used on public values, e.g. to verify proofs or to combine
+// public commitments, like kyber.VartimeMulAdder.
+package msm
+
+import (
+	"errors"
+	"math/bits"
+
+	"go.dedis.ch/kyber/v3"
+)
+
+// MinPoints is the number of points from which MultiScalarMult uses the bucket
+// method; below it, the sum of scalar multiplications is as fast.
+const MinPoints = 16
+
+// MultiScalarMult returns sum_i scalars[i]*points[i] in the group g. The
+// scalars must implement kyber.ScalarEncoder, as those of the groups of kyber
+// do, for the bucket method to apply; it falls back to the sum of scalar
+// multiplications otherwise, or for less than MinPoints points. It returns an
+// error if there are not as many scalars as points.
+func MultiScalarMult(g kyber.Group, points []kyber.Point, scalars []kyber.Scalar) (kyber.Point, error) {
+	if len(points) != len(scalars) {
+		return nil, errors.New("msm: not as many scalars as points")
+	}
+	res := g.Point().Null()
+	if len(points) == 0 {
+		return res, nil
+	}
+	if _, ok := scalars[0].(kyber.ScalarEncoder); !ok || len(points) < MinPoints {
+		tmp := g.Point()
+		for i, P := range points {
+			res.Add(res, tmp.Mul(scalars[i], P))
+		}
+		return res, nil
+	}
+
+	le := make([][]byte, len(scalars))
+	for i, s := range scalars {
+		enc, ok := s.(kyber.ScalarEncoder)
+		if !ok {
+			return nil, errors.New("msm: scalars of different types")
+		}
+		le[i] = enc.BytesLE()
+	}
+	c := windowSize(len(points))
+	nbits := 8 * len(le[0])
+	buckets := make([]kyber.Point, 1<<uint(c))
+	running, sum := g.Point(), g.Point()
+	for w := (nbits+c-1)/c - 1; w >= 0; w-- {
+		for i := 0; i < c; i++ {
+			res.Add(res, res)
+		}
+		for d := range buckets {
+			buckets[d] = nil
+		}
+		for i, P := range points {
+			d := window(le[i], w*c, c)
+			if d == 0 {
+				continue
+			}
+			if buckets[d] == nil {
+				buckets[d] = P.Clone()
+			} else {
+				buckets[d].Add(buckets[d], P)
+			}
+		}
+		// sum_d d*buckets[d] as the sum of the running sums of the buckets
+		running.Null()
+		sum.Null()
+		for d := len(buckets) - 1; d > 0; d-- {
+			if buckets[d] != nil {
+				running.Add(running, buckets[d])
+			}
+			sum.Add(sum, running)
+		}
+		res.Add(res, sum)
+	}
+	return res, nil
+}
+
+// windowSize returns the number of bits c of the windows for n points, which
+// balances the n additions into the buckets against the 2^(c+1) additions of
+// their sum.
+func windowSize(n int) int {
+	c := bits.Len(uint(n)) - 2
+	if c < 2 {
+		return 2
+	}
+	if c > 16 {
+		return 16
+	}
+	return c
+}
+
+// window returns the c bits of the little endian integer b from the bit pos.
+func window(b []byte, pos, c int) int {
+	v := 0
+	for i := 0; i < c; i++ {
+		bit := pos + i
+		if bit/8 < len(b) && (b[bit/8]>>uint(bit%8))&1 == 1 {
+			v |= 1 << uint(i)
+		}
+	}
+	return v
+}
@@ -0,0 +1,86 @@
+package msm
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/pairing/bn256"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+var groups = []kyber.Group{
+	edwards25519.NewBlakeSHA256Ed25519(),
+	bn256.NewSuiteG1(),
+}
+
+func randomInputs(g kyber.Group, n int) ([]kyber.Point, []kyber.Scalar) {
+	points := make([]kyber.Point, n)
+	scalars := make([]kyber.Scalar, n)
+	rand := random.New()
+	for i := range points {
+		points[i] = g.Point().Pick(rand)
+		scalars[i] = g.Scalar().Pick(rand)
+	}
+	return points, scalars
+}
+
+func naive(g kyber.Group, points []kyber.Point, scalars []kyber.Scalar) kyber.Point {
+	res := g.Point().Null()
+	for i := range points {
+		res.Add(res, g.Point().Mul(scalars[i], points[i]))
+	}
+	return res
+}
+
+func TestMultiScalarMult(t *testing.T) {
+	for _, g := range groups {
+		for _, n := range []int{0, 1, MinPoints - 1, MinPoints, 100} {
+			points, scalars := randomInputs(g, n)
+			res, err := MultiScalarMult(g, points, scalars)
+			require.NoError(t, err)
+			require.True(t, res.Equal(naive(g, points, scalars)), "%s, n = %d", g, n)
+		}
+
+		// small and special scalars, and repeated points
+		points, scalars := randomInputs(g, 40)
+		for i := range scalars {
+			switch i % 4 {
+			case 0:
+				scalars[i].Zero()
+			case 1:
+				scalars[i].One()
+			case 2:
+				scalars[i].SetInt64(-1)
+			case 3:
+				points[i] = points[i-1]
+			}
+		}
+		res, err := MultiScalarMult(g, points, scalars)
+		require.NoError(t, err)
+		require.True(t, res.Equal(naive(g, points, scalars)), "%s", g)
+
+		_, err = MultiScalarMult(g, points[1:], scalars)
+		require.Error(t, err)
+	}
+}
+
+func BenchmarkMultiScalarMult(b *testing.B) {
+	for _, g := range groups {
+		for _, n := range []int{16, 64, 256, 1024} {
+			points, scalars := randomInputs(g, n)
+			b.Run(fmt.Sprintf("%s/%d/naive", g, n), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					naive(g, points, scalars)
+				}
+			})
+			b.Run(fmt.Sprintf("%s/%d/pippenger", g, n), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					_, _ = MultiScalarMult(g, points, scalars)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package random
+
+import (
+	"context"
+	"crypto/cipher"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"go.dedis.ch/kyber/v3/xof/blake2xb"
+)
+
+// ErrLimitExceeded is the error of SafePick when a stream returned by
+// WithLimit has been asked for more bytes than its limit.
+var ErrLimitExceeded = errors.New("random: stream limit exceeded")
+
+// streamError is the value of the panics of the streams of WithContext and
+// WithLimit, which SafePick recovers.
+type streamError struct {
+	err error
+}
+
+func (e *streamError) Error() string {
+	return e.err.Error()
+}
+
+// SafePick calls pick, e.g. a function picking a scalar or a point from a
+// stream returned by WithContext or WithLimit, and returns the error of the
+// stream if it failed. Since cipher.Stream cannot return errors, nor can the
+// Pick methods of kyber, these streams fail by panicking, and SafePick turns
+// that panic back into an error. Any other panic is propagated.
+func SafePick(pick func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			e, ok := r.(*streamError)
+			if !ok {
+				panic(r)
+			}
+			err = e.err
+		}
+	}()
+	pick()
+	return nil
+}
+
+type contextStream struct {
+	ctx context.Context
+	s   cipher.Stream
+}
+
+func (c *contextStream) XORKeyStream(dst, src []byte) {
+	if err := c.ctx.Err(); err != nil {
+		panic(&streamError{err})
+	}
+	c.s.XORKeyStream(dst, src)
+}
+
+// WithContext returns a stream reading from s until the context is done,
+// e.g. to abort a Pick doing rejection sampling. Once the context is done,
+// the stream panics with a value that SafePick recovers, so that it must be
+// used within SafePick:
+//
+//	err := random.SafePick(func() { x = suite.Scalar().Pick(stream) })
+//
+// where err is ctx.Err() if the context was done before x was picked.
+func WithContext(ctx context.Context, s cipher.Stream) cipher.Stream {
+	return &contextStream{ctx: ctx, s: s}
+}
+
+type limitStream struct {
+	left int64
+	s    cipher.Stream
+}
+
+func (l *limitStream) XORKeyStream(dst, src []byte) {
+	if atomic.AddInt64(&l.left, -int64(len(dst))) < 0 {
+		panic(&streamError{fmt.Errorf("%w: %d bytes requested", ErrLimitExceeded, len(dst))})
+	}
+	l.s.XORKeyStream(dst, src)
+}
+
+// WithLimit returns a stream reading at most max bytes from s overall, to
+// bound the entropy consumed e.g. by a burst of key generations. Once the
+// limit would be exceeded, the stream panics like the one of WithContext,
+// with an error wrapping ErrLimitExceeded, so that it must be used within
+// SafePick. The stream can be used in multiple threads if s can.
+func WithLimit(s cipher.Stream, max int64) cipher.Stream {
+	return &limitStream{left: max, s: s}
+}
+
+// CountingStream is a stream counting the bytes read from it.
+type CountingStream struct {
+	s     cipher.Stream
+	bytes uint64
+	calls uint64
+}
+
+// Counting returns a stream reading from s which counts the bytes read, e.g.
+// to instrument the entropy consumption. It can be used in multiple threads
+// if s can.
+func Counting(s cipher.Stream) *CountingStream {
+	return &CountingStream{s: s}
+}
+
+// XORKeyStream implements the cipher.Stream interface.
+func (c *CountingStream) XORKeyStream(dst, src []byte) {
+	c.s.XORKeyStream(dst, src)
+	atomic.AddUint64(&c.bytes, uint64(len(dst)))
+	atomic.AddUint64(&c.calls, 1)
+}
+
+// Bytes returns the number of bytes read from the stream.
+func (c *CountingStream) Bytes() uint64 {
+	return atomic.LoadUint64(&c.bytes)
+}
+
+// Calls returns the number of calls to XORKeyStream.
+func (c *CountingStream) Calls() uint64 {
+	return atomic.LoadUint64(&c.calls)
+}
+
+// Fixed returns a deterministic stream derived from the seed with the
+// BLAKE2Xb XOF, so that tests can be reproduced. It is NOT for production:
+// anyone knowing the seed knows all the keys picked from the stream. Unlike
+// the stream of New, it cannot be used in multiple threads.
+func Fixed(seed []byte) cipher.Stream {
+	return blake2xb.New(seed)
+}
@@ -0,0 +1,95 @@
+package random
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// constantStream always outputs 0xff, so that Int rejects all the values
+// above a modulus just over a power of two, and calls hook on each read.
+type constantStream struct {
+	hook func()
+}
+
+func (c *constantStream) XORKeyStream(dst, src []byte) {
+	for i := range dst {
+		dst[i] = src[i] ^ 0xff
+	}
+	c.hook()
+}
+
+func TestWithContextCancelMidPick(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	reads := 0
+	s := WithContext(ctx, &constantStream{hook: func() {
+		reads++
+		if reads == 3 {
+			cancel()
+		}
+	}})
+	err := SafePick(func() { Int(big.NewInt(129), s) })
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if reads != 3 {
+		t.Fatalf("expected 3 reads before cancellation, got %d", reads)
+	}
+
+	// a stream of a live context works like the underlying one
+	var i *big.Int
+	err = SafePick(func() { i = Int(big.NewInt(1000), WithContext(context.Background(), New())) })
+	if err != nil || i.Cmp(big.NewInt(1000)) >= 0 {
+		t.Fatalf("unexpected pick %v, %v", i, err)
+	}
+}
+
+func TestSafePickPropagatesPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Fatalf("expected the panic to be propagated, got %v", r)
+		}
+	}()
+	_ = SafePick(func() { panic("boom") })
+}
+
+func TestWithLimit(t *testing.T) {
+	s := WithLimit(New(), 2*size)
+	buf := make([]byte, size)
+	err := SafePick(func() {
+		Bytes(buf, s)
+		Bytes(buf, s)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = SafePick(func() { Bytes(buf[:1], s) })
+	if !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("expected ErrLimitExceeded, got %v", err)
+	}
+}
+
+func TestCounting(t *testing.T) {
+	c := Counting(New())
+	Bytes(make([]byte, size), c)
+	Bits(12, false, c)
+	if c.Bytes() != size+2 || c.Calls() != 2 {
+		t.Fatalf("counted %d bytes in %d calls", c.Bytes(), c.Calls())
+	}
+}
+
+func TestFixed(t *testing.T) {
+	seed := []byte("fixed seed for tests")
+	dst1, dst2 := make([]byte, size), make([]byte, size)
+	Bytes(dst1, Fixed(seed))
+	Bytes(dst2, Fixed(seed))
+	if !bytes.Equal(dst1, dst2) {
+		t.Fatal("streams of the same seed should be equal")
+	}
+	Bytes(dst2, Fixed([]byte("other seed")))
+	if bytes.Equal(dst1, dst2) {
+		t.Fatal("streams of different seeds should differ")
+	}
+}
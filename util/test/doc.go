@@ -1,3 +1,7 @@
 // Package test contains generic testing and benchmarking infrastructure
 // for cryptographic groups and ciphersuites.
+//
+// The authors of a kyber.Group, in or out of this module, check its
+// conformance with GroupTest, or SuiteTest for a whole ciphersuite, and
+// benchmark it with BenchGroup.
 package test
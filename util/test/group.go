@@ -1,6 +1,8 @@
 package test
 
 import (
+	"testing"
+
 	"go.dedis.ch/kyber/v3"
 	"go.dedis.ch/kyber/v3/util/random"
 )
@@ -148,3 +150,39 @@ func (gb GroupBench) PointDecode(iters int) {
 		_ = gb.X.UnmarshalBinary(gb.Xe)
 	}
 }
+
+// BenchGroup runs the benchmarks of GroupBench on the group g as
+// sub-benchmarks of b, e.g. with
+//
+//	func BenchmarkGroup(b *testing.B) { test.BenchGroup(b, group) }
+//
+// in the tests of the package of the group.
+func BenchGroup(b *testing.B, g kyber.Group) {
+	gb := NewGroupBench(g)
+	benches := []struct {
+		name string
+		fn   func(iters int)
+	}{
+		{"ScalarAdd", gb.ScalarAdd},
+		{"ScalarSub", gb.ScalarSub},
+		{"ScalarNeg", gb.ScalarNeg},
+		{"ScalarMul", gb.ScalarMul},
+		{"ScalarDiv", gb.ScalarDiv},
+		{"ScalarInv", gb.ScalarInv},
+		{"ScalarPick", gb.ScalarPick},
+		{"ScalarEncode", gb.ScalarEncode},
+		{"ScalarDecode", gb.ScalarDecode},
+		{"PointAdd", gb.PointAdd},
+		{"PointSub", gb.PointSub},
+		{"PointNeg", gb.PointNeg},
+		{"PointMul", gb.PointMul},
+		{"PointBaseMul", gb.PointBaseMul},
+		{"PointPick", gb.PointPick},
+		{"PointEncode", gb.PointEncode},
+		{"PointDecode", gb.PointDecode},
+	}
+	for _, bench := range benches {
+		fn := bench.fn
+		b.Run(bench.name, func(b *testing.B) { fn(b.N) })
+	}
+}
@@ -0,0 +1,289 @@
+package test
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"testing"
+
+	"go.dedis.ch/kyber/v3"
+)
+
+// samples is the number of random samples on which the laws are checked.
+const samples = 8
+
+// embeds reports whether the points of g support Embed and Data: some groups,
+// e.g. G2 of bn256, panic instead.
+func embeds(g kyber.Group) (ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+	g.Point().EmbedLen()
+	return true
+}
+
+// testPointLaws checks the group laws of the points, and that the scalar
+// multiplication is a module action, on random samples.
+func testPointLaws(t *testing.T, g kyber.Group, rand cipher.Stream) {
+	null := g.Point().Null()
+	if !g.Point().Neg(null).Equal(null) {
+		t.Errorf("the negation of the identity is not the identity")
+	}
+	if !g.Point().Mul(g.Scalar().One(), nil).Equal(g.Point().Base()) {
+		t.Errorf("multiplying the base point by one doesn't give the base point")
+	}
+	if !g.Point().Mul(g.Scalar().Pick(rand), null).Equal(null) {
+		t.Errorf("multiplying the identity doesn't give the identity")
+	}
+	for i := 0; i < samples; i++ {
+		P := g.Point().Pick(rand)
+		Q := g.Point().Pick(rand)
+		R := g.Point().Pick(rand)
+		a := g.Scalar().Pick(rand)
+		b := g.Scalar().Pick(rand)
+
+		left := g.Point().Add(g.Point().Add(P, Q), R)
+		right := g.Point().Add(P, g.Point().Add(Q, R))
+		if !left.Equal(right) {
+			t.Errorf("point addition is not associative: (%v + %v) + %v != %[1]v + (%[2]v + %[3]v)", P, Q, R)
+		}
+		if !g.Point().Add(P, Q).Equal(g.Point().Add(Q, P)) {
+			t.Errorf("point addition is not commutative: %v + %v != %[2]v + %[1]v", P, Q)
+		}
+		if !g.Point().Add(P, null).Equal(P) || !g.Point().Add(null, P).Equal(P) {
+			t.Errorf("the identity is not neutral for %v", P)
+		}
+		if !g.Point().Add(P, g.Point().Neg(P)).Equal(null) {
+			t.Errorf("%v + (-%[1]v) is not the identity", P)
+		}
+		if !g.Point().Sub(P, Q).Equal(g.Point().Add(P, g.Point().Neg(Q))) {
+			t.Errorf("%v - %v != %[1]v + (-%[2]v)", P, Q)
+		}
+		if !g.Point().Neg(g.Point().Neg(P)).Equal(P) {
+			t.Errorf("-(-%v) != %[1]v", P)
+		}
+
+		ab := g.Scalar().Add(a, b)
+		if !g.Point().Mul(ab, P).Equal(g.Point().Add(g.Point().Mul(a, P), g.Point().Mul(b, P))) {
+			t.Errorf("(%v + %v) * %v != %[1]v * %[3]v + %[2]v * %[3]v", a, b, P)
+		}
+		if !g.Point().Mul(a, g.Point().Add(P, Q)).Equal(g.Point().Add(g.Point().Mul(a, P), g.Point().Mul(a, Q))) {
+			t.Errorf("%v * (%v + %v) != %[1]v * %[2]v + %[1]v * %[3]v", a, P, Q)
+		}
+		ab.Mul(a, b)
+		if !g.Point().Mul(ab, P).Equal(g.Point().Mul(a, g.Point().Mul(b, P))) {
+			t.Errorf("(%v * %v) * %v != %[1]v * (%[2]v * %[3]v)", a, b, P)
+		}
+		if !g.Point().Mul(a, nil).Equal(g.Point().Mul(a, g.Point().Base())) {
+			t.Errorf("multiplying by a nil point doesn't use the base point for %v", a)
+		}
+		if !g.Point().Mul(g.Scalar().Zero(), P).Equal(null) {
+			t.Errorf("0 * %v is not the identity", P)
+		}
+		if !g.Point().Mul(g.Scalar().Neg(a), P).Equal(g.Point().Neg(g.Point().Mul(a, P))) {
+			t.Errorf("(-%v) * %v != -(%[1]v * %[2]v)", a, P)
+		}
+	}
+}
+
+// testScalarLaws checks the ring laws of the scalars, and the field laws if
+// the group has a prime order, on random samples.
+func testScalarLaws(t *testing.T, g kyber.Group, rand cipher.Stream, primeOrder bool) {
+	zero := g.Scalar().Zero()
+	one := g.Scalar().One()
+	if zero.Equal(one) {
+		t.Errorf("zero equals one")
+	}
+	if !g.Scalar().SetInt64(-1).Equal(g.Scalar().Neg(one)) {
+		t.Errorf("SetInt64(-1) != -1")
+	}
+	if !g.Scalar().SetInt64(0).Equal(zero) || !g.Scalar().SetInt64(1).Equal(one) {
+		t.Errorf("SetInt64 doesn't agree with Zero and One")
+	}
+	for i := 0; i < samples; i++ {
+		a := g.Scalar().Pick(rand)
+		b := g.Scalar().Pick(rand)
+		c := g.Scalar().Pick(rand)
+
+		if !g.Scalar().Add(g.Scalar().Add(a, b), c).Equal(g.Scalar().Add(a, g.Scalar().Add(b, c))) {
+			t.Errorf("scalar addition is not associative for %v, %v, %v", a, b, c)
+		}
+		if !g.Scalar().Mul(g.Scalar().Mul(a, b), c).Equal(g.Scalar().Mul(a, g.Scalar().Mul(b, c))) {
+			t.Errorf("scalar multiplication is not associative for %v, %v, %v", a, b, c)
+		}
+		if !g.Scalar().Add(a, b).Equal(g.Scalar().Add(b, a)) {
+			t.Errorf("scalar addition is not commutative for %v, %v", a, b)
+		}
+		if !g.Scalar().Mul(a, b).Equal(g.Scalar().Mul(b, a)) {
+			t.Errorf("scalar multiplication is not commutative for %v, %v", a, b)
+		}
+		left := g.Scalar().Mul(a, g.Scalar().Add(b, c))
+		right := g.Scalar().Add(g.Scalar().Mul(a, b), g.Scalar().Mul(a, c))
+		if !left.Equal(right) {
+			t.Errorf("scalar multiplication doesn't distribute over addition for %v, %v, %v", a, b, c)
+		}
+		if !g.Scalar().Add(a, zero).Equal(a) || !g.Scalar().Mul(a, one).Equal(a) {
+			t.Errorf("zero or one is not neutral for %v", a)
+		}
+		if !g.Scalar().Mul(a, zero).Equal(zero) {
+			t.Errorf("%v * 0 != 0", a)
+		}
+		if !g.Scalar().Add(a, g.Scalar().Neg(a)).Equal(zero) {
+			t.Errorf("%v + (-%[1]v) != 0", a)
+		}
+		if !g.Scalar().Sub(a, b).Equal(g.Scalar().Add(a, g.Scalar().Neg(b))) {
+			t.Errorf("%v - %v != %[1]v + (-%[2]v)", a, b)
+		}
+		if primeOrder && !a.Equal(zero) {
+			if !g.Scalar().Mul(a, g.Scalar().Inv(a)).Equal(one) {
+				t.Errorf("%v * %[1]v^-1 != 1", a)
+			}
+			if !g.Scalar().Mul(g.Scalar().Div(b, a), a).Equal(b) {
+				t.Errorf("(%v / %v) * %[2]v != %[1]v", b, a)
+			}
+		}
+	}
+}
+
+// testMarshalling checks the round trips of the binary encodings of points and
+// scalars, including the identity and the base point.
+func testMarshalling(t *testing.T, g kyber.Group, rand cipher.Stream) {
+	points := []kyber.Point{g.Point().Null(), g.Point().Base()}
+	scalars := []kyber.Scalar{g.Scalar().Zero(), g.Scalar().One(), g.Scalar().SetInt64(-1)}
+	for i := 0; i < samples; i++ {
+		points = append(points, g.Point().Pick(rand))
+		scalars = append(scalars, g.Scalar().Pick(rand))
+	}
+	for _, P := range points {
+		b, err := P.MarshalBinary()
+		if err != nil {
+			t.Errorf("marshalling of %v fails: %v", P, err)
+			continue
+		}
+		if len(b) != P.MarshalSize() || len(b) != g.PointLen() {
+			t.Errorf("encoding of %v has %d bytes, but MarshalSize is %d and PointLen %d",
+				P, len(b), P.MarshalSize(), g.PointLen())
+		}
+		Q := g.Point()
+		if err := Q.UnmarshalBinary(b); err != nil {
+			t.Errorf("unmarshalling of %v fails: %v", P, err)
+		} else if !Q.Equal(P) {
+			t.Errorf("unmarshalling of %v gives %v", P, Q)
+		}
+		var buf bytes.Buffer
+		if _, err := P.MarshalTo(&buf); err != nil || !bytes.Equal(buf.Bytes(), b) {
+			t.Errorf("MarshalTo and MarshalBinary disagree on %v", P)
+		}
+	}
+	for _, s := range scalars {
+		b, err := s.MarshalBinary()
+		if err != nil {
+			t.Errorf("marshalling of %v fails: %v", s, err)
+			continue
+		}
+		if len(b) != s.MarshalSize() || len(b) != g.ScalarLen() {
+			t.Errorf("encoding of %v has %d bytes, but MarshalSize is %d and ScalarLen %d",
+				s, len(b), s.MarshalSize(), g.ScalarLen())
+		}
+		s2 := g.Scalar()
+		if err := s2.UnmarshalBinary(b); err != nil {
+			t.Errorf("unmarshalling of %v fails: %v", s, err)
+		} else if !s2.Equal(s) {
+			t.Errorf("unmarshalling of %v gives %v", s, s2)
+		}
+	}
+}
+
+// testEmbedLimits checks the embedding of data of the sizes around EmbedLen.
+func testEmbedLimits(t *testing.T, g kyber.Group, rand cipher.Stream) {
+	max := g.Point().EmbedLen()
+	if max < 0 {
+		t.Errorf("negative EmbedLen %d", max)
+		return
+	}
+	data := make([]byte, max+1)
+	rand.XORKeyStream(data, data)
+	for _, l := range []int{0, 1, max, max + 1} {
+		P := g.Point().Embed(data[:l], rand)
+		got, err := P.Data()
+		if err != nil {
+			t.Errorf("extraction of %d bytes fails: %v", l, err)
+			continue
+		}
+		want := data[:l]
+		if l > max {
+			want = data[:max]
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("embedding of %d bytes gives %x instead of %x", l, got, want)
+		}
+	}
+	// any point can be given to Data, which may fail but must not panic
+	_, _ = g.Point().Pick(rand).Data()
+	_, _ = g.Point().Null().Data()
+}
+
+// testAliasing checks that the operations give the same results when the
+// receiver is also one of the arguments.
+func testAliasing(t *testing.T, g kyber.Group, rand cipher.Stream, primeOrder bool) {
+	for i := 0; i < samples; i++ {
+		P := g.Point().Pick(rand)
+		Q := g.Point().Pick(rand)
+		a := g.Scalar().Pick(rand)
+
+		pointOps := []struct {
+			name string
+			want kyber.Point
+			got  func(R kyber.Point) kyber.Point
+		}{
+			{"Add(R, Q)", g.Point().Add(P, Q), func(R kyber.Point) kyber.Point { return R.Add(R, Q) }},
+			{"Add(Q, R)", g.Point().Add(Q, P), func(R kyber.Point) kyber.Point { return R.Add(Q, R) }},
+			{"Add(R, R)", g.Point().Add(P, P), func(R kyber.Point) kyber.Point { return R.Add(R, R) }},
+			{"Sub(R, Q)", g.Point().Sub(P, Q), func(R kyber.Point) kyber.Point { return R.Sub(R, Q) }},
+			{"Sub(Q, R)", g.Point().Sub(Q, P), func(R kyber.Point) kyber.Point { return R.Sub(Q, R) }},
+			{"Sub(R, R)", g.Point().Null(), func(R kyber.Point) kyber.Point { return R.Sub(R, R) }},
+			{"Neg(R)", g.Point().Neg(P), func(R kyber.Point) kyber.Point { return R.Neg(R) }},
+			{"Mul(a, R)", g.Point().Mul(a, P), func(R kyber.Point) kyber.Point { return R.Mul(a, R) }},
+			{"Set(R)", P.Clone(), func(R kyber.Point) kyber.Point { return R.Set(R) }},
+		}
+		for _, op := range pointOps {
+			if got := op.got(P.Clone()); !got.Equal(op.want) {
+				t.Errorf("aliased point %s gives %v instead of %v", op.name, got, op.want)
+			}
+		}
+
+		b := g.Scalar().Pick(rand)
+		scalarOps := []struct {
+			name string
+			want kyber.Scalar
+			got  func(r kyber.Scalar) kyber.Scalar
+		}{
+			{"Add(r, b)", g.Scalar().Add(a, b), func(r kyber.Scalar) kyber.Scalar { return r.Add(r, b) }},
+			{"Add(r, r)", g.Scalar().Add(a, a), func(r kyber.Scalar) kyber.Scalar { return r.Add(r, r) }},
+			{"Sub(b, r)", g.Scalar().Sub(b, a), func(r kyber.Scalar) kyber.Scalar { return r.Sub(b, r) }},
+			{"Sub(r, r)", g.Scalar().Zero(), func(r kyber.Scalar) kyber.Scalar { return r.Sub(r, r) }},
+			{"Neg(r)", g.Scalar().Neg(a), func(r kyber.Scalar) kyber.Scalar { return r.Neg(r) }},
+			{"Mul(r, b)", g.Scalar().Mul(a, b), func(r kyber.Scalar) kyber.Scalar { return r.Mul(r, b) }},
+			{"Mul(r, r)", g.Scalar().Mul(a, a), func(r kyber.Scalar) kyber.Scalar { return r.Mul(r, r) }},
+			{"Set(r)", a.Clone(), func(r kyber.Scalar) kyber.Scalar { return r.Set(r) }},
+		}
+		if primeOrder && !a.Equal(g.Scalar().Zero()) && !b.Equal(g.Scalar().Zero()) {
+			scalarOps = append(scalarOps, []struct {
+				name string
+				want kyber.Scalar
+				got  func(r kyber.Scalar) kyber.Scalar
+			}{
+				{"Inv(r)", g.Scalar().Inv(a), func(r kyber.Scalar) kyber.Scalar { return r.Inv(r) }},
+				{"Div(r, b)", g.Scalar().Div(a, b), func(r kyber.Scalar) kyber.Scalar { return r.Div(r, b) }},
+				{"Div(b, r)", g.Scalar().Div(b, a), func(r kyber.Scalar) kyber.Scalar { return r.Div(b, r) }},
+				{"Div(r, r)", g.Scalar().One(), func(r kyber.Scalar) kyber.Scalar { return r.Div(r, r) }},
+			}...)
+		}
+		for _, op := range scalarOps {
+			if got := op.got(a.Clone()); !got.Equal(op.want) {
+				t.Errorf("aliased scalar %s gives %v instead of %v", op.name, got, op.want)
+			}
+		}
+	}
+}
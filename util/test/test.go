@@ -314,8 +314,10 @@ func testGroup(t *testing.T, g kyber.Group, rand cipher.Stream) []kyber.Point {
 	}
 
 	// Test embedding data
-	testEmbed(t, g, rand, &points, "Hi!")
-	testEmbed(t, g, rand, &points, "The quick brown fox jumps over the lazy dog")
+	if embeds(g) {
+		testEmbed(t, g, rand, &points, "Hi!")
+		testEmbed(t, g, rand, &points, "The quick brown fox jumps over the lazy dog")
+	}
 
 	// Test verifiable secret sharing
 
@@ -361,10 +363,25 @@ func testGroup(t *testing.T, g kyber.Group, rand cipher.Stream) []kyber.Point {
 	testScalarSet(t, g, rand)
 	testScalarClone(t, g, rand)
 
+	testPointLaws(t, g, rand)
+	testScalarLaws(t, g, rand, primeOrder)
+	testMarshalling(t, g, rand)
+	if embeds(g) {
+		testEmbedLimits(t, g, rand)
+	}
+	testAliasing(t, g, rand, primeOrder)
+
 	return points
 }
 
-// GroupTest applies a generic set of validation tests to a cryptographic Group.
+// GroupTest applies a generic set of validation tests to a cryptographic Group,
+// for the authors of groups to check their conformance to kyber.Group: the
+// group and ring laws on random samples, the binary encodings of points and
+// scalars including the identity and the base point, the embedding of data
+// when the points support it, and the semantics of Set, Clone and of the
+// operations whose receiver is also an argument. A group which is not of
+// prime order must implement IsPrimeOrder() bool, returning false, to skip
+// the inversion of scalars.
 func GroupTest(t *testing.T, g kyber.Group) {
 	testGroup(t, g, random.New())
 }
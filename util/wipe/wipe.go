@@ -0,0 +1,35 @@
+// Package wipe provides helpers to erase secret material from memory once it
+// is no longer needed. The erasure is best-effort: Go gives no guarantee that
+// the values were not copied elsewhere before, e.g. by the garbage collector
+// when growing a stack, or in the intermediate results of the arithmetic.
+package wipe
+
+import "go.dedis.ch/kyber/v3"
+
+// Scalar erases the value of s, which is then equal to zero. It overwrites
+// the memory of s if it implements kyber.Wiper, and only sets it to zero
+// otherwise. A nil s is ignored.
+func Scalar(s kyber.Scalar) {
+	if s == nil {
+		return
+	}
+	if w, ok := s.(kyber.Wiper); ok {
+		w.Wipe()
+		return
+	}
+	s.Zero()
+}
+
+// Scalars erases all the scalars of ss, see Scalar.
+func Scalars(ss []kyber.Scalar) {
+	for _, s := range ss {
+		Scalar(s)
+	}
+}
+
+// Bytes overwrites b with zeros.
+func Bytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
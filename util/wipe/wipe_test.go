@@ -0,0 +1,36 @@
+package wipe
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/group/mod"
+	"go.dedis.ch/kyber/v3/pairing/bn256"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+func TestScalars(t *testing.T) {
+	groups := []kyber.Group{edwards25519.NewBlakeSHA256Ed25519(), bn256.NewSuiteG1()}
+	for _, g := range groups {
+		ss := []kyber.Scalar{g.Scalar().Pick(random.New()), g.Scalar().Pick(random.New()), nil}
+		Scalars(ss)
+		for _, s := range ss[:2] {
+			require.True(t, s.Equal(g.Scalar().Zero()), g.String())
+		}
+	}
+	// a scalar which does not implement kyber.Wiper is still set to zero
+	s := struct{ kyber.Scalar }{mod.NewInt64(42, big.NewInt(65521))}
+	_, ok := kyber.Scalar(s).(kyber.Wiper)
+	require.False(t, ok)
+	Scalar(s)
+	require.True(t, s.Equal(mod.NewInt64(0, big.NewInt(65521))))
+}
+
+func TestBytes(t *testing.T) {
+	b := []byte("secret")
+	Bytes(b)
+	require.Equal(t, make([]byte, 6), b)
+}